@@ -10,10 +10,29 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/keeps-dev/go-cms-template/internal/autoreply"
+	"github.com/keeps-dev/go-cms-template/internal/changefeed"
 	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/configsync"
 	"github.com/keeps-dev/go-cms-template/internal/database"
+	"github.com/keeps-dev/go-cms-template/internal/database/migrate"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/geoip"
+	"github.com/keeps-dev/go-cms-template/internal/jobs"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/middleware"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/replication"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
 	"github.com/keeps-dev/go-cms-template/internal/router"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+	"github.com/keeps-dev/go-cms-template/internal/social"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
 )
 
 func main() {
@@ -24,6 +43,16 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if cfg.Server.Mode != config.ModeFull && cfg.Server.Mode != config.ModePublic {
+		log.Fatalf("Invalid SERVER_MODE %q (must be full or public)", cfg.Server.Mode)
+	}
+	// A random per-process key only verifies URLs this process signed
+	if cfg.MediaURL.SigningKey == "" {
+		if cfg.IsPublicOnly() {
+			log.Fatal("MEDIA_URL_SIGNING_KEY must be set with SERVER_MODE=public, so every instance accepts the same signed media URLs")
+		}
+		log.Println("[WARN] MEDIA_URL_SIGNING_KEY is not set; signed media URLs stop working on restart and on other instances")
+	}
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -38,8 +67,88 @@ func main() {
 	defer db.Close()
 	log.Println("Database connected successfully")
 
+	// Writers connected to a standby after a failover reconnect to the
+	// new primary
+	dbMonitor := database.NewHostMonitor(db, cfg.Database)
+	go dbMonitor.Run(ctx)
+
+	// Apply pending migrations; read-only public instances leave that to
+	// the full ones
+	if cfg.Database.Migrate {
+		if cfg.IsPublicOnly() {
+			log.Println("Public-only mode: skipping migrations")
+		} else {
+			applied, err := migrate.New(db).Up(ctx, cfg.Database.MigrateBaseline)
+			if err != nil {
+				log.Fatalf("Failed to migrate database: %v", err)
+			}
+			log.Printf("Database schema up to date (%d migrations applied)", applied)
+		}
+	}
+	if cfg.IsPublicOnly() {
+		log.Println("Public-only mode: serving the delivery API and public site read-only")
+	}
+
+	// Load GeoIP database (optional)
+	geo, err := geoip.Open(cfg.GeoIP.DatabasePath)
+	if err != nil {
+		log.Fatalf("Failed to load GeoIP database: %v", err)
+	}
+	defer geo.Close()
+	if geo != nil {
+		log.Println("GeoIP enrichment enabled")
+	}
+
+	// Background jobs run on every replica; advisory locks keep each one's
+	// singleton work on one replica at a time
+	locker := leader.New(db)
+
+	if cfg.Bootstrap.Dir != "" && !cfg.IsPublicOnly() {
+		if err := bootstrap(ctx, cfg.Bootstrap, db, locker); err != nil {
+			log.Fatalf("Bootstrap failed: %v", err)
+		}
+	}
+
+	// API usage is counted in memory by the router and flushed in the
+	// background. Public instances can't write it to the replica.
+	var usage middleware.UsageRecorder
+	var usageRecorder *jobs.APIUsageRecorder
+	if !cfg.IsPublicOnly() {
+		usageRecorder = jobs.NewAPIUsageRecorder(
+			repository.NewAPIUsageRepository(db),
+			cfg.Jobs.APIUsageFlushInterval,
+			time.Duration(cfg.Jobs.APIUsageRetentionDays)*24*time.Hour,
+			locker,
+		)
+		usage = usageRecorder
+	}
+
+	// Media URLs fail over to the replica CDN while the primary one is down
+	failover := replication.NewFailover(cfg.Replication)
+	go failover.Run(ctx)
+
+	// Domain events from the API and jobs reach the features subscribed to
+	// them in this process
+	bus := events.NewBus()
+
+	// Row changes reach every full instance through the change feed and are
+	// published to bus. NOTIFY doesn't reach replicas, so public instances
+	// don't listen.
+	if cfg.ChangeFeed.Enabled && !cfg.IsPublicOnly() {
+		go changefeed.Listen(ctx, db, bus)
+	}
+	settingRepo := repository.NewSettingRepository(db)
+	emailRepo := repository.NewEmailRepository(db)
+	notify.New(settingRepo).Subscribe(bus)
+	autoreply.New(settingRepo, emailRepo, mailer.New(mailer.NewRenderer(repository.NewEmailTemplateRepository(db), locale.NewFallbacks(cfg.Locale.Fallbacks), cfg.Mail.DefaultLocale), emailRepo)).Subscribe(bus)
+
 	// Initialize router
-	r := router.New(db)
+	r := router.New(cfg, db, dbMonitor, geo, usage, locker, failover, bus)
+
+	// Start background jobs; they all write, so public instances run none
+	if !cfg.IsPublicOnly() {
+		startJobs(ctx, cfg, db, locker, usageRecorder, bus)
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -75,5 +184,211 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop background jobs
+	cancel()
+
 	log.Println("Server stopped")
 }
+
+// bootstrap reconciles the database with the bootstrap directory. Replicas
+// starting together take turns; the ones that follow find nothing to change.
+func bootstrap(ctx context.Context, cfg config.BootstrapConfig, db *pgxpool.Pool, locker *leader.Locker) error {
+	seed, err := configsync.LoadDir(cfg.Dir)
+	if err != nil {
+		return err
+	}
+	reconciler := configsync.NewReconciler(repository.NewContentTypeRepository(db), repository.NewSettingRepository(db))
+
+	for {
+		ran, err := locker.WithLease(ctx, "bootstrap", func(ctx context.Context) error {
+			result, err := reconciler.ApplySeed(ctx, seed, repository.NewUserRepository(db), cfg.AdminPassword)
+			if err != nil {
+				return err
+			}
+			log.Printf("Bootstrap from %s: %s", cfg.Dir, result.Plan)
+			if result.AdminUser != nil && result.AdminUser.Action != configsync.ActionUnchanged {
+				log.Printf("Bootstrap admin user %s: %s", result.AdminUser.Email, result.AdminUser.Action)
+			}
+			return nil
+		})
+		if err != nil || ran {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// startJobs starts the background jobs, which stop when ctx is cancelled
+func startJobs(ctx context.Context, cfg *config.Config, db *pgxpool.Pool, locker *leader.Locker, usageRecorder *jobs.APIUsageRecorder, bus *events.Bus) {
+	compactor := jobs.NewViewRollupCompactor(
+		repository.NewContentPostRepository(db),
+		time.Duration(cfg.Jobs.ViewRetentionDays)*24*time.Hour,
+		cfg.Jobs.ViewCompactionInterval,
+		locker,
+	)
+	go compactor.Run(ctx)
+	go usageRecorder.Run(ctx)
+
+	expirer := jobs.NewListingExpirer(repository.NewContentPostRepository(db), cfg.Jobs.ListingExpiryInterval, locker)
+	go expirer.Run(ctx)
+
+	if cfg.Jobs.DashboardRefreshInterval > 0 {
+		refresher := jobs.NewDashboardRefresher(repository.NewDashboardRepository(db), cfg.Jobs.DashboardRefreshInterval, locker)
+		go refresher.Run(ctx)
+	}
+
+	notifier := notify.New(repository.NewSettingRepository(db))
+	if cfg.ChangeFeed.Enabled {
+		publisher := jobs.NewChangePublisher(repository.NewChangeFeedRepository(db), cfg.ChangeFeed.Slot, cfg.ChangeFeed.PollInterval, locker)
+		go publisher.Run(ctx)
+	}
+
+	if cfg.Jobs.PublishInterval > 0 {
+		postRepo := repository.NewContentPostRepository(db)
+		scheduler := jobs.NewPostScheduler(postRepo, service.NewPostService(postRepo, repository.NewApprovalRepository(db), bus), notifier, cfg.Jobs.PublishInterval, locker)
+		go scheduler.Run(ctx)
+	}
+
+	if cfg.Jobs.ApprovalEscalationInterval > 0 {
+		escalator := jobs.NewApprovalEscalator(repository.NewApprovalRepository(db), notifier, cfg.Jobs.ApprovalEscalationInterval, locker)
+		go escalator.Run(ctx)
+	}
+
+	if cfg.Jobs.ReviewReminderInterval > 0 {
+		reminder := jobs.NewReviewReminder(repository.NewReviewRepository(db), notifier, cfg.Jobs.StaleContentDays, cfg.Jobs.ReviewReminderInterval, locker)
+		go reminder.Run(ctx)
+	}
+
+	if cfg.Jobs.DuplicateScanInterval > 0 {
+		detector := jobs.NewDuplicateDetector(
+			repository.NewDuplicateRepository(db),
+			cfg.Jobs.DuplicateThreshold,
+			cfg.Jobs.DuplicateScanInterval,
+			locker,
+		)
+		go detector.Run(ctx)
+	}
+
+	if cfg.Jobs.ReferenceScanInterval > 0 {
+		checker := jobs.NewReferenceChecker(
+			repository.NewReferenceRepository(db),
+			cfg.Jobs.ReferenceAutoClean,
+			cfg.Jobs.ReferenceScanInterval,
+			locker,
+		)
+		go checker.Run(ctx)
+	}
+
+	if cfg.AccessLog.Enabled && cfg.AccessLog.RetentionDays > 0 {
+		pruner := jobs.NewAccessLogPruner(
+			repository.NewAccessLogRepository(db),
+			time.Duration(cfg.AccessLog.RetentionDays)*24*time.Hour,
+			locker,
+		)
+		go pruner.Run(ctx)
+	}
+
+	if cfg.Jobs.PartitionAheadMonths > 0 {
+		// Whole months are dropped once past the retention of the rows they
+		// hold; contact submissions are only ever archived
+		retention := map[string]time.Duration{
+			"post_view_daily":    time.Duration(cfg.Jobs.ViewRetentionDays) * 24 * time.Hour,
+			"post_traffic_daily": time.Duration(cfg.Jobs.ViewRetentionDays) * 24 * time.Hour,
+		}
+		if cfg.AccessLog.RetentionDays > 0 {
+			retention["access_log"] = time.Duration(cfg.AccessLog.RetentionDays) * 24 * time.Hour
+		}
+		partitions := jobs.NewPartitionManager(repository.NewPartitionRepository(db), cfg.Jobs.PartitionAheadMonths, retention, locker)
+		go partitions.Run(ctx)
+	}
+
+	if cfg.Archive.AfterDays > 0 {
+		if cfg.Storage.Driver == "" {
+			log.Fatal("ARCHIVE_AFTER_DAYS needs STORAGE_DRIVER to store the archives")
+		}
+		store, err := storage.New(cfg.Storage)
+		if err != nil {
+			log.Fatalf("Invalid storage configuration: %v", err)
+		}
+		archiver := jobs.NewArchiver(
+			repository.NewArchiveRepository(db),
+			store,
+			cfg.Archive.Bucket,
+			time.Duration(cfg.Archive.AfterDays)*24*time.Hour,
+			locker,
+		)
+		go archiver.Run(ctx)
+	}
+
+	emailRepo := repository.NewEmailRepository(db)
+	dispatcher := jobs.NewEmailDispatcher(
+		emailRepo,
+		mailer.NewSender(cfg.Mail),
+		notifier,
+		cfg.Mail,
+		locker,
+	)
+	go dispatcher.Run(ctx)
+
+	mail := mailer.New(mailer.NewRenderer(repository.NewEmailTemplateRepository(db), locale.NewFallbacks(cfg.Locale.Fallbacks), cfg.Mail.DefaultLocale), emailRepo)
+	urls := permalink.New(repository.NewSettingRepository(db), cfg.Mail.PublicURL, cfg.Web.PageType)
+	campaignSender := jobs.NewCampaignSender(
+		repository.NewCampaignRepository(db),
+		repository.NewSubscriberRepository(db),
+		repository.NewContentPostRepository(db),
+		mail,
+		urls,
+		cfg.Mail,
+		locker,
+	)
+	go campaignSender.Run(ctx)
+
+	if cfg.Jobs.DigestInterval > 0 {
+		digestSender := jobs.NewDigestSender(
+			repository.NewDigestRepository(db),
+			mail,
+			cfg.Mail.PublicURL,
+			cfg.Jobs.DigestInterval,
+			locker,
+		)
+		go digestSender.Run(ctx)
+	}
+
+	if cfg.Jobs.SocialShareInterval > 0 {
+		sharer := jobs.NewSocialSharer(
+			repository.NewSocialShareRepository(db),
+			repository.NewContentPostRepository(db),
+			social.New(repository.NewSettingRepository(db)),
+			urls,
+			cfg.Jobs.SocialShareInterval,
+			cfg.Jobs.SocialShareDelay,
+			cfg.Jobs.SocialShareMaxAge,
+			locker,
+		)
+		go sharer.Run(ctx)
+	}
+
+	if cfg.Storage.Driver != "" && cfg.Replication.Replica.Driver != "" {
+		primary, err := storage.New(cfg.Storage)
+		if err != nil {
+			log.Fatalf("Invalid storage configuration: %v", err)
+		}
+		replica, err := storage.New(cfg.Replication.Replica)
+		if err != nil {
+			log.Fatalf("Invalid replica storage configuration: %v", err)
+		}
+		replicator := jobs.NewMediaReplicator(
+			repository.NewMediaReplicationRepository(db),
+			primary,
+			replica,
+			cfg.Replication,
+			locker,
+		)
+		go replicator.Run(ctx)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const usage = `Compare go test -bench results with a baseline and fail on regressions.
+
+Usage:
+  benchcheck [-time 15] [-mem 10] [-allocs 0] baseline.txt new.txt
+
+Each benchmark's median over the runs in a file is compared. benchcheck
+exits with status 1 when a median time per op grows by more than -time
+percent, bytes per op by more than -mem percent or allocations per op by
+more than -allocs percent. Benchmarks missing from the baseline are listed
+but don't fail the check.
+`
+
+// procsSuffix is the -GOMAXPROCS suffix of benchmark names, left out so
+// results from machines with different core counts still line up
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// results holds the values of each benchmark by unit, keyed by package and
+// benchmark name
+type results map[string]map[string][]float64
+
+func main() {
+	fs := flag.NewFlagSet("benchcheck", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	maxTime := fs.Float64("time", 15, "allowed ns/op growth in percent")
+	maxMem := fs.Float64("mem", 10, "allowed B/op growth in percent")
+	maxAllocs := fs.Float64("allocs", 0, "allowed allocs/op growth in percent")
+	fs.Parse(os.Args[1:])
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	baseline, err := parseFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	current, err := parseFile(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	limits := map[string]float64{"ns/op": *maxTime, "B/op": *maxMem, "allocs/op": *maxAllocs}
+	units := []string{"ns/op", "B/op", "allocs/op"}
+
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	regressions := 0
+	for _, name := range names {
+		base, ok := baseline[name]
+		if !ok {
+			fmt.Printf("new        %s (not in the baseline)\n", name)
+			continue
+		}
+		for _, unit := range units {
+			oldValue, newValue := median(base[unit]), median(current[name][unit])
+			if oldValue < 0 || newValue < 0 {
+				continue
+			}
+			change := 0.0
+			if oldValue > 0 {
+				change = (newValue - oldValue) / oldValue * 100
+			} else if newValue > 0 {
+				change = 100
+			}
+			if change > limits[unit] {
+				fmt.Printf("REGRESSION %s %s: %g -> %g (%+.1f%%, limit %g%%)\n", name, unit, oldValue, newValue, change, limits[unit])
+				regressions++
+			}
+		}
+	}
+
+	if regressions > 0 {
+		fmt.Printf("%d regressions\n", regressions)
+		os.Exit(1)
+	}
+	fmt.Println("No regressions")
+}
+
+// parseFile reads the benchmark lines of go test -bench output, qualifying
+// benchmark names with the package of the preceding pkg: line
+func parseFile(path string) (results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	res := make(results)
+	pkg := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if p, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(p)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		name := pkg + "." + procsSuffix.ReplaceAllString(fields[0], "")
+		if res[name] == nil {
+			res[name] = make(map[string][]float64)
+		}
+		// Name and iterations, then value and unit pairs
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			res[name][fields[i+1]] = append(res[name][fields[i+1]], value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%s has no benchmark results", path)
+	}
+	return res, nil
+}
+
+// median returns the median of values, or -1 without values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return -1
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+const usage = `Manage CMS configuration as code.
+
+Usage:
+  cmsctl types pull -url URL [-file cms.yaml] [-settings site.,contacts.]
+  cmsctl types diff -url URL [-file cms.yaml]
+  cmsctl types push -url URL [-file cms.yaml]
+
+pull writes the instance's content types (and settings with the given key
+prefixes) to the file, diff shows what push would change, and push creates
+and updates content types and creates missing settings to match the file.
+
+Flags may also be set with CMS_URL and CMS_API_KEY. The API key must have
+the full scope and belong to an admin.
+`
+
+func main() {
+	// Load .env file if exists
+	_ = godotenv.Load()
+
+	if len(os.Args) < 3 || os.Args[1] != "types" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[2], flag.ExitOnError)
+	baseURL := fs.String("url", os.Getenv("CMS_URL"), "instance base URL")
+	apiKey := fs.String("api-key", os.Getenv("CMS_API_KEY"), "API key")
+	file := fs.String("file", "cms.yaml", "configuration file")
+	settings := fs.String("settings", "", "comma-separated setting key prefixes to pull")
+	timeout := fs.Duration("timeout", 2*time.Minute, "overall timeout")
+	fs.Parse(os.Args[3:])
+
+	if *baseURL == "" {
+		log.Fatal("-url is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	c := &client{baseURL: strings.TrimRight(*baseURL, "/") + "/api/v1/config", apiKey: *apiKey}
+
+	switch os.Args[2] {
+	case "pull":
+		path := ""
+		if *settings != "" {
+			path = "?" + url.Values{"settings": {*settings}}.Encode()
+		}
+		doc, err := c.do(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			log.Fatalf("Pull failed: %v", err)
+		}
+		if err := os.WriteFile(*file, doc, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *file, err)
+		}
+		log.Printf("Wrote %s", *file)
+
+	case "diff", "push":
+		doc, err := os.ReadFile(*file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *file, err)
+		}
+		path := "/diff"
+		if os.Args[2] == "push" {
+			path = "/apply"
+		}
+		out, err := c.do(ctx, http.MethodPost, path, doc)
+		if err != nil {
+			log.Fatalf("Request failed: %v", err)
+		}
+
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(out, &envelope); err != nil {
+			log.Fatalf("Invalid response: %v", err)
+		}
+		var buf bytes.Buffer
+		json.Indent(&buf, envelope.Data, "", "  ")
+		buf.WriteByte('\n')
+		buf.WriteTo(os.Stdout)
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// client calls the configuration endpoints of a CMS instance
+type client struct {
+	baseURL string
+	apiKey  string
+}
+
+func (c *client) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		var envelope struct {
+			Error *response.APIError `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) == nil && envelope.Error != nil {
+			return nil, fmt.Errorf("%s (%d %s)", envelope.Error.Message, resp.StatusCode, envelope.Error.Code)
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return data, nil
+}
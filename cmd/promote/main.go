@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/keeps-dev/go-cms-template/internal/promote"
+)
+
+const usage = `Promote content between CMS instances.
+
+Usage:
+  promote diff -source URL -target URL
+  promote push -source URL -target URL -slugs slug-a,slug-b [-author UUID]
+
+Flags may also be set with PROMOTE_SOURCE_URL, PROMOTE_SOURCE_API_KEY,
+PROMOTE_TARGET_URL and PROMOTE_TARGET_API_KEY. Both API keys need the full
+scope, and the target's must belong to an admin.
+`
+
+func main() {
+	// Load .env file if exists
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	sourceURL := fs.String("source", os.Getenv("PROMOTE_SOURCE_URL"), "source instance base URL")
+	sourceKey := fs.String("source-api-key", os.Getenv("PROMOTE_SOURCE_API_KEY"), "source API key")
+	targetURL := fs.String("target", os.Getenv("PROMOTE_TARGET_URL"), "target instance base URL")
+	targetKey := fs.String("target-api-key", os.Getenv("PROMOTE_TARGET_API_KEY"), "target API key")
+	slugs := fs.String("slugs", "", "comma-separated post slugs to push")
+	author := fs.String("author", "", "author ID for posts created on the target")
+	timeout := fs.Duration("timeout", 10*time.Minute, "overall timeout")
+	fs.Parse(os.Args[2:])
+
+	if *sourceURL == "" || *targetURL == "" {
+		log.Fatal("both -source and -target are required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	source := promote.NewClient(*sourceURL, *sourceKey)
+	target := promote.NewClient(*targetURL, *targetKey)
+
+	var out interface{}
+	switch os.Args[1] {
+	case "diff":
+		diff, err := promote.Compare(ctx, source, target)
+		if err != nil {
+			log.Fatalf("Diff failed: %v", err)
+		}
+		out = diff
+
+	case "push":
+		if *slugs == "" {
+			log.Fatal("-slugs is required")
+		}
+		var opts promote.Options
+		if *author != "" {
+			id, err := uuid.Parse(*author)
+			if err != nil {
+				log.Fatalf("Invalid -author: %v", err)
+			}
+			opts.AuthorID = &id
+		}
+		result, err := promote.NewPromoter(source, target, opts).Promote(ctx, strings.Split(*slugs, ","))
+		if err != nil {
+			log.Fatalf("Promotion failed: %v", err)
+		}
+		out = result
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(out)
+}
@@ -0,0 +1,44 @@
+// Package admin serves the embedded single-page admin interface. The UI is
+// built entirely on the public API and the admin UI schema, so it needs no
+// server-side logic beyond serving its files.
+package admin
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the admin UI. It must be mounted with its prefix stripped;
+// unknown paths fall back to index.html so deep links work.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.FileServer(http.FS(files))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
+		}
+
+		if _, err := fs.Stat(files, name); err != nil {
+			r = r.Clone(r.Context())
+			r.URL.Path = "/"
+			name = "index.html"
+		}
+
+		if name == "index.html" {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		w.Header().Set("X-Frame-Options", "DENY")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,439 @@
+// Minimal admin UI built on the REST API and /api/v1/admin/ui-schema.
+(function () {
+  'use strict';
+
+  // The access token lives in memory only. The refresh token, which rotates
+  // on every use, is kept in sessionStorage so a reload stays signed in
+  // while nothing outlives the tab.
+  var REFRESH_KEY = 'cms_admin_refresh';
+  var LEGACY_TOKEN_KEY = 'cms_admin_token';
+  var LONG_TEXT = ['content', 'excerpt', 'message', 'description', 'body_html', 'body_text', 'value'];
+
+  var schema = null;
+  var app = document.getElementById('app');
+  var accessToken = null;
+  var refreshing = null;
+
+  // Earlier versions kept a pasted token in localStorage
+  localStorage.removeItem(LEGACY_TOKEN_KEY);
+
+  // --- helpers -------------------------------------------------------------
+
+  function el(tag, attrs) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      var value = attrs[key];
+      if (key === 'text') node.textContent = value;
+      else if (key.indexOf('on') === 0) node.addEventListener(key.slice(2), value);
+      else if (value === true) node.setAttribute(key, '');
+      else if (value !== false && value != null) node.setAttribute(key, value);
+    });
+    for (var i = 2; i < arguments.length; i++) {
+      var child = arguments[i];
+      if (child == null) continue;
+      node.appendChild(typeof child === 'string' ? document.createTextNode(child) : child);
+    }
+    return node;
+  }
+
+  function render() {
+    app.innerHTML = '';
+    for (var i = 0; i < arguments.length; i++) app.appendChild(arguments[i]);
+  }
+
+  function humanize(name) {
+    return name.replace(/_/g, ' ').replace(/^./, function (c) { return c.toUpperCase(); });
+  }
+
+  function signedIn() {
+    return accessToken !== null || sessionStorage.getItem(REFRESH_KEY) !== null;
+  }
+
+  function startSession(tokens) {
+    accessToken = tokens.access_token;
+    sessionStorage.setItem(REFRESH_KEY, tokens.refresh_token);
+  }
+
+  function endSession() {
+    accessToken = null;
+    sessionStorage.removeItem(REFRESH_KEY);
+  }
+
+  // refresh exchanges the refresh token for a new pair. Refresh tokens work
+  // once, so concurrent callers share one exchange.
+  function refresh() {
+    var refreshToken = sessionStorage.getItem(REFRESH_KEY);
+    if (!refreshToken) return Promise.resolve(false);
+    if (!refreshing) {
+      refreshing = send('POST', '/api/v1/auth/refresh', { refresh_token: refreshToken }, false).then(function (res) {
+        return res.json().then(function (payload) {
+          if (!payload.success) {
+            endSession();
+            return false;
+          }
+          startSession(payload.data);
+          return true;
+        });
+      }).finally(function () { refreshing = null; });
+    }
+    return refreshing;
+  }
+
+  function send(method, path, body, authorize) {
+    var headers = { 'Accept': 'application/json' };
+    if (body !== undefined) headers['Content-Type'] = 'application/json';
+    if (authorize && accessToken) headers['Authorization'] = 'Bearer ' + accessToken;
+    return fetch(path, {
+      method: method,
+      headers: headers,
+      body: body === undefined ? undefined : JSON.stringify(body)
+    });
+  }
+
+  function api(method, path, body) {
+    var ready = accessToken === null ? refresh() : Promise.resolve(true);
+    return ready.then(function () {
+      return send(method, path, body, true);
+    }).then(function (res) {
+      if (res.status !== 401 || !sessionStorage.getItem(REFRESH_KEY)) return res;
+      // The access token expired: renew it once and retry
+      accessToken = null;
+      return refresh().then(function (ok) {
+        return ok ? send(method, path, body, true) : res;
+      });
+    }).then(function (res) {
+      if (res.status === 204) return { success: true };
+      if (res.status === 401) {
+        endSession();
+        location.hash = '#/login';
+      }
+      return res.json().then(function (payload) {
+        if (!payload.success) {
+          var err = new Error((payload.error && payload.error.message) || res.statusText);
+          err.details = payload.error && payload.error.details;
+          throw err;
+        }
+        return payload;
+      });
+    });
+  }
+
+  function formatValue(field, value) {
+    if (value == null || value === '') return '';
+    if (field && field.type === 'enum') {
+      var match = enumOption(field, value);
+      return match ? match.label : String(value);
+    }
+    if (field && field.type === 'datetime') return new Date(value).toLocaleString();
+    if (typeof value === 'object') return JSON.stringify(value);
+    return String(value);
+  }
+
+  // Some enums are sent by name, others by number
+  function enumOption(field, value) {
+    return (field.enum || []).filter(function (e) { return e.value === value || e.label === value; })[0];
+  }
+
+  function fieldMap(entity) {
+    var map = {};
+    entity.fields.forEach(function (f) { map[f.name] = f; });
+    return map;
+  }
+
+  // --- views ---------------------------------------------------------------
+
+  function loginView() {
+    var email = el('input', { type: 'email', autocomplete: 'username', required: true });
+    var password = el('input', { type: 'password', autocomplete: 'current-password', required: true });
+    var error = el('p', { class: 'error', hidden: true });
+    var form = el('form', {
+      class: 'card login',
+      onsubmit: function (e) {
+        e.preventDefault();
+        error.hidden = true;
+        send('POST', '/api/v1/auth/login', { email: email.value, password: password.value }, false).then(function (res) {
+          return res.json();
+        }).then(function (payload) {
+          if (!payload.success) throw new Error((payload.error && payload.error.message) || 'Sign in failed');
+          startSession(payload.data);
+          password.value = '';
+          start();
+        }).catch(function (err) {
+          error.textContent = err.message;
+          error.hidden = false;
+        });
+      }
+    },
+      el('h1', { text: 'Sign in' }),
+      el('label', { text: 'Email' }), email,
+      el('label', { text: 'Password' }), password,
+      error,
+      el('div', { class: 'actions' }, el('button', { class: 'primary', type: 'submit', text: 'Sign in' }))
+    );
+    render(form);
+  }
+
+  function homeView() {
+    var list = el('div', { class: 'card' }, el('h1', { text: 'Content' }));
+    schema.entities.forEach(function (entity) {
+      list.appendChild(el('p', {}, el('a', { href: '#/' + entity.name, text: entity.label })));
+    });
+    render(list);
+  }
+
+  function listView(entity, params) {
+    var fields = fieldMap(entity);
+    var page = parseInt(params.get('page') || '1', 10);
+    var query = new URLSearchParams(params);
+    query.set('page', page);
+    query.set('page_size', 20);
+
+    var filters = el('div', { class: 'toolbar' });
+    entity.filters.forEach(function (filter) {
+      var control;
+      if (filter.type === 'enum' || filter.type === 'boolean') {
+        control = el('select', { name: filter.name }, el('option', { value: '', text: humanize(filter.name) + ': any' }));
+        var options = filter.type === 'enum' ? filter.enum : [{ value: 'true', label: 'yes' }, { value: 'false', label: 'no' }];
+        options.forEach(function (o) {
+          control.appendChild(el('option', { value: o.value, text: o.label, selected: String(o.value) === params.get(filter.name) }));
+        });
+      } else if (filter.type === 'string') {
+        control = el('input', { name: filter.name, placeholder: humanize(filter.name), value: params.get(filter.name) || '' });
+      } else {
+        return;
+      }
+      filters.appendChild(control);
+    });
+    if (filters.childNodes.length) {
+      filters.appendChild(el('button', {
+        text: 'Filter',
+        onclick: function () {
+          var next = new URLSearchParams();
+          filters.querySelectorAll('[name]').forEach(function (c) { if (c.value) next.set(c.name, c.value); });
+          location.hash = '#/' + entity.name + '?' + next.toString();
+        }
+      }));
+    }
+
+    var toolbar = el('div', { class: 'toolbar' },
+      el('h1', { text: entity.label }),
+      el('span', { class: 'spacer' }),
+      entity.permissions.create ? el('a', { href: '#/' + entity.name + '/new' }, el('button', { class: 'primary', text: 'New' })) : null
+    );
+
+    render(toolbar, filters, el('p', { class: 'muted', text: 'Loading…' }));
+
+    api('GET', entity.path + '?' + query.toString()).then(function (payload) {
+      var head = el('tr', {});
+      entity.list_columns.forEach(function (c) { head.appendChild(el('th', { text: humanize(c) })); });
+
+      var body = el('tbody', {});
+      (payload.data || []).forEach(function (item) {
+        var row = el('tr', {
+          class: 'row',
+          onclick: function () { location.hash = '#/' + entity.name + '/' + encodeURIComponent(item[entity.id_field]); }
+        });
+        entity.list_columns.forEach(function (c) { row.appendChild(el('td', { text: formatValue(fields[c], item[c]) })); });
+        body.appendChild(row);
+      });
+
+      var meta = payload.meta || {};
+      var total = meta.total || 0;
+      var pages = Math.max(1, Math.ceil(total / 20));
+      function goto(p) {
+        var next = new URLSearchParams(params);
+        next.set('page', p);
+        location.hash = '#/' + entity.name + '?' + next.toString();
+      }
+      var pager = el('div', { class: 'pager' },
+        el('button', { text: 'Previous', disabled: page <= 1, onclick: function () { goto(page - 1); } }),
+        el('span', { class: 'muted', text: 'Page ' + page + ' of ' + pages + ' (' + total + ' total)' }),
+        el('button', { text: 'Next', disabled: page >= pages, onclick: function () { goto(page + 1); } })
+      );
+
+      render(toolbar, filters, el('table', {}, el('thead', {}, head), body), pager);
+    }).catch(function (err) {
+      render(toolbar, filters, el('p', { class: 'error', text: err.message }));
+    });
+  }
+
+  function inputFor(field, value) {
+    var name = field.name;
+    if (field.type === 'enum') {
+      var select = el('select', { name: name });
+      if (!field.required) select.appendChild(el('option', { value: '', text: '—' }));
+      field.enum.forEach(function (o) {
+        select.appendChild(el('option', { value: o.value, text: o.label, selected: enumOption(field, value) === o }));
+      });
+      return select;
+    }
+    if (field.type === 'boolean') return el('input', { type: 'checkbox', name: name, checked: !!value });
+    if (field.type === 'integer' || field.type === 'number') return el('input', { type: 'number', name: name, value: value == null ? '' : value });
+    if (field.type === 'datetime') {
+      var local = value ? new Date(new Date(value).getTime() - new Date().getTimezoneOffset() * 60000).toISOString().slice(0, 16) : '';
+      return el('input', { type: 'datetime-local', name: name, value: local });
+    }
+    if (field.type === 'json') {
+      var ta = el('textarea', { name: name });
+      ta.value = value == null ? '' : JSON.stringify(value, null, 2);
+      return ta;
+    }
+    if (field.type === 'array') return el('input', { name: name, value: (value || []).join(', '), placeholder: 'Comma-separated' });
+    if (LONG_TEXT.indexOf(name) >= 0) {
+      var text = el('textarea', { name: name });
+      text.value = value == null ? '' : value;
+      return text;
+    }
+    return el('input', { name: name, value: value == null ? '' : value });
+  }
+
+  function readInput(field, input) {
+    if (field.type === 'boolean') return input.checked;
+    var raw = input.value.trim();
+    if (raw === '') return undefined;
+    switch (field.type) {
+      case 'enum':
+      case 'integer': return parseInt(raw, 10);
+      case 'number': return parseFloat(raw);
+      case 'datetime': return new Date(raw).toISOString();
+      case 'json': return JSON.parse(raw);
+      case 'array': return raw.split(',').map(function (s) { return s.trim(); }).filter(Boolean);
+      default: return raw;
+    }
+  }
+
+  function editView(entity, id) {
+    var isNew = id === 'new';
+    var title = el('h1', { text: (isNew ? 'New ' : 'Edit ') + entity.label });
+    render(title, el('p', { class: 'muted', text: 'Loading…' }));
+
+    var load = isNew ? Promise.resolve({ data: {} }) : api('GET', entity.path + '/' + encodeURIComponent(id));
+    load.then(function (payload) {
+      var item = payload.data || {};
+      var form = el('form', { class: 'card' });
+      var errors = el('div', { class: 'error' });
+      var inputs = {};
+
+      entity.fields.forEach(function (field) {
+        var editable = isNew ? field.creatable : field.updatable;
+        if (!editable && (isNew || item[field.name] == null)) return;
+
+        var input = inputFor(field, item[field.name]);
+        input.disabled = !editable;
+        inputs[field.name] = input;
+        form.appendChild(el('label', {}, humanize(field.name), field.required && isNew ? el('span', { class: 'req', text: ' *' }) : null));
+        form.appendChild(input);
+        form.appendChild(el('div', { class: 'field-error', 'data-for': field.name }));
+      });
+
+      var actions = el('div', { class: 'actions' }, el('button', { class: 'primary', type: 'submit', text: 'Save' }));
+      if (!isNew && entity.permissions.delete) {
+        actions.appendChild(el('button', {
+          class: 'danger', type: 'button', text: 'Delete',
+          onclick: function () {
+            if (!confirm('Delete this item?')) return;
+            api('DELETE', entity.path + '/' + encodeURIComponent(id)).then(function () {
+              location.hash = '#/' + entity.name;
+            }).catch(function (err) { errors.textContent = err.message; });
+          }
+        }));
+      }
+      actions.appendChild(el('a', { href: '#/' + entity.name }, el('button', { type: 'button', text: 'Back' })));
+      form.appendChild(errors);
+      form.appendChild(actions);
+
+      form.addEventListener('submit', function (e) {
+        e.preventDefault();
+        errors.textContent = '';
+        form.querySelectorAll('.field-error').forEach(function (n) { n.textContent = ''; });
+
+        var body = {};
+        try {
+          entity.fields.forEach(function (field) {
+            var input = inputs[field.name];
+            if (!input || input.disabled) return;
+            var value = readInput(field, input);
+            if (value !== undefined) body[field.name] = value;
+          });
+        } catch (err) {
+          errors.textContent = 'Invalid JSON: ' + err.message;
+          return;
+        }
+
+        var request = isNew
+          ? api('POST', entity.path, body)
+          : api('PUT', entity.path + '/' + encodeURIComponent(id), body);
+        request.then(function (res) {
+          var saved = res.data || {};
+          location.hash = '#/' + entity.name + '/' + encodeURIComponent(saved[entity.id_field] || id);
+          if (!isNew) errors.textContent = '';
+        }).catch(function (err) {
+          errors.textContent = err.message;
+          Object.keys(err.details || {}).forEach(function (name) {
+            var target = form.querySelector('[data-for="' + name + '"]');
+            if (target) target.textContent = err.details[name];
+          });
+        });
+      });
+
+      render(title, form);
+    }).catch(function (err) {
+      render(title, el('p', { class: 'error', text: err.message }));
+    });
+  }
+
+  // --- routing -------------------------------------------------------------
+
+  function route() {
+    var hash = location.hash.replace(/^#\/?/, '');
+    var parts = hash.split('?');
+    var segments = parts[0].split('/').filter(Boolean);
+    var params = new URLSearchParams(parts[1] || '');
+
+    document.getElementById('logout').hidden = !signedIn();
+    document.querySelectorAll('#nav a').forEach(function (a) {
+      a.classList.toggle('active', a.getAttribute('href') === '#/' + segments[0]);
+    });
+
+    if (segments[0] === 'login') return loginView();
+    if (!schema) return;
+    if (segments.length === 0) return homeView();
+
+    var entity = schema.entities.filter(function (e) { return e.name === segments[0]; })[0];
+    if (!entity) return render(el('p', { class: 'error', text: 'Unknown page' }));
+    if (segments[1]) return editView(entity, decodeURIComponent(segments[1]));
+    return listView(entity, params);
+  }
+
+  document.getElementById('logout').addEventListener('click', function () {
+    var done = function () {
+      endSession();
+      location.hash = '#/login';
+      route();
+    };
+    // Ending the session server-side also revokes its refresh token
+    if (accessToken) send('POST', '/api/v1/auth/logout', undefined, true).then(done, done);
+    else done();
+  });
+  window.addEventListener('hashchange', route);
+
+  // start loads the UI schema, signing in first when the API requires it
+  function start() {
+    api('GET', '/api/v1/admin/ui-schema').then(function (payload) {
+      schema = payload.data;
+      var nav = document.getElementById('nav');
+      nav.innerHTML = '';
+      schema.entities.forEach(function (entity) {
+        nav.appendChild(el('a', { href: '#/' + entity.name, text: entity.label }));
+      });
+      // Leaving the sign-in page routes through hashchange
+      if (location.hash === '#/login') location.hash = '#/';
+      else route();
+    }).catch(function (err) {
+      if (location.hash === '#/login') return route();
+      render(el('p', { class: 'error', text: 'Failed to load admin schema: ' + err.message }));
+    });
+  }
+
+  start();
+})();
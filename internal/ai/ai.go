@@ -0,0 +1,143 @@
+// Package ai drafts summaries, tag suggestions and translations with a
+// configurable language model provider. It only produces suggestions; nothing
+// is written back to content.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+)
+
+// Suggestion limits
+const (
+	defaultSummaryWords = 60
+	maxSummaryWords     = 300
+	defaultTagCount     = 5
+	maxTagCount         = 20
+)
+
+// Assistant builds the prompts for each kind of suggestion and parses the
+// provider's answers
+type Assistant struct {
+	provider Provider
+	name     string
+	model    string
+	maxInput int
+}
+
+// New returns an assistant for the configured provider
+func New(cfg config.AIConfig) (*Assistant, error) {
+	provider, err := newProvider(cfg, &http.Client{})
+	if err != nil {
+		return nil, err
+	}
+	return &Assistant{provider: provider, name: cfg.Provider, model: cfg.Model, maxInput: cfg.MaxInputChars}, nil
+}
+
+// Provider names the configured provider and model
+func (a *Assistant) Provider() (name, model string) {
+	return a.name, a.model
+}
+
+// Summarize drafts a plain-text summary of at most maxWords words
+func (a *Assistant) Summarize(ctx context.Context, text string, maxWords int) (string, error) {
+	if maxWords <= 0 {
+		maxWords = defaultSummaryWords
+	}
+	if maxWords > maxSummaryWords {
+		maxWords = maxSummaryWords
+	}
+	system := fmt.Sprintf("You summarize articles for a content management system. "+
+		"Reply with a single plain-text paragraph of at most %d words, in the language of the article, "+
+		"without a preamble, markup or quotes.", maxWords)
+
+	out, err := a.provider.Complete(ctx, system, a.truncate(text))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// SuggestTags proposes up to limit short tag names for text
+func (a *Assistant) SuggestTags(ctx context.Context, text string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = defaultTagCount
+	}
+	if limit > maxTagCount {
+		limit = maxTagCount
+	}
+	system := fmt.Sprintf("You tag articles for a content management system. "+
+		"Reply with a JSON array of at most %d short tag names (one to three words each), "+
+		"most relevant first, and nothing else.", limit)
+
+	out, err := a.provider.Complete(ctx, system, a.truncate(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest tags: %w", err)
+	}
+
+	tags := parseList(out)
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(tag), " ")
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Translate translates text into language (a name or language code),
+// keeping any HTML or Markdown markup intact
+func (a *Assistant) Translate(ctx context.Context, text, language string) (string, error) {
+	system := fmt.Sprintf("You translate content for a content management system into %s. "+
+		"Keep HTML tags, Markdown syntax, URLs and line breaks exactly as they are and only translate the text. "+
+		"Reply with the translation only.", language)
+
+	out, err := a.provider.Complete(ctx, system, a.truncate(text))
+	if err != nil {
+		return "", fmt.Errorf("failed to translate: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// truncate caps the text sent to the provider at maxInput characters
+func (a *Assistant) truncate(text string) string {
+	if a.maxInput <= 0 || len(text) <= a.maxInput {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= a.maxInput {
+		return text
+	}
+	return string(runes[:a.maxInput])
+}
+
+// parseList reads a JSON array of strings from a model answer, tolerating
+// surrounding prose or code fences, and falls back to one item per line or
+// comma
+func parseList(out string) []string {
+	if start, end := strings.Index(out, "["), strings.LastIndex(out, "]"); start >= 0 && end > start {
+		var items []string
+		if err := json.Unmarshal([]byte(out[start:end+1]), &items); err == nil {
+			return items
+		}
+	}
+
+	items := strings.FieldsFunc(out, func(r rune) bool { return r == '\n' || r == ',' })
+	for i, item := range items {
+		items[i] = strings.TrimSpace(strings.Trim(strings.TrimSpace(item), "-*•\"'`"))
+	}
+	return items
+}
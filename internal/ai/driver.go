@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Provider generates text with a language model
+type Provider interface {
+	Complete(ctx context.Context, system, prompt string) (string, error)
+}
+
+// Supported provider drivers
+const (
+	ProviderOpenAI = "openai"
+	ProviderAzure  = "azure"
+	ProviderOllama = "ollama"
+)
+
+// Default endpoints of the hosted and local providers
+const (
+	defaultOpenAIURL = "https://api.openai.com/v1"
+	defaultOllamaURL = "http://localhost:11434"
+)
+
+func newProvider(cfg config.AIConfig, client *http.Client) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		if cfg.APIKey == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("openai: AI_API_KEY and AI_MODEL are required")
+		}
+		base := cfg.BaseURL
+		if base == "" {
+			base = defaultOpenAIURL
+		}
+		return &openAIDriver{
+			url:    strings.TrimRight(base, "/") + "/chat/completions",
+			header: http.Header{"Authorization": {"Bearer " + cfg.APIKey}},
+			model:  cfg.Model,
+			client: client,
+		}, nil
+	case ProviderAzure:
+		if cfg.BaseURL == "" || cfg.APIKey == "" || cfg.Model == "" || cfg.APIVersion == "" {
+			return nil, fmt.Errorf("azure: AI_BASE_URL, AI_API_KEY, AI_MODEL (the deployment) and AI_AZURE_API_VERSION are required")
+		}
+		// Azure picks the model from the deployment in the URL
+		return &openAIDriver{
+			url: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+				strings.TrimRight(cfg.BaseURL, "/"), url.PathEscape(cfg.Model), url.QueryEscape(cfg.APIVersion)),
+			header: http.Header{"Api-Key": {cfg.APIKey}},
+			client: client,
+		}, nil
+	case ProviderOllama:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("ollama: AI_MODEL is required")
+		}
+		base := cfg.BaseURL
+		if base == "" {
+			base = defaultOllamaURL
+		}
+		return &ollamaDriver{url: strings.TrimRight(base, "/") + "/api/chat", model: cfg.Model, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", cfg.Provider)
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func chatMessages(system, prompt string) []chatMessage {
+	return []chatMessage{{Role: "system", Content: system}, {Role: "user", Content: prompt}}
+}
+
+// openAIDriver calls the OpenAI chat completions API, which Azure OpenAI
+// deployments serve as well
+type openAIDriver struct {
+	url    string
+	header http.Header
+	model  string
+	client *http.Client
+}
+
+func (d *openAIDriver) Complete(ctx context.Context, system, prompt string) (string, error) {
+	payload := map[string]interface{}{"messages": chatMessages(system, prompt)}
+	if d.model != "" {
+		payload["model"] = d.model
+	}
+
+	var out struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, d.client, d.url, d.header, payload, &out); err != nil {
+		return "", err
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("provider returned no choices")
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+// ollamaDriver calls the chat API of a local Ollama server
+type ollamaDriver struct {
+	url    string
+	model  string
+	client *http.Client
+}
+
+func (d *ollamaDriver) Complete(ctx context.Context, system, prompt string) (string, error) {
+	var out struct {
+		Message chatMessage `json:"message"`
+	}
+	err := postJSON(ctx, d.client, d.url, nil, map[string]interface{}{
+		"model":    d.model,
+		"messages": chatMessages(system, prompt),
+		"stream":   false,
+	}, &out)
+	if err != nil {
+		return "", err
+	}
+	return out.Message.Content, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, payload, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build completion request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	reqctx.Propagate(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("provider rejected request with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode provider response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// Package anomaly tracks per-IP request patterns on public write endpoints
+// and escalates abusive addresses to CAPTCHA challenges and temporary bans.
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/captcha"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// TokenHeader carries the token of a solved CAPTCHA
+const TokenHeader = "X-Captcha-Token"
+
+// Detector counts requests and rejected requests (4xx responses) per address
+// in fixed windows. Counts are kept per replica; flags are stored in the
+// database, so a challenge or ban applies on every replica.
+type Detector struct {
+	repo     *repository.FlaggedIPRepository
+	verifier captcha.Verifier
+	cfg      config.AnomalyConfig
+
+	mu       sync.Mutex
+	start    time.Time
+	activity map[string]*activity
+}
+
+type activity struct {
+	requests int
+	failures int
+}
+
+// New creates a detector; a nil verifier disables challenges, so detected
+// addresses are banned straight away. Flags and manual overrides are enforced
+// even when detection is disabled in cfg.
+func New(repo *repository.FlaggedIPRepository, verifier captcha.Verifier, cfg config.AnomalyConfig) *Detector {
+	return &Detector{
+		repo:     repo,
+		verifier: verifier,
+		cfg:      cfg,
+		activity: make(map[string]*activity),
+	}
+}
+
+// CanChallenge reports whether a CAPTCHA provider is configured
+func (d *Detector) CanChallenge() bool {
+	return d.verifier != nil
+}
+
+// Middleware enforces flags on the wrapped endpoints and watches their
+// traffic. Lookup failures are logged and let the request through.
+func (d *Detector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsed := net.ParseIP(reqctx.From(r.Context()).IP)
+		if parsed == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ip := parsed.String()
+
+		flag, err := d.repo.Get(r.Context(), ip)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			reqctx.Logf(r.Context(), "[ERROR] %v", err)
+		}
+		if flag != nil && !flag.Active(time.Now()) {
+			flag = nil
+		}
+
+		if flag != nil {
+			switch flag.Status {
+			case models.IPFlagAllowed:
+				next.ServeHTTP(w, r)
+				return
+			case models.IPFlagBanned:
+				if flag.ExpiresAt != nil {
+					retryAfter := time.Until(*flag.ExpiresAt)
+					w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+				}
+				response.Error(w, http.StatusForbidden, "IP_BANNED", "Too many suspicious requests from this address")
+				return
+			case models.IPFlagChallenge:
+				if !d.solved(r, ip) {
+					d.observe(r, ip, true, flag)
+					response.Error(w, http.StatusForbidden, "CAPTCHA_REQUIRED", "Solve the CAPTCHA and send its token in the "+TokenHeader+" header")
+					return
+				}
+			}
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		d.observe(r, ip, rec.status >= 400 && rec.status < 500, flag)
+	})
+}
+
+// solved verifies the request's CAPTCHA token; without a provider nothing
+// can be solved
+func (d *Detector) solved(r *http.Request, ip string) bool {
+	if d.verifier == nil {
+		return false
+	}
+	ok, err := d.verifier.Verify(r.Context(), r.Header.Get(TokenHeader), ip)
+	if err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+	return ok
+}
+
+// observe counts a request and escalates the address once it is over a
+// limit: to a challenge, or to a ban when it was already challenged or
+// challenges are unavailable
+func (d *Detector) observe(r *http.Request, ip string, failed bool, flag *models.FlaggedIP) {
+	if !d.cfg.Enabled {
+		return
+	}
+	reason, exceeded := d.count(ip, failed, time.Now())
+	if !exceeded {
+		return
+	}
+
+	status, duration := models.IPFlagChallenge, d.cfg.ChallengeDuration
+	if d.verifier == nil || (flag != nil && flag.Status == models.IPFlagChallenge) {
+		status, duration = models.IPFlagBanned, d.cfg.BanDuration
+	}
+
+	// The response is already written; the flag must be stored regardless
+	ctx := context.WithoutCancel(r.Context())
+	if _, err := d.repo.Flag(ctx, ip, status, reason, time.Now().Add(duration)); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			reqctx.Logf(ctx, "[ERROR] %v", err)
+		}
+		return
+	}
+	reqctx.Logf(ctx, "[WARN] Flagged %s as %s: %s", ip, status, reason)
+}
+
+// count records a request from ip in the current window, starting a new
+// window once it has passed. When a limit is exceeded it returns why and
+// forgets the address, so counting starts over after the escalation.
+func (d *Detector) count(ip string, failed bool, now time.Time) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now.Sub(d.start) >= d.cfg.Window {
+		d.start = now
+		d.activity = make(map[string]*activity)
+	}
+	a := d.activity[ip]
+	if a == nil {
+		a = &activity{}
+		d.activity[ip] = a
+	}
+	a.requests++
+	if failed {
+		a.failures++
+	}
+
+	var reason string
+	switch {
+	case d.cfg.MaxFailures > 0 && a.failures > d.cfg.MaxFailures:
+		reason = fmt.Sprintf("%d rejected requests within %s", a.failures, d.cfg.Window)
+	case d.cfg.MaxRequests > 0 && a.requests > d.cfg.MaxRequests:
+		reason = fmt.Sprintf("%d requests within %s", a.requests, d.cfg.Window)
+	default:
+		return "", false
+	}
+	delete(d.activity, ip)
+	return reason, true
+}
+
+// statusRecorder remembers the response status
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
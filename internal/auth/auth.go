@@ -0,0 +1,529 @@
+// Package auth logs users in with their password, issues short-lived JWT
+// access tokens and rotating refresh tokens backed by rows in the sessions
+// table, checks the API keys of machine clients, and carries the
+// authenticated user of each request to the handlers.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinSecretLength is the shortest JWT secret accepted, in bytes
+const MinSecretLength = 32
+
+// Identity is the authenticated caller of a request. Callers using an API
+// key have APIKey set and no session.
+type Identity struct {
+	User      *models.User
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+	APIKey    *models.APIKey
+}
+
+type identityKey struct{}
+
+// From returns the authenticated caller of the request ctx belongs to, or
+// nil for anonymous requests
+func From(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// UserFrom returns the authenticated user of the request ctx belongs to, or
+// nil for anonymous requests
+func UserFrom(ctx context.Context) *models.User {
+	if identity := From(ctx); identity != nil {
+		return identity.User
+	}
+	return nil
+}
+
+// WithIdentity returns a copy of ctx carrying identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// RefreshTokenPrefix starts every refresh token. Refresh tokens are opaque,
+// so the middleware never mistakes one for an access token.
+const RefreshTokenPrefix = "crt_"
+
+// APIKeyHeader carries the API key of machine clients
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator logs users in and checks their bearer tokens and API keys
+type Authenticator struct {
+	sessions   *repository.SessionRepository
+	users      *repository.UserRepository
+	apiKeys    *repository.APIKeyRepository
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func New(sessions *repository.SessionRepository, users *repository.UserRepository, apiKeys *repository.APIKeyRepository, cfg config.AuthConfig) (*Authenticator, error) {
+	if len(cfg.JWTSecret) < MinSecretLength {
+		return nil, fmt.Errorf("secret must be at least %d bytes", MinSecretLength)
+	}
+	if cfg.AccessTokenTTL <= 0 || cfg.RefreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("token TTLs must be positive")
+	}
+	if cfg.RefreshTokenTTL < cfg.AccessTokenTTL {
+		return nil, fmt.Errorf("refresh token TTL must not be shorter than the access token TTL")
+	}
+	return &Authenticator{
+		sessions:   sessions,
+		users:      users,
+		apiKeys:    apiKeys,
+		secret:     []byte(cfg.JWTSecret),
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+	}, nil
+}
+
+// Middleware resolves the user of requests bearing an API key or a JWT
+// access token and records them as the request's reqctx.Caller. Requests without either pass through anonymously, as do
+// bearer tokens that aren't JWTs (such as delivery tokens), which are left
+// to the routes that check them. A key or JWT that is invalid, expired,
+// whose session has ended or whose user is inactive is rejected, as are
+// writes with a read-scoped key.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+			a.serveAPIKey(w, r, key, next)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || !looksLikeJWT(token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := Parse(token, a.secret, time.Now())
+		if err != nil {
+			unauthorized(w, "INVALID_TOKEN", "Invalid or expired token")
+			return
+		}
+		user, err := a.sessions.GetUser(r.Context(), claims.SessionID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			response.InternalErrorWithErr(w, "Failed to check session", err)
+			return
+		}
+		if user == nil || user.ID != claims.Subject || !user.IsActive {
+			unauthorized(w, "INVALID_TOKEN", "Invalid or expired token")
+			return
+		}
+
+		ctx := WithIdentity(r.Context(), &Identity{
+			User:      user,
+			SessionID: claims.SessionID,
+			ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		})
+		reqctx.SetCaller(ctx, reqctx.Caller{UserID: user.ID, Role: user.Role})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// serveAPIKey authenticates a request by its API key, acting as the user
+// who issued the key
+func (a *Authenticator) serveAPIKey(w http.ResponseWriter, r *http.Request, key string, next http.Handler) {
+	apiKey, err := a.apiKeys.GetByKey(r.Context(), key)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalErrorWithErr(w, "Failed to check API key", err)
+		return
+	}
+	if apiKey == nil || !apiKey.Usable(time.Now()) {
+		unauthorized(w, "INVALID_API_KEY", "Invalid or expired API key")
+		return
+	}
+	user, err := a.users.GetByID(r.Context(), apiKey.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalErrorWithErr(w, "Failed to look up user", err)
+		return
+	}
+	if user == nil || !user.IsActive {
+		unauthorized(w, "INVALID_API_KEY", "Invalid or expired API key")
+		return
+	}
+	if !apiKey.AllowsMethod(r.Method) {
+		response.Error(w, http.StatusForbidden, "API_KEY_READ_ONLY", "The API key is read-only")
+		return
+	}
+	if err := a.apiKeys.Touch(r.Context(), apiKey.ID); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+
+	ctx := WithIdentity(r.Context(), &Identity{User: user, APIKey: apiKey})
+	reqctx.SetCaller(ctx, reqctx.Caller{UserID: user.ID, Role: user.Role, APIKeyID: apiKey.ID})
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireUser rejects anonymous requests. It must run after Middleware.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if From(r.Context()) == nil {
+			unauthorized(w, "AUTHENTICATION_REQUIRED", "Authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSession rejects anonymous requests and requests made with an API
+// key, so that sessions and keys are only managed by logged-in users. It
+// must run after Middleware.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := From(r.Context())
+		if identity == nil {
+			unauthorized(w, "AUTHENTICATION_REQUIRED", "Authentication required")
+			return
+		}
+		if identity.APIKey != nil {
+			response.Forbidden(w, "This endpoint can't be used with an API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole rejects anonymous requests and users below role. It must run
+// after Middleware.
+func RequireRole(role models.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFrom(r.Context())
+			if user == nil {
+				unauthorized(w, "AUTHENTICATION_REQUIRED", "Authentication required")
+				return
+			}
+			if user.Role < role {
+				response.Forbidden(w, fmt.Sprintf("The %s role is required", role))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Check a user's email and password and start a session. The returned access token is sent as "Authorization: Bearer <token>" until it expires; the refresh token renews it.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.LoginRequest true "Credentials"
+// @Success 200 {object} response.APIResponse{data=models.LoginResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Router /api/v1/auth/login [post]
+func (a *Authenticator) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" {
+		validationErrors["email"] = "Email is required"
+	}
+	if req.Password == "" {
+		validationErrors["password"] = "Password is required"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	user, err := a.users.GetByEmail(r.Context(), req.Email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalErrorWithErr(w, "Failed to look up user", err)
+		return
+	}
+	if !checkPassword(user, req.Password) {
+		response.Error(w, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password")
+		return
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to issue token", err)
+		return
+	}
+	now := time.Now()
+	userAgent, ipAddress := client(r)
+	session := &models.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Token:     repository.HashSessionToken(refreshToken),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: now.Add(a.refreshTTL).Truncate(time.Second),
+	}
+	if err := a.sessions.Create(r.Context(), session); err != nil {
+		response.InternalErrorWithErr(w, "Failed to create session", err)
+		return
+	}
+
+	if err := a.sessions.DeleteExpired(r.Context(), user.ID); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+	if err := a.users.TouchLastLogin(r.Context(), user.ID); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+	user.LastLogin = &now
+
+	a.respondWithTokens(w, user, session, refreshToken, now)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting one again ends its session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} response.APIResponse{data=models.LoginResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Router /api/v1/auth/refresh [post]
+func (a *Authenticator) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	req.RefreshToken = strings.TrimSpace(req.RefreshToken)
+	if req.RefreshToken == "" {
+		response.ValidationError(w, map[string]string{"refresh_token": "Refresh token is required"})
+		return
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to issue token", err)
+		return
+	}
+	now := time.Now()
+	userAgent, ipAddress := client(r)
+	session, err := a.sessions.Rotate(r.Context(), repository.HashSessionToken(req.RefreshToken),
+		repository.HashSessionToken(refreshToken), now.Add(a.refreshTTL).Truncate(time.Second), userAgent, ipAddress)
+	if err != nil {
+		if errors.Is(err, repository.ErrTokenReused) {
+			reqctx.Logf(r.Context(), "[WARN] Reused refresh token presented; its session was ended")
+		}
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrTokenReused) {
+			unauthorized(w, "INVALID_REFRESH_TOKEN", "Invalid or expired refresh token")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to refresh session", err)
+		return
+	}
+
+	user, err := a.users.GetByID(r.Context(), session.UserID)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to look up user", err)
+		return
+	}
+	if !user.IsActive {
+		if err := a.sessions.Delete(r.Context(), session.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			reqctx.Logf(r.Context(), "[ERROR] %v", err)
+		}
+		unauthorized(w, "INVALID_REFRESH_TOKEN", "Invalid or expired refresh token")
+		return
+	}
+
+	a.respondWithTokens(w, user, session, refreshToken, now)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description End the session of the bearer token; its access and refresh tokens are rejected from then on
+// @Tags auth
+// @Success 204 "No Content"
+// @Failure 401 {object} response.APIResponse
+// @Router /api/v1/auth/logout [post]
+//
+// Logout must be mounted behind RequireSession.
+func (a *Authenticator) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := a.sessions.Delete(r.Context(), From(r.Context()).SessionID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalErrorWithErr(w, "Failed to end session", err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ListSessions godoc
+// @Summary List sessions
+// @Description List the caller's active sessions, most recently refreshed first; current marks the session of the bearer token
+// @Tags auth
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=[]models.Session}
+// @Failure 401 {object} response.APIResponse
+// @Router /api/v1/auth/sessions [get]
+//
+// ListSessions must be mounted behind RequireSession.
+func (a *Authenticator) ListSessions(w http.ResponseWriter, r *http.Request) {
+	identity := From(r.Context())
+	sessions, err := a.sessions.ListForUser(r.Context(), identity.User.ID)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to list sessions", err)
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == identity.SessionID
+	}
+
+	response.OK(w, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke session
+// @Description End one of the caller's sessions, for example a lost device
+// @Tags auth
+// @Param id path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+//
+// RevokeSession must be mounted behind RequireSession.
+func (a *Authenticator) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid session ID")
+		return
+	}
+
+	if err := a.sessions.DeleteUserSession(r.Context(), From(r.Context()).User.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Session not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to end session", err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// RevokeOtherSessions godoc
+// @Summary Revoke other sessions
+// @Description End all of the caller's sessions except the current one
+// @Tags auth
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /api/v1/auth/sessions [delete]
+//
+// RevokeOtherSessions must be mounted behind RequireSession.
+func (a *Authenticator) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	identity := From(r.Context())
+	revoked, err := a.sessions.DeleteOthers(r.Context(), identity.User.ID, identity.SessionID)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to end sessions", err)
+		return
+	}
+
+	response.OK(w, map[string]int64{"revoked": revoked})
+}
+
+// respondWithTokens signs an access token for session and writes it with
+// the session's new refresh token
+func (a *Authenticator) respondWithTokens(w http.ResponseWriter, user *models.User, session *models.Session, refreshToken string, now time.Time) {
+	expiresAt := now.Add(a.accessTTL).Truncate(time.Second)
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+	accessToken, err := Sign(Claims{
+		Subject:   user.ID,
+		SessionID: session.ID,
+		Issuer:    Issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}, a.secret)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to issue token", err)
+		return
+	}
+
+	response.OK(w, models.LoginResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             user.ToResponse(),
+	})
+}
+
+// newRefreshToken generates an opaque refresh token
+func newRefreshToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return RefreshTokenPrefix + hex.EncodeToString(secret), nil
+}
+
+// client returns the user agent and address a session is used from
+func client(r *http.Request) (userAgent, ipAddress *string) {
+	if ua := r.UserAgent(); ua != "" {
+		userAgent = &ua
+	}
+	if ip := reqctx.From(r.Context()).IP; ip != "" {
+		ipAddress = &ip
+	}
+	return userAgent, ipAddress
+}
+
+// dummyHash is compared against when the user doesn't exist, so unknown
+// emails take as long to reject as wrong passwords
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// checkPassword reports whether user is active and password matches its
+// hash. A nil user or one without a bcrypt hash (users provisioned over
+// SCIM sign in through their identity provider) never matches.
+func checkPassword(user *models.User, password string) bool {
+	if user == nil || !strings.HasPrefix(user.PasswordHash, "$2") {
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return false
+	}
+	return user.IsActive
+}
+
+func unauthorized(w http.ResponseWriter, code, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	response.Error(w, http.StatusUnauthorized, code, message)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+func TestRequireUserAndRole(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	user := &Identity{User: &models.User{Role: models.RoleUser}}
+	editor := &Identity{User: &models.User{Role: models.RoleEditor}}
+	key := &Identity{User: &models.User{Role: models.RoleAdmin}, APIKey: &models.APIKey{Scope: models.APIKeyScopeRead}}
+
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		method     string
+		identity   *Identity
+		status     int
+	}{
+		{"anonymous read", RequireUser, http.MethodGet, nil, http.StatusUnauthorized},
+		{"anonymous write", RequireUser, http.MethodPost, nil, http.StatusUnauthorized},
+		{"user", RequireUser, http.MethodGet, user, http.StatusNoContent},
+		{"api key", RequireUser, http.MethodGet, key, http.StatusNoContent},
+		{"anonymous editor route", RequireRole(models.RoleEditor), http.MethodGet, nil, http.StatusUnauthorized},
+		{"user on editor route", RequireRole(models.RoleEditor), http.MethodGet, user, http.StatusForbidden},
+		{"editor on editor route", RequireRole(models.RoleEditor), http.MethodGet, editor, http.StatusNoContent},
+		{"editor on admin route", RequireRole(models.RoleAdmin), http.MethodPost, editor, http.StatusForbidden},
+		{"admin key on admin route", RequireRole(models.RoleAdmin), http.MethodGet, key, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		if tt.identity != nil {
+			req = req.WithContext(WithIdentity(req.Context(), tt.identity))
+		}
+		rec := httptest.NewRecorder()
+		tt.middleware(ok).ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 without WWW-Authenticate", tt.name)
+		}
+	}
+}
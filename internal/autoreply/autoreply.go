@@ -0,0 +1,178 @@
+// Package autoreply acknowledges contact submissions with a templated email
+// to the submitter, as configured in the contacts.auto_reply setting.
+package autoreply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// SettingAutoReply holds the auto-responder configuration as JSON, e.g.
+// {"enabled": true, "forms": {"sales": "contact_acknowledgment_sales"}}
+const SettingAutoReply = "contacts.auto_reply"
+
+// DefaultTemplate acknowledges submissions of forms without a template of
+// their own
+const DefaultTemplate = "contact_acknowledgment"
+
+// Metadata keys of a submission naming its form and the language of the
+// acknowledgment
+const (
+	MetadataForm   = "form"
+	MetadataLocale = "locale"
+)
+
+const (
+	// defaultMaxLinks is the number of links above which a submission is
+	// taken for spam
+	defaultMaxLinks = 2
+	// replyInterval is how long an address isn't acknowledged again after
+	// an acknowledgment, so the form can't be used to flood a mailbox
+	replyInterval = 24 * time.Hour
+)
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// Config is the contacts.auto_reply setting. Template overrides the
+// default template; Forms maps form names to their own templates.
+type Config struct {
+	Enabled  bool              `json:"enabled"`
+	Template string            `json:"template,omitempty"`
+	Forms    map[string]string `json:"forms,omitempty"`
+	MaxLinks *int              `json:"max_links,omitempty"`
+}
+
+// template returns the template acknowledging submissions of form
+func (c *Config) template(form string) string {
+	if t := c.Forms[form]; t != "" {
+		return t
+	}
+	if c.Template != "" {
+		return c.Template
+	}
+	return DefaultTemplate
+}
+
+// templates returns every template acknowledgments are sent with
+func (c *Config) templates() []string {
+	templates := []string{c.template("")}
+	for _, t := range c.Forms {
+		if t != "" {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+func (c *Config) maxLinks() int {
+	if c.MaxLinks != nil {
+		return *c.MaxLinks
+	}
+	return defaultMaxLinks
+}
+
+// Responder queues acknowledgments of new contact submissions
+type Responder struct {
+	settings *repository.SettingRepository
+	emails   *repository.EmailRepository
+	mail     *mailer.Mailer
+}
+
+func New(settings *repository.SettingRepository, emails *repository.EmailRepository, mail *mailer.Mailer) *Responder {
+	return &Responder{settings: settings, emails: emails, mail: mail}
+}
+
+// Subscribe acknowledges the submissions announced on bus
+func (r *Responder) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e events.ContactReceived) {
+		r.acknowledge(ctx, e.Contact)
+	})
+}
+
+// LoadConfig reads the auto-responder configuration; a missing setting
+// leaves it disabled
+func (r *Responder) LoadConfig(ctx context.Context) (*Config, error) {
+	values, err := r.settings.GetMultiple(ctx, []string{SettingAutoReply})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if raw := strings.TrimSpace(values[SettingAutoReply]); raw != "" {
+		if err := json.Unmarshal([]byte(raw), cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", SettingAutoReply, err)
+		}
+	}
+	return cfg, nil
+}
+
+// acknowledge queues the acknowledgment of a submission, in the language
+// in its metadata or else the request's negotiated locale, unless it looks
+// like spam or its address was acknowledged within replyInterval. Failures
+// are logged; they don't fail the submission.
+func (r *Responder) acknowledge(ctx context.Context, c *models.ContactSubmission) {
+	cfg, err := r.LoadConfig(ctx)
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] Failed to load auto-reply settings: %v", err)
+		return
+	}
+	if !cfg.Enabled {
+		return
+	}
+
+	if reason := spamReason(c, cfg.maxLinks()); reason != "" {
+		reqctx.Logf(ctx, "Contact %s not acknowledged: %s", c.ID, reason)
+		return
+	}
+	recent, err := r.emails.QueuedSince(ctx, c.Email, cfg.templates(), time.Now().Add(-replyInterval))
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] %v", err)
+		return
+	}
+	if recent {
+		reqctx.Logf(ctx, "Contact %s not acknowledged: address acknowledged in the last %s", c.ID, replyInterval)
+		return
+	}
+
+	locale := metadataString(c.Metadata, MetadataLocale)
+	if locale == "" {
+		locale = reqctx.From(ctx).Locale
+	}
+	name := cfg.template(metadataString(c.Metadata, MetadataForm))
+	if _, err := r.mail.Enqueue(ctx, c.Email, name, locale, c); err != nil {
+		reqctx.Logf(ctx, "[ERROR] Failed to queue contact acknowledgment %s: %v", name, err)
+	}
+}
+
+// spamReason returns why a submission is taken for spam, or "" when it
+// isn't
+func spamReason(c *models.ContactSubmission, maxLinks int) string {
+	text := c.Message
+	if c.Subject != nil {
+		text += " " + *c.Subject
+	}
+	if links := len(linkPattern.FindAllStringIndex(text, -1)); links > maxLinks {
+		return fmt.Sprintf("%d links in message", links)
+	}
+	return ""
+}
+
+// metadataString returns a string value of a submission's metadata, or ""
+func metadataString(metadata json.RawMessage, key string) string {
+	var values map[string]interface{}
+	if err := json.Unmarshal(metadata, &values); err != nil {
+		return ""
+	}
+	s, _ := values[key].(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a (possibly recurring) calendar entry
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Location    string
+	Start       time.Time
+	End         *time.Time
+	RRule       string
+	Updated     time.Time
+}
+
+const icalTime = "20060102T150405Z"
+
+// WriteICS writes events as an iCalendar (RFC 5545) VCALENDAR named name.
+// Recurring events carry their RRULE so calendar clients expand them.
+func WriteICS(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		// Fold lines longer than 75 octets with CRLF + space, never
+		// splitting a UTF-8 sequence
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-cms-template//events//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeText(name))
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + e.Updated.UTC().Format(icalTime))
+		line("DTSTART:" + e.Start.UTC().Format(icalTime))
+		if e.End != nil {
+			line("DTEND:" + e.End.UTC().Format(icalTime))
+		}
+		if e.RRule != "" {
+			line("RRULE:" + strings.TrimPrefix(e.RRule, "RRULE:"))
+		}
+		line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION:" + escapeText(e.Location))
+		}
+		if e.URL != "" {
+			line("URL:" + e.URL)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
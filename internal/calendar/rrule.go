@@ -0,0 +1,238 @@
+// Package calendar expands recurring events and writes iCalendar feeds.
+//
+// Recurrence rules use a subset of RFC 5545 RRULE: FREQ (DAILY, WEEKLY,
+// MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL, BYDAY (plain weekdays, no
+// ordinals) and BYMONTHDAY. Rules are evaluated in UTC.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxIterations bounds rule expansion so a sparse rule (e.g. BYMONTHDAY=31
+// every 12 months) cannot loop for long
+const maxIterations = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// ParseRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is accepted.
+func ParseRule(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			value = strings.ToUpper(value)
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				rule.Freq = value
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("unsupported BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			// Only affects multi-week intervals with BYDAY; weeks start on Monday
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot both be set")
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+// Between returns the start times of the occurrences of an event first
+// starting at dtstart that begin in [from, to), at most limit of them. The
+// first occurrence is always dtstart itself, as in RFC 5545.
+func (r *Rule) Between(dtstart, from, to time.Time, limit int) []time.Time {
+	dtstart = dtstart.UTC()
+
+	var out []time.Time
+	emitted := 0
+	done := func(t time.Time) bool {
+		return !t.Before(to) || (r.Until != nil && t.After(*r.Until)) ||
+			(r.Count > 0 && emitted >= r.Count) || len(out) >= limit
+	}
+	emit := func(t time.Time) {
+		emitted++
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+	}
+
+	if done(dtstart) {
+		return out
+	}
+	emit(dtstart)
+	for i := 0; i < maxIterations; i++ {
+		period := r.period(dtstart, i)
+		if period.IsZero() {
+			continue
+		}
+		candidates := r.expand(dtstart, period)
+		for _, t := range candidates {
+			if !t.After(dtstart) {
+				continue
+			}
+			if done(t) {
+				return out
+			}
+			emit(t)
+		}
+		if done(period) {
+			return out
+		}
+	}
+	return out
+}
+
+// period returns the i-th period after the one containing dtstart (the
+// first of the month for BYMONTHDAY rules), or the zero time when dtstart's
+// day does not exist in that period (e.g. the 31st in a short month)
+func (r *Rule) period(dtstart time.Time, i int) time.Time {
+	n := i * r.Interval
+	year, month, day := dtstart.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		t := time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC)
+		if t.Day() != day {
+			return time.Time{}
+		}
+		return t
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		return dtstart.AddDate(0, 0, n)
+	case FreqWeekly:
+		return dtstart.AddDate(0, 0, 7*n)
+	case FreqMonthly:
+		if len(r.ByMonthDay) > 0 {
+			return at(year, month+time.Month(n), 1)
+		}
+		return at(year, month+time.Month(n), day)
+	default:
+		return at(year+n, month, day)
+	}
+}
+
+// expand returns the occurrences within the period containing t, in order
+func (r *Rule) expand(dtstart, t time.Time) []time.Time {
+	clock := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC)
+	}
+
+	switch {
+	case r.Freq == FreqWeekly && len(r.ByDay) > 0:
+		// Monday-based week containing t
+		offset := (int(t.Weekday()) + 6) % 7
+		monday := t.AddDate(0, 0, -offset)
+		var out []time.Time
+		for _, wd := range r.ByDay {
+			out = append(out, monday.AddDate(0, 0, (int(wd)+6)%7))
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+		return out
+	case r.Freq == FreqMonthly && len(r.ByMonthDay) > 0:
+		var out []time.Time
+		year, month, _ := t.Date()
+		for _, day := range r.ByMonthDay {
+			d := clock(year, month, day)
+			if d.Month() == month {
+				out = append(out, d)
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+		return out
+	case r.Freq == FreqDaily && len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			if t.Weekday() == wd {
+				return []time.Time{t}
+			}
+		}
+		return nil
+	default:
+		return []time.Time{t}
+	}
+}
@@ -0,0 +1,208 @@
+// Package changefeed turns row changes on content tables, read from a
+// logical decoding slot (wal2json), into events such as post.updated. The
+// events reach every instance through PostgreSQL NOTIFY and are published
+// there to the domain events bus as events.ContentChanged, so caches are
+// invalidated even for edits made outside the API.
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+)
+
+// Channel is the NOTIFY channel events are broadcast on
+const Channel = "cms_changes"
+
+// Event is a change to a content row as broadcast on Channel. ID is the
+// row's ID (the post's for post tags and media); Key is a setting's key
+// when the change carries it. Truncating a table yields an event without
+// an ID.
+type Event struct {
+	Name  string `json:"event"`
+	Table string `json:"table"`
+	ID    string `json:"id,omitempty"`
+	Key   string `json:"key,omitempty"`
+}
+
+// source describes how changes of a watched table become events
+type source struct {
+	entity   string
+	idColumn string
+	// asUpdate reports changes of rows that belong to a parent, such as a
+	// post's tags, as updates of the parent
+	asUpdate bool
+	// quiet columns change without changing the content, such as view
+	// counts; updates of only these are dropped when the table's replica
+	// identity is FULL and old values are known
+	quiet []string
+}
+
+var sources = map[string]source{
+	"content_posts":   {entity: "post", idColumn: "id", quiet: []string{"view_count", "updated_at"}},
+	"post_tags":       {entity: "post", idColumn: "post_id", asUpdate: true},
+	"post_media":      {entity: "post", idColumn: "post_id", asUpdate: true},
+	"post_categories": {entity: "post", idColumn: "post_id", asUpdate: true},
+	"content_types":   {entity: "content_type", idColumn: "id"},
+	"tags":            {entity: "tag", idColumn: "id"},
+	"categories":      {entity: "category", idColumn: "id"},
+	"media":           {entity: "media", idColumn: "id"},
+	"settings":        {entity: "setting", idColumn: "id"},
+}
+
+// Tables returns the watched tables in wal2json's add-tables format
+func Tables() string {
+	tables := make([]string, 0, len(sources))
+	for name := range sources {
+		tables = append(tables, "public."+name)
+	}
+	sort.Strings(tables)
+	return strings.Join(tables, ",")
+}
+
+// wal2jsonChange is a change in wal2json's format-version 2
+type wal2jsonChange struct {
+	Action   string           `json:"action"`
+	Table    string           `json:"table"`
+	Columns  []wal2jsonColumn `json:"columns"`
+	Identity []wal2jsonColumn `json:"identity"`
+}
+
+type wal2jsonColumn struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Decode converts a wal2json change into an event. Transaction boundaries,
+// messages and changes of unwatched tables yield no event.
+func Decode(data string) (*Event, error) {
+	var change wal2jsonChange
+	if err := json.Unmarshal([]byte(data), &change); err != nil {
+		return nil, fmt.Errorf("invalid wal2json change: %w", err)
+	}
+	src, ok := sources[change.Table]
+	if !ok {
+		return nil, nil
+	}
+
+	var verb string
+	switch change.Action {
+	case "I":
+		verb = "created"
+	case "U":
+		verb = "updated"
+	case "D":
+		verb = "deleted"
+	case "T":
+		verb = "truncated"
+	default:
+		return nil, nil
+	}
+	if src.asUpdate {
+		verb = "updated"
+	}
+	if change.Action == "U" && quietUpdate(src, &change) {
+		return nil, nil
+	}
+
+	// Deletes carry only the replica identity (the primary key)
+	columns := change.Columns
+	if change.Action == "D" {
+		columns = change.Identity
+	}
+	event := &Event{Name: src.entity + "." + verb, Table: change.Table}
+	for _, col := range columns {
+		value, ok := col.Value.(string)
+		if !ok {
+			continue
+		}
+		switch {
+		case col.Name == src.idColumn:
+			event.ID = value
+		case change.Table == "settings" && col.Name == "key":
+			event.Key = value
+		}
+	}
+	return event, nil
+}
+
+func (e Event) domainEvent() events.ContentChanged {
+	return events.ContentChanged{Change: e.Name, Table: e.Table, ID: e.ID, Key: e.Key}
+}
+
+// quietUpdate reports whether an update changed only quiet columns. Old
+// values are only known when the replica identity covers every column.
+func quietUpdate(src source, change *wal2jsonChange) bool {
+	if len(src.quiet) == 0 || len(change.Identity) != len(change.Columns) {
+		return false
+	}
+	old := make(map[string]interface{}, len(change.Identity))
+	for _, col := range change.Identity {
+		old[col.Name] = col.Value
+	}
+	for _, col := range change.Columns {
+		if slices.Contains(src.quiet, col.Name) {
+			continue
+		}
+		if prev, ok := old[col.Name]; !ok || !reflect.DeepEqual(prev, col.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// listenRetryDelay is how long Listen waits before reconnecting
+const listenRetryDelay = 5 * time.Second
+
+// Listen receives the events broadcast on Channel and publishes them to bus
+// as events.ContentChanged until ctx is cancelled, reconnecting when the
+// connection drops. Subscribers run on the listener's goroutine.
+func Listen(ctx context.Context, pool *pgxpool.Pool, bus *events.Bus) {
+	for {
+		if err := listen(ctx, pool, bus); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Change feed listener: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, bus *events.Bus) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// The connection stays in LISTEN mode, so it never goes back to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+			log.Printf("[ERROR] Invalid change feed event %q: %v", n.Payload, err)
+			continue
+		}
+		bus.Publish(ctx, event.domainEvent())
+	}
+}
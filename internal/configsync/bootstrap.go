@@ -0,0 +1,171 @@
+package configsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Seed is the desired state read from a bootstrap directory: a document
+// plus an optional admin user
+type Seed struct {
+	Document  `yaml:",inline"`
+	AdminUser *AdminUser `yaml:"admin_user,omitempty"`
+}
+
+// AdminUser is an account that must exist with the admin role. Its
+// password is never read from files.
+type AdminUser struct {
+	Email    string `yaml:"email"`
+	FullName string `yaml:"full_name"`
+}
+
+// AdminResult is what seeding did to the admin user
+type AdminResult struct {
+	Email  string `json:"email"`
+	Action Action `json:"action"`
+}
+
+// SeedResult is the outcome of applying a seed
+type SeedResult struct {
+	Plan      *Plan        `json:"plan"`
+	AdminUser *AdminResult `json:"admin_user,omitempty"`
+}
+
+// LoadDir reads and merges every .yaml and .yml file in dir, in name order.
+// A content type, setting or admin user may only be defined once.
+func LoadDir(dir string) (*Seed, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bootstrap directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	seed := &Seed{}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		var part Seed
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&part); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidDocument, name, err)
+		}
+
+		seed.ContentTypes = append(seed.ContentTypes, part.ContentTypes...)
+		for key, value := range part.Settings {
+			if _, ok := seed.Settings[key]; ok {
+				return nil, fmt.Errorf("%w: %s: setting %s is defined twice", ErrInvalidDocument, name, key)
+			}
+			if seed.Settings == nil {
+				seed.Settings = make(map[string]string)
+			}
+			seed.Settings[key] = value
+		}
+		if part.AdminUser != nil {
+			if seed.AdminUser != nil {
+				return nil, fmt.Errorf("%w: %s: admin_user is defined twice", ErrInvalidDocument, name)
+			}
+			seed.AdminUser = part.AdminUser
+		}
+	}
+
+	if err := seed.validate(); err != nil {
+		return nil, err
+	}
+	if seed.AdminUser != nil {
+		seed.AdminUser.Email = strings.ToLower(strings.TrimSpace(seed.AdminUser.Email))
+		if _, err := mail.ParseAddress(seed.AdminUser.Email); err != nil {
+			return nil, fmt.Errorf("%w: admin_user needs a valid email", ErrInvalidDocument)
+		}
+		if seed.AdminUser.FullName == "" {
+			seed.AdminUser.FullName = seed.AdminUser.Email
+		}
+	}
+	return seed, nil
+}
+
+// ApplySeed reconciles the database with seed. The admin user is created
+// with adminPassword when missing; an existing one keeps their password and
+// is made an active admin.
+func (r *Reconciler) ApplySeed(ctx context.Context, seed *Seed, users *repository.UserRepository, adminPassword string) (*SeedResult, error) {
+	plan, err := r.Plan(ctx, &seed.Document)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Apply(ctx, &seed.Document, plan); err != nil {
+		return nil, err
+	}
+	result := &SeedResult{Plan: plan}
+
+	if seed.AdminUser == nil {
+		return result, nil
+	}
+	admin, err := ensureAdmin(ctx, users, seed.AdminUser, adminPassword)
+	if err != nil {
+		return nil, err
+	}
+	result.AdminUser = admin
+	return result, nil
+}
+
+func ensureAdmin(ctx context.Context, users *repository.UserRepository, want *AdminUser, password string) (*AdminResult, error) {
+	result := &AdminResult{Email: want.Email, Action: ActionUnchanged}
+
+	user, err := users.GetByEmail(ctx, want.Email)
+	if err == nil {
+		if user.Role != models.RoleAdmin || !user.IsActive {
+			if err := users.SetRole(ctx, user.ID, models.RoleAdmin, true); err != nil {
+				return nil, err
+			}
+			result.Action = ActionUpdate
+		}
+		return result, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	if password == "" {
+		return nil, fmt.Errorf("admin user %s doesn't exist and no password is set to create it", want.Email)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash admin password: %w", err)
+	}
+	err = users.Create(ctx, &models.User{
+		Email:        want.Email,
+		PasswordHash: string(hash),
+		FullName:     want.FullName,
+		Role:         models.RoleAdmin,
+		IsActive:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Action = ActionCreate
+	return result, nil
+}
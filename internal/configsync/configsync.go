@@ -0,0 +1,385 @@
+// Package configsync serializes content type definitions and setting
+// defaults to YAML, so they can live in version control, and compares such
+// a document with an instance to plan the changes that reconcile them.
+package configsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidDocument is wrapped by errors about the document's contents
+var ErrInvalidDocument = errors.New("invalid configuration document")
+
+// Document is the YAML representation of an instance's configuration.
+// Content types are matched by slug. Settings are defaults: they are created
+// when missing and never overwrite a value set on the instance.
+type Document struct {
+	ContentTypes []ContentType     `yaml:"content_types" json:"content_types"`
+	Settings     map[string]string `yaml:"settings,omitempty" json:"settings,omitempty"`
+}
+
+// ContentType is a content type definition without its instance-specific
+// ID and timestamps. IsActive defaults to true when omitted.
+type ContentType struct {
+	Slug          string                `yaml:"slug" json:"slug"`
+	Name          string                `yaml:"name" json:"name"`
+	SchemaFields  interface{}           `yaml:"schema_fields,omitempty" json:"schema_fields,omitempty"`
+	Traits        []string              `yaml:"traits,omitempty" json:"traits,omitempty"`
+	IndexedFields []models.IndexedField `yaml:"indexed_fields,omitempty" json:"indexed_fields,omitempty"`
+	SlugPattern   string                `yaml:"slug_pattern,omitempty" json:"slug_pattern,omitempty"`
+	IsActive      *bool                 `yaml:"is_active,omitempty" json:"is_active,omitempty"`
+	DisplayOrder  int                   `yaml:"display_order,omitempty" json:"display_order,omitempty"`
+}
+
+// Action is what reconciling does to one item
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+	// ActionServerOnly items exist on the instance but not in the document;
+	// they are left alone
+	ActionServerOnly Action = "server_only"
+	// ActionKept settings exist on the instance, whose value wins
+	ActionKept Action = "kept"
+)
+
+// ContentTypeChange is the planned change to one content type
+type ContentTypeChange struct {
+	Slug    string   `json:"slug"`
+	Action  Action   `json:"action"`
+	Changes []string `json:"changes,omitempty"`
+}
+
+// SettingChange is the planned change to one setting
+type SettingChange struct {
+	Key    string `json:"key"`
+	Action Action `json:"action"`
+}
+
+// Plan lists the changes that reconcile an instance with a document
+type Plan struct {
+	ContentTypes []ContentTypeChange `json:"content_types"`
+	Settings     []SettingChange     `json:"settings"`
+	Summary      map[Action]int      `json:"summary"`
+	Applied      bool                `json:"applied"`
+}
+
+// String summarises the changes the plan makes
+func (p *Plan) String() string {
+	var created, updated, settings int
+	for _, c := range p.ContentTypes {
+		switch c.Action {
+		case ActionCreate:
+			created++
+		case ActionUpdate:
+			updated++
+		}
+	}
+	for _, c := range p.Settings {
+		if c.Action == ActionCreate {
+			settings++
+		}
+	}
+	return fmt.Sprintf("%d content types created, %d updated, %d settings created", created, updated, settings)
+}
+
+// Export builds a document from an instance's content types and the
+// settings chosen for export
+func Export(contentTypes []models.ContentType, settings []models.Setting) (*Document, error) {
+	doc := &Document{ContentTypes: make([]ContentType, 0, len(contentTypes))}
+	for _, ct := range contentTypes {
+		def := ContentType{
+			Slug:          ct.Slug,
+			Name:          ct.Name,
+			Traits:        ct.Traits,
+			IndexedFields: ct.IndexedFields,
+			DisplayOrder:  ct.DisplayOrder,
+		}
+		if ct.SlugPattern != models.DefaultSlugPattern {
+			def.SlugPattern = ct.SlugPattern
+		}
+		if !ct.IsActive {
+			active := false
+			def.IsActive = &active
+		}
+		if len(ct.SchemaFields) > 0 && !bytes.Equal(ct.SchemaFields, []byte("null")) {
+			if err := json.Unmarshal(ct.SchemaFields, &def.SchemaFields); err != nil {
+				return nil, fmt.Errorf("content type %s: failed to decode schema fields: %w", ct.Slug, err)
+			}
+		}
+		doc.ContentTypes = append(doc.ContentTypes, def)
+	}
+	sort.Slice(doc.ContentTypes, func(i, j int) bool { return doc.ContentTypes[i].Slug < doc.ContentTypes[j].Slug })
+
+	for _, s := range settings {
+		if s.Value == nil {
+			continue
+		}
+		if doc.Settings == nil {
+			doc.Settings = make(map[string]string)
+		}
+		doc.Settings[s.Key] = *s.Value
+	}
+	return doc, nil
+}
+
+// Marshal encodes doc as YAML
+func Marshal(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse decodes and validates a YAML (or JSON) document
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (doc *Document) validate() error {
+	seen := make(map[string]bool)
+	for i, ct := range doc.ContentTypes {
+		switch {
+		case ct.Slug == "" || ct.Name == "":
+			return fmt.Errorf("%w: content type %d needs a slug and a name", ErrInvalidDocument, i+1)
+		case seen[ct.Slug]:
+			return fmt.Errorf("%w: content type %s is defined twice", ErrInvalidDocument, ct.Slug)
+		case ct.SlugPattern != "" && !models.ValidSlugPattern(ct.SlugPattern):
+			return fmt.Errorf("%w: content type %s has invalid slug pattern %s", ErrInvalidDocument, ct.Slug, ct.SlugPattern)
+		}
+		seen[ct.Slug] = true
+		for _, t := range ct.Traits {
+			if !models.ValidTrait(t) {
+				return fmt.Errorf("%w: content type %s has unknown trait %s", ErrInvalidDocument, ct.Slug, t)
+			}
+		}
+		for _, f := range ct.IndexedFields {
+			if !f.Valid() {
+				return fmt.Errorf("%w: content type %s has invalid indexed field %s (%s)", ErrInvalidDocument, ct.Slug, f.Field, f.Type)
+			}
+		}
+		if _, err := ct.schemaJSON(); err != nil {
+			return fmt.Errorf("%w: content type %s: %v", ErrInvalidDocument, ct.Slug, err)
+		}
+	}
+	return nil
+}
+
+// schemaJSON returns the schema fields as stored, or nil when there are none
+func (ct *ContentType) schemaJSON() (json.RawMessage, error) {
+	if ct.SchemaFields == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(ct.SchemaFields)
+	if err != nil {
+		return nil, fmt.Errorf("schema_fields can't be stored as JSON: %w", err)
+	}
+	return data, nil
+}
+
+func (ct *ContentType) active() bool {
+	return ct.IsActive == nil || *ct.IsActive
+}
+
+func (ct *ContentType) slugPattern() string {
+	if ct.SlugPattern == "" {
+		return models.DefaultSlugPattern
+	}
+	return ct.SlugPattern
+}
+
+// CreateRequest returns the request that creates ct
+func (ct *ContentType) CreateRequest() *models.CreateContentTypeRequest {
+	schema, _ := ct.schemaJSON()
+	active := ct.active()
+	order := ct.DisplayOrder
+	return &models.CreateContentTypeRequest{
+		Name:          ct.Name,
+		Slug:          ct.Slug,
+		SchemaFields:  schema,
+		Traits:        ct.Traits,
+		IndexedFields: ct.IndexedFields,
+		SlugPattern:   ct.slugPattern(),
+		IsActive:      &active,
+		DisplayOrder:  &order,
+	}
+}
+
+// UpdateRequest returns the request that makes current match ct, and the
+// names of the fields it changes
+func (ct *ContentType) UpdateRequest(current *models.ContentType) (*models.UpdateContentTypeRequest, []string) {
+	req := &models.UpdateContentTypeRequest{}
+	var changes []string
+
+	if ct.Name != current.Name {
+		req.Name = &ct.Name
+		changes = append(changes, "name")
+	}
+	schema, _ := ct.schemaJSON()
+	if !sameJSON(schema, current.SchemaFields) {
+		if schema == nil {
+			schema = json.RawMessage("null")
+		}
+		req.SchemaFields = &schema
+		changes = append(changes, "schema_fields")
+	}
+	if !sameTraits(ct.Traits, current.Traits) {
+		traits := ct.Traits
+		if traits == nil {
+			traits = []string{}
+		}
+		req.Traits = &traits
+		changes = append(changes, "traits")
+	}
+	if !sameIndexedFields(ct.IndexedFields, current.IndexedFields) {
+		fields := ct.IndexedFields
+		if fields == nil {
+			fields = []models.IndexedField{}
+		}
+		req.IndexedFields = &fields
+		changes = append(changes, "indexed_fields")
+	}
+	if pattern := ct.slugPattern(); pattern != current.SlugPattern {
+		req.SlugPattern = &pattern
+		changes = append(changes, "slug_pattern")
+	}
+	if active := ct.active(); active != current.IsActive {
+		req.IsActive = &active
+		changes = append(changes, "is_active")
+	}
+	if ct.DisplayOrder != current.DisplayOrder {
+		req.DisplayOrder = &ct.DisplayOrder
+		changes = append(changes, "display_order")
+	}
+	return req, changes
+}
+
+// Compare plans the changes that reconcile an instance with doc. settings
+// holds the keys of the document's settings that exist on the instance.
+func Compare(doc *Document, current []models.ContentType, settings map[string]bool) *Plan {
+	plan := &Plan{
+		ContentTypes: []ContentTypeChange{},
+		Settings:     []SettingChange{},
+		Summary:      make(map[Action]int),
+	}
+
+	bySlug := make(map[string]*models.ContentType, len(current))
+	for i := range current {
+		bySlug[current[i].Slug] = &current[i]
+	}
+
+	inDoc := make(map[string]bool, len(doc.ContentTypes))
+	for _, ct := range doc.ContentTypes {
+		inDoc[ct.Slug] = true
+		change := ContentTypeChange{Slug: ct.Slug, Action: ActionCreate}
+		if existing, ok := bySlug[ct.Slug]; ok {
+			change.Action = ActionUnchanged
+			if _, changes := ct.UpdateRequest(existing); len(changes) > 0 {
+				change.Action = ActionUpdate
+				change.Changes = changes
+			}
+		}
+		plan.ContentTypes = append(plan.ContentTypes, change)
+	}
+	for _, ct := range current {
+		if !inDoc[ct.Slug] {
+			plan.ContentTypes = append(plan.ContentTypes, ContentTypeChange{Slug: ct.Slug, Action: ActionServerOnly})
+		}
+	}
+	sort.SliceStable(plan.ContentTypes, func(i, j int) bool { return plan.ContentTypes[i].Slug < plan.ContentTypes[j].Slug })
+
+	keys := make([]string, 0, len(doc.Settings))
+	for key := range doc.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		action := ActionCreate
+		if settings[key] {
+			action = ActionKept
+		}
+		plan.Settings = append(plan.Settings, SettingChange{Key: key, Action: action})
+	}
+
+	for _, c := range plan.ContentTypes {
+		plan.Summary[c.Action]++
+	}
+	for _, c := range plan.Settings {
+		plan.Summary[c.Action]++
+	}
+	return plan
+}
+
+// sameJSON compares JSON values ignoring formatting and key order
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// sameTraits compares trait lists ignoring order
+func sameIndexedFields(a, b []models.IndexedField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[models.IndexedField]bool, len(a))
+	for _, f := range a {
+		set[f] = true
+	}
+	for _, f := range b {
+		if !set[f] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTraits(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	for _, t := range b {
+		if !set[t] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package configsync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// Reconciler plans and applies documents against the database
+type Reconciler struct {
+	contentTypes *repository.ContentTypeRepository
+	settings     *repository.SettingRepository
+}
+
+func NewReconciler(contentTypes *repository.ContentTypeRepository, settings *repository.SettingRepository) *Reconciler {
+	return &Reconciler{contentTypes: contentTypes, settings: settings}
+}
+
+// Plan compares doc with the database
+func (r *Reconciler) Plan(ctx context.Context, doc *Document) (*Plan, error) {
+	current, err := r.contentTypes.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(doc.Settings))
+	for key := range doc.Settings {
+		keys = append(keys, key)
+	}
+	existing := make(map[string]bool)
+	if len(keys) > 0 {
+		values, err := r.settings.GetMultiple(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for key := range values {
+			existing[key] = true
+		}
+	}
+
+	return Compare(doc, current, existing), nil
+}
+
+// Apply carries out plan, stopping at the first failure; changes made
+// before it are kept. Applying the same document again changes nothing.
+func (r *Reconciler) Apply(ctx context.Context, doc *Document, plan *Plan) error {
+	defs := make(map[string]*ContentType, len(doc.ContentTypes))
+	for i := range doc.ContentTypes {
+		defs[doc.ContentTypes[i].Slug] = &doc.ContentTypes[i]
+	}
+
+	for _, change := range plan.ContentTypes {
+		def := defs[change.Slug]
+		switch change.Action {
+		case ActionCreate:
+			if _, err := r.contentTypes.Create(ctx, def.CreateRequest()); err != nil {
+				return err
+			}
+		case ActionUpdate:
+			current, err := r.contentTypes.GetBySlug(ctx, change.Slug)
+			if err != nil {
+				return err
+			}
+			req, _ := def.UpdateRequest(current)
+			if _, err := r.contentTypes.Update(ctx, current.ID, req); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, change := range plan.Settings {
+		if change.Action != ActionCreate {
+			continue
+		}
+		value := doc.Settings[change.Key]
+		_, err := r.settings.Create(ctx, &models.CreateSettingRequest{Key: change.Key, Value: &value})
+		if err != nil && !errors.Is(err, repository.ErrDuplicate) {
+			return err
+		}
+	}
+
+	plan.Applied = true
+	return nil
+}
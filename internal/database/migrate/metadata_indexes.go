@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// metadataIndexPrefix starts the names of the expression indexes created
+// for declared metadata fields; other indexes are never touched
+const metadataIndexPrefix = "idx_content_posts_meta_"
+
+// metadataIndexExprs are the indexed expressions by field type, matching
+// the range filter conditions of the post repository
+var metadataIndexExprs = map[string]string{
+	models.IndexedFieldNumber: `metadata_number(metadata, '%s')`,
+	models.IndexedFieldString: `(metadata_string(metadata, '%s') COLLATE "C")`,
+}
+
+func metadataIndexName(f models.IndexedField) string {
+	return metadataIndexPrefix + f.Type + "_" + f.Field
+}
+
+// indexMetadataFields creates the expression indexes on content_posts for
+// the metadata fields content types declare in indexed_fields, and drops
+// those no content type declares any more. Up runs it under the migration
+// lock after applying migrations. Indexes are built concurrently, so posts
+// stay writable; one left invalid by a failed build is rebuilt.
+func indexMetadataFields(ctx context.Context, conn *pgx.Conn) (created, dropped int, err error) {
+	rows, err := conn.Query(ctx, `
+		SELECT DISTINCT f->>'field', f->>'type'
+		FROM content_types, jsonb_array_elements(indexed_fields) f`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list indexed metadata fields: %w", err)
+	}
+	declared := make(map[string]models.IndexedField)
+	for rows.Next() {
+		var f models.IndexedField
+		if err := rows.Scan(&f.Field, &f.Type); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan indexed metadata field: %w", err)
+		}
+		// The field name ends up in DDL, so only validated names do
+		if !f.Valid() {
+			log.Printf("[WARN] Not indexing invalid metadata field %q (%s)", f.Field, f.Type)
+			continue
+		}
+		declared[metadataIndexName(f)] = f
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to list indexed metadata fields: %w", err)
+	}
+
+	rows, err = conn.Query(ctx, `
+		SELECT c.relname, i.indisvalid
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE i.indrelid = 'content_posts'::regclass AND starts_with(c.relname, $1)`, metadataIndexPrefix)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list metadata indexes: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var valid bool
+		if err := rows.Scan(&name, &valid); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan metadata index: %w", err)
+		}
+		existing[name] = valid
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to list metadata indexes: %w", err)
+	}
+
+	for name, valid := range existing {
+		if _, ok := declared[name]; ok && valid {
+			continue
+		}
+		if _, err := conn.Exec(ctx, `DROP INDEX CONCURRENTLY IF EXISTS `+pgx.Identifier{name}.Sanitize()); err != nil {
+			return created, dropped, fmt.Errorf("failed to drop metadata index %s: %w", name, err)
+		}
+		delete(existing, name)
+		dropped++
+	}
+
+	for name, f := range declared {
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		expr := fmt.Sprintf(metadataIndexExprs[f.Type], f.Field)
+		sql := fmt.Sprintf(`CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON content_posts (%s)`, pgx.Identifier{name}.Sanitize(), expr)
+		if _, err := conn.Exec(ctx, sql); err != nil {
+			return created, dropped, fmt.Errorf("failed to create metadata index %s: %w", name, err)
+		}
+		created++
+	}
+	return created, dropped, nil
+}
@@ -0,0 +1,273 @@
+// Package migrate applies the SQL migrations embedded in the binary and
+// records them in the schema_migrations table. Migrations are the files of
+// the migrations directory named <version>_<name>.sql, applied in version
+// order, each in its own transaction.
+//
+// The files use tern's layout (github.com/jackc/tern/v2): the statements
+// above a "---- create above / drop below ----" line migrate up and the ones
+// below it, if any, roll the migration back. Up only runs the part above.
+package migrate
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// Migration statuses
+const (
+	StatusUpToDate = "up_to_date"
+	StatusPending  = "pending"
+	// StatusAhead means the database has migrations this binary doesn't
+	// know, as after rolling back to an older release
+	StatusAhead = "ahead"
+)
+
+// lockKey is the advisory lock held while migrating, so instances starting
+// together apply each migration once
+const lockKey int64 = 0x6d69677261746531
+
+// unlockTimeout bounds releasing the lock, which also runs after the
+// caller's context has been cancelled
+const unlockTimeout = 5 * time.Second
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// downSeparator is tern's line between the up and down parts of a file
+const downSeparator = "---- create above / drop below ----"
+
+type migration struct {
+	version int
+	name    string
+	file    string
+}
+
+// migrations are the embedded migrations by ascending version
+var migrations = mustLoad()
+
+func mustLoad() []migration {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		panic(fmt.Sprintf("migrate: %v", err))
+	}
+
+	var list []migration
+	seen := make(map[int]string)
+	for _, e := range entries {
+		match := fileNamePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			panic(fmt.Sprintf("migrate: %s is not named <version>_<name>.sql", e.Name()))
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			panic(fmt.Sprintf("migrate: %s and %s have the same version", other, e.Name()))
+		}
+		seen[version] = e.Name()
+		list = append(list, migration{version: version, name: match[2], file: "migrations/" + e.Name()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+	// tern requires versions to run from 1 without gaps
+	for i, mig := range list {
+		if mig.version != i+1 {
+			panic(fmt.Sprintf("migrate: %s should have version %d", mig.file, i+1))
+		}
+	}
+	return list
+}
+
+// Latest returns the version of the newest embedded migration
+func Latest() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+type Migrator struct {
+	db *pgxpool.Pool
+}
+
+func New(db *pgxpool.Pool) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Up applies the migrations not applied yet and returns how many it
+// applied, then brings the indexes of declared metadata fields in line with
+// the content types. When schema_migrations is empty, the migrations up to baseline
+// are recorded as applied without running them, for databases whose schema
+// was created from the SQL files by hand.
+func (m *Migrator) Up(ctx context.Context, baseline int) (int, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return 0, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			log.Printf("[ERROR] Failed to unlock migrations: %v", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+		    version INTEGER PRIMARY KEY,
+		    name VARCHAR(255) NOT NULL,
+		    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) == 0 && baseline > 0 {
+		for _, mig := range migrations {
+			if mig.version > baseline {
+				break
+			}
+			if _, err := conn.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.version, mig.name); err != nil {
+				return 0, fmt.Errorf("failed to record baseline migration %d: %w", mig.version, err)
+			}
+			applied[mig.version] = true
+			log.Printf("Recorded migration %d (%s) as applied (baseline)", mig.version, mig.name)
+		}
+	}
+
+	count := 0
+	for _, mig := range migrations {
+		if applied[mig.version] {
+			continue
+		}
+		if err := apply(ctx, conn.Conn(), mig); err != nil {
+			return count, err
+		}
+		log.Printf("Applied migration %d (%s)", mig.version, mig.name)
+		count++
+	}
+
+	created, dropped, err := indexMetadataFields(ctx, conn.Conn())
+	if err != nil {
+		return count, err
+	}
+	if created > 0 || dropped > 0 {
+		log.Printf("Metadata indexes: %d created, %d dropped", created, dropped)
+	}
+	return count, nil
+}
+
+func apply(ctx context.Context, conn *pgx.Conn, mig migration) error {
+	sql, err := files.ReadFile(mig.file)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %d: %w", mig.version, err)
+	}
+	up, _, _ := strings.Cut(string(sql), downSeparator)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Without arguments the file runs over the simple protocol, which
+	// allows several statements
+	if _, err := tx.Exec(ctx, up); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", mig.version, mig.name, err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.version, mig.name); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", mig.version, err)
+	}
+	return tx.Commit(ctx)
+}
+
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int]bool, error) {
+	rows, err := conn.Query(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// Status compares the migrations recorded in the database with the
+// embedded ones. A database without schema_migrations has none applied.
+func (m *Migrator) Status(ctx context.Context) (*models.MigrationStatus, error) {
+	status := &models.MigrationStatus{
+		Latest:  Latest(),
+		Pending: []int{},
+		Applied: []models.AppliedMigration{},
+	}
+
+	var exists bool
+	if err := m.db.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+	applied := make(map[int]bool)
+	if exists {
+		rows, err := m.db.Query(ctx, `SELECT version, name, applied_at FROM schema_migrations ORDER BY version`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a models.AppliedMigration
+			if err := rows.Scan(&a.Version, &a.Name, &a.AppliedAt); err != nil {
+				return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+			}
+			status.Applied = append(status.Applied, a)
+			applied[a.Version] = true
+			status.Version = a.Version
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+	}
+
+	for _, mig := range migrations {
+		if !applied[mig.version] {
+			status.Pending = append(status.Pending, mig.version)
+		}
+	}
+	switch {
+	case len(status.Pending) > 0:
+		status.Status = StatusPending
+	case status.Version > status.Latest:
+		status.Status = StatusAhead
+	default:
+		status.Status = StatusUpToDate
+	}
+	return status, nil
+}
@@ -0,0 +1,754 @@
+-- Core user management
+CREATE TABLE users (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    role SMALLINT NOT NULL DEFAULT 1 CHECK (role BETWEEN 1 AND 3),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_login TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Login sessions. token is the SHA-256 hash of the current refresh token
+-- and previous_token that of the one it replaced, so a rotated-out token
+-- presented again can be recognized as stolen. Access tokens carry the
+-- session ID and stop working when the session is deleted.
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(500) NOT NULL UNIQUE,
+    previous_token VARCHAR(500),
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_previous_token ON sessions(previous_token);
+
+-- API keys for machine clients, sent in the X-API-Key header. key_hash is
+-- the SHA-256 hash of the key; requests act as the issuing user, limited
+-- to reads for read-scoped keys.
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    key_prefix VARCHAR(12) NOT NULL,
+    scope VARCHAR(10) NOT NULL DEFAULT 'read' CHECK (scope IN ('read', 'full')),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- Teams own content spaces; posts and media without a team are shared
+CREATE TABLE teams (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Team roles: 1 viewer, 2 editor, 3 manager
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role SMALLINT NOT NULL DEFAULT 2 CHECK (role BETWEEN 1 AND 3),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id)
+);
+
+-- Content management
+CREATE TABLE content_types (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    schema_fields JSONB,
+    -- Opt-in behaviours: expiring, geolocated, priced, bookable
+    traits TEXT[] NOT NULL DEFAULT '{}',
+    -- Site path of posts, from the {year}, {month}, {type} and {slug} tokens
+    slug_pattern VARCHAR(255) NOT NULL DEFAULT 'posts/{slug}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    display_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE content_posts (
+    id UUID PRIMARY KEY,
+    content_type_id UUID NOT NULL REFERENCES content_types(id) ON DELETE RESTRICT,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    title VARCHAR(500) NOT NULL,
+    slug VARCHAR(500) NOT NULL,
+    excerpt TEXT,
+    content TEXT,
+    metadata JSONB,
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 3),
+    published_at TIMESTAMP WITH TIME ZONE,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    -- 'live' or 'draft'; draft copies of live posts point at them via live_post_id
+    environment VARCHAR(20) NOT NULL DEFAULT 'live' CHECK (environment IN ('live', 'draft')),
+    live_post_id UUID UNIQUE REFERENCES content_posts(id) ON DELETE CASCADE,
+    -- Optional WGS 84 location for near queries
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    -- Event posts: first occurrence and optional RRULE (evaluated in UTC)
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE CHECK (ends_at >= starts_at),
+    recurrence VARCHAR(500),
+    -- Listings of expiring content types are archived after this
+    expires_at TIMESTAMP WITH TIME ZONE,
+    -- Owning team's content space; NULL for shared posts
+    team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(environment, slug),
+    CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+-- Daily view rollups (pruned after VIEW_RETENTION_DAYS)
+CREATE TABLE post_view_daily (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day)
+);
+
+-- Daily views by traffic source, pruned with post_view_daily. referrer is
+-- the referring domain; referrer and UTM values are '' when absent.
+CREATE TABLE post_traffic_daily (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    referrer VARCHAR(255) NOT NULL DEFAULT '',
+    utm_source VARCHAR(100) NOT NULL DEFAULT '',
+    utm_medium VARCHAR(100) NOT NULL DEFAULT '',
+    utm_campaign VARCHAR(100) NOT NULL DEFAULT '',
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day, referrer, utm_source, utm_medium, utm_campaign)
+);
+
+-- Headline A/B testing (first variant per post is the control)
+CREATE TABLE post_title_variants (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    title VARCHAR(500) NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(post_id, title)
+);
+
+-- Media management
+CREATE TABLE media (
+    id UUID PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    object_key VARCHAR(1000) NOT NULL UNIQUE,
+    bucket_name VARCHAR(255) NOT NULL,
+    cdn_url VARCHAR(1000),
+    file_type SMALLINT NOT NULL CHECK (file_type BETWEEN 1 AND 3),
+    mime_type VARCHAR(100) NOT NULL,
+    file_size INTEGER NOT NULL,
+    dimensions JSONB,
+    variants JSONB,
+    alt_text VARCHAR(500),
+    checksum VARCHAR(64),
+    team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE post_media (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    media_role SMALLINT NOT NULL CHECK (media_role BETWEEN 1 AND 3),
+    display_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(post_id, media_id)
+);
+
+-- Queue of media objects (files and their variants) to copy to the replica
+-- storage. status is 1 pending, 2 copying, 3 replicated or 4 failed.
+CREATE TABLE media_replications (
+    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    object_key VARCHAR(1000) NOT NULL,
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 4),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    replicated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (media_id, object_key)
+);
+
+CREATE INDEX idx_media_replications_due ON media_replications(status, next_attempt_at);
+
+-- Tagging system
+CREATE TABLE tags (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE post_tags (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+-- Hierarchical categories. A category with subcategories can't be deleted
+-- until they are moved or deleted.
+CREATE TABLE categories (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    display_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE post_categories (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, category_id)
+);
+
+-- Internal editorial notes on posts, never served publicly. Replies point at
+-- the top-level annotation of their thread, which holds the optional anchor
+-- (a block ID and/or character range) and the resolve state.
+CREATE TABLE post_annotations (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES post_annotations(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    body TEXT NOT NULL,
+    block_id VARCHAR(100),
+    range_start INTEGER CHECK (range_start >= 0),
+    range_end INTEGER CHECK (range_end >= range_start),
+    quote TEXT,
+    mention_ids UUID[] NOT NULL DEFAULT '{}',
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_post_annotations_post_id ON post_annotations(post_id, created_at);
+CREATE INDEX idx_post_annotations_parent_id ON post_annotations(parent_id);
+
+-- Posts whose publication is not shared on social networks
+CREATE TABLE social_share_optouts (
+    post_id UUID PRIMARY KEY REFERENCES content_posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sharing history: one row per post and social account, written as pending
+-- before the network is called so a post is never shared twice. The title
+-- is kept so the history survives the post's deletion.
+CREATE TABLE social_shares (
+    id UUID PRIMARY KEY,
+    post_id UUID REFERENCES content_posts(id) ON DELETE SET NULL,
+    post_title VARCHAR(500) NOT NULL,
+    account VARCHAR(100) NOT NULL,
+    driver VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    message TEXT NOT NULL,
+    link TEXT NOT NULL,
+    image_url TEXT,
+    external_id VARCHAR(255),
+    external_url TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shared_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(post_id, account)
+);
+
+CREATE INDEX idx_social_shares_created_at ON social_shares(created_at DESC);
+
+-- Starting points for new posts of a content type. Templates with a team
+-- belong to that team's space; tag IDs of deleted tags are ignored.
+CREATE TABLE post_templates (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    content_type_id UUID NOT NULL REFERENCES content_types(id) ON DELETE CASCADE,
+    team_id UUID REFERENCES teams(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    title_pattern VARCHAR(500),
+    excerpt TEXT,
+    content TEXT,
+    metadata JSONB,
+    tag_ids UUID[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (content_type_id, name)
+);
+
+CREATE INDEX idx_post_templates_team_id ON post_templates(team_id);
+
+-- Consent tracking
+CREATE TABLE consent_versions (
+    id UUID PRIMARY KEY,
+    version VARCHAR(50) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    document_url VARCHAR(1000),
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Contact and settings
+CREATE TABLE contact_submissions (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    subject VARCHAR(500),
+    message TEXT NOT NULL,
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 4),
+    ip_address INET,
+    user_agent TEXT,
+    metadata JSONB,
+    read_at TIMESTAMP WITH TIME ZONE,
+    consent_version_id UUID REFERENCES consent_versions(id) ON DELETE RESTRICT,
+    consent_accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE blocklist_rules (
+    id UUID PRIMARY KEY,
+    rule_type SMALLINT NOT NULL CHECK (rule_type BETWEEN 1 AND 3),
+    value VARCHAR(255) NOT NULL,
+    action SMALLINT NOT NULL DEFAULT 1 CHECK (action BETWEEN 1 AND 2),
+    reason TEXT,
+    hit_count BIGINT NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(rule_type, value)
+);
+
+-- Addresses flagged by the anomaly detector or set by an admin. status is
+-- 1 challenge (CAPTCHA required), 2 banned or 3 allowed; manual entries are
+-- never changed by the detector. A NULL expires_at never expires.
+CREATE TABLE flagged_ips (
+    ip VARCHAR(45) PRIMARY KEY,
+    status SMALLINT NOT NULL CHECK (status BETWEEN 1 AND 3),
+    manual BOOLEAN NOT NULL DEFAULT false,
+    reason TEXT,
+    flag_count INTEGER NOT NULL DEFAULT 1,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_flagged_ips_updated ON flagged_ips(updated_at DESC);
+
+-- Read-only tokens for the public delivery API. Only a SHA-256 of the token
+-- is stored. Empty scopes allow all content types, locales or environments.
+CREATE TABLE delivery_tokens (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(12) NOT NULL,
+    content_types TEXT[] NOT NULL DEFAULT '{}',
+    locales TEXT[] NOT NULL DEFAULT '{}',
+    environments TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE email_templates (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    locale VARCHAR(20) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body_html TEXT,
+    body_text TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(name, locale)
+);
+
+CREATE TABLE subscribers (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255),
+    locale VARCHAR(20) NOT NULL DEFAULT 'en',
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 2),
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    unsubscribed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    subject VARCHAR(255),
+    intro TEXT,
+    template_name VARCHAR(100) NOT NULL,
+    post_ids UUID[] NOT NULL,
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 3),
+    queued_count INTEGER NOT NULL DEFAULT 0,
+    last_subscriber_id UUID,
+    next_batch_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Editor digest subscriptions; frequency is 'off', 'daily' or 'weekly'
+CREATE TABLE digest_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'off' CHECK (frequency IN ('off', 'daily', 'weekly')),
+    -- Empty means every section
+    sections TEXT[] NOT NULL DEFAULT '{}',
+    locale VARCHAR(20),
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE email_queue (
+    id UUID PRIMARY KEY,
+    to_address VARCHAR(255) NOT NULL,
+    template_name VARCHAR(100),
+    locale VARCHAR(20),
+    subject VARCHAR(255) NOT NULL,
+    body_html TEXT,
+    body_text TEXT,
+    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 4),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    opened_at TIMESTAMP WITH TIME ZONE,
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    -- X-Request-ID of the request that queued the email, sent as a mail header
+    request_id VARCHAR(128),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE settings (
+    id UUID PRIMARY KEY,
+    key VARCHAR(100) NOT NULL UNIQUE,
+    value TEXT,
+    description TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE themes (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    version INTEGER NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT false,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(name, version)
+);
+
+CREATE TABLE theme_files (
+    theme_id UUID NOT NULL REFERENCES themes(id) ON DELETE CASCADE,
+    path VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    content BYTEA NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (theme_id, path)
+);
+
+CREATE TABLE api_usage_daily (
+    day DATE NOT NULL,
+    client VARCHAR(100) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    last_call_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (day, client, method, route)
+);
+
+CREATE TABLE access_log (
+    id UUID PRIMARY KEY,
+    resource VARCHAR(50) NOT NULL,
+    resource_id UUID,
+    action VARCHAR(20) NOT NULL,
+    client VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    request_id VARCHAR(100),
+    query TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Polls (voter_hash is a SHA-256 of the poll and the client IP or visitor ID)
+CREATE TABLE polls (
+    id UUID PRIMARY KEY,
+    question VARCHAR(500) NOT NULL,
+    opens_at TIMESTAMP WITH TIME ZONE,
+    closes_at TIMESTAMP WITH TIME ZONE,
+    dedup_by VARCHAR(20) NOT NULL DEFAULT 'ip' CHECK (dedup_by IN ('ip', 'visitor')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE poll_options (
+    id UUID PRIMARY KEY,
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    label VARCHAR(500) NOT NULL,
+    position INTEGER NOT NULL,
+    UNIQUE(poll_id, position)
+);
+
+CREATE TABLE poll_votes (
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id UUID NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    voter_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, voter_hash)
+);
+
+-- Near-duplicate posts found by the duplicate detector (post_id < duplicate_id);
+-- replaced on every scan
+CREATE TABLE post_duplicates (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    duplicate_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    similarity DOUBLE PRECISION NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, duplicate_id),
+    CHECK (post_id < duplicate_id)
+);
+
+-- Post references to media, posts or tags that no longer exist, found by the
+-- reference checker; replaced on every scan. cleaned is set when the scan
+-- removed the reference from the post's metadata.
+CREATE TABLE post_dangling_references (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('metadata', 'content')),
+    path VARCHAR(500) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('media', 'post', 'tag')),
+    referenced_id UUID NOT NULL,
+    cleaned BOOLEAN NOT NULL DEFAULT false,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, source, path, referenced_id)
+);
+
+-- Approval chains: the steps posts of a content type are approved in, a
+-- JSON array of {"name", "role", "sla_hours", "escalate_to"}. Roles are
+-- approval roles held by users, such as "legal" or "publisher".
+CREATE TABLE approval_chains (
+    content_type_id UUID PRIMARY KEY REFERENCES content_types(id) ON DELETE CASCADE,
+    steps JSONB NOT NULL CHECK (jsonb_typeof(steps) = 'array'),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_approval_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+-- A post's way through its approval chain. steps is the chain as it was
+-- when approval was requested and step the index of the current one, whose
+-- clock started at step_started_at; escalated_at is set once the current
+-- step missed its SLA. Status: 1 pending, 2 approved, 3 rejected,
+-- 4 cancelled.
+CREATE TABLE approval_requests (
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    steps JSONB NOT NULL,
+    step SMALLINT NOT NULL DEFAULT 0,
+    status SMALLINT NOT NULL DEFAULT 1,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    step_started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    escalated_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Decisions on approval steps. Verdict: 1 approved, 2 rejected.
+CREATE TABLE approval_decisions (
+    id UUID PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES approval_requests(id) ON DELETE CASCADE,
+    step SMALLINT NOT NULL,
+    step_name VARCHAR(100) NOT NULL,
+    verdict SMALLINT NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Days after which published posts of a content type are due for review;
+-- content types without a policy use STALE_CONTENT_DAYS
+CREATE TABLE content_review_policies (
+    content_type_id UUID PRIMARY KEY REFERENCES content_types(id) ON DELETE CASCADE,
+    review_after_days INTEGER NOT NULL CHECK (review_after_days > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Content reviews of posts: the last acknowledgment that a post is still
+-- fresh, which restarts its review period like an edit does, and the last
+-- review reminder sent for it
+CREATE TABLE post_reviews (
+    post_id UUID PRIMARY KEY REFERENCES content_posts(id) ON DELETE CASCADE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    reminded_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Caching hints for the public endpoints of a content type's posts and of
+-- single posts: Cache-Control ages in seconds (NULL leaves them to the
+-- content type or the endpoint) and Surrogate-Key values for CDN purges
+CREATE TABLE content_type_cache_hints (
+    content_type_id UUID PRIMARY KEY REFERENCES content_types(id) ON DELETE CASCADE,
+    max_age INTEGER CHECK (max_age >= 0),
+    s_maxage INTEGER CHECK (s_maxage >= 0),
+    surrogate_keys TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE post_cache_hints (
+    post_id UUID PRIMARY KEY REFERENCES content_posts(id) ON DELETE CASCADE,
+    max_age INTEGER CHECK (max_age >= 0),
+    s_maxage INTEGER CHECK (s_maxage >= 0),
+    surrogate_keys TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Dashboard aggregates, refreshed in the background so the dashboard doesn't
+-- scan the raw tables (REFRESH ... CONCURRENTLY needs their unique indexes).
+-- dashboard_refreshes records when each was last refreshed.
+CREATE MATERIALIZED VIEW dashboard_post_counts AS
+SELECT cp.content_type_id, ct.name AS content_type, cp.status, COUNT(*) AS posts
+FROM content_posts cp
+JOIN content_types ct ON ct.id = cp.content_type_id
+WHERE cp.environment = 'live'
+GROUP BY cp.content_type_id, ct.name, cp.status;
+
+CREATE MATERIALIZED VIEW dashboard_daily_views AS
+SELECT day, SUM(views)::BIGINT AS views, COUNT(*) AS posts
+FROM post_view_daily
+GROUP BY day;
+
+CREATE MATERIALIZED VIEW dashboard_tag_counts AS
+SELECT t.id AS tag_id, t.name, t.slug, COUNT(*) AS posts
+FROM tags t
+JOIN post_tags pt ON pt.tag_id = t.id
+JOIN content_posts cp ON cp.id = pt.post_id
+WHERE cp.environment = 'live' AND cp.status = 2
+GROUP BY t.id, t.name, t.slug;
+
+CREATE TABLE dashboard_refreshes (
+    view_name VARCHAR(100) PRIMARY KEY,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms INTEGER NOT NULL
+);
+
+-- Indexes for performance
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_token ON sessions(token, expires_at);
+CREATE INDEX idx_sessions_expires ON sessions(expires_at);
+CREATE INDEX idx_content_posts_type_status ON content_posts(content_type_id, status);
+CREATE INDEX idx_content_posts_author ON content_posts(author_id);
+CREATE INDEX idx_content_posts_slug ON content_posts(slug);
+CREATE INDEX idx_content_posts_published ON content_posts(published_at DESC) WHERE status = 2;
+CREATE INDEX idx_content_posts_status ON content_posts(status);
+CREATE INDEX idx_content_posts_environment ON content_posts(environment);
+CREATE INDEX idx_content_posts_expires_at ON content_posts(expires_at) WHERE expires_at IS NOT NULL;
+CREATE INDEX idx_content_posts_starts_at ON content_posts(starts_at) WHERE starts_at IS NOT NULL;
+CREATE INDEX idx_content_posts_location ON content_posts(latitude, longitude) WHERE latitude IS NOT NULL;
+CREATE INDEX idx_content_posts_metadata ON content_posts USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_content_posts_team ON content_posts(team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_post_view_daily_day ON post_view_daily(day);
+CREATE INDEX idx_post_traffic_daily_day ON post_traffic_daily(day);
+CREATE INDEX idx_api_usage_daily_client ON api_usage_daily(client, day);
+CREATE INDEX idx_access_log_resource ON access_log(resource, resource_id, created_at DESC);
+CREATE INDEX idx_access_log_created ON access_log(created_at);
+CREATE INDEX idx_poll_votes_option ON poll_votes(option_id);
+CREATE INDEX idx_post_duplicates_similarity ON post_duplicates(similarity DESC);
+CREATE UNIQUE INDEX idx_approval_requests_pending ON approval_requests(post_id) WHERE status = 1;
+CREATE INDEX idx_approval_requests_post ON approval_requests(post_id, created_at DESC);
+CREATE INDEX idx_approval_decisions_request ON approval_decisions(request_id, created_at);
+CREATE INDEX idx_user_approval_roles_role ON user_approval_roles(role);
+CREATE INDEX idx_content_posts_published_updated ON content_posts(updated_at) WHERE status = 2 AND environment = 'live';
+CREATE INDEX idx_post_media_post_id ON post_media(post_id);
+CREATE INDEX idx_post_media_media_id ON post_media(media_id);
+CREATE INDEX idx_post_tags_post_id ON post_tags(post_id);
+CREATE INDEX idx_post_tags_tag_id ON post_tags(tag_id);
+CREATE INDEX idx_post_categories_category_id ON post_categories(category_id);
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+CREATE INDEX idx_media_object_key ON media(object_key);
+CREATE INDEX idx_media_checksum ON media(checksum);
+CREATE INDEX idx_media_team ON media(team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_team_members_user ON team_members(user_id);
+CREATE INDEX idx_contact_status_created ON contact_submissions(status, created_at DESC);
+CREATE INDEX idx_contact_created ON contact_submissions(created_at);
+CREATE INDEX idx_contact_email ON contact_submissions(email);
+CREATE INDEX idx_contact_consent_version ON contact_submissions(consent_version_id);
+CREATE INDEX idx_contact_metadata ON contact_submissions USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_consent_versions_published ON consent_versions(published_at DESC);
+CREATE INDEX idx_email_queue_due ON email_queue(next_attempt_at) WHERE status = 1;
+CREATE INDEX idx_email_queue_status_created ON email_queue(status, created_at DESC);
+CREATE INDEX idx_email_queue_campaign ON email_queue(campaign_id) WHERE campaign_id IS NOT NULL;
+CREATE INDEX idx_email_queue_to_address ON email_queue(LOWER(to_address), created_at DESC);
+CREATE INDEX idx_subscribers_status ON subscribers(status, id);
+CREATE INDEX idx_digest_preferences_frequency ON digest_preferences(frequency) WHERE frequency <> 'off';
+CREATE INDEX idx_campaigns_due ON campaigns(next_batch_at) WHERE status = 1;
+CREATE UNIQUE INDEX idx_themes_active ON themes(is_active) WHERE is_active;
+CREATE INDEX idx_content_types_slug ON content_types(slug);
+CREATE INDEX idx_tags_slug ON tags(slug);
+CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_media_file_type ON media(file_type);
+CREATE UNIQUE INDEX idx_dashboard_post_counts ON dashboard_post_counts(content_type_id, status);
+CREATE UNIQUE INDEX idx_dashboard_daily_views ON dashboard_daily_views(day);
+CREATE UNIQUE INDEX idx_dashboard_tag_counts ON dashboard_tag_counts(tag_id);
+CREATE INDEX idx_dashboard_tag_counts_posts ON dashboard_tag_counts(posts DESC);
+
+-- Trigger function for automatic timestamp updates
+CREATE OR REPLACE FUNCTION update_updated_at_column() 
+RETURNS TRIGGER AS $$ 
+BEGIN 
+    NEW.updated_at = CURRENT_TIMESTAMP; 
+    RETURN NEW; 
+END; 
+$$ LANGUAGE plpgsql;
+
+-- Apply triggers
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_teams_updated_at BEFORE UPDATE ON teams FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_content_types_updated_at BEFORE UPDATE ON content_types FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_categories_updated_at BEFORE UPDATE ON categories FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+-- Counting a view doesn't make a post updated, or every read post would look
+-- freshly edited to stale-content reports and structured data
+CREATE TRIGGER update_content_posts_updated_at BEFORE UPDATE ON content_posts FOR EACH ROW
+    WHEN (OLD.view_count IS NOT DISTINCT FROM NEW.view_count) EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_approval_chains_updated_at BEFORE UPDATE ON approval_chains FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_approval_requests_updated_at BEFORE UPDATE ON approval_requests FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_content_review_policies_updated_at BEFORE UPDATE ON content_review_policies FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_content_type_cache_hints_updated_at BEFORE UPDATE ON content_type_cache_hints FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_post_cache_hints_updated_at BEFORE UPDATE ON post_cache_hints FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_email_templates_updated_at BEFORE UPDATE ON email_templates FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_themes_updated_at BEFORE UPDATE ON themes FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_flagged_ips_updated_at BEFORE UPDATE ON flagged_ips FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_delivery_tokens_updated_at BEFORE UPDATE ON delivery_tokens FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_polls_updated_at BEFORE UPDATE ON polls FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_settings_updated_at BEFORE UPDATE ON settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Post translations, one per post and locale. source_hash identifies the
+-- title, excerpt and content the translation was made from, so it can be
+-- reported as needing an update once they change.
+CREATE TABLE post_translations (
+    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    excerpt TEXT,
+    content TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'complete')),
+    source_hash VARCHAR(64) NOT NULL,
+    machine_translated BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, locale)
+);
+
+CREATE TRIGGER update_post_translations_updated_at BEFORE UPDATE ON post_translations FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+---- create above / drop below ----
+
+DROP TABLE post_translations;
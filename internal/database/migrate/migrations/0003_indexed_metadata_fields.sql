@@ -0,0 +1,37 @@
+-- Per-content-type declarations of the top-level metadata fields whose
+-- range filters should be indexed, as [{"field": "price", "type": "number"}].
+-- The expression indexes themselves are created after migrating, one per
+-- declared field name and type, named idx_content_posts_meta_<type>_<field>.
+ALTER TABLE content_types ADD COLUMN indexed_fields JSONB NOT NULL DEFAULT '[]';
+
+-- Range filters compare these, so the indexes on them serve the filters.
+-- Values of another JSON type are NULL and never match.
+CREATE FUNCTION metadata_number(doc JSONB, field TEXT) RETURNS NUMERIC AS $$
+    SELECT CASE WHEN jsonb_typeof(doc -> field) = 'number' THEN (doc ->> field)::numeric END
+$$ LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE;
+
+CREATE FUNCTION metadata_string(doc JSONB, field TEXT) RETURNS TEXT AS $$
+    SELECT CASE WHEN jsonb_typeof(doc -> field) = 'string' THEN doc ->> field END
+$$ LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE;
+
+-- The priced trait's range filters on price were the reason for indexing
+UPDATE content_types SET indexed_fields = '[{"field": "price", "type": "number"}]'
+WHERE 'priced' = ANY(traits);
+
+---- create above / drop below ----
+
+-- Drop the metadata indexes that depend on the functions first
+DO $$
+DECLARE
+    idx TEXT;
+BEGIN
+    FOR idx IN SELECT c.relname FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid
+        WHERE i.indrelid = 'content_posts'::regclass AND starts_with(c.relname, 'idx_content_posts_meta_') LOOP
+        EXECUTE format('DROP INDEX %I', idx);
+    END LOOP;
+END;
+$$;
+
+DROP FUNCTION metadata_string(JSONB, TEXT);
+DROP FUNCTION metadata_number(JSONB, TEXT);
+ALTER TABLE content_types DROP COLUMN indexed_fields;
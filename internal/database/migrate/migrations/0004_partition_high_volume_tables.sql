@@ -0,0 +1,89 @@
+-- Monthly range partitioning for the high-volume tables: access_log and
+-- contact_submissions by created_at, post_view_daily and post_traffic_daily
+-- by day. On a populated database this migration locks each table while it
+-- is converted and scans its rows when attached, so apply it in a
+-- maintenance window. Tables that were already partitioned by hand with
+-- the former partitioning.sql are left as they are.
+--
+-- Existing rows stay in the current month's partition, whose range starts
+-- at MINVALUE, so they are dropped along with it once that month passes the
+-- table's retention. Partitions are named <table>_pYYYYMM with UTC month
+-- bounds; the partition manager job creates the months ahead and drops
+-- expired ones.
+--
+-- There is no drop section: merging the partitions back is left to a
+-- restore from backup.
+
+CREATE FUNCTION pg_temp.partition_by_month(tbl TEXT, part_key TEXT, pk TEXT) RETURNS VOID AS $$
+DECLARE
+    month_start DATE := date_trunc('month', now() AT TIME ZONE 'UTC')::DATE;
+    part TEXT := tbl || '_p' || to_char(month_start, 'YYYYMM');
+    date_key BOOLEAN;
+    index_defs TEXT[];
+    fk_defs TEXT[];
+    def TEXT;
+    idx RECORD;
+    next_bound TEXT;
+    last_bound TEXT;
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass(tbl)) THEN
+        RAISE NOTICE '% is already partitioned', tbl;
+        RETURN;
+    END IF;
+
+    SELECT atttypid = 'date'::REGTYPE INTO date_key
+    FROM pg_attribute
+    WHERE attrelid = to_regclass(tbl) AND attname = part_key;
+
+    -- Secondary indexes and foreign keys are recreated on the partitioned
+    -- table, which adopts the old table's matching ones when it's attached
+    SELECT array_agg(pg_get_indexdef(indexrelid)) INTO index_defs
+    FROM pg_index
+    WHERE indrelid = to_regclass(tbl) AND NOT indisprimary;
+
+    SELECT array_agg(format('ALTER TABLE %I ADD CONSTRAINT %I %s', tbl, conname, pg_get_constraintdef(oid))) INTO fk_defs
+    FROM pg_constraint
+    WHERE conrelid = to_regclass(tbl) AND contype = 'f';
+
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', tbl, part);
+    FOR idx IN SELECT c.relname FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid WHERE i.indrelid = to_regclass(part) LOOP
+        EXECUTE format('ALTER INDEX %I RENAME TO %I', idx.relname, left(part || '_' || idx.relname, 63));
+    END LOOP;
+
+    EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY RANGE (%I)', tbl, part, part_key);
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (%s)', tbl, pk);
+
+    next_bound := to_char(month_start + INTERVAL '1 month', 'YYYY-MM-DD');
+    last_bound := to_char(month_start + INTERVAL '2 months', 'YYYY-MM-DD');
+    IF NOT date_key THEN
+        next_bound := next_bound || ' 00:00:00+00';
+        last_bound := last_bound || ' 00:00:00+00';
+    END IF;
+    EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (MINVALUE) TO (%L)', tbl, part, next_bound);
+    EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        tbl || '_p' || to_char(month_start + INTERVAL '1 month', 'YYYYMM'), tbl, next_bound, last_bound);
+
+    FOREACH def IN ARRAY coalesce(index_defs, '{}') LOOP
+        EXECUTE def;
+    END LOOP;
+    FOREACH def IN ARRAY coalesce(fk_defs, '{}') LOOP
+        EXECUTE def;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Primary keys of partitioned tables must include the partition key
+SELECT pg_temp.partition_by_month('access_log', 'created_at', 'id, created_at');
+SELECT pg_temp.partition_by_month('contact_submissions', 'created_at', 'id, created_at');
+SELECT pg_temp.partition_by_month('post_view_daily', 'day', 'post_id, day');
+SELECT pg_temp.partition_by_month('post_traffic_daily', 'day', 'post_id, day, referrer, utm_source, utm_medium, utm_campaign');
+
+-- Views follow the renamed table, so point the daily views aggregate at the
+-- partitioned one
+DROP MATERIALIZED VIEW IF EXISTS dashboard_daily_views;
+CREATE MATERIALIZED VIEW dashboard_daily_views AS
+SELECT day, SUM(views)::BIGINT AS views, COUNT(*) AS posts
+FROM post_view_daily
+GROUP BY day;
+CREATE UNIQUE INDEX idx_dashboard_daily_views ON dashboard_daily_views(day);
+
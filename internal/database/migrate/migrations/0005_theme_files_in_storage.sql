@@ -0,0 +1,18 @@
+-- Theme file contents are kept in the storage driver's theme bucket, under
+-- their checksum, so versions sharing a file share its object. content
+-- only still holds files written before; the server moves them to storage
+-- on startup and clears it.
+ALTER TABLE theme_files ADD COLUMN size INTEGER;
+UPDATE theme_files SET size = length(content);
+ALTER TABLE theme_files ALTER COLUMN size SET NOT NULL, ALTER COLUMN content DROP NOT NULL;
+
+-- Objects are deleted once no file references their checksum
+CREATE INDEX idx_theme_files_checksum ON theme_files(checksum);
+
+---- create above / drop below ----
+
+-- Files already moved to storage have no content to restore, so this only
+-- rolls back databases whose files were never moved
+ALTER TABLE theme_files ALTER COLUMN content SET NOT NULL;
+DROP INDEX idx_theme_files_checksum;
+ALTER TABLE theme_files DROP COLUMN size;
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// HostMonitor checks which database server the pool is connected to.
+// With a multi-host URL pgx connects to the first host that satisfies
+// target_session_attrs, and connections to a host that goes down are
+// replaced on their own. A demoted primary keeps its connections open but
+// rejects writes, so a pool that writes is reset when its server turns
+// out to be a standby, and reconnects to whichever host is primary now.
+type HostMonitor struct {
+	pool         *pgxpool.Pool
+	needsPrimary bool
+	interval     time.Duration
+
+	mu    sync.RWMutex
+	state models.DatabaseHostState
+}
+
+func NewHostMonitor(pool *pgxpool.Pool, cfg config.DatabaseConfig) *HostMonitor {
+	return &HostMonitor{pool: pool, needsPrimary: !cfg.ReadOnly, interval: cfg.HostCheckInterval}
+}
+
+// Run checks on every check interval until ctx is cancelled; a zero
+// interval leaves checking to readiness probes
+func (m *HostMonitor) Run(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Database check failed: %v", err)
+		}
+	}
+}
+
+// Check asks the server behind a pooled connection whether it is a
+// standby, resetting the pool when it must write, and returns the state.
+// The state is returned with the error of a failed check.
+func (m *HostMonitor) Check(ctx context.Context) (*models.DatabaseHostState, error) {
+	host, inRecovery, err := m.probe(ctx)
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state.LastCheckAt = &now
+	if err != nil {
+		msg := err.Error()
+		m.state.LastError = &msg
+		state := m.state
+		return &state, err
+	}
+
+	if m.state.Host != "" && m.state.Host != host {
+		log.Printf("Database connection moved from %s to %s", m.state.Host, host)
+	}
+	m.state.Host = host
+	m.state.InRecovery = inRecovery
+	m.state.LastError = nil
+
+	if inRecovery && m.needsPrimary {
+		err := fmt.Errorf("database server %s is a standby", host)
+		msg := err.Error()
+		m.state.LastError = &msg
+		m.state.Reconnects++
+		log.Printf("[WARN] Database server %s is a standby, reconnecting", host)
+		m.pool.Reset()
+		state := m.state
+		return &state, err
+	}
+
+	state := m.state
+	return &state, nil
+}
+
+func (m *HostMonitor) probe(ctx context.Context) (string, bool, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	host := conn.Conn().PgConn().Conn().RemoteAddr().String()
+	var inRecovery bool
+	if err := conn.QueryRow(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return host, false, fmt.Errorf("failed to query %s: %w", host, err)
+	}
+	return host, inRecovery, nil
+}
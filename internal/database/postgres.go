@@ -20,6 +20,13 @@ func NewPostgresPool(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.P
 	poolConfig.MaxConnLifetime = time.Hour
 	poolConfig.MaxConnIdleTime = 30 * time.Minute
 	poolConfig.HealthCheckPeriod = time.Minute
+	if cfg.ReadOnly {
+		// Writes fail fast even if the URL points at the primary
+		poolConfig.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+	}
+	if cfg.LogQueries || cfg.SlowQueryThreshold > 0 || cfg.QueryStats {
+		poolConfig.ConnConfig.Tracer = &queryTracer{slow: cfg.SlowQueryThreshold, logAll: cfg.LogQueries}
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// QueryStats counts the queries run under a context and the time spent in
+// them. Queries may run concurrently, so it is safe for concurrent use.
+type QueryStats struct {
+	queries atomic.Int64
+	nanos   atomic.Int64
+}
+
+type queryStatsKey struct{}
+
+// WithQueryStats returns a copy of ctx whose queries are counted in the
+// returned stats. It only counts when the pool was created with
+// DatabaseConfig.QueryStats set.
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsKey{}, stats), stats
+}
+
+// Queries returns the number of queries run so far
+func (s *QueryStats) Queries() int64 {
+	return s.queries.Load()
+}
+
+// Time returns the time spent in queries so far
+func (s *QueryStats) Time() time.Duration {
+	return time.Duration(s.nanos.Load())
+}
+
+// recordQuery counts a query under ctx, if ctx is counted
+func recordQuery(ctx context.Context, elapsed time.Duration) {
+	if s, ok := ctx.Value(queryStatsKey{}).(*QueryStats); ok {
+		s.queries.Add(1)
+		s.nanos.Add(int64(elapsed))
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// maxLoggedSQL truncates logged statements so bulk inserts don't flood the log
+const maxLoggedSQL = 500
+
+// queryTracer logs slow queries, and every query when logAll is set, with the
+// request ID of the context the query ran under. Queries under a context
+// from WithQueryStats are counted.
+type queryTracer struct {
+	slow   time.Duration
+	logAll bool
+}
+
+type queryStart struct {
+	at  time.Time
+	sql string
+}
+
+type queryStartKey struct{}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), sql: data.SQL})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.at)
+	recordQuery(ctx, elapsed)
+
+	switch {
+	case t.slow > 0 && elapsed >= t.slow:
+		reqctx.Logf(ctx, "[WARN] Slow query (%s): %s", elapsed, compactSQL(start.sql))
+	case t.logAll && data.Err != nil:
+		reqctx.Logf(ctx, "Query failed (%s): %s: %v", elapsed, compactSQL(start.sql), data.Err)
+	case t.logAll:
+		reqctx.Logf(ctx, "Query (%s): %s", elapsed, compactSQL(start.sql))
+	}
+}
+
+// compactSQL collapses whitespace so a statement fits on one log line
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedSQL {
+		sql = sql[:maxLoggedSQL] + "..."
+	}
+	return sql
+}
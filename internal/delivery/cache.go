@@ -0,0 +1,51 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// SetCacheHeaders writes the caching headers of a public response for a
+// post. Live posts get a Cache-Control built from their cache hints, or
+// fallback when the hints set no age, and a Surrogate-Key listing
+// "post-<id>", "content-type-<slug>" and the hints' keys, so a CDN can
+// purge everything showing a post or a content type. Posts of other
+// environments, and live posts whose hints fail to load, get fallback
+// only. An empty fallback leaves Cache-Control unset.
+func SetCacheHeaders(w http.ResponseWriter, r *http.Request, repo *repository.CacheHintRepository, post *models.ContentPost, fallback string) {
+	cacheControl := fallback
+	if post.Environment == models.EnvironmentLive {
+		hints, err := repo.Resolve(r.Context(), post.ID, post.ContentTypeID)
+		if err != nil {
+			reqctx.Logf(r.Context(), "[ERROR] %v", err)
+		} else {
+			if hints.MaxAge != nil || hints.SharedMaxAge != nil {
+				cacheControl = hintedCacheControl(hints)
+			}
+			keys := []string{"post-" + post.ID.String()}
+			if post.ContentType != nil {
+				keys = append(keys, "content-type-"+post.ContentType.Slug)
+			}
+			w.Header().Set("Surrogate-Key", strings.Join(append(keys, hints.SurrogateKeys...), " "))
+		}
+	}
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+}
+
+func hintedCacheControl(hints *models.CacheHints) string {
+	directives := []string{"public"}
+	if hints.MaxAge != nil {
+		directives = append(directives, fmt.Sprintf("max-age=%d", *hints.MaxAge))
+	}
+	if hints.SharedMaxAge != nil {
+		directives = append(directives, fmt.Sprintf("s-maxage=%d", *hints.SharedMaxAge))
+	}
+	return strings.Join(directives, ", ")
+}
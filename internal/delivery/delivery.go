@@ -0,0 +1,158 @@
+// Package delivery authenticates the public delivery API with read-only
+// delivery tokens and carries each request's scope (content types, locale
+// and environment) to the handlers.
+package delivery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// TokenQueryParam carries a token for clients that cannot set headers, such
+// as calendar apps subscribing to the events feed
+const TokenQueryParam = "access_token"
+
+// Access is the scope of a delivery request
+type Access struct {
+	// Token is the delivery token used, nil for anonymous requests
+	Token *models.DeliveryToken
+	// Environment is the content environment to read from
+	Environment string
+}
+
+// AllowsContentType reports whether posts of a content type may be read
+func (a *Access) AllowsContentType(slug string) bool {
+	return a.Token == nil || a.Token.AllowsContentType(slug)
+}
+
+// ContentTypes returns the content type slugs the request is limited to,
+// nil when it may read all
+func (a *Access) ContentTypes() []string {
+	if a.Token == nil || len(a.Token.ContentTypes) == 0 {
+		return nil
+	}
+	return a.Token.ContentTypes
+}
+
+type accessKey struct{}
+
+// From returns the scope of the request ctx belongs to. Outside the
+// delivery routes it allows everything live.
+func From(ctx context.Context) *Access {
+	if access, ok := ctx.Value(accessKey{}).(*Access); ok {
+		return access
+	}
+	return &Access{Environment: models.EnvironmentLive}
+}
+
+// Authenticator checks delivery tokens on the public routes
+type Authenticator struct {
+	repo       *repository.DeliveryTokenRepository
+	cfg        config.DeliveryConfig
+	negotiator *locale.Negotiator
+}
+
+func New(repo *repository.DeliveryTokenRepository, cfg config.DeliveryConfig, negotiator *locale.Negotiator) *Authenticator {
+	return &Authenticator{repo: repo, cfg: cfg, negotiator: negotiator}
+}
+
+// Middleware resolves the token and scope of a request. Anonymous requests
+// read live content unless tokens are required; a presented token must be
+// valid, and the environment and locale asked for must be in its scope.
+// The locale served is sent as Content-Language.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Responses depend on the token, so caches must not share them
+		w.Header().Add("Vary", "Authorization")
+		access := &Access{}
+
+		if token := requestToken(r); token != "" {
+			t, err := a.repo.GetByToken(r.Context(), token)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				response.InternalErrorWithErr(w, "Failed to check delivery token", err)
+				return
+			}
+			if t == nil || !t.Usable(time.Now()) {
+				unauthorized(w, "INVALID_DELIVERY_TOKEN", "Invalid, inactive or expired delivery token")
+				return
+			}
+			if !a.cfg.ReadOnly {
+				if err := a.repo.Touch(r.Context(), t.ID); err != nil {
+					reqctx.Logf(r.Context(), "[ERROR] %v", err)
+				}
+			}
+			access.Token = t
+			reqctx.SetCaller(r.Context(), reqctx.Caller{DeliveryTokenID: t.ID})
+		} else if a.cfg.TokenRequired {
+			unauthorized(w, "DELIVERY_TOKEN_REQUIRED", "A delivery token is required")
+			return
+		}
+
+		env := r.URL.Query().Get("environment")
+		switch {
+		case env == "":
+			env = defaultEnvironment(access.Token)
+		case !models.ValidEnvironment(env):
+			response.BadRequest(w, "Invalid environment (must be live or draft)")
+			return
+		}
+		if (access.Token == nil && env != models.EnvironmentLive) || (access.Token != nil && !access.Token.AllowsEnvironment(env)) {
+			response.Error(w, http.StatusForbidden, "ENVIRONMENT_NOT_ALLOWED", "Reading the "+env+" environment is not allowed")
+			return
+		}
+		access.Environment = env
+
+		ctx := context.WithValue(r.Context(), accessKey{}, access)
+		info := *reqctx.From(ctx)
+		if tag := locale.Normalize(r.URL.Query().Get("locale")); tag != "" {
+			if access.Token != nil && !access.Token.AllowsLocale(tag) {
+				response.Error(w, http.StatusForbidden, "LOCALE_NOT_ALLOWED", "The delivery token does not allow the "+tag+" locale")
+				return
+			}
+			info.Locale = tag
+		} else if access.Token != nil && info.Locale != "" && !access.Token.AllowsLocale(info.Locale) {
+			// Accept-Language is only a preference: negotiate among the
+			// token's locales instead of failing
+			info.Locale = a.negotiator.Negotiate(r.Header.Get("Accept-Language"), access.Token.Locales)
+		}
+		ctx = reqctx.With(ctx, &info)
+		w.Header().Add("Vary", "Accept-Language")
+		if info.Locale != "" {
+			w.Header().Set("Content-Language", info.Locale)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestToken reads a bearer token or, failing that, the access_token
+// query parameter
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get(TokenQueryParam)
+}
+
+// defaultEnvironment is live, unless the token is limited to other environments
+func defaultEnvironment(t *models.DeliveryToken) string {
+	if t == nil || t.AllowsEnvironment(models.EnvironmentLive) {
+		return models.EnvironmentLive
+	}
+	return t.Environments[0]
+}
+
+func unauthorized(w http.ResponseWriter, code, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="delivery"`)
+	response.Error(w, http.StatusUnauthorized, code, message)
+}
@@ -0,0 +1,134 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/replication"
+)
+
+// Serializer shapes posts for the delivery API. Media URLs come from the
+// media's cdn_url and variant URLs or, failing those, from the object key
+// under cdnURL; they go through CDN failover like media responses do.
+type Serializer struct {
+	cdnURL   string
+	failover *replication.Failover
+}
+
+// NewSerializer creates a serializer; cdnURL and failover may be empty/nil
+func NewSerializer(cdnURL string, failover *replication.Failover) *Serializer {
+	return &Serializer{cdnURL: strings.TrimRight(cdnURL, "/"), failover: failover}
+}
+
+// Post converts a post and whichever relations are loaded
+func (s *Serializer) Post(post *models.ContentPost) *models.DeliveryPost {
+	out := &models.DeliveryPost{
+		ID:          post.ID,
+		Title:       post.Title,
+		Slug:        post.Slug,
+		Path:        "/" + post.Path(),
+		Excerpt:     post.Excerpt,
+		Content:     post.Content,
+		Metadata:    post.Metadata,
+		PublishedAt: post.PublishedAt,
+		UpdatedAt:   post.UpdatedAt,
+		Latitude:    post.Latitude,
+		Longitude:   post.Longitude,
+		StartsAt:    post.StartsAt,
+		EndsAt:      post.EndsAt,
+		Recurrence:  post.Recurrence,
+	}
+	if post.ContentType != nil {
+		out.ContentType = &models.DeliveryRef{Name: post.ContentType.Name, Slug: post.ContentType.Slug}
+	}
+	if post.Author != nil {
+		out.Author = &models.DeliveryAuthor{Name: post.Author.FullName}
+	}
+	for _, tag := range post.Tags {
+		out.Tags = append(out.Tags, models.DeliveryRef{Name: tag.Name, Slug: tag.Slug})
+	}
+	for _, pm := range post.Media {
+		if media, ok := s.Media(&pm); ok {
+			out.Media = append(out.Media, media)
+		}
+	}
+	return out
+}
+
+// Media converts an attached media item. Items without a resolvable URL are
+// reported as not ok, since clients could not use them.
+func (s *Serializer) Media(pm *models.PostMedia) (models.DeliveryMedia, bool) {
+	m := pm.Media
+	if m == nil {
+		return models.DeliveryMedia{}, false
+	}
+	cdnURL := ""
+	if m.CDNUrl != nil {
+		cdnURL = *m.CDNUrl
+	}
+	mediaURL := s.resolve(cdnURL, m.ObjectKey)
+	if mediaURL == "" {
+		return models.DeliveryMedia{}, false
+	}
+
+	out := models.DeliveryMedia{
+		ID:       m.ID,
+		Role:     pm.MediaRole.String(),
+		FileType: m.FileType.String(),
+		MimeType: m.MimeType,
+		AltText:  m.AltText,
+		URL:      mediaURL,
+	}
+	var dims struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}
+	if json.Unmarshal(m.Dimensions, &dims) == nil {
+		out.Width, out.Height = dims.Width, dims.Height
+	}
+	out.Variants = s.variants(m.Variants)
+	return out, true
+}
+
+// variants resolves a variants object, which maps names to a URL or to
+// {"url", "width", "height", "object_key"}. Unresolvable entries are dropped.
+func (s *Serializer) variants(raw json.RawMessage) map[string]models.DeliveryImage {
+	var entries map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &entries) != nil {
+		return nil
+	}
+
+	variants := make(map[string]models.DeliveryImage, len(entries))
+	for name, entry := range entries {
+		var v struct {
+			URL       string `json:"url"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+			ObjectKey string `json:"object_key"`
+		}
+		if json.Unmarshal(entry, &v.URL) != nil && json.Unmarshal(entry, &v) != nil {
+			continue
+		}
+		if u := s.resolve(v.URL, v.ObjectKey); u != "" {
+			variants[name] = models.DeliveryImage{URL: u, Width: v.Width, Height: v.Height}
+		}
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+	return variants
+}
+
+// resolve returns explicit when set, else the object key under the CDN base
+func (s *Serializer) resolve(explicit, objectKey string) string {
+	u := explicit
+	if u == "" && objectKey != "" && s.cdnURL != "" {
+		u = s.cdnURL + "/" + (&url.URL{Path: strings.TrimLeft(objectKey, "/")}).EscapedPath()
+	}
+	if u == "" {
+		return ""
+	}
+	return s.failover.Rewrite(u)
+}
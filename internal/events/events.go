@@ -0,0 +1,107 @@
+// Package events carries domain events, such as a post being published,
+// to the features subscribed to them in this process. The service layer
+// publishes events carrying the changed records for changes made through
+// the API and jobs of this instance; the change feed publishes
+// ContentChanged for every row change, on every full instance and also for
+// edits made outside the API.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Event is a domain event; its name identifies its type
+type Event interface {
+	EventName() string
+}
+
+// PostPublished is published when a live post becomes published: created
+// or updated as published, published on schedule, or promoted from a
+// published draft
+type PostPublished struct {
+	Post *models.ContentPost
+}
+
+func (PostPublished) EventName() string { return "post.published" }
+
+// MediaCreated is published when a media record is created, by
+// registration or upload
+type MediaCreated struct {
+	Media *models.Media
+}
+
+func (MediaCreated) EventName() string { return "media.created" }
+
+// ContactReceived is published when a contact submission is accepted
+type ContactReceived struct {
+	Contact *models.ContactSubmission
+}
+
+func (ContactReceived) EventName() string { return "contact.received" }
+
+// ContentChanged is published by the change feed for a row change on a
+// content table. Change names the entity and what happened to it, such as
+// post.updated; ID is the row's ID (the post's for post tags and media)
+// and Key a setting's key when the change carries it. Truncating a table
+// yields an event without an ID.
+type ContentChanged struct {
+	Change string
+	Table  string
+	ID     string
+	Key    string
+}
+
+func (ContentChanged) EventName() string { return "content.changed" }
+
+// Bus hands events to the handlers subscribed to their type. A nil Bus
+// drops every event.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(context.Context, Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]func(context.Context, Event))}
+}
+
+// Subscribe adds a handler for events of type E. Handlers run one after the
+// other on the publisher's goroutine, after the change was saved, so they
+// should return quickly and hand slow work such as HTTP calls to a
+// goroutine or queue. A handler that panics is logged and skipped.
+func Subscribe[E Event](b *Bus, h func(context.Context, E)) {
+	var zero E
+	name := zero.EventName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], func(ctx context.Context, e Event) {
+		h(ctx, e.(E))
+	})
+}
+
+// Publish calls every handler subscribed to e's type
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[e.EventName()]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		call(ctx, h, e)
+	}
+}
+
+func call(ctx context.Context, h func(context.Context, Event), e Event) {
+	defer func() {
+		if p := recover(); p != nil {
+			reqctx.Logf(ctx, "[ERROR] %s event handler panicked: %v", e.EventName(), p)
+		}
+	}()
+	h(ctx, e)
+}
@@ -0,0 +1,61 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Location represents the geographic data resolved for an IP address
+type Location struct {
+	CountryCode string `json:"country_code,omitempty"`
+	Country     string `json:"country,omitempty"`
+	City        string `json:"city,omitempty"`
+}
+
+// Resolver looks up IP addresses in a MaxMind GeoIP2/GeoLite2 City database.
+// A nil Resolver is valid and resolves nothing.
+type Resolver struct {
+	reader *geoip2.Reader
+}
+
+// Open loads the MaxMind database at path. An empty path disables lookups.
+func Open(path string) (*Resolver, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+
+	return &Resolver{reader: reader}, nil
+}
+
+// Lookup resolves the location of ip, reporting false when nothing is known
+func (r *Resolver) Lookup(ip net.IP) (*Location, bool) {
+	if r == nil || ip == nil {
+		return nil, false
+	}
+
+	record, err := r.reader.City(ip)
+	if err != nil || record.Country.IsoCode == "" {
+		return nil, false
+	}
+
+	return &Location{
+		CountryCode: record.Country.IsoCode,
+		Country:     record.Country.Names["en"],
+		City:        record.City.Names["en"],
+	}, true
+}
+
+// Close releases the underlying database
+func (r *Resolver) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.reader.Close()
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// accessLogger records reads of personal data. A nil *accessLogger (access
+// logging disabled) records nothing.
+type accessLogger struct {
+	repo *repository.AccessLogRepository
+}
+
+func newAccessLogger(repo *repository.AccessLogRepository) *accessLogger {
+	if repo == nil {
+		return nil
+	}
+	return &accessLogger{repo: repo}
+}
+
+// record logs one entry per id, or a single entry carrying the query string
+// when ids is empty (exports). Failures are logged and never fail the request.
+func (a *accessLogger) record(w http.ResponseWriter, r *http.Request, resource, action string, ids ...uuid.UUID) {
+	if a == nil {
+		return
+	}
+
+	info := reqctx.From(r.Context())
+	base := models.AccessLogEntry{
+		Resource:  resource,
+		Action:    action,
+		Client:    info.Client(),
+		IPAddress: clientIP(r),
+	}
+	if info.RequestID != "" {
+		base.RequestID = &info.RequestID
+	}
+
+	var entries []models.AccessLogEntry
+	if len(ids) == 0 {
+		if query := r.URL.RawQuery; query != "" {
+			base.Query = &query
+		}
+		entries = append(entries, base)
+	}
+	for i := range ids {
+		entry := base
+		entry.ResourceID = &ids[i]
+		entries = append(entries, entry)
+	}
+
+	if err := a.repo.Record(r.Context(), entries); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+}
+
+type AccessLogHandler struct {
+	repo *repository.AccessLogRepository
+}
+
+func NewAccessLogHandler(repo *repository.AccessLogRepository) *AccessLogHandler {
+	return &AccessLogHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List personal data access log
+// @Description Get who read which contact submissions and subscribers, newest first. Only populated when ACCESS_LOG_ENABLED is set.
+// @Tags admin
+// @Produce json
+// @Param resource query string false "Filter by resource (contact, subscriber)"
+// @Param resource_id query string false "Filter by resource ID"
+// @Param client query string false "Filter by client (e.g. token:1a2b3c4d5e6f)"
+// @Param from query string false "Only entries at or after this time (RFC 3339)"
+// @Param to query string false "Only entries before this time (RFC 3339)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/admin/access-log [get]
+func (h *AccessLogHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.AccessLogFilter{
+		PaginationParams: parsePaginationParams(r),
+		Resource:         q.Get("resource"),
+		Client:           q.Get("client"),
+	}
+
+	validationErrors := make(map[string]string)
+	if idStr := q.Get("resource_id"); idStr != "" {
+		id, err := parseUUID(idStr)
+		if err != nil {
+			validationErrors["resource_id"] = "Must be a valid UUID"
+		} else {
+			filter.ResourceID = &id
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			validationErrors[param.name] = "Must be an RFC 3339 timestamp"
+			continue
+		}
+		*param.dst = &t
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	entries, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list access log")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, entries, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/middleware"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/uischema"
+)
+
+type AdminHandler struct {
+	usageRepo  *repository.APIUsageRepository
+	locker     *leader.Locker
+	recovery   *middleware.Recovery
+	schemaOnce sync.Once
+	schema     *uischema.Schema
+}
+
+func NewAdminHandler(usageRepo *repository.APIUsageRepository, locker *leader.Locker, recovery *middleware.Recovery) *AdminHandler {
+	return &AdminHandler{usageRepo: usageRepo, locker: locker, recovery: recovery}
+}
+
+// UISchema godoc
+// @Summary Get admin UI schema
+// @Description Get machine-readable descriptions of all entities (fields, types, validations, list columns, filters and permissions) for generic admin frontends
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/ui-schema [get]
+func (h *AdminHandler) UISchema(w http.ResponseWriter, r *http.Request) {
+	// The schema only depends on the compiled models, so build it once
+	h.schemaOnce.Do(func() {
+		h.schema = uischema.Generate()
+	})
+
+	response.OK(w, h.schema)
+}
+
+// APIUsage godoc
+// @Summary Get API usage per client
+// @Description Get call counts and last call time per client and route, busiest first. Clients are identified by their verified credentials: key:<API key ID>, user:<user ID>, delivery:<token ID>, or "anonymous".
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day to include (YYYY-MM-DD, UTC)"
+// @Param to query string false "Last day to include (YYYY-MM-DD, UTC)"
+// @Param client query string false "Filter by client (e.g. token:1a2b3c4d5e6f)"
+// @Param route query string false "Filter by route pattern (partial match)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/admin/api-usage [get]
+func (h *AdminHandler) APIUsage(w http.ResponseWriter, r *http.Request) {
+	filter := models.APIUsageFilter{
+		PaginationParams: parsePaginationParams(r),
+		Client:           r.URL.Query().Get("client"),
+		Route:            r.URL.Query().Get("route"),
+	}
+
+	validationErrors := make(map[string]string)
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := r.URL.Query().Get(param.name)
+		if value == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			validationErrors[param.name] = "Must be a date in YYYY-MM-DD format"
+			continue
+		}
+		*param.dst = &day
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	usage, total, err := h.usageRepo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to get API usage")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, usage, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Leadership godoc
+// @Summary Get background job leadership
+// @Description Get this replica's advisory lock counters per background job: whether it currently leads, how often it acquired the lock, found it held elsewhere or failed, and how often leadership changed
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/leadership [get]
+func (h *AdminHandler) Leadership(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, h.locker.Stats())
+}
+
+// Panics godoc
+// @Summary Get recovered panics
+// @Description Get how many handler panics this replica recovered since it started, and the most recent one
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/panics [get]
+func (h *AdminHandler) Panics(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, h.recovery.Stats())
+}
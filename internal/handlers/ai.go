@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/keeps-dev/go-cms-template/internal/ai"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// AIHandler serves AI-generated suggestions. Nothing is saved: editors
+// review a suggestion and apply it through the regular post and tag
+// endpoints.
+type AIHandler struct {
+	assistant *ai.Assistant
+	postRepo  *repository.ContentPostRepository
+	tagRepo   *repository.TagRepository
+}
+
+// NewAIHandler returns a handler for assistant; the endpoints respond with
+// 503 when assistant is nil because no provider is configured
+func NewAIHandler(assistant *ai.Assistant, postRepo *repository.ContentPostRepository, tagRepo *repository.TagRepository) *AIHandler {
+	return &AIHandler{assistant: assistant, postRepo: postRepo, tagRepo: tagRepo}
+}
+
+// Summarize godoc
+// @Summary Suggest a summary
+// @Description Draft a plain-text summary of a post or of raw text. The summary is not saved; apply it as the post excerpt to keep it.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param body body models.AIRequest true "post_id or text, and optional max_words"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/ai/summarize [post]
+func (h *AIHandler) Summarize(w http.ResponseWriter, r *http.Request) {
+	req, post, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	text := req.Text
+	if post != nil {
+		text = postText(post)
+	}
+
+	summary, err := h.assistant.Summarize(r.Context(), text, req.MaxWords)
+	if err != nil {
+		providerError(r.Context(), w, err)
+		return
+	}
+
+	provider, model := h.assistant.Provider()
+	response.OK(w, models.AISummary{PostID: req.PostID, Summary: summary, Provider: provider, Model: model})
+}
+
+// SuggestTags godoc
+// @Summary Suggest tags
+// @Description Suggest tags for a post or for raw text. Suggestions matching an existing tag by slug include its tag_id. Nothing is saved.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param body body models.AIRequest true "post_id or text, and optional limit"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/ai/suggest-tags [post]
+func (h *AIHandler) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	req, post, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	text := req.Text
+	if post != nil {
+		text = postText(post)
+	}
+
+	names, err := h.assistant.SuggestTags(r.Context(), text, req.Limit)
+	if err != nil {
+		providerError(r.Context(), w, err)
+		return
+	}
+
+	// Skip tags the post already has and point the rest at existing tags
+	current := make(map[string]bool)
+	if post != nil {
+		for _, tag := range post.Tags {
+			current[tag.Slug] = true
+		}
+	}
+	tags := make([]models.AISuggestedTag, 0, len(names))
+	for _, name := range names {
+		suggestion := models.AISuggestedTag{Name: name, Slug: slugify(name)}
+		if suggestion.Slug == "" || current[suggestion.Slug] {
+			continue
+		}
+		tag, err := h.tagRepo.GetBySlug(r.Context(), suggestion.Slug)
+		switch {
+		case err == nil:
+			suggestion.Name, suggestion.TagID = tag.Name, &tag.ID
+		case !errors.Is(err, repository.ErrNotFound):
+			response.InternalError(w, "Failed to match suggested tags")
+			return
+		}
+		current[suggestion.Slug] = true
+		tags = append(tags, suggestion)
+	}
+
+	provider, model := h.assistant.Provider()
+	response.OK(w, models.AITagSuggestions{PostID: req.PostID, Tags: tags, Provider: provider, Model: model})
+}
+
+// Translate godoc
+// @Summary Suggest a translation
+// @Description Translate a post's title and content, or raw text, into the requested language, keeping markup intact. Nothing is saved.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Param body body models.AIRequest true "post_id or text, and language"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/ai/translate [post]
+func (h *AIHandler) Translate(w http.ResponseWriter, r *http.Request) {
+	req, post, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	language := strings.TrimSpace(req.Language)
+	if language == "" {
+		response.ValidationError(w, map[string]string{"language": "Language is required"})
+		return
+	}
+
+	provider, model := h.assistant.Provider()
+	result := models.AITranslation{PostID: req.PostID, Language: language, Provider: provider, Model: model}
+
+	var err error
+	if post == nil {
+		result.Text, err = h.assistant.Translate(r.Context(), req.Text, language)
+	} else {
+		result.Title, err = h.assistant.Translate(r.Context(), post.Title, language)
+		if err == nil && post.Content != nil && *post.Content != "" {
+			result.Content, err = h.assistant.Translate(r.Context(), *post.Content, language)
+		}
+	}
+	if err != nil {
+		providerError(r.Context(), w, err)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+// decode reads the request and loads the post it names. It writes the error
+// response and reports false when the request can't be served.
+func (h *AIHandler) decode(w http.ResponseWriter, r *http.Request) (*models.AIRequest, *models.ContentPost, bool) {
+	if h.assistant == nil {
+		response.Error(w, http.StatusServiceUnavailable, "AI_DISABLED", "No AI provider is configured")
+		return nil, nil, false
+	}
+
+	var req models.AIRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return nil, nil, false
+	}
+
+	if (req.PostID == nil) == (strings.TrimSpace(req.Text) == "") {
+		response.ValidationError(w, map[string]string{"post_id": "Exactly one of post_id or text is required"})
+		return nil, nil, false
+	}
+	if req.PostID == nil {
+		return &req, nil, true
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), *req.PostID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return nil, nil, false
+		}
+		response.InternalError(w, "Failed to get post")
+		return nil, nil, false
+	}
+	return &req, post, true
+}
+
+// postText is the text of a post given to the assistant
+func postText(post *models.ContentPost) string {
+	text := post.Title
+	if post.Content != nil {
+		text += "\n\n" + *post.Content
+	}
+	return text
+}
+
+func providerError(ctx context.Context, w http.ResponseWriter, err error) {
+	reqctx.Logf(ctx, "[ERROR] AI provider request failed: %v", err)
+	response.Error(w, http.StatusBadGateway, "AI_PROVIDER_ERROR", "The AI provider failed to respond")
+}
+
+// slugify lowercases name and joins its words with hyphens
+func slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
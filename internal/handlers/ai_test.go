@@ -0,0 +1,24 @@
+package handlers
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":           "hello-world",
+		"  Go 1.22: what's new": "go-1-22-what-s-new",
+		"Crème brûlée":          "crème-brûlée",
+		"---":                   "",
+	}
+	for in, want := range tests {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func BenchmarkSlugify(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		slugify("Ten Tips for Writing Faster Go Services in 2024!")
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Traffic source report defaults: the last 30 days, top 10 values per list
+const (
+	defaultTrafficDays  = 30
+	defaultTrafficLimit = 10
+	maxTrafficLimit     = 100
+)
+
+// maxUTMLength caps stored UTM values, in characters
+const maxUTMLength = 100
+
+type AnalyticsHandler struct {
+	repo *repository.TrafficRepository
+}
+
+func NewAnalyticsHandler(repo *repository.TrafficRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{repo: repo}
+}
+
+// TrafficSources godoc
+// @Summary Get traffic sources
+// @Description Summarize where readers of live posts came from over a period: total and direct views, and the top referring domains and UTM sources, mediums and campaigns with their share of views
+// @Tags analytics
+// @Produce json
+// @Param from query string false "First day to include (YYYY-MM-DD, UTC; default 29 days before to)"
+// @Param to query string false "Last day to include (YYYY-MM-DD, UTC; default today)"
+// @Param post_id query string false "Only views of this post"
+// @Param limit query int false "Values listed per dimension (1-100, default 10)"
+// @Success 200 {object} response.APIResponse{data=models.TrafficSourceReport}
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/analytics/traffic-sources [get]
+func (h *AnalyticsHandler) TrafficSources(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	filter := models.TrafficSourceFilter{To: today, Limit: defaultTrafficLimit}
+
+	validationErrors := make(map[string]string)
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			validationErrors[param.name] = "Must be a date in YYYY-MM-DD format"
+			continue
+		}
+		*param.dst = day
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, 1-defaultTrafficDays)
+	}
+	if filter.From.After(filter.To) {
+		validationErrors["from"] = "Must not be after to"
+	}
+
+	if value := q.Get("post_id"); value != "" {
+		id, err := parseUUID(value)
+		if err != nil {
+			validationErrors["post_id"] = "Invalid post ID"
+		} else {
+			filter.PostID = &id
+		}
+	}
+	if value := q.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxTrafficLimit {
+			validationErrors["limit"] = "Must be between 1 and 100"
+		} else {
+			filter.Limit = limit
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	report, err := h.repo.Sources(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to get traffic sources")
+		return
+	}
+
+	response.OK(w, report)
+}
+
+// trafficSource reads where a post view came from. Frontends fetching
+// posts for their readers pass the reader's referrer as the referrer
+// parameter and forward the page's utm_* parameters; without the
+// parameter the Referer header is used.
+func trafficSource(r *http.Request) models.TrafficSource {
+	q := r.URL.Query()
+	referrer := q.Get("referrer")
+	if referrer == "" {
+		referrer = r.Referer()
+	}
+	return models.TrafficSource{
+		Referrer:    referrerDomain(referrer),
+		UTMSource:   utmValue(q.Get("utm_source")),
+		UTMMedium:   utmValue(q.Get("utm_medium")),
+		UTMCampaign: utmValue(q.Get("utm_campaign")),
+	}
+}
+
+// referrerDomain returns the lowercase host of a referrer URL without its
+// port and www. prefix, or "" when there is none. Only the domain is kept,
+// so no reader's path or query ever reaches the rollups.
+func referrerDomain(referrer string) string {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return ""
+	}
+	if !strings.Contains(referrer, "://") {
+		referrer = "https://" + referrer
+	}
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > 255 || strings.IndexFunc(host, invalidHostRune) >= 0 {
+		return ""
+	}
+	return host
+}
+
+func invalidHostRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '-' && r != '_'
+}
+
+// utmValue normalizes a UTM parameter so "Newsletter" and "newsletter "
+// count together
+func utmValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if utf8.RuneCountInString(value) > maxUTMLength {
+		value = string([]rune(value)[:maxUTMLength])
+	}
+	return value
+}
@@ -0,0 +1,447 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// mentionPattern matches @user@example.com mentions in annotation bodies
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+type AnnotationHandler struct {
+	repo      *repository.AnnotationRepository
+	postRepo  *repository.ContentPostRepository
+	teamRepo  *repository.TeamRepository
+	userRepo  *repository.UserRepository
+	mailer    *mailer.Mailer
+	publicURL string
+}
+
+func NewAnnotationHandler(repo *repository.AnnotationRepository, postRepo *repository.ContentPostRepository, teamRepo *repository.TeamRepository, userRepo *repository.UserRepository, mail *mailer.Mailer, publicURL string) *AnnotationHandler {
+	return &AnnotationHandler{
+		repo:      repo,
+		postRepo:  postRepo,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+		mailer:    mail,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// List godoc
+// @Summary List post annotations
+// @Description Get the editorial annotation threads of a post, oldest first, each with its replies
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param resolved query bool false "Only resolved (true) or open (false) threads"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations [get]
+func (h *AnnotationHandler) List(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.getPost(w, r)
+	if !ok {
+		return
+	}
+
+	threads, err := h.repo.ListThreads(r.Context(), models.AnnotationFilter{
+		PostID:   post.ID,
+		Resolved: getBoolParam(r, "resolved"),
+	})
+	if err != nil {
+		response.InternalError(w, "Failed to list annotations")
+		return
+	}
+
+	response.OK(w, threads)
+}
+
+// Create godoc
+// @Summary Create post annotation
+// @Description Start an annotation thread on a post, optionally anchored to a block and character range, or reply to one with parent_id. The author is the authenticated user, who must be an editor or admin, or for team posts an editor or manager of the team. Users mentioned as @email are notified by email.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.CreateAnnotationRequest true "Annotation data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations [post]
+func (h *AnnotationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+	post, ok := h.getPost(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAnnotationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		validationErrors["body"] = "Body is required"
+	}
+	if req.BlockID != nil && len(*req.BlockID) > 100 {
+		validationErrors["block_id"] = "Block ID must be at most 100 characters"
+	}
+	if (req.RangeStart == nil) != (req.RangeEnd == nil) {
+		validationErrors["range_end"] = "Range start and end must be given together"
+	} else if req.RangeStart != nil {
+		if *req.RangeStart < 0 {
+			validationErrors["range_start"] = "Range start cannot be negative"
+		} else if *req.RangeEnd < *req.RangeStart {
+			validationErrors["range_end"] = "Range end must not be before range start"
+		}
+	}
+	if req.ParentID != nil && (req.BlockID != nil || req.RangeStart != nil || req.Quote != nil) {
+		validationErrors["parent_id"] = "Replies cannot be anchored; anchor the thread instead"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.repo.GetByID(r.Context(), *req.ParentID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			response.InternalError(w, "Failed to get parent annotation")
+			return
+		}
+		if parent == nil || parent.PostID != post.ID || parent.ParentID != nil {
+			response.ValidationError(w, map[string]string{"parent_id": "Parent must be a thread on this post"})
+			return
+		}
+	}
+
+	if !h.checkEditor(w, r, post, user) {
+		return
+	}
+	req.AuthorID = user.ID
+
+	mentioned, err := h.resolveMentions(r.Context(), req.Body)
+	if err != nil {
+		response.InternalError(w, "Failed to resolve mentions")
+		return
+	}
+
+	annotation, err := h.repo.Create(r.Context(), post.ID, &req, userIDs(mentioned))
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid parent ID")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to create annotation", err)
+		return
+	}
+
+	h.notifyMentions(r.Context(), post, annotation, mentioned, nil)
+	response.Created(w, annotation)
+}
+
+// Update godoc
+// @Summary Update post annotation
+// @Description Edit an annotation's text. Users newly mentioned are notified by email.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param annotationId path string true "Annotation ID"
+// @Param body body models.UpdateAnnotationRequest true "Annotation data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations/{annotationId} [put]
+func (h *AnnotationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	post, current, ok := h.getAnnotation(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAnnotationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		response.ValidationError(w, map[string]string{"body": "Body is required"})
+		return
+	}
+
+	mentioned, err := h.resolveMentions(r.Context(), req.Body)
+	if err != nil {
+		response.InternalError(w, "Failed to resolve mentions")
+		return
+	}
+
+	annotation, err := h.repo.UpdateBody(r.Context(), current.ID, req.Body, userIDs(mentioned))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Annotation not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to update annotation", err)
+		return
+	}
+
+	h.notifyMentions(r.Context(), post, annotation, mentioned, current.MentionIDs)
+	response.OK(w, annotation)
+}
+
+// Resolve godoc
+// @Summary Resolve annotation thread
+// @Description Mark an annotation thread as resolved by the authenticated user, who must be able to edit the post.
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param annotationId path string true "Annotation ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations/{annotationId}/resolve [post]
+func (h *AnnotationHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+	post, current, ok := h.getAnnotation(w, r)
+	if !ok {
+		return
+	}
+	if current.ParentID != nil {
+		response.ValidationError(w, map[string]string{"annotation_id": "Only threads can be resolved, not replies"})
+		return
+	}
+
+	if !h.checkEditor(w, r, post, user) {
+		return
+	}
+
+	h.setResolved(w, r, current.ID, &user.ID)
+}
+
+// Reopen godoc
+// @Summary Reopen annotation thread
+// @Description Mark a resolved annotation thread as open again
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param annotationId path string true "Annotation ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations/{annotationId}/reopen [post]
+func (h *AnnotationHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	_, current, ok := h.getAnnotation(w, r)
+	if !ok {
+		return
+	}
+	if current.ParentID != nil {
+		response.ValidationError(w, map[string]string{"annotation_id": "Only threads can be reopened, not replies"})
+		return
+	}
+
+	h.setResolved(w, r, current.ID, nil)
+}
+
+// Delete godoc
+// @Summary Delete post annotation
+// @Description Delete an annotation; deleting a thread also deletes its replies
+// @Tags posts
+// @Param id path string true "Post ID"
+// @Param annotationId path string true "Annotation ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/annotations/{annotationId} [delete]
+func (h *AnnotationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	_, current, ok := h.getAnnotation(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), current.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Annotation not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete annotation")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *AnnotationHandler) setResolved(w http.ResponseWriter, r *http.Request, id uuid.UUID, userID *uuid.UUID) {
+	annotation, err := h.repo.SetResolved(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Annotation not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to update annotation", err)
+		return
+	}
+	response.OK(w, annotation)
+}
+
+// getPost loads the post named in the URL, writing the error response when
+// it cannot
+func (h *AnnotationHandler) getPost(w http.ResponseWriter, r *http.Request) (*models.ContentPost, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return nil, false
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get post")
+		return nil, false
+	}
+	return post, true
+}
+
+// getAnnotation loads the post and the annotation named in the URL. An
+// annotation belonging to another post is reported as not found.
+func (h *AnnotationHandler) getAnnotation(w http.ResponseWriter, r *http.Request) (*models.ContentPost, *models.PostAnnotation, bool) {
+	post, ok := h.getPost(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	id, err := parseUUID(chi.URLParam(r, "annotationId"))
+	if err != nil {
+		response.BadRequest(w, "Invalid annotation ID")
+		return nil, nil, false
+	}
+
+	annotation, err := h.repo.GetByID(r.Context(), id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalError(w, "Failed to get annotation")
+		return nil, nil, false
+	}
+	if annotation == nil || annotation.PostID != post.ID {
+		response.NotFound(w, "Annotation not found")
+		return nil, nil, false
+	}
+	return post, annotation, true
+}
+
+// checkEditor checks that user may annotate the post, writing a 403 when
+// not: team posts need an editor or manager of the team (or an admin),
+// other posts an editor or admin
+func (h *AnnotationHandler) checkEditor(w http.ResponseWriter, r *http.Request, post *models.ContentPost, user *models.User) bool {
+	if post.TeamID == nil {
+		if user.Role < models.RoleEditor {
+			response.Forbidden(w, "Only editors and admins can annotate posts")
+			return false
+		}
+		return true
+	}
+
+	ok, err := h.teamRepo.CanEdit(r.Context(), *post.TeamID, user.ID)
+	if err != nil {
+		response.InternalError(w, "Failed to check user permissions")
+		return false
+	}
+	if !ok {
+		response.Forbidden(w, "Only editors and managers of the post's team can annotate it")
+		return false
+	}
+	return true
+}
+
+// resolveMentions returns the active users mentioned in body, in order of
+// first mention. Addresses that match no active user are left as plain text.
+func (h *AnnotationHandler) resolveMentions(ctx context.Context, body string) ([]*models.User, error) {
+	var users []*models.User
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := m[1]
+		if seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+
+		user, err := h.userRepo.GetByEmail(ctx, email)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.IsActive {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// notifyMentions emails mentioned users who were not already mentioned
+// before the change, skipping the annotation's author. Failures to queue are
+// logged rather than failing the request.
+func (h *AnnotationHandler) notifyMentions(ctx context.Context, post *models.ContentPost, annotation *models.PostAnnotation, mentioned []*models.User, previous []uuid.UUID) {
+	if h.mailer == nil {
+		return
+	}
+
+	data := models.AnnotationMention{
+		PostTitle: post.Title,
+		Body:      annotation.Body,
+		URL:       h.publicURL + "/admin/#/posts/" + post.ID.String(),
+	}
+	if annotation.Author != nil {
+		data.Author = annotation.Author.FullName
+	}
+	if annotation.Quote != nil {
+		data.Quote = *annotation.Quote
+	}
+
+	for _, user := range mentioned {
+		if user.ID == annotation.AuthorID || slices.Contains(previous, user.ID) {
+			continue
+		}
+		data.Name = user.FullName
+		if _, err := h.mailer.Enqueue(ctx, user.Email, "annotation_mention", "", data); err != nil {
+			reqctx.Logf(ctx, "[ERROR] Failed to queue annotation mention for %s: %v", user.Email, err)
+		}
+	}
+}
+
+func userIDs(users []*models.User) []uuid.UUID {
+	ids := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type APIKeyHandler struct {
+	repo *repository.APIKeyRepository
+}
+
+func NewAPIKeyHandler(repo *repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List API keys
+// @Description Get the caller's API keys, newest first; admins get everyone's, optionally filtered by user. Key secrets are never returned after creation.
+// @Tags api-keys
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param user_id query string false "Filter by user ID (admins only)"
+// @Param search query string false "Search in name and key prefix"
+// @Success 200 {object} response.APIResponse{data=[]models.APIKey}
+// @Failure 401 {object} response.APIResponse
+// @Router /api/v1/api-keys [get]
+//
+// List must be mounted behind auth.RequireSession.
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFrom(r.Context())
+	filter := models.APIKeyFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if user.Role < models.RoleAdmin {
+		filter.UserID = &user.ID
+	} else if userID := r.URL.Query().Get("user_id"); userID != "" {
+		id, err := parseUUID(userID)
+		if err != nil {
+			response.BadRequest(w, "Invalid user ID")
+			return
+		}
+		filter.UserID = &id
+	}
+
+	keys, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list API keys")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, keys, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Create godoc
+// @Summary Create API key
+// @Description Issue an API key acting as the caller, for machine clients to send in the X-API-Key header. Read keys may only make GET requests. The key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param body body models.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} response.APIResponse{data=models.CreatedAPIKey}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /api/v1/api-keys [post]
+//
+// Create must be mounted behind auth.RequireSession.
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if req.Scope == "" {
+		req.Scope = models.APIKeyScopeRead
+	} else if !models.ValidAPIKeyScope(req.Scope) {
+		validationErrors["scope"] = "Scope must be read or full"
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		validationErrors["expires_at"] = "Expiry must be in the future"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	key, err := h.repo.Create(r.Context(), auth.UserFrom(r.Context()).ID, &req)
+	if err != nil {
+		response.InternalError(w, "Failed to create API key")
+		return
+	}
+
+	response.Created(w, key)
+}
+
+// Delete godoc
+// @Summary Revoke API key
+// @Description Revoke one of the caller's API keys for good; admins may revoke anyone's
+// @Tags api-keys
+// @Param id path string true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/api-keys/{id} [delete]
+//
+// Delete must be mounted behind auth.RequireSession.
+func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid API key ID")
+		return
+	}
+
+	key, err := h.repo.GetByID(r.Context(), id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalError(w, "Failed to get API key")
+		return
+	}
+	// Other users' keys look the same as missing ones to non-admins
+	user := auth.UserFrom(r.Context())
+	if key == nil || (key.UserID != user.ID && user.Role < models.RoleAdmin) {
+		response.NotFound(w, "API key not found")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "API key not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete API key")
+		return
+	}
+
+	response.NoContent(w)
+}
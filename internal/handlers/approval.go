@@ -0,0 +1,605 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Approval chain limits
+const (
+	maxApprovalSteps    = 20
+	maxApprovalComment  = 2000
+	maxApprovalStepName = 100
+)
+
+// approvalRolePattern matches approval role names such as "legal" or
+// "managing-editor"
+var approvalRolePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+type ApprovalHandler struct {
+	repo     *repository.ApprovalRepository
+	postRepo *repository.ContentPostRepository
+	userRepo *repository.UserRepository
+	notifier *notify.Notifier
+}
+
+func NewApprovalHandler(repo *repository.ApprovalRepository, postRepo *repository.ContentPostRepository, userRepo *repository.UserRepository, notifier *notify.Notifier) *ApprovalHandler {
+	return &ApprovalHandler{repo: repo, postRepo: postRepo, userRepo: userRepo, notifier: notifier}
+}
+
+// GetChain godoc
+// @Summary Get approval chain
+// @Description Get the approval steps posts of a content type go through
+// @Tags content-types
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalChain}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/approval-chain [get]
+func (h *ApprovalHandler) GetChain(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	chain, err := h.repo.GetChain(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Approval chain not found")
+			return
+		}
+		response.InternalError(w, "Failed to get approval chain")
+		return
+	}
+
+	response.OK(w, chain)
+}
+
+// SetChain godoc
+// @Summary Set approval chain
+// @Description Create or replace the approval steps of a content type, decided in order. Each step names the approval role whose holders decide it and may set an SLA in hours after which it is escalated to escalate_to. Requests already submitted keep the steps they started with.
+// @Tags content-types
+// @Accept json
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Param body body models.SetApprovalChainRequest true "Approval steps"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalChain}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/approval-chain [put]
+func (h *ApprovalHandler) SetChain(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	var req models.SetApprovalChainRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if len(req.Steps) == 0 || len(req.Steps) > maxApprovalSteps {
+		validationErrors["steps"] = "Between 1 and 20 steps are required"
+	}
+	for i := range req.Steps {
+		step := &req.Steps[i]
+		field := fmt.Sprintf("steps[%d]", i)
+		step.Name = strings.TrimSpace(step.Name)
+		step.Role = strings.ToLower(strings.TrimSpace(step.Role))
+		step.EscalateTo = strings.ToLower(strings.TrimSpace(step.EscalateTo))
+		switch {
+		case step.Name == "":
+			validationErrors[field+".name"] = "Name is required"
+		case len(step.Name) > maxApprovalStepName:
+			validationErrors[field+".name"] = "Name must be at most 100 characters"
+		}
+		if !approvalRolePattern.MatchString(step.Role) {
+			validationErrors[field+".role"] = "Role must be 1-50 lowercase letters, digits, dashes or underscores"
+		}
+		if step.SLAHours < 0 {
+			validationErrors[field+".sla_hours"] = "SLA cannot be negative"
+		}
+		if step.EscalateTo != "" && !approvalRolePattern.MatchString(step.EscalateTo) {
+			validationErrors[field+".escalate_to"] = "Role must be 1-50 lowercase letters, digits, dashes or underscores"
+		}
+		if step.EscalateTo != "" && step.SLAHours == 0 {
+			validationErrors[field+".sla_hours"] = "An SLA is required to escalate"
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	chain, err := h.repo.SetChain(r.Context(), id, req.Steps)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Content type not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to set approval chain", err)
+		return
+	}
+
+	response.OK(w, chain)
+}
+
+// DeleteChain godoc
+// @Summary Delete approval chain
+// @Description Remove the approval chain of a content type. Pending requests keep going through the steps they started with.
+// @Tags content-types
+// @Param id path string true "Content type ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/approval-chain [delete]
+func (h *ApprovalHandler) DeleteChain(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	if err := h.repo.DeleteChain(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Approval chain not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete approval chain")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Submit godoc
+// @Summary Submit post for approval
+// @Description Start an approval request for a post through its content type's approval chain. A post has at most one pending request. Holders of the first step's role are notified (approval.requested).
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 201 {object} response.APIResponse{data=models.ApprovalRequest}
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 422 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/approvals [post]
+func (h *ApprovalHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.getPost(w, r)
+	if !ok {
+		return
+	}
+
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+
+	chain, err := h.repo.GetChain(r.Context(), post.ContentTypeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.ValidationError(w, map[string]string{"content_type_id": "The post's content type has no approval chain"})
+			return
+		}
+		response.InternalError(w, "Failed to get approval chain")
+		return
+	}
+
+	request, err := h.repo.Submit(r.Context(), post.ID, chain.Steps, &user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			response.Conflict(w, "The post already has a pending approval request")
+		case errors.Is(err, repository.ErrForeignKey):
+			response.BadRequest(w, "Invalid user ID")
+		default:
+			response.InternalErrorWithErr(w, "Failed to submit post for approval", err)
+		}
+		return
+	}
+
+	h.notifier.Notify(r.Context(), notify.Approval(notify.EventApprovalRequested, "Post awaiting approval", request))
+	response.Created(w, request)
+}
+
+// ListForPost godoc
+// @Summary List post approval requests
+// @Description Get the approval requests of a post, newest first
+// @Tags approvals
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse{data=[]models.ApprovalRequest}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/approvals [get]
+func (h *ApprovalHandler) ListForPost(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.getPost(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.repo.ListForPost(r.Context(), post.ID)
+	if err != nil {
+		response.InternalError(w, "Failed to list approval requests")
+		return
+	}
+
+	response.OK(w, requests)
+}
+
+// Queue godoc
+// @Summary List pending approvals
+// @Description Get the pending approval requests awaiting a decision from the given roles, longest waiting first. Escalated steps also await their escalation role. Without roles, users see the queue of the approval roles they hold and admins every pending request.
+// @Tags approvals
+// @Produce json
+// @Param role query string false "Comma-separated approval roles"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse{data=[]models.ApprovalRequest}
+// @Router /api/v1/approvals [get]
+func (h *ApprovalHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	filter := models.ApprovalQueueFilter{PaginationParams: parsePaginationParams(r)}
+
+	if value := r.URL.Query().Get("role"); value != "" {
+		filter.Roles = []string{}
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.ToLower(strings.TrimSpace(role)); role != "" {
+				filter.Roles = append(filter.Roles, role)
+			}
+		}
+	} else if user := auth.UserFrom(r.Context()); user != nil && user.Role < models.RoleAdmin {
+		roles, err := h.repo.GetUserRoles(r.Context(), user.ID)
+		if err != nil {
+			response.InternalError(w, "Failed to list approval queue")
+			return
+		}
+		filter.Roles = roles
+	}
+
+	requests, total, err := h.repo.Queue(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list approval queue")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, requests, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get approval request
+// @Description Get an approval request with the decisions taken so far
+// @Tags approvals
+// @Produce json
+// @Param id path string true "Approval request ID"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalRequest}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/approvals/{id} [get]
+func (h *ApprovalHandler) Get(w http.ResponseWriter, r *http.Request) {
+	request, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+	response.OK(w, request)
+}
+
+// Approve godoc
+// @Summary Approve approval step
+// @Description Approve the current step of a pending request, moving it to the next step (approval.requested) or, on the last step, approving the request (approval.approved). The approver must be an active admin or hold the step's role, or its escalation role once escalated. Approving a request does not publish the post.
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval request ID"
+// @Param body body models.ApprovalDecisionRequest false "Comment"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalRequest}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/approvals/{id}/approve [post]
+func (h *ApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, models.ApprovalVerdictApproved)
+}
+
+// Reject godoc
+// @Summary Reject approval step
+// @Description Reject the current step of a pending request, which rejects the request (approval.rejected). The approver must be an active admin or hold the step's role, or its escalation role once escalated.
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval request ID"
+// @Param body body models.ApprovalDecisionRequest false "Comment"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalRequest}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/approvals/{id}/reject [post]
+func (h *ApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, models.ApprovalVerdictRejected)
+}
+
+// Cancel godoc
+// @Summary Cancel approval request
+// @Description Withdraw a pending approval request. Only the submitter, editors and admins can cancel.
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval request ID"
+// @Success 200 {object} response.APIResponse{data=models.ApprovalRequest}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/approvals/{id}/cancel [post]
+func (h *ApprovalHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+	submitter := current.RequestedBy != nil && *current.RequestedBy == user.ID
+	if !submitter && user.Role < models.RoleEditor {
+		response.Forbidden(w, "Only the submitter, editors and admins can cancel an approval request")
+		return
+	}
+
+	request, err := h.repo.Cancel(r.Context(), current.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			response.NotFound(w, "Approval request not found")
+		case errors.Is(err, repository.ErrInvalidInput):
+			response.Conflict(w, "The approval request is no longer pending")
+		default:
+			response.InternalErrorWithErr(w, "Failed to cancel approval request", err)
+		}
+		return
+	}
+
+	response.OK(w, request)
+}
+
+// GetUserRoles godoc
+// @Summary Get user approval roles
+// @Description Get the approval roles a user holds, which decide the approval steps they can approve
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.APIResponse{data=[]string}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id}/approval-roles [get]
+func (h *ApprovalHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	exists, err := h.userRepo.Exists(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to get approval roles")
+		return
+	}
+	if !exists {
+		response.NotFound(w, "User not found")
+		return
+	}
+
+	roles, err := h.repo.GetUserRoles(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to get approval roles")
+		return
+	}
+
+	response.OK(w, roles)
+}
+
+// SetUserRoles godoc
+// @Summary Set user approval roles
+// @Description Replace the approval roles a user holds
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body models.SetApprovalRolesRequest true "Approval roles"
+// @Success 200 {object} response.APIResponse{data=[]string}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id}/approval-roles [put]
+func (h *ApprovalHandler) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req models.SetApprovalRolesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	roles := make([]string, 0, len(req.Roles))
+	for _, role := range req.Roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if !approvalRolePattern.MatchString(role) {
+			response.ValidationError(w, map[string]string{"roles": "Roles must be 1-50 lowercase letters, digits, dashes or underscores"})
+			return
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	slices.Sort(roles)
+
+	if err := h.repo.SetUserRoles(r.Context(), id, roles); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to set approval roles", err)
+		return
+	}
+
+	response.OK(w, roles)
+}
+
+// decide records a verdict on the current step of the request in the URL
+func (h *ApprovalHandler) decide(w http.ResponseWriter, r *http.Request, verdict models.ApprovalVerdict) {
+	current, ok := h.getRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ApprovalDecisionRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+	if req.Comment != nil {
+		comment := strings.TrimSpace(*req.Comment)
+		if len(comment) > maxApprovalComment {
+			response.ValidationError(w, map[string]string{"comment": "Comment must be at most 2000 characters"})
+			return
+		}
+		req.Comment = &comment
+	}
+
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+	if current.CurrentStep == nil {
+		response.Conflict(w, "The approval request is no longer pending")
+		return
+	}
+	allowed, err := h.canDecide(r.Context(), user, current)
+	if err != nil {
+		response.InternalError(w, "Failed to check approval roles")
+		return
+	}
+	if !allowed {
+		response.Forbidden(w, "The "+current.CurrentStep.Role+" approval role is required for this step")
+		return
+	}
+
+	request, err := h.repo.Decide(r.Context(), current.ID, current.Step, verdict, &user.ID, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			response.NotFound(w, "Approval request not found")
+		case errors.Is(err, repository.ErrInvalidInput):
+			response.Conflict(w, "The approval step was already decided")
+		default:
+			response.InternalErrorWithErr(w, "Failed to record approval decision", err)
+		}
+		return
+	}
+
+	var n notify.Notification
+	switch request.Status {
+	case models.ApprovalStatusPending:
+		n = notify.Approval(notify.EventApprovalRequested, "Post awaiting approval", request)
+	case models.ApprovalStatusApproved:
+		n = notify.Approval(notify.EventApprovalApproved, "Post approved", request)
+	default:
+		n = notify.Approval(notify.EventApprovalRejected, "Post rejected at "+current.CurrentStep.Name, request)
+	}
+	if req.Comment != nil {
+		n.Text = *req.Comment
+	}
+	n.Fields = append(n.Fields, notify.Field{Label: "By", Value: user.FullName})
+	h.notifier.Notify(r.Context(), n)
+
+	response.OK(w, request)
+}
+
+// actingUser returns the authenticated user acting on a request. Routes
+// acting on approvals run behind auth.RequireUser; API key callers act as
+// the key's user.
+func actingUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		response.Unauthorized(w, "Authentication required")
+		return nil, false
+	}
+	return user, true
+}
+
+// canDecide reports whether user may decide the current step of a request:
+// admins always can, others need the step's role or, once the step was
+// escalated, its escalation role
+func (h *ApprovalHandler) canDecide(ctx context.Context, user *models.User, request *models.ApprovalRequest) (bool, error) {
+	if user.Role >= models.RoleAdmin {
+		return true, nil
+	}
+	roles, err := h.repo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	step := request.CurrentStep
+	if slices.Contains(roles, step.Role) {
+		return true, nil
+	}
+	return request.EscalatedAt != nil && step.EscalateTo != "" && slices.Contains(roles, step.EscalateTo), nil
+}
+
+func (h *ApprovalHandler) getPost(w http.ResponseWriter, r *http.Request) (*models.ContentPost, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return nil, false
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get post")
+		return nil, false
+	}
+	return post, true
+}
+
+func (h *ApprovalHandler) getRequest(w http.ResponseWriter, r *http.Request) (*models.ApprovalRequest, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid approval request ID")
+		return nil, false
+	}
+
+	request, err := h.repo.GetRequest(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Approval request not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get approval request")
+		return nil, false
+	}
+	return request, true
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type BlocklistHandler struct {
+	repo *repository.BlocklistRepository
+}
+
+func NewBlocklistHandler(repo *repository.BlocklistRepository) *BlocklistHandler {
+	return &BlocklistHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List blocklist rules
+// @Description Get all blocklist rules with their hit counters
+// @Tags blocklist
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param rule_type query int false "Filter by rule type (1=ip, 2=email_domain, 3=email_pattern)"
+// @Param search query string false "Search in value and reason"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/blocklist [get]
+func (h *BlocklistHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.BlocklistFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if typeStr := r.URL.Query().Get("rule_type"); typeStr != "" {
+		if t, err := strconv.Atoi(typeStr); err == nil {
+			ruleType := models.BlocklistRuleType(t)
+			filter.RuleType = &ruleType
+		}
+	}
+
+	rules, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list blocklist rules")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, rules, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get blocklist rule by ID
+// @Description Get a single blocklist rule by its ID
+// @Tags blocklist
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/blocklist/{id} [get]
+func (h *BlocklistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid rule ID")
+		return
+	}
+
+	rule, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Blocklist rule not found")
+			return
+		}
+		response.InternalError(w, "Failed to get blocklist rule")
+		return
+	}
+
+	response.OK(w, rule)
+}
+
+// Create godoc
+// @Summary Create blocklist rule
+// @Description Block public submissions by IP/CIDR, email domain or email pattern
+// @Tags blocklist
+// @Accept json
+// @Produce json
+// @Param body body models.CreateBlocklistRuleRequest true "Rule data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/blocklist [post]
+func (h *BlocklistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateBlocklistRuleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Value = strings.TrimSpace(req.Value)
+	validationErrors := make(map[string]string)
+	validateBlocklistValue(req.RuleType, req.Value, validationErrors)
+	validateBlocklistAction(req.Action, validationErrors)
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	rule, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Blocklist rule already exists")
+			return
+		}
+		response.InternalError(w, "Failed to create blocklist rule")
+		return
+	}
+
+	response.Created(w, rule)
+}
+
+// Update godoc
+// @Summary Update blocklist rule
+// @Description Update an existing blocklist rule
+// @Tags blocklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param body body models.UpdateBlocklistRuleRequest true "Rule data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/blocklist/{id} [put]
+func (h *BlocklistHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid rule ID")
+		return
+	}
+
+	var req models.UpdateBlocklistRuleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	validateBlocklistAction(req.Action, validationErrors)
+
+	if req.Value != nil {
+		existing, err := h.repo.GetByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				response.NotFound(w, "Blocklist rule not found")
+				return
+			}
+			response.InternalError(w, "Failed to get blocklist rule")
+			return
+		}
+		value := strings.TrimSpace(*req.Value)
+		req.Value = &value
+		validateBlocklistValue(existing.RuleType, value, validationErrors)
+	}
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	rule, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Blocklist rule not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Blocklist rule already exists")
+			return
+		}
+		response.InternalError(w, "Failed to update blocklist rule")
+		return
+	}
+
+	response.OK(w, rule)
+}
+
+// Delete godoc
+// @Summary Delete blocklist rule
+// @Description Delete a blocklist rule
+// @Tags blocklist
+// @Param id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/blocklist/{id} [delete]
+func (h *BlocklistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid rule ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Blocklist rule not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete blocklist rule")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func validateBlocklistValue(ruleType models.BlocklistRuleType, value string, validationErrors map[string]string) {
+	switch ruleType {
+	case models.BlocklistRuleIP:
+		if net.ParseIP(value) == nil {
+			if _, _, err := net.ParseCIDR(value); err != nil {
+				validationErrors["value"] = "Value must be an IP address or CIDR range"
+			}
+		}
+	case models.BlocklistRuleEmailDomain, models.BlocklistRuleEmailPattern:
+		if value == "" {
+			validationErrors["value"] = "Value is required"
+		}
+	default:
+		validationErrors["rule_type"] = "Rule type must be 1 (ip), 2 (email_domain), or 3 (email_pattern)"
+	}
+}
+
+func validateBlocklistAction(action *models.BlocklistAction, validationErrors map[string]string) {
+	if action != nil && *action != models.BlocklistActionReject && *action != models.BlocklistActionDiscard {
+		validationErrors["action"] = "Action must be 1 (reject) or 2 (discard)"
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Cache hint limits: ages in seconds (a year) and surrogate keys
+const (
+	maxCacheAge           = 31536000
+	maxSurrogateKeys      = 20
+	maxSurrogateKeyLength = 100
+)
+
+// surrogateKeyPattern allows the characters CDNs accept in surrogate keys;
+// keys are space-separated in the header
+var surrogateKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]+$`)
+
+type CacheHintHandler struct {
+	repo *repository.CacheHintRepository
+}
+
+func NewCacheHintHandler(repo *repository.CacheHintRepository) *CacheHintHandler {
+	return &CacheHintHandler{repo: repo}
+}
+
+// GetContentType godoc
+// @Summary Get content type cache hints
+// @Description Get the Cache-Control ages and surrogate keys the public endpoints send for posts of a content type
+// @Tags content-types
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Success 200 {object} response.APIResponse{data=models.CacheHints}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/cache-hints [get]
+func (h *CacheHintHandler) GetContentType(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	hints, err := h.repo.GetForContentType(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Cache hints not found")
+			return
+		}
+		response.InternalError(w, "Failed to get cache hints")
+		return
+	}
+
+	response.OK(w, hints)
+}
+
+// SetContentType godoc
+// @Summary Set content type cache hints
+// @Description Set the Cache-Control ages (seconds) and surrogate keys the public endpoints send for posts of a content type. Posts' own hints override the ages; surrogate keys of both are sent. Editors and admins only.
+// @Tags content-types
+// @Accept json
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Param body body models.SetCacheHintsRequest true "Cache hints"
+// @Success 200 {object} response.APIResponse{data=models.CacheHints}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/cache-hints [put]
+func (h *CacheHintHandler) SetContentType(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	var req models.SetCacheHintsRequest
+	if !decodeCacheHints(w, r, &req) {
+		return
+	}
+
+	hints, err := h.repo.SetForContentType(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Content type not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to set cache hints", err)
+		return
+	}
+
+	response.OK(w, hints)
+}
+
+// DeleteContentType godoc
+// @Summary Delete content type cache hints
+// @Description Remove a content type's cache hints, so its posts get the endpoints' default caching. Editors and admins only.
+// @Tags content-types
+// @Param id path string true "Content type ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/cache-hints [delete]
+func (h *CacheHintHandler) DeleteContentType(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	if err := h.repo.DeleteForContentType(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Cache hints not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete cache hints")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetPost godoc
+// @Summary Get post cache hints
+// @Description Get the Cache-Control ages and surrogate keys set on a post itself, overriding its content type's
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse{data=models.CacheHints}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/cache-hints [get]
+func (h *CacheHintHandler) GetPost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	hints, err := h.repo.GetForPost(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Cache hints not found")
+			return
+		}
+		response.InternalError(w, "Failed to get cache hints")
+		return
+	}
+
+	response.OK(w, hints)
+}
+
+// SetPost godoc
+// @Summary Set post cache hints
+// @Description Set the Cache-Control ages (seconds) and surrogate keys the public endpoints send for a live post. Ages override the content type's; a null age falls back to it. Editors and admins only.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.SetCacheHintsRequest true "Cache hints"
+// @Success 200 {object} response.APIResponse{data=models.CacheHints}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/cache-hints [put]
+func (h *CacheHintHandler) SetPost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req models.SetCacheHintsRequest
+	if !decodeCacheHints(w, r, &req) {
+		return
+	}
+
+	hints, err := h.repo.SetForPost(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to set cache hints", err)
+		return
+	}
+
+	response.OK(w, hints)
+}
+
+// DeletePost godoc
+// @Summary Delete post cache hints
+// @Description Remove a post's own cache hints, so it gets its content type's. Editors and admins only.
+// @Tags posts
+// @Param id path string true "Post ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/cache-hints [delete]
+func (h *CacheHintHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	if err := h.repo.DeleteForPost(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Cache hints not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete cache hints")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// decodeCacheHints reads and validates cache hints, writing the error
+// response when they are invalid
+func decodeCacheHints(w http.ResponseWriter, r *http.Request, req *models.SetCacheHintsRequest) bool {
+	if err := decodeJSON(r, req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return false
+	}
+
+	validationErrors := make(map[string]string)
+	if req.MaxAge != nil && (*req.MaxAge < 0 || *req.MaxAge > maxCacheAge) {
+		validationErrors["max_age"] = "Must be between 0 and 31536000 seconds"
+	}
+	if req.SharedMaxAge != nil && (*req.SharedMaxAge < 0 || *req.SharedMaxAge > maxCacheAge) {
+		validationErrors["s_maxage"] = "Must be between 0 and 31536000 seconds"
+	}
+	if len(req.SurrogateKeys) > maxSurrogateKeys {
+		validationErrors["surrogate_keys"] = "At most 20 surrogate keys are allowed"
+	}
+	for _, key := range req.SurrogateKeys {
+		if len(key) > maxSurrogateKeyLength || !surrogateKeyPattern.MatchString(key) {
+			validationErrors["surrogate_keys"] = "Keys must be at most 100 letters, digits or . _ : / - characters"
+			break
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// defaultCampaignTemplate is the built-in digest template
+const defaultCampaignTemplate = "campaign_digest"
+
+type CampaignHandler struct {
+	repo     *repository.CampaignRepository
+	postRepo *repository.ContentPostRepository
+}
+
+func NewCampaignHandler(repo *repository.CampaignRepository, postRepo *repository.ContentPostRepository) *CampaignHandler {
+	return &CampaignHandler{repo: repo, postRepo: postRepo}
+}
+
+// List godoc
+// @Summary List campaigns
+// @Description Get email campaigns, newest first
+// @Tags campaigns
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param status query int false "Filter by status (1=sending, 2=sent, 3=cancelled)"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/campaigns [get]
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.CampaignFilter{
+		PaginationParams: parsePaginationParams(r),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if s, err := strconv.Atoi(statusStr); err == nil {
+			status := models.CampaignStatus(s)
+			filter.Status = &status
+		}
+	}
+
+	campaigns, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list campaigns")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, campaigns, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get campaign by ID
+// @Description Get a campaign with its send and open statistics
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/campaigns/{id} [get]
+func (h *CampaignHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid campaign ID")
+		return
+	}
+
+	campaign, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Campaign not found")
+			return
+		}
+		response.InternalError(w, "Failed to get campaign")
+		return
+	}
+
+	stats, err := h.repo.GetStats(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to get campaign statistics")
+		return
+	}
+	campaign.Stats = stats
+
+	response.OK(w, campaign)
+}
+
+// Create godoc
+// @Summary Create campaign
+// @Description Compose an email digest from posts and send it to all subscribers in throttled batches, immediately or at send_at
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param body body models.CreateCampaignRequest true "Campaign data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/campaigns [post]
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCampaignRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Template = strings.TrimSpace(req.Template)
+	if req.Template == "" {
+		req.Template = defaultCampaignTemplate
+	}
+
+	// Validate required fields
+	validationErrors := make(map[string]string)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if len(req.PostIDs) == 0 {
+		validationErrors["post_ids"] = "At least one post is required"
+	}
+	for _, id := range req.PostIDs {
+		post, err := h.postRepo.GetByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				validationErrors["post_ids"] = "Post " + id.String() + " not found"
+				break
+			}
+			response.InternalError(w, "Failed to load posts")
+			return
+		}
+		if post.Status != models.PostStatusPublished {
+			validationErrors["post_ids"] = "Post " + id.String() + " is not published"
+			break
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	campaign, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		response.InternalError(w, "Failed to create campaign")
+		return
+	}
+
+	response.Created(w, campaign)
+}
+
+// Cancel godoc
+// @Summary Cancel campaign
+// @Description Stop a campaign that is still sending and drop its undelivered emails
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/campaigns/{id}/cancel [post]
+func (h *CampaignHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid campaign ID")
+		return
+	}
+
+	campaign, err := h.repo.Cancel(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Campaign not found or no longer sending")
+			return
+		}
+		response.InternalError(w, "Failed to cancel campaign")
+		return
+	}
+
+	response.OK(w, campaign)
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type CategoryHandler struct {
+	repo *repository.CategoryRepository
+}
+
+func NewCategoryHandler(repo *repository.CategoryRepository) *CategoryHandler {
+	return &CategoryHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List categories
+// @Description Get categories as a flat list with optional filtering
+// @Tags categories
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param parent_id query string false "Only direct subcategories of this category"
+// @Param search query string false "Search in name and slug"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.CategoryFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if parentID := r.URL.Query().Get("parent_id"); parentID != "" {
+		id, err := parseUUID(parentID)
+		if err != nil {
+			response.BadRequest(w, "Invalid parent ID")
+			return
+		}
+		filter.ParentID = &id
+	}
+
+	categories, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list categories")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, categories, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Tree godoc
+// @Summary Get category tree
+// @Description Get all top-level categories with their subcategories nested under children
+// @Tags categories
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/categories/tree [get]
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.repo.Tree(r.Context(), nil)
+	if err != nil {
+		response.InternalError(w, "Failed to get category tree")
+		return
+	}
+
+	if tree == nil {
+		tree = []models.Category{}
+	}
+	response.OK(w, tree)
+}
+
+// Subtree godoc
+// @Summary Get category subtree
+// @Description Get a category with its subcategories nested under children
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/categories/{id}/tree [get]
+func (h *CategoryHandler) Subtree(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	tree, err := h.repo.Tree(r.Context(), &id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Category not found")
+			return
+		}
+		response.InternalError(w, "Failed to get category tree")
+		return
+	}
+
+	response.OK(w, tree[0])
+}
+
+// Get godoc
+// @Summary Get category by ID
+// @Description Get a single category by its ID
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/categories/{id} [get]
+func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	category, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Category not found")
+			return
+		}
+		response.InternalError(w, "Failed to get category")
+		return
+	}
+
+	response.OK(w, category)
+}
+
+// GetBySlug godoc
+// @Summary Get category by slug
+// @Description Get a single category by its slug
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category Slug"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/categories/slug/{slug} [get]
+func (h *CategoryHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	if slug == "" {
+		response.BadRequest(w, "Slug is required")
+		return
+	}
+
+	category, err := h.repo.GetBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Category not found")
+			return
+		}
+		response.InternalError(w, "Failed to get category")
+		return
+	}
+
+	response.OK(w, category)
+}
+
+// Create godoc
+// @Summary Create category
+// @Description Create a new category, optionally under a parent category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param body body models.CreateCategoryRequest true "Category data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCategoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	// Validate required fields
+	if req.Name == "" || req.Slug == "" {
+		response.ValidationError(w, map[string]string{
+			"name": "Name is required",
+			"slug": "Slug is required",
+		})
+		return
+	}
+	if req.ParentID != nil && *req.ParentID == uuid.Nil {
+		req.ParentID = nil
+	}
+
+	category, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Category with this slug already exists")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid parent ID")
+			return
+		}
+		response.InternalError(w, "Failed to create category")
+		return
+	}
+
+	response.Created(w, category)
+}
+
+// Update godoc
+// @Summary Update category
+// @Description Update an existing category; a nil UUID parent_id makes it top-level
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param body body models.UpdateCategoryRequest true "Category data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/categories/{id} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	var req models.UpdateCategoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	category, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Category not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Category with this slug already exists")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid parent ID")
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidInput) {
+			response.BadRequest(w, "A category can't be moved under itself or its subcategories")
+			return
+		}
+		response.InternalError(w, "Failed to update category")
+		return
+	}
+
+	response.OK(w, category)
+}
+
+// Delete godoc
+// @Summary Delete category
+// @Description Delete a category without subcategories; its posts are unassigned from it
+// @Tags categories
+// @Param id path string true "Category ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid category ID")
+		return
+	}
+
+	err = h.repo.Delete(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Category not found")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.Conflict(w, "Category has subcategories; move or delete them first")
+			return
+		}
+		response.InternalError(w, "Failed to delete category")
+		return
+	}
+
+	response.NoContent(w)
+}
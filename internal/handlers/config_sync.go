@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/configsync"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// maxConfigDocumentSize limits uploaded configuration documents
+const maxConfigDocumentSize = 1 << 20
+
+type ConfigSyncHandler struct {
+	contentTypeRepo *repository.ContentTypeRepository
+	settingRepo     *repository.SettingRepository
+	reconciler      *configsync.Reconciler
+}
+
+func NewConfigSyncHandler(contentTypeRepo *repository.ContentTypeRepository, settingRepo *repository.SettingRepository) *ConfigSyncHandler {
+	return &ConfigSyncHandler{
+		contentTypeRepo: contentTypeRepo,
+		settingRepo:     settingRepo,
+		reconciler:      configsync.NewReconciler(contentTypeRepo, settingRepo),
+	}
+}
+
+// Export godoc
+// @Summary Export configuration as YAML
+// @Description Serialize every content type definition, and the settings whose keys start with the given prefixes, to a YAML document for version control
+// @Tags config
+// @Produce application/yaml
+// @Param settings query string false "Comma-separated setting key prefixes to include, e.g. site.,contacts."
+// @Success 200 {string} string "YAML document"
+// @Router /api/v1/config [get]
+func (h *ConfigSyncHandler) Export(w http.ResponseWriter, r *http.Request) {
+	contentTypes, err := h.contentTypeRepo.ListAll(r.Context())
+	if err != nil {
+		response.InternalError(w, "Failed to list content types")
+		return
+	}
+
+	var settings []models.Setting
+	if prefixes := splitList(r.URL.Query().Get("settings")); len(prefixes) > 0 {
+		settings, err = h.settingRepo.ListByPrefix(r.Context(), prefixes)
+		if err != nil {
+			response.InternalError(w, "Failed to list settings")
+			return
+		}
+	}
+
+	doc, err := configsync.Export(contentTypes, settings)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to export configuration", err)
+		return
+	}
+	out, err := configsync.Marshal(doc)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to export configuration", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+}
+
+// Diff godoc
+// @Summary Plan configuration changes
+// @Description Compare a YAML configuration document with this instance and list the content types that would be created or updated and the settings that would be created. Nothing is changed.
+// @Tags config
+// @Accept application/yaml
+// @Produce json
+// @Param body body string true "YAML document"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/config/diff [post]
+func (h *ConfigSyncHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	_, plan, ok := h.plan(w, r)
+	if !ok {
+		return
+	}
+	response.OK(w, plan)
+}
+
+// Apply godoc
+// @Summary Apply a configuration document
+// @Description Create and update content types to match a YAML configuration document and create its missing settings. Content types missing from the document and existing settings are left alone.
+// @Tags config
+// @Accept application/yaml
+// @Produce json
+// @Param body body string true "YAML document"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/config/apply [post]
+func (h *ConfigSyncHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	doc, plan, ok := h.plan(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.reconciler.Apply(r.Context(), doc, plan); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "A content type name in the document is already used by another content type")
+			return
+		}
+		reqctx.Logf(r.Context(), "[ERROR] Applying configuration failed: %v", err)
+		response.InternalError(w, "Failed to apply configuration; changes before the failure were kept")
+		return
+	}
+
+	response.OK(w, plan)
+}
+
+// plan reads the document from the request and compares it with the
+// instance, writing an error response when either fails
+func (h *ConfigSyncHandler) plan(w http.ResponseWriter, r *http.Request) (*configsync.Document, *configsync.Plan, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxConfigDocumentSize))
+	if err != nil {
+		response.BadRequest(w, "Configuration document is too large")
+		return nil, nil, false
+	}
+	doc, err := configsync.Parse(body)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return nil, nil, false
+	}
+
+	plan, err := h.reconciler.Plan(r.Context(), doc)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to compare configuration", err)
+		return nil, nil, false
+	}
+	return doc, plan, true
+}
+
+// splitList splits a comma-separated query value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type ConsentHandler struct {
+	repo *repository.ConsentRepository
+}
+
+func NewConsentHandler(repo *repository.ConsentRepository) *ConsentHandler {
+	return &ConsentHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List consent versions
+// @Description Get all published and scheduled privacy policy / terms versions
+// @Tags consent
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/consent-versions [get]
+func (h *ConsentHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.ConsentVersionFilter{
+		PaginationParams: parsePaginationParams(r),
+	}
+
+	versions, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list consent versions")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, versions, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get consent version by ID
+// @Description Get a single consent version by its ID
+// @Tags consent
+// @Produce json
+// @Param id path string true "Consent Version ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/consent-versions/{id} [get]
+func (h *ConsentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid consent version ID")
+		return
+	}
+
+	cv, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Consent version not found")
+			return
+		}
+		response.InternalError(w, "Failed to get consent version")
+		return
+	}
+
+	response.OK(w, cv)
+}
+
+// GetCurrent godoc
+// @Summary Get current consent version
+// @Description Get the consent version public forms must ask users to accept (public endpoint)
+// @Tags consent
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/consent-versions/current [get]
+func (h *ConsentHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	cv, err := h.repo.GetCurrent(r.Context())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "No consent version has been published")
+			return
+		}
+		response.InternalError(w, "Failed to get current consent version")
+		return
+	}
+
+	response.OK(w, cv)
+}
+
+// Create godoc
+// @Summary Publish consent version
+// @Description Publish a new privacy policy / terms version
+// @Tags consent
+// @Accept json
+// @Produce json
+// @Param body body models.CreateConsentVersionRequest true "Consent version data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/consent-versions [post]
+func (h *ConsentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateConsentVersionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	// Validate required fields
+	validationErrors := make(map[string]string)
+	if req.Version == "" {
+		validationErrors["version"] = "Version is required"
+	}
+	if req.Title == "" {
+		validationErrors["title"] = "Title is required"
+	}
+	if req.Content == nil && req.DocumentURL == nil {
+		validationErrors["content"] = "Content or document URL is required"
+	}
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	cv, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Consent version already exists")
+			return
+		}
+		response.InternalError(w, "Failed to create consent version")
+		return
+	}
+
+	response.Created(w, cv)
+}
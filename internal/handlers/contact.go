@@ -1,22 +1,57 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/geoip"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
 	"github.com/keeps-dev/go-cms-template/internal/models"
 	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
 	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/service"
 )
 
 type ContactHandler struct {
-	repo *repository.ContactRepository
+	repo          *repository.ContactRepository
+	consentRepo   *repository.ConsentRepository
+	blocklistRepo *repository.BlocklistRepository
+	settingRepo   *repository.SettingRepository
+	geo           *geoip.Resolver
+	mailer        *mailer.Mailer
+	notifyTo      string
+	contacts      *service.ContactService
+	accessLog     *accessLogger
 }
 
-func NewContactHandler(repo *repository.ContactRepository) *ContactHandler {
-	return &ContactHandler{repo: repo}
+func NewContactHandler(
+	repo *repository.ContactRepository,
+	consentRepo *repository.ConsentRepository,
+	blocklistRepo *repository.BlocklistRepository,
+	settingRepo *repository.SettingRepository,
+	geo *geoip.Resolver,
+	mail *mailer.Mailer,
+	notifyTo string,
+	contacts *service.ContactService,
+	accessLogRepo *repository.AccessLogRepository,
+) *ContactHandler {
+	return &ContactHandler{
+		repo:          repo,
+		consentRepo:   consentRepo,
+		blocklistRepo: blocklistRepo,
+		settingRepo:   settingRepo,
+		geo:           geo,
+		mailer:        mail,
+		notifyTo:      notifyTo,
+		contacts:      contacts,
+		accessLog:     newAccessLogger(accessLogRepo),
+	}
 }
 
 // List godoc
@@ -28,20 +63,11 @@ func NewContactHandler(repo *repository.ContactRepository) *ContactHandler {
 // @Param page_size query int false "Page size"
 // @Param status query int false "Filter by status (1=new, 2=read, 3=replied, 4=archived)"
 // @Param email query string false "Filter by email"
+// @Param meta[field] query string false "Filter by a metadata or extra field, e.g. meta[company]=Acme or meta[budget][gte]=1000"
 // @Success 200 {object} response.APIResponse
 // @Router /api/v1/contacts [get]
 func (h *ContactHandler) List(w http.ResponseWriter, r *http.Request) {
-	filter := models.ContactFilter{
-		PaginationParams: parsePaginationParams(r),
-		Email:            r.URL.Query().Get("email"),
-	}
-
-	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
-		if s, err := strconv.Atoi(statusStr); err == nil {
-			status := models.ContactStatus(s)
-			filter.Status = &status
-		}
-	}
+	filter := parseContactFilter(r)
 
 	contacts, total, err := h.repo.List(r.Context(), filter)
 	if err != nil {
@@ -49,6 +75,12 @@ func (h *ContactHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]uuid.UUID, len(contacts))
+	for i := range contacts {
+		ids[i] = contacts[i].ID
+	}
+	h.accessLog.record(w, r, models.AccessResourceContact, models.AccessActionList, ids...)
+
 	response.JSONWithMeta(w, http.StatusOK, contacts, &response.Meta{
 		Page:       filter.Page,
 		PageSize:   filter.PageSize,
@@ -57,6 +89,59 @@ func (h *ContactHandler) List(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Export godoc
+// @Summary Export contact submissions as CSV
+// @Description Stream all contact submissions matching the list filters as a CSV file, with a column per extra field
+// @Tags contacts
+// @Produce text/csv
+// @Param status query int false "Filter by status (1=new, 2=read, 3=replied, 4=archived)"
+// @Param email query string false "Filter by email"
+// @Param meta[field] query string false "Filter by a metadata or extra field"
+// @Success 200 {file} file
+// @Router /api/v1/contacts/export [get]
+func (h *ContactHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter := parseContactFilter(r)
+
+	fields, err := h.contactFields(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load contact fields", err)
+		return
+	}
+
+	header := []string{"id", "name", "email", "phone", "subject", "message", "status", "read_at", "created_at"}
+	for _, f := range fields {
+		header = append(header, f.Name)
+	}
+	export, err := newCSVExport(w, "contacts", header)
+	if err != nil {
+		return
+	}
+
+	err = h.repo.ExportEach(r.Context(), filter, func(contact *models.ContactSubmission) error {
+		row := []string{
+			contact.ID.String(),
+			contact.Name,
+			contact.Email,
+			csvString(contact.Phone),
+			csvString(contact.Subject),
+			contact.Message,
+			contact.Status.String(),
+			csvTime(contact.ReadAt),
+			csvTime(&contact.CreatedAt),
+		}
+		if len(fields) > 0 {
+			var metadata map[string]interface{}
+			json.Unmarshal(contact.Metadata, &metadata)
+			for _, f := range fields {
+				row = append(row, contactFieldCSV(metadata, f.Name))
+			}
+		}
+		return export.Write(row)
+	})
+	export.Finish(r.Context(), "contacts", err)
+	h.accessLog.record(w, r, models.AccessResourceContact, models.AccessActionExport)
+}
+
 // Get godoc
 // @Summary Get contact submission by ID
 // @Description Get a single contact submission by its ID
@@ -83,12 +168,13 @@ func (h *ContactHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.accessLog.record(w, r, models.AccessResourceContact, models.AccessActionRead, contact.ID)
 	response.OK(w, contact)
 }
 
 // Create godoc
 // @Summary Create contact submission
-// @Description Create a new contact submission (public endpoint)
+// @Description Create a new contact submission (public endpoint). Values of the extra fields defined in the contacts.fields setting go in fields and are stored in metadata.
 // @Tags contacts
 // @Accept json
 // @Produce json
@@ -115,30 +201,92 @@ func (h *ContactHandler) Create(w http.ResponseWriter, r *http.Request) {
 		validationErrors["message"] = "Message is required"
 	}
 
+	// Require acceptance of a published consent version once one exists
+	if req.ConsentVersion != nil && *req.ConsentVersion != "" {
+		cv, err := h.consentRepo.GetByVersion(r.Context(), *req.ConsentVersion)
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				response.InternalError(w, "Failed to verify consent version")
+				return
+			}
+			validationErrors["consent_version"] = "Unknown consent version"
+		} else {
+			req.ConsentVersionID = &cv.ID
+		}
+	} else {
+		if _, err := h.consentRepo.GetCurrent(r.Context()); err == nil {
+			validationErrors["consent_version"] = "Consent version is required"
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			response.InternalError(w, "Failed to verify consent version")
+			return
+		}
+	}
+
+	fields, err := h.contactFields(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load contact fields", err)
+		return
+	}
+	values := validateContactFields(fields, req.Fields, validationErrors)
+
 	if len(validationErrors) > 0 {
 		response.ValidationError(w, validationErrors)
 		return
 	}
 
+	metadata, err := applyContactFields(req.Metadata, fields, values)
+	if err != nil {
+		response.ValidationError(w, map[string]string{"metadata": "Metadata must be a JSON object"})
+		return
+	}
+	req.Metadata = metadata
+
 	// Capture client info
-	ipAddr := r.Header.Get("X-Forwarded-For")
-	if ipAddr == "" {
-		ipAddr = r.Header.Get("X-Real-IP")
+	ipAddr := clientIP(r)
+	req.IPAddress = &ipAddr
+
+	// Reject or silently discard submissions from blocked sources
+	blockIP := ""
+	if net.ParseIP(ipAddr) != nil {
+		blockIP = ipAddr
+	}
+	rule, err := h.blocklistRepo.Match(r.Context(), blockIP, req.Email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalErrorWithErr(w, "Failed to check blocklist", err)
+		return
 	}
-	if ipAddr == "" {
-		ipAddr = r.RemoteAddr
+	if rule != nil {
+		if rule.Action == models.BlocklistActionDiscard {
+			response.Created(w, discardedContact(&req))
+			return
+		}
+		response.Forbidden(w, "Submission rejected")
+		return
+	}
+
+	// Enrich with geographic data when a GeoIP database is configured
+	if location, ok := h.geo.Lookup(net.ParseIP(ipAddr)); ok {
+		if metadata, err := mergeMetadata(req.Metadata, "geo", location); err == nil {
+			req.Metadata = metadata
+		}
 	}
-	req.IPAddress = &ipAddr
 
 	userAgent := r.Header.Get("User-Agent")
 	req.UserAgent = &userAgent
 
-	contact, err := h.repo.Create(r.Context(), &req)
+	contact, err := h.contacts.Create(r.Context(), &req)
 	if err != nil {
 		response.InternalError(w, "Failed to create contact submission")
 		return
 	}
 
+	// Notify the site owner; a queueing failure must not fail the submission
+	if h.notifyTo != "" {
+		if _, err := h.mailer.Enqueue(r.Context(), h.notifyTo, "contact_notification", "", contact); err != nil {
+			reqctx.Logf(r.Context(), "[ERROR] Failed to queue contact notification: %v", err)
+		}
+	}
+
 	response.Created(w, contact)
 }
 
@@ -209,6 +357,23 @@ func (h *ContactHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+// CountByCountry godoc
+// @Summary Contact submissions by country
+// @Description Get the number of contact submissions per country resolved by GeoIP
+// @Tags contacts
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/contacts/by-country [get]
+func (h *ContactHandler) CountByCountry(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.repo.CountByCountry(r.Context())
+	if err != nil {
+		response.InternalError(w, "Failed to count contact submissions by country")
+		return
+	}
+
+	response.OK(w, counts)
+}
+
 // GetUnreadCount godoc
 // @Summary Get unread contact count
 // @Description Get the count of unread contact submissions
@@ -225,3 +390,35 @@ func (h *ContactHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request)
 
 	response.OK(w, map[string]int64{"unread_count": count})
 }
+
+// parseContactFilter extracts contact filter options from the query string
+func parseContactFilter(r *http.Request) models.ContactFilter {
+	filter := models.ContactFilter{
+		PaginationParams: parsePaginationParams(r),
+		Email:            r.URL.Query().Get("email"),
+		Meta:             parseMetaFilters(r),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if status, ok := models.ParseEnum[models.ContactStatus](statusStr); ok {
+			filter.Status = &status
+		}
+	}
+
+	return filter
+}
+
+// discardedContact builds a plausible response for a silently discarded
+// submission so blocked senders can't tell it was dropped
+func discardedContact(req *models.CreateContactRequest) *models.ContactSubmission {
+	return &models.ContactSubmission{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Subject:   req.Subject,
+		Message:   req.Message,
+		Status:    models.ContactStatusNew,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// SettingContactFields defines the extra contact form fields as a JSON array
+// of models.ContactField, e.g. [{"name": "company", "type": "string"}]
+const SettingContactFields = "contacts.fields"
+
+// contactReservedFields are metadata keys the server sets itself
+var contactReservedFields = map[string]bool{"geo": true}
+
+// Fields godoc
+// @Summary List extra contact fields
+// @Description Get the extra contact form fields defined in the contacts.fields setting, for rendering the form (public endpoint)
+// @Tags contacts
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/contacts/fields [get]
+func (h *ContactHandler) Fields(w http.ResponseWriter, r *http.Request) {
+	fields, err := h.contactFields(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load contact fields", err)
+		return
+	}
+	if fields == nil {
+		fields = []models.ContactField{}
+	}
+	response.OK(w, fields)
+}
+
+// contactFields reads the extra field definitions from settings. A missing
+// setting means no extra fields.
+func (h *ContactHandler) contactFields(ctx context.Context) ([]models.ContactField, error) {
+	settings, err := h.settingRepo.GetMultiple(ctx, []string{SettingContactFields})
+	if err != nil {
+		return nil, err
+	}
+	raw := settings[SettingContactFields]
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []models.ContactField
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, fmt.Errorf("invalid %s setting: %w", SettingContactFields, err)
+	}
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		switch {
+		case !models.ValidMetaField(f.Name) || contactReservedFields[f.Name]:
+			return nil, fmt.Errorf("invalid %s setting: invalid field name %q", SettingContactFields, f.Name)
+		case seen[f.Name]:
+			return nil, fmt.Errorf("invalid %s setting: duplicate field %q", SettingContactFields, f.Name)
+		case f.Type == models.ContactFieldSelect && len(f.Options) == 0:
+			return nil, fmt.Errorf("invalid %s setting: select field %q has no options", SettingContactFields, f.Name)
+		case f.Type != models.ContactFieldString && f.Type != models.ContactFieldNumber &&
+			f.Type != models.ContactFieldBoolean && f.Type != models.ContactFieldSelect:
+			return nil, fmt.Errorf("invalid %s setting: field %q has unknown type %q", SettingContactFields, f.Name, f.Type)
+		}
+		seen[f.Name] = true
+	}
+	return fields, nil
+}
+
+// validateContactFields checks submitted values against the field
+// definitions, adding messages to validationErrors under fields.<name>. It
+// returns the values to store in metadata.
+func validateContactFields(fields []models.ContactField, values map[string]json.RawMessage, validationErrors map[string]string) map[string]interface{} {
+	known := make(map[string]bool, len(fields))
+	valid := make(map[string]interface{})
+
+	for _, f := range fields {
+		known[f.Name] = true
+		key := "fields." + f.Name
+
+		raw, ok := values[f.Name]
+		if !ok || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if f.Required {
+				validationErrors[key] = "Field is required"
+			}
+			continue
+		}
+
+		value, msg := contactFieldValue(f, raw)
+		if msg != "" {
+			validationErrors[key] = msg
+			continue
+		}
+		if f.Required && value == "" {
+			validationErrors[key] = "Field is required"
+			continue
+		}
+		valid[f.Name] = value
+	}
+
+	for name := range values {
+		if !known[name] {
+			validationErrors["fields."+name] = "Unknown field"
+		}
+	}
+	return valid
+}
+
+// contactFieldValue decodes a single value, returning a message when it
+// doesn't satisfy the field definition
+func contactFieldValue(f models.ContactField, raw json.RawMessage) (interface{}, string) {
+	switch f.Type {
+	case models.ContactFieldNumber:
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, "Must be a number"
+		}
+		if f.Min != nil && n < *f.Min {
+			return nil, "Must be at least " + strconv.FormatFloat(*f.Min, 'f', -1, 64)
+		}
+		if f.Max != nil && n > *f.Max {
+			return nil, "Must be at most " + strconv.FormatFloat(*f.Max, 'f', -1, 64)
+		}
+		return n, ""
+	case models.ContactFieldBoolean:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, "Must be true or false"
+		}
+		return b, ""
+	case models.ContactFieldSelect:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, "Must be a string"
+		}
+		if s == "" {
+			return s, ""
+		}
+		for _, option := range f.Options {
+			if s == option {
+				return s, ""
+			}
+		}
+		return nil, "Must be one of: " + strings.Join(f.Options, ", ")
+	default:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, "Must be a string"
+		}
+		s = strings.TrimSpace(s)
+		if f.MaxLength > 0 && utf8.RuneCountInString(s) > f.MaxLength {
+			return nil, fmt.Sprintf("Must be at most %d characters", f.MaxLength)
+		}
+		return s, ""
+	}
+}
+
+// applyContactFields stores the validated field values in metadata. Keys of
+// defined fields sent in metadata directly are dropped, so stored values are
+// always validated.
+func applyContactFields(raw json.RawMessage, fields []models.ContactField, values map[string]interface{}) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return raw, nil
+	}
+	data := make(map[string]interface{})
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fields {
+		delete(data, f.Name)
+		if value, ok := values[f.Name]; ok {
+			data[f.Name] = value
+		}
+	}
+	return json.Marshal(data)
+}
+
+// contactFieldCSV formats the stored value of a field for CSV export
+func contactFieldCSV(metadata map[string]interface{}, name string) string {
+	switch v := metadata[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
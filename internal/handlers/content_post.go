@@ -1,23 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
 	"github.com/keeps-dev/go-cms-template/internal/models"
 	"github.com/keeps-dev/go-cms-template/internal/repository"
 	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/service"
 )
 
 type ContentPostHandler struct {
-	repo *repository.ContentPostRepository
+	repo            PostRepository
+	posts           *service.PostService
+	contentTypeRepo *repository.ContentTypeRepository
+	teamRepo        *repository.TeamRepository
+	templateRepo    *repository.PostTemplateRepository
+	annotationRepo  *repository.AnnotationRepository
+	trafficRepo     *repository.TrafficRepository
+	facets          *facetCache
 }
 
-func NewContentPostHandler(repo *repository.ContentPostRepository) *ContentPostHandler {
-	return &ContentPostHandler{repo: repo}
+func NewContentPostHandler(repo PostRepository, posts *service.PostService, contentTypeRepo *repository.ContentTypeRepository, teamRepo *repository.TeamRepository, templateRepo *repository.PostTemplateRepository, annotationRepo *repository.AnnotationRepository, trafficRepo *repository.TrafficRepository) *ContentPostHandler {
+	return &ContentPostHandler{repo: repo, posts: posts, contentTypeRepo: contentTypeRepo, teamRepo: teamRepo, templateRepo: templateRepo, annotationRepo: annotationRepo, trafficRepo: trafficRepo, facets: newFacetCache(repo)}
 }
 
 // List godoc
@@ -29,47 +44,179 @@ func NewContentPostHandler(repo *repository.ContentPostRepository) *ContentPostH
 // @Param page_size query int false "Page size"
 // @Param content_type_id query string false "Filter by content type ID"
 // @Param author_id query string false "Filter by author ID"
+// @Param team_id query string false "Filter by owning team ID"
+// @Param category_id query string false "Filter by category ID, including subcategories"
 // @Param status query int false "Filter by status (1=draft, 2=published, 3=archived)"
 // @Param search query string false "Search in title and excerpt"
+// @Param include_view_stats query bool false "Include views_7d and views_30d"
+// @Param environment query string false "Content environment (live or draft)"
+// @Param meta[field] query string false "Filter by metadata field, e.g. meta[color]=red or meta[price][lte]=100 (eq, lt, lte, gt, gte)"
+// @Param facets query string false "Comma-separated facets to count (tags, meta.<field>), returned in meta.facets"
+// @Param expired query bool false "Only posts whose expires_at has (true) or has not (false) passed"
+// @Param near query string false "Only posts within radius of lat,lng"
+// @Param radius query number false "Radius in kilometres for near (default 10)"
+// @Param sort_by query string false "Sort column, or distance with near"
+// @Param format query string false "geojson for a GeoJSON FeatureCollection"
 // @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
 // @Router /api/v1/posts [get]
 func (h *ContentPostHandler) List(w http.ResponseWriter, r *http.Request) {
-	filter := models.PostFilter{
-		PaginationParams: parsePaginationParams(r),
-		Search:           r.URL.Query().Get("search"),
+	filter := parsePostFilter(r)
+
+	facetFields, msg := parseFacets(r)
+	if msg != "" {
+		response.ValidationError(w, map[string]string{"facets": msg})
+		return
 	}
 
-	if ctID := r.URL.Query().Get("content_type_id"); ctID != "" {
-		if id, err := uuid.Parse(ctID); err == nil {
-			filter.ContentTypeID = &id
+	posts, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list posts")
+		return
+	}
+
+	meta := &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	}
+	if len(facetFields) > 0 {
+		meta.Facets, err = h.facets.compute(r.Context(), r, publishedUnlessEditor(r, filter), facetFields)
+		if err != nil {
+			response.InternalError(w, "Failed to compute facets")
+			return
 		}
 	}
 
-	if authorID := r.URL.Query().Get("author_id"); authorID != "" {
-		if id, err := uuid.Parse(authorID); err == nil {
-			filter.AuthorID = &id
+	if r.URL.Query().Get("format") == "geojson" {
+		writeGeoJSON(w, posts)
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, posts, meta)
+}
+
+// partialResultMargin is how long before the request deadline partial
+// reports stop computing, leaving time to send the results
+const partialResultMargin = time.Second
+
+// Aggregate godoc
+// @Summary Aggregate posts by metadata
+// @Description Group posts matching the list filters by a metadata field and count them or compute avg, sum, min or max of a numeric metadata field per group. Callers below editor only see published posts
+// @Tags posts
+// @Produce json
+// @Param content_type query string false "Filter by content type slug"
+// @Param group_by query string false "Field to group by (meta.<field>); omit for a single bucket"
+// @Param metric query string false "count (default) or avg|sum|min|max:meta.<field>"
+// @Param meta[field] query string false "Filter by metadata field, e.g. meta[price][lte]=100"
+// @Param environment query string false "Content environment (live or draft)"
+// @Param partial query bool false "Return the groups computed so far, with meta.truncated, instead of failing with 504 when the request times out"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 504 {object} response.APIResponse
+// @Router /api/v1/posts/aggregate [get]
+func (h *ContentPostHandler) Aggregate(w http.ResponseWriter, r *http.Request) {
+	agg := models.PostAggregate{
+		Filter: publishedUnlessEditor(r, parsePostFilter(r)),
+		Metric: models.AggregateCount,
+	}
+
+	validationErrors := make(map[string]string)
+
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		field, ok := strings.CutPrefix(groupBy, "meta.")
+		if !ok || !models.ValidMetaField(field) {
+			validationErrors["group_by"] = "Must be meta.<field>"
 		}
+		agg.GroupBy = field
 	}
 
-	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
-		if s, err := strconv.Atoi(statusStr); err == nil {
-			status := models.PostStatus(s)
-			filter.Status = &status
+	if metric := r.URL.Query().Get("metric"); metric != "" && metric != models.AggregateCount {
+		fn, field, _ := strings.Cut(metric, ":")
+		field, ok := strings.CutPrefix(field, "meta.")
+		switch {
+		case fn != models.AggregateAvg && fn != models.AggregateSum && fn != models.AggregateMin && fn != models.AggregateMax:
+			validationErrors["metric"] = "Must be count, avg, sum, min or max"
+		case !ok || !models.ValidMetaField(field):
+			validationErrors["metric"] = "Must be count or <fn>:meta.<field>"
 		}
+		agg.Metric = fn
+		agg.MetricField = field
 	}
 
-	posts, total, err := h.repo.List(r.Context(), filter)
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if partial := getBoolParam(r, "partial"); partial != nil && *partial {
+		ctx := r.Context()
+		// Stop early enough to still send what was computed
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline.Add(-partialResultMargin))
+			defer cancel()
+		}
+
+		buckets, truncated, err := h.repo.AggregatePartial(ctx, agg)
+		if err != nil {
+			response.InternalError(w, "Failed to aggregate posts")
+			return
+		}
+		response.JSONWithMeta(w, http.StatusOK, buckets, &response.Meta{Truncated: &truncated})
+		return
+	}
+
+	buckets, err := h.repo.Aggregate(r.Context(), agg)
 	if err != nil {
-		response.InternalError(w, "Failed to list posts")
+		response.InternalError(w, "Failed to aggregate posts")
 		return
 	}
 
-	response.JSONWithMeta(w, http.StatusOK, posts, &response.Meta{
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		Total:      total,
-		TotalPages: int(total)/filter.PageSize + 1,
+	response.OK(w, buckets)
+}
+
+// Export godoc
+// @Summary Export posts as CSV
+// @Description Stream all posts matching the list filters as a CSV file
+// @Tags posts
+// @Produce text/csv
+// @Param content_type_id query string false "Filter by content type ID"
+// @Param author_id query string false "Filter by author ID"
+// @Param team_id query string false "Filter by owning team ID"
+// @Param category_id query string false "Filter by category ID, including subcategories"
+// @Param status query int false "Filter by status (1=draft, 2=published, 3=archived)"
+// @Param search query string false "Search in title and excerpt"
+// @Param meta[field] query string false "Filter by metadata field, e.g. meta[price][lte]=100"
+// @Success 200 {file} file
+// @Router /api/v1/posts/export [get]
+func (h *ContentPostHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter := parsePostFilter(r)
+
+	export, err := newCSVExport(w, "posts", []string{
+		"id", "title", "slug", "status", "content_type", "author", "view_count",
+		"published_at", "created_at", "updated_at",
+	})
+	if err != nil {
+		return
+	}
+
+	err = h.repo.ExportEach(r.Context(), filter, func(post *models.ContentPost) error {
+		return export.Write([]string{
+			post.ID.String(),
+			post.Title,
+			post.Slug,
+			post.Status.String(),
+			post.ContentType.Name,
+			post.Author.FullName,
+			strconv.Itoa(post.ViewCount),
+			csvTime(post.PublishedAt),
+			csvTime(&post.CreatedAt),
+			csvTime(&post.UpdatedAt),
+		})
 	})
+	export.Finish(r.Context(), "posts", err)
 }
 
 // Get godoc
@@ -78,6 +225,7 @@ func (h *ContentPostHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Tags posts
 // @Produce json
 // @Param id path string true "Post ID"
+// @Param include_annotations query bool false "Include open editorial annotation threads"
 // @Success 200 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Router /api/v1/posts/{id} [get]
@@ -98,6 +246,15 @@ func (h *ContentPostHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if include := getBoolParam(r, "include_annotations"); include != nil && *include {
+		open := false
+		post.Annotations, err = h.annotationRepo.ListThreads(r.Context(), models.AnnotationFilter{PostID: post.ID, Resolved: &open})
+		if err != nil {
+			response.InternalError(w, "Failed to list annotations")
+			return
+		}
+	}
+
 	response.OK(w, post)
 }
 
@@ -107,6 +264,11 @@ func (h *ContentPostHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Tags posts
 // @Produce json
 // @Param slug path string true "Post Slug"
+// @Param environment query string false "Content environment (live or draft)"
+// @Param referrer query string false "The reader's referrer, recorded as a traffic source (defaults to the Referer header)"
+// @Param utm_source query string false "Campaign source, recorded as a traffic source"
+// @Param utm_medium query string false "Campaign medium, recorded as a traffic source"
+// @Param utm_campaign query string false "Campaign name, recorded as a traffic source"
 // @Success 200 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Router /api/v1/posts/slug/{slug} [get]
@@ -117,7 +279,8 @@ func (h *ContentPostHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.repo.GetBySlug(r.Context(), slug)
+	env := parseEnvironment(r)
+	post, err := h.repo.GetBySlugInEnvironment(r.Context(), slug, env)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			response.NotFound(w, "Post not found")
@@ -127,30 +290,55 @@ func (h *ContentPostHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Increment view count asynchronously
-	go func() {
-		_ = h.repo.IncrementViewCount(r.Context(), post.ID)
-	}()
+	// Increment view count asynchronously; the request context is cancelled
+	// once the response is written, so use a detached one. Draft previews
+	// are not counted.
+	if post.Environment == models.EnvironmentLive {
+		source := trafficSource(r)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = h.repo.IncrementViewCount(ctx, post.ID)
+			_ = h.trafficRepo.RecordView(ctx, post.ID, source)
+		}()
+	}
 
 	response.OK(w, post)
 }
 
 // Create godoc
 // @Summary Create post
-// @Description Create a new post
+// @Description Create a new post by the authenticated user; admins may name another author_id. With template, fields left out are filled from the post template and the post starts as a draft.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param body body models.CreatePostRequest true "Post data"
+// @Param template query string false "Post template ID to pre-fill the draft from"
 // @Success 201 {object} response.APIResponse
 // @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Router /api/v1/posts [post]
 func (h *ContentPostHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+
 	var req models.CreatePostRequest
 	if err := decodeJSON(r, &req); err != nil {
 		response.BadRequest(w, "Invalid request body")
 		return
 	}
+	if req.AuthorID == uuid.Nil {
+		req.AuthorID = user.ID
+	} else if req.AuthorID != user.ID && user.Role < models.RoleAdmin {
+		response.Forbidden(w, "Only admins can create posts for another author")
+		return
+	}
+	if !h.applyTemplate(w, r, &req) {
+		return
+	}
 
 	// Validate required fields
 	validationErrors := make(map[string]string)
@@ -163,8 +351,13 @@ func (h *ContentPostHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if req.ContentTypeID == uuid.Nil {
 		validationErrors["content_type_id"] = "Content type ID is required"
 	}
-	if req.AuthorID == uuid.Nil {
-		validationErrors["author_id"] = "Author ID is required"
+	if req.Environment != "" && !models.ValidEnvironment(req.Environment) {
+		validationErrors["environment"] = "Environment must be live or draft"
+	}
+	validateLocation(req.Latitude, req.Longitude, validationErrors)
+	validateEvent(req.StartsAt, req.EndsAt, req.Recurrence, validationErrors)
+	if (req.EndsAt != nil || req.Recurrence != nil) && req.StartsAt == nil {
+		validationErrors["starts_at"] = "Start is required for events with an end or recurrence"
 	}
 
 	if len(validationErrors) > 0 {
@@ -172,14 +365,41 @@ func (h *ContentPostHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.repo.Create(r.Context(), &req)
+	// Unknown content types are reported by the insert below
+	ct, err := h.contentTypeRepo.GetByID(r.Context(), req.ContentTypeID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		response.InternalError(w, "Failed to get content type")
+		return
+	}
+	if ct != nil {
+		validatePostTraits(ct, &models.ContentPost{
+			Metadata:  req.Metadata,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+			StartsAt:  req.StartsAt,
+			ExpiresAt: req.ExpiresAt,
+		}, validationErrors)
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+	if req.TeamID != nil && !h.checkTeam(w, r, *req.TeamID) {
+		return
+	}
+
+	post, err := h.posts.Create(r.Context(), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrApprovalRequired) {
+			response.ValidationError(w, map[string]string{"status": "Posts of this content type are published after approval; create a draft and submit it"})
+			return
+		}
 		if errors.Is(err, repository.ErrDuplicate) {
 			response.Conflict(w, "Post with this slug already exists")
 			return
 		}
 		if errors.Is(err, repository.ErrForeignKey) {
-			response.BadRequest(w, "Invalid content type ID or author ID")
+			response.BadRequest(w, "Invalid content type ID, author ID, team ID or category ID")
 			return
 		}
 		response.InternalErrorWithErr(w, "Failed to create post", err)
@@ -189,16 +409,97 @@ func (h *ContentPostHandler) Create(w http.ResponseWriter, r *http.Request) {
 	response.Created(w, post)
 }
 
+// applyTemplate fills what the request leaves out from the template named by
+// ?template=: the content type and team, a title from the title pattern (and
+// a slug from the title), excerpt and content. Template metadata and tags
+// are defaults the request adds to. It reports false once it has responded.
+func (h *ContentPostHandler) applyTemplate(w http.ResponseWriter, r *http.Request, req *models.CreatePostRequest) bool {
+	raw := r.URL.Query().Get("template")
+	if raw == "" {
+		return true
+	}
+	id, err := parseUUID(raw)
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return false
+	}
+	t, err := h.templateRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post template not found")
+			return false
+		}
+		response.InternalError(w, "Failed to get post template")
+		return false
+	}
+
+	validationErrors := make(map[string]string)
+	if !t.IsActive {
+		validationErrors["template"] = "Template is inactive"
+	}
+	if req.ContentTypeID == uuid.Nil {
+		req.ContentTypeID = t.ContentTypeID
+	} else if req.ContentTypeID != t.ContentTypeID {
+		validationErrors["content_type_id"] = "Content type must be the template's"
+	}
+	if t.TeamID != nil {
+		if req.TeamID == nil {
+			req.TeamID = t.TeamID
+		} else if *req.TeamID != *t.TeamID {
+			validationErrors["team_id"] = "Team must be the template's"
+		}
+	}
+	if req.Status != nil && *req.Status != models.PostStatusDraft {
+		validationErrors["status"] = "Posts created from a template start as drafts"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return false
+	}
+
+	if req.Title == "" && t.TitlePattern != nil {
+		ct, err := h.contentTypeRepo.GetByID(r.Context(), t.ContentTypeID)
+		if err != nil {
+			response.InternalError(w, "Failed to get content type")
+			return false
+		}
+		req.Title = t.Title(time.Now(), ct.Name)
+	}
+	if req.Slug == "" {
+		req.Slug = slugify(req.Title)
+	}
+	if req.Excerpt == nil {
+		req.Excerpt = t.Excerpt
+	}
+	if req.Content == nil {
+		req.Content = t.Content
+	}
+	metadata, err := mergeObjects(t.Metadata, req.Metadata)
+	if err != nil {
+		response.ValidationError(w, map[string]string{"metadata": "Metadata must be a JSON object"})
+		return false
+	}
+	req.Metadata = metadata
+	for _, tagID := range t.TagIDs {
+		if !slices.Contains(req.TagIDs, tagID) {
+			req.TagIDs = append(req.TagIDs, tagID)
+		}
+	}
+	return true
+}
+
 // Update godoc
 // @Summary Update post
-// @Description Update an existing post
+// @Description Update an existing post. Posts in a team's space, or moving into one, can only be updated by editors and managers of the team, and admins. Only admins may change author_id.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param id path string true "Post ID"
 // @Param body body models.UpdatePostRequest true "Post data"
+// @Param environment query string false "Set to draft to edit a copy-on-write draft of a live post"
 // @Success 200 {object} response.APIResponse
 // @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Router /api/v1/posts/{id} [put]
 func (h *ContentPostHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -214,8 +515,29 @@ func (h *ContentPostHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := h.repo.Update(r.Context(), id, &req)
+	validationErrors := make(map[string]string)
+	validateLocation(req.Latitude, req.Longitude, validationErrors)
+	validateEvent(req.StartsAt, req.EndsAt, req.Recurrence, validationErrors)
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	id, ok := h.writablePostID(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !h.validateUpdate(w, r, id, &req) {
+		return
+	}
+
+	post, err := h.posts.Update(r.Context(), id, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrApprovalRequired) {
+			response.ValidationError(w, map[string]string{"status": "The post needs an approved approval request before it is published"})
+			return
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			response.NotFound(w, "Post not found")
 			return
@@ -225,7 +547,7 @@ func (h *ContentPostHandler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if errors.Is(err, repository.ErrForeignKey) {
-			response.BadRequest(w, "Invalid content type ID")
+			response.BadRequest(w, "Invalid content type ID, author ID, team ID or category ID")
 			return
 		}
 		response.InternalError(w, "Failed to update post")
@@ -237,11 +559,12 @@ func (h *ContentPostHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Delete post
-// @Description Delete a post
+// @Description Delete a post. Posts in a team's space can only be deleted by editors and managers of the team, and admins.
 // @Tags posts
 // @Param id path string true "Post ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Router /api/v1/posts/{id} [delete]
 func (h *ContentPostHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -251,6 +574,19 @@ func (h *ContentPostHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	post, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+	if post.TeamID != nil && !h.checkTeam(w, r, *post.TeamID) {
+		return
+	}
+
 	err = h.repo.Delete(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -272,6 +608,7 @@ func (h *ContentPostHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Post ID"
 // @Param body body models.AttachMediaRequest true "Media attachment data"
+// @Param environment query string false "Set to draft to edit a copy-on-write draft of a live post"
 // @Success 201 {object} response.APIResponse
 // @Failure 400 {object} response.APIResponse
 // @Router /api/v1/posts/{id}/media [post]
@@ -293,6 +630,11 @@ func (h *ContentPostHandler) AttachMedia(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	postID, ok := h.writablePostID(w, r, postID)
+	if !ok {
+		return
+	}
+
 	postMedia, err := h.repo.AttachMedia(r.Context(), postID, &req)
 	if err != nil {
 		if errors.Is(err, repository.ErrDuplicate) {
@@ -316,6 +658,7 @@ func (h *ContentPostHandler) AttachMedia(w http.ResponseWriter, r *http.Request)
 // @Tags posts
 // @Param id path string true "Post ID"
 // @Param mediaId path string true "Media ID"
+// @Param environment query string false "Set to draft to edit a copy-on-write draft of a live post"
 // @Success 204 "No Content"
 // @Failure 400 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
@@ -333,6 +676,11 @@ func (h *ContentPostHandler) DetachMedia(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	postID, ok := h.writablePostID(w, r, postID)
+	if !ok {
+		return
+	}
+
 	err = h.repo.DetachMedia(r.Context(), postID, mediaID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -345,3 +693,236 @@ func (h *ContentPostHandler) DetachMedia(w http.ResponseWriter, r *http.Request)
 
 	response.NoContent(w)
 }
+
+// Promote godoc
+// @Summary Promote draft post to live
+// @Description Publish a draft post to the live environment. Draft copies replace the live post they were forked from.
+// @Tags posts
+// @Produce json
+// @Param id path string true "Draft Post ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/promote [post]
+func (h *ContentPostHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	post, err := h.posts.PromoteDraft(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrApprovalRequired) {
+			response.ValidationError(w, map[string]string{"status": "The draft needs an approved approval request before it is published"})
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Draft post not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "A live post with this slug already exists")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to promote post", err)
+		return
+	}
+
+	response.OK(w, post)
+}
+
+// validateUpdate checks the post as it will look after req against its
+// (possibly new) content type's traits, that only admins change its author
+// and that the authenticated user may edit in its current team and any team
+// it moves to, writing the error response on failure
+func (h *ContentPostHandler) validateUpdate(w http.ResponseWriter, r *http.Request, id uuid.UUID, req *models.UpdatePostRequest) bool {
+	current, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return false
+		}
+		response.InternalError(w, "Failed to get post")
+		return false
+	}
+	if req.AuthorID != nil && *req.AuthorID != current.AuthorID {
+		if user := auth.UserFrom(r.Context()); user == nil || user.Role < models.RoleAdmin {
+			response.Forbidden(w, "Only admins can change a post's author")
+			return false
+		}
+	}
+
+	ct := current.ContentType
+	if req.ContentTypeID != nil && *req.ContentTypeID != current.ContentTypeID {
+		ct, err = h.contentTypeRepo.GetByID(r.Context(), *req.ContentTypeID)
+		if errors.Is(err, repository.ErrNotFound) {
+			response.BadRequest(w, "Invalid content type ID")
+			return false
+		}
+		if err != nil {
+			response.InternalError(w, "Failed to get content type")
+			return false
+		}
+	}
+
+	validationErrors := make(map[string]string)
+	validatePostTraits(ct, applyPostUpdate(*current, req), validationErrors)
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return false
+	}
+	if current.TeamID != nil && !h.checkTeam(w, r, *current.TeamID) {
+		return false
+	}
+	if req.TeamID != nil && *req.TeamID != uuid.Nil && !h.checkTeam(w, r, *req.TeamID) {
+		return false
+	}
+	return true
+}
+
+// checkTeam checks that the authenticated user may edit content in the
+// team's space, which keeps editors to their own teams' spaces. It writes a
+// 403 when not and reports whether the request may go on.
+func (h *ContentPostHandler) checkTeam(w http.ResponseWriter, r *http.Request, teamID uuid.UUID) bool {
+	user := auth.UserFrom(r.Context())
+	if user == nil {
+		response.Forbidden(w, "Only editors and managers of this team can edit its content")
+		return false
+	}
+	ok, err := h.teamRepo.CanEdit(r.Context(), teamID, user.ID)
+	if err != nil {
+		response.InternalError(w, "Failed to check team membership")
+		return false
+	}
+	if !ok {
+		response.Forbidden(w, "Only editors and managers of this team can edit its content")
+		return false
+	}
+	return true
+}
+
+// writablePostID resolves the post a write should apply to. With
+// ?environment=draft, writes to a live post go to its draft copy, which is
+// created on first write.
+func (h *ContentPostHandler) writablePostID(w http.ResponseWriter, r *http.Request, id uuid.UUID) (uuid.UUID, bool) {
+	if parseEnvironment(r) != models.EnvironmentDraft {
+		return id, true
+	}
+
+	draftID, err := h.repo.ForkToDraft(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return uuid.Nil, false
+		}
+		response.InternalErrorWithErr(w, "Failed to create draft copy", err)
+		return uuid.Nil, false
+	}
+
+	return draftID, true
+}
+
+// parseEnvironment reads the environment query parameter, defaulting to live
+func parseEnvironment(r *http.Request) string {
+	if env := r.URL.Query().Get("environment"); models.ValidEnvironment(env) {
+		return env
+	}
+	return models.EnvironmentLive
+}
+
+// parsePostFilter extracts post filter options from the query string
+// publishedUnlessEditor limits filter to published posts for callers below
+// editor. Aggregates and facets summarise metadata, which redaction hides
+// from them on unpublished posts.
+func publishedUnlessEditor(r *http.Request, filter models.PostFilter) models.PostFilter {
+	if user := auth.UserFrom(r.Context()); user == nil || user.Role < models.RoleEditor {
+		published := models.PostStatusPublished
+		filter.Status = &published
+	}
+	return filter
+}
+
+func parsePostFilter(r *http.Request) models.PostFilter {
+	filter := models.PostFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+		Environment:      parseEnvironment(r),
+	}
+
+	if ctID := r.URL.Query().Get("content_type_id"); ctID != "" {
+		if id, err := uuid.Parse(ctID); err == nil {
+			filter.ContentTypeID = &id
+		}
+	}
+
+	filter.ContentTypeSlug = r.URL.Query().Get("content_type")
+
+	if authorID := r.URL.Query().Get("author_id"); authorID != "" {
+		if id, err := uuid.Parse(authorID); err == nil {
+			filter.AuthorID = &id
+		}
+	}
+
+	if teamID := r.URL.Query().Get("team_id"); teamID != "" {
+		if id, err := uuid.Parse(teamID); err == nil {
+			filter.TeamID = &id
+		}
+	}
+
+	if categoryID := r.URL.Query().Get("category_id"); categoryID != "" {
+		if id, err := uuid.Parse(categoryID); err == nil {
+			filter.CategoryID = &id
+		}
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if status, ok := models.ParseEnum[models.PostStatus](statusStr); ok {
+			filter.Status = &status
+		}
+	}
+
+	if includeStats := getBoolParam(r, "include_view_stats"); includeStats != nil {
+		filter.IncludeViewStats = *includeStats
+	}
+
+	filter.Meta = parseMetaFilters(r)
+	filter.Near, filter.RadiusKm = parseNear(r)
+	filter.Expired = getBoolParam(r, "expired")
+
+	return filter
+}
+
+// metaParam matches meta[field] and meta[field][op] query keys
+var metaParam = regexp.MustCompile(`^meta\[([A-Za-z0-9_]+)\](?:\[(eq|lt|lte|gt|gte)\])?$`)
+
+// parseMetaFilters collects metadata filters such as meta[color]=red or
+// meta[price][lte]=100, ignoring keys that do not match
+func parseMetaFilters(r *http.Request) []models.MetaFilter {
+	var filters []models.MetaFilter
+	for key, values := range r.URL.Query() {
+		m := metaParam.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+		op := m[2]
+		if op == "" {
+			op = models.MetaOpEq
+		}
+		for _, value := range values {
+			filters = append(filters, models.MetaFilter{Field: m[1], Op: op, Value: value})
+		}
+	}
+
+	// Stable argument order keeps generated queries identical between requests
+	sort.Slice(filters, func(i, j int) bool {
+		if filters[i].Field != filters[j].Field {
+			return filters[i].Field < filters[j].Field
+		}
+		if filters[i].Op != filters[j].Op {
+			return filters[i].Op < filters[j].Op
+		}
+		return filters[i].Value < filters[j].Value
+	})
+	return filters
+}
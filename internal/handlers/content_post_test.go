@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// postRouter mounts the ContentPostHandler read and delete routes the way
+// router.New does. The other dependencies are left nil, as none of these
+// paths use them.
+func postRouter(repo PostRepository) (*ContentPostHandler, http.Handler) {
+	h := NewContentPostHandler(repo, nil, nil, nil, nil, nil, nil)
+	r := chi.NewRouter()
+	r.Route("/posts", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Get("/aggregate", h.Aggregate)
+		r.Get("/slug/{slug}", h.GetBySlug)
+		r.Get("/{id}", h.Get)
+		r.Delete("/{id}", h.Delete)
+	})
+	return h, r
+}
+
+// testPosts returns n published live posts, the first one newest, whose
+// color metadata alternates between red and blue
+func testPosts(n int) []models.ContentPost {
+	now := time.Now()
+	posts := make([]models.ContentPost, n)
+	for i := range posts {
+		color := "red"
+		if i%2 == 1 {
+			color = "blue"
+		}
+		posts[i] = models.ContentPost{
+			ID:          uuid.New(),
+			Title:       fmt.Sprintf("Post %d", i),
+			Slug:        fmt.Sprintf("post-%d", i),
+			Metadata:    json.RawMessage(fmt.Sprintf(`{"color":%q}`, color)),
+			Status:      models.PostStatusPublished,
+			Environment: models.EnvironmentLive,
+			CreatedAt:   now.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return posts
+}
+
+func TestContentPostHandlerListPaginates(t *testing.T) {
+	posts := testPosts(5)
+	_, router := postRouter(newPostRepository(posts...))
+
+	status, resp := serve(t, router, http.MethodGet, "/posts?page=2&page_size=2", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	var got []models.ContentPost
+	resp.decode(t, &got)
+	if len(got) != 2 || got[0].ID != posts[2].ID || got[1].ID != posts[3].ID {
+		t.Errorf("page 2 = %+v, want posts 2 and 3", got)
+	}
+	if m := resp.Meta; m == nil || m.Page != 2 || m.PageSize != 2 || m.Total != 5 || m.TotalPages != 3 {
+		t.Errorf("meta = %+v, want page 2 of 3 with 5 in total", m)
+	}
+}
+
+func TestContentPostHandlerListFacets(t *testing.T) {
+	repo := newPostRepository(testPosts(3)...)
+	h, router := postRouter(repo)
+
+	status, resp := serve(t, router, http.MethodGet, "/posts?facets=meta.color", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if resp.Meta == nil || len(resp.Meta.Facets) != 1 {
+		t.Fatalf("meta = %+v, want one facet", resp.Meta)
+	}
+	facet := resp.Meta.Facets[0]
+	want := []models.FacetValue{{Value: "blue", Count: 1}, {Value: "red", Count: 2}}
+	if facet.Field != "meta.color" || fmt.Sprint(facet.Values) != fmt.Sprint(want) {
+		t.Errorf("facet = %+v, want meta.color with %v", facet, want)
+	}
+
+	// Pagination doesn't change which posts match, so the cached facet is
+	// reused until a post changes
+	serve(t, router, http.MethodGet, "/posts?facets=meta.color&page=2", "")
+	if calls := len(repo.FacetCalls()); calls != 1 {
+		t.Errorf("facet computed %d times, want 1", calls)
+	}
+	h.InvalidateFacets(context.Background(), events.ContentChanged{Change: "post.updated"})
+	serve(t, router, http.MethodGet, "/posts?facets=meta.color", "")
+	if calls := len(repo.FacetCalls()); calls != 2 {
+		t.Errorf("facet computed %d times after invalidation, want 2", calls)
+	}
+}
+
+func TestContentPostHandlerListRejectsInvalidFacets(t *testing.T) {
+	_, router := postRouter(newPostRepository())
+
+	for _, facets := range []string{"title", "meta.a-b", "tags,meta.a,meta.b,meta.c,meta.d,meta.e"} {
+		status, resp := serve(t, router, http.MethodGet, "/posts?facets="+facets, "")
+		if status != http.StatusUnprocessableEntity {
+			t.Errorf("facets=%s: status = %d, want 422", facets, status)
+			continue
+		}
+		if resp.Error == nil || resp.Error.Details["facets"] == "" {
+			t.Errorf("facets=%s: error = %+v, want a facets detail", facets, resp.Error)
+		}
+	}
+}
+
+func TestContentPostHandlerGet(t *testing.T) {
+	posts := testPosts(1)
+	_, router := postRouter(newPostRepository(posts...))
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/posts/" + posts[0].ID.String(), http.StatusOK},
+		{"/posts/" + uuid.NewString(), http.StatusNotFound},
+		{"/posts/not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		status, resp := serve(t, router, http.MethodGet, tt.target, "")
+		if status != tt.status {
+			t.Errorf("GET %s = %d, want %d", tt.target, status, tt.status)
+			continue
+		}
+		if status == http.StatusOK {
+			var got models.ContentPost
+			resp.decode(t, &got)
+			if got.ID != posts[0].ID || got.Title != posts[0].Title {
+				t.Errorf("GET %s = %+v, want %+v", tt.target, got, posts[0])
+			}
+		}
+	}
+}
+
+func TestContentPostHandlerGetBySlug(t *testing.T) {
+	// A draft, so reading it records no view
+	post := testPosts(1)[0]
+	post.Environment = models.EnvironmentDraft
+	_, router := postRouter(newPostRepository(post))
+
+	status, resp := serve(t, router, http.MethodGet, "/posts/slug/post-0?environment=draft", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	var got models.ContentPost
+	resp.decode(t, &got)
+	if got.ID != post.ID {
+		t.Errorf("got post %s, want %s", got.ID, post.ID)
+	}
+
+	if status, _ := serve(t, router, http.MethodGet, "/posts/slug/missing", ""); status != http.StatusNotFound {
+		t.Errorf("missing slug = %d, want 404", status)
+	}
+}
+
+func TestContentPostHandlerAggregate(t *testing.T) {
+	_, router := postRouter(newPostRepository(testPosts(3)...))
+
+	status, resp := serve(t, router, http.MethodGet, "/posts/aggregate", "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	var buckets []models.PostAggregateBucket
+	resp.decode(t, &buckets)
+	if len(buckets) != 1 || buckets[0].Count != 3 {
+		t.Errorf("buckets = %+v, want one bucket of 3", buckets)
+	}
+
+	tests := []struct {
+		query, field string
+	}{
+		{"group_by=color", "group_by"},
+		{"group_by=meta.a-b", "group_by"},
+		{"metric=median:meta.price", "metric"},
+		{"metric=sum:price", "metric"},
+	}
+	for _, tt := range tests {
+		status, resp := serve(t, router, http.MethodGet, "/posts/aggregate?"+tt.query, "")
+		if status != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want 422", tt.query, status)
+			continue
+		}
+		if resp.Error == nil || resp.Error.Details[tt.field] == "" {
+			t.Errorf("%s: error = %+v, want a %s detail", tt.query, resp.Error, tt.field)
+		}
+	}
+}
+
+// asUser serves handler with user signed in
+func asUser(user *models.User, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), &auth.Identity{User: user})))
+	})
+}
+
+func TestContentPostHandlerSummariesHideUnpublished(t *testing.T) {
+	posts := testPosts(3)
+	posts[0].Status = models.PostStatusDraft
+	_, router := postRouter(newPostRepository(posts...))
+	editor := asUser(&models.User{ID: uuid.New(), Role: models.RoleEditor}, router)
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		query   string
+		count   int64
+	}{
+		{"anonymous", router, "", 2},
+		{"anonymous asking for drafts", router, "?status=1", 2},
+		{"editor", editor, "", 3},
+		{"editor asking for drafts", editor, "?status=1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp := serve(t, tt.handler, http.MethodGet, "/posts/aggregate"+tt.query, "")
+			var buckets []models.PostAggregateBucket
+			resp.decode(t, &buckets)
+			if len(buckets) != 1 || buckets[0].Count != tt.count {
+				t.Errorf("aggregate = %+v, want one bucket of %d", buckets, tt.count)
+			}
+
+			sep := "?"
+			if tt.query != "" {
+				sep = tt.query + "&"
+			}
+			_, resp = serve(t, tt.handler, http.MethodGet, "/posts"+sep+"facets=meta.color", "")
+			var total int64
+			if resp.Meta != nil && len(resp.Meta.Facets) == 1 {
+				for _, v := range resp.Meta.Facets[0].Values {
+					total += v.Count
+				}
+			}
+			if total != tt.count {
+				t.Errorf("facet counts add up to %d, want %d", total, tt.count)
+			}
+		})
+	}
+}
+
+func TestContentPostHandlerDelete(t *testing.T) {
+	posts := testPosts(2)
+	repo := newPostRepository(posts...)
+	_, router := postRouter(repo)
+
+	target := "/posts/" + posts[0].ID.String()
+	if status, _ := serve(t, router, http.MethodDelete, target, ""); status != http.StatusNoContent {
+		t.Errorf("first delete = %d, want 204", status)
+	}
+	if status, _ := serve(t, router, http.MethodDelete, target, ""); status != http.StatusNotFound {
+		t.Errorf("second delete = %d, want 404", status)
+	}
+	if calls := repo.DeleteCalls(); len(calls) != 1 || calls[0].ID != posts[0].ID {
+		t.Errorf("delete calls = %+v, want one for %s", calls, posts[0].ID)
+	}
+	if _, err := repo.GetByID(context.Background(), posts[1].ID); err != nil {
+		t.Errorf("other post: %v, want it kept", err)
+	}
+}
+
+func BenchmarkContentPostHandlerList(b *testing.B) {
+	_, router := postRouter(newPostStore(testPosts(100)...))
+	req := httptest.NewRequest(http.MethodGet, "/posts?page_size=20", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkContentPostHandlerGet(b *testing.B) {
+	posts := testPosts(1)
+	_, router := postRouter(newPostStore(posts...))
+	req := httptest.NewRequest(http.MethodGet, "/posts/"+posts[0].ID.String(), nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkContentPostHandlerGetBySlug(b *testing.B) {
+	// Drafts, so reading them records no views
+	posts := testPosts(100)
+	for i := range posts {
+		posts[i].Environment = models.EnvironmentDraft
+	}
+	_, router := postRouter(newPostStore(posts...))
+	req := httptest.NewRequest(http.MethodGet, "/posts/slug/post-50?environment=draft", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkContentPostHandlerSearch(b *testing.B) {
+	_, router := postRouter(newPostStore(testPosts(100)...))
+	req := httptest.NewRequest(http.MethodGet, "/posts?search=Post+5&page_size=20", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkParsePostFilter(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/posts?search=golang+tips&status=2&author_id="+uuid.NewString()+
+		"&meta[color]=red&meta[price][lte]=100&sort_by=title&sort_dir=asc&page=3", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parsePostFilter(req)
+	}
+}
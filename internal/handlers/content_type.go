@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/keeps-dev/go-cms-template/internal/models"
@@ -133,6 +134,18 @@ func (h *ContentTypeHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	validationErrors := make(map[string]string)
+	validateTraitNames(req.Traits, validationErrors)
+	validateIndexedFields(req.IndexedFields, validationErrors)
+	if req.SlugPattern != "" {
+		req.SlugPattern = strings.Trim(strings.TrimSpace(req.SlugPattern), "/")
+		validateSlugPattern(req.SlugPattern, validationErrors)
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
 	contentType, err := h.repo.Create(r.Context(), &req)
 	if err != nil {
 		if errors.Is(err, repository.ErrDuplicate) {
@@ -172,6 +185,23 @@ func (h *ContentTypeHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	validationErrors := make(map[string]string)
+	if req.Traits != nil {
+		validateTraitNames(*req.Traits, validationErrors)
+	}
+	if req.IndexedFields != nil {
+		validateIndexedFields(*req.IndexedFields, validationErrors)
+	}
+	if req.SlugPattern != nil {
+		pattern := strings.Trim(strings.TrimSpace(*req.SlugPattern), "/")
+		req.SlugPattern = &pattern
+		validateSlugPattern(pattern, validationErrors)
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
 	contentType, err := h.repo.Update(r.Context(), id, &req)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -222,3 +252,27 @@ func (h *ContentTypeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	response.NoContent(w)
 }
+
+func validateIndexedFields(fields []models.IndexedField, validationErrors map[string]string) {
+	seen := make(map[models.IndexedField]bool)
+	for _, f := range fields {
+		switch {
+		case !models.ValidMetaField(f.Field) || len(f.Field) > models.MaxIndexedFieldLength:
+			validationErrors["indexed_fields"] = "Fields must be top-level metadata names of at most 32 letters, digits and underscores"
+		case f.Type != models.IndexedFieldNumber && f.Type != models.IndexedFieldString:
+			validationErrors["indexed_fields"] = "Type of " + f.Field + " must be number or string"
+		case seen[f]:
+			validationErrors["indexed_fields"] = f.Field + " is declared twice"
+		default:
+			seen[f] = true
+			continue
+		}
+		return
+	}
+}
+
+func validateSlugPattern(pattern string, validationErrors map[string]string) {
+	if !models.ValidSlugPattern(pattern) {
+		validationErrors["slug_pattern"] = "Slug pattern must be a path ending in {slug}, of lowercase letters, digits, hyphens and the {year}, {month} and {type} tokens, not starting with a reserved path"
+	}
+}
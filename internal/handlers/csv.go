@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// csvFlushEvery controls how many rows are buffered before flushing to the client
+const csvFlushEvery = 100
+
+// csvExport streams CSV rows to the response, flushing periodically so large
+// exports don't have to be buffered in memory
+type csvExport struct {
+	w       http.ResponseWriter
+	writer  *csv.Writer
+	rows    int
+	flusher http.Flusher
+}
+
+// newCSVExport writes the download headers and the CSV header row
+func newCSVExport(w http.ResponseWriter, name string, header []string) (*csvExport, error) {
+	filename := fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	e := &csvExport{w: w, writer: csv.NewWriter(w)}
+	e.flusher, _ = w.(http.Flusher)
+
+	if err := e.writer.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Write appends a record, flushing every csvFlushEvery rows. Cells that a
+// spreadsheet would read as a formula are escaped in place.
+func (e *csvExport) Write(record []string) error {
+	for i, cell := range record {
+		record[i] = csvCell(cell)
+	}
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%csvFlushEvery == 0 {
+		e.Flush()
+	}
+	return e.writer.Error()
+}
+
+// Flush sends buffered rows to the client
+func (e *csvExport) Flush() {
+	e.writer.Flush()
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+}
+
+// Finish flushes the remaining rows and logs a failure that happened mid-stream,
+// since the status code has already been sent at that point
+func (e *csvExport) Finish(ctx context.Context, name string, err error) {
+	e.Flush()
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] CSV export %s failed after %d rows: %v", name, e.rows, err)
+	}
+}
+
+// csvCell prefixes cells starting with a formula trigger with a quote, so
+// spreadsheet apps show values such as =HYPERLINK(...) from user input as
+// text instead of evaluating them
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
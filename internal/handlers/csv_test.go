@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSVExportEscapesFormulas(t *testing.T) {
+	rec := httptest.NewRecorder()
+	export, err := newCSVExport(rec, "test", []string{"value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range []string{"=HYPERLINK(\"http://x\")", "+1", "-1", "@SUM(A1)", "\tx", "\rx", "plain", "a=b", ""} {
+		if err := export.Write([]string{cell}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	export.Finish(context.Background(), "test", nil)
+
+	want := "value\n" +
+		"\"'=HYPERLINK(\"\"http://x\"\")\"\n" +
+		"'+1\n'-1\n'@SUM(A1)\n'\tx\n\"'\rx\"\nplain\na=b\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("export = %q, want %q", got, want)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	for in, want := range map[string]string{"=1+1": "'=1+1", "-5": "'-5", "5-": "5-", "": ""} {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Dashboard defaults: 30 days of views and the top 10 tags
+const (
+	defaultDashboardDays = 30
+	maxDashboardDays     = 366
+	defaultDashboardTags = 10
+	maxDashboardTags     = 100
+)
+
+type DashboardHandler struct {
+	repo *repository.DashboardRepository
+	// staleAfter is the age from which aggregates count as stale; zero
+	// flags only aggregates that were never refreshed
+	staleAfter time.Duration
+}
+
+// NewDashboardHandler creates a dashboard handler for aggregates refreshed
+// every refreshInterval; they are reported stale once two refreshes were
+// missed
+func NewDashboardHandler(repo *repository.DashboardRepository, refreshInterval time.Duration) *DashboardHandler {
+	return &DashboardHandler{repo: repo, staleAfter: 2 * refreshInterval}
+}
+
+// Get godoc
+// @Summary Get dashboard aggregates
+// @Description Live post counts by content type and status, views per day and the tags with the most published posts, read from periodically refreshed materialized views. meta.freshness tells when each was last refreshed and whether it is stale.
+// @Tags dashboard
+// @Produce json
+// @Param days query int false "Days of views, including today (1-366, default 30)"
+// @Param top_tags query int false "Number of top tags (1-100, default 10)"
+// @Success 200 {object} response.APIResponse{data=models.Dashboard}
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/dashboard [get]
+func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	days, topTags := defaultDashboardDays, defaultDashboardTags
+
+	validationErrors := make(map[string]string)
+	if value := q.Get("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxDashboardDays {
+			validationErrors["days"] = "Must be between 1 and 366"
+		} else {
+			days = n
+		}
+	}
+	if value := q.Get("top_tags"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxDashboardTags {
+			validationErrors["top_tags"] = "Must be between 1 and 100"
+		} else {
+			topTags = n
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	ctx := r.Context()
+	var (
+		dashboard models.Dashboard
+		err       error
+	)
+	if dashboard.PostCounts, err = h.repo.PostCounts(ctx); err != nil {
+		response.InternalError(w, "Failed to get dashboard")
+		return
+	}
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	if dashboard.DailyViews, err = h.repo.DailyViews(ctx, from); err != nil {
+		response.InternalError(w, "Failed to get dashboard")
+		return
+	}
+	if dashboard.TopTags, err = h.repo.TopTags(ctx, topTags); err != nil {
+		response.InternalError(w, "Failed to get dashboard")
+		return
+	}
+
+	freshness, err := h.repo.Freshness(ctx)
+	if err != nil {
+		response.InternalError(w, "Failed to get dashboard")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, dashboard, &response.Meta{Freshness: h.withStaleness(freshness)})
+}
+
+// Refresh godoc
+// @Summary Refresh dashboard aggregates
+// @Description Recompute the dashboard's materialized views now instead of waiting for the next background refresh. Readers keep seeing the previous values until each view is refreshed.
+// @Tags dashboard
+// @Produce json
+// @Param view query string false "Refresh only this view (dashboard_post_counts, dashboard_daily_views or dashboard_tag_counts)"
+// @Success 200 {object} response.APIResponse{data=[]models.AggregateFreshness}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/dashboard/refresh [post]
+func (h *DashboardHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	views := repository.DashboardViews
+	if view := r.URL.Query().Get("view"); view != "" {
+		views = []string{view}
+	}
+
+	refreshed := make([]models.AggregateFreshness, 0, len(views))
+	for _, view := range views {
+		freshness, err := h.repo.Refresh(r.Context(), view)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				response.NotFound(w, "Unknown dashboard view")
+				return
+			}
+			response.InternalErrorWithErr(w, "Failed to refresh dashboard", err)
+			return
+		}
+		refreshed = append(refreshed, *freshness)
+	}
+
+	response.OK(w, h.withStaleness(refreshed))
+}
+
+// withStaleness fills in the age and stale flag of each aggregate
+func (h *DashboardHandler) withStaleness(freshness []models.AggregateFreshness) []models.AggregateFreshness {
+	now := time.Now()
+	for i := range freshness {
+		f := &freshness[i]
+		if f.RefreshedAt == nil {
+			f.Stale = true
+			continue
+		}
+		age := now.Sub(*f.RefreshedAt)
+		seconds := int64(age.Seconds())
+		f.AgeSeconds = &seconds
+		f.Stale = h.staleAfter > 0 && age > h.staleAfter
+	}
+	return freshness
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// DeliveryHandler serves published content in the shape of the delivery API
+type DeliveryHandler struct {
+	postRepo      *repository.ContentPostRepository
+	cacheHintRepo *repository.CacheHintRepository
+	serializer    *delivery.Serializer
+}
+
+func NewDeliveryHandler(postRepo *repository.ContentPostRepository, cacheHintRepo *repository.CacheHintRepository, serializer *delivery.Serializer) *DeliveryHandler {
+	return &DeliveryHandler{postRepo: postRepo, cacheHintRepo: cacheHintRepo, serializer: serializer}
+}
+
+// Post godoc
+// @Summary Get a published post
+// @Description A published post with its content type, author name, tags and media, for headless front ends (public endpoint). Media are resolved to URLs for the original and each variant; storage details are left out. Live posts are sent with Cache-Control and Surrogate-Key headers from their cache hints.
+// @Tags public
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param environment query string false "Content environment (live or draft), if the delivery token allows it"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/public/posts/{slug} [get]
+func (h *DeliveryHandler) Post(w http.ResponseWriter, r *http.Request) {
+	post, err := getPublishedPost(r, h.postRepo, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	delivery.SetCacheHeaders(w, r, h.cacheHintRepo, post, "")
+	response.OK(w, h.serializer.Post(post))
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type DeliveryTokenHandler struct {
+	repo            *repository.DeliveryTokenRepository
+	contentTypeRepo *repository.ContentTypeRepository
+}
+
+func NewDeliveryTokenHandler(repo *repository.DeliveryTokenRepository, contentTypeRepo *repository.ContentTypeRepository) *DeliveryTokenHandler {
+	return &DeliveryTokenHandler{repo: repo, contentTypeRepo: contentTypeRepo}
+}
+
+// List godoc
+// @Summary List delivery tokens
+// @Description Get read-only tokens for the public delivery API, newest first. Token secrets are never returned after creation.
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param is_active query bool false "Filter by active status"
+// @Param search query string false "Search in name and token prefix"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/delivery-tokens [get]
+func (h *DeliveryTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.DeliveryTokenFilter{
+		PaginationParams: parsePaginationParams(r),
+		IsActive:         getBoolParam(r, "is_active"),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	tokens, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list delivery tokens")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, tokens, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get delivery token by ID
+// @Description Get a single delivery token and its scope
+// @Tags admin
+// @Produce json
+// @Param id path string true "Delivery token ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/admin/delivery-tokens/{id} [get]
+func (h *DeliveryTokenHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid delivery token ID")
+		return
+	}
+
+	token, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Delivery token not found")
+			return
+		}
+		response.InternalError(w, "Failed to get delivery token")
+		return
+	}
+
+	response.OK(w, token)
+}
+
+// Create godoc
+// @Summary Create delivery token
+// @Description Create a read-only token for the public delivery API, optionally limited to content types (slugs), locales and environments. The token is only returned in this response.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param body body models.CreateDeliveryTokenRequest true "Delivery token data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/admin/delivery-tokens [post]
+func (h *DeliveryTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateDeliveryTokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	req.Locales = normalizeLocales(req.Locales)
+	if err := h.validateScope(r.Context(), req.ContentTypes, req.Locales, req.Environments, req.ExpiresAt, validationErrors); err != nil {
+		response.InternalError(w, "Failed to check content types")
+		return
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	token, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		response.InternalError(w, "Failed to create delivery token")
+		return
+	}
+
+	response.Created(w, token)
+}
+
+// Update godoc
+// @Summary Update delivery token
+// @Description Rename, rescope, deactivate or extend a delivery token. Scopes given replace the stored ones; an empty list allows all.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery token ID"
+// @Param body body models.UpdateDeliveryTokenRequest true "Delivery token data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/admin/delivery-tokens/{id} [put]
+func (h *DeliveryTokenHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid delivery token ID")
+		return
+	}
+
+	var req models.UpdateDeliveryTokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+		if name == "" {
+			validationErrors["name"] = "Name cannot be empty"
+		}
+	}
+	var contentTypes, locales, environments []string
+	if req.ContentTypes != nil {
+		contentTypes = *req.ContentTypes
+	}
+	if req.Locales != nil {
+		locales = normalizeLocales(*req.Locales)
+		req.Locales = &locales
+	}
+	if req.Environments != nil {
+		environments = *req.Environments
+	}
+	if err := h.validateScope(r.Context(), contentTypes, locales, environments, req.ExpiresAt, validationErrors); err != nil {
+		response.InternalError(w, "Failed to check content types")
+		return
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	token, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Delivery token not found")
+			return
+		}
+		response.InternalError(w, "Failed to update delivery token")
+		return
+	}
+
+	response.OK(w, token)
+}
+
+// Delete godoc
+// @Summary Delete delivery token
+// @Description Revoke a delivery token for good
+// @Tags admin
+// @Param id path string true "Delivery token ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/admin/delivery-tokens/{id} [delete]
+func (h *DeliveryTokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid delivery token ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Delivery token not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete delivery token")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// validateScope checks that content types exist and environments are known
+func (h *DeliveryTokenHandler) validateScope(ctx context.Context, contentTypes, locales, environments []string, expiresAt *time.Time, validationErrors map[string]string) error {
+	for _, slug := range contentTypes {
+		if _, err := h.contentTypeRepo.GetBySlug(ctx, slug); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				validationErrors["content_types"] = "Unknown content type: " + slug
+				break
+			}
+			return err
+		}
+	}
+	for _, locale := range locales {
+		if locale == "" {
+			validationErrors["locales"] = "Locales cannot be empty"
+			break
+		}
+	}
+	for _, env := range environments {
+		if !models.ValidEnvironment(env) {
+			validationErrors["environments"] = "Environments must be live or draft"
+			break
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		validationErrors["expires_at"] = "Expiry must be in the future"
+	}
+	return nil
+}
+
+// normalizeLocales normalizes locales to match the negotiated ones
+func normalizeLocales(locales []string) []string {
+	for i, tag := range locales {
+		locales[i] = locale.Normalize(tag)
+	}
+	return locales
+}
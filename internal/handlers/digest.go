@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// digestPreviewLimit is the number of posts listed per preview section
+const digestPreviewLimit = 10
+
+var digestSections = map[string]bool{
+	models.DigestSectionPublished: true,
+	models.DigestSectionDrafts:    true,
+	models.DigestSectionTopViewed: true,
+	models.DigestSectionContacts:  true,
+}
+
+type DigestHandler struct {
+	repo     *repository.DigestRepository
+	userRepo *repository.UserRepository
+}
+
+func NewDigestHandler(repo *repository.DigestRepository, userRepo *repository.UserRepository) *DigestHandler {
+	return &DigestHandler{repo: repo, userRepo: userRepo}
+}
+
+// subscriberID parses the user ID in the path. Users manage their own
+// subscription; admins can manage anyone's.
+func subscriberID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	user, ok := actingUser(w, r)
+	if !ok {
+		return uuid.Nil, false
+	}
+	if user.ID != id && user.Role < models.RoleAdmin {
+		response.Forbidden(w, "Only admins can manage other users' digest subscriptions")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// GetPreference godoc
+// @Summary Get digest subscription
+// @Description Get a user's editor digest subscription; users who never subscribed get frequency off
+// @Tags digests
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id}/digest [get]
+func (h *DigestHandler) GetPreference(w http.ResponseWriter, r *http.Request) {
+	id, ok := subscriberID(w, r)
+	if !ok {
+		return
+	}
+
+	pref, err := h.repo.GetPreference(r.Context(), id)
+	if err == nil {
+		response.OK(w, pref)
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		response.InternalError(w, "Failed to get digest subscription")
+		return
+	}
+
+	exists, err := h.userRepo.Exists(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to get digest subscription")
+		return
+	}
+	if !exists {
+		response.NotFound(w, "User not found")
+		return
+	}
+	response.OK(w, &models.DigestPreference{UserID: id, Frequency: models.DigestFrequencyOff, Sections: []string{}})
+}
+
+// UpdatePreference godoc
+// @Summary Update digest subscription
+// @Description Subscribe a user to the daily or weekly editor digest, choose its sections (all when empty) and locale, or turn it off. Only active editors and admins receive digests.
+// @Tags digests
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body models.UpdateDigestPreferenceRequest true "Digest subscription"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id}/digest [put]
+func (h *DigestHandler) UpdatePreference(w http.ResponseWriter, r *http.Request) {
+	id, ok := subscriberID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateDigestPreferenceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	switch req.Frequency {
+	case models.DigestFrequencyOff, models.DigestFrequencyDaily, models.DigestFrequencyWeekly:
+	default:
+		validationErrors["frequency"] = "Frequency must be off, daily or weekly"
+	}
+	for _, section := range req.Sections {
+		if !digestSections[section] {
+			validationErrors["sections"] = "Sections must be published, drafts, top_viewed or contacts"
+			break
+		}
+	}
+	if req.Locale != nil {
+		locale := strings.ToLower(strings.TrimSpace(*req.Locale))
+		switch {
+		case locale == "":
+			req.Locale = nil
+		case len(locale) > 20:
+			validationErrors["locale"] = "Locale must be at most 20 characters"
+		default:
+			req.Locale = &locale
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	exists, err := h.userRepo.Exists(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to update digest subscription")
+		return
+	}
+	if !exists {
+		response.NotFound(w, "User not found")
+		return
+	}
+
+	pref, err := h.repo.SavePreference(r.Context(), id, &req)
+	if err != nil {
+		response.InternalError(w, "Failed to update digest subscription")
+		return
+	}
+
+	response.OK(w, pref)
+}
+
+// Preview godoc
+// @Summary Preview the editor digest
+// @Description Get the activity a daily or weekly digest sent now would summarise: posts published, drafts pending review, top viewed posts and new contact submissions
+// @Tags reports
+// @Produce json
+// @Param frequency query string false "daily or weekly (default weekly)"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/reports/digest [get]
+func (h *DigestHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	var from time.Time
+	switch r.URL.Query().Get("frequency") {
+	case models.DigestFrequencyDaily:
+		from = now.AddDate(0, 0, -1)
+	case "", models.DigestFrequencyWeekly:
+		from = now.AddDate(0, 0, -7)
+	default:
+		response.ValidationError(w, map[string]string{"frequency": "Frequency must be daily or weekly"})
+		return
+	}
+
+	summary, err := h.repo.Summary(r.Context(), from, now, digestPreviewLimit)
+	if err != nil {
+		response.InternalError(w, "Failed to build digest")
+		return
+	}
+
+	response.OK(w, summary)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+	// maxSuggestionQuery caps the query, which is matched on every keystroke
+	maxSuggestionQuery = 100
+)
+
+// EditorHandler serves helpers for rich text editors
+type EditorHandler struct {
+	repo *repository.SuggestionRepository
+}
+
+func NewEditorHandler(repo *repository.SuggestionRepository) *EditorHandler {
+	return &EditorHandler{repo: repo}
+}
+
+// Suggest godoc
+// @Summary Suggest links and mentions
+// @Description Get internal link and @mention candidates for autocompletion in the editor: live posts (not archived), tags and active authors matching the query, ranked by match quality and recency. insert holds the text to insert: a post's site path, a tag's slug or an author's @email mention.
+// @Tags editor
+// @Produce json
+// @Param q query string false "Text typed so far; empty ranks by recency alone"
+// @Param types query string false "Comma-separated types to include: post, tag, author (default all)"
+// @Param limit query int false "Maximum number of suggestions (default 10, max 50)"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/editor/suggest [get]
+func (h *EditorHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.SuggestionFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+		Types: models.SuggestionTypes,
+		Limit: defaultSuggestionLimit,
+	}
+
+	validationErrors := make(map[string]string)
+	if len(filter.Query) > maxSuggestionQuery {
+		validationErrors["q"] = "Query must be at most " + strconv.Itoa(maxSuggestionQuery) + " characters"
+	}
+	if types := splitList(query.Get("types")); len(types) > 0 {
+		for _, t := range types {
+			if !slices.Contains(models.SuggestionTypes, t) {
+				validationErrors["types"] = "Types must be post, tag or author"
+			}
+		}
+		filter.Types = types
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxSuggestionLimit {
+			validationErrors["limit"] = "Limit must be between 1 and " + strconv.Itoa(maxSuggestionLimit)
+		}
+		filter.Limit = n
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	suggestions, err := h.repo.Suggest(r.Context(), filter)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to get suggestions", err)
+		return
+	}
+
+	response.OK(w, suggestions)
+}
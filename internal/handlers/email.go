@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// trackingPixel is a transparent 1x1 GIF
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type EmailHandler struct {
+	repo   *repository.EmailRepository
+	mailer *mailer.Mailer
+}
+
+func NewEmailHandler(repo *repository.EmailRepository, mail *mailer.Mailer) *EmailHandler {
+	return &EmailHandler{repo: repo, mailer: mail}
+}
+
+// List godoc
+// @Summary List queued emails
+// @Description Get recent sends and failures from the outbound email queue
+// @Tags emails
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param status query int false "Filter by status (1=pending, 2=sending, 3=sent, 4=failed)"
+// @Param to query string false "Filter by recipient"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/emails [get]
+func (h *EmailHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.EmailFilter{
+		PaginationParams: parsePaginationParams(r),
+		To:               r.URL.Query().Get("to"),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if s, err := strconv.Atoi(statusStr); err == nil {
+			status := models.EmailStatus(s)
+			filter.Status = &status
+		}
+	}
+
+	emails, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list emails")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, emails, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get queued email by ID
+// @Description Get a single queued email including its rendered bodies and last error
+// @Tags emails
+// @Produce json
+// @Param id path string true "Email ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/emails/{id} [get]
+func (h *EmailHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid email ID")
+		return
+	}
+
+	email, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Email not found")
+			return
+		}
+		response.InternalError(w, "Failed to get email")
+		return
+	}
+
+	response.OK(w, email)
+}
+
+// Send godoc
+// @Summary Queue an email
+// @Description Render a named template for a locale and queue it for delivery
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param body body models.SendEmailRequest true "Email data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/emails [post]
+func (h *EmailHandler) Send(w http.ResponseWriter, r *http.Request) {
+	var req models.SendEmailRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	// Validate required fields
+	validationErrors := make(map[string]string)
+	if _, err := mail.ParseAddress(req.To); err != nil {
+		validationErrors["to"] = "A valid recipient address is required"
+	}
+	if req.Template == "" {
+		validationErrors["template"] = "Template is required"
+	}
+
+	var data map[string]interface{}
+	if len(req.Data) > 0 {
+		if err := json.Unmarshal(req.Data, &data); err != nil {
+			validationErrors["data"] = "Data must be a JSON object"
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	email, err := h.mailer.Enqueue(r.Context(), req.To, req.Template, req.Locale, data)
+	if err != nil {
+		if errors.Is(err, mailer.ErrTemplateNotFound) {
+			response.ValidationError(w, map[string]string{"template": "Template not found"})
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to queue email", err)
+		return
+	}
+
+	response.Created(w, email)
+}
+
+// Retry godoc
+// @Summary Retry a failed email
+// @Description Put a permanently failed email back in the queue
+// @Tags emails
+// @Produce json
+// @Param id path string true "Email ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/emails/{id}/retry [post]
+func (h *EmailHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid email ID")
+		return
+	}
+
+	email, err := h.repo.Retry(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Failed email not found")
+			return
+		}
+		response.InternalError(w, "Failed to retry email")
+		return
+	}
+
+	response.OK(w, email)
+}
+
+// TrackOpen godoc
+// @Summary Email open tracking pixel
+// @Description Record that an email was opened and return a 1x1 transparent GIF (public endpoint)
+// @Tags emails
+// @Produce image/gif
+// @Param id path string true "Email ID"
+// @Success 200 {file} file
+// @Router /api/v1/emails/{id}/open.gif [get]
+func (h *EmailHandler) TrackOpen(w http.ResponseWriter, r *http.Request) {
+	// Always serve the pixel so mail clients never show a broken image
+	if id, err := parseUUID(chi.URLParam(r, "id")); err == nil {
+		if err := h.repo.MarkOpened(r.Context(), id); err != nil {
+			reqctx.Logf(r.Context(), "[ERROR] %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store, max-age=0")
+	w.WriteHeader(http.StatusOK)
+	w.Write(trackingPixel)
+}
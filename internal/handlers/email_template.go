@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type EmailTemplateHandler struct {
+	repo *repository.EmailTemplateRepository
+}
+
+func NewEmailTemplateHandler(repo *repository.EmailTemplateRepository) *EmailTemplateHandler {
+	return &EmailTemplateHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List email templates
+// @Description Get all stored email templates and their locale variants
+// @Tags email-templates
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param name query string false "Filter by template name"
+// @Param locale query string false "Filter by locale"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/email-templates [get]
+func (h *EmailTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.EmailTemplateFilter{
+		PaginationParams: parsePaginationParams(r),
+		Name:             r.URL.Query().Get("name"),
+		Locale:           strings.ToLower(r.URL.Query().Get("locale")),
+	}
+
+	templates, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list email templates")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, templates, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get email template by ID
+// @Description Get a single email template variant by its ID
+// @Tags email-templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/email-templates/{id} [get]
+func (h *EmailTemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	t, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Email template not found")
+			return
+		}
+		response.InternalError(w, "Failed to get email template")
+		return
+	}
+
+	response.OK(w, t)
+}
+
+// Create godoc
+// @Summary Create email template
+// @Description Create a locale variant of a named template. Subject and bodies use Go template syntax.
+// @Tags email-templates
+// @Accept json
+// @Produce json
+// @Param body body models.CreateEmailTemplateRequest true "Template data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/email-templates [post]
+func (h *EmailTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateEmailTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Locale = strings.ToLower(strings.TrimSpace(req.Locale))
+
+	// Validate required fields
+	validationErrors := make(map[string]string)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if req.Locale == "" {
+		validationErrors["locale"] = "Locale is required"
+	}
+	if req.Subject == "" {
+		validationErrors["subject"] = "Subject is required"
+	}
+	if req.BodyHTML == nil && req.BodyText == nil {
+		validationErrors["body_text"] = "At least one of body_html or body_text is required"
+	}
+	if err := mailer.Validate(&req.Subject, req.BodyHTML, req.BodyText); err != nil {
+		validationErrors["template"] = err.Error()
+	}
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	t, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Template already exists for this locale")
+			return
+		}
+		response.InternalError(w, "Failed to create email template")
+		return
+	}
+
+	response.Created(w, t)
+}
+
+// Update godoc
+// @Summary Update email template
+// @Description Update the subject or bodies of an email template variant
+// @Tags email-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param body body models.UpdateEmailTemplateRequest true "Template data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/email-templates/{id} [put]
+func (h *EmailTemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	var req models.UpdateEmailTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := mailer.Validate(req.Subject, req.BodyHTML, req.BodyText); err != nil {
+		response.ValidationError(w, map[string]string{"template": err.Error()})
+		return
+	}
+
+	t, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Email template not found")
+			return
+		}
+		response.InternalError(w, "Failed to update email template")
+		return
+	}
+
+	response.OK(w, t)
+}
+
+// Delete godoc
+// @Summary Delete email template
+// @Description Delete an email template variant
+// @Tags email-templates
+// @Param id path string true "Template ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/email-templates/{id} [delete]
+func (h *EmailTemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Email template not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete email template")
+		return
+	}
+
+	response.NoContent(w)
+}
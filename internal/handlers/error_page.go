@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Setting keys used to brand public error responses
+const (
+	SettingErrorNotFoundMessage    = "error_pages.not_found_message"
+	SettingErrorServerErrorMessage = "error_pages.server_error_message"
+	SettingErrorSupportURL         = "error_pages.support_url"
+	SettingErrorHTMLEnabled        = "error_pages.html_enabled"
+	SettingErrorShowReference      = "error_pages.show_reference"
+)
+
+type ErrorPageHandler struct {
+	settingRepo *repository.SettingRepository
+}
+
+func NewErrorPageHandler(settingRepo *repository.SettingRepository) *ErrorPageHandler {
+	return &ErrorPageHandler{settingRepo: settingRepo}
+}
+
+// NotFound renders the branded 404 response for unknown routes
+func (h *ErrorPageHandler) NotFound(w http.ResponseWriter, r *http.Request) {
+	page := h.loadPage(r, SettingErrorNotFoundMessage, false)
+	response.WriteErrorPage(w, r, http.StatusNotFound, "NOT_FOUND", "Endpoint not found", page)
+}
+
+// InternalError renders the branded 500 response, used after a recovered panic.
+// With error_pages.show_reference the request ID is included as a reference
+// the user can quote to support.
+func (h *ErrorPageHandler) InternalError(w http.ResponseWriter, r *http.Request) {
+	page := h.loadPage(r, SettingErrorServerErrorMessage, true)
+	response.WriteErrorPage(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error", page)
+}
+
+// loadPage reads the error page settings, falling back to the defaults when
+// settings are missing or the database is unavailable. withReference adds
+// the request ID as the reference when error_pages.show_reference is on.
+func (h *ErrorPageHandler) loadPage(r *http.Request, messageKey string, withReference bool) response.ErrorPage {
+	settings, err := h.settingRepo.GetMultiple(r.Context(), []string{
+		messageKey, SettingErrorSupportURL, SettingErrorHTMLEnabled, SettingErrorShowReference,
+	})
+	if err != nil {
+		return response.ErrorPage{}
+	}
+
+	page := response.ErrorPage{
+		Message:    settings[messageKey],
+		SupportURL: settings[SettingErrorSupportURL],
+		HTML:       settingEnabled(settings[SettingErrorHTMLEnabled]),
+	}
+	if withReference && settingEnabled(settings[SettingErrorShowReference]) {
+		page.Reference = reqctx.From(r.Context()).RequestID
+	}
+	return page
+}
+
+func settingEnabled(value string) bool {
+	return value == "true" || value == "1"
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/calendar"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Upcoming event window defaults and bounds
+const (
+	defaultUpcomingDays  = 30
+	maxUpcomingDays      = 366
+	defaultUpcomingLimit = 50
+	maxUpcomingLimit     = 500
+)
+
+type EventHandler struct {
+	postRepo   *repository.ContentPostRepository
+	serializer *delivery.Serializer
+	urls       *permalink.Builder
+}
+
+func NewEventHandler(postRepo *repository.ContentPostRepository, serializer *delivery.Serializer, urls *permalink.Builder) *EventHandler {
+	return &EventHandler{postRepo: postRepo, serializer: serializer, urls: urls}
+}
+
+// Upcoming godoc
+// @Summary Upcoming events
+// @Description Occurrences of published event posts (posts with starts_at) in the next days, with recurring events expanded, soonest first
+// @Tags public
+// @Produce json
+// @Param content_type query string false "Filter by content type slug"
+// @Param tag query string false "Filter by tag slug"
+// @Param from query string false "Start of the window (RFC 3339, default now)"
+// @Param days query int false "Length of the window in days (default 30, max 366)"
+// @Param limit query int false "Maximum occurrences (default 50, max 500)"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/public/events/upcoming [get]
+func (h *EventHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from := time.Now().UTC()
+	if fromStr := q.Get("from"); fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid from (must be RFC 3339)")
+			return
+		}
+		from = t.UTC()
+	}
+	days := defaultUpcomingDays
+	if d := getIntParam(r, "days"); d != nil && *d > 0 {
+		days = min(*d, maxUpcomingDays)
+	}
+	limit := defaultUpcomingLimit
+	if l := getIntParam(r, "limit"); l != nil && *l > 0 {
+		limit = min(*l, maxUpcomingLimit)
+	}
+	to := from.AddDate(0, 0, days)
+
+	posts, err := h.postRepo.ListEvents(r.Context(), models.EventFilter{
+		ContentTypeSlug:  q.Get("content_type"),
+		TagSlug:          q.Get("tag"),
+		ContentTypeSlugs: delivery.From(r.Context()).ContentTypes(),
+		From:             &from,
+	})
+	if err != nil {
+		response.InternalError(w, "Failed to list events")
+		return
+	}
+
+	occurrences := []models.EventOccurrence{}
+	for i := range posts {
+		post := &posts[i]
+		var duration time.Duration
+		if post.EndsAt != nil {
+			duration = post.EndsAt.Sub(*post.StartsAt)
+		}
+
+		// Occurrences still running at from are included, so the window
+		// starts one event length earlier
+		starts := []time.Time{post.StartsAt.UTC()}
+		if post.Recurrence != nil {
+			rule, err := calendar.ParseRule(*post.Recurrence)
+			if err != nil {
+				continue
+			}
+			starts = rule.Between(*post.StartsAt, from.Add(-duration), to, limit)
+		}
+
+		shaped := h.serializer.Post(post)
+		for _, start := range starts {
+			occurrence := models.EventOccurrence{StartsAt: start, Post: shaped}
+			if post.EndsAt != nil {
+				end := start.Add(duration)
+				occurrence.EndsAt = &end
+			}
+			if !start.Before(to) || (occurrence.EndsAt == nil && start.Before(from)) ||
+				(occurrence.EndsAt != nil && occurrence.EndsAt.Before(from)) {
+				continue
+			}
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartsAt.Before(occurrences[j].StartsAt)
+	})
+	if len(occurrences) > limit {
+		occurrences = occurrences[:limit]
+	}
+
+	response.OK(w, occurrences)
+}
+
+// Feed godoc
+// @Summary iCalendar event feed
+// @Description Published event posts as an iCalendar (.ics) feed; recurring events carry their RRULE
+// @Tags public
+// @Produce text/calendar
+// @Param content_type query string false "Filter by content type slug"
+// @Param tag query string false "Filter by tag slug"
+// @Success 200 {file} file
+// @Router /api/v1/public/events.ics [get]
+func (h *EventHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.EventFilter{
+		ContentTypeSlug:  q.Get("content_type"),
+		TagSlug:          q.Get("tag"),
+		ContentTypeSlugs: delivery.From(r.Context()).ContentTypes(),
+	}
+
+	posts, err := h.postRepo.ListEvents(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list events")
+		return
+	}
+
+	urls, err := h.urls.Load(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load site URL settings", err)
+		return
+	}
+
+	name := "Events"
+	switch {
+	case filter.ContentTypeSlug != "" && filter.TagSlug != "":
+		name = filter.ContentTypeSlug + " / " + filter.TagSlug
+	case filter.ContentTypeSlug != "":
+		name = filter.ContentTypeSlug
+	case filter.TagSlug != "":
+		name = filter.TagSlug
+	}
+
+	events := make([]calendar.Event, 0, len(posts))
+	for _, post := range posts {
+		event := calendar.Event{
+			UID:     post.ID.String(),
+			Summary: post.Title,
+			Start:   *post.StartsAt,
+			End:     post.EndsAt,
+			Updated: post.UpdatedAt,
+		}
+		if post.Excerpt != nil {
+			event.Description = *post.Excerpt
+		}
+		if urls.BaseURL != "" {
+			event.URL = urls.PostURL(&post)
+		}
+		if post.Recurrence != nil {
+			event.RRule = *post.Recurrence
+		}
+		if post.Latitude != nil && post.Longitude != nil {
+			event.Location = strconv.FormatFloat(*post.Latitude, 'f', -1, 64) + "," +
+				strconv.FormatFloat(*post.Longitude, 'f', -1, 64)
+		}
+		events = append(events, event)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="events.ics"`)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	calendar.WriteICS(w, name, events)
+}
+
+// validateEvent checks event fields on create and update: the end may not
+// precede the start and recurrence rules must parse
+func validateEvent(startsAt, endsAt *time.Time, recurrence *string, validationErrors map[string]string) {
+	if startsAt != nil && endsAt != nil && endsAt.Before(*startsAt) {
+		validationErrors["ends_at"] = "End must not be before start"
+	}
+	if recurrence != nil && *recurrence != "" {
+		if _, err := calendar.ParseRule(*recurrence); err != nil {
+			validationErrors["recurrence"] = "Invalid recurrence rule: " + err.Error()
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type ExportHandler struct {
+	repo *repository.ExportRepository
+	key  []byte
+}
+
+// NewExportHandler creates the data export handler. anonymizationKey keys
+// the hash applied to visitor identifiers; an empty key is replaced by a
+// random one.
+func NewExportHandler(repo *repository.ExportRepository, anonymizationKey string) *ExportHandler {
+	key := []byte(anonymizationKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &ExportHandler{repo: repo, key: key}
+}
+
+// Interactions godoc
+// @Summary Export interactions as NDJSON
+// @Description Stream daily post views and poll votes, oldest first, one JSON object per line. Voters are replaced by keyed hashes, so they can be joined within the export but not traced back.
+// @Tags export
+// @Produce application/x-ndjson
+// @Param event query string false "Comma-separated event types (view, poll_vote)"
+// @Param from query string false "Only interactions at or after this time (RFC 3339)"
+// @Param to query string false "Only interactions before this time (RFC 3339)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/export/interactions [get]
+func (h *ExportHandler) Interactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter models.InteractionFilter
+
+	validationErrors := make(map[string]string)
+	if events := q.Get("event"); events != "" {
+		for _, event := range strings.Split(events, ",") {
+			event = strings.TrimSpace(event)
+			if event != models.InteractionView && event != models.InteractionPollVote {
+				validationErrors["event"] = "Must be view or poll_vote"
+				break
+			}
+			filter.Events = append(filter.Events, event)
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			validationErrors[param.name] = "Must be an RFC 3339 timestamp"
+			continue
+		}
+		*param.dst = &t
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	export := newNDJSONExport(w, "interactions")
+	err := h.repo.EachInteraction(r.Context(), filter, func(interaction *models.Interaction) error {
+		if interaction.User != "" {
+			interaction.User = h.anonymize(interaction.User)
+		}
+		return export.Write(interaction)
+	})
+	export.Finish(r.Context(), "interactions", err)
+}
+
+// ContentFeatures godoc
+// @Summary Export content features as NDJSON
+// @Description Stream one JSON object per published post with its content type, tags, length and engagement counts, newest first
+// @Tags export
+// @Produce application/x-ndjson
+// @Success 200 {file} file
+// @Router /api/v1/export/content-features [get]
+func (h *ExportHandler) ContentFeatures(w http.ResponseWriter, r *http.Request) {
+	export := newNDJSONExport(w, "content-features")
+	err := h.repo.EachContentFeatures(r.Context(), func(features *models.ContentFeatures) error {
+		return export.Write(features)
+	})
+	export.Finish(r.Context(), "content-features", err)
+}
+
+// Outline godoc
+// @Summary Export the content outline
+// @Description Download every content type with its posts (titles, slugs and statuses) as an OPML or Markdown outline for content audits, optionally grouping posts within each type by a metadata field
+// @Tags export
+// @Produce text/x-opml,text/markdown
+// @Param format query string false "opml (default) or markdown"
+// @Param group_by query string false "Metadata field to group posts by within each type (meta.<field>)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/export/outline [get]
+func (h *ExportHandler) Outline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	validationErrors := make(map[string]string)
+	format := q.Get("format")
+	if format == "" {
+		format = outlineOPML
+	}
+	if format != outlineOPML && format != outlineMarkdown {
+		validationErrors["format"] = "Must be opml or markdown"
+	}
+	var groupBy string
+	if value := q.Get("group_by"); value != "" {
+		field, ok := strings.CutPrefix(value, "meta.")
+		if !ok || !models.ValidMetaField(field) {
+			validationErrors["group_by"] = "Must be meta.<field>"
+		}
+		groupBy = field
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	export := newOutlineExport(w, format)
+	err := h.repo.EachOutlineEntry(r.Context(), groupBy, export.Write)
+	export.Finish(r.Context(), err)
+}
+
+// anonymize replaces a visitor identifier with a keyed hash
+func (h *ExportHandler) anonymize(id string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
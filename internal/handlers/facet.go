@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// Facet guardrails: how many facets a request may ask for, how many values
+// each returns and how long computed facets are reused
+const (
+	maxFacets            = 5
+	maxFacetValues       = 50
+	facetCacheTTL        = time.Minute
+	maxFacetCacheEntries = 1000
+)
+
+// nonFilterParams do not change which posts match and are left out of facet
+// cache keys
+var nonFilterParams = []string{"page", "page_size", "sort_by", "sort_dir", "facets", "include_view_stats"}
+
+// parseFacets reads the comma-separated facets parameter, accepting "tags"
+// and meta.<field>
+func parseFacets(r *http.Request) ([]string, string) {
+	raw := r.URL.Query().Get("facets")
+	if raw == "" {
+		return nil, ""
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		name, isMeta := strings.CutPrefix(field, "meta.")
+		if field != "tags" && (!isMeta || !models.ValidMetaField(name)) {
+			return nil, "Facets must be tags or meta.<field>"
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	if len(fields) > maxFacets {
+		return nil, "At most 5 facets can be requested"
+	}
+	return fields, ""
+}
+
+type facetCacheEntry struct {
+	facet   *models.Facet
+	expires time.Time
+}
+
+// facetCache keeps computed facets for facetCacheTTL, keyed by field and the
+// filter query string
+type facetCache struct {
+	repo PostRepository
+
+	mu      sync.Mutex
+	entries map[string]facetCacheEntry
+}
+
+func newFacetCache(repo PostRepository) *facetCache {
+	return &facetCache{repo: repo, entries: make(map[string]facetCacheEntry)}
+}
+
+// compute returns the requested facets for the posts matching filter
+func (c *facetCache) compute(ctx context.Context, r *http.Request, filter models.PostFilter, fields []string) ([]models.Facet, error) {
+	query := r.URL.Query()
+	for _, param := range nonFilterParams {
+		query.Del(param)
+	}
+	// The status applied may differ from the one asked for
+	query.Del("status")
+	if filter.Status != nil {
+		query.Set("status", strconv.Itoa(int(*filter.Status)))
+	}
+	filterKey := query.Encode()
+
+	facets := make([]models.Facet, 0, len(fields))
+	for _, field := range fields {
+		key := field + "?" + filterKey
+
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && time.Now().Before(entry.expires) {
+			facets = append(facets, *entry.facet)
+			continue
+		}
+
+		facet, err := c.repo.Facet(ctx, filter, field, maxFacetValues)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, facet)
+		facets = append(facets, *facet)
+	}
+
+	return facets, nil
+}
+
+// InvalidateFacets drops cached facets when posts, their tags, categories
+// or content types change, so facets follow edits sooner than facetCacheTTL
+func (h *ContentPostHandler) InvalidateFacets(ctx context.Context, e events.ContentChanged) {
+	entity, _, _ := strings.Cut(e.Change, ".")
+	switch entity {
+	case "post", "tag", "category", "content_type":
+		h.facets.clear()
+	}
+}
+
+// clear drops every cached facet
+func (c *facetCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]facetCacheEntry)
+}
+
+func (c *facetCache) store(key string, facet *models.Facet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxFacetCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full of live entries: start over rather than grow unbounded
+		if len(c.entries) >= maxFacetCacheEntries {
+			c.entries = make(map[string]facetCacheEntry)
+		}
+	}
+	c.entries[key] = facetCacheEntry{facet: facet, expires: now.Add(facetCacheTTL)}
+}
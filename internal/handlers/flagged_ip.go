@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/anomaly"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type FlaggedIPHandler struct {
+	repo     *repository.FlaggedIPRepository
+	detector *anomaly.Detector
+}
+
+func NewFlaggedIPHandler(repo *repository.FlaggedIPRepository, detector *anomaly.Detector) *FlaggedIPHandler {
+	return &FlaggedIPHandler{repo: repo, detector: detector}
+}
+
+// List godoc
+// @Summary List flagged IP addresses
+// @Description Get addresses the anomaly detector challenged or banned on public endpoints, and manual overrides, most recently changed first
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param status query int false "Filter by status (1=challenge, 2=banned, 3=allowed)"
+// @Param manual query bool false "Only manual overrides (true) or detector flags (false)"
+// @Param active query bool false "Only flags in effect (true) or expired ones (false)"
+// @Param search query string false "Search in address and reason"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/flagged-ips [get]
+func (h *FlaggedIPHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.FlaggedIPFilter{
+		PaginationParams: parsePaginationParams(r),
+		Manual:           getBoolParam(r, "manual"),
+		Active:           getBoolParam(r, "active"),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if s, err := strconv.Atoi(statusStr); err == nil {
+			status := models.IPFlagStatus(s)
+			filter.Status = &status
+		}
+	}
+
+	flags, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list flagged IPs")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, flags, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Set godoc
+// @Summary Override an IP address
+// @Description Manually challenge, ban or allow an address on public endpoints. Manual overrides are never changed by the detector; allowed addresses are not tracked at all.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param ip path string true "IP address"
+// @Param body body models.SetFlaggedIPRequest true "Override"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/admin/flagged-ips/{ip} [put]
+func (h *FlaggedIPHandler) Set(w http.ResponseWriter, r *http.Request) {
+	ip := net.ParseIP(chi.URLParam(r, "ip"))
+	if ip == nil {
+		response.BadRequest(w, "Invalid IP address")
+		return
+	}
+
+	var req models.SetFlaggedIPRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if !req.Status.Valid() {
+		validationErrors["status"] = "Status must be 1 (challenge), 2 (banned), or 3 (allowed)"
+	} else if req.Status == models.IPFlagChallenge && !h.detector.CanChallenge() {
+		validationErrors["status"] = "Challenges need a CAPTCHA provider (CAPTCHA_PROVIDER)"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	flag, err := h.repo.Set(r.Context(), ip.String(), &req)
+	if err != nil {
+		response.InternalError(w, "Failed to set flagged IP")
+		return
+	}
+
+	response.OK(w, flag)
+}
+
+// Delete godoc
+// @Summary Clear a flagged IP address
+// @Description Lift a challenge, ban or manual override so the address is treated like any other
+// @Tags admin
+// @Param ip path string true "IP address"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/admin/flagged-ips/{ip} [delete]
+func (h *FlaggedIPHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ip := net.ParseIP(chi.URLParam(r, "ip"))
+	if ip == nil {
+		response.BadRequest(w, "Invalid IP address")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), ip.String()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Flagged IP not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete flagged IP")
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Near query defaults and bounds, in kilometres
+const (
+	defaultNearRadiusKm = 10
+	maxNearRadiusKm     = 20000
+)
+
+// parseNear reads near=lat,lng and radius (km), ignoring invalid points.
+// The radius defaults to defaultNearRadiusKm and is capped at half the
+// Earth's circumference.
+func parseNear(r *http.Request) (*models.GeoPoint, float64) {
+	latStr, lngStr, ok := strings.Cut(r.URL.Query().Get("near"), ",")
+	if !ok {
+		return nil, 0
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil {
+		return nil, 0
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || !models.ValidCoordinates(lat, lng) {
+		return nil, 0
+	}
+
+	radius := float64(defaultNearRadiusKm)
+	if radiusStr := r.URL.Query().Get("radius"); radiusStr != "" {
+		if v, err := strconv.ParseFloat(radiusStr, 64); err == nil && v > 0 {
+			radius = v
+		}
+	}
+	if radius > maxNearRadiusKm {
+		radius = maxNearRadiusKm
+	}
+
+	return &models.GeoPoint{Latitude: lat, Longitude: lng}, radius
+}
+
+// validateLocation requires latitude and longitude together and within range
+func validateLocation(lat, lng *float64, validationErrors map[string]string) {
+	switch {
+	case lat == nil && lng == nil:
+	case lat == nil || lng == nil:
+		validationErrors["location"] = "Latitude and longitude must be set together"
+	case !models.ValidCoordinates(*lat, *lng):
+		validationErrors["location"] = "Latitude must be within -90..90 and longitude within -180..180"
+	}
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   *geoJSONPoint          `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// writeGeoJSON writes posts as a GeoJSON FeatureCollection. Posts without a
+// location get a null geometry.
+func writeGeoJSON(w http.ResponseWriter, posts []models.ContentPost) {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	for _, post := range posts {
+		feature := geoJSONFeature{
+			Type: "Feature",
+			ID:   post.ID.String(),
+			Properties: map[string]interface{}{
+				"title":        post.Title,
+				"slug":         post.Slug,
+				"excerpt":      post.Excerpt,
+				"status":       post.Status,
+				"published_at": post.PublishedAt,
+			},
+		}
+		if !response.Hides(w, &post, "metadata") {
+			feature.Properties["metadata"] = post.Metadata
+		}
+		if post.ContentType != nil {
+			feature.Properties["content_type"] = post.ContentType.Slug
+		}
+		if post.DistanceKm != nil {
+			feature.Properties["distance_km"] = *post.DistanceKm
+		}
+		if post.Latitude != nil && post.Longitude != nil {
+			// GeoJSON orders coordinates longitude first
+			feature.Geometry = &geoJSONPoint{Type: "Point", Coordinates: [2]float64{*post.Longitude, *post.Latitude}}
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(collection)
+}
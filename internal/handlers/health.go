@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/database"
+	"github.com/keeps-dev/go-cms-template/internal/database/migrate"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// readinessTimeout bounds the database check of a readiness probe
+const readinessTimeout = 3 * time.Second
+
+type HealthHandler struct {
+	monitor  *database.HostMonitor
+	migrator *migrate.Migrator
+}
+
+func NewHealthHandler(monitor *database.HostMonitor, migrator *migrate.Migrator) *HealthHandler {
+	return &HealthHandler{monitor: monitor, migrator: migrator}
+}
+
+// Ready godoc
+// @Summary Readiness check
+// @Description Check the database and report the server the connection pool uses. Instances that write are not ready while connected to a standby; the check reconnects them to the current primary.
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=models.Readiness}
+// @Failure 503 {object} response.APIResponse{data=models.Readiness}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	state, err := h.monitor.Check(ctx)
+	if err != nil {
+		response.JSON(w, http.StatusServiceUnavailable, &models.Readiness{Status: "unavailable", Database: state})
+		return
+	}
+
+	response.OK(w, &models.Readiness{Status: "ready", Database: state})
+}
+
+// Migrations godoc
+// @Summary Migration status
+// @Description Report the schema version of the database, the latest migration embedded in the server and the migrations still pending. Status is up_to_date, pending, or ahead when the database has migrations this server doesn't know.
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=models.MigrationStatus}
+// @Failure 503 {object} response.APIResponse
+// @Router /health/migrations [get]
+func (h *HealthHandler) Migrations(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	status, err := h.migrator.Status(ctx)
+	if err != nil {
+		response.Error(w, http.StatusServiceUnavailable, "DATABASE_UNAVAILABLE", "Failed to get migration status")
+		return
+	}
+
+	response.OK(w, status)
+}
@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
 	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
 )
 
 // parseUUID parses a UUID from a string
@@ -68,3 +73,77 @@ func getIntParam(r *http.Request, key string) *int {
 	}
 	return nil
 }
+
+// clientIP returns the originating address of a request as resolved by the
+// RealIP middleware, which only honours proxy headers from trusted proxies
+func clientIP(r *http.Request) string {
+	if ip := reqctx.From(r.Context()).IP; ip != "" {
+		return ip
+	}
+	return normalizeIP(r.RemoteAddr)
+}
+
+// normalizeIP strips the port from a client address
+func normalizeIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// mergeMetadata sets key on a JSON object, creating the object when raw is empty.
+// Non-object metadata is rejected so client data is never overwritten.
+func mergeMetadata(raw json.RawMessage, key string, value interface{}) (json.RawMessage, error) {
+	data := make(map[string]interface{})
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+	}
+	data[key] = value
+	return json.Marshal(data)
+}
+
+// isJSONObject reports whether raw holds a JSON object
+func isJSONObject(raw json.RawMessage) bool {
+	var data map[string]interface{}
+	return json.Unmarshal(raw, &data) == nil && data != nil
+}
+
+// mergeObjects merges JSON objects, keys of override winning over base
+func mergeObjects(base, override json.RawMessage) (json.RawMessage, error) {
+	if len(base) == 0 || string(base) == "null" {
+		return override, nil
+	}
+	if len(override) == 0 || string(override) == "null" {
+		return base, nil
+	}
+	data := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(base, &data); err != nil {
+		return nil, err
+	}
+	var extra map[string]json.RawMessage
+	if err := json.Unmarshal(override, &extra); err != nil {
+		return nil, err
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return json.Marshal(data)
+}
+
+// getPublishedPost returns a published post for the public delivery API, in
+// the request's environment. Posts not yet published or outside the delivery
+// token's content types are reported as repository.ErrNotFound.
+func getPublishedPost(r *http.Request, repo *repository.ContentPostRepository, slug string) (*models.ContentPost, error) {
+	access := delivery.From(r.Context())
+	post, err := repo.GetBySlugInEnvironment(r.Context(), slug, access.Environment)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != models.PostStatusPublished || (post.PublishedAt != nil && post.PublishedAt.After(time.Now())) ||
+		!access.AllowsContentType(post.ContentType.Slug) {
+		return nil, repository.ErrNotFound
+	}
+	return post, nil
+}
@@ -1,22 +1,78 @@
 package handlers
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/imaging"
 	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/replication"
 	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
 	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
 )
 
+// Upload form limits: the form fields besides the file may add up to
+// uploadFormOverhead bytes, and up to uploadMemory bytes of the form are
+// held in memory, the rest in temporary files
+const (
+	uploadFormOverhead = 1 << 20
+	uploadMemory       = 8 << 20
+)
+
+// MediaHandler serves media records and, when store is set, their files.
+// Upload and download endpoints respond with 503 when no storage is
+// configured. When
+// replicas is set, new files and variants are queued for replication; CDN
+// URLs in responses go through failover.
 type MediaHandler struct {
-	repo *repository.MediaRepository
+	repo      *repository.MediaRepository
+	media     *service.MediaService
+	store     storage.Storage
+	replicas  *repository.MediaReplicationRepository
+	failover  *replication.Failover
+	urls      config.MediaURLConfig
+	upload    config.MediaUploadConfig
+	key       []byte
+	publicURL string
 }
 
-func NewMediaHandler(repo *repository.MediaRepository) *MediaHandler {
-	return &MediaHandler{repo: repo}
+// NewMediaHandler creates the media handler. An empty signing key in urls
+// is replaced by a random one; replicas and failover may be nil.
+func NewMediaHandler(repo *repository.MediaRepository, mediaService *service.MediaService, store storage.Storage, replicas *repository.MediaReplicationRepository, failover *replication.Failover, urls config.MediaURLConfig, upload config.MediaUploadConfig, publicURL string) *MediaHandler {
+	key := []byte(urls.SigningKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &MediaHandler{
+		repo:      repo,
+		media:     mediaService,
+		store:     store,
+		replicas:  replicas,
+		failover:  failover,
+		urls:      urls,
+		upload:    upload,
+		key:       key,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
 }
 
 // List godoc
@@ -28,26 +84,20 @@ func NewMediaHandler(repo *repository.MediaRepository) *MediaHandler {
 // @Param page_size query int false "Page size"
 // @Param file_type query int false "Filter by file type (1=image, 2=video, 3=document)"
 // @Param search query string false "Search in file name and alt text"
+// @Param team_id query string false "Filter by owning team ID"
 // @Success 200 {object} response.APIResponse
 // @Router /api/v1/media [get]
 func (h *MediaHandler) List(w http.ResponseWriter, r *http.Request) {
-	filter := models.MediaFilter{
-		PaginationParams: parsePaginationParams(r),
-		Search:           r.URL.Query().Get("search"),
-	}
-
-	if ftStr := r.URL.Query().Get("file_type"); ftStr != "" {
-		if ft, err := strconv.Atoi(ftStr); err == nil {
-			fileType := models.FileType(ft)
-			filter.FileType = &fileType
-		}
-	}
+	filter := parseMediaFilter(r)
 
 	mediaList, total, err := h.repo.List(r.Context(), filter)
 	if err != nil {
 		response.InternalError(w, "Failed to list media")
 		return
 	}
+	for i := range mediaList {
+		h.failover.Apply(&mediaList[i])
+	}
 
 	response.JSONWithMeta(w, http.StatusOK, mediaList, &response.Meta{
 		Page:       filter.Page,
@@ -57,6 +107,45 @@ func (h *MediaHandler) List(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Export godoc
+// @Summary Export media inventory as CSV
+// @Description Stream all media matching the list filters as a CSV file, including usage counts
+// @Tags media
+// @Produce text/csv
+// @Param file_type query int false "Filter by file type (1=image, 2=video, 3=document)"
+// @Param search query string false "Search in file name and alt text"
+// @Param team_id query string false "Filter by owning team ID"
+// @Success 200 {file} file
+// @Router /api/v1/media/export [get]
+func (h *MediaHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter := parseMediaFilter(r)
+
+	export, err := newCSVExport(w, "media", []string{
+		"id", "file_name", "file_type", "mime_type", "file_size", "object_key",
+		"bucket_name", "cdn_url", "alt_text", "usage_count", "created_at",
+	})
+	if err != nil {
+		return
+	}
+
+	err = h.repo.ExportEach(r.Context(), filter, func(media *models.Media, usageCount int64) error {
+		return export.Write([]string{
+			media.ID.String(),
+			media.FileName,
+			media.FileType.String(),
+			media.MimeType,
+			strconv.Itoa(media.FileSize),
+			media.ObjectKey,
+			media.BucketName,
+			csvString(media.CDNUrl),
+			csvString(media.AltText),
+			strconv.FormatInt(usageCount, 10),
+			csvTime(&media.CreatedAt),
+		})
+	})
+	export.Finish(r.Context(), "media", err)
+}
+
 // Get godoc
 // @Summary Get media by ID
 // @Description Get a single media by its ID
@@ -83,9 +172,206 @@ func (h *MediaHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.failover.Apply(media)
 	response.OK(w, media)
 }
 
+// Download godoc
+// @Summary Download media file
+// @Description Stream a media file from storage. Range requests are supported, so videos can be seeked.
+// @Tags media
+// @Produce octet-stream
+// @Param id path string true "Media ID"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 404 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/media/{id}/download [get]
+func (h *MediaHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid media ID")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private")
+	h.serveFile(w, r, id)
+}
+
+// SignURL godoc
+// @Summary Sign a media download URL
+// @Description Create a URL that downloads the media file without credentials until it expires, for sharing. With MEDIA_URL_DIRECT set and S3 storage, the URL is presigned by the storage and points at it directly.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param body body models.SignMediaURLRequest false "URL lifetime"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/media/{id}/signed-url [post]
+func (h *MediaHandler) SignURL(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		response.Error(w, http.StatusServiceUnavailable, "STORAGE_DISABLED", "No media storage is configured")
+		return
+	}
+
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid media ID")
+		return
+	}
+
+	var req models.SignMediaURLRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	ttl := h.urls.TTL
+	if req.ExpiresIn != nil {
+		ttl = time.Duration(*req.ExpiresIn) * time.Second
+		if ttl <= 0 || ttl > h.urls.MaxTTL {
+			response.ValidationError(w, map[string]string{
+				"expires_in": fmt.Sprintf("Must be between 1 and %d seconds", int(h.urls.MaxTTL/time.Second)),
+			})
+			return
+		}
+	}
+
+	media, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Media not found")
+			return
+		}
+		response.InternalError(w, "Failed to get media")
+		return
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	if h.urls.Direct && ttl <= storage.MaxSignedURLTTL {
+		signedURL, err := h.store.SignedURL(r.Context(), media.BucketName, media.ObjectKey, ttl)
+		if err == nil {
+			response.OK(w, &models.SignedMediaURL{URL: signedURL, ExpiresAt: expiresAt})
+			return
+		}
+		if !errors.Is(err, storage.ErrSigningUnsupported) {
+			response.InternalErrorWithErr(w, "Failed to sign media URL", err)
+			return
+		}
+	}
+
+	expires := expiresAt.Unix()
+	response.OK(w, &models.SignedMediaURL{
+		URL:       fmt.Sprintf("%s/api/v1/public/media/%s?expires=%d&signature=%s", h.publicURL, id, expires, h.signature(id, expires)),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// SignedDownload godoc
+// @Summary Download media file with a signed URL
+// @Description Stream a media file using a URL from the signed-url endpoint (public endpoint). Range requests are supported.
+// @Tags media
+// @Produce octet-stream
+// @Param id path string true "Media ID"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/public/media/{id} [get]
+func (h *MediaHandler) SignedDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid media ID")
+		return
+	}
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	signature := r.URL.Query().Get("signature")
+	remaining := time.Until(time.Unix(expires, 0))
+	if err != nil || remaining <= 0 || !hmac.Equal([]byte(signature), []byte(h.signature(id, expires))) {
+		response.Forbidden(w, "Invalid or expired link")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(remaining/time.Second)))
+	h.serveFile(w, r, id)
+}
+
+// signature authenticates a download URL for media id expiring at expires
+func (h *MediaHandler) signature(id uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, h.key)
+	fmt.Fprintf(mac, "%s\n%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// inlineMediaTypes are displayed in the browser when downloaded; anything
+// else, such as HTML or SVG that could run script on the API's origin, is
+// sent as an attachment
+var inlineMediaTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/avif":      true,
+	"video/mp4":       true,
+	"video/webm":      true,
+	"video/ogg":       true,
+	"audio/mpeg":      true,
+	"audio/ogg":       true,
+	"audio/wav":       true,
+	"audio/webm":      true,
+	"audio/mp4":       true,
+	"application/pdf": true,
+}
+
+// serveFile streams a media file from storage, answering Range and
+// conditional requests
+func (h *MediaHandler) serveFile(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if h.store == nil {
+		response.Error(w, http.StatusServiceUnavailable, "STORAGE_DISABLED", "No media storage is configured")
+		return
+	}
+
+	media, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Media not found")
+			return
+		}
+		response.InternalError(w, "Failed to get media")
+		return
+	}
+
+	file, modTime, err := h.store.Get(r.Context(), media.BucketName, media.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, "Media file not found in storage")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to open media file", err)
+		return
+	}
+	defer file.Close()
+
+	disposition := "attachment"
+	if mediaType, _, err := mime.ParseMediaType(media.MimeType); err == nil && inlineMediaTypes[mediaType] {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", media.MimeType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": media.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if media.Checksum != nil && *media.Checksum != "" {
+		w.Header().Set("ETag", `"`+*media.Checksum+`"`)
+	}
+	http.ServeContent(w, r, media.FileName, modTime, file)
+}
+
 // Create godoc
 // @Summary Create media record
 // @Description Create a new media record (metadata only, file upload handled separately)
@@ -121,7 +407,7 @@ func (h *MediaHandler) Create(w http.ResponseWriter, r *http.Request) {
 		validationErrors["file_size"] = "File size must be positive"
 	}
 	if req.FileType < 1 || req.FileType > 3 {
-		validationErrors["file_type"] = "File type must be 1 (image), 2 (video), or 3 (document)"
+		validationErrors["file_type"] = "File type must be image, video or document"
 	}
 
 	if len(validationErrors) > 0 {
@@ -129,19 +415,223 @@ func (h *MediaHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	media, err := h.repo.Create(r.Context(), &req)
+	media, err := h.media.Create(r.Context(), &req)
 	if err != nil {
 		if errors.Is(err, repository.ErrDuplicate) {
 			response.Conflict(w, "Media with this object key already exists")
 			return
 		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid team ID")
+			return
+		}
 		response.InternalError(w, "Failed to create media")
 		return
 	}
 
+	h.enqueueReplication(r, media)
+	h.failover.Apply(media)
 	response.Created(w, media)
 }
 
+// Upload godoc
+// @Summary Upload media file
+// @Description Store a file in media storage and create its media record in one request. The MIME type is detected from the file's first bytes, ignoring the part's Content-Type, and must be an allowed image, video, audio, PDF, archive, office or text format (415 otherwise); the file type follows from it. The checksum (SHA-256) and, for PNG, JPEG and GIF images, the dimensions are computed from the file.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File to upload"
+// @Param alt_text formData string false "Alternative text"
+// @Param team_id formData string false "Owning team ID"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 413 {object} response.APIResponse
+// @Failure 415 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/media/upload [post]
+func (h *MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		response.Error(w, http.StatusServiceUnavailable, "STORAGE_DISABLED", "No media storage is configured")
+		return
+	}
+
+	// Large files take longer to arrive than the server's read timeout
+	// allows; wrapped writers that can't extend it keep the default
+	http.NewResponseController(w).SetReadDeadline(time.Now().Add(h.upload.Timeout))
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.upload.MaxSize+uploadFormOverhead)
+	if err := r.ParseMultipartForm(uploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.uploadTooLarge(w)
+			return
+		}
+		response.BadRequest(w, "Invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.ValidationError(w, map[string]string{"file": "File is required"})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.upload.MaxSize {
+		h.uploadTooLarge(w)
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if header.Size == 0 {
+		validationErrors["file"] = "File is empty"
+	}
+	req := models.CreateMediaRequest{
+		FileName:   header.Filename,
+		BucketName: h.upload.Bucket,
+		FileSize:   int(header.Size),
+	}
+	if altText := r.FormValue("alt_text"); altText != "" {
+		req.AltText = &altText
+	}
+	if teamID := r.FormValue("team_id"); teamID != "" {
+		if id, err := uuid.Parse(teamID); err == nil {
+			req.TeamID = &id
+		} else {
+			validationErrors["team_id"] = "Invalid team ID"
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		response.InternalErrorWithErr(w, "Failed to read uploaded file", err)
+		return
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	req.Checksum = &checksum
+
+	head := make([]byte, 512)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		response.InternalErrorWithErr(w, "Failed to read uploaded file", err)
+		return
+	}
+	n, _ := io.ReadFull(file, head)
+	mimeType, ok := uploadContentType(header.Filename, head[:n])
+	if !ok {
+		response.Error(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", fmt.Sprintf("Files of type %s can't be uploaded", mimeType))
+		return
+	}
+	req.MimeType = mimeType
+	req.FileType = fileTypeOf(req.MimeType)
+
+	if req.FileType == models.FileTypeImage {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			response.InternalErrorWithErr(w, "Failed to read uploaded file", err)
+			return
+		}
+		// Formats the standard library can't decode are stored without
+		// dimensions
+		if width, height, err := imaging.Size(file); err == nil {
+			req.Dimensions = json.RawMessage(fmt.Sprintf(`{"width":%d,"height":%d}`, width, height))
+		}
+	}
+
+	req.ObjectKey = fmt.Sprintf("uploads/%s/%s%s", time.Now().UTC().Format("2006/01"), uuid.New(), strings.ToLower(path.Ext(req.FileName)))
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		response.InternalErrorWithErr(w, "Failed to read uploaded file", err)
+		return
+	}
+	if err := h.store.Put(r.Context(), req.BucketName, req.ObjectKey, file, header.Size, req.MimeType); err != nil {
+		response.InternalErrorWithErr(w, "Failed to store uploaded file", err)
+		return
+	}
+
+	media, err := h.media.Create(r.Context(), &req)
+	if err != nil {
+		h.deleteFiles(r, req.BucketName, []string{req.ObjectKey})
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid team ID")
+			return
+		}
+		response.InternalError(w, "Failed to create media")
+		return
+	}
+
+	h.enqueueReplication(r, media)
+	h.failover.Apply(media)
+	response.Created(w, media)
+}
+
+// uploadTooLarge rejects an upload over the size limit
+func (h *MediaHandler) uploadTooLarge(w http.ResponseWriter) {
+	response.Error(w, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", fmt.Sprintf("Uploads are limited to %d bytes", h.upload.MaxSize))
+}
+
+// uploadMediaTypes are the types sniffed from the content of files that
+// may be uploaded
+var uploadMediaTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/bmp":       true,
+	"video/mp4":       true,
+	"video/webm":      true,
+	"video/avi":       true,
+	"audio/mpeg":      true,
+	"audio/wave":      true,
+	"application/ogg": true,
+	"application/pdf": true,
+	"application/zip": true,
+	"text/plain":      true,
+}
+
+// uploadExtensionTypes name the formats that sniff as a generic container
+// (ZIP archives, plain text) by their extension
+var uploadExtensionTypes = map[string]map[string]string{
+	"application/zip": {
+		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		".odt":  "application/vnd.oasis.opendocument.text",
+		".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+		".odp":  "application/vnd.oasis.opendocument.presentation",
+		".epub": "application/epub+zip",
+	},
+	"text/plain": {
+		".csv": "text/csv",
+		".md":  "text/markdown",
+	},
+}
+
+// uploadContentType detects the MIME type of an uploaded file from its
+// first bytes, whatever the client claims, and reports whether files of
+// that type may be uploaded. Containers are narrowed down by extension.
+func uploadContentType(fileName string, head []byte) (string, bool) {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if byExt, ok := uploadExtensionTypes[detected][strings.ToLower(path.Ext(fileName))]; ok {
+		return byExt, true
+	}
+	return detected, uploadMediaTypes[detected]
+}
+
+// fileTypeOf classifies a MIME type as image, video or document
+func fileTypeOf(mimeType string) models.FileType {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return models.FileTypeImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return models.FileTypeVideo
+	default:
+		return models.FileTypeDocument
+	}
+}
+
 // Update godoc
 // @Summary Update media
 // @Description Update media metadata
@@ -173,16 +663,24 @@ func (h *MediaHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w, "Media not found")
 			return
 		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid team ID")
+			return
+		}
 		response.InternalError(w, "Failed to update media")
 		return
 	}
 
+	if req.Variants != nil {
+		h.enqueueReplication(r, media)
+	}
+	h.failover.Apply(media)
 	response.OK(w, media)
 }
 
 // Delete godoc
 // @Summary Delete media
-// @Description Delete a media record
+// @Description Delete a media record and, when storage is configured, its file and variant files
 // @Tags media
 // @Param id path string true "Media ID"
 // @Success 204 "No Content"
@@ -197,7 +695,7 @@ func (h *MediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.repo.Delete(r.Context(), id)
+	media, err := h.repo.Delete(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			response.NotFound(w, "Media not found")
@@ -211,5 +709,53 @@ func (h *MediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.deleteFiles(r, media.BucketName, replication.ObjectKeys(media))
 	response.NoContent(w)
 }
+
+// deleteFiles removes the files of a deleted or never created media record
+// from storage. The record is already gone, so failures are only logged.
+func (h *MediaHandler) deleteFiles(r *http.Request, bucket string, keys []string) {
+	if h.store == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := h.store.Delete(r.Context(), bucket, key); err != nil {
+			reqctx.Logf(r.Context(), "[ERROR] Failed to delete media file %s/%s: %v", bucket, key, err)
+		}
+	}
+}
+
+// enqueueReplication queues the media file and its variants for copying to
+// the replica storage. The record is already saved, so a failure is only
+// logged; the backfill endpoint queues anything missed.
+func (h *MediaHandler) enqueueReplication(r *http.Request, media *models.Media) {
+	if h.replicas == nil {
+		return
+	}
+	if err := h.replicas.Enqueue(r.Context(), media.ID, replication.ObjectKeys(media)); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+}
+
+// parseMediaFilter extracts media filter options from the query string
+func parseMediaFilter(r *http.Request) models.MediaFilter {
+	filter := models.MediaFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if ftStr := r.URL.Query().Get("file_type"); ftStr != "" {
+		if fileType, ok := models.ParseEnum[models.FileType](ftStr); ok {
+			filter.FileType = &fileType
+		}
+	}
+
+	if teamID := r.URL.Query().Get("team_id"); teamID != "" {
+		if id, err := uuid.Parse(teamID); err == nil {
+			filter.TeamID = &id
+		}
+	}
+
+	return filter
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// ndjsonExport streams newline-delimited JSON records to the response,
+// flushing every csvFlushEvery records like csvExport
+type ndjsonExport struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	rows    int
+	flusher http.Flusher
+}
+
+// newNDJSONExport writes the download headers for an .ndjson file
+func newNDJSONExport(w http.ResponseWriter, name string) *ndjsonExport {
+	filename := fmt.Sprintf("%s-%s.ndjson", name, time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	e := &ndjsonExport{buf: bufio.NewWriter(w)}
+	e.encoder = json.NewEncoder(e.buf)
+	e.flusher, _ = w.(http.Flusher)
+	return e
+}
+
+// Write appends one record as a JSON line
+func (e *ndjsonExport) Write(record interface{}) error {
+	if err := e.encoder.Encode(record); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%csvFlushEvery == 0 {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush sends buffered records to the client
+func (e *ndjsonExport) Flush() error {
+	err := e.buf.Flush()
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	return err
+}
+
+// Finish flushes the remaining records and logs a failure that happened
+// mid-stream, since the status code has already been sent at that point
+func (e *ndjsonExport) Finish(ctx context.Context, name string, err error) {
+	if flushErr := e.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] NDJSON export %s failed after %d records: %v", name, e.rows, err)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type NotificationHandler struct {
+	notifier *notify.Notifier
+}
+
+func NewNotificationHandler(notifier *notify.Notifier) *NotificationHandler {
+	return &NotificationHandler{notifier: notifier}
+}
+
+// GetConfig godoc
+// @Summary Get notification configuration
+// @Description Get configured chat channels (secrets masked) and event routing rules
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/notifications/config [get]
+func (h *NotificationHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.notifier.LoadConfig(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load notification settings", err)
+		return
+	}
+
+	response.OK(w, cfg.Redacted())
+}
+
+// Test godoc
+// @Summary Send test notification
+// @Description Send a test message to a configured chat channel
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param body body models.TestNotificationRequest true "Channel name"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Router /api/v1/notifications/test [post]
+func (h *NotificationHandler) Test(w http.ResponseWriter, r *http.Request) {
+	var req models.TestNotificationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Channel == "" {
+		response.ValidationError(w, map[string]string{"channel": "Channel is required"})
+		return
+	}
+
+	if err := h.notifier.SendTest(r.Context(), req.Channel); err != nil {
+		if errors.Is(err, notify.ErrChannelNotFound) {
+			response.NotFound(w, "Notification channel not found")
+			return
+		}
+		response.Error(w, http.StatusBadGateway, "NOTIFICATION_FAILED", err.Error())
+		return
+	}
+
+	response.OK(w, map[string]string{"status": "sent"})
+}
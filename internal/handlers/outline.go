@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Outline formats
+const (
+	outlineOPML     = "opml"
+	outlineMarkdown = "markdown"
+)
+
+// outlineExport streams the content outline as OPML or Markdown. Entries
+// arrive ordered by content type and group, so nesting only needs to watch
+// for changes; output is flushed every csvFlushEvery posts like csvExport.
+type outlineExport struct {
+	buf      *bufio.Writer
+	flusher  http.Flusher
+	markdown bool
+	rows     int
+
+	typeID  uuid.UUID
+	inGroup bool
+	group   string
+}
+
+// newOutlineExport writes the download headers and the document prologue
+func newOutlineExport(w http.ResponseWriter, format string) *outlineExport {
+	ext, contentType := "opml", "text/x-opml; charset=utf-8"
+	if format == outlineMarkdown {
+		ext, contentType = "md", "text/markdown; charset=utf-8"
+	}
+	filename := fmt.Sprintf("outline-%s.%s", time.Now().Format("20060102-150405"), ext)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	e := &outlineExport{buf: bufio.NewWriter(w), markdown: format == outlineMarkdown}
+	e.flusher, _ = w.(http.Flusher)
+
+	if e.markdown {
+		e.buf.WriteString("# Content outline\n")
+	} else {
+		fmt.Fprintf(e.buf, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<opml version=\"2.0\">\n"+
+			"<head><title>Content outline</title><dateCreated>%s</dateCreated></head>\n<body>\n",
+			time.Now().UTC().Format(time.RFC1123Z))
+	}
+	return e
+}
+
+// Write adds an entry, opening its content type and group when they change
+func (e *outlineExport) Write(entry *models.OutlineEntry) error {
+	if entry.ContentTypeID != e.typeID {
+		e.closeType()
+		e.typeID = entry.ContentTypeID
+		if e.markdown {
+			fmt.Fprintf(e.buf, "\n## %s\n\n", outlineText(entry.ContentTypeName))
+		} else {
+			fmt.Fprintf(e.buf, "<outline text=\"%s\" type=\"content_type\" slug=\"%s\">\n",
+				xmlAttr(entry.ContentTypeName), xmlAttr(entry.ContentTypeSlug))
+		}
+	}
+
+	if entry.Group != nil && (!e.inGroup || *entry.Group != e.group) {
+		e.closeGroup()
+		e.inGroup, e.group = true, *entry.Group
+		if e.markdown {
+			fmt.Fprintf(e.buf, "- **%s**\n", outlineText(e.group))
+		} else {
+			fmt.Fprintf(e.buf, "  <outline text=\"%s\" type=\"group\">\n", xmlAttr(e.group))
+		}
+	}
+
+	if entry.PostTitle == nil {
+		return nil
+	}
+	indent := ""
+	if e.inGroup {
+		indent = "  "
+	}
+	if e.markdown {
+		fmt.Fprintf(e.buf, "%s- %s _(%s)_\n", indent, outlineText(*entry.PostTitle), entry.PostStatus)
+	} else {
+		fmt.Fprintf(e.buf, "%s  <outline text=\"%s\" type=\"post\" slug=\"%s\" status=\"%s\"/>\n",
+			indent, xmlAttr(*entry.PostTitle), xmlAttr(*entry.PostSlug), entry.PostStatus)
+	}
+
+	e.rows++
+	if e.rows%csvFlushEvery == 0 {
+		return e.Flush()
+	}
+	return nil
+}
+
+func (e *outlineExport) closeGroup() {
+	if e.inGroup && !e.markdown {
+		e.buf.WriteString("  </outline>\n")
+	}
+	e.inGroup = false
+}
+
+func (e *outlineExport) closeType() {
+	e.closeGroup()
+	if e.typeID != uuid.Nil && !e.markdown {
+		e.buf.WriteString("</outline>\n")
+	}
+}
+
+// Flush sends buffered output to the client
+func (e *outlineExport) Flush() error {
+	err := e.buf.Flush()
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	return err
+}
+
+// Finish closes the document, flushes and logs a failure that happened
+// mid-stream, since the status code has already been sent at that point
+func (e *outlineExport) Finish(ctx context.Context, err error) {
+	e.closeType()
+	if !e.markdown {
+		e.buf.WriteString("</body>\n</opml>\n")
+	}
+	if flushErr := e.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] Outline export failed after %d posts: %v", e.rows, err)
+	}
+}
+
+// xmlAttr escapes s for use in a double-quoted XML attribute
+func xmlAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// outlineText puts s on a single Markdown line
+func outlineText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
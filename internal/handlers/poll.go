@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// maxPollOptions bounds the number of answers a poll can have
+const maxPollOptions = 20
+
+// pollReference matches poll embeds in post content: the [poll id="..."]
+// shortcode or an element with a data-poll-id attribute
+var pollReference = regexp.MustCompile(`(?:\[poll\s+id=|data-poll-id=)["']([0-9a-fA-F-]{36})["']`)
+
+type PollHandler struct {
+	repo     *repository.PollRepository
+	postRepo *repository.ContentPostRepository
+}
+
+func NewPollHandler(repo *repository.PollRepository, postRepo *repository.ContentPostRepository) *PollHandler {
+	return &PollHandler{repo: repo, postRepo: postRepo}
+}
+
+// List godoc
+// @Summary List polls
+// @Description Get all polls with their options and vote counts
+// @Tags polls
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/polls [get]
+func (h *PollHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.PollFilter{PaginationParams: parsePaginationParams(r)}
+
+	polls, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list polls")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, polls, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get poll
+// @Description Get a poll with its options and live results (also served publicly)
+// @Tags polls
+// @Produce json
+// @Param id path string true "Poll ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/polls/{id} [get]
+func (h *PollHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid poll ID")
+		return
+	}
+
+	poll, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Poll not found")
+			return
+		}
+		response.InternalError(w, "Failed to get poll")
+		return
+	}
+
+	response.OK(w, poll)
+}
+
+// Create godoc
+// @Summary Create poll
+// @Description Create a poll with its options, optionally limited to an open/close window
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Param body body models.CreatePollRequest true "Poll data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/polls [post]
+func (h *PollHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePollRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if strings.TrimSpace(req.Question) == "" {
+		validationErrors["question"] = "Question is required"
+	}
+	if len(req.Options) < 2 || len(req.Options) > maxPollOptions {
+		validationErrors["options"] = "Between 2 and 20 options are required"
+	}
+	for _, option := range req.Options {
+		if strings.TrimSpace(option) == "" {
+			validationErrors["options"] = "Options must not be empty"
+			break
+		}
+	}
+	if req.DedupBy != "" {
+		validatePollDedup(req.DedupBy, validationErrors)
+	}
+	validatePollWindow(req.OpensAt, req.ClosesAt, validationErrors)
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	poll, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		response.InternalError(w, "Failed to create poll")
+		return
+	}
+
+	response.Created(w, poll)
+}
+
+// Update godoc
+// @Summary Update poll
+// @Description Update a poll's question, window or deduplication mode. Options cannot be changed.
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Param id path string true "Poll ID"
+// @Param body body models.UpdatePollRequest true "Poll data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/polls/{id} [put]
+func (h *PollHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid poll ID")
+		return
+	}
+
+	var req models.UpdatePollRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Question != nil && strings.TrimSpace(*req.Question) == "" {
+		validationErrors["question"] = "Question must not be empty"
+	}
+	if req.DedupBy != nil {
+		validatePollDedup(*req.DedupBy, validationErrors)
+	}
+	validatePollWindow(req.OpensAt, req.ClosesAt, validationErrors)
+
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	poll, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Poll not found")
+			return
+		}
+		response.InternalError(w, "Failed to update poll")
+		return
+	}
+
+	response.OK(w, poll)
+}
+
+// Delete godoc
+// @Summary Delete poll
+// @Description Delete a poll and its votes
+// @Tags polls
+// @Param id path string true "Poll ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/polls/{id} [delete]
+func (h *PollHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid poll ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Poll not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete poll")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Vote godoc
+// @Summary Vote in a poll
+// @Description Cast one vote for an option of an open poll (public endpoint). Voters are deduplicated by client IP or, for dedup_by=visitor polls, by the visitor_id parameter or cms_visitor cookie.
+// @Tags public
+// @Accept json
+// @Produce json
+// @Param id path string true "Poll ID"
+// @Param visitor_id query string false "Stable visitor identifier"
+// @Param body body models.PollVoteRequest true "Chosen option"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/public/polls/{id}/vote [post]
+func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid poll ID")
+		return
+	}
+
+	var req models.PollVoteRequest
+	if err := decodeJSON(r, &req); err != nil || req.OptionID == uuid.Nil {
+		response.BadRequest(w, "option_id is required")
+		return
+	}
+
+	poll, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Poll not found")
+			return
+		}
+		response.InternalError(w, "Failed to get poll")
+		return
+	}
+	if !poll.IsOpen(time.Now()) {
+		response.Forbidden(w, "Poll is not open for voting")
+		return
+	}
+
+	voter := "ip:" + clientIP(r)
+	if poll.DedupBy == models.PollDedupVisitor {
+		voter = "visitor:" + visitorIdentifier(w, r)
+	}
+	sum := sha256.Sum256([]byte(poll.ID.String() + "|" + voter))
+
+	if err := h.repo.Vote(r.Context(), poll.ID, req.OptionID, hex.EncodeToString(sum[:])); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Poll option not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Already voted in this poll")
+			return
+		}
+		response.InternalError(w, "Failed to record vote")
+		return
+	}
+
+	poll, err = h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to get poll")
+		return
+	}
+
+	response.OK(w, poll)
+}
+
+// PostPolls godoc
+// @Summary Polls embedded in a post
+// @Description Polls referenced from a published post's content with [poll id="..."] or data-poll-id="...", in order of appearance, with live results
+// @Tags public
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/public/posts/{slug}/polls [get]
+func (h *PollHandler) PostPolls(w http.ResponseWriter, r *http.Request) {
+	post, err := getPublishedPost(r, h.postRepo, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	var ids []uuid.UUID
+	if post.Content != nil {
+		ids = pollReferences(*post.Content)
+	}
+
+	polls, err := h.repo.GetByIDs(r.Context(), ids)
+	if err != nil {
+		response.InternalError(w, "Failed to get polls")
+		return
+	}
+
+	response.OK(w, polls)
+}
+
+// pollReferences returns the distinct poll IDs embedded in content, in order
+func pollReferences(content string) []uuid.UUID {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range pollReference.FindAllStringSubmatch(content, -1) {
+		id, err := uuid.Parse(m[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func validatePollDedup(dedupBy string, validationErrors map[string]string) {
+	if dedupBy != models.PollDedupIP && dedupBy != models.PollDedupVisitor {
+		validationErrors["dedup_by"] = "Must be ip or visitor"
+	}
+}
+
+func validatePollWindow(opensAt, closesAt *time.Time, validationErrors map[string]string) {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		validationErrors["closes_at"] = "Must be after opens_at"
+	}
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type PostTemplateHandler struct {
+	repo            *repository.PostTemplateRepository
+	contentTypeRepo *repository.ContentTypeRepository
+	teamRepo        *repository.TeamRepository
+}
+
+func NewPostTemplateHandler(repo *repository.PostTemplateRepository, contentTypeRepo *repository.ContentTypeRepository, teamRepo *repository.TeamRepository) *PostTemplateHandler {
+	return &PostTemplateHandler{repo: repo, contentTypeRepo: contentTypeRepo, teamRepo: teamRepo}
+}
+
+// List godoc
+// @Summary List post templates
+// @Description Get starting templates for new posts
+// @Tags posts
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param content_type_id query string false "Filter by content type"
+// @Param team_id query string false "Filter by team"
+// @Param is_active query bool false "Filter by active status"
+// @Param search query string false "Search in name and description"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/post-templates [get]
+func (h *PostTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.PostTemplateFilter{
+		PaginationParams: parsePaginationParams(r),
+		IsActive:         getBoolParam(r, "is_active"),
+		Search:           r.URL.Query().Get("search"),
+	}
+	if id, err := uuid.Parse(r.URL.Query().Get("content_type_id")); err == nil {
+		filter.ContentTypeID = &id
+	}
+	if id, err := uuid.Parse(r.URL.Query().Get("team_id")); err == nil {
+		filter.TeamID = &id
+	}
+
+	templates, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list post templates")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, templates, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get post template by ID
+// @Description Get a single post template
+// @Tags posts
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/post-templates/{id} [get]
+func (h *PostTemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	template, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post template not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post template")
+		return
+	}
+
+	response.OK(w, template)
+}
+
+// Create godoc
+// @Summary Create post template
+// @Description Create a starting template for posts of a content type. The author is the authenticated user, who must be an editor or admin, and for team templates an editor or manager of the team.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param body body models.CreatePostTemplateRequest true "Template data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/post-templates [post]
+func (h *PostTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePostTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if req.ContentTypeID == uuid.Nil {
+		validationErrors["content_type_id"] = "Content type ID is required"
+	}
+	if len(req.Metadata) > 0 && !isJSONObject(req.Metadata) {
+		validationErrors["metadata"] = "Metadata must be a JSON object"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if !h.checkManager(w, r, req.TeamID) {
+		return
+	}
+	req.AuthorID = user.ID
+
+	template, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "A template with this name already exists for the content type")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid content type ID or team ID")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to create post template", err)
+		return
+	}
+
+	response.Created(w, template)
+}
+
+// Update godoc
+// @Summary Update post template
+// @Description Update a post template. The authenticated user must be able to manage it where it is and, when it moves, where it goes; a nil UUID team ID makes it shared again.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param body body models.UpdatePostTemplateRequest true "Template data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/post-templates/{id} [put]
+func (h *PostTemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+
+	var req models.UpdatePostTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+		if name == "" {
+			validationErrors["name"] = "Name cannot be empty"
+		}
+	}
+	if req.Metadata != nil && len(*req.Metadata) > 0 && !isJSONObject(*req.Metadata) {
+		validationErrors["metadata"] = "Metadata must be a JSON object"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if _, ok := h.getManaged(w, r, id); !ok {
+		return
+	}
+	if req.TeamID != nil {
+		teamID := req.TeamID
+		if *teamID == uuid.Nil {
+			teamID = nil
+		}
+		if !h.checkManager(w, r, teamID) {
+			return
+		}
+	}
+
+	template, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post template not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "A template with this name already exists for the content type")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.BadRequest(w, "Invalid team ID")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to update post template", err)
+		return
+	}
+
+	response.OK(w, template)
+}
+
+// Delete godoc
+// @Summary Delete post template
+// @Description Delete a post template; posts created from it are kept. The authenticated user must be able to manage it.
+// @Tags posts
+// @Param id path string true "Template ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/post-templates/{id} [delete]
+func (h *PostTemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid template ID")
+		return
+	}
+	if _, ok := h.getManaged(w, r, id); !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post template not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete post template")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// getManaged loads a template the authenticated user may manage, writing
+// the error response when it cannot
+func (h *PostTemplateHandler) getManaged(w http.ResponseWriter, r *http.Request, id uuid.UUID) (*models.PostTemplate, bool) {
+	template, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post template not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get post template")
+		return nil, false
+	}
+	return template, h.checkManager(w, r, template.TeamID)
+}
+
+// checkManager checks that the authenticated user may manage templates in a
+// team's space, or shared ones for a nil team, writing a 403 when not:
+// shared templates need an editor or admin, team templates an editor or
+// manager of the team (or an admin)
+func (h *PostTemplateHandler) checkManager(w http.ResponseWriter, r *http.Request, teamID *uuid.UUID) bool {
+	user := auth.UserFrom(r.Context())
+	if teamID == nil {
+		if user == nil || user.Role < models.RoleEditor {
+			response.Forbidden(w, "Shared templates can only be managed by editors and admins")
+			return false
+		}
+		return true
+	}
+
+	ok := false
+	if user != nil {
+		var err error
+		if ok, err = h.teamRepo.CanEdit(r.Context(), *teamID, user.ID); err != nil {
+			response.InternalError(w, "Failed to check team membership")
+			return false
+		}
+	}
+	if !ok {
+		response.Forbidden(w, "Only editors and managers of this team can manage its templates")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type PostURLHandler struct {
+	postRepo *repository.ContentPostRepository
+	urls     *permalink.Builder
+}
+
+func NewPostURLHandler(postRepo *repository.ContentPostRepository, urls *permalink.Builder) *PostURLHandler {
+	return &PostURLHandler{postRepo: postRepo, urls: urls}
+}
+
+// Get godoc
+// @Summary Get post URLs
+// @Description Get a post's site path, canonical URL and URL in each of the site's locales, built from the site.url and site.locales settings and its content type's slug pattern. Unpublished posts are dated by their creation.
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse{data=models.PostURLs}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/urls [get]
+func (h *PostURLHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	urls, err := h.urls.Load(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load site URL settings", err)
+		return
+	}
+
+	response.OK(w, urls.PostURLs(post))
+}
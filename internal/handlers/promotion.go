@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/promote"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type PromotionHandler struct {
+	source *promote.Client
+	target *promote.Client
+}
+
+// NewPromotionHandler returns a handler for the configured instances; the
+// endpoints respond with 503 when no target is configured
+func NewPromotionHandler(cfg config.PromoteConfig) *PromotionHandler {
+	h := &PromotionHandler{source: promote.NewClient(cfg.SourceURL, cfg.SourceAPIKey)}
+	if cfg.TargetURL != "" {
+		h.target = promote.NewClient(cfg.TargetURL, cfg.TargetAPIKey)
+	}
+	return h
+}
+
+// Diff godoc
+// @Summary Diff content against the promotion target
+// @Description Compare posts on this instance with the target instance by slug, including missing dependencies
+// @Tags promotion
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/promotion/diff [get]
+func (h *PromotionHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	if h.target == nil {
+		response.Error(w, http.StatusServiceUnavailable, "PROMOTION_DISABLED", "No promotion target is configured")
+		return
+	}
+
+	diff, err := promote.Compare(r.Context(), h.source, h.target)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to diff content", err)
+		return
+	}
+
+	response.OK(w, diff)
+}
+
+// Push godoc
+// @Summary Promote posts to the target
+// @Description Push selected posts to the target instance, creating missing content types, tags and media records
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param body body models.PromoteRequest true "Posts to promote"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/promotion/push [post]
+func (h *PromotionHandler) Push(w http.ResponseWriter, r *http.Request) {
+	if h.target == nil {
+		response.Error(w, http.StatusServiceUnavailable, "PROMOTION_DISABLED", "No promotion target is configured")
+		return
+	}
+
+	var req models.PromoteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if len(req.Slugs) == 0 {
+		response.ValidationError(w, map[string]string{"slugs": "At least one slug is required"})
+		return
+	}
+
+	promoter := promote.NewPromoter(h.source, h.target, promote.Options{AuthorID: req.AuthorID})
+	result, err := promoter.Promote(r.Context(), req.Slugs)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to promote content", err)
+		return
+	}
+
+	response.OK(w, result)
+}
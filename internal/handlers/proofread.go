@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/proofread"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// defaultProofreadFields are checked when the request doesn't choose
+var defaultProofreadFields = []string{models.ProofreadFieldTitle, models.ProofreadFieldExcerpt, models.ProofreadFieldContent}
+
+type ProofreadHandler struct {
+	checker  proofread.Checker
+	postRepo *repository.ContentPostRepository
+	language string
+}
+
+// NewProofreadHandler returns a handler for checker; the endpoint responds
+// with 503 when checker is nil because no driver is configured
+func NewProofreadHandler(checker proofread.Checker, postRepo *repository.ContentPostRepository, language string) *ProofreadHandler {
+	return &ProofreadHandler{checker: checker, postRepo: postRepo, language: language}
+}
+
+// Proofread godoc
+// @Summary Proofread a post
+// @Description Check a post's title, excerpt and content for spelling, grammar and style issues. Issues are positioned in the stored text (markup included) in UTF-16 code units, so admin UIs can underline them.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.ProofreadRequest false "Fields to check and language"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/proofread [post]
+func (h *ProofreadHandler) Proofread(w http.ResponseWriter, r *http.Request) {
+	if h.checker == nil {
+		response.Error(w, http.StatusServiceUnavailable, "PROOFREAD_DISABLED", "No proofreading checker is configured")
+		return
+	}
+
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	// The body is optional
+	var req models.ProofreadRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if len(req.Fields) == 0 {
+		req.Fields = defaultProofreadFields
+	}
+	for _, field := range req.Fields {
+		if field != models.ProofreadFieldTitle && field != models.ProofreadFieldExcerpt && field != models.ProofreadFieldContent {
+			response.ValidationError(w, map[string]string{"fields": "Fields must be title, excerpt or content"})
+			return
+		}
+	}
+	if req.Language == "" {
+		req.Language = h.language
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	result := models.PostProofread{PostID: post.ID, Fields: make(map[string]*models.ProofreadResult)}
+	for _, field := range req.Fields {
+		var text string
+		switch {
+		case field == models.ProofreadFieldTitle:
+			text = post.Title
+		case field == models.ProofreadFieldExcerpt && post.Excerpt != nil:
+			text = *post.Excerpt
+		case field == models.ProofreadFieldContent && post.Content != nil:
+			text = *post.Content
+		}
+		if text == "" || result.Fields[field] != nil {
+			continue
+		}
+
+		checked, err := h.checker.Check(r.Context(), text, req.Language)
+		if err != nil {
+			reqctx.Logf(r.Context(), "[ERROR] Proofreading post %s failed: %v", post.ID, err)
+			response.Error(w, http.StatusBadGateway, "PROOFREAD_FAILED", "The proofreading checker failed to respond")
+			return
+		}
+		result.Fields[field] = checked
+	}
+
+	response.OK(w, result)
+}
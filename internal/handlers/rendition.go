@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/rendition"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// defaultRenditionVariant is the media variant images are rewritten to when
+// the request does not name one
+const defaultRenditionVariant = "medium"
+
+type RenditionHandler struct {
+	postRepo      *repository.ContentPostRepository
+	cacheHintRepo *repository.CacheHintRepository
+}
+
+func NewRenditionHandler(postRepo *repository.ContentPostRepository, cacheHintRepo *repository.CacheHintRepository) *RenditionHandler {
+	return &RenditionHandler{postRepo: postRepo, cacheHintRepo: cacheHintRepo}
+}
+
+// PostRendition godoc
+// @Summary Simplified post rendition
+// @Description Sanitized HTML of a published post (no scripts, constrained markup) for AMP pages or email bodies. Images attached to the post are rewritten to the requested CDN variant.
+// @Tags public
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param format query string false "html (default) or amp"
+// @Param variant query string false "Media variant used for images (default medium)"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/public/posts/{slug}/rendition [get]
+func (h *RenditionHandler) PostRendition(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = rendition.FormatHTML
+	}
+	if format != rendition.FormatHTML && format != rendition.FormatAMP {
+		response.BadRequest(w, "Invalid format (must be html or amp)")
+		return
+	}
+	variant := r.URL.Query().Get("variant")
+	if variant == "" {
+		variant = defaultRenditionVariant
+	}
+
+	post, err := getPublishedPost(r, h.postRepo, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	images := mediaImages(post.Media, variant)
+	content := ""
+	if post.Content != nil {
+		content = *post.Content
+	}
+
+	delivery.SetCacheHeaders(w, r, h.cacheHintRepo, post, "")
+	response.OK(w, models.PostRendition{
+		PostID:  post.ID,
+		Slug:    post.Slug,
+		Title:   post.Title,
+		Format:  format,
+		Variant: variant,
+		HTML: rendition.Render(content, rendition.Options{
+			Format: format,
+			RewriteImage: func(src string) rendition.Image {
+				return images[src]
+			},
+		}),
+	})
+}
+
+// mediaImages indexes a post's image media by every way content may refer to
+// them (CDN URL, object key, file name), resolved to the named variant when
+// the media item has one
+func mediaImages(media []models.PostMedia, variant string) map[string]rendition.Image {
+	images := make(map[string]rendition.Image)
+	for _, pm := range media {
+		m := pm.Media
+		if m == nil || m.FileType != models.FileTypeImage {
+			continue
+		}
+
+		img := rendition.Image{}
+		if m.CDNUrl != nil {
+			img.URL = *m.CDNUrl
+		}
+		var dims struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		}
+		if json.Unmarshal(m.Dimensions, &dims) == nil {
+			img.Width, img.Height = dims.Width, dims.Height
+		}
+		if v, ok := mediaVariant(m.Variants, variant); ok {
+			img = v
+		}
+		if img.URL == "" {
+			continue
+		}
+
+		for _, key := range []string{img.URL, m.ObjectKey, m.FileName} {
+			if key != "" {
+				images[key] = img
+			}
+		}
+		if m.CDNUrl != nil {
+			images[*m.CDNUrl] = img
+		}
+	}
+	return images
+}
+
+// mediaVariant reads a variant from a media item's variants object, which
+// maps variant names either to a URL or to {"url", "width", "height"}
+func mediaVariant(raw json.RawMessage, name string) (rendition.Image, bool) {
+	var variants map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &variants) != nil {
+		return rendition.Image{}, false
+	}
+	entry, ok := variants[name]
+	if !ok {
+		return rendition.Image{}, false
+	}
+
+	var url string
+	if json.Unmarshal(entry, &url) == nil && url != "" {
+		return rendition.Image{URL: url}, true
+	}
+
+	var detailed struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+	if json.Unmarshal(entry, &detailed) == nil && detailed.URL != "" {
+		return rendition.Image{URL: detailed.URL, Width: detailed.Width, Height: detailed.Height}, true
+	}
+	return rendition.Image{}, false
+}
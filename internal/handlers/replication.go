@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/replication"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// backfillPageSize is the number of media records queued per query
+const backfillPageSize = 500
+
+// ReplicationHandler reports and manages media replication. A nil repo
+// means replication is not configured.
+type ReplicationHandler struct {
+	repo     *repository.MediaReplicationRepository
+	failover *replication.Failover
+	maxLag   time.Duration
+}
+
+func NewReplicationHandler(repo *repository.MediaReplicationRepository, failover *replication.Failover, maxLag time.Duration) *ReplicationHandler {
+	return &ReplicationHandler{repo: repo, failover: failover, maxLag: maxLag}
+}
+
+// Stats godoc
+// @Summary Get media replication status
+// @Description Get the number of pending, failed and replicated copies, the replication lag (age of the oldest pending copy) and the CDN failover state. Replication is unhealthy when the lag exceeds REPLICATION_MAX_LAG or a copy failed for good.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/admin/replication [get]
+func (h *ReplicationHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	stats := &models.ReplicationStats{}
+	if h.repo != nil {
+		var err error
+		stats, err = h.repo.Stats(r.Context())
+		if err != nil {
+			response.InternalError(w, "Failed to get replication status")
+			return
+		}
+	}
+
+	stats.MaxLagSeconds = int64(h.maxLag / time.Second)
+	if stats.OldestPendingAt != nil {
+		stats.LagSeconds = int64(time.Since(*stats.OldestPendingAt) / time.Second)
+	}
+	stats.Healthy = stats.Failed == 0 && stats.LagSeconds <= stats.MaxLagSeconds
+	stats.CDN = h.failover.Status()
+
+	response.OK(w, stats)
+}
+
+// Backfill godoc
+// @Summary Queue existing media for replication
+// @Description Queue the files and variants of all media for copying to the replica storage, e.g. after enabling replication. Objects already queued or copied are skipped.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/admin/replication/backfill [post]
+func (h *ReplicationHandler) Backfill(w http.ResponseWriter, r *http.Request) {
+	if h.repo == nil {
+		replicationDisabled(w)
+		return
+	}
+
+	var afterCreatedAt time.Time
+	var afterID uuid.UUID
+	queued := 0
+	for {
+		media, err := h.repo.ListMedia(r.Context(), afterCreatedAt, afterID, backfillPageSize)
+		if err != nil {
+			response.InternalError(w, "Failed to list media")
+			return
+		}
+		for i := range media {
+			if err := h.repo.Enqueue(r.Context(), media[i].ID, replication.ObjectKeys(&media[i])); err != nil {
+				response.InternalError(w, "Failed to queue media for replication")
+				return
+			}
+		}
+		queued += len(media)
+		if len(media) < backfillPageSize {
+			break
+		}
+		afterCreatedAt, afterID = media[len(media)-1].CreatedAt, media[len(media)-1].ID
+	}
+
+	response.OK(w, map[string]int{"media": queued})
+}
+
+// Retry godoc
+// @Summary Retry failed media replication
+// @Description Put every copy that failed for good back in the replication queue
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/admin/replication/retry [post]
+func (h *ReplicationHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	if h.repo == nil {
+		replicationDisabled(w)
+		return
+	}
+
+	retried, err := h.repo.RetryFailed(r.Context())
+	if err != nil {
+		response.InternalError(w, "Failed to retry media replication")
+		return
+	}
+
+	response.OK(w, map[string]int64{"retried": retried})
+}
+
+func replicationDisabled(w http.ResponseWriter) {
+	response.Error(w, http.StatusServiceUnavailable, "REPLICATION_DISABLED", "Media replication is not configured")
+}
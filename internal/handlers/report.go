@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/keeps-dev/go-cms-template/internal/integrity"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type ReportHandler struct {
+	duplicateRepo *repository.DuplicateRepository
+	referenceRepo *repository.ReferenceRepository
+}
+
+func NewReportHandler(duplicateRepo *repository.DuplicateRepository, referenceRepo *repository.ReferenceRepository) *ReportHandler {
+	return &ReportHandler{duplicateRepo: duplicateRepo, referenceRepo: referenceRepo}
+}
+
+// Duplicates godoc
+// @Summary List near-duplicate posts
+// @Description List pairs of live posts with largely the same text, most similar first, as found by the last duplicate scan. Similarity estimates the share of five-word sequences the posts have in common.
+// @Tags reports
+// @Produce json
+// @Param min_similarity query number false "Only pairs at least this similar (0-1)"
+// @Param content_type_id query string false "Only pairs where either post has this content type"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/reports/duplicates [get]
+func (h *ReportHandler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.DuplicateFilter{PaginationParams: parsePaginationParams(r)}
+
+	validationErrors := make(map[string]string)
+	if value := q.Get("min_similarity"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			validationErrors["min_similarity"] = "Must be a number between 0 and 1"
+		} else {
+			filter.MinSimilarity = &threshold
+		}
+	}
+	if value := q.Get("content_type_id"); value != "" {
+		id, err := parseUUID(value)
+		if err != nil {
+			validationErrors["content_type_id"] = "Must be a valid UUID"
+		} else {
+			filter.ContentTypeID = &id
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	pairs, total, err := h.duplicateRepo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list duplicates")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, pairs, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// DanglingReferences godoc
+// @Summary List dangling references
+// @Description List references from post metadata and content to media, posts and tags that no longer exist, as found by the last reference scan. References removed by auto-clean are marked cleaned.
+// @Tags reports
+// @Produce json
+// @Param kind query string false "media, post or tag"
+// @Param source query string false "metadata or content"
+// @Param cleaned query bool false "Only cleaned (true) or remaining (false) references"
+// @Param content_type_id query string false "Only posts of this content type"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/reports/dangling-references [get]
+func (h *ReportHandler) DanglingReferences(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.DanglingReferenceFilter{
+		PaginationParams: parsePaginationParams(r),
+		Kind:             q.Get("kind"),
+		Source:           q.Get("source"),
+		Cleaned:          getBoolParam(r, "cleaned"),
+	}
+
+	validationErrors := make(map[string]string)
+	if filter.Kind != "" && filter.Kind != integrity.KindMedia && filter.Kind != integrity.KindPost && filter.Kind != integrity.KindTag {
+		validationErrors["kind"] = "Must be media, post or tag"
+	}
+	if filter.Source != "" && filter.Source != integrity.SourceMetadata && filter.Source != integrity.SourceContent {
+		validationErrors["source"] = "Must be metadata or content"
+	}
+	if value := q.Get("content_type_id"); value != "" {
+		id, err := parseUUID(value)
+		if err != nil {
+			validationErrors["content_type_id"] = "Must be a valid UUID"
+		} else {
+			filter.ContentTypeID = &id
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	refs, total, err := h.referenceRepo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list dangling references")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, refs, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
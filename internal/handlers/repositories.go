@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// Handlers that take an interface instead of a *repository.X declare here
+// the repository methods they use, so they can run against another
+// implementation. The pgx repositories remain the implementations the
+// router wires in; tests use the moq mocks in mocks_test.go, regenerated
+// with go generate after an interface changes.
+
+//go:generate go run github.com/matryer/moq@v0.5.3 -out mocks_test.go . PostRepository SettingRepository TagRepository
+
+// PostRepository reads and deletes posts for ContentPostHandler and its
+// facet cache. Writes that publish domain events go through
+// service.PostService instead.
+type PostRepository interface {
+	List(ctx context.Context, filter models.PostFilter) ([]models.ContentPost, int64, error)
+	ExportEach(ctx context.Context, filter models.PostFilter, fn func(*models.ContentPost) error) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ContentPost, error)
+	GetBySlugInEnvironment(ctx context.Context, slug, env string) (*models.ContentPost, error)
+	Aggregate(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, error)
+	AggregatePartial(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, bool, error)
+	Facet(ctx context.Context, filter models.PostFilter, field string, limit int) (*models.Facet, error)
+	ForkToDraft(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	AttachMedia(ctx context.Context, postID uuid.UUID, req *models.AttachMediaRequest) (*models.PostMedia, error)
+	DetachMedia(ctx context.Context, postID, mediaID uuid.UUID) error
+	IncrementViewCount(ctx context.Context, id uuid.UUID) error
+}
+
+// SettingRepository stores settings for SettingHandler
+type SettingRepository interface {
+	Create(ctx context.Context, req *models.CreateSettingRequest) (*models.Setting, error)
+	GetByKey(ctx context.Context, key string) (*models.Setting, error)
+	List(ctx context.Context, filter models.SettingFilter) ([]models.Setting, int64, error)
+	Update(ctx context.Context, key string, req *models.UpdateSettingRequest) (*models.Setting, error)
+	Upsert(ctx context.Context, req *models.CreateSettingRequest) (*models.Setting, error)
+	Delete(ctx context.Context, key string) error
+	ListByPrefix(ctx context.Context, prefixes []string) ([]models.Setting, error)
+	Import(ctx context.Context, upserts []models.SettingEntry, deletes []string) error
+	GetMultiple(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// TagRepository stores tags for TagHandler
+type TagRepository interface {
+	Create(ctx context.Context, req *models.CreateTagRequest) (*models.Tag, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	List(ctx context.Context, filter models.TagFilter) ([]models.Tag, int64, error)
+	ExportEach(ctx context.Context, filter models.TagFilter, fn func(*models.Tag, int64) error) error
+	Update(ctx context.Context, id uuid.UUID, req *models.UpdateTagRequest) (*models.Tag, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+var (
+	_ PostRepository    = (*repository.ContentPostRepository)(nil)
+	_ SettingRepository = (*repository.SettingRepository)(nil)
+	_ TagRepository     = (*repository.TagRepository)(nil)
+)
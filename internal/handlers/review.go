@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Content review limits: review periods in days and note length
+const (
+	maxReviewAfterDays = 3650
+	maxReviewNote      = 2000
+)
+
+type ReviewHandler struct {
+	repo        *repository.ReviewRepository
+	postRepo    *repository.ContentPostRepository
+	teamRepo    *repository.TeamRepository
+	defaultDays int
+}
+
+// NewReviewHandler creates a content review handler; defaultDays is the
+// review period of content types without a policy
+func NewReviewHandler(repo *repository.ReviewRepository, postRepo *repository.ContentPostRepository, teamRepo *repository.TeamRepository, defaultDays int) *ReviewHandler {
+	return &ReviewHandler{repo: repo, postRepo: postRepo, teamRepo: teamRepo, defaultDays: defaultDays}
+}
+
+// GetPolicy godoc
+// @Summary Get content review policy
+// @Description Get after how many days without an edit or acknowledgment published posts of a content type are due for review
+// @Tags content-types
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Success 200 {object} response.APIResponse{data=models.ContentReviewPolicy}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/review-policy [get]
+func (h *ReviewHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	policy, err := h.repo.GetPolicy(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Review policy not found")
+			return
+		}
+		response.InternalError(w, "Failed to get review policy")
+		return
+	}
+
+	response.OK(w, policy)
+}
+
+// SetPolicy godoc
+// @Summary Set content review policy
+// @Description Set after how many days without an edit or acknowledgment published posts of a content type are due for review, instead of the STALE_CONTENT_DAYS default
+// @Tags content-types
+// @Accept json
+// @Produce json
+// @Param id path string true "Content type ID"
+// @Param body body models.SetReviewPolicyRequest true "Review period"
+// @Success 200 {object} response.APIResponse{data=models.ContentReviewPolicy}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/review-policy [put]
+func (h *ReviewHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	var req models.SetReviewPolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if req.ReviewAfterDays < 1 || req.ReviewAfterDays > maxReviewAfterDays {
+		response.ValidationError(w, map[string]string{"review_after_days": "Must be between 1 and 3650"})
+		return
+	}
+
+	policy, err := h.repo.SetPolicy(r.Context(), id, req.ReviewAfterDays)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Content type not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to set review policy", err)
+		return
+	}
+
+	response.OK(w, policy)
+}
+
+// DeletePolicy godoc
+// @Summary Delete content review policy
+// @Description Remove a content type's review policy, so its posts fall back to the STALE_CONTENT_DAYS default
+// @Tags content-types
+// @Param id path string true "Content type ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/content-types/{id}/review-policy [delete]
+func (h *ReviewHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid content type ID")
+		return
+	}
+
+	if err := h.repo.DeletePolicy(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Review policy not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete review policy")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// StaleContent godoc
+// @Summary List stale content
+// @Description List published live posts neither edited nor acknowledged as still fresh within their content type's review period (STALE_CONTENT_DAYS without a policy), longest unreviewed first
+// @Tags reports
+// @Produce json
+// @Param days query int false "Review period in days for every content type, overriding their policies"
+// @Param content_type_id query string false "Only posts of this content type"
+// @Param team_id query string false "Only posts of this team"
+// @Param reminded query bool false "Only posts already reminded of (true) or not yet (false)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse{data=[]models.StalePost}
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/reports/stale-content [get]
+func (h *ReviewHandler) StaleContent(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.StaleContentFilter{
+		PaginationParams: parsePaginationParams(r),
+		DefaultDays:      h.defaultDays,
+		Reminded:         getBoolParam(r, "reminded"),
+	}
+
+	validationErrors := make(map[string]string)
+	if value := q.Get("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxReviewAfterDays {
+			validationErrors["days"] = "Must be between 1 and 3650"
+		} else {
+			filter.Days = &days
+		}
+	}
+	if value := q.Get("content_type_id"); value != "" {
+		id, err := parseUUID(value)
+		if err != nil {
+			validationErrors["content_type_id"] = "Must be a valid UUID"
+		} else {
+			filter.ContentTypeID = &id
+		}
+	}
+	if value := q.Get("team_id"); value != "" {
+		id, err := parseUUID(value)
+		if err != nil {
+			validationErrors["team_id"] = "Must be a valid UUID"
+		} else {
+			filter.TeamID = &id
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	posts, total, err := h.repo.ListStale(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list stale content")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, posts, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Acknowledge godoc
+// @Summary Acknowledge content review
+// @Description Confirm a post is still fresh without editing it, which restarts its review period. The authenticated user is recorded as the reviewer and must be able to edit the post: an editor or manager of its team, or for posts outside a team an editor or admin.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.AcknowledgeReviewRequest true "Note"
+// @Success 200 {object} response.APIResponse{data=models.PostReview}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/reports/stale-content/{id}/acknowledge [post]
+func (h *ReviewHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	user, ok := actingUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.AcknowledgeReviewRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		if len(note) > maxReviewNote {
+			validationErrors["note"] = "Note must be at most 2000 characters"
+		}
+		req.Note = &note
+	}
+	if post.Status != models.PostStatusPublished || post.Environment != models.EnvironmentLive {
+		validationErrors["post"] = "Only published live posts are reviewed"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if post.TeamID != nil {
+		ok, err := h.teamRepo.CanEdit(r.Context(), *post.TeamID, user.ID)
+		if err != nil {
+			response.InternalError(w, "Failed to check reviewer permissions")
+			return
+		}
+		if !ok {
+			response.Forbidden(w, "Only editors and managers of the post's team can review it")
+			return
+		}
+	} else if user.Role < models.RoleEditor {
+		response.Forbidden(w, "Only editors and admins can review posts")
+		return
+	}
+
+	review, err := h.repo.Acknowledge(r.Context(), post.ID, &user.ID, req.Note)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to acknowledge review", err)
+		return
+	}
+
+	response.OK(w, review)
+}
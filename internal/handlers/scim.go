@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/scim"
+)
+
+// unusablePasswordHash is stored for provisioned users, who sign in through
+// their identity provider; it never matches a password
+const unusablePasswordHash = "!"
+
+// SCIMHandler serves the SCIM 2.0 Users endpoint identity providers use to
+// provision and deprovision CMS users
+type SCIMHandler struct {
+	repo      *repository.UserRepository
+	audit     *repository.AccessLogRepository
+	token     string
+	roles     scim.RoleMap
+	publicURL string
+}
+
+func NewSCIMHandler(repo *repository.UserRepository, audit *repository.AccessLogRepository, token string, groupRoles []string, publicURL string) (*SCIMHandler, error) {
+	roles, err := scim.ParseRoleMap(groupRoles)
+	if err != nil {
+		return nil, err
+	}
+	return &SCIMHandler{
+		repo:      repo,
+		audit:     audit,
+		token:     token,
+		roles:     roles,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}, nil
+}
+
+// Authenticate rejects requests without the configured bearer token
+func (h *SCIMHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+			scim.WriteError(w, scim.NewError(http.StatusUnauthorized, "", "A valid bearer token is required"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServiceProviderConfig godoc
+// @Summary SCIM service provider configuration
+// @Description Describe the SCIM features supported: PATCH and userName filters, no bulk, sorting, ETags or password changes
+// @Tags scim
+// @Produce json
+// @Success 200 {object} object
+// @Failure 401 {object} scim.Error
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *SCIMHandler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(ok bool) map[string]bool { return map[string]bool{"supported": ok} }
+	scim.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": scim.MaxResults},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The token set in SCIM_TOKEN",
+		}},
+	})
+}
+
+// ListUsers godoc
+// @Summary List provisioned users
+// @Description List users in creation order, or look one up with filter=userName eq "email"
+// @Tags scim
+// @Produce json
+// @Param filter query string false "userName eq \"email\""
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (max 100)"
+// @Success 200 {object} scim.ListResponse
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	list := &scim.ListResponse{
+		Schemas:    []string{scim.SchemaListResponse},
+		StartIndex: 1,
+		Resources:  []scim.User{},
+	}
+
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		email, err := scim.ParseFilter(filter)
+		if err != nil {
+			scim.WriteError(w, err)
+			return
+		}
+		user, err := h.repo.GetByEmail(r.Context(), email)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			scim.WriteError(w, err)
+			return
+		}
+		if user != nil {
+			list.Resources = append(list.Resources, *scim.FromModel(user, h.location()))
+		}
+		list.TotalResults = int64(len(list.Resources))
+		list.ItemsPerPage = len(list.Resources)
+		scim.WriteJSON(w, http.StatusOK, list)
+		return
+	}
+
+	if start, err := strconv.Atoi(r.URL.Query().Get("startIndex")); err == nil && start > 1 {
+		list.StartIndex = start
+	}
+	count := scim.MaxResults
+	if c, err := strconv.Atoi(r.URL.Query().Get("count")); err == nil && c >= 0 && c < count {
+		count = c
+	}
+
+	users, total, err := h.repo.ListRange(r.Context(), list.StartIndex-1, count)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+	for i := range users {
+		list.Resources = append(list.Resources, *scim.FromModel(&users[i], h.location()))
+	}
+	list.TotalResults = total
+	list.ItemsPerPage = len(list.Resources)
+	scim.WriteJSON(w, http.StatusOK, list)
+}
+
+// GetUser godoc
+// @Summary Get a provisioned user
+// @Tags scim
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} scim.User
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.user(r)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+	scim.WriteJSON(w, http.StatusOK, scim.FromModel(user, h.location()))
+}
+
+// CreateUser godoc
+// @Summary Provision a user
+// @Description Create a CMS user from a SCIM User. userName must be the user's email address. The CMS role is mapped from roles and groups with SCIM_GROUP_ROLES.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param body body scim.User true "SCIM User"
+// @Success 201 {object} scim.User
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Failure 409 {object} scim.Error
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var in scim.User
+	if err := decodeJSON(r, &in); err != nil {
+		scim.WriteError(w, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Invalid request body"))
+		return
+	}
+	if err := in.Validate(); err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	user := &models.User{
+		Email:        in.UserName,
+		PasswordHash: unusablePasswordHash,
+		FullName:     in.FullName(),
+		Role:         models.RoleUser,
+		IsActive:     in.IsActive(),
+	}
+	if len(h.roles) > 0 {
+		user.Role = h.roles.Resolve(&in)
+	}
+
+	if err := h.repo.Create(r.Context(), user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			scim.WriteError(w, scim.NewError(http.StatusConflict, "uniqueness", "A user with this userName already exists"))
+			return
+		}
+		scim.WriteError(w, err)
+		return
+	}
+
+	h.record(r, models.AccessActionProvision, user)
+	w.Header().Set("Location", h.location()+"/"+user.ID.String())
+	scim.WriteJSON(w, http.StatusCreated, scim.FromModel(user, h.location()))
+}
+
+// ReplaceUser godoc
+// @Summary Replace a provisioned user
+// @Description Replace a user's userName, name and active flag. The CMS role is re-mapped only when roles or groups are sent.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body scim.User true "SCIM User"
+// @Success 200 {object} scim.User
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Failure 409 {object} scim.Error
+// @Router /scim/v2/Users/{id} [put]
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.user(r)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	var in scim.User
+	if err := decodeJSON(r, &in); err != nil {
+		scim.WriteError(w, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Invalid request body"))
+		return
+	}
+
+	h.save(w, r, user, &in, in.Roles != nil || in.Groups != nil)
+}
+
+// PatchUser godoc
+// @Summary Update a provisioned user
+// @Description Apply SCIM PATCH operations. Identity providers deactivate users with replace active=false.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body scim.PatchRequest true "PATCH operations"
+// @Success 200 {object} scim.User
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Failure 409 {object} scim.Error
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.user(r)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	var req scim.PatchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		scim.WriteError(w, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Invalid request body"))
+		return
+	}
+
+	in := scim.FromModel(user, h.location())
+	rolesChanged, err := in.Apply(req.Operations)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	h.save(w, r, user, in, rolesChanged)
+}
+
+// DeleteUser godoc
+// @Summary Deprovision a user
+// @Description Deactivate a user. Users are kept because posts reference their authors; a deprovisioned user reads back with active=false.
+// @Tags scim
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Router /scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.user(r)
+	if err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	if user.IsActive {
+		user.IsActive = false
+		if err := h.repo.Update(r.Context(), user); err != nil {
+			scim.WriteError(w, err)
+			return
+		}
+		h.record(r, models.AccessActionDeactivate, user)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// save applies in to user and stores it, re-mapping the role when
+// rolesChanged and group mappings are configured
+func (h *SCIMHandler) save(w http.ResponseWriter, r *http.Request, user *models.User, in *scim.User, rolesChanged bool) {
+	if err := in.Validate(); err != nil {
+		scim.WriteError(w, err)
+		return
+	}
+
+	wasActive := user.IsActive
+	user.Email = in.UserName
+	user.FullName = in.FullName()
+	user.IsActive = in.IsActive()
+	if rolesChanged && len(h.roles) > 0 {
+		user.Role = h.roles.Resolve(in)
+	}
+
+	if err := h.repo.Update(r.Context(), user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			scim.WriteError(w, scim.NewError(http.StatusConflict, "uniqueness", "A user with this userName already exists"))
+			return
+		}
+		scim.WriteError(w, err)
+		return
+	}
+
+	action := models.AccessActionUpdate
+	if wasActive && !user.IsActive {
+		action = models.AccessActionDeactivate
+	}
+	h.record(r, action, user)
+	scim.WriteJSON(w, http.StatusOK, scim.FromModel(user, h.location()))
+}
+
+// user loads the user named by the id path parameter
+func (h *SCIMHandler) user(r *http.Request) (*models.User, error) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, scim.NewError(http.StatusNotFound, "", "User not found")
+	}
+	user, err := h.repo.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, scim.NewError(http.StatusNotFound, "", "User not found")
+	}
+	return user, err
+}
+
+// location returns the URL of the Users endpoint
+func (h *SCIMHandler) location() string {
+	return h.publicURL + "/scim/v2/Users"
+}
+
+// record adds a provisioning event to the access log. Failures are logged
+// and never fail the request.
+func (h *SCIMHandler) record(r *http.Request, action string, user *models.User) {
+	info := reqctx.From(r.Context())
+	summary := fmt.Sprintf("email=%s role=%d active=%t", user.Email, user.Role, user.IsActive)
+	entry := models.AccessLogEntry{
+		Resource:   models.AccessResourceUser,
+		ResourceID: &user.ID,
+		Action:     action,
+		Client:     "scim",
+		IPAddress:  clientIP(r),
+		Query:      &summary,
+	}
+	if info.RequestID != "" {
+		entry.RequestID = &info.RequestID
+	}
+
+	if err := h.audit.Record(r.Context(), []models.AccessLogEntry{entry}); err != nil {
+		reqctx.Logf(r.Context(), "[ERROR] %v", err)
+	}
+	reqctx.Logf(r.Context(), "SCIM %s of user %s (%s)", action, user.ID, summary)
+}
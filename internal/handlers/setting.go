@@ -1,23 +1,83 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
 	"github.com/keeps-dev/go-cms-template/internal/repository"
 	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/social"
 )
 
 type SettingHandler struct {
-	repo *repository.SettingRepository
+	repo SettingRepository
 }
 
-func NewSettingHandler(repo *repository.SettingRepository) *SettingHandler {
+func NewSettingHandler(repo SettingRepository) *SettingHandler {
 	return &SettingHandler{repo: repo}
 }
 
+// secretSetting masks the secrets kept in a JSON setting. Values are
+// masked in every response; unmask puts the stored secrets back into a
+// written value that still holds masks, so a setting read from the API can
+// be saved again.
+type secretSetting struct {
+	mask   func(value string) string
+	unmask func(value, stored string) string
+}
+
+// secretSettings are the settings holding credentials, by key
+var secretSettings = map[string]secretSetting{
+	notify.SettingChannels: {notify.MaskChannels, notify.UnmaskChannels},
+	social.SettingAccounts: {social.MaskAccounts, social.UnmaskAccounts},
+}
+
+// maskSettings masks the secrets in settings in place
+func maskSettings(settings ...*models.Setting) {
+	for _, s := range settings {
+		if secret, ok := secretSettings[s.Key]; ok && s.Value != nil {
+			value := secret.mask(*s.Value)
+			s.Value = &value
+		}
+	}
+}
+
+// unmaskValue puts the secrets of stored back into value, a new value of
+// the setting key
+func unmaskValue(key string, value *string, stored *string) *string {
+	secret, ok := secretSettings[key]
+	if !ok || value == nil || stored == nil {
+		return value
+	}
+	unmasked := secret.unmask(*value, *stored)
+	return &unmasked
+}
+
+// storedValue returns the current value of a secret setting, or nil for
+// other settings and ones not set yet
+func (h *SettingHandler) storedValue(r *http.Request, key string) (*string, error) {
+	if _, ok := secretSettings[key]; !ok {
+		return nil, nil
+	}
+	setting, err := h.repo.GetByKey(r.Context(), key)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return setting.Value, nil
+}
+
 // List godoc
 // @Summary List settings
 // @Description Get all settings with optional search
@@ -39,6 +99,9 @@ func (h *SettingHandler) List(w http.ResponseWriter, r *http.Request) {
 		response.InternalError(w, "Failed to list settings")
 		return
 	}
+	for i := range settings {
+		maskSettings(&settings[i])
+	}
 
 	response.JSONWithMeta(w, http.StatusOK, settings, &response.Meta{
 		Page:       filter.Page,
@@ -74,6 +137,7 @@ func (h *SettingHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maskSettings(setting)
 	response.OK(w, setting)
 }
 
@@ -113,6 +177,7 @@ func (h *SettingHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maskSettings(setting)
 	response.Created(w, setting)
 }
 
@@ -141,6 +206,13 @@ func (h *SettingHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stored, err := h.storedValue(r, key)
+	if err != nil {
+		response.InternalError(w, "Failed to update setting")
+		return
+	}
+	req.Value = unmaskValue(key, req.Value, stored)
+
 	setting, err := h.repo.Update(r.Context(), key, &req)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -151,6 +223,7 @@ func (h *SettingHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	maskSettings(setting)
 	response.OK(w, setting)
 }
 
@@ -178,12 +251,20 @@ func (h *SettingHandler) Upsert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stored, err := h.storedValue(r, req.Key)
+	if err != nil {
+		response.InternalError(w, "Failed to upsert setting")
+		return
+	}
+	req.Value = unmaskValue(req.Key, req.Value, stored)
+
 	setting, err := h.repo.Upsert(r.Context(), &req)
 	if err != nil {
 		response.InternalError(w, "Failed to upsert setting")
 		return
 	}
 
+	maskSettings(setting)
 	response.OK(w, setting)
 }
 
@@ -242,6 +323,204 @@ func (h *SettingHandler) GetMultiple(w http.ResponseWriter, r *http.Request) {
 		response.InternalError(w, "Failed to get settings")
 		return
 	}
+	for key, value := range settings {
+		if secret, ok := secretSettings[key]; ok {
+			settings[key] = secret.mask(value)
+		}
+	}
 
 	response.OK(w, settings)
 }
+
+// Export godoc
+// @Summary Export settings
+// @Description Download the settings table, or the given groups of it, as one JSON document for POST /api/v1/settings/import. Secrets such as access tokens and webhook URLs are masked. A setting's group is the part of its key before the first dot.
+// @Tags settings
+// @Produce json
+// @Param group query string false "Comma-separated groups to export (e.g. site,seo)"
+// @Success 200 {object} models.SettingsDocument
+// @Router /api/v1/settings/export [get]
+func (h *SettingHandler) Export(w http.ResponseWriter, r *http.Request) {
+	groups := splitList(r.URL.Query().Get("group"))
+	settings, err := h.repo.ListByPrefix(r.Context(), groupPrefixes(groups))
+	if err != nil {
+		response.InternalError(w, "Failed to export settings")
+		return
+	}
+
+	doc := models.SettingsDocument{
+		ExportedAt: time.Now().UTC(),
+		Groups:     groups,
+		Settings:   make([]models.SettingEntry, 0, len(settings)),
+	}
+	for _, s := range settings {
+		maskSettings(&s)
+		doc.Settings = append(doc.Settings, models.SettingEntry{Key: s.Key, Value: s.Value, Description: s.Description})
+	}
+
+	filename := fmt.Sprintf("settings-%s.json", doc.ExportedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
+
+// Import godoc
+// @Summary Import settings
+// @Description Apply a document from GET /api/v1/settings/export: missing settings are created and differing ones overwritten, all in one transaction. Only the document's groups (or the group parameter) are touched; settings in them that the document lacks are deleted with prune=true. With dry_run=true the changes are listed but not made.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param body body models.SettingsDocument true "Settings document"
+// @Param group query string false "Comma-separated groups to import, overriding the document's"
+// @Param dry_run query bool false "Only list the changes"
+// @Param prune query bool false "Delete settings missing from the document"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/settings/import [post]
+func (h *SettingHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var doc models.SettingsDocument
+	if err := decodeJSON(r, &doc); err != nil {
+		response.BadRequest(w, "Invalid settings document")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	seen := make(map[string]bool, len(doc.Settings))
+	for _, s := range doc.Settings {
+		switch {
+		case s.Key == "":
+			validationErrors["settings"] = "Every setting needs a key"
+		case len(s.Key) > 100:
+			validationErrors["settings"] = "Key is too long: " + s.Key
+		case seen[s.Key]:
+			validationErrors["settings"] = "Duplicate key: " + s.Key
+		}
+		seen[s.Key] = true
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	groups := splitList(r.URL.Query().Get("group"))
+	if len(groups) == 0 {
+		groups = doc.Groups
+	}
+	current, err := h.repo.ListByPrefix(r.Context(), groupPrefixes(groups))
+	if err != nil {
+		response.InternalError(w, "Failed to list settings")
+		return
+	}
+
+	prune := getBoolParam(r, "prune")
+	result, upserts, deletes := planSettingsImport(current, doc.Settings, groups, prune != nil && *prune)
+	if dryRun := getBoolParam(r, "dry_run"); dryRun != nil && *dryRun {
+		result.DryRun = true
+		response.OK(w, result)
+		return
+	}
+
+	if err := h.repo.Import(r.Context(), upserts, deletes); err != nil {
+		response.InternalErrorWithErr(w, "Failed to import settings", err)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+// groupPrefixes turns setting groups into key prefixes; no groups means
+// every setting
+func groupPrefixes(groups []string) []string {
+	if len(groups) == 0 {
+		return []string{""}
+	}
+	prefixes := make([]string, len(groups))
+	for i, g := range groups {
+		prefixes[i] = g + "."
+	}
+	return prefixes
+}
+
+// inGroups reports whether key belongs to one of groups (any, when empty).
+// Keys without a dot belong to no group.
+func inGroups(key string, groups []string) bool {
+	if len(groups) == 0 {
+		return true
+	}
+	group, _, ok := strings.Cut(key, ".")
+	return ok && slices.Contains(groups, group)
+}
+
+// planSettingsImport compares the document's entries with the current
+// settings of the imported groups, returning the changes, the entries to
+// write and the keys to delete
+func planSettingsImport(current []models.Setting, entries []models.SettingEntry, groups []string, prune bool) (*models.SettingsImportResult, []models.SettingEntry, []string) {
+	result := &models.SettingsImportResult{
+		Changes: []models.SettingImportChange{},
+		Summary: make(map[models.SettingImportAction]int),
+	}
+	var upserts []models.SettingEntry
+	var deletes []string
+	add := func(change models.SettingImportChange) {
+		result.Changes = append(result.Changes, change)
+		result.Summary[change.Action]++
+	}
+
+	byKey := make(map[string]models.Setting, len(current))
+	for _, s := range current {
+		byKey[s.Key] = s
+	}
+	inDoc := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		inDoc[e.Key] = true
+		if !inGroups(e.Key, groups) {
+			add(models.SettingImportChange{Key: e.Key, Action: models.SettingImportSkipped})
+			continue
+		}
+		existing, ok := byKey[e.Key]
+		if !ok {
+			add(models.SettingImportChange{Key: e.Key, Action: models.SettingImportCreate})
+			upserts = append(upserts, e)
+			continue
+		}
+		// Exports mask secrets, so re-importing one keeps the stored ones
+		e.Value = unmaskValue(e.Key, e.Value, existing.Value)
+		var changes []string
+		if !equalStringPtr(existing.Value, e.Value) {
+			changes = append(changes, "value")
+		}
+		if !equalStringPtr(existing.Description, e.Description) {
+			changes = append(changes, "description")
+		}
+		if len(changes) == 0 {
+			add(models.SettingImportChange{Key: e.Key, Action: models.SettingImportUnchanged})
+			continue
+		}
+		add(models.SettingImportChange{Key: e.Key, Action: models.SettingImportUpdate, Changes: changes})
+		upserts = append(upserts, e)
+	}
+
+	for _, s := range current {
+		if inDoc[s.Key] {
+			continue
+		}
+		if prune {
+			add(models.SettingImportChange{Key: s.Key, Action: models.SettingImportDelete})
+			deletes = append(deletes, s.Key)
+		} else {
+			add(models.SettingImportChange{Key: s.Key, Action: models.SettingImportServerOnly})
+		}
+	}
+
+	sort.Slice(result.Changes, func(i, j int) bool { return result.Changes[i].Key < result.Changes[j].Key })
+	return result, upserts, deletes
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/social"
+)
+
+// settingRouter mounts SettingHandler the way router.New does
+func settingRouter(repo SettingRepository) http.Handler {
+	h := NewSettingHandler(repo)
+	r := chi.NewRouter()
+	r.Route("/settings", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Post("/upsert", h.Upsert)
+		r.Post("/bulk", h.GetMultiple)
+		r.Get("/export", h.Export)
+		r.Post("/import", h.Import)
+		r.Get("/{key}", h.Get)
+		r.Put("/{key}", h.Update)
+		r.Delete("/{key}", h.Delete)
+	})
+	return r
+}
+
+func testSetting(key, value string) models.Setting {
+	return models.Setting{ID: uuid.New(), Key: key, Value: &value, UpdatedAt: time.Now()}
+}
+
+const (
+	testAccounts = `[{"name":"x","driver":"x","access_token":"secret-x-token"}]`
+	testChannels = `[{"name":"team","driver":"slack","webhook_url":"https://hooks.slack.com/services/secret-hook"},` +
+		`{"name":"alerts","driver":"telegram","bot_token":"secret-bot-token","chat_id":"-100"}]`
+)
+
+func TestSettingHandlerMasksSecrets(t *testing.T) {
+	router := settingRouter(newSettingRepository(
+		testSetting(social.SettingAccounts, testAccounts),
+		testSetting(notify.SettingChannels, testChannels),
+	))
+
+	tests := []struct {
+		method, target, body string
+	}{
+		{http.MethodGet, "/settings/" + social.SettingAccounts, ""},
+		{http.MethodGet, "/settings/" + notify.SettingChannels, ""},
+		{http.MethodGet, "/settings", ""},
+		{http.MethodPost, "/settings/bulk", `["` + social.SettingAccounts + `","` + notify.SettingChannels + `"]`},
+		{http.MethodGet, "/settings/export", ""},
+		{http.MethodPost, "/settings/upsert", `{"key":"` + social.SettingAccounts + `","value":"[{\"name\":\"x\",\"driver\":\"x\",\"access_token\":\"********\"}]"}`},
+	}
+	for _, tt := range tests {
+		var req *http.Request
+		if tt.body == "" {
+			req = httptest.NewRequest(tt.method, tt.target, nil)
+		} else {
+			req = httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s %s = %d, want 200", tt.method, tt.target, rec.Code)
+			continue
+		}
+		body := rec.Body.String()
+		for _, secret := range []string{"secret-x-token", "secret-hook", "secret-bot-token"} {
+			if strings.Contains(body, secret) {
+				t.Errorf("%s %s echoes %s: %s", tt.method, tt.target, secret, body)
+			}
+		}
+		if !strings.Contains(body, "********") {
+			t.Errorf("%s %s = %s, want the masked account", tt.method, tt.target, body)
+		}
+	}
+}
+
+func TestSettingHandlerKeepsMaskedSecrets(t *testing.T) {
+	repo := newSettingRepository(testSetting(social.SettingAccounts, testAccounts))
+	router := settingRouter(repo)
+
+	// Saving the masked value read from the API keeps the stored token
+	status, resp := serve(t, router, http.MethodGet, "/settings/"+social.SettingAccounts, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	var setting models.Setting
+	resp.decode(t, &setting)
+	body := `{"value":` + strconv.Quote(*setting.Value) + `}`
+	if status, _ := serve(t, router, http.MethodPut, "/settings/"+social.SettingAccounts, body); status != http.StatusOK {
+		t.Fatalf("update = %d, want 200", status)
+	}
+	if got := storedValue(t, repo, social.SettingAccounts); !strings.Contains(got, "secret-x-token") {
+		t.Errorf("stored value = %s, want the original token kept", got)
+	}
+
+	// A new token replaces it
+	body = `{"value":` + strconv.Quote(`[{"name":"x","driver":"x","access_token":"new-token"}]`) + `}`
+	serve(t, router, http.MethodPut, "/settings/"+social.SettingAccounts, body)
+	if got := storedValue(t, repo, social.SettingAccounts); !strings.Contains(got, "new-token") {
+		t.Errorf("stored value = %s, want the new token", got)
+	}
+}
+
+// storedValue returns the value repo holds for key
+func storedValue(t *testing.T, repo SettingRepository, key string) string {
+	t.Helper()
+	setting, err := repo.GetByKey(context.Background(), key)
+	if err != nil || setting.Value == nil {
+		t.Fatalf("setting %s = %+v, %v", key, setting, err)
+	}
+	return *setting.Value
+}
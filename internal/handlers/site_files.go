@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// Setting keys holding the full contents of the well-known text files
+const (
+	SettingRobotsTxt   = "site_files.robots_txt"
+	SettingSecurityTxt = "site_files.security_txt"
+)
+
+// Outside production crawlers are kept away from the whole site so staging
+// instances do not get indexed
+const (
+	defaultRobotsTxtProduction = "User-agent: *\nDisallow: /api/\n"
+	defaultRobotsTxtOther      = "User-agent: *\nDisallow: /\n"
+)
+
+type SiteFilesHandler struct {
+	settingRepo *repository.SettingRepository
+	production  bool
+}
+
+func NewSiteFilesHandler(settingRepo *repository.SettingRepository, production bool) *SiteFilesHandler {
+	return &SiteFilesHandler{settingRepo: settingRepo, production: production}
+}
+
+// RobotsTxt godoc
+// @Summary robots.txt
+// @Description Serve robots.txt from the site_files.robots_txt setting. Without the setting production allows everything but /api/ and other environments disallow all crawling.
+// @Tags site-files
+// @Produce plain
+// @Success 200 {string} string
+// @Router /robots.txt [get]
+func (h *SiteFilesHandler) RobotsTxt(w http.ResponseWriter, r *http.Request) {
+	body := h.setting(r, SettingRobotsTxt)
+	if body == "" {
+		body = defaultRobotsTxtOther
+		if h.production {
+			body = defaultRobotsTxtProduction
+		}
+	}
+
+	writeTextFile(w, body, "public, max-age=3600")
+}
+
+// SecurityTxt godoc
+// @Summary security.txt
+// @Description Serve the RFC 9116 security.txt from the site_files.security_txt setting
+// @Tags site-files
+// @Produce plain
+// @Success 200 {string} string
+// @Failure 404 {string} string
+// @Router /.well-known/security.txt [get]
+func (h *SiteFilesHandler) SecurityTxt(w http.ResponseWriter, r *http.Request) {
+	body := h.setting(r, SettingSecurityTxt)
+	if body == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeTextFile(w, body, "public, max-age=86400")
+}
+
+// setting reads a file setting, treating a missing setting or an unavailable
+// database as unset
+func (h *SiteFilesHandler) setting(r *http.Request, key string) string {
+	settings, err := h.settingRepo.GetMultiple(r.Context(), []string{key})
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(settings[key])
+}
+
+func writeTextFile(w http.ResponseWriter, body, cacheControl string) {
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/imaging"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/web"
+)
+
+// Setting keys used to build the web app manifest and icons. The site name
+// is shared with the public site (site.name).
+const (
+	SettingSiteShortName       = "site.short_name"
+	SettingSiteThemeColor      = "site.theme_color"
+	SettingSiteBackgroundColor = "site.background_color"
+	SettingSiteIconMediaID     = "site.icon_media_id"
+)
+
+// maxIconSourceSize bounds the source image downloaded for icon generation
+const maxIconSourceSize = 10 << 20
+
+// iconSizes are the square sizes served under /icons/icon-{size}.png
+var iconSizes = map[int]bool{16: true, 32: true, 48: true, 180: true, 192: true, 512: true}
+
+type SiteIconHandler struct {
+	settingRepo *repository.SettingRepository
+	mediaRepo   *repository.MediaRepository
+	client      *http.Client
+
+	// Generated icons keyed by source media, checksum and size
+	mu    sync.Mutex
+	icons map[string][]byte
+}
+
+func NewSiteIconHandler(settingRepo *repository.SettingRepository, mediaRepo *repository.MediaRepository) *SiteIconHandler {
+	return &SiteIconHandler{
+		settingRepo: settingRepo,
+		mediaRepo:   mediaRepo,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		icons:       make(map[string][]byte),
+	}
+}
+
+type manifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+type webManifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name,omitempty"`
+	StartURL        string         `json:"start_url"`
+	Display         string         `json:"display"`
+	ThemeColor      string         `json:"theme_color,omitempty"`
+	BackgroundColor string         `json:"background_color,omitempty"`
+	Icons           []manifestIcon `json:"icons"`
+}
+
+// Manifest godoc
+// @Summary Web app manifest
+// @Description Web app manifest built from the site.* settings, with icons generated from the site.icon_media_id media item
+// @Tags site-files
+// @Produce json
+// @Success 200 {object} webManifest
+// @Router /site.webmanifest [get]
+func (h *SiteIconHandler) Manifest(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.settingRepo.GetMultiple(r.Context(), []string{
+		web.SettingSiteName, SettingSiteShortName, SettingSiteThemeColor,
+		SettingSiteBackgroundColor, SettingSiteIconMediaID,
+	})
+	if err != nil {
+		settings = map[string]string{}
+	}
+
+	manifest := webManifest{
+		Name:            settings[web.SettingSiteName],
+		ShortName:       settings[SettingSiteShortName],
+		StartURL:        "/",
+		Display:         "standalone",
+		ThemeColor:      settings[SettingSiteThemeColor],
+		BackgroundColor: settings[SettingSiteBackgroundColor],
+		Icons:           []manifestIcon{},
+	}
+	if manifest.Name == "" {
+		manifest.Name = "CMS"
+	}
+	if settings[SettingSiteIconMediaID] != "" {
+		for _, size := range []int{192, 512} {
+			manifest.Icons = append(manifest.Icons, manifestIcon{
+				Src:   fmt.Sprintf("/icons/icon-%d.png", size),
+				Sizes: fmt.Sprintf("%dx%d", size, size),
+				Type:  "image/png",
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/manifest+json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(manifest)
+}
+
+// Favicon godoc
+// @Summary Favicon
+// @Description 32x32 PNG favicon generated from the site.icon_media_id media item
+// @Tags site-files
+// @Produce png
+// @Success 200
+// @Failure 404
+// @Router /favicon.ico [get]
+func (h *SiteIconHandler) Favicon(w http.ResponseWriter, r *http.Request) {
+	h.serveIcon(w, r, 32)
+}
+
+// AppleTouchIcon godoc
+// @Summary Apple touch icon
+// @Description 180x180 PNG generated from the site.icon_media_id media item
+// @Tags site-files
+// @Produce png
+// @Success 200
+// @Failure 404
+// @Router /apple-touch-icon.png [get]
+func (h *SiteIconHandler) AppleTouchIcon(w http.ResponseWriter, r *http.Request) {
+	h.serveIcon(w, r, 180)
+}
+
+// Icon godoc
+// @Summary Site icon
+// @Description Square PNG icon (16, 32, 48, 180, 192 or 512 px) generated from the site.icon_media_id media item
+// @Tags site-files
+// @Produce png
+// @Param size path int true "Icon size in pixels"
+// @Success 200
+// @Failure 404
+// @Router /icons/icon-{size}.png [get]
+func (h *SiteIconHandler) Icon(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.Atoi(chi.URLParam(r, "size"))
+	if err != nil || !iconSizes[size] {
+		http.NotFound(w, r)
+		return
+	}
+	h.serveIcon(w, r, size)
+}
+
+func (h *SiteIconHandler) serveIcon(w http.ResponseWriter, r *http.Request, size int) {
+	icon, err := h.icon(r.Context(), size)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			reqctx.Logf(r.Context(), "[ERROR] Failed to generate %dpx site icon: %v", size, err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	sum := sha256.Sum256(icon)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	w.Write(icon)
+}
+
+// icon returns the generated PNG for size, downloading and scaling the
+// designated media item the first time each size is requested
+func (h *SiteIconHandler) icon(ctx context.Context, size int) ([]byte, error) {
+	settings, err := h.settingRepo.GetMultiple(ctx, []string{SettingSiteIconMediaID})
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseUUID(settings[SettingSiteIconMediaID])
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+
+	media, err := h.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if media.CDNUrl == nil || *media.CDNUrl == "" {
+		return nil, fmt.Errorf("media %s has no cdn_url to read the icon from", media.ID)
+	}
+
+	version := media.ObjectKey
+	if media.Checksum != nil {
+		version = *media.Checksum
+	}
+	key := fmt.Sprintf("%s/%s/%d", media.ID, version, size)
+
+	h.mu.Lock()
+	cached, ok := h.icons[key]
+	h.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *media.CDNUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build icon request: %w", err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download icon source: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download icon source: %s", resp.Status)
+	}
+
+	src, err := imaging.Decode(io.LimitReader(resp.Body, maxIconSourceSize))
+	if err != nil {
+		return nil, err
+	}
+	icon, err := imaging.EncodePNG(imaging.SquareThumbnail(src, size))
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.icons[key] = icon
+	h.mu.Unlock()
+
+	return icon, nil
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/social"
+)
+
+type SocialHandler struct {
+	repo      *repository.SocialShareRepository
+	postRepo  *repository.ContentPostRepository
+	publisher *social.Publisher
+}
+
+func NewSocialHandler(repo *repository.SocialShareRepository, postRepo *repository.ContentPostRepository, publisher *social.Publisher) *SocialHandler {
+	return &SocialHandler{repo: repo, postRepo: postRepo, publisher: publisher}
+}
+
+// GetConfig godoc
+// @Summary Get social sharing configuration
+// @Description Get the configured social accounts (access tokens masked) and message template
+// @Tags social
+// @Produce json
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/social/config [get]
+func (h *SocialHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.publisher.LoadConfig(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load social sharing settings", err)
+		return
+	}
+
+	response.OK(w, cfg.Redacted())
+}
+
+// ListShares godoc
+// @Summary List social shares
+// @Description Get the history of posts shared to social accounts, newest first
+// @Tags social
+// @Produce json
+// @Param post_id query string false "Filter by post ID"
+// @Param account query string false "Filter by account name"
+// @Param status query string false "Filter by status (pending, sent, failed)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/social/shares [get]
+func (h *SocialHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.SocialShareFilter{
+		Account:          q.Get("account"),
+		Status:           q.Get("status"),
+		PaginationParams: parsePaginationParams(r),
+	}
+
+	if postID := q.Get("post_id"); postID != "" {
+		id, err := parseUUID(postID)
+		if err != nil {
+			response.BadRequest(w, "Invalid post ID")
+			return
+		}
+		filter.PostID = &id
+	}
+
+	switch filter.Status {
+	case "", models.SocialSharePending, models.SocialShareSent, models.SocialShareFailed:
+	default:
+		response.ValidationError(w, map[string]string{"status": "Status must be pending, sent or failed"})
+		return
+	}
+
+	shares, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list social shares")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, shares, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// GetPostSharing godoc
+// @Summary Get post social sharing
+// @Description Get whether a post is shared automatically when published, and where it was shared
+// @Tags social
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse{data=models.PostSocialSharing}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/social-sharing [get]
+func (h *SocialHandler) GetPostSharing(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	if !h.postExists(w, r, id) {
+		return
+	}
+
+	sharing, err := h.postSharing(r, id)
+	if err != nil {
+		response.InternalError(w, "Failed to get social sharing")
+		return
+	}
+
+	response.OK(w, sharing)
+}
+
+// UpdatePostSharing godoc
+// @Summary Update post social sharing
+// @Description Opt a post out of automatic social sharing, or back in. Posts already shared are not removed from the networks.
+// @Tags social
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.UpdatePostSocialSharingRequest true "Sharing preference"
+// @Success 200 {object} response.APIResponse{data=models.PostSocialSharing}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/social-sharing [put]
+func (h *SocialHandler) UpdatePostSharing(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req models.UpdatePostSocialSharingRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if req.Enabled == nil {
+		response.ValidationError(w, map[string]string{"enabled": "Enabled is required"})
+		return
+	}
+
+	if !h.postExists(w, r, id) {
+		return
+	}
+
+	if err := h.repo.SetOptOut(r.Context(), id, !*req.Enabled); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to update social sharing")
+		return
+	}
+
+	sharing, err := h.postSharing(r, id)
+	if err != nil {
+		response.InternalError(w, "Failed to get social sharing")
+		return
+	}
+
+	response.OK(w, sharing)
+}
+
+// postExists writes a not found response when the post doesn't exist
+func (h *SocialHandler) postExists(w http.ResponseWriter, r *http.Request, id uuid.UUID) bool {
+	if _, err := h.postRepo.GetByID(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return false
+		}
+		response.InternalError(w, "Failed to get post")
+		return false
+	}
+	return true
+}
+
+// postSharing loads a post's opt-out and its latest shares
+func (h *SocialHandler) postSharing(r *http.Request, postID uuid.UUID) (*models.PostSocialSharing, error) {
+	optedOut, err := h.repo.IsOptedOut(r.Context(), postID)
+	if err != nil {
+		return nil, err
+	}
+	shares, _, err := h.repo.List(r.Context(), models.SocialShareFilter{
+		PostID:           &postID,
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 100},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.PostSocialSharing{Enabled: !optedOut, Shares: shares}, nil
+}
@@ -0,0 +1,538 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// newTagRepository returns a TagRepositoryMock backed by an in-memory
+// tagStore holding tags
+func newTagRepository(tags ...models.Tag) *TagRepositoryMock {
+	f := &tagStore{tags: make(map[uuid.UUID]models.Tag)}
+	for _, tag := range tags {
+		f.tags[tag.ID] = tag
+	}
+	return &TagRepositoryMock{
+		CreateFunc:     f.Create,
+		GetByIDFunc:    f.GetByID,
+		GetBySlugFunc:  f.GetBySlug,
+		ListFunc:       f.List,
+		ExportEachFunc: f.ExportEach,
+		UpdateFunc:     f.Update,
+		DeleteFunc:     f.Delete,
+	}
+}
+
+// tagStore keeps tags in memory for TagRepositoryMock. Names and slugs are
+// unique, as enforced by the tags table.
+type tagStore struct {
+	mu   sync.Mutex
+	tags map[uuid.UUID]models.Tag
+}
+
+func (f *tagStore) taken(id uuid.UUID, name, slug string) bool {
+	for _, tag := range f.tags {
+		if tag.ID != id && (tag.Name == name || tag.Slug == slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns the tags matching filter by name
+func (f *tagStore) sorted(filter models.TagFilter) []models.Tag {
+	var tags []models.Tag
+	for _, tag := range f.tags {
+		if filter.Search == "" || strings.Contains(tag.Name, filter.Search) || strings.Contains(tag.Slug, filter.Search) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags
+}
+
+func (f *tagStore) Create(ctx context.Context, req *models.CreateTagRequest) (*models.Tag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.taken(uuid.Nil, req.Name, req.Slug) {
+		return nil, repository.ErrDuplicate
+	}
+	tag := models.Tag{ID: uuid.New(), Name: req.Name, Slug: req.Slug, CreatedAt: time.Now()}
+	f.tags[tag.ID] = tag
+	return &tag, nil
+}
+
+func (f *tagStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tag, ok := f.tags[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &tag, nil
+}
+
+func (f *tagStore) GetBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tag := range f.tags {
+		if tag.Slug == slug {
+			return &tag, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *tagStore) List(ctx context.Context, filter models.TagFilter) ([]models.Tag, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tags := f.sorted(filter)
+	return paginate(tags, filter.PaginationParams), int64(len(tags)), nil
+}
+
+func (f *tagStore) ExportEach(ctx context.Context, filter models.TagFilter, fn func(*models.Tag, int64) error) error {
+	f.mu.Lock()
+	tags := f.sorted(filter)
+	f.mu.Unlock()
+
+	for i := range tags {
+		if err := fn(&tags[i], 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *tagStore) Update(ctx context.Context, id uuid.UUID, req *models.UpdateTagRequest) (*models.Tag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tag, ok := f.tags[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Slug != nil {
+		tag.Slug = *req.Slug
+	}
+	if f.taken(id, tag.Name, tag.Slug) {
+		return nil, repository.ErrDuplicate
+	}
+	f.tags[id] = tag
+	return &tag, nil
+}
+
+func (f *tagStore) Delete(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.tags[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.tags, id)
+	return nil
+}
+
+// newPostRepository returns a PostRepositoryMock backed by an in-memory
+// postStore holding posts
+func newPostRepository(posts ...models.ContentPost) *PostRepositoryMock {
+	f := newPostStore(posts...)
+	return &PostRepositoryMock{
+		ListFunc:                   f.List,
+		ExportEachFunc:             f.ExportEach,
+		GetByIDFunc:                f.GetByID,
+		GetBySlugInEnvironmentFunc: f.GetBySlugInEnvironment,
+		AggregateFunc:              f.Aggregate,
+		AggregatePartialFunc:       f.AggregatePartial,
+		FacetFunc:                  f.Facet,
+		ForkToDraftFunc:            f.ForkToDraft,
+		DeleteFunc:                 f.Delete,
+		AttachMediaFunc:            f.AttachMedia,
+		DetachMediaFunc:            f.DetachMedia,
+		IncrementViewCountFunc:     f.IncrementViewCount,
+	}
+}
+
+// postStore keeps live posts in memory for PostRepositoryMock. It filters
+// by search and status only; facets count the values of a metadata field.
+type postStore struct {
+	mu    sync.Mutex
+	posts map[uuid.UUID]models.ContentPost
+}
+
+// newPostStore returns a postStore holding posts. Benchmarks use it as the
+// PostRepository directly, so the mock's call recording isn't measured.
+func newPostStore(posts ...models.ContentPost) *postStore {
+	f := &postStore{posts: make(map[uuid.UUID]models.ContentPost)}
+	for _, post := range posts {
+		f.posts[post.ID] = post
+	}
+	return f
+}
+
+// matching returns the posts matching filter, newest first
+func (f *postStore) matching(filter models.PostFilter) []models.ContentPost {
+	var posts []models.ContentPost
+	for _, post := range f.posts {
+		if filter.Search != "" && !strings.Contains(post.Title, filter.Search) {
+			continue
+		}
+		if filter.Status != nil && post.Status != *filter.Status {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].CreatedAt.After(posts[j].CreatedAt) })
+	return posts
+}
+
+func (f *postStore) List(ctx context.Context, filter models.PostFilter) ([]models.ContentPost, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	posts := f.matching(filter)
+	return paginate(posts, filter.PaginationParams), int64(len(posts)), nil
+}
+
+func (f *postStore) ExportEach(ctx context.Context, filter models.PostFilter, fn func(*models.ContentPost) error) error {
+	f.mu.Lock()
+	posts := f.matching(filter)
+	f.mu.Unlock()
+
+	for i := range posts {
+		if err := fn(&posts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *postStore) GetByID(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &post, nil
+}
+
+func (f *postStore) GetBySlugInEnvironment(ctx context.Context, slug, env string) (*models.ContentPost, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, post := range f.posts {
+		if post.Slug == slug {
+			return &post, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *postStore) Aggregate(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, error) {
+	buckets, _, err := f.AggregatePartial(ctx, agg)
+	return buckets, err
+}
+
+// AggregatePartial counts the matching posts in a single bucket
+func (f *postStore) AggregatePartial(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := int64(len(f.matching(agg.Filter)))
+	return []models.PostAggregateBucket{{Count: count}}, false, nil
+}
+
+func (f *postStore) Facet(ctx context.Context, filter models.PostFilter, field string, limit int) (*models.Facet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := strings.TrimPrefix(field, "meta.")
+	counts := make(map[string]int64)
+	for _, post := range f.matching(filter) {
+		var meta map[string]interface{}
+		if json.Unmarshal(post.Metadata, &meta) != nil {
+			continue
+		}
+		if value, ok := meta[name].(string); ok {
+			counts[value]++
+		}
+	}
+
+	facet := &models.Facet{Field: field, Values: []models.FacetValue{}}
+	for value, count := range counts {
+		facet.Values = append(facet.Values, models.FacetValue{Value: value, Count: count})
+	}
+	sort.Slice(facet.Values, func(i, j int) bool { return facet.Values[i].Value < facet.Values[j].Value })
+	if len(facet.Values) > limit {
+		facet.Values, facet.Truncated = facet.Values[:limit], true
+	}
+	return facet, nil
+}
+
+func (f *postStore) ForkToDraft(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	post, ok := f.posts[id]
+	if !ok {
+		return uuid.Nil, repository.ErrNotFound
+	}
+	live := post.ID
+	post.ID = uuid.New()
+	post.Environment = models.EnvironmentDraft
+	post.LivePostID = &live
+	f.posts[post.ID] = post
+	return post.ID, nil
+}
+
+func (f *postStore) Delete(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.posts[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.posts, id)
+	return nil
+}
+
+func (f *postStore) AttachMedia(ctx context.Context, postID uuid.UUID, req *models.AttachMediaRequest) (*models.PostMedia, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.posts[postID]; !ok {
+		return nil, repository.ErrForeignKey
+	}
+	return &models.PostMedia{PostID: postID, MediaID: req.MediaID}, nil
+}
+
+func (f *postStore) DetachMedia(ctx context.Context, postID, mediaID uuid.UUID) error {
+	return nil
+}
+
+func (f *postStore) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if post, ok := f.posts[id]; ok {
+		post.ViewCount++
+		f.posts[id] = post
+	}
+	return nil
+}
+
+// newSettingRepository returns a SettingRepositoryMock backed by an
+// in-memory settingStore holding settings
+func newSettingRepository(settings ...models.Setting) *SettingRepositoryMock {
+	f := &settingStore{settings: make(map[string]models.Setting)}
+	for _, s := range settings {
+		f.settings[s.Key] = s
+	}
+	return &SettingRepositoryMock{
+		CreateFunc:       f.Create,
+		GetByKeyFunc:     f.GetByKey,
+		ListFunc:         f.List,
+		UpdateFunc:       f.Update,
+		UpsertFunc:       f.Upsert,
+		DeleteFunc:       f.Delete,
+		ListByPrefixFunc: f.ListByPrefix,
+		ImportFunc:       f.Import,
+		GetMultipleFunc:  f.GetMultiple,
+	}
+}
+
+// settingStore keeps settings in memory for SettingRepositoryMock
+type settingStore struct {
+	mu       sync.Mutex
+	settings map[string]models.Setting
+}
+
+// sorted returns the settings whose key starts with one of prefixes, by key
+func (f *settingStore) sorted(prefixes ...string) []models.Setting {
+	var settings []models.Setting
+	for _, s := range f.settings {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(s.Key, prefix) {
+				settings = append(settings, s)
+				break
+			}
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+func (f *settingStore) Create(ctx context.Context, req *models.CreateSettingRequest) (*models.Setting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.settings[req.Key]; ok {
+		return nil, repository.ErrDuplicate
+	}
+	s := models.Setting{ID: uuid.New(), Key: req.Key, Value: req.Value, Description: req.Description, UpdatedAt: time.Now()}
+	f.settings[s.Key] = s
+	return &s, nil
+}
+
+func (f *settingStore) GetByKey(ctx context.Context, key string) (*models.Setting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.settings[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (f *settingStore) List(ctx context.Context, filter models.SettingFilter) ([]models.Setting, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	settings := f.sorted(filter.Search)
+	return paginate(settings, filter.PaginationParams), int64(len(settings)), nil
+}
+
+func (f *settingStore) Update(ctx context.Context, key string, req *models.UpdateSettingRequest) (*models.Setting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.settings[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if req.Value != nil {
+		s.Value = req.Value
+	}
+	if req.Description != nil {
+		s.Description = req.Description
+	}
+	f.settings[key] = s
+	return &s, nil
+}
+
+func (f *settingStore) Upsert(ctx context.Context, req *models.CreateSettingRequest) (*models.Setting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.settings[req.Key]
+	if !ok {
+		s = models.Setting{ID: uuid.New(), Key: req.Key}
+	}
+	s.Value, s.Description, s.UpdatedAt = req.Value, req.Description, time.Now()
+	f.settings[s.Key] = s
+	return &s, nil
+}
+
+func (f *settingStore) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.settings[key]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.settings, key)
+	return nil
+}
+
+func (f *settingStore) ListByPrefix(ctx context.Context, prefixes []string) ([]models.Setting, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sorted(prefixes...), nil
+}
+
+func (f *settingStore) Import(ctx context.Context, upserts []models.SettingEntry, deletes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range upserts {
+		s, ok := f.settings[e.Key]
+		if !ok {
+			s = models.Setting{ID: uuid.New(), Key: e.Key}
+		}
+		s.Value, s.Description = e.Value, e.Description
+		f.settings[e.Key] = s
+	}
+	for _, key := range deletes {
+		delete(f.settings, key)
+	}
+	return nil
+}
+
+func (f *settingStore) GetMultiple(ctx context.Context, keys []string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if s, ok := f.settings[key]; ok && s.Value != nil {
+			values[key] = *s.Value
+		}
+	}
+	return values, nil
+}
+
+func paginate[T any](items []T, p models.PaginationParams) []T {
+	start := min(p.Offset(), len(items))
+	end := min(start+p.PageSize, len(items))
+	return items[start:end]
+}
+
+// serve sends a request through handler and decodes the API response,
+// with data left as raw JSON for the caller to decode
+func serve(t *testing.T, handler http.Handler, method, target, body string) (int, apiResponse) {
+	t.Helper()
+
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp apiResponse
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: response is not JSON: %v\n%s", method, target, err, rec.Body.String())
+		}
+	}
+	return rec.Code, resp
+}
+
+type apiResponse struct {
+	Success bool               `json:"success"`
+	Data    json.RawMessage    `json:"data"`
+	Error   *response.APIError `json:"error"`
+	Meta    *response.Meta     `json:"meta"`
+}
+
+func (r apiResponse) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("failed to decode data %s: %v", r.Data, err)
+	}
+}
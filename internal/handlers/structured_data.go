@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/web"
+)
+
+// SettingJSONLDArticleTypes maps content type slugs to schema.org types as a
+// JSON object, e.g. {"news": "NewsArticle"}. Unmapped types use BlogPosting.
+const SettingJSONLDArticleTypes = "seo.jsonld_article_types"
+
+type StructuredDataHandler struct {
+	postRepo      *repository.ContentPostRepository
+	settingRepo   *repository.SettingRepository
+	cacheHintRepo *repository.CacheHintRepository
+	urls          *permalink.Builder
+	publicURL     string
+}
+
+func NewStructuredDataHandler(postRepo *repository.ContentPostRepository, settingRepo *repository.SettingRepository, cacheHintRepo *repository.CacheHintRepository, urls *permalink.Builder, publicURL string) *StructuredDataHandler {
+	return &StructuredDataHandler{postRepo: postRepo, settingRepo: settingRepo, cacheHintRepo: cacheHintRepo, urls: urls, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+type jsonLDThing struct {
+	Type string `json:"@type"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	ID   string `json:"@id,omitempty"`
+}
+
+type jsonLDOrganization struct {
+	Type string       `json:"@type"`
+	Name string       `json:"name"`
+	Logo *jsonLDThing `json:"logo,omitempty"`
+}
+
+type jsonLDArticle struct {
+	Context          string              `json:"@context"`
+	Type             string              `json:"@type"`
+	Headline         string              `json:"headline"`
+	Description      string              `json:"description,omitempty"`
+	URL              string              `json:"url"`
+	MainEntityOfPage *jsonLDThing        `json:"mainEntityOfPage"`
+	DatePublished    *time.Time          `json:"datePublished,omitempty"`
+	DateModified     time.Time           `json:"dateModified"`
+	Author           *jsonLDThing        `json:"author,omitempty"`
+	Publisher        *jsonLDOrganization `json:"publisher"`
+	Image            []string            `json:"image,omitempty"`
+	Keywords         string              `json:"keywords,omitempty"`
+	ArticleSection   string              `json:"articleSection,omitempty"`
+}
+
+// PostJSONLD godoc
+// @Summary Post structured data
+// @Description schema.org Article/BlogPosting JSON-LD for a published post, ready to embed in a <script type="application/ld+json"> tag
+// @Tags public
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Success 200 {object} jsonLDArticle
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/public/posts/{slug}/jsonld [get]
+func (h *StructuredDataHandler) PostJSONLD(w http.ResponseWriter, r *http.Request) {
+	post, err := getPublishedPost(r, h.postRepo, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to get post")
+		return
+	}
+
+	settings, err := h.settingRepo.GetMultiple(r.Context(), []string{
+		web.SettingSiteName, SettingSiteIconMediaID, SettingJSONLDArticleTypes,
+	})
+	if err != nil {
+		settings = map[string]string{}
+	}
+
+	urls, err := h.urls.Load(r.Context())
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to load site URL settings", err)
+		return
+	}
+
+	url := urls.PostURL(post)
+	article := jsonLDArticle{
+		Context:          "https://schema.org",
+		Type:             "BlogPosting",
+		Headline:         post.Title,
+		URL:              url,
+		MainEntityOfPage: &jsonLDThing{Type: "WebPage", ID: url},
+		DatePublished:    post.PublishedAt,
+		DateModified:     post.UpdatedAt,
+		Publisher:        &jsonLDOrganization{Type: "Organization", Name: settings[web.SettingSiteName]},
+	}
+	if article.Publisher.Name == "" {
+		article.Publisher.Name = "CMS"
+	}
+	if settings[SettingSiteIconMediaID] != "" {
+		article.Publisher.Logo = &jsonLDThing{Type: "ImageObject", URL: h.publicURL + "/icons/icon-512.png"}
+	}
+	if post.Excerpt != nil {
+		article.Description = *post.Excerpt
+	}
+	if post.Author != nil && post.Author.FullName != "" {
+		article.Author = &jsonLDThing{Type: "Person", Name: post.Author.FullName}
+	}
+	if post.ContentType != nil {
+		article.ArticleSection = post.ContentType.Name
+
+		var types map[string]string
+		if err := json.Unmarshal([]byte(settings[SettingJSONLDArticleTypes]), &types); err == nil {
+			if t := types[post.ContentType.Slug]; t != "" {
+				article.Type = t
+			}
+		}
+	}
+
+	// Featured images first, then the rest in display order
+	media := append([]models.PostMedia(nil), post.Media...)
+	sort.SliceStable(media, func(i, j int) bool {
+		return media[i].MediaRole == models.MediaRoleFeatured && media[j].MediaRole != models.MediaRoleFeatured
+	})
+	for _, pm := range media {
+		if pm.Media != nil && pm.Media.FileType == models.FileTypeImage && pm.Media.CDNUrl != nil {
+			article.Image = append(article.Image, *pm.Media.CDNUrl)
+		}
+	}
+
+	var keywords []string
+	for _, tag := range post.Tags {
+		keywords = append(keywords, tag.Name)
+	}
+	article.Keywords = strings.Join(keywords, ", ")
+
+	w.Header().Set("Content-Type", "application/ld+json")
+	delivery.SetCacheHeaders(w, r, h.cacheHintRepo, post, "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(article)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type SubscriberHandler struct {
+	repo          *repository.SubscriberRepository
+	defaultLocale string
+	accessLog     *accessLogger
+}
+
+func NewSubscriberHandler(repo *repository.SubscriberRepository, defaultLocale string, accessLogRepo *repository.AccessLogRepository) *SubscriberHandler {
+	return &SubscriberHandler{repo: repo, defaultLocale: defaultLocale, accessLog: newAccessLogger(accessLogRepo)}
+}
+
+// List godoc
+// @Summary List subscribers
+// @Description Get campaign subscribers
+// @Tags subscribers
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param status query int false "Filter by status (1=subscribed, 2=unsubscribed)"
+// @Param search query string false "Search in email and name"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/subscribers [get]
+func (h *SubscriberHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.SubscriberFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if s, err := strconv.Atoi(statusStr); err == nil {
+			status := models.SubscriberStatus(s)
+			filter.Status = &status
+		}
+	}
+
+	subscribers, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list subscribers")
+		return
+	}
+
+	ids := make([]uuid.UUID, len(subscribers))
+	for i := range subscribers {
+		ids[i] = subscribers[i].ID
+	}
+	h.accessLog.record(w, r, models.AccessResourceSubscriber, models.AccessActionList, ids...)
+
+	response.JSONWithMeta(w, http.StatusOK, subscribers, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Create godoc
+// @Summary Add subscriber
+// @Description Subscribe an email address to campaigns; re-subscribes an address that unsubscribed earlier
+// @Tags subscribers
+// @Accept json
+// @Produce json
+// @Param body body models.CreateSubscriberRequest true "Subscriber data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/subscribers [post]
+func (h *SubscriberHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSubscriberRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Locale = strings.ToLower(strings.TrimSpace(req.Locale))
+	if req.Locale == "" {
+		req.Locale = reqctx.From(r.Context()).Locale
+	}
+	if req.Locale == "" {
+		req.Locale = h.defaultLocale
+	}
+
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		response.ValidationError(w, map[string]string{"email": "A valid email address is required"})
+		return
+	}
+
+	sub, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Email address is already subscribed")
+			return
+		}
+		response.InternalError(w, "Failed to add subscriber")
+		return
+	}
+
+	response.Created(w, sub)
+}
+
+// Delete godoc
+// @Summary Delete subscriber
+// @Description Remove a subscriber entirely
+// @Tags subscribers
+// @Param id path string true "Subscriber ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/subscribers/{id} [delete]
+func (h *SubscriberHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid subscriber ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Subscriber not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete subscriber")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe
+// @Description Unsubscribe using the token from a campaign email's unsubscribe link
+// @Tags subscribers
+// @Produce json
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/subscribers/unsubscribe [get]
+func (h *SubscriberHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		response.BadRequest(w, "Token is required")
+		return
+	}
+
+	if _, err := h.repo.Unsubscribe(r.Context(), token); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Subscription not found")
+			return
+		}
+		response.InternalError(w, "Failed to unsubscribe")
+		return
+	}
+
+	response.OK(w, map[string]string{"status": "unsubscribed"})
+}
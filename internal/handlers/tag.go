@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/keeps-dev/go-cms-template/internal/models"
@@ -11,10 +12,10 @@ import (
 )
 
 type TagHandler struct {
-	repo *repository.TagRepository
+	repo TagRepository
 }
 
-func NewTagHandler(repo *repository.TagRepository) *TagHandler {
+func NewTagHandler(repo TagRepository) *TagHandler {
 	return &TagHandler{repo: repo}
 }
 
@@ -48,6 +49,37 @@ func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Export godoc
+// @Summary Export tags as CSV
+// @Description Stream all tags matching the list filters as a CSV file, including post counts
+// @Tags tags
+// @Produce text/csv
+// @Param search query string false "Search in name and slug"
+// @Success 200 {file} file
+// @Router /api/v1/tags/export [get]
+func (h *TagHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter := models.TagFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+
+	export, err := newCSVExport(w, "tags", []string{"id", "name", "slug", "post_count", "created_at"})
+	if err != nil {
+		return
+	}
+
+	err = h.repo.ExportEach(r.Context(), filter, func(tag *models.Tag, postCount int64) error {
+		return export.Write([]string{
+			tag.ID.String(),
+			tag.Name,
+			tag.Slug,
+			strconv.FormatInt(postCount, 10),
+			csvTime(&tag.CreatedAt),
+		})
+	})
+	export.Finish(r.Context(), "tags", err)
+}
+
 // Get godoc
 // @Summary Get tag by ID
 // @Description Get a single tag by its ID
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// tagRouter mounts TagHandler the way router.New does
+func tagRouter(repo TagRepository) http.Handler {
+	h := NewTagHandler(repo)
+	r := chi.NewRouter()
+	r.Route("/tags", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/export", h.Export)
+		r.Get("/slug/{slug}", h.GetBySlug)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+	return r
+}
+
+func testTag(name string) models.Tag {
+	return models.Tag{ID: uuid.New(), Name: name, Slug: strings.ToLower(name), CreatedAt: time.Now()}
+}
+
+func TestTagHandlerList(t *testing.T) {
+	repo := newTagRepository(testTag("Go"), testTag("Golang"), testTag("Rust"))
+	router := tagRouter(repo)
+
+	status, resp := serve(t, router, http.MethodGet, "/tags?search=Go&page_size=1", "")
+	if status != http.StatusOK || !resp.Success {
+		t.Fatalf("status = %d, success = %v", status, resp.Success)
+	}
+	var tags []models.Tag
+	resp.decode(t, &tags)
+	if len(tags) != 1 || tags[0].Name != "Go" {
+		t.Errorf("tags = %+v, want only Go", tags)
+	}
+	if m := resp.Meta; m == nil || m.Page != 1 || m.PageSize != 1 || m.Total != 2 {
+		t.Errorf("meta = %+v, want page 1 of size 1 with 2 in total", m)
+	}
+}
+
+func TestTagHandlerGet(t *testing.T) {
+	tag := testTag("Go")
+	router := tagRouter(newTagRepository(tag))
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/tags/" + tag.ID.String(), http.StatusOK},
+		{"/tags/" + uuid.NewString(), http.StatusNotFound},
+		{"/tags/not-a-uuid", http.StatusBadRequest},
+		{"/tags/slug/go", http.StatusOK},
+		{"/tags/slug/rust", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		status, resp := serve(t, router, http.MethodGet, tt.target, "")
+		if status != tt.status {
+			t.Errorf("GET %s = %d, want %d", tt.target, status, tt.status)
+			continue
+		}
+		if status == http.StatusOK {
+			var got models.Tag
+			resp.decode(t, &got)
+			if got.ID != tag.ID {
+				t.Errorf("GET %s returned tag %s, want %s", tt.target, got.ID, tag.ID)
+			}
+		}
+	}
+}
+
+func TestTagHandlerCreate(t *testing.T) {
+	repo := newTagRepository(testTag("Go"))
+	router := tagRouter(repo)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"valid", `{"name":"Rust","slug":"rust"}`, http.StatusCreated, ""},
+		{"duplicate slug", `{"name":"Golang","slug":"go"}`, http.StatusConflict, "CONFLICT"},
+		{"missing slug", `{"name":"Zig"}`, http.StatusUnprocessableEntity, "VALIDATION_ERROR"},
+		{"malformed", `{"name":`, http.StatusBadRequest, "BAD_REQUEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := serve(t, router, http.MethodPost, "/tags", tt.body)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if tt.code != "" && (resp.Error == nil || resp.Error.Code != tt.code) {
+				t.Errorf("error = %+v, want code %s", resp.Error, tt.code)
+			}
+		})
+	}
+
+	if _, err := repo.GetBySlug(context.Background(), "rust"); err != nil {
+		t.Errorf("created tag not stored: %v", err)
+	}
+}
+
+func TestTagHandlerUpdate(t *testing.T) {
+	goTag, rust := testTag("Go"), testTag("Rust")
+	repo := newTagRepository(goTag, rust)
+	router := tagRouter(repo)
+
+	status, resp := serve(t, router, http.MethodPut, "/tags/"+goTag.ID.String(), `{"name":"Golang"}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	var got models.Tag
+	resp.decode(t, &got)
+	if got.Name != "Golang" || got.Slug != "go" {
+		t.Errorf("updated tag = %+v, want name Golang and slug go", got)
+	}
+
+	if status, _ := serve(t, router, http.MethodPut, "/tags/"+rust.ID.String(), `{"slug":"go"}`); status != http.StatusConflict {
+		t.Errorf("taking another tag's slug = %d, want 409", status)
+	}
+	if status, _ := serve(t, router, http.MethodPut, "/tags/"+uuid.NewString(), `{"name":"Zig"}`); status != http.StatusNotFound {
+		t.Errorf("updating a missing tag = %d, want 404", status)
+	}
+}
+
+func TestTagHandlerDelete(t *testing.T) {
+	tag := testTag("Go")
+	router := tagRouter(newTagRepository(tag))
+
+	target := "/tags/" + tag.ID.String()
+	if status, _ := serve(t, router, http.MethodDelete, target, ""); status != http.StatusNoContent {
+		t.Errorf("first delete = %d, want 204", status)
+	}
+	if status, _ := serve(t, router, http.MethodDelete, target, ""); status != http.StatusNotFound {
+		t.Errorf("second delete = %d, want 404", status)
+	}
+}
+
+func TestTagHandlerExport(t *testing.T) {
+	router := tagRouter(newTagRepository(testTag("Go"), testTag("Rust")))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tags/export", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 3 || lines[0] != "id,name,slug,post_count,created_at" {
+		t.Fatalf("export = %q, want a header and 2 rows", rec.Body.String())
+	}
+	if !strings.Contains(lines[1], ",Go,go,0,") || !strings.Contains(lines[2], ",Rust,rust,0,") {
+		t.Errorf("rows = %q", lines[1:])
+	}
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+type TeamHandler struct {
+	repo *repository.TeamRepository
+}
+
+func NewTeamHandler(repo *repository.TeamRepository) *TeamHandler {
+	return &TeamHandler{repo: repo}
+}
+
+// List godoc
+// @Summary List teams
+// @Description Get all teams with their member counts
+// @Tags teams
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param search query string false "Search in name and slug"
+// @Param member_id query string false "Only teams this user belongs to"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/teams [get]
+func (h *TeamHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.TeamFilter{
+		PaginationParams: parsePaginationParams(r),
+		Search:           r.URL.Query().Get("search"),
+	}
+	if memberID := r.URL.Query().Get("member_id"); memberID != "" {
+		if id, err := uuid.Parse(memberID); err == nil {
+			filter.MemberID = &id
+		}
+	}
+
+	teams, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list teams")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, teams, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get team by ID
+// @Description Get a single team by its ID
+// @Tags teams
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/teams/{id} [get]
+func (h *TeamHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid team ID")
+		return
+	}
+
+	team, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Team not found")
+			return
+		}
+		response.InternalError(w, "Failed to get team")
+		return
+	}
+
+	response.OK(w, team)
+}
+
+// Create godoc
+// @Summary Create team
+// @Description Create a new team with its own content space
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param body body models.CreateTeamRequest true "Team data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/teams [post]
+func (h *TeamHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateTeamRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Name == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if req.Slug == "" {
+		validationErrors["slug"] = "Slug is required"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	team, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Team with this name or slug already exists")
+			return
+		}
+		response.InternalError(w, "Failed to create team")
+		return
+	}
+
+	response.Created(w, team)
+}
+
+// Update godoc
+// @Summary Update team
+// @Description Update an existing team; an empty description clears it
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param body body models.UpdateTeamRequest true "Team data"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/teams/{id} [put]
+func (h *TeamHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid team ID")
+		return
+	}
+
+	var req models.UpdateTeamRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := make(map[string]string)
+	if req.Name != nil && *req.Name == "" {
+		validationErrors["name"] = "Name cannot be empty"
+	}
+	if req.Slug != nil && *req.Slug == "" {
+		validationErrors["slug"] = "Slug cannot be empty"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	team, err := h.repo.Update(r.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Team not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Team with this name or slug already exists")
+			return
+		}
+		response.InternalError(w, "Failed to update team")
+		return
+	}
+
+	response.OK(w, team)
+}
+
+// Delete godoc
+// @Summary Delete team
+// @Description Delete a team and its memberships. Its posts and media are kept and become shared.
+// @Tags teams
+// @Param id path string true "Team ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/teams/{id} [delete]
+func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid team ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Team not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete team")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// ListMembers godoc
+// @Summary List team members
+// @Description Get a team's members and their team roles (1=viewer, 2=editor, 3=manager)
+// @Tags teams
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/teams/{id}/members [get]
+func (h *TeamHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid team ID")
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Team not found")
+			return
+		}
+		response.InternalError(w, "Failed to get team")
+		return
+	}
+
+	members, err := h.repo.ListMembers(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, "Failed to list team members")
+		return
+	}
+
+	response.OK(w, members)
+}
+
+// SetMember godoc
+// @Summary Add or update a team member
+// @Description Add a user to a team, or change their team role. Viewers can read the team's space, editors can own posts and media in it and managers can also manage its members.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param userId path string true "User ID"
+// @Param body body models.SetTeamMemberRequest true "Team role"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/teams/{id}/members/{userId} [put]
+func (h *TeamHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	teamID, userID, ok := parseTeamMemberIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SetTeamMemberRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if !req.Role.Valid() {
+		response.ValidationError(w, map[string]string{
+			"role": "Role must be 1 (viewer), 2 (editor), or 3 (manager)",
+		})
+		return
+	}
+
+	member, err := h.repo.SetMember(r.Context(), teamID, userID, req.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Team or user not found")
+			return
+		}
+		response.InternalError(w, "Failed to set team member")
+		return
+	}
+
+	response.OK(w, member)
+}
+
+// RemoveMember godoc
+// @Summary Remove a team member
+// @Description Remove a user from a team. Content they own in the team's space stays there.
+// @Tags teams
+// @Param id path string true "Team ID"
+// @Param userId path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/teams/{id}/members/{userId} [delete]
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	teamID, userID, ok := parseTeamMemberIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RemoveMember(r.Context(), teamID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Team member not found")
+			return
+		}
+		response.InternalError(w, "Failed to remove team member")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// parseTeamMemberIDs reads the team and user IDs from the path, writing the
+// error response when either is invalid
+func parseTeamMemberIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	teamID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid team ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := parseUUID(chi.URLParam(r, "userId"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return teamID, userID, true
+}
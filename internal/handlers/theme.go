@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+	"github.com/keeps-dev/go-cms-template/internal/web"
+)
+
+// maxThemeFileSize limits a single uploaded template or asset
+const maxThemeFileSize = 5 << 20
+
+type ThemeHandler struct {
+	repo   *repository.ThemeRepository
+	themes *service.ThemeService
+	site   *web.Site
+}
+
+// NewThemeHandler returns a handler for stored themes. site may be nil when
+// the public site is disabled; themes can still be managed but not previewed.
+func NewThemeHandler(repo *repository.ThemeRepository, themes *service.ThemeService, site *web.Site) *ThemeHandler {
+	return &ThemeHandler{repo: repo, themes: themes, site: site}
+}
+
+// List godoc
+// @Summary List themes
+// @Description Get stored theme versions, newest version first
+// @Tags themes
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param name query string false "Filter by theme name"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/themes [get]
+func (h *ThemeHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := models.ThemeFilter{
+		PaginationParams: parsePaginationParams(r),
+		Name:             r.URL.Query().Get("name"),
+	}
+	// The default pagination sorts by created_at; keep versions grouped instead
+	if r.URL.Query().Get("sort_by") == "" {
+		filter.SortBy = ""
+	}
+
+	themes, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list themes")
+		return
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, themes, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get theme by ID
+// @Description Get a theme version with its file listing
+// @Tags themes
+// @Produce json
+// @Param id path string true "Theme ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/themes/{id} [get]
+func (h *ThemeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid theme ID")
+		return
+	}
+
+	theme, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return
+		}
+		response.InternalError(w, "Failed to get theme")
+		return
+	}
+
+	response.OK(w, theme)
+}
+
+// Create godoc
+// @Summary Create theme version
+// @Description Create the next version of a theme, optionally copying the files of base_id
+// @Tags themes
+// @Accept json
+// @Produce json
+// @Param body body models.CreateThemeRequest true "Theme data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /api/v1/themes [post]
+func (h *ThemeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateThemeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		response.ValidationError(w, map[string]string{"name": "Name is required"})
+		return
+	}
+
+	theme, err := h.repo.Create(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.ValidationError(w, map[string]string{"base_id": "Base theme not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "A new version of this theme is being created concurrently")
+			return
+		}
+		response.InternalError(w, "Failed to create theme")
+		return
+	}
+
+	response.Created(w, theme)
+}
+
+// Delete godoc
+// @Summary Delete theme version
+// @Description Delete an inactive theme version and its files
+// @Tags themes
+// @Param id path string true "Theme ID"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/themes/{id} [delete]
+func (h *ThemeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	theme, ok := h.loadTheme(w, r)
+	if !ok {
+		return
+	}
+	if theme.IsActive {
+		response.Conflict(w, "The active theme cannot be deleted")
+		return
+	}
+
+	if err := h.themes.Delete(r.Context(), theme.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete theme")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// PutFile godoc
+// @Summary Upload theme file
+// @Description Create or replace a template (top-level .html) or static asset (static/...) in an inactive theme version. The request body is the raw file.
+// @Tags themes
+// @Accept octet-stream
+// @Produce json
+// @Param id path string true "Theme ID"
+// @Param path path string true "File path"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/themes/{id}/files/{path} [put]
+func (h *ThemeHandler) PutFile(w http.ResponseWriter, r *http.Request) {
+	theme, ok := h.loadTheme(w, r)
+	if !ok {
+		return
+	}
+	if theme.IsActive {
+		response.Conflict(w, "The active theme cannot be modified; create a new version instead")
+		return
+	}
+
+	filePath := chi.URLParam(r, "*")
+	if !web.IsThemePath(filePath) {
+		response.ValidationError(w, map[string]string{"path": "Path must be a top-level .html template or a file under static/"})
+		return
+	}
+
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxThemeFileSize))
+	if err != nil {
+		response.Error(w, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "Theme files are limited to 5 MB")
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") {
+		if byExt := mime.TypeByExtension(path.Ext(filePath)); byExt != "" {
+			contentType = byExt
+		} else {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	file, err := h.themes.PutFile(r.Context(), theme.ID, filePath, contentType, content)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return
+		}
+		if errors.Is(err, service.ErrThemeStorageDisabled) {
+			response.Error(w, http.StatusServiceUnavailable, "STORAGE_DISABLED", "No theme storage is configured")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to save theme file", err)
+		return
+	}
+
+	response.OK(w, file)
+}
+
+// GetFile godoc
+// @Summary Download theme file
+// @Description Get the raw contents of a theme file
+// @Tags themes
+// @Param id path string true "Theme ID"
+// @Param path path string true "File path"
+// @Success 200
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/themes/{id}/files/{path} [get]
+func (h *ThemeHandler) GetFile(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid theme ID")
+		return
+	}
+
+	file, err := h.themes.GetFile(r.Context(), id, chi.URLParam(r, "*"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme file not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to get theme file", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("ETag", `"`+file.Checksum+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
+
+// DeleteFile godoc
+// @Summary Delete theme file
+// @Description Remove a file from an inactive theme version; the default theme's copy is used instead
+// @Tags themes
+// @Param id path string true "Theme ID"
+// @Param path path string true "File path"
+// @Success 204
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/themes/{id}/files/{path} [delete]
+func (h *ThemeHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	theme, ok := h.loadTheme(w, r)
+	if !ok {
+		return
+	}
+	if theme.IsActive {
+		response.Conflict(w, "The active theme cannot be modified; create a new version instead")
+		return
+	}
+
+	if err := h.themes.DeleteFile(r.Context(), theme.ID, chi.URLParam(r, "*")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme file not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete theme file")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Activate godoc
+// @Summary Activate theme version
+// @Description Validate a theme version and make it the live theme without a restart
+// @Tags themes
+// @Produce json
+// @Param id path string true "Theme ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 422 {object} response.APIResponse
+// @Router /api/v1/themes/{id}/activate [post]
+func (h *ThemeHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid theme ID")
+		return
+	}
+
+	parsed, ok := h.parseTheme(w, r.Context(), id)
+	if !ok {
+		return
+	}
+
+	theme, err := h.repo.Activate(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return
+		}
+		response.InternalError(w, "Failed to activate theme")
+		return
+	}
+
+	if h.site != nil {
+		h.site.SetTheme(parsed)
+	}
+
+	response.OK(w, theme)
+}
+
+// Deactivate godoc
+// @Summary Deactivate stored theme
+// @Description Switch the site back to its configured filesystem theme
+// @Tags themes
+// @Success 204
+// @Router /api/v1/themes/deactivate [post]
+func (h *ThemeHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	if err := h.repo.Deactivate(r.Context()); err != nil {
+		response.InternalError(w, "Failed to deactivate theme")
+		return
+	}
+
+	if h.site != nil {
+		if err := h.site.SetTheme(nil); err != nil {
+			response.InternalErrorWithErr(w, "Failed to load filesystem theme", err)
+			return
+		}
+	}
+
+	response.NoContent(w)
+}
+
+// Preview godoc
+// @Summary Preview theme version
+// @Description Render the public site with a theme version without activating it. Links in the preview stay within the preview.
+// @Tags themes
+// @Produce html
+// @Param id path string true "Theme ID"
+// @Success 200
+// @Failure 404 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/themes/{id}/preview/ [get]
+func (h *ThemeHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	if h.site == nil {
+		response.Error(w, http.StatusServiceUnavailable, "WEB_DISABLED", "The public site is not enabled")
+		return
+	}
+
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid theme ID")
+		return
+	}
+
+	parsed, ok := h.parseTheme(w, r.Context(), id)
+	if !ok {
+		return
+	}
+
+	// Route the remainder of the path through a fresh router
+	preview := r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+	preview.URL.Path = "/" + chi.URLParam(r, "*")
+	preview.URL.RawPath = ""
+
+	h.site.Preview(parsed, "/api/v1/themes/"+id.String()+"/preview").ServeHTTP(w, preview)
+}
+
+// loadTheme fetches the theme named by the id URL parameter, writing an
+// error response when it cannot
+func (h *ThemeHandler) loadTheme(w http.ResponseWriter, r *http.Request) (*models.Theme, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid theme ID")
+		return nil, false
+	}
+
+	theme, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get theme")
+		return nil, false
+	}
+
+	return theme, true
+}
+
+// parseTheme loads a theme version with its files and parses its templates,
+// reporting template errors as validation errors
+func (h *ThemeHandler) parseTheme(w http.ResponseWriter, ctx context.Context, id uuid.UUID) (*web.Theme, bool) {
+	theme, err := h.themes.GetWithContent(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Theme not found")
+			return nil, false
+		}
+		response.InternalErrorWithErr(w, "Failed to load theme", err)
+		return nil, false
+	}
+
+	parsed, err := web.StoredTheme(theme.Files)
+	if err != nil {
+		response.ValidationError(w, map[string]string{"theme": err.Error()})
+		return nil, false
+	}
+
+	return parsed, true
+}
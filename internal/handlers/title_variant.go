@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+const (
+	visitorCookieName = "cms_visitor"
+	visitorCookieTTL  = 365 * 24 * time.Hour
+
+	// significanceZ is the two-sided 95% confidence threshold
+	significanceZ = 1.96
+)
+
+type TitleVariantHandler struct {
+	repo     *repository.TitleVariantRepository
+	postRepo *repository.ContentPostRepository
+}
+
+func NewTitleVariantHandler(repo *repository.TitleVariantRepository, postRepo *repository.ContentPostRepository) *TitleVariantHandler {
+	return &TitleVariantHandler{repo: repo, postRepo: postRepo}
+}
+
+// List godoc
+// @Summary List title variants
+// @Description Get all title variants of a post with their counters
+// @Tags title-variants
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants [get]
+func (h *TitleVariantHandler) List(w http.ResponseWriter, r *http.Request) {
+	postID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	variants, err := h.repo.ListByPost(r.Context(), postID)
+	if err != nil {
+		response.InternalError(w, "Failed to list title variants")
+		return
+	}
+
+	response.OK(w, variants)
+}
+
+// Create godoc
+// @Summary Add title variant
+// @Description Add an alternative headline to a post. The first variant acts as the control.
+// @Tags title-variants
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param body body models.CreateTitleVariantRequest true "Variant data"
+// @Success 201 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants [post]
+func (h *TitleVariantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	postID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	var req models.CreateTitleVariantRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		response.ValidationError(w, map[string]string{"title": "Title is required"})
+		return
+	}
+
+	variant, err := h.repo.Create(r.Context(), postID, &req)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "Post already has a variant with this title")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalError(w, "Failed to create title variant")
+		return
+	}
+
+	response.Created(w, variant)
+}
+
+// Delete godoc
+// @Summary Delete title variant
+// @Description Remove a title variant from a post
+// @Tags title-variants
+// @Param id path string true "Post ID"
+// @Param variantId path string true "Variant ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants/{variantId} [delete]
+func (h *TitleVariantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	postID, variantID, ok := parseVariantParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), postID, variantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Title variant not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete title variant")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Assign godoc
+// @Summary Assign title variant
+// @Description Deterministically pick the title variant shown to the current visitor (public endpoint).
+// @Description The visitor is identified by the visitor_id parameter, the cms_visitor cookie, or a client fingerprint.
+// @Tags title-variants
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param visitor_id query string false "Stable visitor identifier"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants/assign [get]
+func (h *TitleVariantHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	postID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	variants, err := h.repo.ListByPost(r.Context(), postID)
+	if err != nil {
+		response.InternalError(w, "Failed to list title variants")
+		return
+	}
+
+	assignment := models.TitleVariantAssignment{PostID: postID}
+	if len(variants) == 0 {
+		post, err := h.postRepo.GetByID(r.Context(), postID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				response.NotFound(w, "Post not found")
+				return
+			}
+			response.InternalError(w, "Failed to get post")
+			return
+		}
+		assignment.Title = post.Title
+		response.OK(w, assignment)
+		return
+	}
+
+	visitorID := visitorIdentifier(w, r)
+	hasher := fnv.New32a()
+	hasher.Write([]byte(visitorID + ":" + postID.String()))
+	variant := variants[int(hasher.Sum32()%uint32(len(variants)))]
+
+	assignment.VariantID = &variant.ID
+	assignment.Title = variant.Title
+	response.OK(w, assignment)
+}
+
+// RecordImpression godoc
+// @Summary Record title variant impression
+// @Description Count one impression of a title variant (public endpoint)
+// @Tags title-variants
+// @Param id path string true "Post ID"
+// @Param variantId path string true "Variant ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants/{variantId}/impression [post]
+func (h *TitleVariantHandler) RecordImpression(w http.ResponseWriter, r *http.Request) {
+	postID, variantID, ok := parseVariantParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RecordImpression(r.Context(), postID, variantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Title variant not found")
+			return
+		}
+		response.InternalError(w, "Failed to record impression")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// RecordClick godoc
+// @Summary Record title variant click
+// @Description Count one click-through on a title variant (public endpoint)
+// @Tags title-variants
+// @Param id path string true "Post ID"
+// @Param variantId path string true "Variant ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants/{variantId}/click [post]
+func (h *TitleVariantHandler) RecordClick(w http.ResponseWriter, r *http.Request) {
+	postID, variantID, ok := parseVariantParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RecordClick(r.Context(), postID, variantID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Title variant not found")
+			return
+		}
+		response.InternalError(w, "Failed to record click")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Results godoc
+// @Summary Title variant results
+// @Description Get click-through rates of each variant with lift and z-score against the control
+// @Tags title-variants
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/title-variants/results [get]
+func (h *TitleVariantHandler) Results(w http.ResponseWriter, r *http.Request) {
+	postID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	variants, err := h.repo.ListByPost(r.Context(), postID)
+	if err != nil {
+		response.InternalError(w, "Failed to list title variants")
+		return
+	}
+
+	response.OK(w, computeVariantResults(variants))
+}
+
+// computeVariantResults compares every variant against the first one using a
+// two-proportion z-test. A winner is only flagged once it is significantly
+// better than every other variant.
+func computeVariantResults(variants []models.TitleVariant) []models.TitleVariantResult {
+	results := make([]models.TitleVariantResult, len(variants))
+	if len(variants) == 0 {
+		return results
+	}
+
+	control := variants[0]
+	best := 0
+	for i, v := range variants {
+		results[i] = models.TitleVariantResult{TitleVariant: v, ClickRate: clickRate(v)}
+		if i > 0 {
+			results[i].ZScore = zScore(control, v)
+			if results[0].ClickRate > 0 {
+				results[i].Lift = (results[i].ClickRate - results[0].ClickRate) / results[0].ClickRate
+			}
+		}
+		if results[i].ClickRate > results[best].ClickRate {
+			best = i
+		}
+	}
+
+	if len(variants) > 1 {
+		significant := true
+		for i, v := range variants {
+			if i != best && zScore(v, variants[best]) < significanceZ {
+				significant = false
+				break
+			}
+		}
+		results[best].Winner = significant
+	}
+
+	return results
+}
+
+func clickRate(v models.TitleVariant) float64 {
+	if v.Impressions == 0 {
+		return 0
+	}
+	return float64(v.Clicks) / float64(v.Impressions)
+}
+
+// zScore returns how many standard errors b's click rate is above a's
+func zScore(a, b models.TitleVariant) float64 {
+	if a.Impressions == 0 || b.Impressions == 0 {
+		return 0
+	}
+	pooled := float64(a.Clicks+b.Clicks) / float64(a.Impressions+b.Impressions)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(a.Impressions) + 1/float64(b.Impressions)))
+	if se == 0 {
+		return 0
+	}
+	return (clickRate(b) - clickRate(a)) / se
+}
+
+// visitorIdentifier returns a stable identifier for the visitor, issuing a
+// cookie derived from the client fingerprint when none is present
+func visitorIdentifier(w http.ResponseWriter, r *http.Request) string {
+	if id := r.URL.Query().Get("visitor_id"); id != "" {
+		return id
+	}
+	if cookie, err := r.Cookie(visitorCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	sum := sha256.Sum256([]byte(clientIP(r) + "|" + r.Header.Get("User-Agent") + "|" + r.Header.Get("Accept-Language")))
+	id := hex.EncodeToString(sum[:16])
+	http.SetCookie(w, &http.Cookie{
+		Name:     visitorCookieName,
+		Value:    id,
+		Path:     "/",
+		Expires:  time.Now().Add(visitorCookieTTL),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+func parseVariantParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	postID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	variantID, err := parseUUID(chi.URLParam(r, "variantId"))
+	if err != nil {
+		response.BadRequest(w, "Invalid variant ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return postID, variantID, true
+}
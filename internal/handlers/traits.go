@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// currencyCode matches ISO 4217 alphabetic codes
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateTraitNames rejects unknown content type traits
+func validateTraitNames(traits []string, validationErrors map[string]string) {
+	for _, t := range traits {
+		if !models.ValidTrait(t) {
+			validationErrors["traits"] = "Unknown trait " + t + " (must be expiring, geolocated, priced or bookable)"
+			return
+		}
+	}
+}
+
+// validatePostTraits checks the fields that the content type's traits
+// require on a post, as it will be stored
+func validatePostTraits(ct *models.ContentType, post *models.ContentPost, validationErrors map[string]string) {
+	if ct.HasTrait(models.TraitExpiring) && post.ExpiresAt == nil {
+		validationErrors["expires_at"] = "Expiry is required for " + ct.Slug + " posts"
+	}
+	if ct.HasTrait(models.TraitGeolocated) && (post.Latitude == nil || post.Longitude == nil) {
+		validationErrors["location"] = "Latitude and longitude are required for " + ct.Slug + " posts"
+	}
+	if ct.HasTrait(models.TraitBookable) && post.StartsAt == nil {
+		validationErrors["starts_at"] = "Start is required for " + ct.Slug + " posts"
+	}
+
+	if !ct.HasTrait(models.TraitPriced) && !ct.HasTrait(models.TraitBookable) {
+		return
+	}
+	var metadata map[string]interface{}
+	if len(post.Metadata) > 0 {
+		if err := json.Unmarshal(post.Metadata, &metadata); err != nil {
+			validationErrors["metadata"] = "Metadata must be a JSON object"
+			return
+		}
+	}
+
+	if ct.HasTrait(models.TraitPriced) {
+		if price, ok := metadata["price"].(float64); !ok || price < 0 {
+			validationErrors["metadata.price"] = "Price must be a non-negative number"
+		}
+		if currency, ok := metadata["currency"].(string); !ok || !currencyCode.MatchString(currency) {
+			validationErrors["metadata.currency"] = "Currency must be a three-letter ISO 4217 code"
+		}
+	}
+	if ct.HasTrait(models.TraitBookable) {
+		if raw, ok := metadata["capacity"]; ok {
+			if capacity, ok := raw.(float64); !ok || capacity < 1 || capacity != math.Trunc(capacity) {
+				validationErrors["metadata.capacity"] = "Capacity must be a positive integer"
+			}
+		}
+	}
+}
+
+// applyPostUpdate returns a copy of post with the fields set in req applied,
+// for validating the result of an update
+func applyPostUpdate(post models.ContentPost, req *models.UpdatePostRequest) *models.ContentPost {
+	if req.ContentTypeID != nil {
+		post.ContentTypeID = *req.ContentTypeID
+	}
+	if req.Metadata != nil {
+		post.Metadata = *req.Metadata
+	}
+	if req.Latitude != nil && req.Longitude != nil {
+		post.Latitude, post.Longitude = req.Latitude, req.Longitude
+	}
+	if req.StartsAt != nil {
+		post.StartsAt = req.StartsAt
+	}
+	if req.ExpiresAt != nil {
+		post.ExpiresAt = req.ExpiresAt
+	}
+	return &post
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/ai"
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// localePattern matches normalized language tags such as "fr" or "pt-br"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// TranslationHandler tracks the translations of posts into the supported
+// locales. Posts are written in the source locale, the first supported
+// one.
+type TranslationHandler struct {
+	repo      *repository.TranslationRepository
+	postRepo  *repository.ContentPostRepository
+	assistant *ai.Assistant
+	locales   []string
+}
+
+// NewTranslationHandler returns a handler for the supported locales,
+// source locale first. Without supported locales posts can be translated
+// into any locale. Prefilling responds with 503 when assistant is nil.
+func NewTranslationHandler(repo *repository.TranslationRepository, postRepo *repository.ContentPostRepository, assistant *ai.Assistant, supported []string) *TranslationHandler {
+	h := &TranslationHandler{repo: repo, postRepo: postRepo, assistant: assistant}
+	for _, tag := range supported {
+		if tag = locale.Normalize(tag); tag != "" && !slices.Contains(h.locales, tag) {
+			h.locales = append(h.locales, tag)
+		}
+	}
+	return h
+}
+
+// List godoc
+// @Summary List post translations
+// @Description Report the translation status of a post in every supported locale other than the source locale, and in any other locale it has a translation for: missing, in_progress, complete, or needs_update when the post's title, excerpt or content changed since the translation was saved
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Success 200 {object} response.APIResponse{data=models.PostTranslations}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/translations [get]
+func (h *TranslationHandler) List(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.loadPost(w, r)
+	if !ok {
+		return
+	}
+
+	translations, err := h.repo.ListByPost(r.Context(), post.ID)
+	if err != nil {
+		response.InternalError(w, "Failed to list translations")
+		return
+	}
+
+	result := models.PostTranslations{PostID: post.ID, Translations: []models.TranslationState{}}
+	if len(h.locales) > 0 {
+		result.SourceLocale = h.locales[0]
+	}
+	hash := translationSourceHash(post)
+	saved := make(map[string]bool, len(translations))
+	for _, t := range translations {
+		saved[t.Locale] = true
+	}
+	for _, tag := range h.targetLocales() {
+		if !saved[tag] {
+			result.Translations = append(result.Translations, models.TranslationState{Locale: tag, Status: models.TranslationMissing})
+		}
+	}
+	for i := range translations {
+		t := &translations[i]
+		result.Translations = append(result.Translations, models.TranslationState{
+			Locale:            t.Locale,
+			Status:            translationStatus(t, hash),
+			Title:             &t.Title,
+			MachineTranslated: t.MachineTranslated,
+			UpdatedAt:         &t.UpdatedAt,
+		})
+	}
+	slices.SortStableFunc(result.Translations, func(a, b models.TranslationState) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+
+	response.OK(w, result)
+}
+
+// Get godoc
+// @Summary Get a post translation
+// @Description Get a post's translation into a locale, with its status
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. fr or pt-br"
+// @Success 200 {object} response.APIResponse{data=models.PostTranslation}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/translations/{locale} [get]
+func (h *TranslationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tag, ok := h.parseLocale(w, r)
+	if !ok {
+		return
+	}
+	post, ok := h.loadPost(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := h.repo.Get(r.Context(), post.ID, tag)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Translation not found")
+			return
+		}
+		response.InternalError(w, "Failed to get translation")
+		return
+	}
+
+	t.Status = translationStatus(t, translationSourceHash(post))
+	response.OK(w, t)
+}
+
+// Save godoc
+// @Summary Save a post translation
+// @Description Create or replace a post's translation into a locale. Status is in_progress (the default) or complete; either way the translation is taken to match the post as it is now.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. fr or pt-br"
+// @Param body body models.SaveTranslationRequest true "Translation"
+// @Success 200 {object} response.APIResponse{data=models.PostTranslation}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/translations/{locale} [put]
+func (h *TranslationHandler) Save(w http.ResponseWriter, r *http.Request) {
+	tag, ok := h.parseLocale(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SaveTranslationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Status == "" {
+		req.Status = models.TranslationInProgress
+	}
+	validationErrors := make(map[string]string)
+	if req.Title == "" {
+		validationErrors["title"] = "Title is required"
+	}
+	if !models.ValidTranslationStatus(req.Status) {
+		validationErrors["status"] = "Status must be in_progress or complete"
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	post, ok := h.loadPost(w, r)
+	if !ok {
+		return
+	}
+
+	h.save(w, r, &models.PostTranslation{
+		PostID:     post.ID,
+		Locale:     tag,
+		Title:      req.Title,
+		Excerpt:    req.Excerpt,
+		Content:    req.Content,
+		Status:     req.Status,
+		SourceHash: translationSourceHash(post),
+	})
+}
+
+// Delete godoc
+// @Summary Delete a post translation
+// @Description Remove a post's translation into a locale, which is then reported missing
+// @Tags posts
+// @Param id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. fr or pt-br"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tag, ok := h.parseLocale(w, r)
+	if !ok {
+		return
+	}
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id, tag); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Translation not found")
+			return
+		}
+		response.InternalError(w, "Failed to delete translation")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Prefill godoc
+// @Summary Prefill a post translation
+// @Description Machine-translate a post's title, excerpt and content into a locale with the AI provider and save the result as an in_progress translation marked machine_translated, for an editor to review. An existing translation is only replaced with overwrite=true.
+// @Tags posts
+// @Produce json
+// @Param id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. fr or pt-br"
+// @Param overwrite query bool false "Replace an existing translation"
+// @Success 200 {object} response.APIResponse{data=models.PostTranslation}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Failure 502 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Router /api/v1/posts/{id}/translations/{locale}/prefill [post]
+func (h *TranslationHandler) Prefill(w http.ResponseWriter, r *http.Request) {
+	if h.assistant == nil {
+		response.Error(w, http.StatusServiceUnavailable, "AI_DISABLED", "No AI provider is configured")
+		return
+	}
+	tag, ok := h.parseLocale(w, r)
+	if !ok {
+		return
+	}
+	post, ok := h.loadPost(w, r)
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("overwrite") != "true" {
+		_, err := h.repo.Get(r.Context(), post.ID, tag)
+		if err == nil {
+			response.Conflict(w, "The post already has a translation into this locale; pass overwrite=true to replace it")
+			return
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			response.InternalError(w, "Failed to get translation")
+			return
+		}
+	}
+
+	t := &models.PostTranslation{
+		PostID:            post.ID,
+		Locale:            tag,
+		Status:            models.TranslationInProgress,
+		SourceHash:        translationSourceHash(post),
+		MachineTranslated: true,
+	}
+	var err error
+	t.Title, err = h.assistant.Translate(r.Context(), post.Title, tag)
+	if err == nil {
+		t.Excerpt, err = h.translateOptional(r, post.Excerpt, tag)
+	}
+	if err == nil {
+		t.Content, err = h.translateOptional(r, post.Content, tag)
+	}
+	if err != nil {
+		providerError(r.Context(), w, err)
+		return
+	}
+
+	h.save(w, r, t)
+}
+
+// translateOptional translates an optional field, leaving it nil or empty
+func (h *TranslationHandler) translateOptional(r *http.Request, text *string, language string) (*string, error) {
+	if text == nil || *text == "" {
+		return text, nil
+	}
+	translated, err := h.assistant.Translate(r.Context(), *text, language)
+	if err != nil {
+		return nil, err
+	}
+	return &translated, nil
+}
+
+func (h *TranslationHandler) save(w http.ResponseWriter, r *http.Request, t *models.PostTranslation) {
+	saved, err := h.repo.Save(r.Context(), t)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.NotFound(w, "Post not found")
+			return
+		}
+		response.InternalErrorWithErr(w, "Failed to save translation", err)
+		return
+	}
+
+	response.OK(w, saved)
+}
+
+// loadPost loads the post of the request, writing the error response when
+// it can't
+func (h *TranslationHandler) loadPost(w http.ResponseWriter, r *http.Request) (*models.ContentPost, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid post ID")
+		return nil, false
+	}
+
+	post, err := h.postRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "Post not found")
+			return nil, false
+		}
+		response.InternalError(w, "Failed to get post")
+		return nil, false
+	}
+	return post, true
+}
+
+// parseLocale reads the locale of the request, which must be a supported
+// locale other than the source locale when locales are configured
+func (h *TranslationHandler) parseLocale(w http.ResponseWriter, r *http.Request) (string, bool) {
+	tag := locale.Normalize(chi.URLParam(r, "locale"))
+	switch {
+	case !localePattern.MatchString(tag):
+		response.ValidationError(w, map[string]string{"locale": "Must be a language tag such as fr or pt-br"})
+		return "", false
+	case len(h.locales) > 0 && !slices.Contains(h.targetLocales(), tag):
+		response.ValidationError(w, map[string]string{"locale": "Must be a supported locale other than the source locale " + h.locales[0]})
+		return "", false
+	}
+	return tag, true
+}
+
+// targetLocales are the locales posts are translated into
+func (h *TranslationHandler) targetLocales() []string {
+	if len(h.locales) == 0 {
+		return nil
+	}
+	return h.locales[1:]
+}
+
+// translationSourceHash identifies the translatable text of a post
+func translationSourceHash(post *models.ContentPost) string {
+	sum := sha256.New()
+	sum.Write([]byte(post.Title))
+	for _, field := range []*string{post.Excerpt, post.Content} {
+		sum.Write([]byte{0})
+		if field != nil {
+			sum.Write([]byte(*field))
+		}
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// translationStatus is the status of a saved translation given the hash of
+// its post's current text
+func translationStatus(t *models.PostTranslation, sourceHash string) string {
+	if t.SourceHash != sourceHash {
+		return models.TranslationNeedsUpdate
+	}
+	return t.Status
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits, in bytes. bcrypt ignores everything past 72
+// bytes, so longer passwords are rejected rather than silently truncated.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+type UserHandler struct {
+	repo        *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+}
+
+func NewUserHandler(repo *repository.UserRepository, sessionRepo *repository.SessionRepository) *UserHandler {
+	return &UserHandler{repo: repo, sessionRepo: sessionRepo}
+}
+
+// List godoc
+// @Summary List users
+// @Description Get all users, newest first
+// @Tags users
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /api/v1/users [get]
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter := parsePaginationParams(r)
+
+	users, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, "Failed to list users")
+		return
+	}
+
+	out := make([]*models.UserResponse, len(users))
+	for i := range users {
+		out[i] = users[i].ToResponse()
+	}
+
+	response.JSONWithMeta(w, http.StatusOK, out, &response.Meta{
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int(total)/filter.PageSize + 1,
+	})
+}
+
+// Get godoc
+// @Summary Get user by ID
+// @Description Get a single user by their ID
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.APIResponse{data=models.UserResponse}
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id} [get]
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		response.InternalError(w, "Failed to get user")
+		return
+	}
+
+	response.OK(w, user.ToResponse())
+}
+
+// Create godoc
+// @Summary Create user
+// @Description Create a user with a password. Role defaults to user.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param body body models.CreateUserRequest true "User data"
+// @Success 201 {object} response.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/users [post]
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateUserRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	user := &models.User{
+		Email:    strings.TrimSpace(req.Email),
+		FullName: strings.TrimSpace(req.FullName),
+		Role:     models.RoleUser,
+		IsActive: true,
+	}
+	if req.Role != nil {
+		user.Role = *req.Role
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+
+	validationErrors := validateUser(user)
+	if msg := validatePassword(req.Password); msg != "" {
+		validationErrors["password"] = msg
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		response.InternalErrorWithErr(w, "Failed to hash password", err)
+		return
+	}
+	user.PasswordHash = string(hash)
+
+	if err := h.repo.Create(r.Context(), user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "User with this email already exists")
+			return
+		}
+		response.InternalError(w, "Failed to create user")
+		return
+	}
+
+	response.Created(w, user.ToResponse())
+}
+
+// Update godoc
+// @Summary Update user
+// @Description Update a user's email, name, role, active flag or password. A new password or deactivation ends the user's sessions.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body models.UpdateUserRequest true "User data"
+// @Success 200 {object} response.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/users/{id} [put]
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		response.InternalError(w, "Failed to get user")
+		return
+	}
+	wasActive := user.IsActive
+
+	if req.Email != nil {
+		user.Email = strings.TrimSpace(*req.Email)
+	}
+	if req.FullName != nil {
+		user.FullName = strings.TrimSpace(*req.FullName)
+	}
+	if req.Role != nil {
+		user.Role = *req.Role
+	}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+
+	validationErrors := validateUser(user)
+	if req.Password != nil {
+		if msg := validatePassword(*req.Password); msg != "" {
+			validationErrors["password"] = msg
+		}
+	}
+	if isCaller(r.Context(), id) {
+		if !user.IsActive {
+			validationErrors["is_active"] = "You can't deactivate your own account"
+		}
+		if user.Role != models.RoleAdmin {
+			validationErrors["role"] = "You can't remove your own admin role"
+		}
+	}
+	if len(validationErrors) > 0 {
+		response.ValidationError(w, validationErrors)
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), user); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		if errors.Is(err, repository.ErrDuplicate) {
+			response.Conflict(w, "User with this email already exists")
+			return
+		}
+		response.InternalError(w, "Failed to update user")
+		return
+	}
+
+	if req.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			response.InternalErrorWithErr(w, "Failed to hash password", err)
+			return
+		}
+		if err := h.repo.SetPassword(r.Context(), id, string(hash)); err != nil {
+			response.InternalError(w, "Failed to update password")
+			return
+		}
+	}
+
+	if req.Password != nil || (wasActive && !user.IsActive) {
+		h.endSessions(r.Context(), id)
+	}
+
+	response.OK(w, user.ToResponse())
+}
+
+// Deactivate godoc
+// @Summary Deactivate user
+// @Description Mark a user inactive and end their sessions. Their posts and history are kept; reactivate them by updating is_active.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.APIResponse{data=models.UserResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /api/v1/users/{id}/deactivate [post]
+func (h *UserHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+	if isCaller(r.Context(), id) {
+		response.BadRequest(w, "You can't deactivate your own account")
+		return
+	}
+
+	user, err := h.repo.Deactivate(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		response.InternalError(w, "Failed to deactivate user")
+		return
+	}
+
+	h.endSessions(r.Context(), id)
+
+	response.OK(w, user.ToResponse())
+}
+
+// Delete godoc
+// @Summary Delete user
+// @Description Delete a user. Users who still author posts, post templates or annotations can only be deactivated.
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /api/v1/users/{id} [delete]
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+	if isCaller(r.Context(), id) {
+		response.BadRequest(w, "You can't delete your own account")
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.NotFound(w, "User not found")
+			return
+		}
+		if errors.Is(err, repository.ErrForeignKey) {
+			response.Conflict(w, "User still authors content; deactivate them instead")
+			return
+		}
+		response.InternalError(w, "Failed to delete user")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// endSessions logs a user out everywhere. Inactive users are rejected by
+// the authenticator anyway, so a failure is only logged.
+func (h *UserHandler) endSessions(ctx context.Context, id uuid.UUID) {
+	if err := h.sessionRepo.DeleteForUser(ctx, id); err != nil {
+		reqctx.Logf(ctx, "[ERROR] Failed to end sessions of user %s: %v", id, err)
+	}
+}
+
+// isCaller reports whether id is the authenticated user making the request
+func isCaller(ctx context.Context, id uuid.UUID) bool {
+	user := auth.UserFrom(ctx)
+	return user != nil && user.ID == id
+}
+
+func validateUser(user *models.User) map[string]string {
+	validationErrors := make(map[string]string)
+	if user.Email == "" {
+		validationErrors["email"] = "Email is required"
+	} else if _, err := mail.ParseAddress(user.Email); err != nil {
+		validationErrors["email"] = "Invalid email address"
+	}
+	if user.FullName == "" {
+		validationErrors["full_name"] = "Full name is required"
+	}
+	if user.Role < models.RoleUser || user.Role > models.RoleAdmin {
+		validationErrors["role"] = "Role must be user, editor or admin"
+	}
+	return validationErrors
+}
+
+func validatePassword(password string) string {
+	switch {
+	case password == "":
+		return "Password is required"
+	case len(password) < minPasswordLength:
+		return "Password must be at least 8 characters"
+	case len(password) > maxPasswordLength:
+		return "Password must be at most 72 bytes"
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+// Package imaging holds the small amount of image processing the CMS does
+// itself, using only the standard library decoders.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	"image/png"
+	"io"
+)
+
+// Decode reads a PNG, JPEG or GIF image
+func Decode(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// Size reads the width and height of a PNG, JPEG or GIF image from its
+// header, without decoding the pixels
+func Size(r io.Reader) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode image header: %w", err)
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// SquareThumbnail center-crops src to a square and scales it to size x size.
+// Downscaling averages every source pixel that falls into a target pixel, so
+// large logos stay smooth at favicon sizes.
+func SquareThumbnail(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	if side == 0 {
+		return dst
+	}
+
+	for dy := 0; dy < size; dy++ {
+		sy0 := y0 + dy*side/size
+		sy1 := y0 + (dy+1)*side/size
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < size; dx++ {
+			sx0 := x0 + dx*side/size
+			sx1 := x0 + (dx+1)*side/size
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			dst.SetNRGBA(dx, dy, average(src, sx0, sy0, sx1, sy1))
+		}
+	}
+
+	return dst
+}
+
+// average returns the mean colour of the source rectangle, weighting colour
+// channels by alpha so transparent pixels do not darken edges
+func average(src image.Image, x0, y0, x1, y1 int) color.NRGBA {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			cr, cg, cb, ca := src.At(x, y).RGBA() // alpha-premultiplied, 16-bit
+			r += uint64(cr)
+			g += uint64(cg)
+			b += uint64(cb)
+			a += uint64(ca)
+			n++
+		}
+	}
+	if a == 0 {
+		return color.NRGBA{}
+	}
+
+	return color.NRGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: uint8(a / n >> 8),
+	}
+}
+
+// EncodePNG encodes img as PNG
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,193 @@
+// Package integrity finds the media, posts and tags a post refers to from
+// its metadata and content, so references to deleted records can be found
+// and removed.
+package integrity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of referenced records
+const (
+	KindMedia = "media"
+	KindPost  = "post"
+	KindTag   = "tag"
+)
+
+// Where a reference was found
+const (
+	SourceMetadata = "metadata"
+	SourceContent  = "content"
+)
+
+// contentRef matches data-media-id, data-post-id and data-tag-id attributes
+var contentRef = regexp.MustCompile(`(?i)data-(media|post|tag)-id\s*=\s*["']([0-9a-f-]{36})["']`)
+
+// Reference is a UUID in a post that names a record of Kind. Path locates it:
+// a JSON path under metadata (e.g. metadata.gallery_media_ids[2]) or the
+// attribute in content (e.g. content[data-media-id]).
+type Reference struct {
+	Kind   string
+	ID     uuid.UUID
+	Source string
+	Path   string
+}
+
+// Target identifies the referenced record
+type Target struct {
+	Kind string
+	ID   uuid.UUID
+}
+
+// Target returns the record ref points at
+func (ref *Reference) Target() Target {
+	return Target{Kind: ref.Kind, ID: ref.ID}
+}
+
+// Extract returns the references in a post's metadata and content. Metadata
+// values count as references when their key ends in "id" or "ids" and names
+// the kind, e.g. hero_image_id, related_post_ids or tagId.
+func Extract(metadata []byte, content string) ([]Reference, error) {
+	var refs []Reference
+	if len(bytes.TrimSpace(metadata)) > 0 {
+		value, err := decode(metadata)
+		if err != nil {
+			return nil, err
+		}
+		walk(value, "", SourceMetadata, func(kind, path, s string) {
+			if id, err := uuid.Parse(s); err == nil {
+				refs = append(refs, Reference{Kind: kind, ID: id, Source: SourceMetadata, Path: path})
+			}
+		})
+	}
+
+	seen := make(map[Reference]bool)
+	for _, m := range contentRef.FindAllStringSubmatch(content, -1) {
+		id, err := uuid.Parse(m[2])
+		if err != nil {
+			continue
+		}
+		kind := strings.ToLower(m[1])
+		ref := Reference{Kind: kind, ID: id, Source: SourceContent, Path: fmt.Sprintf("content[data-%s-id]", kind)}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// Clean removes the metadata references to missing records: keys holding
+// one are deleted and ID list entries dropped. It returns the new metadata
+// and the number of references removed. References in content are left
+// alone, since removing markup could break the page.
+func Clean(metadata []byte, missing map[Target]bool) ([]byte, int, error) {
+	value, err := decode(metadata)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	removed := 0
+	value, _ = prune(value, "", func(kind, s string) bool {
+		id, err := uuid.Parse(s)
+		if err == nil && missing[Target{Kind: kind, ID: id}] {
+			removed++
+			return false
+		}
+		return true
+	})
+	if removed == 0 {
+		return metadata, 0, nil
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	return out, removed, nil
+}
+
+func decode(metadata []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(metadata))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	return value, nil
+}
+
+// walk calls fn for every string under a reference key, in key order
+func walk(value interface{}, kind, path string, fn func(kind, path, s string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walk(v[k], keyKind(k), path+"."+k, fn)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walk(item, kind, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case string:
+		if kind != "" {
+			fn(kind, path, v)
+		}
+	}
+}
+
+// prune returns value without the strings under reference keys that keep
+// rejects, and whether value itself is kept
+func prune(value interface{}, kind string, keep func(kind, s string) bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if pruned, ok := prune(child, keyKind(k), keep); ok {
+				v[k] = pruned
+			} else {
+				delete(v, k)
+			}
+		}
+		return v, true
+	case []interface{}:
+		kept := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if pruned, ok := prune(item, kind, keep); ok {
+				kept = append(kept, pruned)
+			}
+		}
+		return kept, true
+	case string:
+		return v, kind == "" || keep(kind, v)
+	}
+	return value, true
+}
+
+// keyKind returns the kind of record a metadata key refers to, or "" when
+// it doesn't look like a reference
+func keyKind(key string) string {
+	k := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	if !strings.HasSuffix(k, "id") && !strings.HasSuffix(k, "ids") {
+		return ""
+	}
+	switch {
+	case strings.Contains(k, "media") || strings.Contains(k, "image"):
+		return KindMedia
+	case strings.Contains(k, "tag"):
+		return KindTag
+	case strings.Contains(k, "post"):
+		return KindPost
+	}
+	return ""
+}
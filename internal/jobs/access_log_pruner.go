@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// AccessLogPruner deletes access log entries older than the retention window
+// once a day
+type AccessLogPruner struct {
+	repo      *repository.AccessLogRepository
+	retention time.Duration
+	locker    *leader.Locker
+}
+
+func NewAccessLogPruner(repo *repository.AccessLogRepository, retention time.Duration, locker *leader.Locker) *AccessLogPruner {
+	return &AccessLogPruner{repo: repo, retention: retention, locker: locker}
+}
+
+// Run prunes once immediately and then daily until ctx is cancelled, on one
+// replica at a time
+func (p *AccessLogPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, p.locker, "access-log-pruner", p.prune)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *AccessLogPruner) prune(ctx context.Context) {
+	cutoff := time.Now().Add(-p.retention)
+	deleted, err := p.repo.Prune(ctx, cutoff)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Access log pruning failed: %v", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Access log pruning removed %d entries older than %s", deleted, cutoff.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+type usageKey struct {
+	day    time.Time
+	client string
+	method string
+	route  string
+}
+
+type usageCount struct {
+	count int64
+	last  time.Time
+}
+
+// APIUsageRecorder counts API calls in memory and periodically adds them to
+// the daily rollup table, so recording a request never touches the database
+type APIUsageRecorder struct {
+	repo          *repository.APIUsageRepository
+	flushInterval time.Duration
+	retention     time.Duration
+	locker        *leader.Locker
+
+	mu     sync.Mutex
+	counts map[usageKey]*usageCount
+}
+
+func NewAPIUsageRecorder(repo *repository.APIUsageRepository, flushInterval, retention time.Duration, locker *leader.Locker) *APIUsageRecorder {
+	return &APIUsageRecorder{
+		repo:          repo,
+		flushInterval: flushInterval,
+		retention:     retention,
+		locker:        locker,
+		counts:        make(map[usageKey]*usageCount),
+	}
+}
+
+// Record counts one call; it implements middleware.UsageRecorder
+func (u *APIUsageRecorder) Record(client, method, route string) {
+	now := time.Now().UTC()
+	key := usageKey{day: now.Truncate(24 * time.Hour), client: client, method: method, route: route}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	c, ok := u.counts[key]
+	if !ok {
+		c = &usageCount{}
+		u.counts[key] = c
+	}
+	c.count++
+	c.last = now
+}
+
+// Run flushes buffered counts on every interval and once more when ctx is
+// cancelled. Every replica flushes its own counts; old rollups are pruned
+// once a day by one replica at a time.
+func (u *APIUsageRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.flushInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			u.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			u.flush(ctx)
+
+			if u.retention > 0 && time.Since(lastPrune) > 24*time.Hour {
+				runLeased(ctx, u.locker, "api-usage-pruner", u.prune)
+				lastPrune = time.Now()
+			}
+		}
+	}
+}
+
+func (u *APIUsageRecorder) prune(ctx context.Context) {
+	if _, err := u.repo.Prune(ctx, time.Now().Add(-u.retention)); err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] Failed to prune api usage: %v", err)
+	}
+}
+
+func (u *APIUsageRecorder) flush(ctx context.Context) {
+	u.mu.Lock()
+	counts := u.counts
+	u.counts = make(map[usageKey]*usageCount)
+	u.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	deltas := make([]repository.APIUsageDelta, 0, len(counts))
+	for k, c := range counts {
+		deltas = append(deltas, repository.APIUsageDelta{
+			Day: k.day, Client: k.client, Method: k.method, Route: k.route,
+			Count: c.count, LastCallAt: c.last,
+		})
+	}
+
+	if err := u.repo.AddDeltas(ctx, deltas); err != nil {
+		log.Printf("[ERROR] Failed to flush api usage (%d rows dropped): %v", len(deltas), err)
+	}
+}
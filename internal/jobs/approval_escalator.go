@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ApprovalEscalator escalates approval steps left pending past their SLA,
+// letting holders of the step's escalation role decide them too, and
+// announces each as an approval.escalated notification. A step is escalated
+// at most once.
+type ApprovalEscalator struct {
+	repo     *repository.ApprovalRepository
+	notifier *notify.Notifier
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewApprovalEscalator(repo *repository.ApprovalRepository, notifier *notify.Notifier, interval time.Duration, locker *leader.Locker) *ApprovalEscalator {
+	return &ApprovalEscalator{repo: repo, notifier: notifier, interval: interval, locker: locker}
+}
+
+// Run escalates overdue steps once immediately and then on every interval
+// until ctx is cancelled, on one replica at a time
+func (e *ApprovalEscalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, e.locker, "approval-escalator", e.escalate)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *ApprovalEscalator) escalate(ctx context.Context) {
+	requests, err := e.repo.EscalateDue(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] %v", err)
+		}
+		return
+	}
+
+	for i := range requests {
+		e.notifier.Notify(ctx, notify.Approval(notify.EventApprovalEscalated, "Approval step overdue", &requests[i]))
+	}
+	if len(requests) > 0 {
+		log.Printf("Escalated %d overdue approval steps", len(requests))
+	}
+}
@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
+)
+
+// archiveBatch is the number of rows written to one archive file
+const archiveBatch = 5000
+
+// Archiver moves contact submissions and access log entries older than
+// the retention window into gzip-compressed NDJSON files in object storage
+// once a day, deleting them from the database once stored
+type Archiver struct {
+	repo   *repository.ArchiveRepository
+	store  storage.Storage
+	bucket string
+	age    time.Duration
+	locker *leader.Locker
+}
+
+func NewArchiver(repo *repository.ArchiveRepository, store storage.Storage, bucket string, age time.Duration, locker *leader.Locker) *Archiver {
+	return &Archiver{repo: repo, store: store, bucket: bucket, age: age, locker: locker}
+}
+
+// Run archives once immediately and then daily until ctx is cancelled, on
+// one replica at a time
+func (a *Archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, a.locker, "archiver", a.archive)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Archiver) archive(ctx context.Context) {
+	tables := make([]string, 0, len(repository.ArchiveTables))
+	for table := range repository.ArchiveTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	cutoff := time.Now().Add(-a.age)
+	for _, table := range tables {
+		archived, err := a.archiveTable(ctx, table, cutoff)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Archiving %s failed: %v", table, err)
+		}
+		if archived > 0 {
+			log.Printf("Archived %d %s rows older than %s", archived, table, cutoff.Format("2006-01-02"))
+		}
+	}
+}
+
+// archiveTable stores the table's old rows a batch at a time, returning how
+// many were archived
+func (a *Archiver) archiveTable(ctx context.Context, table string, cutoff time.Time) (int64, error) {
+	var archived int64
+	for ctx.Err() == nil {
+		rows, err := a.repo.ListOlder(ctx, table, cutoff, archiveBatch)
+		if err != nil {
+			return archived, err
+		}
+		if len(rows) == 0 {
+			return archived, nil
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		ids := make([]uuid.UUID, len(rows))
+		for i, row := range rows {
+			zw.Write(row.Data)
+			zw.Write([]byte{'\n'})
+			ids[i] = row.ID
+		}
+		if err := zw.Close(); err != nil {
+			return archived, fmt.Errorf("failed to compress archive: %w", err)
+		}
+
+		key := fmt.Sprintf("%s/%s-%s.ndjson.gz", table, time.Now().UTC().Format("20060102T150405Z"), rows[0].ID)
+		if err := a.store.Put(ctx, a.bucket, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "application/gzip"); err != nil {
+			return archived, fmt.Errorf("failed to store %s: %w", key, err)
+		}
+
+		deleted, err := a.repo.Delete(ctx, table, ids)
+		if err != nil {
+			return archived, err
+		}
+		archived += deleted
+
+		if len(rows) < archiveBatch {
+			return archived, nil
+		}
+	}
+	return archived, ctx.Err()
+}
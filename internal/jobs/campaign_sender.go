@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// CampaignDigest is the template data for a campaign email
+type CampaignDigest struct {
+	Name           string
+	Subject        string
+	Intro          string
+	Posts          []CampaignDigestPost
+	SubscriberName string
+	UnsubscribeURL string
+	SiteURL        string
+}
+
+// CampaignDigestPost is a post as listed in a campaign email
+type CampaignDigestPost struct {
+	Title   string
+	Excerpt string
+	URL     string
+}
+
+// CampaignSender queues campaign emails for subscribers in throttled
+// batches. Delivery itself is left to the EmailDispatcher.
+type CampaignSender struct {
+	campaigns   *repository.CampaignRepository
+	subscribers *repository.SubscriberRepository
+	posts       *repository.ContentPostRepository
+	mail        *mailer.Mailer
+	urls        *permalink.Builder
+	cfg         config.MailConfig
+	locker      *leader.Locker
+}
+
+func NewCampaignSender(campaigns *repository.CampaignRepository, subscribers *repository.SubscriberRepository, posts *repository.ContentPostRepository, mail *mailer.Mailer, urls *permalink.Builder, cfg config.MailConfig, locker *leader.Locker) *CampaignSender {
+	return &CampaignSender{campaigns: campaigns, subscribers: subscribers, posts: posts, mail: mail, urls: urls, cfg: cfg, locker: locker}
+}
+
+// Run processes due campaign batches on every poll interval until ctx is
+// cancelled, on one replica at a time
+func (s *CampaignSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, s.locker, "campaign-sender", s.processDue)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *CampaignSender) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		campaign, err := s.campaigns.ClaimDue(ctx, s.cfg.CampaignBatchInterval)
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) && ctx.Err() == nil {
+				log.Printf("[ERROR] Failed to claim campaign: %v", err)
+			}
+			return
+		}
+
+		if err := s.sendBatch(ctx, campaign); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Campaign %s: %v", campaign.ID, err)
+		}
+	}
+}
+
+// sendBatch queues the next batch of recipients for campaign, completing it
+// once no recipients are left
+func (s *CampaignSender) sendBatch(ctx context.Context, campaign *models.Campaign) error {
+	recipients, err := s.subscribers.ListSubscribedAfter(ctx, campaign.LastSubscriber, s.cfg.CampaignBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		log.Printf("Campaign %s completed: %d emails queued", campaign.ID, campaign.QueuedCount)
+		return s.campaigns.Complete(ctx, campaign.ID)
+	}
+
+	digest, err := s.digest(ctx, campaign)
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, sub := range recipients {
+		data := *digest
+		data.UnsubscribeURL = fmt.Sprintf("%s/api/v1/subscribers/unsubscribe?token=%s", s.cfg.PublicURL, sub.UnsubscribeToken)
+		if sub.Name != nil {
+			data.SubscriberName = *sub.Name
+		}
+
+		if _, err := s.mail.EnqueueForCampaign(ctx, campaign.ID, sub.Email, campaign.TemplateName, sub.Locale, data); err != nil {
+			// Record progress so far; the rest of the batch is retried next time
+			if queued > 0 {
+				if advErr := s.campaigns.AdvanceCursor(ctx, campaign.ID, recipients[queued-1].ID, queued); advErr != nil {
+					return advErr
+				}
+			}
+			return fmt.Errorf("failed to queue email for %s: %w", sub.Email, err)
+		}
+		queued++
+	}
+
+	return s.campaigns.AdvanceCursor(ctx, campaign.ID, recipients[len(recipients)-1].ID, queued)
+}
+
+// digest loads the campaign's posts once per batch. Posts that were deleted
+// or unpublished since the campaign was created are left out.
+func (s *CampaignSender) digest(ctx context.Context, campaign *models.Campaign) (*CampaignDigest, error) {
+	urls, err := s.urls.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &CampaignDigest{Name: campaign.Name, SiteURL: urls.BaseURL}
+	if campaign.Subject != nil {
+		digest.Subject = *campaign.Subject
+	}
+	if campaign.Intro != nil {
+		digest.Intro = *campaign.Intro
+	}
+
+	for _, id := range campaign.PostIDs {
+		post, err := s.posts.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if post.Status != models.PostStatusPublished {
+			continue
+		}
+
+		item := CampaignDigestPost{Title: post.Title, URL: urls.PostURL(post)}
+		if post.Excerpt != nil {
+			item.Excerpt = *post.Excerpt
+		}
+		digest.Posts = append(digest.Posts, item)
+	}
+
+	return digest, nil
+}
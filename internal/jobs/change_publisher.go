@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/changefeed"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// changeBatch is the number of changes read from the slot at a time
+const changeBatch = 500
+
+// ChangePublisher reads row changes on content tables from a logical
+// decoding slot and broadcasts them as change feed events. Changes are
+// consumed only once their events are sent, so events may repeat after a
+// crash but are never lost. Changes made while the publisher is stopped
+// are kept by the slot and sent when it resumes.
+type ChangePublisher struct {
+	repo     *repository.ChangeFeedRepository
+	slot     string
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewChangePublisher(repo *repository.ChangeFeedRepository, slot string, interval time.Duration, locker *leader.Locker) *ChangePublisher {
+	return &ChangePublisher{repo: repo, slot: slot, interval: interval, locker: locker}
+}
+
+// Run publishes changes on every interval until ctx is cancelled, on one
+// replica at a time
+func (p *ChangePublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	slotReady := false
+	for {
+		runLeased(ctx, p.locker, "change-publisher", func(ctx context.Context) {
+			if !slotReady {
+				if err := p.repo.EnsureSlot(ctx, p.slot); err != nil {
+					if ctx.Err() == nil {
+						log.Printf("[ERROR] %v", err)
+					}
+					return
+				}
+				slotReady = true
+			}
+			p.publish(ctx)
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ChangePublisher) publish(ctx context.Context) {
+	tables := changefeed.Tables()
+	for ctx.Err() == nil {
+		changes, err := p.repo.PeekChanges(ctx, p.slot, tables, changeBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] %v", err)
+			}
+			return
+		}
+		if len(changes) == 0 {
+			return
+		}
+
+		// Repeated changes to a row, such as view counts, are sent once
+		seen := make(map[changefeed.Event]bool)
+		var payloads []string
+		for _, change := range changes {
+			event, err := changefeed.Decode(change.Data)
+			if err != nil {
+				log.Printf("[ERROR] Skipping change at %s: %v", change.LSN, err)
+				continue
+			}
+			if event == nil || seen[*event] {
+				continue
+			}
+			seen[*event] = true
+			payload, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			payloads = append(payloads, string(payload))
+		}
+
+		if err := p.repo.Notify(ctx, changefeed.Channel, payloads); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] %v", err)
+			}
+			return
+		}
+		if err := p.repo.AdvanceSlot(ctx, p.slot, changes[len(changes)-1].LSN); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] %v", err)
+			}
+			return
+		}
+
+		if len(changes) < changeBatch {
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// DashboardRefresher recomputes the dashboard's materialized views on an
+// interval. A view that fails to refresh keeps its previous contents and is
+// retried on the next run.
+type DashboardRefresher struct {
+	repo     *repository.DashboardRepository
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewDashboardRefresher(repo *repository.DashboardRepository, interval time.Duration, locker *leader.Locker) *DashboardRefresher {
+	return &DashboardRefresher{repo: repo, interval: interval, locker: locker}
+}
+
+// Run refreshes once immediately and then on every interval until ctx is
+// cancelled, on one replica at a time
+func (d *DashboardRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, d.locker, "dashboard-refresher", d.refresh)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *DashboardRefresher) refresh(ctx context.Context) {
+	for _, view := range repository.DashboardViews {
+		if _, err := d.repo.Refresh(ctx, view); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[ERROR] %v", err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// digestPostLimit is the number of posts listed per digest section
+const digestPostLimit = 10
+
+// EditorDigest is the template data for an editor digest email. Counts and
+// lists of sections the editor didn't choose are nil.
+type EditorDigest struct {
+	Name           string
+	Frequency      string
+	From           time.Time
+	To             time.Time
+	Published      []EditorDigestPost
+	PublishedCount *int64
+	Drafts         []EditorDigestPost
+	DraftCount     *int64
+	TopViewed      []EditorDigestPost
+	NewContacts    *int64
+	SiteURL        string
+	AdminURL       string
+}
+
+// EditorDigestPost is a post as listed in a digest email
+type EditorDigestPost struct {
+	Title string
+	URL   string
+	Views int64
+}
+
+// DigestSender emails subscribed editors a daily or weekly summary of
+// editorial activity. Delivery itself is left to the EmailDispatcher.
+type DigestSender struct {
+	repo     *repository.DigestRepository
+	mail     *mailer.Mailer
+	siteURL  string
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewDigestSender(repo *repository.DigestRepository, mail *mailer.Mailer, siteURL string, interval time.Duration, locker *leader.Locker) *DigestSender {
+	return &DigestSender{repo: repo, mail: mail, siteURL: siteURL, interval: interval, locker: locker}
+}
+
+// Run queues due digests once immediately and then on every interval until
+// ctx is cancelled, on one replica at a time
+func (s *DigestSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, s.locker, "digest-sender", s.sendDue)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *DigestSender) sendDue(ctx context.Context) {
+	now := time.Now()
+	recipients, err := s.repo.ListDue(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to list due digests: %v", err)
+		}
+		return
+	}
+
+	queued := 0
+	for _, rcpt := range recipients {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.send(ctx, &rcpt, now); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] Digest for user %s: %v", rcpt.UserID, err)
+			}
+			continue
+		}
+		queued++
+	}
+	if queued > 0 {
+		log.Printf("Queued %d editor digests", queued)
+	}
+}
+
+// send queues one editor's digest, covering the time since their last one
+func (s *DigestSender) send(ctx context.Context, rcpt *models.DigestRecipient, now time.Time) error {
+	from := now.AddDate(0, 0, -1)
+	if rcpt.Frequency == models.DigestFrequencyWeekly {
+		from = now.AddDate(0, 0, -7)
+	}
+	if rcpt.LastSentAt != nil && rcpt.LastSentAt.After(from) {
+		from = *rcpt.LastSentAt
+	}
+
+	summary, err := s.repo.Summary(ctx, from, now, digestPostLimit)
+	if err != nil {
+		return err
+	}
+
+	locale := ""
+	if rcpt.Locale != nil {
+		locale = *rcpt.Locale
+	}
+	data := s.digest(rcpt, summary)
+	if _, err := s.mail.Enqueue(ctx, rcpt.Email, "editor_digest", locale, data); err != nil {
+		return err
+	}
+	return s.repo.MarkSent(ctx, rcpt.UserID, now)
+}
+
+func (s *DigestSender) digest(rcpt *models.DigestRecipient, summary *models.DigestSummary) *EditorDigest {
+	wants := func(section string) bool {
+		if len(rcpt.Sections) == 0 {
+			return true
+		}
+		for _, chosen := range rcpt.Sections {
+			if chosen == section {
+				return true
+			}
+		}
+		return false
+	}
+
+	d := &EditorDigest{
+		Name:      rcpt.FullName,
+		Frequency: rcpt.Frequency,
+		From:      summary.From,
+		To:        summary.To,
+		SiteURL:   s.siteURL,
+		AdminURL:  s.siteURL + "/admin/",
+	}
+	if wants(models.DigestSectionPublished) {
+		d.Published = s.posts(summary.Published, false)
+		d.PublishedCount = &summary.PublishedCount
+	}
+	if wants(models.DigestSectionDrafts) {
+		d.Drafts = s.posts(summary.Drafts, true)
+		d.DraftCount = &summary.DraftCount
+	}
+	if wants(models.DigestSectionTopViewed) {
+		d.TopViewed = s.posts(summary.TopViewed, false)
+	}
+	if wants(models.DigestSectionContacts) {
+		d.NewContacts = &summary.NewContacts
+	}
+	return d
+}
+
+// posts links posts to the site, or drafts to the admin UI since they
+// aren't public yet
+func (s *DigestSender) posts(posts []models.DigestPost, drafts bool) []EditorDigestPost {
+	out := make([]EditorDigestPost, 0, len(posts))
+	for _, p := range posts {
+		item := EditorDigestPost{Title: p.Title, URL: s.siteURL + "/posts/" + p.Slug, Views: p.Views}
+		if drafts {
+			item.URL = s.siteURL + "/admin/"
+		}
+		out = append(out, item)
+	}
+	return out
+}
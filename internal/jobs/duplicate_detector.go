@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/similarity"
+)
+
+// DuplicateDetector periodically compares the bodies of all live posts and
+// stores the pairs whose estimated similarity reaches the threshold, for the
+// duplicates report
+type DuplicateDetector struct {
+	repo      *repository.DuplicateRepository
+	threshold float64
+	interval  time.Duration
+	locker    *leader.Locker
+}
+
+func NewDuplicateDetector(repo *repository.DuplicateRepository, threshold float64, interval time.Duration, locker *leader.Locker) *DuplicateDetector {
+	return &DuplicateDetector{repo: repo, threshold: threshold, interval: interval, locker: locker}
+}
+
+// Run scans once immediately and then on every interval until ctx is
+// cancelled, on one replica at a time
+func (d *DuplicateDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, d.locker, "duplicate-detector", d.scan)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *DuplicateDetector) scan(ctx context.Context) {
+	start := time.Now()
+
+	var posts []models.ReportPost
+	var sigs []similarity.Signature
+	err := d.repo.EachPostText(ctx, func(p *models.PostText) error {
+		if sig, ok := similarity.Sign(p.Title + " " + p.Content); ok {
+			posts = append(posts, models.ReportPost{ID: p.ID})
+			sigs = append(sigs, sig)
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Duplicate scan failed: %v", err)
+		}
+		return
+	}
+
+	found := similarity.Duplicates(sigs, d.threshold)
+	pairs := make([]models.DuplicatePair, len(found))
+	for i, p := range found {
+		a, b := posts[p.A], posts[p.B]
+		// Stored with the smaller ID first so each pair appears once
+		if b.ID.String() < a.ID.String() {
+			a, b = b, a
+		}
+		pairs[i] = models.DuplicatePair{Post: a, Duplicate: b, Similarity: p.Similarity, DetectedAt: start}
+	}
+
+	if err := d.repo.Replace(ctx, pairs); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to store duplicate scan: %v", err)
+		}
+		return
+	}
+	log.Printf("Duplicate scan found %d pairs among %d posts in %s", len(pairs), len(posts), time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,150 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// staleSendingAfter is how long an email may sit in the sending state before
+// it is assumed its worker died and it is put back in the queue
+const staleSendingAfter = 10 * time.Minute
+
+// EmailDispatcher polls the outbound email queue and delivers due messages,
+// retrying failures with exponential backoff
+type EmailDispatcher struct {
+	repo     *repository.EmailRepository
+	sender   mailer.Sender
+	notifier *notify.Notifier
+	cfg      config.MailConfig
+	locker   *leader.Locker
+}
+
+func NewEmailDispatcher(repo *repository.EmailRepository, sender mailer.Sender, notifier *notify.Notifier, cfg config.MailConfig, locker *leader.Locker) *EmailDispatcher {
+	return &EmailDispatcher{repo: repo, sender: sender, notifier: notifier, cfg: cfg, locker: locker}
+}
+
+// Run dispatches once immediately and then on every poll interval until ctx is cancelled
+func (d *EmailDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch delivers due emails. Claiming is safe on every replica at once;
+// only releasing stale claims is left to one replica at a time.
+func (d *EmailDispatcher) dispatch(ctx context.Context) {
+	runLeased(ctx, d.locker, "email-stale-release", d.releaseStale)
+
+	emails, err := d.repo.ClaimDue(ctx, d.cfg.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to claim queued emails: %v", err)
+		}
+		return
+	}
+
+	for i := range emails {
+		d.deliver(ctx, &emails[i])
+	}
+}
+
+func (d *EmailDispatcher) releaseStale(ctx context.Context) {
+	if released, err := d.repo.ReleaseStale(ctx, staleSendingAfter); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to release stale emails: %v", err)
+		}
+	} else if released > 0 {
+		log.Printf("Released %d stale emails back to the queue", released)
+	}
+}
+
+func (d *EmailDispatcher) deliver(ctx context.Context, email *models.Email) {
+	msg := &mailer.Message{To: email.ToAddress, Subject: email.Subject}
+	if email.RequestID != nil {
+		// Correlate delivery logs and notifications with the queuing request
+		ctx = reqctx.WithRequestID(ctx, *email.RequestID)
+		msg.RequestID = *email.RequestID
+	}
+	if email.BodyText != nil {
+		msg.Text = *email.BodyText
+	}
+	if email.BodyHTML != nil {
+		msg.HTML = *email.BodyHTML
+		// Campaign emails are always tracked so campaigns can report opens
+		if d.cfg.TrackOpens || email.CampaignID != nil {
+			msg.HTML = withTrackingPixel(msg.HTML, fmt.Sprintf("%s/api/v1/emails/%s/open.gif", d.cfg.PublicURL, email.ID))
+		}
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := d.sender.Send(sendCtx, msg); err != nil {
+		var retryAt *time.Time
+		if email.Attempts < d.cfg.MaxAttempts {
+			t := time.Now().Add(backoff(email.Attempts))
+			retryAt = &t
+		}
+		if markErr := d.repo.MarkFailed(ctx, email.ID, err, retryAt); markErr != nil {
+			reqctx.Logf(ctx, "[ERROR] %v", markErr)
+		}
+		reqctx.Logf(ctx, "[ERROR] Email %s to %s failed (attempt %d): %v", email.ID, email.ToAddress, email.Attempts, err)
+		if retryAt == nil {
+			d.notifier.Notify(ctx, notify.Notification{
+				Event: notify.EventEmailDeliveryFailed,
+				Title: "Email delivery failed",
+				Text:  err.Error(),
+				Fields: []notify.Field{
+					{Label: "To", Value: email.ToAddress},
+					{Label: "Subject", Value: email.Subject},
+					{Label: "Attempts", Value: fmt.Sprint(email.Attempts)},
+				},
+			})
+		}
+		return
+	}
+
+	if err := d.repo.MarkSent(ctx, email.ID); err != nil {
+		reqctx.Logf(ctx, "[ERROR] %v", err)
+	}
+}
+
+// backoff returns the delay before the next attempt: 1m, 2m, 4m, ... capped at 1h
+func backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := time.Minute << (attempt - 1)
+	if attempt > 7 || delay > time.Hour {
+		return time.Hour
+	}
+	return delay
+}
+
+func withTrackingPixel(html, src string) string {
+	pixel := fmt.Sprintf(`<img src="%s" width="1" height="1" alt="" style="display:none">`, src)
+	if i := strings.LastIndex(strings.ToLower(html), "</body>"); i >= 0 {
+		return html[:i] + pixel + html[i:]
+	}
+	return html + pixel
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+)
+
+// runLeased runs fn only while holding the named lock, so a job scheduled on
+// every replica does its work on one at a time
+func runLeased(ctx context.Context, locker *leader.Locker, name string, fn func(context.Context)) {
+	_, err := locker.WithLease(ctx, name, func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Printf("[ERROR] Failed to take %s lock: %v", name, err)
+	}
+}
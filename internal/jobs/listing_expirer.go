@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ListingExpirer archives published posts of expiring content types once
+// their expires_at has passed
+type ListingExpirer struct {
+	repo     *repository.ContentPostRepository
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewListingExpirer(repo *repository.ContentPostRepository, interval time.Duration, locker *leader.Locker) *ListingExpirer {
+	return &ListingExpirer{repo: repo, interval: interval, locker: locker}
+}
+
+// Run expires once immediately and then on every interval until ctx is
+// cancelled, on one replica at a time
+func (e *ListingExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, e.locker, "listing-expirer", e.expire)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *ListingExpirer) expire(ctx context.Context) {
+	archived, err := e.repo.ExpireListings(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Listing expiry failed: %v", err)
+		}
+		return
+	}
+	if archived > 0 {
+		log.Printf("Listing expiry archived %d posts", archived)
+	}
+}
@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
+)
+
+// staleCopyingAfter is how long a copy may sit in the copying state before
+// it is assumed its worker died and it is put back in the queue
+const staleCopyingAfter = 30 * time.Minute
+
+// MediaReplicator polls the media replication queue and copies due objects
+// from the primary to the replica storage, retrying failures with
+// exponential backoff
+type MediaReplicator struct {
+	repo    *repository.MediaReplicationRepository
+	primary storage.Storage
+	replica storage.Storage
+	cfg     config.ReplicationConfig
+	locker  *leader.Locker
+}
+
+func NewMediaReplicator(repo *repository.MediaReplicationRepository, primary, replica storage.Storage, cfg config.ReplicationConfig, locker *leader.Locker) *MediaReplicator {
+	return &MediaReplicator{repo: repo, primary: primary, replica: replica, cfg: cfg, locker: locker}
+}
+
+// Run replicates once immediately and then on every interval until ctx is cancelled
+func (m *MediaReplicator) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.replicate(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicate copies due objects. Claiming is safe on every replica at once;
+// only releasing stale claims is left to one replica at a time.
+func (m *MediaReplicator) replicate(ctx context.Context) {
+	runLeased(ctx, m.locker, "media-replication-stale-release", m.releaseStale)
+
+	items, err := m.repo.ClaimDue(ctx, m.cfg.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to claim media replications: %v", err)
+		}
+		return
+	}
+
+	for i := range items {
+		m.copyObject(ctx, &items[i])
+	}
+}
+
+func (m *MediaReplicator) releaseStale(ctx context.Context) {
+	if released, err := m.repo.ReleaseStale(ctx, staleCopyingAfter); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to release stale media replications: %v", err)
+		}
+	} else if released > 0 {
+		log.Printf("Released %d stale media replications back to the queue", released)
+	}
+}
+
+func (m *MediaReplicator) copyObject(ctx context.Context, item *models.MediaReplication) {
+	copyCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	if err := m.copy(copyCtx, item); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		// A missing source never shows up by retrying
+		var retryAt *time.Time
+		if item.Attempts < m.cfg.MaxAttempts && !errors.Is(err, storage.ErrNotFound) {
+			t := time.Now().Add(backoff(item.Attempts))
+			retryAt = &t
+		}
+		if markErr := m.repo.MarkFailed(ctx, item.MediaID, item.ObjectKey, err, retryAt); markErr != nil {
+			log.Printf("[ERROR] %v", markErr)
+		}
+		log.Printf("[ERROR] Replicating %s/%s of media %s failed (attempt %d): %v",
+			item.BucketName, item.ObjectKey, item.MediaID, item.Attempts, err)
+		return
+	}
+
+	if err := m.repo.MarkReplicated(ctx, item.MediaID, item.ObjectKey); err != nil {
+		log.Printf("[ERROR] %v", err)
+	}
+}
+
+func (m *MediaReplicator) copy(ctx context.Context, item *models.MediaReplication) error {
+	src, _, err := m.primary.Get(ctx, item.BucketName, item.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	size, err := src.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	bucket := m.cfg.Bucket
+	if bucket == "" {
+		bucket = item.BucketName
+	}
+	return m.replica.Put(ctx, bucket, item.ObjectKey, src, size, item.MimeType)
+}
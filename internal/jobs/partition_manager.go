@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// PartitionManager keeps the monthly partitions of the tables partitioned
+// by migration 4: once a day it creates the partitions of the current and
+// next aheadMonths months and drops partitions whose whole month is older
+// than the table's retention. Tables that aren't partitioned are skipped,
+// as are partitions of tables without a retention.
+type PartitionManager struct {
+	repo        *repository.PartitionRepository
+	aheadMonths int
+	retention   map[string]time.Duration
+	locker      *leader.Locker
+}
+
+func NewPartitionManager(repo *repository.PartitionRepository, aheadMonths int, retention map[string]time.Duration, locker *leader.Locker) *PartitionManager {
+	return &PartitionManager{repo: repo, aheadMonths: aheadMonths, retention: retention, locker: locker}
+}
+
+// Run maintains partitions once immediately and then daily until ctx is
+// cancelled, on one replica at a time
+func (m *PartitionManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, m.locker, "partition-manager", m.maintain)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *PartitionManager) maintain(ctx context.Context) {
+	tables := make([]string, 0, len(repository.PartitionTables))
+	for table := range repository.PartitionTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		if err := m.maintainTable(ctx, table); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Partition maintenance of %s failed: %v", table, err)
+		}
+	}
+}
+
+func (m *PartitionManager) maintainTable(ctx context.Context, table string) error {
+	partitioned, err := m.repo.IsPartitioned(ctx, table)
+	if err != nil || !partitioned {
+		return err
+	}
+
+	partitions, err := m.repo.ListPartitions(ctx, table)
+	if err != nil {
+		return err
+	}
+	existing := make(map[time.Time]bool, len(partitions))
+	for _, p := range partitions {
+		existing[p.Month] = true
+	}
+
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= m.aheadMonths; i++ {
+		month := thisMonth.AddDate(0, i, 0)
+		if existing[month] {
+			continue
+		}
+		partition, err := m.repo.CreatePartition(ctx, table, month)
+		if err != nil {
+			return err
+		}
+		log.Printf("Created partition %s", partition.Name)
+	}
+
+	retention := m.retention[table]
+	if retention <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-retention)
+	for _, p := range partitions {
+		if p.Month.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+		if err := m.repo.DropPartition(ctx, table, p); err != nil {
+			return err
+		}
+		log.Printf("Dropped partition %s, older than %s", p.Name, cutoff.Format("2006-01-02"))
+	}
+	return nil
+}
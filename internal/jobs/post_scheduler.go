@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+)
+
+// scheduledPublishBatch is the number of posts listed at a time
+const scheduledPublishBatch = 100
+
+// PostScheduler publishes drafts once their published_at has passed.
+// Failures are announced as post.publish_failed notifications and retried
+// on the next run.
+type PostScheduler struct {
+	repo     *repository.ContentPostRepository
+	posts    *service.PostService
+	notifier *notify.Notifier
+	interval time.Duration
+	locker   *leader.Locker
+}
+
+func NewPostScheduler(repo *repository.ContentPostRepository, posts *service.PostService, notifier *notify.Notifier, interval time.Duration, locker *leader.Locker) *PostScheduler {
+	return &PostScheduler{repo: repo, posts: posts, notifier: notifier, interval: interval, locker: locker}
+}
+
+// Run publishes due posts once immediately and then on every interval
+// until ctx is cancelled, on one replica at a time
+func (s *PostScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, s.locker, "post-scheduler", s.publishDue)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PostScheduler) publishDue(ctx context.Context) {
+	published := 0
+	defer func() {
+		if published > 0 {
+			log.Printf("Published %d scheduled posts", published)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		posts, err := s.repo.ListScheduled(ctx, scheduledPublishBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] %v", err)
+			}
+			return
+		}
+
+		failed := 0
+		for _, post := range posts {
+			if ctx.Err() != nil {
+				return
+			}
+			ok, err := s.posts.PublishScheduled(ctx, post.ID)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.failed(ctx, post, err)
+				}
+				failed++
+				continue
+			}
+			if ok {
+				published++
+			}
+		}
+
+		// Failed posts stay due, so stop rather than list them again
+		if len(posts) < scheduledPublishBatch || failed > 0 {
+			return
+		}
+	}
+}
+
+func (s *PostScheduler) failed(ctx context.Context, post models.ScheduledPost, err error) {
+	log.Printf("[ERROR] Publishing scheduled post %s failed: %v", post.ID, err)
+	s.notifier.Notify(ctx, notify.Notification{
+		Event: notify.EventPostPublishFailed,
+		Title: "Scheduled post was not published",
+		Text:  err.Error(),
+		Fields: []notify.Field{
+			{Label: "Post", Value: post.Title},
+			{Label: "ID", Value: post.ID.String()},
+			{Label: "Scheduled for", Value: post.PublishedAt.UTC().Format(time.RFC3339)},
+		},
+	})
+}
@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/integrity"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// referenceCheckBatch is the number of IDs looked up per query
+const referenceCheckBatch = 5000
+
+// ReferenceChecker periodically looks for post metadata and content that
+// refer to deleted media, posts or tags, stores them for the dangling
+// references report and, with autoClean, removes them from metadata
+type ReferenceChecker struct {
+	repo      *repository.ReferenceRepository
+	autoClean bool
+	interval  time.Duration
+	locker    *leader.Locker
+}
+
+func NewReferenceChecker(repo *repository.ReferenceRepository, autoClean bool, interval time.Duration, locker *leader.Locker) *ReferenceChecker {
+	return &ReferenceChecker{repo: repo, autoClean: autoClean, interval: interval, locker: locker}
+}
+
+// Run scans once immediately and then on every interval until ctx is
+// cancelled, on one replica at a time
+func (c *ReferenceChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, c.locker, "reference-checker", c.scan)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// postRefs are the references found in one post
+type postRefs struct {
+	id       uuid.UUID
+	metadata []byte
+	refs     []integrity.Reference
+}
+
+func (c *ReferenceChecker) scan(ctx context.Context) {
+	start := time.Now()
+
+	var posts []postRefs
+	targets := make(map[string]map[uuid.UUID]bool)
+	scanned := 0
+	err := c.repo.EachPostReferences(ctx, func(p *models.PostReferences) error {
+		scanned++
+		refs, err := integrity.Extract(p.Metadata, p.Content)
+		if err != nil {
+			log.Printf("[WARN] Skipping references of post %s: %v", p.ID, err)
+			return nil
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+		posts = append(posts, postRefs{id: p.ID, metadata: p.Metadata, refs: refs})
+		for _, ref := range refs {
+			if targets[ref.Kind] == nil {
+				targets[ref.Kind] = make(map[uuid.UUID]bool)
+			}
+			targets[ref.Kind][ref.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Reference scan failed: %v", err)
+		}
+		return
+	}
+
+	missing, err := c.missing(ctx, targets)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Reference scan failed: %v", err)
+		}
+		return
+	}
+
+	var dangling []models.DanglingReference
+	cleaned := 0
+	for _, p := range posts {
+		first := len(dangling)
+		for _, ref := range p.refs {
+			if missing[ref.Target()] {
+				dangling = append(dangling, models.DanglingReference{
+					Post:         models.ReportPost{ID: p.id},
+					Source:       ref.Source,
+					Path:         ref.Path,
+					Kind:         ref.Kind,
+					ReferencedID: ref.ID,
+					DetectedAt:   start,
+				})
+			}
+		}
+		if !c.autoClean || len(dangling) == first {
+			continue
+		}
+		if c.clean(ctx, p, missing) {
+			for i := first; i < len(dangling); i++ {
+				if dangling[i].Source == integrity.SourceMetadata {
+					dangling[i].Cleaned = true
+					cleaned++
+				}
+			}
+		}
+	}
+
+	if err := c.repo.Replace(ctx, dangling); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to store reference scan: %v", err)
+		}
+		return
+	}
+	log.Printf("Reference scan found %d dangling references (%d cleaned) among %d posts in %s",
+		len(dangling), cleaned, scanned, time.Since(start).Round(time.Millisecond))
+}
+
+// missing looks up the referenced IDs of each kind and returns those that
+// match no record
+func (c *ReferenceChecker) missing(ctx context.Context, targets map[string]map[uuid.UUID]bool) (map[integrity.Target]bool, error) {
+	missing := make(map[integrity.Target]bool)
+	for kind, set := range targets {
+		ids := make([]uuid.UUID, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		for len(ids) > 0 {
+			n := min(len(ids), referenceCheckBatch)
+			found, err := c.repo.Missing(ctx, kind, ids[:n])
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range found {
+				missing[integrity.Target{Kind: kind, ID: id}] = true
+			}
+			ids = ids[n:]
+		}
+	}
+	return missing, nil
+}
+
+// clean removes the dangling references from a post's metadata and reports
+// whether the post was updated; posts edited since the scan read them are
+// left for the next scan
+func (c *ReferenceChecker) clean(ctx context.Context, p postRefs, missing map[integrity.Target]bool) bool {
+	cleaned, removed, err := integrity.Clean(p.metadata, missing)
+	if err != nil || removed == 0 {
+		return false
+	}
+	updated, err := c.repo.ReplaceMetadata(ctx, p.id, p.metadata, cleaned)
+	if err != nil {
+		log.Printf("[ERROR] Failed to clean references of post %s: %v", p.id, err)
+		return false
+	}
+	return updated
+}
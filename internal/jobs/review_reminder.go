@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+const (
+	// reviewReminderBatch is the number of posts reminded of at a time
+	reviewReminderBatch = 100
+	// reviewReminderTitles is the number of post titles listed in a
+	// reminder notification
+	reviewReminderTitles = 10
+)
+
+// ReviewReminder records review reminders for published posts that became
+// due for review and announces them as a content.review_due notification.
+// A post is reminded of once per review period: again only after it was
+// edited or acknowledged and became due once more.
+type ReviewReminder struct {
+	repo        *repository.ReviewRepository
+	notifier    *notify.Notifier
+	defaultDays int
+	interval    time.Duration
+	locker      *leader.Locker
+}
+
+func NewReviewReminder(repo *repository.ReviewRepository, notifier *notify.Notifier, defaultDays int, interval time.Duration, locker *leader.Locker) *ReviewReminder {
+	return &ReviewReminder{repo: repo, notifier: notifier, defaultDays: defaultDays, interval: interval, locker: locker}
+}
+
+// Run sends reminders once immediately and then on every interval until ctx
+// is cancelled, on one replica at a time
+func (r *ReviewReminder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, r.locker, "review-reminder", r.remind)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReviewReminder) remind(ctx context.Context) {
+	var titles []string
+	reminded := 0
+	for ctx.Err() == nil {
+		posts, err := r.repo.MarkReminders(ctx, r.defaultDays, reviewReminderBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[ERROR] %v", err)
+			}
+			break
+		}
+		reminded += len(posts)
+		for _, p := range posts {
+			if len(titles) < reviewReminderTitles {
+				titles = append(titles, "• "+p.Title)
+			}
+		}
+		if len(posts) < reviewReminderBatch {
+			break
+		}
+	}
+	if reminded == 0 {
+		return
+	}
+
+	if more := reminded - len(titles); more > 0 {
+		titles = append(titles, fmt.Sprintf("and %d more", more))
+	}
+	r.notifier.Notify(ctx, notify.Notification{
+		Event: notify.EventContentReviewDue,
+		Title: "Posts due for review",
+		Text:  strings.Join(titles, "\n"),
+		Fields: []notify.Field{
+			{Label: "Posts", Value: fmt.Sprint(reminded)},
+		},
+	})
+	log.Printf("Sent review reminders for %d stale posts", reminded)
+}
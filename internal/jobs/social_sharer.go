@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/social"
+)
+
+// socialShareBatch is the number of posts shared per run
+const socialShareBatch = 20
+
+// SocialSharer shares newly published posts to the social accounts
+// configured in settings. Posts are shared once their publication is delay
+// old, which gives editors time to opt a post out, and only while it is
+// younger than maxAge, so configuring a new account doesn't share the
+// archive.
+type SocialSharer struct {
+	shares    *repository.SocialShareRepository
+	posts     *repository.ContentPostRepository
+	publisher *social.Publisher
+	urls      *permalink.Builder
+	interval  time.Duration
+	delay     time.Duration
+	maxAge    time.Duration
+	locker    *leader.Locker
+}
+
+func NewSocialSharer(shares *repository.SocialShareRepository, posts *repository.ContentPostRepository, publisher *social.Publisher, urls *permalink.Builder, interval, delay, maxAge time.Duration, locker *leader.Locker) *SocialSharer {
+	return &SocialSharer{
+		shares:    shares,
+		posts:     posts,
+		publisher: publisher,
+		urls:      urls,
+		interval:  interval,
+		delay:     delay,
+		maxAge:    maxAge,
+		locker:    locker,
+	}
+}
+
+// Run shares due posts once immediately and then on every interval until
+// ctx is cancelled, on one replica at a time
+func (s *SocialSharer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, s.locker, "social-sharer", s.shareDue)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SocialSharer) shareDue(ctx context.Context) {
+	cfg, err := s.publisher.LoadConfig(ctx)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load social sharing settings: %v", err)
+		return
+	}
+	if len(cfg.Accounts) == 0 {
+		return
+	}
+
+	urls, err := s.urls.Load(ctx)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load site URL settings: %v", err)
+		return
+	}
+
+	now := time.Now()
+	ids, err := s.shares.ListDue(ctx, cfg.Names(), now.Add(-s.maxAge), now.Add(-s.delay), socialShareBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] %v", err)
+		}
+		return
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.sharePost(ctx, cfg, urls, id); err != nil && ctx.Err() == nil {
+			log.Printf("[ERROR] Social sharing of post %s: %v", id, err)
+		}
+	}
+}
+
+// sharePost shares a post to every account it hasn't been shared to yet
+func (s *SocialSharer) sharePost(ctx context.Context, cfg *social.Config, urls *permalink.Config, id uuid.UUID) error {
+	post, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	share := social.Compose(cfg.Message, post, urls.PostURL(post))
+	for _, account := range cfg.Accounts {
+		record := &models.SocialShare{
+			PostID:    &post.ID,
+			PostTitle: post.Title,
+			Account:   account.Name,
+			Driver:    account.Driver,
+			Message:   share.Message,
+			Link:      share.Link,
+		}
+		if share.ImageURL != "" {
+			record.ImageURL = &share.ImageURL
+		}
+		if err := s.shares.Claim(ctx, record); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				continue
+			}
+			return err
+		}
+
+		result, shareErr := s.publisher.Send(ctx, account, share)
+		var externalID, externalURL string
+		if shareErr == nil {
+			externalID, externalURL = result.ID, result.URL
+			log.Printf("Shared post %s to %s", post.ID, account.Name)
+		} else {
+			log.Printf("[ERROR] Sharing post %s to %s failed: %v", post.ID, account.Name, shareErr)
+		}
+		if err := s.shares.Finish(ctx, record.ID, externalID, externalURL, shareErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ViewRollupCompactor periodically prunes daily view rollups that fall
+// outside the retention window
+type ViewRollupCompactor struct {
+	repo      *repository.ContentPostRepository
+	retention time.Duration
+	interval  time.Duration
+	locker    *leader.Locker
+}
+
+func NewViewRollupCompactor(repo *repository.ContentPostRepository, retention, interval time.Duration, locker *leader.Locker) *ViewRollupCompactor {
+	return &ViewRollupCompactor{repo: repo, retention: retention, interval: interval, locker: locker}
+}
+
+// Run compacts once immediately and then on every interval until ctx is
+// cancelled, on one replica at a time
+func (c *ViewRollupCompactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		runLeased(ctx, c.locker, "view-rollup-compactor", c.compact)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ViewRollupCompactor) compact(ctx context.Context) {
+	cutoff := time.Now().Add(-c.retention)
+	deleted, err := c.repo.PruneViewRollups(ctx, cutoff)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] View rollup compaction failed: %v", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		log.Printf("View rollup compaction removed %d rows older than %s", deleted, cutoff.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,171 @@
+// Package leader coordinates background work across replicas with Postgres
+// session-level advisory locks: whichever process holds the lock for a name
+// is the leader for that name and does the work.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// releaseTimeout bounds unlocking, which also runs after the caller's
+// context has been cancelled
+const releaseTimeout = 5 * time.Second
+
+// Stats reports this process's leadership of one lock name
+type Stats struct {
+	Name           string     `json:"name"`
+	Leader         bool       `json:"leader"`
+	Acquired       int64      `json:"acquired"`
+	Contended      int64      `json:"contended"`
+	Errors         int64      `json:"errors"`
+	Changes        int64      `json:"changes"`
+	LastChangeAt   *time.Time `json:"last_change_at,omitempty"`
+	LastAcquiredAt *time.Time `json:"last_acquired_at,omitempty"`
+}
+
+// Locker hands out advisory-lock leases. A nil Locker is valid and grants
+// every lease, for single-instance setups.
+type Locker struct {
+	db *pgxpool.Pool
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+func New(db *pgxpool.Pool) *Locker {
+	return &Locker{db: db, stats: make(map[string]*Stats)}
+}
+
+// Lease is a held advisory lock. It pins a pool connection until Release.
+type Lease struct {
+	conn *pgxpool.Conn
+	key  int64
+	name string
+}
+
+// TryAcquire takes the lock for name without waiting. It returns a nil
+// lease when another process holds it.
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lease, error) {
+	if l == nil {
+		return &Lease{name: name}, nil
+	}
+
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		l.record(name, false, err)
+		return nil, fmt.Errorf("failed to acquire connection for lock %s: %w", name, err)
+	}
+
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Release()
+		l.record(name, false, err)
+		return nil, fmt.Errorf("failed to take lock %s: %w", name, err)
+	}
+
+	l.record(name, acquired, nil)
+	if !acquired {
+		conn.Release()
+		return nil, nil
+	}
+	return &Lease{conn: conn, key: key, name: name}, nil
+}
+
+// Release unlocks and returns the connection to the pool. If unlocking
+// fails the connection is closed, which drops the lock with the session.
+func (lease *Lease) Release() {
+	if lease == nil || lease.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	if _, err := lease.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, lease.key); err != nil {
+		log.Printf("[ERROR] Failed to release lock %s, closing its connection: %v", lease.name, err)
+		lease.conn.Conn().Close(ctx)
+	}
+	lease.conn.Release()
+	lease.conn = nil
+}
+
+// WithLease runs fn while holding the lock for name. It reports false
+// without running fn when another process holds the lock.
+func (l *Locker) WithLease(ctx context.Context, name string, fn func(context.Context) error) (bool, error) {
+	lease, err := l.TryAcquire(ctx, name)
+	if err != nil || lease == nil {
+		return false, err
+	}
+	defer lease.Release()
+
+	return true, fn(ctx)
+}
+
+// Stats returns this process's leadership counters per lock name, sorted by name
+func (l *Locker) Stats() []Stats {
+	if l == nil {
+		return []Stats{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]Stats, 0, len(l.stats))
+	for _, s := range l.stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// record updates the counters for an acquisition attempt and logs when this
+// process gains or loses leadership. Errors count as not being leader.
+func (l *Locker) record(name string, acquired bool, err error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.stats[name]
+	if !ok {
+		s = &Stats{Name: name}
+		l.stats[name] = s
+	}
+
+	switch {
+	case err != nil:
+		s.Errors++
+	case acquired:
+		s.Acquired++
+		s.LastAcquiredAt = &now
+	default:
+		s.Contended++
+	}
+
+	if acquired != s.Leader {
+		s.Leader = acquired
+		s.Changes++
+		s.LastChangeAt = &now
+		if acquired {
+			log.Printf("Became leader for %s", name)
+		} else {
+			log.Printf("No longer leader for %s", name)
+		}
+	}
+}
+
+// lockKey maps a lock name to the bigint advisory lock key
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("go-cms-template:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,170 @@
+// Package locale negotiates the locale of a request from its
+// Accept-Language header and resolves locale fallback chains such as
+// fr-ca → fr → en.
+package locale
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Normalize lowercases a language tag and uses hyphens as separators, so
+// "pt_BR" and "pt-br" compare equal
+func Normalize(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
+
+// base returns the language of a tag ("pt" for "pt-br")
+func base(tag string) string {
+	language, _, _ := strings.Cut(tag, "-")
+	return language
+}
+
+// Fallbacks are the configured fallback chains of locales. A locale without
+// a chain falls back to its base language.
+type Fallbacks struct {
+	chains map[string][]string
+}
+
+// NewFallbacks parses chains written as locales separated by ">", e.g.
+// "fr-ca>fr>en". Specs naming fewer than two locales are ignored.
+func NewFallbacks(specs []string) *Fallbacks {
+	f := &Fallbacks{chains: make(map[string][]string)}
+	for _, spec := range specs {
+		var chain []string
+		for _, tag := range strings.Split(spec, ">") {
+			if tag = Normalize(tag); tag != "" {
+				chain = append(chain, tag)
+			}
+		}
+		if len(chain) >= 2 {
+			f.chains[chain[0]] = chain[1:]
+		}
+	}
+	return f
+}
+
+// Chain returns locale followed by the locales to try in its place, in
+// order: its configured chain, else its base language, each followed by
+// their own fallbacks. A nil Fallbacks only falls back to base languages.
+func (f *Fallbacks) Chain(locale string) []string {
+	var chain []string
+	var add func(tag string)
+	add = func(tag string) {
+		if tag == "" || slices.Contains(chain, tag) {
+			return
+		}
+		chain = append(chain, tag)
+		if next, ok := f.next(tag); ok {
+			for _, n := range next {
+				add(n)
+			}
+		} else {
+			add(base(tag))
+		}
+	}
+	add(Normalize(locale))
+	return chain
+}
+
+func (f *Fallbacks) next(tag string) ([]string, bool) {
+	if f == nil {
+		return nil, false
+	}
+	next, ok := f.chains[tag]
+	return next, ok
+}
+
+// Negotiator picks the locale a request is served in among the supported
+// locales, the first of which is the default
+type Negotiator struct {
+	supported []string
+	fallbacks *Fallbacks
+}
+
+func NewNegotiator(supported []string, fallbacks *Fallbacks) *Negotiator {
+	n := &Negotiator{fallbacks: fallbacks}
+	for _, tag := range supported {
+		if tag = Normalize(tag); tag != "" && !slices.Contains(n.supported, tag) {
+			n.supported = append(n.supported, tag)
+		}
+	}
+	return n
+}
+
+// Negotiate returns the best of supported for an Accept-Language header:
+// the client's languages are tried by descending quality, each with its
+// fallback chain, and the first of supported is the default. With no
+// supported locales the client's preferred language is returned as is, or
+// "" when it states none.
+func (n *Negotiator) Negotiate(acceptLanguage string, supported []string) string {
+	preferred := parseAcceptLanguage(acceptLanguage)
+	if len(supported) == 0 {
+		if len(preferred) == 0 {
+			return ""
+		}
+		return preferred[0]
+	}
+
+	for _, tag := range preferred {
+		for _, candidate := range n.fallbacks.Chain(tag) {
+			for _, s := range supported {
+				if Normalize(s) == candidate {
+					return s
+				}
+			}
+		}
+	}
+	return supported[0]
+}
+
+// Middleware stores the negotiated locale in the request context (see
+// reqctx.Info.Locale). It must run after middleware.RequestID.
+func (n *Negotiator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := *reqctx.From(r.Context())
+		info.Locale = n.Negotiate(r.Header.Get("Accept-Language"), n.supported)
+		next.ServeHTTP(w, r.WithContext(reqctx.With(r.Context(), &info)))
+	})
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language
+// header by descending quality, normalized, without "*" and tags of
+// quality 0
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = Normalize(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.tag
+	}
+	return out
+}
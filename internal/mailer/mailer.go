@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Mailer renders templates and places the result on the outbound queue.
+// Delivery happens asynchronously in jobs.EmailDispatcher.
+type Mailer struct {
+	renderer *Renderer
+	queue    *repository.EmailRepository
+}
+
+func New(renderer *Renderer, queue *repository.EmailRepository) *Mailer {
+	return &Mailer{renderer: renderer, queue: queue}
+}
+
+// Enqueue renders the named template for locale and queues it for to
+func (m *Mailer) Enqueue(ctx context.Context, to, name, locale string, data interface{}) (*models.Email, error) {
+	return m.enqueue(ctx, nil, to, name, locale, data)
+}
+
+// EnqueueForCampaign is Enqueue for a message that belongs to a campaign,
+// so its delivery and opens count towards the campaign's statistics
+func (m *Mailer) EnqueueForCampaign(ctx context.Context, campaignID uuid.UUID, to, name, locale string, data interface{}) (*models.Email, error) {
+	return m.enqueue(ctx, &campaignID, to, name, locale, data)
+}
+
+func (m *Mailer) enqueue(ctx context.Context, campaignID *uuid.UUID, to, name, locale string, data interface{}) (*models.Email, error) {
+	rendered, err := m.renderer.Render(ctx, name, locale, data)
+	if err != nil {
+		return nil, err
+	}
+
+	email := &models.Email{
+		ToAddress:    strings.TrimSpace(to),
+		TemplateName: &name,
+		Locale:       &rendered.Locale,
+		Subject:      rendered.Subject,
+		CampaignID:   campaignID,
+	}
+	if rendered.HTML != "" {
+		email.BodyHTML = &rendered.HTML
+	}
+	if rendered.Text != "" {
+		email.BodyText = &rendered.Text
+	}
+	if requestID := reqctx.From(ctx).RequestID; requestID != "" {
+		email.RequestID = &requestID
+	}
+
+	return m.queue.Enqueue(ctx, email)
+}
@@ -0,0 +1,186 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ErrTemplateNotFound is returned when no variant of a template exists for
+// the requested locale or any of its fallbacks
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Built-in templates live in templates/<name>.<locale>.tmpl and define the
+// "subject", "text" and (optionally) "html" blocks. Templates stored in the
+// database take precedence over these.
+//
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// Renderer resolves a named template for a locale and executes it
+type Renderer struct {
+	repo          *repository.EmailTemplateRepository
+	files         fs.FS
+	fallbacks     *locale.Fallbacks
+	defaultLocale string
+}
+
+func NewRenderer(repo *repository.EmailTemplateRepository, fallbacks *locale.Fallbacks, defaultLocale string) *Renderer {
+	return &Renderer{repo: repo, files: embeddedTemplates, fallbacks: fallbacks, defaultLocale: defaultLocale}
+}
+
+// Rendered holds the output of a template for a single recipient
+type Rendered struct {
+	Locale  string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Render looks up name for locale, falling back along its fallback chain
+// (by default its base language, "pt" for "pt-BR") and then to the default
+// locale. Rendered.Locale is the locale of the template used.
+func (r *Renderer) Render(ctx context.Context, name, locale string, data interface{}) (*Rendered, error) {
+	for _, candidate := range r.localeChain(locale) {
+		t, err := r.repo.GetByNameAndLocale(ctx, name, candidate)
+		if err == nil {
+			return renderStored(t, data)
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+
+		out, err := r.renderEmbedded(name, candidate, data)
+		if err == nil {
+			return out, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, ErrTemplateNotFound
+}
+
+func (r *Renderer) localeChain(tag string) []string {
+	chain := r.fallbacks.Chain(tag)
+	if def := locale.Normalize(r.defaultLocale); def != "" && !slices.Contains(chain, def) {
+		chain = append(chain, def)
+	}
+	return chain
+}
+
+// Validate checks that the given template sources parse; nil parts are skipped
+func Validate(subject, bodyHTML, bodyText *string) error {
+	if subject != nil {
+		if _, err := template.New("subject").Parse(*subject); err != nil {
+			return fmt.Errorf("subject: %w", err)
+		}
+	}
+	if bodyHTML != nil {
+		if _, err := htmltemplate.New("html").Parse(*bodyHTML); err != nil {
+			return fmt.Errorf("body_html: %w", err)
+		}
+	}
+	if bodyText != nil {
+		if _, err := template.New("text").Parse(*bodyText); err != nil {
+			return fmt.Errorf("body_text: %w", err)
+		}
+	}
+	return nil
+}
+
+func renderStored(t *models.EmailTemplate, data interface{}) (*Rendered, error) {
+	out := &Rendered{Locale: t.Locale}
+
+	subject, err := executeText(t.Subject, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", t.Name, err)
+	}
+	out.Subject = strings.TrimSpace(subject)
+
+	if t.BodyText != nil {
+		if out.Text, err = executeText(*t.BodyText, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s text body: %w", t.Name, err)
+		}
+	}
+	if t.BodyHTML != nil {
+		tmpl, err := htmltemplate.New("html").Parse(*t.BodyHTML)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html body: %w", t.Name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s html body: %w", t.Name, err)
+		}
+		out.HTML = buf.String()
+	}
+
+	return out, nil
+}
+
+func (r *Renderer) renderEmbedded(name, locale string, data interface{}) (*Rendered, error) {
+	src, err := fs.ReadFile(r.files, fmt.Sprintf("templates/%s.%s.tmpl", name, locale))
+	if err != nil {
+		return nil, err
+	}
+
+	// Subject and text are parsed as plain text templates; the html block is
+	// parsed separately so it gets contextual escaping
+	textTmpl, err := template.New(name).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in template %s: %w", name, err)
+	}
+
+	out := &Rendered{Locale: locale}
+	var buf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	out.Subject = strings.TrimSpace(buf.String())
+
+	if textTmpl.Lookup("text") != nil {
+		buf.Reset()
+		if err := textTmpl.ExecuteTemplate(&buf, "text", data); err != nil {
+			return nil, fmt.Errorf("failed to render %s text body: %w", name, err)
+		}
+		out.Text = strings.TrimSpace(buf.String())
+	}
+
+	if textTmpl.Lookup("html") != nil {
+		htmlTmpl, err := htmltemplate.New(name).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse built-in template %s: %w", name, err)
+		}
+		buf.Reset()
+		if err := htmlTmpl.ExecuteTemplate(&buf, "html", data); err != nil {
+			return nil, fmt.Errorf("failed to render %s html body: %w", name, err)
+		}
+		out.HTML = strings.TrimSpace(buf.String())
+	}
+
+	return out, nil
+}
+
+func executeText(src string, data interface{}) (string, error) {
+	tmpl, err := template.New("").Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
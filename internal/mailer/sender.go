@@ -0,0 +1,172 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Message is a fully rendered email ready for delivery
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	// RequestID, when set, is sent as the X-Request-ID header
+	RequestID string
+}
+
+// Sender delivers a single message
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewSender returns an SMTP sender when a host is configured, otherwise a
+// sender that only logs messages (useful in development)
+func NewSender(cfg config.MailConfig) Sender {
+	if cfg.SMTPHost == "" {
+		return LogSender{}
+	}
+	return &SMTPSender{cfg: cfg}
+}
+
+// SMTPSender delivers messages through an SMTP relay
+type SMTPSender struct {
+	cfg config.MailConfig
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	from := mail.Address{Name: s.cfg.FromName, Address: s.cfg.FromAddress}
+	body, err := buildMIME(from, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	var auth smtp.Auth
+	if s.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, from.Address, []string{msg.To}, body)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LogSender writes messages to the log instead of delivering them
+type LogSender struct{}
+
+func (LogSender) Send(_ context.Context, msg *Message) error {
+	log.Printf("[MAIL] To: %s Subject: %q (SMTP_HOST not set, not delivered)", msg.To, msg.Subject)
+	return nil
+}
+
+// buildMIME encodes msg as a multipart/alternative message when both bodies
+// are present, or as a single part otherwise
+func buildMIME(from mail.Address, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := textproto.MIMEHeader{}
+	headers.Set("From", from.String())
+	headers.Set("To", msg.To)
+	headers.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	headers.Set("Date", time.Now().Format(time.RFC1123Z))
+	headers.Set("Message-ID", fmt.Sprintf("<%s@%s>", uuid.New(), domainOf(from.Address)))
+	headers.Set("MIME-Version", "1.0")
+	if msg.RequestID != "" {
+		headers.Set(reqctx.HeaderRequestID, msg.RequestID)
+	}
+
+	writeHeaders := func() {
+		for k, v := range headers {
+			fmt.Fprintf(&buf, "%s: %s\r\n", k, v[0])
+		}
+		buf.WriteString("\r\n")
+	}
+
+	if msg.HTML == "" || msg.Text == "" {
+		contentType, body := "text/plain", msg.Text
+		if msg.HTML != "" {
+			contentType, body = "text/html", msg.HTML
+		}
+		headers.Set("Content-Type", contentType+"; charset=utf-8")
+		headers.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeaders()
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := writeQuotedPrintable(pw, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	headers.Set("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	writeHeaders()
+	buf.Write(parts.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return nil
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
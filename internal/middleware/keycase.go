@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// KeyCaseParam lets a request choose the casing of response keys
+const KeyCaseParam = "case"
+
+// KeyCase sets the casing of JSON response keys: ?case=camel or ?case=snake
+// when given, defaultCase otherwise
+func KeyCase(defaultCase response.KeyCase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyCase := defaultCase
+			if param := r.URL.Query().Get(KeyCaseParam); param != "" {
+				c, ok := response.ParseKeyCase(param)
+				if !ok {
+					response.BadRequest(w, "Invalid case (must be snake or camel)")
+					return
+				}
+				keyCase = c
+			}
+			if keyCase == response.SnakeCase {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(response.WithKeyCase(w, keyCase), r)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Priority ranks requests for load shedding; lower priorities are shed first
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// Load shedding tuning
+const (
+	// Only latencies observed in the last latencyWindow count towards p99,
+	// so the estimate recovers once slow requests stop
+	latencyWindow     = 10 * time.Second
+	latencySamples    = 2048
+	minLatencySamples = 20
+	p99RefreshEvery   = time.Second
+
+	// Above severeOverload times a threshold normal-priority requests are
+	// shed as well as low-priority ones
+	severeOverload = 1.5
+)
+
+type latencySample struct {
+	at       time.Time
+	duration time.Duration
+}
+
+// LoadShedder rejects low-priority requests with 503 and Retry-After while
+// the server is overloaded: when the number of requests in flight exceeds
+// maxInFlight or the recent p99 latency exceeds latencyTarget. High-priority
+// requests are never shed.
+type LoadShedder struct {
+	maxInFlight   int64
+	latencyTarget time.Duration
+	retryAfter    string
+	classify      func(*http.Request) Priority
+
+	inFlight atomic.Int64
+
+	mu      sync.Mutex
+	samples [latencySamples]latencySample
+	next    int
+	p99     time.Duration
+	p99At   time.Time
+	level   int
+}
+
+// NewLoadShedder creates a load shedder. A zero maxInFlight or
+// latencyTarget disables that signal; classify assigns request priorities.
+func NewLoadShedder(maxInFlight int, latencyTarget, retryAfter time.Duration, classify func(*http.Request) Priority) *LoadShedder {
+	return &LoadShedder{
+		maxInFlight:   int64(maxInFlight),
+		latencyTarget: latencyTarget,
+		retryAfter:    strconv.Itoa(int(retryAfter.Round(time.Second) / time.Second)),
+		classify:      classify,
+	}
+}
+
+// Middleware sheds requests whose priority is below the current overload
+// level and records the latency of the rest
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
+		if int(s.classify(r)) < s.overloadLevel(inFlight) {
+			w.Header().Set("Retry-After", s.retryAfter)
+			response.Error(w, http.StatusServiceUnavailable, "OVERLOADED", "Server is overloaded, please retry later")
+			return
+		}
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		s.observe(start, time.Since(start))
+	})
+}
+
+// overloadLevel returns how many priorities are currently shed: 0 (none),
+// 1 (low) or 2 (low and normal)
+func (s *LoadShedder) overloadLevel(inFlight int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pressure float64
+	if s.maxInFlight > 0 {
+		pressure = float64(inFlight) / float64(s.maxInFlight)
+	}
+	if s.latencyTarget > 0 {
+		pressure = max(pressure, float64(s.currentP99())/float64(s.latencyTarget))
+	}
+
+	level := 0
+	switch {
+	case pressure >= severeOverload:
+		level = 2
+	case pressure > 1:
+		level = 1
+	}
+
+	if level != s.level {
+		if level > s.level {
+			log.Printf("Load shedding raised to level %d (in flight %d, p99 %s)", level, inFlight, s.p99)
+		} else {
+			log.Printf("Load shedding lowered to level %d (in flight %d, p99 %s)", level, inFlight, s.p99)
+		}
+		s.level = level
+	}
+	return level
+}
+
+// currentP99 returns the p99 latency of the recent window, recomputed at
+// most once per p99RefreshEvery. The caller must hold s.mu.
+func (s *LoadShedder) currentP99() time.Duration {
+	now := time.Now()
+	if now.Sub(s.p99At) < p99RefreshEvery {
+		return s.p99
+	}
+	s.p99At = now
+
+	recent := make([]time.Duration, 0, latencySamples)
+	for _, sample := range s.samples {
+		if !sample.at.IsZero() && now.Sub(sample.at) <= latencyWindow {
+			recent = append(recent, sample.duration)
+		}
+	}
+	if len(recent) < minLatencySamples {
+		s.p99 = 0
+		return 0
+	}
+
+	sort.Slice(recent, func(i, j int) bool { return recent[i] < recent[j] })
+	s.p99 = recent[(len(recent)*99+99)/100-1]
+	return s.p99
+}
+
+func (s *LoadShedder) observe(at time.Time, d time.Duration) {
+	s.mu.Lock()
+	s.samples[s.next] = latencySample{at: at, duration: d}
+	s.next = (s.next + 1) % latencySamples
+	s.mu.Unlock()
+}
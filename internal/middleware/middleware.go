@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
 	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
 )
 
 // Logger logs HTTP requests
@@ -12,7 +14,7 @@ func Logger(next http.Handler) http.Handler {
 		start := time.Now()
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 		next.ServeHTTP(wrapped, r)
-		log.Printf("%s %s %d %s", r.Method, r.URL.Path, wrapped.statusCode, time.Since(start))
+		reqctx.Logf(r.Context(), "%s %s %d %s", r.Method, r.URL.Path, wrapped.statusCode, time.Since(start))
 	})
 }
 
@@ -26,29 +28,44 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Recoverer recovers from panics and returns a 500 error
+// Flush lets streaming handlers flush through the logger
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Recoverer recovers from panics and returns a JSON 500 error
 func Recoverer(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("panic: %v", err)
-				w.Header().Set("Content-Type", "application/json")
-				http.Error(w, `{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Internal server error"}}`, http.StatusInternalServerError)
-			}
-		}()
-		next.ServeHTTP(w, r)
-	})
+	return RecovererWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+	})(next)
+}
+
+// RecovererWithHandler recovers from panics and delegates the 500 response to
+// errorHandler. Use NewRecovery to also alert on panics and count them.
+func RecovererWithHandler(errorHandler http.HandlerFunc) func(http.Handler) http.Handler {
+	return NewRecovery(errorHandler, nil).Middleware
 }
 
-// RequestID adds a unique request ID to each request
+// RequestID adds a unique request ID to each request and stores it in the
+// request context (see reqctx).
+// A valid incoming X-Request-ID is kept so IDs carry across services.
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := r.Header.Get("X-Request-ID")
-		if requestID == "" {
+		requestID := r.Header.Get(reqctx.HeaderRequestID)
+		if !reqctx.ValidRequestID(requestID) {
 			requestID = generateRequestID()
 		}
-		w.Header().Set("X-Request-ID", requestID)
-		next.ServeHTTP(w, r)
+		w.Header().Set(reqctx.HeaderRequestID, requestID)
+
+		ctx := reqctx.With(r.Context(), &reqctx.Info{RequestID: requestID})
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/database"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Headers reporting the database work behind a response
+const (
+	HeaderDBQueries = "X-DB-Queries"
+	HeaderDBTime    = "X-DB-Time"
+)
+
+// QueryBudget counts the database queries of each request and the time
+// spent in them, reports both in X-DB-Queries and X-DB-Time, and logs a
+// warning when a request runs more than maxQueries queries or spends more
+// than maxTime in them (zero disables either), so N+1 regressions show up
+// during development. The headers go out with the status line and miss
+// queries a streaming handler runs afterwards; the warning covers the
+// whole request.
+func QueryBudget(maxQueries int, maxTime time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, stats := database.WithQueryStats(r.Context())
+			next.ServeHTTP(&queryStatsWriter{ResponseWriter: w, stats: stats}, r.WithContext(ctx))
+
+			queries, spent := stats.Queries(), stats.Time()
+			if (maxQueries > 0 && queries > int64(maxQueries)) || (maxTime > 0 && spent > maxTime) {
+				reqctx.Logf(r.Context(), "[WARN] Query budget exceeded: %s %s ran %d queries in %s (budget %d queries, %s)",
+					r.Method, r.URL.Path, queries, spent.Round(time.Microsecond), maxQueries, maxTime)
+			}
+		})
+	}
+}
+
+// queryStatsWriter adds the query headers when the response starts
+type queryStatsWriter struct {
+	http.ResponseWriter
+	stats       *database.QueryStats
+	wroteHeader bool
+}
+
+func (qw *queryStatsWriter) WriteHeader(code int) {
+	if !qw.wroteHeader {
+		qw.wroteHeader = true
+		qw.Header().Set(HeaderDBQueries, strconv.FormatInt(qw.stats.Queries(), 10))
+		qw.Header().Set(HeaderDBTime, qw.stats.Time().Round(time.Microsecond).String())
+	}
+	qw.ResponseWriter.WriteHeader(code)
+}
+
+func (qw *queryStatsWriter) Write(b []byte) (int, error) {
+	if !qw.wroteHeader {
+		qw.WriteHeader(http.StatusOK)
+	}
+	return qw.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers flush through the query counter
+func (qw *queryStatsWriter) Flush() {
+	if !qw.wroteHeader {
+		qw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := qw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (qw *queryStatsWriter) Unwrap() http.ResponseWriter {
+	return qw.ResponseWriter
+}
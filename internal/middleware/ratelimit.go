@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// RateLimiter allows each client a fixed number of requests per window and
+// rejects the rest with 429 and Retry-After. Clients are told apart by
+// their verified credentials (see reqctx.Info.Client), and anonymous
+// callers by IP address.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+	byIP   bool
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+// NewRateLimiter creates a limiter allowing limit requests per window; a
+// limit of zero or less allows everything
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, counts: make(map[string]int)}
+}
+
+// NewIPRateLimiter creates a limiter that tells clients apart by IP address
+// only, for endpoints taking credentials, where callers could otherwise get
+// a fresh count with every made-up bearer token
+func NewIPRateLimiter(limit int, window time.Duration) *RateLimiter {
+	l := NewRateLimiter(limit, window)
+	l.byIP = true
+	return l
+}
+
+// Middleware rejects requests over the limit
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.limit > 0 {
+			if retryAfter, ok := l.allow(l.key(r), time.Now()); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+				response.Error(w, http.StatusTooManyRequests, "RATE_LIMITED", "Too many requests, retry later")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow counts a request from key in the current window, starting a new
+// window (and forgetting every count) once it has passed
+func (l *RateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+	if l.counts[key] >= l.limit {
+		return l.start.Add(l.window).Sub(now), false
+	}
+	l.counts[key]++
+	return 0, true
+}
+
+// key is the caller's user or API key (see reqctx.Info.Client) once the
+// auth middleware has verified it, and the client IP otherwise, so headers
+// that fail or skip verification never open a new count
+func (l *RateLimiter) key(r *http.Request) string {
+	info := reqctx.From(r.Context())
+	if client := info.Client(); !l.byIP && client != "anonymous" {
+		return client
+	}
+	return "ip:" + info.IP
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+func TestRateLimiterKeysOnVerifiedCaller(t *testing.T) {
+	limiter := NewRateLimiter(1, time.Minute)
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// serve sends a request from ip with made-up credentials, as caller
+	serve := func(ip string, caller reqctx.Caller) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/summarize", nil)
+		req.Header.Set("Authorization", "Bearer "+uuid.NewString())
+		req.Header.Set("X-API-Key", uuid.NewString())
+		ctx := reqctx.With(req.Context(), &reqctx.Info{IP: ip})
+		reqctx.SetCaller(ctx, caller)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req.WithContext(ctx))
+		return rec.Code
+	}
+
+	if code := serve("192.0.2.1", reqctx.Caller{}); code != http.StatusOK {
+		t.Fatalf("first anonymous request = %d, want 200", code)
+	}
+	if code := serve("192.0.2.1", reqctx.Caller{}); code != http.StatusTooManyRequests {
+		t.Errorf("anonymous request with fresh headers = %d, want 429", code)
+	}
+
+	user := reqctx.Caller{UserID: uuid.New()}
+	if code := serve("192.0.2.1", user); code != http.StatusOK {
+		t.Errorf("first request of a signed-in user = %d, want 200", code)
+	}
+	if code := serve("198.51.100.7", user); code != http.StatusTooManyRequests {
+		t.Errorf("same user from another IP = %d, want 429", code)
+	}
+	if code := serve("192.0.2.1", reqctx.Caller{UserID: user.UserID, APIKeyID: uuid.New()}); code != http.StatusOK {
+		t.Errorf("first request with one of the user's API keys = %d, want 200", code)
+	}
+}
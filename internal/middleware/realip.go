@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// ParseTrustedProxies parses proxy addresses given as CIDRs ("10.0.0.0/8") or
+// single IPs ("203.0.113.7")
+func ParseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// RealIP resolves the client address of each request and stores it in the
+// request context (reqctx.Info.IP). Forwarding headers are only honoured
+// when the request comes from a trusted proxy. X-Forwarded-For is then read
+// right to left, skipping trusted hops, so a client can't pick its own
+// address by sending the header itself.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	isTrusted := func(ip net.IP) bool {
+		for _, n := range trusted {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := *reqctx.From(r.Context())
+			info.IP = resolveClientIP(r, isTrusted)
+			next.ServeHTTP(w, r.WithContext(reqctx.With(r.Context(), &info)))
+		})
+	}
+}
+
+func resolveClientIP(r *http.Request, isTrusted func(net.IP) bool) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	ip := net.ParseIP(remote)
+	if ip == nil || !isTrusted(ip) {
+		return remote
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				// Garbage in the chain: stop at the last address we trust
+				return ip.String()
+			}
+			ip = hop
+			if !isTrusted(hop) {
+				return hop.String()
+			}
+		}
+		// Every hop is a trusted proxy; the left-most is the origin
+		return ip.String()
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+	return ip.String()
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// panicAlertInterval limits alerts to one per interval so a panicking hot
+// path doesn't flood the alert channels; every panic is still logged
+const panicAlertInterval = time.Minute
+
+// PanicReport describes a recovered panic
+type PanicReport struct {
+	Value     string
+	Stack     string
+	Method    string
+	Path      string
+	RequestID string
+	At        time.Time
+}
+
+// PanicStats counts the panics recovered by this process
+type PanicStats struct {
+	Total         int64      `json:"total"`
+	LastAt        *time.Time `json:"last_at,omitempty"`
+	LastValue     string     `json:"last_value,omitempty"`
+	LastPath      string     `json:"last_path,omitempty"`
+	LastRequestID string     `json:"last_request_id,omitempty"`
+}
+
+// Recovery recovers from panics in handlers: it logs the panic with its
+// stack, counts it, alerts (throttled) and delegates the 500 response to
+// errorHandler
+type Recovery struct {
+	errorHandler http.HandlerFunc
+	alert        func(*http.Request, *PanicReport)
+
+	mu          sync.Mutex
+	stats       PanicStats
+	lastAlertAt time.Time
+}
+
+// NewRecovery creates a panic recoverer. alert may be nil; it runs on the
+// panicking request's goroutine and must not block.
+func NewRecovery(errorHandler http.HandlerFunc, alert func(*http.Request, *PanicReport)) *Recovery {
+	return &Recovery{errorHandler: errorHandler, alert: alert}
+}
+
+// Middleware recovers panics from next
+func (rc *Recovery) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			// Panics from a Timeout handler goroutine carry their own stack
+			value, stack := err, debug.Stack()
+			if hp, ok := err.(*handlerPanic); ok {
+				value, stack = hp.value, hp.stack
+			}
+			// The server uses ErrAbortHandler to abort a response on purpose
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			report := &PanicReport{
+				Value:     fmt.Sprint(value),
+				Stack:     string(stack),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				RequestID: reqctx.From(r.Context()).RequestID,
+				At:        time.Now(),
+			}
+			reqctx.Logf(r.Context(), "panic: %s %s: %s\n%s", report.Method, report.Path, report.Value, report.Stack)
+
+			if rc.record(report) && rc.alert != nil {
+				rc.alert(r, report)
+			}
+			rc.errorHandler(w, r)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// record counts the panic and reports whether an alert is due
+func (rc *Recovery) record(report *PanicReport) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.stats.Total++
+	rc.stats.LastAt = &report.At
+	rc.stats.LastValue = report.Value
+	rc.stats.LastPath = report.Path
+	rc.stats.LastRequestID = report.RequestID
+
+	if report.At.Sub(rc.lastAlertAt) < panicAlertInterval {
+		return false
+	}
+	rc.lastAlertAt = report.At
+	return true
+}
+
+// Stats returns the panic counters
+func (rc *Recovery) Stats() PanicStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.stats
+}
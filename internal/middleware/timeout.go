@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// writeDeadlineGrace is added to a request's deadline for the connection's
+// write deadline, leaving time to send the 504 after the deadline passes
+const writeDeadlineGrace = 5 * time.Second
+
+// Timeout gives each request the deadline chosen by timeoutFor, replacing
+// the server's global write timeout for that request. When the deadline
+// passes before the handler has started responding, or the handler fails
+// with a 5xx after it, the client gets a 504 TIMEOUT JSON error instead.
+// A zero timeout means no deadline, for long streaming responses.
+func Timeout(timeoutFor func(*http.Request) time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := timeoutFor(r)
+			rc := http.NewResponseController(w)
+			if d <= 0 {
+				rc.SetWriteDeadline(time.Time{})
+				next.ServeHTTP(w, r)
+				return
+			}
+			rc.SetWriteDeadline(time.Now().Add(d + writeDeadlineGrace))
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, ctx: ctx, header: make(http.Header)}
+			done := make(chan struct{})
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						tw.recordPanic(r, &handlerPanic{value: p, stack: debug.Stack()})
+					}
+					close(done)
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+			}()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				if tw.timeout() {
+					reqctx.Logf(r.Context(), "[WARN] %s %s timed out after %s", r.Method, r.URL.Path, d)
+					return
+				}
+				// The response was already under way; let the handler finish it
+				<-done
+			}
+
+			if p := tw.panicked(); p != nil {
+				// Re-panic on the serving goroutine so Recovery handles it
+				panic(p)
+			}
+		})
+	}
+}
+
+// handlerPanic carries a panic, with the stack of the goroutine it happened
+// on, from a handler goroutine to the serving goroutine
+type handlerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+// timeoutWriter guards the response between the handler goroutine and the
+// deadline. The handler gets its own header map so a late handler can't
+// race with the 504.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	ctx    context.Context
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+	panic       *handlerPanic
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(code)
+}
+
+// writeHeader sends the handler's status, or the 504 when a 5xx comes after
+// the deadline (the handler's query was most likely cancelled by it). The
+// caller must hold tw.mu.
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	if code >= http.StatusInternalServerError && tw.ctx.Err() == context.DeadlineExceeded {
+		tw.writeTimeout()
+		return
+	}
+	for k, v := range tw.header {
+		tw.w.Header()[k] = v
+	}
+	tw.wroteHeader = true
+	tw.w.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return tw.w.Write(b)
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	if f, ok := tw.w.(http.Flusher); ok && !tw.timedOut {
+		f.Flush()
+	}
+}
+
+// timeout sends the 504 unless the handler has started responding or
+// panicked, and reports whether the request ended with it
+func (tw *timeoutWriter) timeout() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader && tw.panic == nil {
+		tw.writeTimeout()
+	}
+	return tw.timedOut
+}
+
+// recordPanic keeps a handler panic for the serving goroutine, or logs it
+// when the request has already ended with a 504
+func (tw *timeoutWriter) recordPanic(r *http.Request, p *handlerPanic) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		reqctx.Logf(r.Context(), "panic after timeout: %s %s: %v\n%s", r.Method, r.URL.Path, p.value, p.stack)
+		return
+	}
+	tw.panic = p
+}
+
+func (tw *timeoutWriter) panicked() *handlerPanic {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.panic
+}
+
+// writeTimeout sends the 504; the caller must hold tw.mu
+func (tw *timeoutWriter) writeTimeout() {
+	if tw.timedOut {
+		return
+	}
+	tw.timedOut = true
+	response.Error(tw.w, http.StatusGatewayTimeout, "TIMEOUT", "Request timed out, try narrowing it down")
+}
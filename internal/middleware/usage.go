@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// UsageRecorder receives one call per API request
+type UsageRecorder interface {
+	Record(client, method, route string)
+}
+
+// APIUsage records every request under /api/ against its authenticated
+// caller (see reqctx.Info.Client) and chi route pattern (e.g.
+// /api/v1/posts/{id}), so usage groups by endpoint rather than by
+// individual URL
+func APIUsage(recorder UsageRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if !strings.HasPrefix(r.URL.Path, "/api/") {
+				return
+			}
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil {
+				return
+			}
+			route := rctx.RoutePattern()
+			if route == "" {
+				return
+			}
+
+			recorder.Record(reqctx.From(r.Context()).Client(), r.Method, route)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Access log resources and actions
+const (
+	AccessResourceContact    = "contact"
+	AccessResourceSubscriber = "subscriber"
+	AccessResourceUser       = "user"
+
+	AccessActionRead   = "read"
+	AccessActionList   = "list"
+	AccessActionExport = "export"
+
+	// Provisioning events, recorded whether or not access logging is on
+	AccessActionProvision  = "provision"
+	AccessActionUpdate     = "update"
+	AccessActionDeactivate = "deactivate"
+)
+
+// AccessLogEntry records that a client read personal data or provisioned a
+// user. ResourceID is empty for exports, where Query holds the filters that
+// were applied; for provisioning events Query describes the resulting user.
+type AccessLogEntry struct {
+	ID         uuid.UUID  `json:"id"`
+	Resource   string     `json:"resource"`
+	ResourceID *uuid.UUID `json:"resource_id,omitempty"`
+	Action     string     `json:"action"`
+	Client     string     `json:"client"`
+	IPAddress  string     `json:"ip_address"`
+	RequestID  *string    `json:"request_id,omitempty"`
+	Query      *string    `json:"query,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// AccessLogFilter represents filter options for listing access log entries
+type AccessLogFilter struct {
+	Resource   string
+	ResourceID *uuid.UUID
+	Client     string
+	From       *time.Time
+	To         *time.Time
+	PaginationParams
+}
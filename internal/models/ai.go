@@ -0,0 +1,54 @@
+package models
+
+import "github.com/google/uuid"
+
+// AIRequest is the input of the AI assistance endpoints: either a post,
+// whose title and content are used, or raw text
+type AIRequest struct {
+	PostID *uuid.UUID `json:"post_id,omitempty"`
+	Text   string     `json:"text,omitempty"`
+
+	// MaxWords caps the length of summaries
+	MaxWords int `json:"max_words,omitempty"`
+	// Limit caps the number of suggested tags
+	Limit int `json:"limit,omitempty"`
+	// Language is the target language of translations, e.g. "de" or "German"
+	Language string `json:"language,omitempty"`
+}
+
+// AISummary is a suggested summary; when generated for a post it can be
+// applied as the post's excerpt
+type AISummary struct {
+	PostID   *uuid.UUID `json:"post_id,omitempty"`
+	Summary  string     `json:"summary"`
+	Provider string     `json:"provider"`
+	Model    string     `json:"model,omitempty"`
+}
+
+// AITagSuggestions lists suggested tags. Suggestions matching an existing tag
+// by slug carry its ID; the others would have to be created first.
+type AITagSuggestions struct {
+	PostID   *uuid.UUID       `json:"post_id,omitempty"`
+	Tags     []AISuggestedTag `json:"tags"`
+	Provider string           `json:"provider"`
+	Model    string           `json:"model,omitempty"`
+}
+
+// AISuggestedTag is a single tag suggestion
+type AISuggestedTag struct {
+	Name  string     `json:"name"`
+	Slug  string     `json:"slug"`
+	TagID *uuid.UUID `json:"tag_id,omitempty"`
+}
+
+// AITranslation is a suggested translation. For posts the title and content
+// are translated separately; for raw text only Text is set.
+type AITranslation struct {
+	PostID   *uuid.UUID `json:"post_id,omitempty"`
+	Language string     `json:"language"`
+	Title    string     `json:"title,omitempty"`
+	Content  string     `json:"content,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Provider string     `json:"provider"`
+	Model    string     `json:"model,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrafficSource is where a post view came from: the referring domain and
+// the campaign's UTM parameters, each "" when absent
+type TrafficSource struct {
+	Referrer    string
+	UTMSource   string
+	UTMMedium   string
+	UTMCampaign string
+}
+
+// TrafficSourceFilter selects the views summarized by a traffic source
+// report: the days From to To (inclusive, UTC) of one post or all posts
+type TrafficSourceFilter struct {
+	From   time.Time
+	To     time.Time
+	PostID *uuid.UUID
+	Limit  int
+}
+
+// TrafficSourceCount is the number of views from one referrer or UTM value
+// and its share of all views in the period
+type TrafficSourceCount struct {
+	Name  string  `json:"name"`
+	Views int64   `json:"views"`
+	Share float64 `json:"share"`
+}
+
+// TrafficSourceReport summarizes where readers came from. Direct views
+// have neither a referrer nor UTM parameters; each list holds the top
+// values of one dimension, views without it left out.
+type TrafficSourceReport struct {
+	From         time.Time            `json:"from"`
+	To           time.Time            `json:"to"`
+	PostID       *uuid.UUID           `json:"post_id,omitempty"`
+	Views        int64                `json:"views"`
+	Direct       int64                `json:"direct"`
+	Referrers    []TrafficSourceCount `json:"referrers"`
+	UTMSources   []TrafficSourceCount `json:"utm_sources"`
+	UTMMediums   []TrafficSourceCount `json:"utm_mediums"`
+	UTMCampaigns []TrafficSourceCount `json:"utm_campaigns"`
+}
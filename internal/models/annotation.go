@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostAnnotation is an internal editorial note on a post, kept apart from
+// anything served publicly. Top-level annotations start a thread and may be
+// anchored to a block and/or a character range of the content; replies
+// (ParentID set) belong to that thread, which is resolved as a whole.
+type PostAnnotation struct {
+	ID         uuid.UUID     `json:"id"`
+	PostID     uuid.UUID     `json:"post_id"`
+	ParentID   *uuid.UUID    `json:"parent_id,omitempty"`
+	AuthorID   uuid.UUID     `json:"author_id"`
+	Author     *UserResponse `json:"author,omitempty"`
+	Body       string        `json:"body"`
+	BlockID    *string       `json:"block_id,omitempty"`
+	RangeStart *int          `json:"range_start,omitempty"`
+	RangeEnd   *int          `json:"range_end,omitempty"`
+	Quote      *string       `json:"quote,omitempty"`
+	MentionIDs []uuid.UUID   `json:"mention_ids"`
+	ResolvedAt *time.Time    `json:"resolved_at,omitempty"`
+	ResolvedBy *uuid.UUID    `json:"resolved_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+
+	// Replies of a top-level annotation, oldest first
+	Replies []PostAnnotation `json:"replies,omitempty"`
+}
+
+// CreateAnnotationRequest starts a thread or, with ParentID, replies to
+// one. Anchors (block ID, range and quoted text) only apply to new threads.
+type CreateAnnotationRequest struct {
+	// AuthorID is the authenticated user, not read from the body
+	AuthorID   uuid.UUID  `json:"-"`
+	Body       string     `json:"body"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	BlockID    *string    `json:"block_id,omitempty"`
+	RangeStart *int       `json:"range_start,omitempty"`
+	RangeEnd   *int       `json:"range_end,omitempty"`
+	Quote      *string    `json:"quote,omitempty"`
+}
+
+// UpdateAnnotationRequest edits an annotation's text
+type UpdateAnnotationRequest struct {
+	Body string `json:"body"`
+}
+
+// AnnotationFilter selects the threads of a post
+type AnnotationFilter struct {
+	PostID uuid.UUID
+	// Resolved limits threads to resolved (true) or open (false) ones
+	Resolved *bool
+}
+
+// AnnotationMention is the template data for a mention notification email
+type AnnotationMention struct {
+	Name      string
+	Author    string
+	PostTitle string
+	Body      string
+	Quote     string
+	URL       string
+}
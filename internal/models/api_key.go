@@ -0,0 +1,68 @@
+package models
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes. Read keys may only make GET, HEAD and OPTIONS requests.
+const (
+	APIKeyScopeRead = "read"
+	APIKeyScopeFull = "full"
+)
+
+// APIKey lets a machine client such as a build pipeline or headless
+// frontend call the API as the user who issued it, without logging in
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Usable reports whether the key is not expired at now
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// AllowsMethod reports whether the key's scope allows a request method
+func (k *APIKey) AllowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return k.Scope == APIKeyScopeFull
+}
+
+// ValidAPIKeyScope reports whether scope is read or full
+func ValidAPIKeyScope(scope string) bool {
+	return scope == APIKeyScopeRead || scope == APIKeyScopeFull
+}
+
+// CreatedAPIKey is a new key together with its secret, which is only ever
+// returned once
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKeyRequest represents the request to issue an API key. Scope
+// defaults to read.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyFilter represents filtering options for listing API keys
+type APIKeyFilter struct {
+	UserID *uuid.UUID
+	Search string
+	PaginationParams
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// APIUsage is the number of calls a client made to a route in a period
+type APIUsage struct {
+	Client     string    `json:"client"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route"`
+	Count      int64     `json:"count"`
+	LastCallAt time.Time `json:"last_call_at"`
+}
+
+// APIUsageFilter represents filter options for the API usage report.
+// From and To are inclusive days.
+type APIUsageFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Client string
+	Route  string
+	PaginationParams
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalStatus represents the state of an approval request
+type ApprovalStatus int16
+
+const (
+	ApprovalStatusPending   ApprovalStatus = 1
+	ApprovalStatusApproved  ApprovalStatus = 2
+	ApprovalStatusRejected  ApprovalStatus = 3
+	ApprovalStatusCancelled ApprovalStatus = 4
+)
+
+func (s ApprovalStatus) String() string {
+	switch s {
+	case ApprovalStatusPending:
+		return "pending"
+	case ApprovalStatusApproved:
+		return "approved"
+	case ApprovalStatusRejected:
+		return "rejected"
+	case ApprovalStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON writes the name of an approval status
+func (s ApprovalStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum(s)
+}
+
+// UnmarshalJSON accepts the name or the number of an approval status
+func (s *ApprovalStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, "approval status")
+}
+
+// ApprovalVerdict is an approver's decision on a step
+type ApprovalVerdict int16
+
+const (
+	ApprovalVerdictApproved ApprovalVerdict = 1
+	ApprovalVerdictRejected ApprovalVerdict = 2
+)
+
+func (v ApprovalVerdict) String() string {
+	switch v {
+	case ApprovalVerdictApproved:
+		return "approved"
+	case ApprovalVerdictRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON writes the name of a verdict
+func (v ApprovalVerdict) MarshalJSON() ([]byte, error) {
+	return marshalEnum(v)
+}
+
+// UnmarshalJSON accepts the name or the number of a verdict
+func (v *ApprovalVerdict) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, v, "verdict")
+}
+
+// ApprovalStep is one stage of an approval chain, decided by a user holding
+// Role. A step still pending SLAHours after it started (zero means no SLA)
+// is escalated: users holding EscalateTo may then decide it too.
+type ApprovalStep struct {
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	SLAHours   int    `json:"sla_hours,omitempty"`
+	EscalateTo string `json:"escalate_to,omitempty"`
+}
+
+// ApprovalChain is the sequence of approval steps posts of a content type
+// go through, e.g. editor, then legal, then publisher
+type ApprovalChain struct {
+	ContentTypeID uuid.UUID      `json:"content_type_id"`
+	Steps         []ApprovalStep `json:"steps"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// SetApprovalChainRequest replaces a content type's approval chain
+type SetApprovalChainRequest struct {
+	Steps []ApprovalStep `json:"steps"`
+}
+
+// ApprovalRequest is a post going through an approval chain. Steps is a
+// copy of the chain taken when the request was submitted, so later changes
+// to the chain don't affect it; Step indexes the step awaiting a decision.
+type ApprovalRequest struct {
+	ID            uuid.UUID      `json:"id"`
+	PostID        uuid.UUID      `json:"post_id"`
+	PostTitle     string         `json:"post_title,omitempty"`
+	Steps         []ApprovalStep `json:"steps"`
+	Step          int            `json:"step"`
+	CurrentStep   *ApprovalStep  `json:"current_step,omitempty"`
+	Status        ApprovalStatus `json:"status"`
+	RequestedBy   *uuid.UUID     `json:"requested_by,omitempty"`
+	StepStartedAt time.Time      `json:"step_started_at"`
+	StepDueAt     *time.Time     `json:"step_due_at,omitempty"`
+	EscalatedAt   *time.Time     `json:"escalated_at,omitempty"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// Decisions taken so far, oldest first; only filled in for a single
+	// request
+	Decisions []ApprovalDecision `json:"decisions,omitempty"`
+}
+
+// Fill sets the derived CurrentStep and StepDueAt of a pending request
+func (a *ApprovalRequest) Fill() {
+	if a.Status != ApprovalStatusPending || a.Step < 0 || a.Step >= len(a.Steps) {
+		return
+	}
+	step := a.Steps[a.Step]
+	a.CurrentStep = &step
+	if step.SLAHours > 0 {
+		due := a.StepStartedAt.Add(time.Duration(step.SLAHours) * time.Hour)
+		a.StepDueAt = &due
+	}
+}
+
+// ApprovalDecision records a verdict on one step of a request
+type ApprovalDecision struct {
+	ID        uuid.UUID       `json:"id"`
+	RequestID uuid.UUID       `json:"request_id"`
+	Step      int             `json:"step"`
+	StepName  string          `json:"step_name"`
+	Verdict   ApprovalVerdict `json:"verdict"`
+	UserID    *uuid.UUID      `json:"user_id,omitempty"`
+	Comment   *string         `json:"comment,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ApprovalDecisionRequest comments on the approval or rejection of the
+// current step of a request
+type ApprovalDecisionRequest struct {
+	Comment *string `json:"comment,omitempty"`
+}
+
+// ApprovalQueueFilter selects pending requests awaiting any of Roles; nil
+// Roles selects every pending request
+type ApprovalQueueFilter struct {
+	PaginationParams
+	Roles []string
+}
+
+// SetApprovalRolesRequest replaces the approval roles a user holds
+type SetApprovalRolesRequest struct {
+	Roles []string `json:"roles"`
+}
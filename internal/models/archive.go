@@ -0,0 +1,14 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// ArchivedRow is a row moved out of a hot table, with its columns as a
+// JSON object
+type ArchivedRow struct {
+	ID   uuid.UUID
+	Data json.RawMessage
+}
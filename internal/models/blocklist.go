@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlocklistRuleType represents what a blocklist rule matches against
+type BlocklistRuleType int16
+
+const (
+	BlocklistRuleIP           BlocklistRuleType = 1 // IP address or CIDR range
+	BlocklistRuleEmailDomain  BlocklistRuleType = 2 // exact email domain
+	BlocklistRuleEmailPattern BlocklistRuleType = 3 // email glob pattern, * matches anything
+)
+
+func (t BlocklistRuleType) String() string {
+	switch t {
+	case BlocklistRuleIP:
+		return "ip"
+	case BlocklistRuleEmailDomain:
+		return "email_domain"
+	case BlocklistRuleEmailPattern:
+		return "email_pattern"
+	default:
+		return "unknown"
+	}
+}
+
+// BlocklistAction represents what happens to a submission matching a rule
+type BlocklistAction int16
+
+const (
+	BlocklistActionReject  BlocklistAction = 1 // respond with 403
+	BlocklistActionDiscard BlocklistAction = 2 // pretend success, store nothing
+)
+
+func (a BlocklistAction) String() string {
+	switch a {
+	case BlocklistActionReject:
+		return "reject"
+	case BlocklistActionDiscard:
+		return "discard"
+	default:
+		return "unknown"
+	}
+}
+
+// BlocklistRule represents a rule blocking public submissions
+type BlocklistRule struct {
+	ID        uuid.UUID         `json:"id"`
+	RuleType  BlocklistRuleType `json:"rule_type"`
+	Value     string            `json:"value"`
+	Action    BlocklistAction   `json:"action"`
+	Reason    *string           `json:"reason,omitempty"`
+	HitCount  int64             `json:"hit_count"`
+	LastHitAt *time.Time        `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// CreateBlocklistRuleRequest represents the request to create a blocklist rule
+type CreateBlocklistRuleRequest struct {
+	RuleType BlocklistRuleType `json:"rule_type"`
+	Value    string            `json:"value"`
+	Action   *BlocklistAction  `json:"action,omitempty"`
+	Reason   *string           `json:"reason,omitempty"`
+}
+
+// UpdateBlocklistRuleRequest represents the request to update a blocklist rule
+type UpdateBlocklistRuleRequest struct {
+	Value  *string          `json:"value,omitempty"`
+	Action *BlocklistAction `json:"action,omitempty"`
+	Reason *string          `json:"reason,omitempty"`
+}
+
+// BlocklistFilter represents filter options for blocklist rules
+type BlocklistFilter struct {
+	RuleType *BlocklistRuleType
+	Search   string
+	PaginationParams
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheHints tell caches and CDNs how long public responses for a post may
+// be kept (MaxAge for browsers, SharedMaxAge for shared caches) and under
+// which surrogate keys they can be purged. They are set on a content type
+// or a post; a post's ages override its content type's and the surrogate
+// keys of both apply.
+type CacheHints struct {
+	ContentTypeID *uuid.UUID `json:"content_type_id,omitempty"`
+	PostID        *uuid.UUID `json:"post_id,omitempty"`
+	MaxAge        *int       `json:"max_age"`
+	SharedMaxAge  *int       `json:"s_maxage"`
+	SurrogateKeys []string   `json:"surrogate_keys"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetCacheHintsRequest sets the cache hints of a content type or post,
+// replacing any set before. Ages are in seconds; a nil age is left to the
+// content type or the endpoint's default.
+type SetCacheHintsRequest struct {
+	MaxAge        *int     `json:"max_age"`
+	SharedMaxAge  *int     `json:"s_maxage"`
+	SurrogateKeys []string `json:"surrogate_keys"`
+}
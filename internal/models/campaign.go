@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignStatus represents the progress of an email campaign
+type CampaignStatus int16
+
+const (
+	CampaignStatusSending   CampaignStatus = 1
+	CampaignStatusSent      CampaignStatus = 2
+	CampaignStatusCancelled CampaignStatus = 3
+)
+
+func (s CampaignStatus) String() string {
+	switch s {
+	case CampaignStatusSending:
+		return "sending"
+	case CampaignStatusSent:
+		return "sent"
+	case CampaignStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// Campaign is an email digest of posts sent to every subscriber. Recipients
+// are queued in throttled batches; delivery and opens are tracked through
+// the email queue.
+type Campaign struct {
+	ID             uuid.UUID      `json:"id"`
+	Name           string         `json:"name"`
+	Subject        *string        `json:"subject,omitempty"`
+	Intro          *string        `json:"intro,omitempty"`
+	TemplateName   string         `json:"template_name"`
+	PostIDs        []uuid.UUID    `json:"post_ids"`
+	Status         CampaignStatus `json:"status"`
+	QueuedCount    int            `json:"queued_count"`
+	LastSubscriber *uuid.UUID     `json:"-"`
+	NextBatchAt    time.Time      `json:"next_batch_at"`
+	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Stats          *CampaignStats `json:"stats,omitempty"`
+}
+
+// CampaignStats summarizes the queued emails of a campaign
+type CampaignStats struct {
+	Pending  int64   `json:"pending"`
+	Sent     int64   `json:"sent"`
+	Failed   int64   `json:"failed"`
+	Opened   int64   `json:"opened"`
+	OpenRate float64 `json:"open_rate"`
+}
+
+// CreateCampaignRequest represents the request to compose and send a campaign
+type CreateCampaignRequest struct {
+	Name     string      `json:"name"`
+	Subject  *string     `json:"subject,omitempty"`
+	Intro    *string     `json:"intro,omitempty"`
+	Template string      `json:"template,omitempty"`
+	PostIDs  []uuid.UUID `json:"post_ids"`
+	SendAt   *time.Time  `json:"send_at,omitempty"`
+}
+
+// CampaignFilter represents filter options for campaigns
+type CampaignFilter struct {
+	Status *CampaignStatus
+	PaginationParams
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a node in the category hierarchy. Top-level categories have
+// no parent.
+type Category struct {
+	ID           uuid.UUID  `json:"id"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	Name         string     `json:"name"`
+	Slug         string     `json:"slug"`
+	Description  *string    `json:"description,omitempty"`
+	DisplayOrder int        `json:"display_order"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Subcategories (populated for trees)
+	Children []Category `json:"children,omitempty"`
+}
+
+// CreateCategoryRequest represents the request to create a category
+type CreateCategoryRequest struct {
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	Name         string     `json:"name"`
+	Slug         string     `json:"slug"`
+	Description  *string    `json:"description,omitempty"`
+	DisplayOrder int        `json:"display_order"`
+}
+
+// UpdateCategoryRequest represents the request to update a category. A nil
+// UUID parent ID makes the category top-level.
+type UpdateCategoryRequest struct {
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	Name         *string    `json:"name,omitempty"`
+	Slug         *string    `json:"slug,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	DisplayOrder *int       `json:"display_order,omitempty"`
+}
+
+// CategoryFilter represents filter options for categories
+type CategoryFilter struct {
+	// ParentID lists the direct subcategories of a category
+	ParentID *uuid.UUID
+	Search   string
+	PaginationParams
+}
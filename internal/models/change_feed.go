@@ -0,0 +1,8 @@
+package models
+
+// WALChange is a change read from a logical decoding slot: its LSN and the
+// output plugin's rendering of it
+type WALChange struct {
+	LSN  string
+	Data string
+}
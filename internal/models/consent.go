@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsentVersion represents a published version of the privacy policy / terms
+type ConsentVersion struct {
+	ID          uuid.UUID `json:"id"`
+	Version     string    `json:"version"`
+	Title       string    `json:"title"`
+	Content     *string   `json:"content,omitempty"`
+	DocumentURL *string   `json:"document_url,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateConsentVersionRequest represents the request to publish a consent version
+type CreateConsentVersionRequest struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	Content     *string    `json:"content,omitempty"`
+	DocumentURL *string    `json:"document_url,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// ConsentVersionFilter represents filter options for consent versions
+type ConsentVersionFilter struct {
+	PaginationParams
+}
@@ -33,6 +33,16 @@ func (s ContactStatus) String() string {
 	}
 }
 
+// MarshalJSON writes the name of a contact status
+func (s ContactStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum(s)
+}
+
+// UnmarshalJSON accepts the name or the number of a contact status
+func (s *ContactStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, "contact status")
+}
+
 // ContactSubmission represents a contact form submission
 type ContactSubmission struct {
 	ID        uuid.UUID       `json:"id"`
@@ -47,6 +57,10 @@ type ContactSubmission struct {
 	Metadata  json.RawMessage `json:"metadata,omitempty"`
 	ReadAt    *time.Time      `json:"read_at,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
+
+	// Consent captured at submission time
+	ConsentVersionID  *uuid.UUID `json:"consent_version_id,omitempty"`
+	ConsentAcceptedAt *time.Time `json:"consent_accepted_at,omitempty"`
 }
 
 // CreateContactRequest represents the request to create a contact submission
@@ -59,6 +73,14 @@ type CreateContactRequest struct {
 	IPAddress *string         `json:"ip_address,omitempty"`
 	UserAgent *string         `json:"user_agent,omitempty"`
 	Metadata  json.RawMessage `json:"metadata,omitempty"`
+
+	// Fields holds the values of the extra fields configured in the
+	// contacts.fields setting; they are stored in Metadata
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+
+	// ConsentVersion is the privacy policy version the submitter accepted
+	ConsentVersion   *string    `json:"consent_version,omitempty"`
+	ConsentVersionID *uuid.UUID `json:"-"`
 }
 
 // UpdateContactRequest represents the request to update a contact submission
@@ -70,5 +92,35 @@ type UpdateContactRequest struct {
 type ContactFilter struct {
 	Status *ContactStatus
 	Email  string
+	Meta   []MetaFilter
 	PaginationParams
 }
+
+// Contact field types
+const (
+	ContactFieldString  = "string"
+	ContactFieldNumber  = "number"
+	ContactFieldBoolean = "boolean"
+	ContactFieldSelect  = "select"
+)
+
+// ContactField defines an extra contact form field. Values are stored in the
+// submission's metadata under Name. MaxLength applies to strings, Min and
+// Max to numbers and Options lists the allowed values of a select.
+type ContactField struct {
+	Name      string   `json:"name"`
+	Label     string   `json:"label,omitempty"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Options   []string `json:"options,omitempty"`
+}
+
+// ContactCountryCount represents the number of submissions from a country
+type ContactCountryCount struct {
+	CountryCode string `json:"country_code"`
+	Country     string `json:"country"`
+	Count       int64  `json:"count"`
+}
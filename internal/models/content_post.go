@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +30,29 @@ func (s PostStatus) String() string {
 	}
 }
 
+// MarshalJSON writes the name of a post status
+func (s PostStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum(s)
+}
+
+// UnmarshalJSON accepts the name or the number of a post status
+func (s *PostStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, "post status")
+}
+
+// Content environments. Posts live in "live" unless they are staged in
+// "draft", either as new draft-only posts or as copy-on-write copies of live
+// posts (LivePostID set) that replace the live post when promoted.
+const (
+	EnvironmentLive  = "live"
+	EnvironmentDraft = "draft"
+)
+
+// ValidEnvironment reports whether env names a content environment
+func ValidEnvironment(env string) bool {
+	return env == EnvironmentLive || env == EnvironmentDraft
+}
+
 // ContentPost represents a content post
 type ContentPost struct {
 	ID            uuid.UUID       `json:"id"`
@@ -42,20 +66,71 @@ type ContentPost struct {
 	Status        PostStatus      `json:"status"`
 	PublishedAt   *time.Time      `json:"published_at,omitempty"`
 	ViewCount     int             `json:"view_count"`
+	Environment   string          `json:"environment"`
+	LivePostID    *uuid.UUID      `json:"live_post_id,omitempty"`
+	Latitude      *float64        `json:"latitude,omitempty"`
+	Longitude     *float64        `json:"longitude,omitempty"`
+	StartsAt      *time.Time      `json:"starts_at,omitempty"`
+	EndsAt        *time.Time      `json:"ends_at,omitempty"`
+	Recurrence    *string         `json:"recurrence,omitempty"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"`
+	TeamID        *uuid.UUID      `json:"team_id,omitempty"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
 
+	// Distance from the near point in kilometres (populated for near queries)
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// Rolling view deltas (populated when requested)
+	Views7d  *int64 `json:"views_7d,omitempty"`
+	Views30d *int64 `json:"views_30d,omitempty"`
+
 	// Relations (populated on demand)
 	ContentType *ContentType  `json:"content_type,omitempty"`
 	Author      *UserResponse `json:"author,omitempty"`
 	Tags        []Tag         `json:"tags,omitempty"`
+	Categories  []Category    `json:"categories,omitempty"`
 	Media       []PostMedia   `json:"media,omitempty"`
+
+	// Open editorial annotation threads (admin post detail only)
+	Annotations []PostAnnotation `json:"annotations,omitempty"`
+}
+
+// Path returns the post's site path, without a leading slash, from its
+// content type's slug pattern. Dates come from the publication, or the
+// creation of posts that aren't published.
+func (p *ContentPost) Path() string {
+	ct := p.ContentType
+	if ct == nil {
+		ct = &ContentType{}
+	}
+	date := p.CreatedAt
+	if p.PublishedAt != nil {
+		date = *p.PublishedAt
+	}
+	return ct.PostPath(p.Slug, date)
 }
 
-// CreatePostRequest represents the request to create a post
+// PostURLs are the public URLs of a post: its site path, canonical URL and
+// URL in each of the site's locales
+type PostURLs struct {
+	Path    string            `json:"path"`
+	URL     string            `json:"url"`
+	Locales map[string]string `json:"locales,omitempty"`
+}
+
+// ScheduledPost is a draft due to be published
+type ScheduledPost struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// CreatePostRequest represents the request to create a post. AuthorID
+// defaults to the authenticated user; only admins may name another author.
 type CreatePostRequest struct {
 	ContentTypeID uuid.UUID       `json:"content_type_id"`
-	AuthorID      uuid.UUID       `json:"author_id"`
+	AuthorID      uuid.UUID       `json:"author_id,omitempty"`
 	Title         string          `json:"title"`
 	Slug          string          `json:"slug"`
 	Excerpt       *string         `json:"excerpt,omitempty"`
@@ -64,9 +139,19 @@ type CreatePostRequest struct {
 	Status        *PostStatus     `json:"status,omitempty"`
 	PublishedAt   *time.Time      `json:"published_at,omitempty"`
 	TagIDs        []uuid.UUID     `json:"tag_ids,omitempty"`
+	CategoryIDs   []uuid.UUID     `json:"category_ids,omitempty"`
+	Environment   string          `json:"environment,omitempty"`
+	Latitude      *float64        `json:"latitude,omitempty"`
+	Longitude     *float64        `json:"longitude,omitempty"`
+	StartsAt      *time.Time      `json:"starts_at,omitempty"`
+	EndsAt        *time.Time      `json:"ends_at,omitempty"`
+	Recurrence    *string         `json:"recurrence,omitempty"`
+	ExpiresAt     *time.Time      `json:"expires_at,omitempty"`
+	TeamID        *uuid.UUID      `json:"team_id,omitempty"`
 }
 
-// UpdatePostRequest represents the request to update a post
+// UpdatePostRequest represents the request to update a post. A nil UUID
+// team ID makes the post shared again. Only admins may change AuthorID.
 type UpdatePostRequest struct {
 	ContentTypeID *uuid.UUID       `json:"content_type_id,omitempty"`
 	Title         *string          `json:"title,omitempty"`
@@ -77,13 +162,167 @@ type UpdatePostRequest struct {
 	Status        *PostStatus      `json:"status,omitempty"`
 	PublishedAt   *time.Time       `json:"published_at,omitempty"`
 	TagIDs        *[]uuid.UUID     `json:"tag_ids,omitempty"`
+	CategoryIDs   *[]uuid.UUID     `json:"category_ids,omitempty"`
+	Latitude      *float64         `json:"latitude,omitempty"`
+	Longitude     *float64         `json:"longitude,omitempty"`
+	StartsAt      *time.Time       `json:"starts_at,omitempty"`
+	EndsAt        *time.Time       `json:"ends_at,omitempty"`
+	Recurrence    *string          `json:"recurrence,omitempty"`
+	ExpiresAt     *time.Time       `json:"expires_at,omitempty"`
+	TeamID        *uuid.UUID       `json:"team_id,omitempty"`
+	AuthorID      *uuid.UUID       `json:"author_id,omitempty"`
 }
 
 // PostFilter represents filter options for posts
 type PostFilter struct {
-	ContentTypeID *uuid.UUID
-	AuthorID      *uuid.UUID
-	Status        *PostStatus
-	Search        string
+	ContentTypeID   *uuid.UUID
+	ContentTypeSlug string
+	AuthorID        *uuid.UUID
+	Status          *PostStatus
+	Search          string
+
+	// TeamID restricts results to a team's space
+	TeamID *uuid.UUID
+
+	// CategoryID restricts results to posts in a category or any of its
+	// subcategories
+	CategoryID *uuid.UUID
+
+	// Environment selects live posts (default) or the draft view, in which
+	// draft copies replace the live posts they were forked from
+	Environment string
+
+	// Meta filters on top-level metadata fields; all must match
+	Meta []MetaFilter
+
+	// Expired selects posts whose expires_at has (true) or has not (false) passed
+	Expired *bool
+
+	// Near restricts results to posts within RadiusKm of a point
+	Near     *GeoPoint
+	RadiusKm float64
+
+	// IncludeViewStats populates Views7d and Views30d from the daily rollups
+	IncludeViewStats bool
 	PaginationParams
 }
+
+// EventFilter selects published event posts (posts with starts_at) for
+// upcoming listings and calendar feeds
+type EventFilter struct {
+	ContentTypeSlug string
+	TagSlug         string
+
+	// ContentTypeSlugs limits events to some content types, e.g. those a
+	// delivery token is scoped to; nil allows all
+	ContentTypeSlugs []string
+
+	// From drops one-off events that ended before it; recurring events are
+	// always returned and expanded by the caller
+	From *time.Time
+}
+
+// EventOccurrence is one occurrence of an event post
+type EventOccurrence struct {
+	StartsAt time.Time     `json:"starts_at"`
+	EndsAt   *time.Time    `json:"ends_at,omitempty"`
+	Post     *DeliveryPost `json:"post"`
+}
+
+// GeoPoint is a WGS 84 coordinate in degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// ValidCoordinates reports whether lat and lng are within WGS 84 bounds
+func ValidCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// Metadata filter operators
+const (
+	MetaOpEq  = "eq"
+	MetaOpLt  = "lt"
+	MetaOpLte = "lte"
+	MetaOpGt  = "gt"
+	MetaOpGte = "gte"
+)
+
+// metaFieldPattern is a top-level metadata field name as accepted in
+// filters, aggregations and index declarations
+var metaFieldPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ValidMetaField reports whether name is a top-level metadata field name
+// that filters, aggregations and index declarations accept
+func ValidMetaField(name string) bool {
+	return metaFieldPattern.MatchString(name)
+}
+
+// MetaFilter compares a top-level metadata field with a value. Values that
+// parse as JSON (numbers, true, false) are compared as such, anything else as
+// a string.
+type MetaFilter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Aggregation metrics
+const (
+	AggregateCount = "count"
+	AggregateAvg   = "avg"
+	AggregateSum   = "sum"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+)
+
+// PostAggregate describes a grouped aggregation over post metadata. GroupBy
+// and MetricField name top-level metadata fields; an empty GroupBy yields a
+// single bucket and MetricField is unused for counts.
+type PostAggregate struct {
+	Filter      PostFilter
+	GroupBy     string
+	Metric      string
+	MetricField string
+}
+
+// PostAggregateBucket is one group of an aggregation. Key is nil for posts
+// without the grouped field; Value is nil for counts and for groups without
+// numeric values.
+type PostAggregateBucket struct {
+	Key   *string  `json:"key"`
+	Count int64    `json:"count"`
+	Value *float64 `json:"value,omitempty"`
+}
+
+// Facet holds the most common values of a field among the posts matching a
+// list filter. Truncated is set when more distinct values exist than were
+// returned.
+type Facet struct {
+	Field     string       `json:"field"`
+	Values    []FacetValue `json:"values"`
+	Truncated bool         `json:"truncated,omitempty"`
+}
+
+// FacetValue is a facet value and the number of matching posts carrying it
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PostViewStats represents rolling view totals for a post
+type PostViewStats struct {
+	Views7d  int64
+	Views30d int64
+}
+
+// PostRendition is a sanitized, simplified HTML version of a post's content
+type PostRendition struct {
+	PostID  uuid.UUID `json:"post_id"`
+	Slug    string    `json:"slug"`
+	Title   string    `json:"title"`
+	Format  string    `json:"format"`
+	Variant string    `json:"variant,omitempty"`
+	HTML    string    `json:"html"`
+}
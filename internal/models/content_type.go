@@ -2,39 +2,153 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// ContentType represents a content type definition
+// Content type traits switch on extra validated post fields and behaviours
+const (
+	// TraitExpiring requires expires_at; published posts are archived once it passes
+	TraitExpiring = "expiring"
+	// TraitGeolocated requires latitude and longitude
+	TraitGeolocated = "geolocated"
+	// TraitPriced requires a numeric metadata.price and a metadata.currency code
+	TraitPriced = "priced"
+	// TraitBookable requires starts_at and allows a positive integer metadata.capacity
+	TraitBookable = "bookable"
+)
+
+// ValidTrait reports whether t names a content type trait
+func ValidTrait(t string) bool {
+	switch t {
+	case TraitExpiring, TraitGeolocated, TraitPriced, TraitBookable:
+		return true
+	}
+	return false
+}
+
+// Indexed metadata field types
+const (
+	IndexedFieldNumber = "number"
+	IndexedFieldString = "string"
+)
+
+// MaxIndexedFieldLength keeps the names of the indexes created for
+// declared fields within PostgreSQL's identifier limit
+const MaxIndexedFieldLength = 32
+
+// IndexedField declares a top-level metadata field whose range filters
+// should be served by an index. Migrations create one expression index per
+// declared field name and type, shared by all content types declaring it.
+type IndexedField struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+}
+
+// Valid reports whether f names an indexable field and type
+func (f IndexedField) Valid() bool {
+	return ValidMetaField(f.Field) && len(f.Field) <= MaxIndexedFieldLength &&
+		(f.Type == IndexedFieldNumber || f.Type == IndexedFieldString)
+}
+
+// DefaultSlugPattern is the site path of posts of content types that don't
+// set one
+const DefaultSlugPattern = "posts/{slug}"
+
+// reservedPathPrefixes are first path segments the server routes itself
+var reservedPathPrefixes = []string{"api", "admin", "scim", "theme", "icons", "health", ".well-known"}
+
+// ValidSlugPattern reports whether pattern is a site path of lowercase
+// letters, digits, hyphens, underscores and the {year}, {month} and {type}
+// tokens, whose last segment is {slug} and whose first isn't routed by the
+// server itself
+func ValidSlugPattern(pattern string) bool {
+	segments := strings.Split(pattern, "/")
+	if segments[len(segments)-1] != "{slug}" {
+		return false
+	}
+	for i, segment := range segments[:len(segments)-1] {
+		if i == 0 {
+			for _, reserved := range reservedPathPrefixes {
+				if segment == reserved {
+					return false
+				}
+			}
+		}
+		literal := strings.NewReplacer("{year}", "", "{month}", "", "{type}", "").Replace(segment)
+		if segment == "" || strings.Trim(literal, "abcdefghijklmnopqrstuvwxyz0123456789-_") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentType represents a content type definition. SlugPattern is the site
+// path of its posts (see ValidSlugPattern).
 type ContentType struct {
-	ID           uuid.UUID       `json:"id"`
-	Name         string          `json:"name"`
-	Slug         string          `json:"slug"`
-	SchemaFields json.RawMessage `json:"schema_fields,omitempty"`
-	IsActive     bool            `json:"is_active"`
-	DisplayOrder int             `json:"display_order"`
-	CreatedAt    time.Time       `json:"created_at"`
-	UpdatedAt    time.Time       `json:"updated_at"`
+	ID            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Slug          string          `json:"slug"`
+	SchemaFields  json.RawMessage `json:"schema_fields,omitempty"`
+	Traits        []string        `json:"traits"`
+	IndexedFields []IndexedField  `json:"indexed_fields"`
+	SlugPattern   string          `json:"slug_pattern"`
+	IsActive      bool            `json:"is_active"`
+	DisplayOrder  int             `json:"display_order"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// HasTrait reports whether the content type has opted into trait t
+func (ct *ContentType) HasTrait(t string) bool {
+	for _, trait := range ct.Traits {
+		if trait == t {
+			return true
+		}
+	}
+	return false
+}
+
+// PostPath expands the slug pattern for a post. The date tokens are in UTC.
+func (ct *ContentType) PostPath(slug string, date time.Time) string {
+	pattern := ct.SlugPattern
+	if pattern == "" {
+		pattern = DefaultSlugPattern
+	}
+	date = date.UTC()
+	return strings.NewReplacer(
+		"{year}", fmt.Sprintf("%04d", date.Year()),
+		"{month}", fmt.Sprintf("%02d", int(date.Month())),
+		"{type}", ct.Slug,
+		"{slug}", slug,
+	).Replace(pattern)
 }
 
 // CreateContentTypeRequest represents the request to create a content type
 type CreateContentTypeRequest struct {
-	Name         string          `json:"name"`
-	Slug         string          `json:"slug"`
-	SchemaFields json.RawMessage `json:"schema_fields,omitempty"`
-	IsActive     *bool           `json:"is_active,omitempty"`
-	DisplayOrder *int            `json:"display_order,omitempty"`
+	Name          string          `json:"name"`
+	Slug          string          `json:"slug"`
+	SchemaFields  json.RawMessage `json:"schema_fields,omitempty"`
+	Traits        []string        `json:"traits,omitempty"`
+	IndexedFields []IndexedField  `json:"indexed_fields,omitempty"`
+	SlugPattern   string          `json:"slug_pattern,omitempty"`
+	IsActive      *bool           `json:"is_active,omitempty"`
+	DisplayOrder  *int            `json:"display_order,omitempty"`
 }
 
 // UpdateContentTypeRequest represents the request to update a content type
 type UpdateContentTypeRequest struct {
-	Name         *string          `json:"name,omitempty"`
-	Slug         *string          `json:"slug,omitempty"`
-	SchemaFields *json.RawMessage `json:"schema_fields,omitempty"`
-	IsActive     *bool            `json:"is_active,omitempty"`
-	DisplayOrder *int             `json:"display_order,omitempty"`
+	Name          *string          `json:"name,omitempty"`
+	Slug          *string          `json:"slug,omitempty"`
+	SchemaFields  *json.RawMessage `json:"schema_fields,omitempty"`
+	Traits        *[]string        `json:"traits,omitempty"`
+	IndexedFields *[]IndexedField  `json:"indexed_fields,omitempty"`
+	SlugPattern   *string          `json:"slug_pattern,omitempty"`
+	IsActive      *bool            `json:"is_active,omitempty"`
+	DisplayOrder  *int             `json:"display_order,omitempty"`
 }
 
 // ContentTypeFilter represents filter options for content types
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dashboard holds the editorial dashboard aggregates, read from
+// materialized views that are refreshed in the background
+type Dashboard struct {
+	PostCounts []DashboardPostCount  `json:"post_counts"`
+	DailyViews []DashboardDailyViews `json:"daily_views"`
+	TopTags    []DashboardTagCount   `json:"top_tags"`
+}
+
+// DashboardPostCount is the number of live posts of a content type in a
+// status
+type DashboardPostCount struct {
+	ContentTypeID uuid.UUID  `json:"content_type_id"`
+	ContentType   string     `json:"content_type"`
+	Status        PostStatus `json:"status"`
+	Posts         int64      `json:"posts"`
+}
+
+// DashboardDailyViews is the number of views on a day (UTC) and of posts
+// viewed
+type DashboardDailyViews struct {
+	Day   string `json:"day"`
+	Views int64  `json:"views"`
+	Posts int64  `json:"posts"`
+}
+
+// DashboardTagCount is the number of published live posts with a tag
+type DashboardTagCount struct {
+	TagID uuid.UUID `json:"tag_id"`
+	Name  string    `json:"name"`
+	Slug  string    `json:"slug"`
+	Posts int64     `json:"posts"`
+}
+
+// AggregateFreshness tells when a materialized aggregate was last
+// refreshed. Stale is set when it was never refreshed or is older than
+// expected.
+type AggregateFreshness struct {
+	View        string     `json:"view"`
+	RefreshedAt *time.Time `json:"refreshed_at"`
+	DurationMs  int        `json:"duration_ms,omitempty"`
+	AgeSeconds  *int64     `json:"age_seconds,omitempty"`
+	Stale       bool       `json:"stale"`
+}
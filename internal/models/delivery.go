@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryPost is a published post as served by the public delivery API:
+// editorial and storage internals are left out and media are resolved to
+// ready-to-use URLs. Path is the post's site path from its content type's
+// slug pattern.
+type DeliveryPost struct {
+	ID          uuid.UUID       `json:"id"`
+	Title       string          `json:"title"`
+	Slug        string          `json:"slug"`
+	Path        string          `json:"path"`
+	Excerpt     *string         `json:"excerpt,omitempty"`
+	Content     *string         `json:"content,omitempty"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ContentType *DeliveryRef    `json:"content_type,omitempty"`
+	Author      *DeliveryAuthor `json:"author,omitempty"`
+	Tags        []DeliveryRef   `json:"tags,omitempty"`
+	Media       []DeliveryMedia `json:"media,omitempty"`
+	Latitude    *float64        `json:"latitude,omitempty"`
+	Longitude   *float64        `json:"longitude,omitempty"`
+	StartsAt    *time.Time      `json:"starts_at,omitempty"`
+	EndsAt      *time.Time      `json:"ends_at,omitempty"`
+	Recurrence  *string         `json:"recurrence,omitempty"`
+}
+
+// DeliveryRef names a content type or tag
+type DeliveryRef struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// DeliveryAuthor is the public face of a post's author
+type DeliveryAuthor struct {
+	Name string `json:"name"`
+}
+
+// DeliveryMedia is a media item attached to a post, with the URL of the
+// original file and of each variant
+type DeliveryMedia struct {
+	ID       uuid.UUID                `json:"id"`
+	Role     string                   `json:"role"`
+	FileType string                   `json:"file_type"`
+	MimeType string                   `json:"mime_type"`
+	AltText  *string                  `json:"alt_text,omitempty"`
+	URL      string                   `json:"url"`
+	Width    int                      `json:"width,omitempty"`
+	Height   int                      `json:"height,omitempty"`
+	Variants map[string]DeliveryImage `json:"variants,omitempty"`
+}
+
+// DeliveryImage is one variant of a media item
+type DeliveryImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
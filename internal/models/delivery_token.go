@@ -0,0 +1,84 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryToken is a read-only token for the public delivery API. Empty
+// scopes allow all content types (by slug), locales or environments.
+type DeliveryToken struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	TokenPrefix  string     `json:"token_prefix"`
+	ContentTypes []string   `json:"content_types"`
+	Locales      []string   `json:"locales"`
+	Environments []string   `json:"environments"`
+	IsActive     bool       `json:"is_active"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Usable reports whether the token is active and not expired at now
+func (t *DeliveryToken) Usable(now time.Time) bool {
+	return t.IsActive && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// AllowsContentType reports whether the token may read posts of a content type
+func (t *DeliveryToken) AllowsContentType(slug string) bool {
+	return len(t.ContentTypes) == 0 || slices.Contains(t.ContentTypes, slug)
+}
+
+// AllowsEnvironment reports whether the token may read an environment
+func (t *DeliveryToken) AllowsEnvironment(env string) bool {
+	return len(t.Environments) == 0 || slices.Contains(t.Environments, env)
+}
+
+// AllowsLocale reports whether the token may request a locale. A scope
+// entry without a region ("pt") also allows its regional variants ("pt-br").
+func (t *DeliveryToken) AllowsLocale(locale string) bool {
+	if len(t.Locales) == 0 {
+		return true
+	}
+	base, _, _ := strings.Cut(locale, "-")
+	return slices.Contains(t.Locales, locale) || slices.Contains(t.Locales, base)
+}
+
+// CreatedDeliveryToken is a new token together with its secret, which is
+// only ever returned once
+type CreatedDeliveryToken struct {
+	DeliveryToken
+	Token string `json:"token"`
+}
+
+// CreateDeliveryTokenRequest represents the request to create a delivery token
+type CreateDeliveryTokenRequest struct {
+	Name         string     `json:"name"`
+	ContentTypes []string   `json:"content_types,omitempty"`
+	Locales      []string   `json:"locales,omitempty"`
+	Environments []string   `json:"environments,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// UpdateDeliveryTokenRequest represents the request to update a delivery
+// token. Scopes given replace the stored ones; an empty list allows all.
+type UpdateDeliveryTokenRequest struct {
+	Name         *string    `json:"name,omitempty"`
+	ContentTypes *[]string  `json:"content_types,omitempty"`
+	Locales      *[]string  `json:"locales,omitempty"`
+	Environments *[]string  `json:"environments,omitempty"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// DeliveryTokenFilter represents filter options for delivery tokens
+type DeliveryTokenFilter struct {
+	IsActive *bool
+	Search   string
+	PaginationParams
+}
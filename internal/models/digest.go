@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest frequencies; editors without a preference receive no digest
+const (
+	DigestFrequencyOff    = "off"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+// Digest sections an editor can choose from; no sections means all of them
+const (
+	DigestSectionPublished = "published"
+	DigestSectionDrafts    = "drafts"
+	DigestSectionTopViewed = "top_viewed"
+	DigestSectionContacts  = "contacts"
+)
+
+// DigestPreference is a user's editor digest subscription
+type DigestPreference struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Frequency  string     `json:"frequency"`
+	Sections   []string   `json:"sections"`
+	Locale     *string    `json:"locale,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateDigestPreferenceRequest represents the request to change a user's
+// digest subscription
+type UpdateDigestPreferenceRequest struct {
+	Frequency string   `json:"frequency"`
+	Sections  []string `json:"sections,omitempty"`
+	Locale    *string  `json:"locale,omitempty"`
+}
+
+// DigestRecipient is a subscribed editor whose digest is due
+type DigestRecipient struct {
+	DigestPreference
+	Email    string
+	FullName string
+}
+
+// DigestPost is a post listed in a digest
+type DigestPost struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Views       int64      `json:"views,omitempty"`
+}
+
+// DigestSummary is the editorial activity between From and To. Drafts are
+// all drafts still waiting to be published, not only those of the period.
+type DigestSummary struct {
+	From           time.Time    `json:"from"`
+	To             time.Time    `json:"to"`
+	PublishedCount int64        `json:"published_count"`
+	Published      []DigestPost `json:"published"`
+	DraftCount     int64        `json:"draft_count"`
+	Drafts         []DigestPost `json:"drafts"`
+	TopViewed      []DigestPost `json:"top_viewed"`
+	NewContacts    int64        `json:"new_contacts"`
+}
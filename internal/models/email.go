@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailStatus represents the delivery status of a queued email
+type EmailStatus int16
+
+const (
+	EmailStatusPending EmailStatus = 1
+	EmailStatusSending EmailStatus = 2
+	EmailStatusSent    EmailStatus = 3
+	EmailStatusFailed  EmailStatus = 4
+)
+
+func (s EmailStatus) String() string {
+	switch s {
+	case EmailStatusPending:
+		return "pending"
+	case EmailStatusSending:
+		return "sending"
+	case EmailStatusSent:
+		return "sent"
+	case EmailStatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Email represents a message in the outbound email queue
+type Email struct {
+	ID            uuid.UUID   `json:"id"`
+	ToAddress     string      `json:"to_address"`
+	TemplateName  *string     `json:"template_name,omitempty"`
+	Locale        *string     `json:"locale,omitempty"`
+	Subject       string      `json:"subject"`
+	BodyHTML      *string     `json:"body_html,omitempty"`
+	BodyText      *string     `json:"body_text,omitempty"`
+	Status        EmailStatus `json:"status"`
+	Attempts      int         `json:"attempts"`
+	LastError     *string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+	SentAt        *time.Time  `json:"sent_at,omitempty"`
+	OpenedAt      *time.Time  `json:"opened_at,omitempty"`
+	CampaignID    *uuid.UUID  `json:"campaign_id,omitempty"`
+	RequestID     *string     `json:"request_id,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// EmailTemplate represents a named, localized email template using Go template syntax
+type EmailTemplate struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject"`
+	BodyHTML  *string   `json:"body_html,omitempty"`
+	BodyText  *string   `json:"body_text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateEmailTemplateRequest represents the request to create an email template
+type CreateEmailTemplateRequest struct {
+	Name     string  `json:"name"`
+	Locale   string  `json:"locale"`
+	Subject  string  `json:"subject"`
+	BodyHTML *string `json:"body_html,omitempty"`
+	BodyText *string `json:"body_text,omitempty"`
+}
+
+// UpdateEmailTemplateRequest represents the request to update an email template
+type UpdateEmailTemplateRequest struct {
+	Subject  *string `json:"subject,omitempty"`
+	BodyHTML *string `json:"body_html,omitempty"`
+	BodyText *string `json:"body_text,omitempty"`
+}
+
+// SendEmailRequest represents the request to queue an email from a template
+type SendEmailRequest struct {
+	To       string          `json:"to"`
+	Template string          `json:"template"`
+	Locale   string          `json:"locale,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+// EmailFilter represents filter options for queued emails
+type EmailFilter struct {
+	Status *EmailStatus
+	To     string
+	PaginationParams
+}
+
+// EmailTemplateFilter represents filter options for email templates
+type EmailTemplateFilter struct {
+	Name   string
+	Locale string
+	PaginationParams
+}
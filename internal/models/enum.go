@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// enum is an integer enum starting at 1 whose String reports "unknown" past
+// the last value
+type enum interface {
+	~int16
+	String() string
+}
+
+// ParseEnum reads an enum from its name ("published") or, for older
+// clients, its number ("2"). Numbers are not range checked.
+func ParseEnum[T enum](s string) (T, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 16); err == nil {
+		return T(n), true
+	}
+	s = strings.ToLower(s)
+	for i := T(1); i < 64; i++ {
+		name := i.String()
+		if name == "unknown" {
+			break
+		}
+		if name == s {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// marshalEnum writes an enum by name, or by number when it has none
+func marshalEnum[T enum](v T) ([]byte, error) {
+	if name := v.String(); name != "unknown" {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int16(v))
+}
+
+// unmarshalEnum accepts a name or a number
+func unmarshalEnum[T enum](data []byte, v *T, what string) error {
+	var n int16
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = T(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%s must be a string or number", what)
+	}
+	parsed, ok := ParseEnum[T](s)
+	if !ok {
+		return fmt.Errorf("unknown %s %q", what, s)
+	}
+	*v = parsed
+	return nil
+}
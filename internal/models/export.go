@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Interaction event types
+const (
+	InteractionView     = "view"
+	InteractionPollVote = "poll_vote"
+)
+
+// Interaction is one row of the interaction export. Views are daily rollups
+// per post (Count views on the day starting at At); poll votes are single
+// events by an anonymized User.
+type Interaction struct {
+	Event    string     `json:"event"`
+	PostID   *uuid.UUID `json:"post_id,omitempty"`
+	PollID   *uuid.UUID `json:"poll_id,omitempty"`
+	OptionID *uuid.UUID `json:"option_id,omitempty"`
+	User     string     `json:"user,omitempty"`
+	Count    int        `json:"count"`
+	At       time.Time  `json:"at"`
+}
+
+// InteractionFilter represents filter options for the interaction export
+type InteractionFilter struct {
+	Events []string
+	From   *time.Time
+	To     *time.Time
+}
+
+// ContentFeatures describes a published post for recommender training
+type ContentFeatures struct {
+	PostID      uuid.UUID  `json:"post_id"`
+	ContentType string     `json:"content_type"`
+	Tags        []string   `json:"tags"`
+	TitleLength int        `json:"title_length"`
+	WordCount   int        `json:"word_count"`
+	CharCount   int        `json:"char_count"`
+	MediaCount  int        `json:"media_count"`
+	ViewCount   int        `json:"view_count"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OutlineEntry is one row of the content outline: a content type and,
+// unless the type has no posts, one of its posts with the post's group
+// (nil when ungrouped or the post lacks the grouping field). Entries come
+// ordered by type, group and title.
+type OutlineEntry struct {
+	ContentTypeID   uuid.UUID
+	ContentTypeName string
+	ContentTypeSlug string
+	Group           *string
+	PostTitle       *string
+	PostSlug        *string
+	PostStatus      *PostStatus
+}
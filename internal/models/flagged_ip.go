@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// IPFlagStatus represents how requests from a flagged address are treated
+type IPFlagStatus int16
+
+const (
+	IPFlagChallenge IPFlagStatus = 1 // requests must carry a solved CAPTCHA
+	IPFlagBanned    IPFlagStatus = 2 // requests are rejected with 403
+	IPFlagAllowed   IPFlagStatus = 3 // never challenged, banned or tracked
+)
+
+func (s IPFlagStatus) String() string {
+	switch s {
+	case IPFlagChallenge:
+		return "challenge"
+	case IPFlagBanned:
+		return "banned"
+	case IPFlagAllowed:
+		return "allowed"
+	default:
+		return "unknown"
+	}
+}
+
+// Valid reports whether s is a known status
+func (s IPFlagStatus) Valid() bool {
+	return s >= IPFlagChallenge && s <= IPFlagAllowed
+}
+
+// FlaggedIP is an address flagged by the anomaly detector or set by an
+// admin. Manual entries are never changed by the detector; FlagCount counts
+// how often the detector escalated the address.
+type FlaggedIP struct {
+	IP        string       `json:"ip"`
+	Status    IPFlagStatus `json:"status"`
+	Manual    bool         `json:"manual"`
+	Reason    *string      `json:"reason,omitempty"`
+	FlagCount int          `json:"flag_count"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Active reports whether the flag still applies at now
+func (f *FlaggedIP) Active(now time.Time) bool {
+	return f.ExpiresAt == nil || now.Before(*f.ExpiresAt)
+}
+
+// SetFlaggedIPRequest represents a manual override of an address. A nil
+// ExpiresAt never expires.
+type SetFlaggedIPRequest struct {
+	Status    IPFlagStatus `json:"status"`
+	Reason    *string      `json:"reason,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+// FlaggedIPFilter represents filter options for flagged addresses
+type FlaggedIPFilter struct {
+	Status *IPFlagStatus
+	Manual *bool
+	Active *bool
+	Search string
+	PaginationParams
+}
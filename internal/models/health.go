@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// DatabaseHostState is the database server the connection pool uses, as
+// last checked. Host is the server's address, which tells the hosts of a
+// multi-host database URL apart.
+type DatabaseHostState struct {
+	Host        string     `json:"host,omitempty"`
+	InRecovery  bool       `json:"in_recovery"`
+	Reconnects  int        `json:"reconnects"`
+	LastCheckAt *time.Time `json:"last_check_at,omitempty"`
+	LastError   *string    `json:"last_error,omitempty"`
+}
+
+// Readiness is the readiness check result
+type Readiness struct {
+	Status   string             `json:"status"`
+	Database *DatabaseHostState `json:"database"`
+}
+
+// MigrationStatus reports the schema version of the database against the
+// migrations embedded in the binary. Version is the latest applied
+// migration (0 for none) and Pending lists the versions not applied yet.
+type MigrationStatus struct {
+	Status  string             `json:"status"`
+	Version int                `json:"version"`
+	Latest  int                `json:"latest"`
+	Pending []int              `json:"pending"`
+	Applied []AppliedMigration `json:"applied"`
+}
+
+// AppliedMigration is a migration recorded in schema_migrations
+type AppliedMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
@@ -29,6 +29,16 @@ func (t FileType) String() string {
 	}
 }
 
+// MarshalJSON writes the name of a file type
+func (t FileType) MarshalJSON() ([]byte, error) {
+	return marshalEnum(t)
+}
+
+// UnmarshalJSON accepts the name or the number of a file type
+func (t *FileType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t, "file type")
+}
+
 // MediaRole represents the role of media in a post
 type MediaRole int16
 
@@ -51,6 +61,16 @@ func (r MediaRole) String() string {
 	}
 }
 
+// MarshalJSON writes the name of a media role
+func (r MediaRole) MarshalJSON() ([]byte, error) {
+	return marshalEnum(r)
+}
+
+// UnmarshalJSON accepts the name or the number of a media role
+func (r *MediaRole) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, r, "media role")
+}
+
 // Media represents a media file
 type Media struct {
 	ID         uuid.UUID       `json:"id"`
@@ -65,6 +85,7 @@ type Media struct {
 	Variants   json.RawMessage `json:"variants,omitempty"`
 	AltText    *string         `json:"alt_text,omitempty"`
 	Checksum   *string         `json:"checksum,omitempty"`
+	TeamID     *uuid.UUID      `json:"team_id,omitempty"`
 	CreatedAt  time.Time       `json:"created_at"`
 }
 
@@ -92,15 +113,18 @@ type CreateMediaRequest struct {
 	Variants   json.RawMessage `json:"variants,omitempty"`
 	AltText    *string         `json:"alt_text,omitempty"`
 	Checksum   *string         `json:"checksum,omitempty"`
+	TeamID     *uuid.UUID      `json:"team_id,omitempty"`
 }
 
-// UpdateMediaRequest represents the request to update a media record
+// UpdateMediaRequest represents the request to update a media record. A nil
+// UUID team ID makes the media shared again.
 type UpdateMediaRequest struct {
 	FileName   *string          `json:"file_name,omitempty"`
 	CDNUrl     *string          `json:"cdn_url,omitempty"`
 	Dimensions *json.RawMessage `json:"dimensions,omitempty"`
 	Variants   *json.RawMessage `json:"variants,omitempty"`
 	AltText    *string          `json:"alt_text,omitempty"`
+	TeamID     *uuid.UUID       `json:"team_id,omitempty"`
 }
 
 // AttachMediaRequest represents the request to attach media to a post
@@ -110,9 +134,24 @@ type AttachMediaRequest struct {
 	DisplayOrder *int      `json:"display_order,omitempty"`
 }
 
+// SignMediaURLRequest represents the request to sign a media download URL.
+// ExpiresIn is the URL's lifetime in seconds; the configured default when
+// omitted.
+type SignMediaURLRequest struct {
+	ExpiresIn *int `json:"expires_in,omitempty"`
+}
+
+// SignedMediaURL is a download URL that works without credentials until it
+// expires
+type SignedMediaURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // MediaFilter represents filter options for media
 type MediaFilter struct {
 	FileType *FileType
 	Search   string
+	TeamID   *uuid.UUID
 	PaginationParams
 }
@@ -0,0 +1,6 @@
+package models
+
+// TestNotificationRequest represents the request to send a test chat notification
+type TestNotificationRequest struct {
+	Channel string `json:"channel"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// TablePartition is a monthly partition of a partitioned table. Month is
+// the first day of the month it holds, in UTC.
+type TablePartition struct {
+	Name  string
+	Month time.Time
+}
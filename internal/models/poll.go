@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll vote deduplication modes
+const (
+	// PollDedupIP allows one vote per client IP address
+	PollDedupIP = "ip"
+	// PollDedupVisitor allows one vote per visitor (visitor_id or cms_visitor cookie)
+	PollDedupVisitor = "visitor"
+)
+
+// Poll represents a question with options that visitors vote on while open
+type Poll struct {
+	ID         uuid.UUID    `json:"id"`
+	Question   string       `json:"question"`
+	OpensAt    *time.Time   `json:"opens_at,omitempty"`
+	ClosesAt   *time.Time   `json:"closes_at,omitempty"`
+	DedupBy    string       `json:"dedup_by"`
+	Options    []PollOption `json:"options"`
+	TotalVotes int64        `json:"total_votes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// IsOpen reports whether the poll accepts votes at t
+func (p *Poll) IsOpen(t time.Time) bool {
+	return (p.OpensAt == nil || !t.Before(*p.OpensAt)) && (p.ClosesAt == nil || t.Before(*p.ClosesAt))
+}
+
+// PollOption is one answer of a poll with its current vote count
+type PollOption struct {
+	ID       uuid.UUID `json:"id"`
+	Label    string    `json:"label"`
+	Position int       `json:"position"`
+	Votes    int64     `json:"votes"`
+	Share    float64   `json:"share"`
+}
+
+// CreatePollRequest represents the request to create a poll
+type CreatePollRequest struct {
+	Question string     `json:"question"`
+	Options  []string   `json:"options"`
+	OpensAt  *time.Time `json:"opens_at,omitempty"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	DedupBy  string     `json:"dedup_by,omitempty"`
+}
+
+// UpdatePollRequest represents the request to update a poll. Options cannot
+// be changed once created, so existing votes keep their meaning.
+type UpdatePollRequest struct {
+	Question *string    `json:"question,omitempty"`
+	OpensAt  *time.Time `json:"opens_at,omitempty"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	DedupBy  *string    `json:"dedup_by,omitempty"`
+}
+
+// PollVoteRequest represents a public vote
+type PollVoteRequest struct {
+	OptionID uuid.UUID `json:"option_id"`
+}
+
+// PollFilter represents filter options for listing polls
+type PollFilter struct {
+	PaginationParams
+}
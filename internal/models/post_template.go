@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostTemplate is a named starting point for new posts of a content type:
+// a title pattern, the content's block structure and default tags and
+// metadata. Templates with a team belong to that team's space.
+type PostTemplate struct {
+	ID            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Description   *string         `json:"description,omitempty"`
+	ContentTypeID uuid.UUID       `json:"content_type_id"`
+	TeamID        *uuid.UUID      `json:"team_id,omitempty"`
+	AuthorID      uuid.UUID       `json:"-"`
+	TitlePattern  *string         `json:"title_pattern,omitempty"`
+	Excerpt       *string         `json:"excerpt,omitempty"`
+	Content       *string         `json:"content,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	TagIDs        []uuid.UUID     `json:"tag_ids"`
+	IsActive      bool            `json:"is_active"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Title expands the title pattern for a post created at now. Patterns may
+// use {date} (2006-01-02), {year}, {month} (January), {day}, {week} (ISO
+// week number) and {content_type} (the content type's name).
+func (t *PostTemplate) Title(now time.Time, contentType string) string {
+	if t.TitlePattern == nil {
+		return ""
+	}
+	_, week := now.ISOWeek()
+	return strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{year}", strconv.Itoa(now.Year()),
+		"{month}", now.Month().String(),
+		"{day}", strconv.Itoa(now.Day()),
+		"{week}", strconv.Itoa(week),
+		"{content_type}", contentType,
+	).Replace(*t.TitlePattern)
+}
+
+// CreatePostTemplateRequest represents the request to create a template. The
+// author is the authenticated user.
+type CreatePostTemplateRequest struct {
+	Name          string          `json:"name"`
+	Description   *string         `json:"description,omitempty"`
+	ContentTypeID uuid.UUID       `json:"content_type_id"`
+	TeamID        *uuid.UUID      `json:"team_id,omitempty"`
+	AuthorID      uuid.UUID       `json:"-"`
+	TitlePattern  *string         `json:"title_pattern,omitempty"`
+	Excerpt       *string         `json:"excerpt,omitempty"`
+	Content       *string         `json:"content,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	TagIDs        []uuid.UUID     `json:"tag_ids,omitempty"`
+	IsActive      *bool           `json:"is_active,omitempty"`
+}
+
+// UpdatePostTemplateRequest represents the request to update a template. A
+// nil UUID team ID makes the template shared again.
+type UpdatePostTemplateRequest struct {
+	Name         *string          `json:"name,omitempty"`
+	Description  *string          `json:"description,omitempty"`
+	TeamID       *uuid.UUID       `json:"team_id,omitempty"`
+	TitlePattern *string          `json:"title_pattern,omitempty"`
+	Excerpt      *string          `json:"excerpt,omitempty"`
+	Content      *string          `json:"content,omitempty"`
+	Metadata     *json.RawMessage `json:"metadata,omitempty"`
+	TagIDs       *[]uuid.UUID     `json:"tag_ids,omitempty"`
+	IsActive     *bool            `json:"is_active,omitempty"`
+}
+
+// PostTemplateFilter represents filter options for templates
+type PostTemplateFilter struct {
+	ContentTypeID *uuid.UUID
+	TeamID        *uuid.UUID
+	IsActive      *bool
+	Search        string
+	PaginationParams
+}
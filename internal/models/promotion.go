@@ -0,0 +1,9 @@
+package models
+
+import "github.com/google/uuid"
+
+// PromoteRequest represents the request to push posts to the promotion target
+type PromoteRequest struct {
+	Slugs    []string   `json:"slugs"`
+	AuthorID *uuid.UUID `json:"author_id,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "github.com/google/uuid"
+
+// Proofread fields
+const (
+	ProofreadFieldTitle   = "title"
+	ProofreadFieldExcerpt = "excerpt"
+	ProofreadFieldContent = "content"
+)
+
+// ProofreadRequest selects the post fields to check and their language.
+// Fields defaults to title, excerpt and content; an empty Language uses the
+// configured default.
+type ProofreadRequest struct {
+	Fields   []string `json:"fields,omitempty"`
+	Language string   `json:"language,omitempty"`
+}
+
+// PostProofread holds the issues found in each checked field of a post
+type PostProofread struct {
+	PostID uuid.UUID                   `json:"post_id"`
+	Fields map[string]*ProofreadResult `json:"fields"`
+}
+
+// ProofreadResult is the outcome of checking one text
+type ProofreadResult struct {
+	Language string           `json:"language,omitempty"`
+	Issues   []ProofreadIssue `json:"issues"`
+}
+
+// ProofreadIssue is a single spelling, grammar or style problem. Offset and
+// Length are in UTF-16 code units of the stored text, markup included, the
+// way JavaScript indexes strings, so editors can underline the span directly.
+type ProofreadIssue struct {
+	Offset       int      `json:"offset"`
+	Length       int      `json:"length"`
+	Message      string   `json:"message"`
+	ShortMessage string   `json:"short_message,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	RuleID       string   `json:"rule_id,omitempty"`
+	Replacements []string `json:"replacements,omitempty"`
+}
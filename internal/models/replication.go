@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReplicationStatus represents the state of a media object copy
+type ReplicationStatus int16
+
+const (
+	ReplicationPending    ReplicationStatus = 1
+	ReplicationCopying    ReplicationStatus = 2
+	ReplicationReplicated ReplicationStatus = 3
+	ReplicationFailed     ReplicationStatus = 4
+)
+
+func (s ReplicationStatus) String() string {
+	switch s {
+	case ReplicationPending:
+		return "pending"
+	case ReplicationCopying:
+		return "copying"
+	case ReplicationReplicated:
+		return "replicated"
+	case ReplicationFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// MediaReplication is one media object (the file or a variant) queued for
+// copying to the replica storage. BucketName and MimeType come from the
+// media record.
+type MediaReplication struct {
+	MediaID       uuid.UUID         `json:"media_id"`
+	ObjectKey     string            `json:"object_key"`
+	BucketName    string            `json:"bucket_name"`
+	MimeType      string            `json:"mime_type"`
+	Status        ReplicationStatus `json:"status"`
+	Attempts      int               `json:"attempts"`
+	LastError     *string           `json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	ReplicatedAt  *time.Time        `json:"replicated_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// ReplicationStats reports replication progress. Lag is the age of the
+// oldest copy still waiting; replication is healthy while it stays under
+// MaxLagSeconds and nothing has failed for good.
+type ReplicationStats struct {
+	Enabled          bool              `json:"enabled"`
+	Pending          int64             `json:"pending"`
+	Failed           int64             `json:"failed"`
+	Replicated       int64             `json:"replicated"`
+	OldestPendingAt  *time.Time        `json:"oldest_pending_at,omitempty"`
+	LastReplicatedAt *time.Time        `json:"last_replicated_at,omitempty"`
+	LagSeconds       int64             `json:"lag_seconds"`
+	MaxLagSeconds    int64             `json:"max_lag_seconds"`
+	Healthy          bool              `json:"healthy"`
+	CDN              *CDNFailoverState `json:"cdn,omitempty"`
+}
+
+// CDNFailoverState reports whether media URLs are served from the replica
+// CDN because the primary one failed its health check
+type CDNFailoverState struct {
+	PrimaryURL  string     `json:"primary_url"`
+	ReplicaURL  string     `json:"replica_url"`
+	FailedOver  bool       `json:"failed_over"`
+	Since       *time.Time `json:"since,omitempty"`
+	LastCheckAt *time.Time `json:"last_check_at,omitempty"`
+	LastError   *string    `json:"last_error,omitempty"`
+}
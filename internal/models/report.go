@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DuplicatePair is a pair of near-duplicate posts. Similarity estimates the
+// share of word sequences the posts have in common, from 0 to 1.
+type DuplicatePair struct {
+	Post       ReportPost `json:"post"`
+	Duplicate  ReportPost `json:"duplicate"`
+	Similarity float64    `json:"similarity"`
+	DetectedAt time.Time  `json:"detected_at"`
+}
+
+// ReportPost identifies a post in an editorial report, such as one side of
+// a DuplicatePair
+type ReportPost struct {
+	ID            uuid.UUID  `json:"id"`
+	ContentTypeID uuid.UUID  `json:"content_type_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Status        PostStatus `json:"status"`
+}
+
+// DuplicateFilter represents filter options for the duplicates report
+type DuplicateFilter struct {
+	PaginationParams
+	MinSimilarity *float64
+	ContentTypeID *uuid.UUID
+}
+
+// PostText is a post's title and body, as read by the duplicate detector
+type PostText struct {
+	ID      uuid.UUID
+	Title   string
+	Content string
+}
+
+// DanglingReference is a reference from a post to a media item, post or tag
+// that no longer exists. Path locates it in the post's metadata (a JSON path
+// such as metadata.gallery_media_ids[2]) or content (content[data-media-id]).
+type DanglingReference struct {
+	Post         ReportPost `json:"post"`
+	Source       string     `json:"source"`
+	Path         string     `json:"path"`
+	Kind         string     `json:"kind"`
+	ReferencedID uuid.UUID  `json:"referenced_id"`
+	Cleaned      bool       `json:"cleaned"`
+	DetectedAt   time.Time  `json:"detected_at"`
+}
+
+// DanglingReferenceFilter represents filter options for the dangling
+// references report
+type DanglingReferenceFilter struct {
+	PaginationParams
+	Kind          string
+	Source        string
+	Cleaned       *bool
+	ContentTypeID *uuid.UUID
+}
+
+// PostReferences is a post's metadata and body, as read by the reference
+// checker
+type PostReferences struct {
+	ID       uuid.UUID
+	Metadata json.RawMessage
+	Content  string
+}
+
+// ContentReviewPolicy sets after how many days without an edit or an
+// acknowledgment published posts of a content type are due for review
+type ContentReviewPolicy struct {
+	ContentTypeID   uuid.UUID `json:"content_type_id"`
+	ReviewAfterDays int       `json:"review_after_days"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SetReviewPolicyRequest sets a content type's review period
+type SetReviewPolicyRequest struct {
+	ReviewAfterDays int `json:"review_after_days"`
+}
+
+// StalePost is a published post that was neither edited nor acknowledged
+// as still fresh for ReviewAfterDays. ReviewedAt is the later of the two;
+// StaleDays counts the days since.
+type StalePost struct {
+	Post            ReportPost `json:"post"`
+	TeamID          *uuid.UUID `json:"team_id,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	ReviewedAt      time.Time  `json:"reviewed_at"`
+	ReviewAfterDays int        `json:"review_after_days"`
+	StaleDays       int        `json:"stale_days"`
+	RemindedAt      *time.Time `json:"reminded_at,omitempty"`
+}
+
+// StaleContentFilter represents filter options for the stale content
+// report. Days overrides the content types' review periods; DefaultDays
+// applies to content types without a policy.
+type StaleContentFilter struct {
+	PaginationParams
+	ContentTypeID *uuid.UUID
+	TeamID        *uuid.UUID
+	Days          *int
+	DefaultDays   int
+	// Reminded limits posts to those already reminded of (true) or not yet
+	// (false) in their current review period
+	Reminded *bool
+}
+
+// PostReview is the last acknowledgment that a post is still fresh and the
+// last review reminder sent for it
+type PostReview struct {
+	PostID         uuid.UUID  `json:"post_id"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *uuid.UUID `json:"acknowledged_by,omitempty"`
+	Note           *string    `json:"note,omitempty"`
+	RemindedAt     *time.Time `json:"reminded_at,omitempty"`
+}
+
+// AcknowledgeReviewRequest confirms a post is still fresh; the reviewer is
+// the authenticated user
+type AcknowledgeReviewRequest struct {
+	Note *string `json:"note,omitempty"`
+}
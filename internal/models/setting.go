@@ -33,3 +33,51 @@ type SettingFilter struct {
 	Search string
 	PaginationParams
 }
+
+// SettingsDocument moves the settings table, or some groups of it, between
+// deployments. A setting's group is the part of its key before the first dot
+// ("site" for "site.name").
+type SettingsDocument struct {
+	ExportedAt time.Time      `json:"exported_at"`
+	Groups     []string       `json:"groups,omitempty"`
+	Settings   []SettingEntry `json:"settings"`
+}
+
+// SettingEntry is one setting without its instance-specific ID
+type SettingEntry struct {
+	Key         string  `json:"key"`
+	Value       *string `json:"value"`
+	Description *string `json:"description,omitempty"`
+}
+
+// SettingImportAction is what an import does to one setting
+type SettingImportAction string
+
+const (
+	SettingImportCreate    SettingImportAction = "create"
+	SettingImportUpdate    SettingImportAction = "update"
+	SettingImportUnchanged SettingImportAction = "unchanged"
+	// SettingImportDelete removes settings missing from the document, when
+	// the import prunes
+	SettingImportDelete SettingImportAction = "delete"
+	// SettingImportServerOnly settings are missing from the document and kept
+	SettingImportServerOnly SettingImportAction = "server_only"
+	// SettingImportSkipped entries are outside the groups being imported
+	SettingImportSkipped SettingImportAction = "skipped"
+)
+
+// SettingImportChange is the change an import makes to one setting. Changes
+// names the fields that differ (value, description).
+type SettingImportChange struct {
+	Key     string              `json:"key"`
+	Action  SettingImportAction `json:"action"`
+	Changes []string            `json:"changes,omitempty"`
+}
+
+// SettingsImportResult lists the changes of an import, which are only made
+// when it is not a dry run
+type SettingsImportResult struct {
+	DryRun  bool                        `json:"dry_run"`
+	Changes []SettingImportChange       `json:"changes"`
+	Summary map[SettingImportAction]int `json:"summary"`
+}
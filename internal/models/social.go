@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Social share statuses
+const (
+	SocialSharePending = "pending"
+	SocialShareSent    = "sent"
+	SocialShareFailed  = "failed"
+)
+
+// SocialShare records the sharing of a published post to one social
+// account. A pending share whose network call never completed (e.g. the
+// server stopped) is not retried, so a post is never shared twice.
+type SocialShare struct {
+	ID          uuid.UUID  `json:"id"`
+	PostID      *uuid.UUID `json:"post_id,omitempty"`
+	PostTitle   string     `json:"post_title"`
+	Account     string     `json:"account"`
+	Driver      string     `json:"driver"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message"`
+	Link        string     `json:"link"`
+	ImageURL    *string    `json:"image_url,omitempty"`
+	ExternalID  *string    `json:"external_id,omitempty"`
+	ExternalURL *string    `json:"external_url,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SharedAt    *time.Time `json:"shared_at,omitempty"`
+}
+
+// SocialShareFilter represents filter options for the sharing history
+type SocialShareFilter struct {
+	PostID  *uuid.UUID
+	Account string
+	Status  string
+	PaginationParams
+}
+
+// PostSocialSharing is a post's sharing preference and history
+type PostSocialSharing struct {
+	Enabled bool          `json:"enabled"`
+	Shares  []SocialShare `json:"shares"`
+}
+
+// UpdatePostSocialSharingRequest opts a post out of (or back into)
+// automatic sharing
+type UpdatePostSocialSharingRequest struct {
+	Enabled *bool `json:"enabled"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriberStatus represents whether a subscriber receives campaigns
+type SubscriberStatus int16
+
+const (
+	SubscriberStatusSubscribed   SubscriberStatus = 1
+	SubscriberStatusUnsubscribed SubscriberStatus = 2
+)
+
+func (s SubscriberStatus) String() string {
+	switch s {
+	case SubscriberStatusSubscribed:
+		return "subscribed"
+	case SubscriberStatusUnsubscribed:
+		return "unsubscribed"
+	default:
+		return "unknown"
+	}
+}
+
+// Subscriber is a recipient of email campaigns
+type Subscriber struct {
+	ID               uuid.UUID        `json:"id"`
+	Email            string           `json:"email"`
+	Name             *string          `json:"name,omitempty"`
+	Locale           string           `json:"locale"`
+	Status           SubscriberStatus `json:"status"`
+	UnsubscribeToken string           `json:"-"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UnsubscribedAt   *time.Time       `json:"unsubscribed_at,omitempty"`
+}
+
+// CreateSubscriberRequest represents the request to add a subscriber
+type CreateSubscriberRequest struct {
+	Email  string  `json:"email"`
+	Name   *string `json:"name,omitempty"`
+	Locale string  `json:"locale,omitempty"`
+}
+
+// SubscriberFilter represents filter options for subscribers
+type SubscriberFilter struct {
+	Status *SubscriberStatus
+	Search string
+	PaginationParams
+}
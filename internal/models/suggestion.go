@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Suggestion types
+const (
+	SuggestionPost   = "post"
+	SuggestionTag    = "tag"
+	SuggestionAuthor = "author"
+)
+
+// SuggestionTypes lists every suggestion type, in the order results of
+// equal score are returned
+var SuggestionTypes = []string{SuggestionPost, SuggestionTag, SuggestionAuthor}
+
+// Suggestion is an autocomplete candidate for an internal link or mention
+// in the editor. Score (0-1) combines how well the label matches the query
+// with how recently the item was active.
+type Suggestion struct {
+	Type  string    `json:"type"`
+	ID    uuid.UUID `json:"id"`
+	Label string    `json:"label"`
+	// Detail is the content type of a post, the slug of a tag or the
+	// email of an author
+	Detail string `json:"detail"`
+	// Insert is the text the editor inserts: a post's site path, a tag's
+	// slug or an author's @mention
+	Insert   string      `json:"insert"`
+	Status   *PostStatus `json:"status,omitempty"`
+	ActiveAt *time.Time  `json:"active_at,omitempty"`
+	Score    float64     `json:"score"`
+}
+
+// SuggestionFilter selects suggestion candidates
+type SuggestionFilter struct {
+	Query string
+	Types []string
+	Limit int
+}
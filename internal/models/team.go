@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamRole is a member's role within a team
+type TeamRole int16
+
+const (
+	TeamRoleViewer  TeamRole = 1
+	TeamRoleEditor  TeamRole = 2
+	TeamRoleManager TeamRole = 3
+)
+
+func (r TeamRole) String() string {
+	switch r {
+	case TeamRoleViewer:
+		return "viewer"
+	case TeamRoleEditor:
+		return "editor"
+	case TeamRoleManager:
+		return "manager"
+	default:
+		return "unknown"
+	}
+}
+
+// Valid reports whether r is a known team role
+func (r TeamRole) Valid() bool {
+	return r >= TeamRoleViewer && r <= TeamRoleManager
+}
+
+// Team is a group of users owning a content space. Posts and media with a
+// team ID belong to that team's space; those without one are shared.
+type Team struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description *string   `json:"description,omitempty"`
+	MemberCount int64     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TeamMember is a user's membership of a team
+type TeamMember struct {
+	TeamID    uuid.UUID     `json:"team_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Role      TeamRole      `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      *UserResponse `json:"user,omitempty"`
+}
+
+// CreateTeamRequest represents the request to create a team
+type CreateTeamRequest struct {
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateTeamRequest represents the request to update a team
+type UpdateTeamRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Slug        *string `json:"slug,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// SetTeamMemberRequest adds a user to a team or changes their role
+type SetTeamMemberRequest struct {
+	Role TeamRole `json:"role"`
+}
+
+// TeamFilter represents filter options for teams
+type TeamFilter struct {
+	Search string
+	// MemberID lists only the teams this user belongs to
+	MemberID *uuid.UUID
+	PaginationParams
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Theme is one version of a stored site theme. Versions are edited while
+// inactive; the active version is frozen until another one replaces it.
+type Theme struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Version     int         `json:"version"`
+	Description *string     `json:"description,omitempty"`
+	IsActive    bool        `json:"is_active"`
+	ActivatedAt *time.Time  `json:"activated_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Files       []ThemeFile `json:"files,omitempty"`
+}
+
+// ThemeFile is a template or static asset belonging to a theme version. Its
+// content is kept in storage under its checksum and only loaded for
+// parsing and downloads.
+type ThemeFile struct {
+	ThemeID     uuid.UUID `json:"-"`
+	Path        string    `json:"path"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	Checksum    string    `json:"checksum"`
+	Content     []byte    `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateThemeRequest creates the next version of a theme, optionally
+// starting from the files of an existing version
+type CreateThemeRequest struct {
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	BaseID      *uuid.UUID `json:"base_id,omitempty"`
+}
+
+// ThemeFilter represents filter options for themes
+type ThemeFilter struct {
+	Name string
+	PaginationParams
+}
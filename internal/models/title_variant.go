@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TitleVariant represents an alternative headline for a post under A/B test
+type TitleVariant struct {
+	ID          uuid.UUID `json:"id"`
+	PostID      uuid.UUID `json:"post_id"`
+	Title       string    `json:"title"`
+	Impressions int64     `json:"impressions"`
+	Clicks      int64     `json:"clicks"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateTitleVariantRequest represents the request to add a title variant
+type CreateTitleVariantRequest struct {
+	Title string `json:"title"`
+}
+
+// TitleVariantAssignment is the variant served to a visitor
+type TitleVariantAssignment struct {
+	PostID    uuid.UUID  `json:"post_id"`
+	VariantID *uuid.UUID `json:"variant_id,omitempty"`
+	Title     string     `json:"title"`
+}
+
+// TitleVariantResult represents conversion statistics for a title variant
+type TitleVariantResult struct {
+	TitleVariant
+	ClickRate float64 `json:"click_rate"`
+	// Lift and ZScore are relative to the first (control) variant
+	Lift   float64 `json:"lift"`
+	ZScore float64 `json:"z_score"`
+	Winner bool    `json:"winner"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Translation statuses. A translation is saved in progress or complete;
+// it is reported missing when it doesn't exist and needs_update when the
+// post's title, excerpt or content changed since it was last saved.
+const (
+	TranslationMissing     = "missing"
+	TranslationInProgress  = "in_progress"
+	TranslationComplete    = "complete"
+	TranslationNeedsUpdate = "needs_update"
+)
+
+// ValidTranslationStatus reports whether status can be saved on a
+// translation
+func ValidTranslationStatus(status string) bool {
+	return status == TranslationInProgress || status == TranslationComplete
+}
+
+// PostTranslation is a post's title, excerpt and content in one locale.
+// SourceHash identifies the version of the post it was translated from;
+// MachineTranslated marks a translation prefilled by the AI provider and
+// not yet saved by an editor.
+type PostTranslation struct {
+	PostID            uuid.UUID `json:"post_id"`
+	Locale            string    `json:"locale"`
+	Title             string    `json:"title"`
+	Excerpt           *string   `json:"excerpt,omitempty"`
+	Content           *string   `json:"content,omitempty"`
+	Status            string    `json:"status"`
+	SourceHash        string    `json:"-"`
+	MachineTranslated bool      `json:"machine_translated"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SaveTranslationRequest creates or replaces a post's translation. Status
+// defaults to in_progress.
+type SaveTranslationRequest struct {
+	Title   string  `json:"title"`
+	Excerpt *string `json:"excerpt"`
+	Content *string `json:"content"`
+	Status  string  `json:"status"`
+}
+
+// TranslationState is the status of one locale of a post
+type TranslationState struct {
+	Locale            string     `json:"locale"`
+	Status            string     `json:"status"`
+	Title             *string    `json:"title,omitempty"`
+	MachineTranslated bool       `json:"machine_translated"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// PostTranslations reports a post's translations into every supported
+// locale other than its source locale, plus any other locale it has a
+// translation for
+type PostTranslations struct {
+	PostID       uuid.UUID          `json:"post_id"`
+	SourceLocale string             `json:"source_locale,omitempty"`
+	Translations []TranslationState `json:"translations"`
+}
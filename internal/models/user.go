@@ -15,6 +15,29 @@ const (
 	RoleAdmin  Role = 3
 )
 
+func (r Role) String() string {
+	switch r {
+	case RoleUser:
+		return "user"
+	case RoleEditor:
+		return "editor"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON writes the name of a role
+func (r Role) MarshalJSON() ([]byte, error) {
+	return marshalEnum(r)
+}
+
+// UnmarshalJSON accepts the name or the number of a role
+func (r *Role) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, r, "role")
+}
+
 // User represents a user in the system
 type User struct {
 	ID           uuid.UUID  `json:"id"`
@@ -28,13 +51,43 @@ type User struct {
 	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
-// Session represents a user session
+// Session represents a user session. Token is the hash of its current
+// refresh token and is never exposed. RefreshedAt is the last login or
+// refresh; Current marks the session of the access token a list was
+// requested with.
 type Session struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Token       string    `json:"-"`
+	UserAgent   *string   `json:"user_agent,omitempty"`
+	IPAddress   *string   `json:"ip_address,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	Current     bool      `json:"current"`
+}
+
+// LoginRequest represents the request to log in with a password
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest represents the request to exchange a refresh token for a
+// new token pair
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginResponse carries the tokens of a session: a short-lived bearer
+// access token and the single-use refresh token that renews it
+type LoginResponse struct {
+	AccessToken      string        `json:"access_token"`
+	TokenType        string        `json:"token_type"`
+	ExpiresAt        time.Time     `json:"expires_at"`
+	RefreshToken     string        `json:"refresh_token"`
+	RefreshExpiresAt time.Time     `json:"refresh_expires_at"`
+	User             *UserResponse `json:"user"`
 }
 
 // UserResponse is the safe user representation for API responses
@@ -61,3 +114,23 @@ func (u *User) ToResponse() *UserResponse {
 		UpdatedAt: u.UpdatedAt,
 	}
 }
+
+// CreateUserRequest represents the request to create a user. Role defaults
+// to user and IsActive to true.
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	FullName string `json:"full_name"`
+	Role     *Role  `json:"role,omitempty"`
+	IsActive *bool  `json:"is_active,omitempty"`
+}
+
+// UpdateUserRequest represents the request to update a user. A new
+// password ends the user's sessions.
+type UpdateUserRequest struct {
+	Email    *string `json:"email,omitempty"`
+	Password *string `json:"password,omitempty"`
+	FullName *string `json:"full_name,omitempty"`
+	Role     *Role   `json:"role,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
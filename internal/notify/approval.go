@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// Approval describes an approval request event. Pending requests name the
+// step awaiting a decision and the role it waits on.
+func Approval(event Event, title string, a *models.ApprovalRequest) Notification {
+	n := Notification{
+		Event: event,
+		Title: title,
+		Fields: []Field{
+			{Label: "Post", Value: a.PostTitle},
+			{Label: "Post ID", Value: a.PostID.String()},
+			{Label: "Request ID", Value: a.ID.String()},
+		},
+	}
+	if step := a.CurrentStep; step != nil {
+		n.Fields = append(n.Fields,
+			Field{Label: "Step", Value: strconv.Itoa(a.Step+1) + "/" + strconv.Itoa(len(a.Steps)) + " " + step.Name},
+			Field{Label: "Role", Value: step.Role},
+		)
+		if a.EscalatedAt != nil {
+			n.Fields = append(n.Fields, Field{Label: "Escalated to", Value: step.EscalateTo})
+		}
+		if a.StepDueAt != nil {
+			n.Fields = append(n.Fields, Field{Label: "Due", Value: a.StepDueAt.UTC().Format(time.RFC3339)})
+		}
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Driver delivers a notification to a single chat channel
+type Driver interface {
+	Send(ctx context.Context, n *Notification) error
+}
+
+// Supported channel drivers
+const (
+	DriverSlack    = "slack"
+	DriverDiscord  = "discord"
+	DriverTelegram = "telegram"
+)
+
+func newDriver(ch Channel, client *http.Client) (Driver, error) {
+	switch ch.Driver {
+	case DriverSlack:
+		if ch.WebhookURL == "" {
+			return nil, fmt.Errorf("channel %q: webhook_url is required", ch.Name)
+		}
+		return &slackDriver{url: ch.WebhookURL, client: client}, nil
+	case DriverDiscord:
+		if ch.WebhookURL == "" {
+			return nil, fmt.Errorf("channel %q: webhook_url is required", ch.Name)
+		}
+		return &discordDriver{url: ch.WebhookURL, client: client}, nil
+	case DriverTelegram:
+		if ch.BotToken == "" || ch.ChatID == "" {
+			return nil, fmt.Errorf("channel %q: bot_token and chat_id are required", ch.Name)
+		}
+		return &telegramDriver{token: ch.BotToken, chatID: ch.ChatID, client: client}, nil
+	default:
+		return nil, fmt.Errorf("channel %q: unknown driver %q", ch.Name, ch.Driver)
+	}
+}
+
+// slackDriver posts to a Slack incoming webhook
+type slackDriver struct {
+	url    string
+	client *http.Client
+}
+
+func (d *slackDriver) Send(ctx context.Context, n *Notification) error {
+	return postJSON(ctx, d.client, d.url, map[string]string{"text": n.plainText("*")})
+}
+
+// discordDriver posts to a Discord channel webhook
+type discordDriver struct {
+	url    string
+	client *http.Client
+}
+
+func (d *discordDriver) Send(ctx context.Context, n *Notification) error {
+	// Discord rejects content longer than 2000 characters
+	content := n.plainText("**")
+	if len(content) > 2000 {
+		content = content[:1997] + "..."
+	}
+	return postJSON(ctx, d.client, d.url, map[string]string{"content": content})
+}
+
+// telegramDriver sends a message through the Telegram Bot API
+type telegramDriver struct {
+	token  string
+	chatID string
+	client *http.Client
+}
+
+func (d *telegramDriver) Send(ctx context.Context, n *Notification) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", d.token)
+	return postJSON(ctx, d.client, url, map[string]interface{}{
+		"chat_id":                  d.chatID,
+		"text":                     n.plainText(""),
+		"disable_web_page_preview": true,
+	})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	reqctx.Propagate(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification rejected with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// Subscribe sends chat notifications for the domain events that have one
+func (n *Notifier) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e events.ContactReceived) {
+		n.Notify(ctx, contactNotification(e.Contact))
+	})
+}
+
+// contactNotification summarises a new submission for team chat
+func contactNotification(c *models.ContactSubmission) Notification {
+	n := Notification{
+		Event: EventContactCreated,
+		Title: "New contact submission",
+		Text:  c.Message,
+		Fields: []Field{
+			{Label: "Name", Value: c.Name},
+			{Label: "Email", Value: c.Email},
+		},
+	}
+	if c.Subject != nil {
+		n.Fields = append(n.Fields, Field{Label: "Subject", Value: *c.Subject})
+	}
+	return n
+}
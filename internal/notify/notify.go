@@ -0,0 +1,263 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// Setting keys holding the channel definitions and routing rules
+const (
+	SettingChannels = "notifications.channels"
+	SettingRoutes   = "notifications.routes"
+)
+
+// Event identifies what happened; routing rules map events to channels
+type Event string
+
+const (
+	EventContactCreated      Event = "contact.created"
+	EventPostPublishFailed   Event = "post.publish_failed"
+	EventEmailDeliveryFailed Event = "email.delivery_failed"
+	EventServerPanic         Event = "server.panic"
+	EventApprovalRequested   Event = "approval.requested"
+	EventApprovalApproved    Event = "approval.approved"
+	EventApprovalRejected    Event = "approval.rejected"
+	EventApprovalEscalated   Event = "approval.escalated"
+	EventContentReviewDue    Event = "content.review_due"
+	EventTest                Event = "test"
+)
+
+// routeWildcard matches every event in the routing rules
+const routeWildcard = "*"
+
+// ErrChannelNotFound is returned when a named channel is not configured
+var ErrChannelNotFound = errors.New("notification channel not found")
+
+// Field is a labelled value shown under the notification title
+type Field struct {
+	Label string
+	Value string
+}
+
+// Notification is a chat message about a single event
+type Notification struct {
+	Event  Event
+	Title  string
+	Text   string
+	Fields []Field
+	URL    string
+}
+
+// plainText formats the notification using bold as the emphasis marker
+// for the title and field labels
+func (n *Notification) plainText(bold string) string {
+	var b strings.Builder
+	b.WriteString(bold + n.Title + bold)
+	if n.Text != "" {
+		b.WriteString("\n" + n.Text)
+	}
+	for _, f := range n.Fields {
+		if f.Value == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s%s:%s %s", bold, f.Label, bold, f.Value)
+	}
+	if n.URL != "" {
+		b.WriteString("\n" + n.URL)
+	}
+	return b.String()
+}
+
+// Channel is a configured chat destination. Secrets are write-only and are
+// omitted when channels are listed.
+type Channel struct {
+	Name       string `json:"name"`
+	Driver     string `json:"driver"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+	BotToken   string `json:"bot_token,omitempty"`
+	ChatID     string `json:"chat_id,omitempty"`
+}
+
+// Config is the channel and routing configuration loaded from settings
+type Config struct {
+	Channels []Channel           `json:"channels"`
+	Routes   map[string][]string `json:"routes"`
+}
+
+// Notifier routes events to the chat channels configured in settings
+type Notifier struct {
+	settings *repository.SettingRepository
+	client   *http.Client
+}
+
+func New(settings *repository.SettingRepository) *Notifier {
+	return &Notifier{
+		settings: settings,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadConfig reads channels and routes from settings. Missing settings yield
+// an empty configuration.
+func (n *Notifier) LoadConfig(ctx context.Context) (*Config, error) {
+	values, err := n.settings.GetMultiple(ctx, []string{SettingChannels, SettingRoutes})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Routes: map[string][]string{}}
+	if raw := values[SettingChannels]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.Channels); err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", SettingChannels, err)
+		}
+	}
+	if raw := values[SettingRoutes]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.Routes); err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", SettingRoutes, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Notify delivers n in the background to every channel routed for its event.
+// Failures are logged; callers never wait on chat services. ctx only
+// supplies the request ID for correlation, its cancellation is ignored.
+func (n *Notifier) Notify(ctx context.Context, notification Notification) {
+	go func() {
+		ctx, cancel := context.WithTimeout(reqctx.Detach(ctx), 30*time.Second)
+		defer cancel()
+
+		cfg, err := n.LoadConfig(ctx)
+		if err != nil {
+			reqctx.Logf(ctx, "[ERROR] Failed to load notification settings: %v", err)
+			return
+		}
+
+		for _, ch := range cfg.channelsFor(notification.Event) {
+			if err := n.send(ctx, ch, &notification); err != nil {
+				reqctx.Logf(ctx, "[ERROR] Notification %s to channel %q failed: %v", notification.Event, ch.Name, err)
+			}
+		}
+	}()
+}
+
+// SendTest synchronously sends a test message to the named channel
+func (n *Notifier) SendTest(ctx context.Context, name string) error {
+	cfg, err := n.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range cfg.Channels {
+		if ch.Name == name {
+			return n.send(ctx, ch, &Notification{
+				Event: EventTest,
+				Title: "Test notification",
+				Text:  fmt.Sprintf("Channel %q is configured correctly.", name),
+			})
+		}
+	}
+
+	return ErrChannelNotFound
+}
+
+func (n *Notifier) send(ctx context.Context, ch Channel, notification *Notification) error {
+	driver, err := newDriver(ch, n.client)
+	if err != nil {
+		return err
+	}
+	return driver.Send(ctx, notification)
+}
+
+// channelsFor returns the channels routed for event, including wildcard
+// routes, without duplicates
+func (c *Config) channelsFor(event Event) []Channel {
+	names := append(append([]string{}, c.Routes[string(event)]...), c.Routes[routeWildcard]...)
+
+	var channels []Channel
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		for _, ch := range c.Channels {
+			if ch.Name == name {
+				channels = append(channels, ch)
+				break
+			}
+		}
+	}
+
+	return channels
+}
+
+// masked replaces webhook URLs and bot tokens in what the API returns
+const masked = "********"
+
+// Redacted returns the configuration with webhook URLs and bot tokens masked
+func (c *Config) Redacted() *Config {
+	out := &Config{Routes: c.Routes, Channels: make([]Channel, len(c.Channels))}
+	for i, ch := range c.Channels {
+		if ch.WebhookURL != "" {
+			ch.WebhookURL = masked
+		}
+		if ch.BotToken != "" {
+			ch.BotToken = masked
+		}
+		out.Channels[i] = ch
+	}
+	return out
+}
+
+// MaskChannels masks the webhook URLs and bot tokens in a
+// notifications.channels setting value. A value that isn't a list of
+// channels is masked whole.
+func MaskChannels(value string) string {
+	var channels []Channel
+	if err := json.Unmarshal([]byte(value), &channels); err != nil {
+		return masked
+	}
+	b, _ := json.Marshal((&Config{Channels: channels}).Redacted().Channels)
+	return string(b)
+}
+
+// UnmaskChannels puts back the secrets that MaskChannels masked in a
+// notifications.channels value being written, taking them from the stored
+// channels of the same name, so a value read from the API can be saved
+// unchanged
+func UnmaskChannels(value, stored string) string {
+	var channels, current []Channel
+	if json.Unmarshal([]byte(value), &channels) != nil || json.Unmarshal([]byte(stored), &current) != nil {
+		return value
+	}
+	byName := make(map[string]Channel, len(current))
+	for _, ch := range current {
+		byName[ch.Name] = ch
+	}
+	unmasked := false
+	for i, ch := range channels {
+		if ch.WebhookURL == masked {
+			channels[i].WebhookURL = byName[ch.Name].WebhookURL
+			unmasked = true
+		}
+		if ch.BotToken == masked {
+			channels[i].BotToken = byName[ch.Name].BotToken
+			unmasked = true
+		}
+	}
+	if !unmasked {
+		return value
+	}
+	b, _ := json.Marshal(channels)
+	return string(b)
+}
@@ -0,0 +1,108 @@
+// Package permalink builds the public URLs of posts from settings: the
+// site's base URL, the content types' slug patterns and locale prefixes.
+// Structured data, calendar feeds, emails and social shares all link posts
+// through it, so they agree with each other and with the public site.
+package permalink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// Setting keys holding the public site's base URL and its locales
+const (
+	SettingBaseURL = "site.url"
+	SettingLocales = "site.locales"
+)
+
+// Config is the URL configuration loaded from settings
+type Config struct {
+	BaseURL string `json:"base_url"`
+	// Locales are the site's locales, default first. The default locale is
+	// served without a prefix, the others under /{locale}/.
+	Locales  []string `json:"locales,omitempty"`
+	pageType string
+}
+
+// Builder loads the URL configuration from settings
+type Builder struct {
+	settings  *repository.SettingRepository
+	publicURL string
+	pageType  string
+}
+
+// New returns a builder falling back to publicURL when the site.url setting
+// is empty. Posts of pageType live at the root of the site rather than
+// under their content type's slug pattern.
+func New(settings *repository.SettingRepository, publicURL, pageType string) *Builder {
+	return &Builder{settings: settings, publicURL: publicURL, pageType: pageType}
+}
+
+// Load reads the base URL and locales from settings
+func (b *Builder) Load(ctx context.Context) (*Config, error) {
+	values, err := b.settings.GetMultiple(ctx, []string{SettingBaseURL, SettingLocales})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{BaseURL: strings.TrimSpace(values[SettingBaseURL]), pageType: b.pageType}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = b.publicURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	if raw := values[SettingLocales]; raw != "" {
+		var locales []string
+		if err := json.Unmarshal([]byte(raw), &locales); err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", SettingLocales, err)
+		}
+		for _, locale := range locales {
+			if locale = strings.ToLower(strings.TrimSpace(locale)); locale != "" {
+				cfg.Locales = append(cfg.Locales, locale)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// URL returns the absolute URL of a site path such as "/about"
+func (c *Config) URL(path string) string {
+	return c.BaseURL + path
+}
+
+// PostPath returns the site path of a post in the default locale
+func (c *Config) PostPath(post *models.ContentPost) string {
+	if c.pageType != "" && post.ContentType != nil && post.ContentType.Slug == c.pageType {
+		return "/" + post.Slug
+	}
+	return "/" + post.Path()
+}
+
+// PostURL returns the canonical URL of a post
+func (c *Config) PostURL(post *models.ContentPost) string {
+	return c.URL(c.PostPath(post))
+}
+
+// PostURLs returns a post's path, canonical URL and its URL in each of the
+// site's locales
+func (c *Config) PostURLs(post *models.ContentPost) *models.PostURLs {
+	path := c.PostPath(post)
+	urls := &models.PostURLs{Path: path, URL: c.URL(path)}
+	if len(c.Locales) > 0 {
+		urls.Locales = make(map[string]string, len(c.Locales))
+		for i, locale := range c.Locales {
+			if i == 0 {
+				urls.Locales[locale] = urls.URL
+				continue
+			}
+			urls.Locales[locale] = c.URL("/" + locale + path)
+		}
+	}
+	return urls
+}
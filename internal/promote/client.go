@@ -0,0 +1,206 @@
+package promote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// pageSize is the largest page the list endpoints return
+const pageSize = 100
+
+// Client talks to the /api/v1 endpoints of a CMS instance
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewClient returns a client for the instance at baseURL. apiKey, when set,
+// is sent in the X-API-Key header; promotion needs a full-scope key.
+func NewClient(baseURL, apiKey string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v1",
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError is an error response returned by a remote instance
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.Status, e.Code)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*response.Meta, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	reqctx.Propagate(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var envelope struct {
+		Data  json.RawMessage    `json:"data"`
+		Error *response.APIError `json:"error"`
+		Meta  *response.Meta     `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("%s %s: invalid response: %w", method, u, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{Status: resp.StatusCode, Message: resp.Status}
+		if envelope.Error != nil {
+			apiErr.Code = envelope.Error.Code
+			apiErr.Message = envelope.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return nil, fmt.Errorf("%s %s: invalid response data: %w", method, u, err)
+		}
+	}
+
+	return envelope.Meta, nil
+}
+
+// listAll walks every page of a list endpoint
+func listAll[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		var items []T
+		query := url.Values{"page": {strconv.Itoa(page)}, "page_size": {strconv.Itoa(pageSize)}}
+		meta, err := c.do(ctx, http.MethodGet, path, query, nil, &items)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		if len(items) < pageSize || (meta != nil && int64(len(all)) >= meta.Total) {
+			return all, nil
+		}
+	}
+}
+
+func (c *Client) ContentTypes(ctx context.Context) ([]models.ContentType, error) {
+	return listAll[models.ContentType](ctx, c, "/content-types")
+}
+
+func (c *Client) Tags(ctx context.Context) ([]models.Tag, error) {
+	return listAll[models.Tag](ctx, c, "/tags")
+}
+
+func (c *Client) Media(ctx context.Context) ([]models.Media, error) {
+	return listAll[models.Media](ctx, c, "/media")
+}
+
+// Posts lists posts without their tag and media relations
+func (c *Client) Posts(ctx context.Context) ([]models.ContentPost, error) {
+	return listAll[models.ContentPost](ctx, c, "/posts")
+}
+
+// Post returns a post with its content type, tags and media. It is fetched
+// by ID because the slug endpoint counts a page view.
+func (c *Client) Post(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	var post models.ContentPost
+	if _, err := c.do(ctx, http.MethodGet, "/posts/"+id.String(), nil, nil, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (c *Client) CreateContentType(ctx context.Context, req *models.CreateContentTypeRequest) (*models.ContentType, error) {
+	var ct models.ContentType
+	if _, err := c.do(ctx, http.MethodPost, "/content-types", nil, req, &ct); err != nil {
+		return nil, err
+	}
+	return &ct, nil
+}
+
+func (c *Client) CreateTag(ctx context.Context, req *models.CreateTagRequest) (*models.Tag, error) {
+	var tag models.Tag
+	if _, err := c.do(ctx, http.MethodPost, "/tags", nil, req, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (c *Client) CreateMedia(ctx context.Context, req *models.CreateMediaRequest) (*models.Media, error) {
+	var media models.Media
+	if _, err := c.do(ctx, http.MethodPost, "/media", nil, req, &media); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (c *Client) CreatePost(ctx context.Context, req *models.CreatePostRequest) (*models.ContentPost, error) {
+	var post models.ContentPost
+	if _, err := c.do(ctx, http.MethodPost, "/posts", nil, req, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (c *Client) UpdatePost(ctx context.Context, id uuid.UUID, req *models.UpdatePostRequest) (*models.ContentPost, error) {
+	var post models.ContentPost
+	if _, err := c.do(ctx, http.MethodPut, "/posts/"+id.String(), nil, req, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (c *Client) AttachMedia(ctx context.Context, postID uuid.UUID, req *models.AttachMediaRequest) error {
+	_, err := c.do(ctx, http.MethodPost, "/posts/"+postID.String()+"/media", nil, req, nil)
+	return err
+}
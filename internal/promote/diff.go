@@ -0,0 +1,288 @@
+package promote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ChangeStatus describes how a post on the source differs from the target
+type ChangeStatus string
+
+const (
+	StatusNew        ChangeStatus = "new"
+	StatusChanged    ChangeStatus = "changed"
+	StatusUnchanged  ChangeStatus = "unchanged"
+	StatusTargetOnly ChangeStatus = "target_only"
+)
+
+// PostDiff is the comparison of one post, matched by slug
+type PostDiff struct {
+	Slug     string       `json:"slug"`
+	Title    string       `json:"title"`
+	Status   ChangeStatus `json:"status"`
+	SourceID *uuid.UUID   `json:"source_id,omitempty"`
+	TargetID *uuid.UUID   `json:"target_id,omitempty"`
+	Changes  []string     `json:"changes,omitempty"`
+
+	// Dependencies promotion would create on the target
+	MissingContentType string   `json:"missing_content_type,omitempty"`
+	MissingTags        []string `json:"missing_tags,omitempty"`
+	MissingMedia       []string `json:"missing_media,omitempty"`
+}
+
+// Diff is the result of comparing all posts between two instances
+type Diff struct {
+	Posts   []PostDiff           `json:"posts"`
+	Summary map[ChangeStatus]int `json:"summary"`
+}
+
+// index holds a target instance's content keyed by its portable identifiers:
+// slugs for content types, tags and posts, object keys for media
+type index struct {
+	contentTypes map[string]models.ContentType
+	tags         map[string]models.Tag
+	media        map[string]models.Media
+	posts        map[string]models.ContentPost
+}
+
+func loadIndex(ctx context.Context, c *Client) (*index, error) {
+	idx := &index{
+		contentTypes: make(map[string]models.ContentType),
+		tags:         make(map[string]models.Tag),
+		media:        make(map[string]models.Media),
+		posts:        make(map[string]models.ContentPost),
+	}
+
+	contentTypes, err := c.ContentTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content types: %w", err)
+	}
+	for _, ct := range contentTypes {
+		idx.contentTypes[ct.Slug] = ct
+	}
+
+	tags, err := c.Tags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	for _, t := range tags {
+		idx.tags[t.Slug] = t
+	}
+
+	media, err := c.Media(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	for _, m := range media {
+		idx.media[m.ObjectKey] = m
+	}
+
+	posts, err := c.Posts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+	for _, p := range posts {
+		idx.posts[p.Slug] = p
+	}
+
+	return idx, nil
+}
+
+// Compare diffs every post on source against target
+func Compare(ctx context.Context, source, target *Client) (*Diff, error) {
+	sourcePosts, err := source.Posts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source posts: %w", err)
+	}
+
+	idx, err := loadIndex(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+
+	diff := &Diff{Summary: make(map[ChangeStatus]int)}
+	seen := make(map[string]bool)
+
+	for _, sp := range sourcePosts {
+		seen[sp.Slug] = true
+
+		post, err := source.Post(ctx, sp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get source post %s: %w", sp.Slug, err)
+		}
+
+		d := PostDiff{Slug: post.Slug, Title: post.Title, SourceID: &post.ID}
+		missingDependencies(post, idx, &d)
+
+		tp, exists := idx.posts[post.Slug]
+		if !exists {
+			d.Status = StatusNew
+		} else {
+			targetPost, err := target.Post(ctx, tp.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get target post %s: %w", tp.Slug, err)
+			}
+			d.TargetID = &targetPost.ID
+			d.Changes = changedFields(post, targetPost)
+			d.Status = StatusUnchanged
+			if len(d.Changes) > 0 {
+				d.Status = StatusChanged
+			}
+		}
+
+		diff.Posts = append(diff.Posts, d)
+		diff.Summary[d.Status]++
+	}
+
+	for slug, tp := range idx.posts {
+		if seen[slug] {
+			continue
+		}
+		id := tp.ID
+		diff.Posts = append(diff.Posts, PostDiff{Slug: slug, Title: tp.Title, Status: StatusTargetOnly, TargetID: &id})
+		diff.Summary[StatusTargetOnly]++
+	}
+
+	sort.Slice(diff.Posts, func(i, j int) bool { return diff.Posts[i].Slug < diff.Posts[j].Slug })
+	return diff, nil
+}
+
+func missingDependencies(post *models.ContentPost, idx *index, d *PostDiff) {
+	if post.ContentType != nil {
+		if _, ok := idx.contentTypes[post.ContentType.Slug]; !ok {
+			d.MissingContentType = post.ContentType.Slug
+		}
+	}
+	for _, t := range post.Tags {
+		if _, ok := idx.tags[t.Slug]; !ok {
+			d.MissingTags = append(d.MissingTags, t.Slug)
+		}
+	}
+	for _, pm := range post.Media {
+		if pm.Media == nil {
+			continue
+		}
+		if _, ok := idx.media[pm.Media.ObjectKey]; !ok {
+			d.MissingMedia = append(d.MissingMedia, pm.Media.ObjectKey)
+		}
+	}
+}
+
+// changedFields lists the fields that differ between two detailed posts.
+// IDs, timestamps and view counts are instance-specific and ignored.
+func changedFields(src, dst *models.ContentPost) []string {
+	var changes []string
+
+	if src.Title != dst.Title {
+		changes = append(changes, "title")
+	}
+	if !equalStringPtr(src.Excerpt, dst.Excerpt) {
+		changes = append(changes, "excerpt")
+	}
+	if !equalStringPtr(src.Content, dst.Content) {
+		changes = append(changes, "content")
+	}
+	if !equalJSON(src.Metadata, dst.Metadata) {
+		changes = append(changes, "metadata")
+	}
+	if src.Status != dst.Status {
+		changes = append(changes, "status")
+	}
+	if !equalTimePtr(src, dst) {
+		changes = append(changes, "published_at")
+	}
+	if contentTypeSlug(src) != contentTypeSlug(dst) {
+		changes = append(changes, "content_type")
+	}
+	if !equalSets(tagSlugs(src), tagSlugs(dst)) {
+		changes = append(changes, "tags")
+	}
+	if !equalSets(mediaKeys(src), mediaKeys(dst)) {
+		changes = append(changes, "media")
+	}
+
+	return changes
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTimePtr(src, dst *models.ContentPost) bool {
+	if src.PublishedAt == nil || dst.PublishedAt == nil {
+		return src.PublishedAt == dst.PublishedAt
+	}
+	return src.PublishedAt.Equal(*dst.PublishedAt)
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if len(a) > 0 && json.Compact(&ca, a) != nil {
+		return false
+	}
+	if len(b) > 0 && json.Compact(&cb, b) != nil {
+		return false
+	}
+	// Treat a missing object and an empty one as equal
+	na, nb := ca.String(), cb.String()
+	if na == "" || na == "null" {
+		na = "{}"
+	}
+	if nb == "" || nb == "null" {
+		nb = "{}"
+	}
+	return na == nb
+}
+
+func contentTypeSlug(p *models.ContentPost) string {
+	if p.ContentType == nil {
+		return ""
+	}
+	return p.ContentType.Slug
+}
+
+func tagSlugs(p *models.ContentPost) []string {
+	slugs := make([]string, 0, len(p.Tags))
+	for _, t := range p.Tags {
+		slugs = append(slugs, t.Slug)
+	}
+	return slugs
+}
+
+// mediaKeys identifies attachments by object key, role and order
+func mediaKeys(p *models.ContentPost) []string {
+	keys := make([]string, 0, len(p.Media))
+	for _, pm := range p.Media {
+		if pm.Media == nil {
+			continue
+		}
+		keys = append(keys, fmt.Sprintf("%s/%d/%d", pm.Media.ObjectKey, pm.MediaRole, pm.DisplayOrder))
+	}
+	return keys
+}
+
+func equalSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package promote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// Options controls how posts are written to the target
+type Options struct {
+	// AuthorID is used for posts created on the target. When nil the source
+	// author ID is kept, which requires the same user to exist there.
+	AuthorID *uuid.UUID
+}
+
+// PromotedPost is the outcome of promoting a single post
+type PromotedPost struct {
+	Slug     string     `json:"slug"`
+	Action   string     `json:"action"` // created, updated, unchanged or failed
+	TargetID *uuid.UUID `json:"target_id,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Result summarises a promotion run, including dependencies created on the target
+type Result struct {
+	Posts        []PromotedPost `json:"posts"`
+	ContentTypes []string       `json:"created_content_types,omitempty"`
+	Tags         []string       `json:"created_tags,omitempty"`
+	Media        []string       `json:"created_media,omitempty"`
+}
+
+// Promoter pushes selected posts from a source instance to a target,
+// creating the content types, tags and media records they depend on
+type Promoter struct {
+	source *Client
+	target *Client
+	opts   Options
+
+	idx    *index
+	result *Result
+}
+
+func NewPromoter(source, target *Client, opts Options) *Promoter {
+	return &Promoter{source: source, target: target, opts: opts}
+}
+
+// Promote pushes the posts with the given slugs. A failure on one post is
+// recorded in the result and does not stop the others.
+func (p *Promoter) Promote(ctx context.Context, slugs []string) (*Result, error) {
+	sourcePosts, err := p.source.Posts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source posts: %w", err)
+	}
+	bySlug := make(map[string]uuid.UUID, len(sourcePosts))
+	for _, sp := range sourcePosts {
+		bySlug[sp.Slug] = sp.ID
+	}
+
+	p.idx, err = loadIndex(ctx, p.target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+	p.result = &Result{}
+
+	for _, slug := range slugs {
+		id, ok := bySlug[slug]
+		if !ok {
+			p.result.Posts = append(p.result.Posts, PromotedPost{Slug: slug, Action: "failed", Error: "post not found on source"})
+			continue
+		}
+
+		promoted, err := p.promotePost(ctx, id)
+		if err != nil {
+			promoted = PromotedPost{Slug: slug, Action: "failed", Error: err.Error()}
+		}
+		p.result.Posts = append(p.result.Posts, promoted)
+	}
+
+	return p.result, nil
+}
+
+func (p *Promoter) promotePost(ctx context.Context, sourceID uuid.UUID) (PromotedPost, error) {
+	post, err := p.source.Post(ctx, sourceID)
+	if err != nil {
+		return PromotedPost{}, fmt.Errorf("failed to get source post: %w", err)
+	}
+
+	contentTypeID, err := p.ensureContentType(ctx, post.ContentType)
+	if err != nil {
+		return PromotedPost{}, err
+	}
+
+	tagIDs := make([]uuid.UUID, 0, len(post.Tags))
+	for _, t := range post.Tags {
+		id, err := p.ensureTag(ctx, t)
+		if err != nil {
+			return PromotedPost{}, err
+		}
+		tagIDs = append(tagIDs, id)
+	}
+
+	out := PromotedPost{Slug: post.Slug}
+	var target *models.ContentPost
+
+	if existing, ok := p.idx.posts[post.Slug]; ok {
+		current, err := p.target.Post(ctx, existing.ID)
+		if err != nil {
+			return PromotedPost{}, fmt.Errorf("failed to get target post: %w", err)
+		}
+		if len(changedFields(post, current)) == 0 {
+			out.Action = "unchanged"
+			out.TargetID = &current.ID
+			return out, nil
+		}
+
+		metadata := post.Metadata
+		target, err = p.target.UpdatePost(ctx, current.ID, &models.UpdatePostRequest{
+			ContentTypeID: &contentTypeID,
+			Title:         &post.Title,
+			Excerpt:       post.Excerpt,
+			Content:       post.Content,
+			Metadata:      &metadata,
+			Status:        &post.Status,
+			PublishedAt:   post.PublishedAt,
+			TagIDs:        &tagIDs,
+			Latitude:      post.Latitude,
+			Longitude:     post.Longitude,
+			StartsAt:      post.StartsAt,
+			EndsAt:        post.EndsAt,
+			Recurrence:    post.Recurrence,
+			ExpiresAt:     post.ExpiresAt,
+		})
+		if err != nil {
+			return PromotedPost{}, fmt.Errorf("failed to update target post: %w", err)
+		}
+		target.Media = current.Media
+		out.Action = "updated"
+	} else {
+		authorID := post.AuthorID
+		if p.opts.AuthorID != nil {
+			authorID = *p.opts.AuthorID
+		}
+		target, err = p.target.CreatePost(ctx, &models.CreatePostRequest{
+			ContentTypeID: contentTypeID,
+			AuthorID:      authorID,
+			Title:         post.Title,
+			Slug:          post.Slug,
+			Excerpt:       post.Excerpt,
+			Content:       post.Content,
+			Metadata:      post.Metadata,
+			Status:        &post.Status,
+			PublishedAt:   post.PublishedAt,
+			TagIDs:        tagIDs,
+			Latitude:      post.Latitude,
+			Longitude:     post.Longitude,
+			StartsAt:      post.StartsAt,
+			EndsAt:        post.EndsAt,
+			Recurrence:    post.Recurrence,
+			ExpiresAt:     post.ExpiresAt,
+		})
+		if err != nil {
+			return PromotedPost{}, fmt.Errorf("failed to create target post: %w", err)
+		}
+		p.idx.posts[target.Slug] = *target
+		out.Action = "created"
+	}
+	out.TargetID = &target.ID
+
+	if err := p.syncMedia(ctx, post, target); err != nil {
+		return PromotedPost{}, err
+	}
+
+	return out, nil
+}
+
+func (p *Promoter) ensureContentType(ctx context.Context, ct *models.ContentType) (uuid.UUID, error) {
+	if ct == nil {
+		return uuid.Nil, fmt.Errorf("source post has no content type")
+	}
+	if existing, ok := p.idx.contentTypes[ct.Slug]; ok {
+		return existing.ID, nil
+	}
+
+	created, err := p.target.CreateContentType(ctx, &models.CreateContentTypeRequest{
+		Name:          ct.Name,
+		Slug:          ct.Slug,
+		SchemaFields:  ct.SchemaFields,
+		Traits:        ct.Traits,
+		IndexedFields: ct.IndexedFields,
+		SlugPattern:   ct.SlugPattern,
+		IsActive:      &ct.IsActive,
+		DisplayOrder:  &ct.DisplayOrder,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create content type %s: %w", ct.Slug, err)
+	}
+
+	p.idx.contentTypes[created.Slug] = *created
+	p.result.ContentTypes = append(p.result.ContentTypes, created.Slug)
+	return created.ID, nil
+}
+
+func (p *Promoter) ensureTag(ctx context.Context, t models.Tag) (uuid.UUID, error) {
+	if existing, ok := p.idx.tags[t.Slug]; ok {
+		return existing.ID, nil
+	}
+
+	created, err := p.target.CreateTag(ctx, &models.CreateTagRequest{Name: t.Name, Slug: t.Slug})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create tag %s: %w", t.Slug, err)
+	}
+
+	p.idx.tags[created.Slug] = *created
+	p.result.Tags = append(p.result.Tags, created.Slug)
+	return created.ID, nil
+}
+
+func (p *Promoter) ensureMedia(ctx context.Context, m *models.Media) (uuid.UUID, error) {
+	if existing, ok := p.idx.media[m.ObjectKey]; ok {
+		return existing.ID, nil
+	}
+
+	// Only the metadata record is copied; both instances are expected to
+	// share the bucket or CDN the object key points at
+	created, err := p.target.CreateMedia(ctx, &models.CreateMediaRequest{
+		FileName:   m.FileName,
+		ObjectKey:  m.ObjectKey,
+		BucketName: m.BucketName,
+		CDNUrl:     m.CDNUrl,
+		FileType:   m.FileType,
+		MimeType:   m.MimeType,
+		FileSize:   m.FileSize,
+		Dimensions: m.Dimensions,
+		Variants:   m.Variants,
+		AltText:    m.AltText,
+		Checksum:   m.Checksum,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create media %s: %w", m.ObjectKey, err)
+	}
+
+	p.idx.media[created.ObjectKey] = *created
+	p.result.Media = append(p.result.Media, created.ObjectKey)
+	return created.ID, nil
+}
+
+// syncMedia attaches the source post's media that the target post lacks.
+// Attachments that only exist on the target are left in place.
+func (p *Promoter) syncMedia(ctx context.Context, src, dst *models.ContentPost) error {
+	attached := make(map[string]bool, len(dst.Media))
+	for _, pm := range dst.Media {
+		if pm.Media != nil {
+			attached[pm.Media.ObjectKey] = true
+		}
+	}
+
+	for _, pm := range src.Media {
+		if pm.Media == nil || attached[pm.Media.ObjectKey] {
+			continue
+		}
+
+		mediaID, err := p.ensureMedia(ctx, pm.Media)
+		if err != nil {
+			return err
+		}
+
+		order := pm.DisplayOrder
+		if err := p.target.AttachMedia(ctx, dst.ID, &models.AttachMediaRequest{
+			MediaID:      mediaID,
+			MediaRole:    pm.MediaRole,
+			DisplayOrder: &order,
+		}); err != nil {
+			return fmt.Errorf("failed to attach media %s: %w", pm.Media.ObjectKey, err)
+		}
+	}
+
+	return nil
+}
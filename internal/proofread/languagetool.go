@@ -0,0 +1,141 @@
+package proofread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+)
+
+// markup matches HTML tags and character references
+var markup = regexp.MustCompile(`<[^>]*>|&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// blockTag matches tags that end a paragraph or line, which LanguageTool
+// should read as a break so sentences don't run together
+var blockTag = regexp.MustCompile(`(?i)^</?(p|div|br|li|ul|ol|h[1-6]|blockquote|pre|tr|td|th|table|section|article|hr)\b`)
+
+// languageTool calls the check API of a (self-hosted) LanguageTool server
+type languageTool struct {
+	url    string
+	client *http.Client
+}
+
+type annotation struct {
+	Text        string `json:"text,omitempty"`
+	Markup      string `json:"markup,omitempty"`
+	InterpretAs string `json:"interpretAs,omitempty"`
+}
+
+func (c *languageTool) Check(ctx context.Context, text, language string) (*models.ProofreadResult, error) {
+	data, err := json.Marshal(map[string][]annotation{"annotation": annotate(text)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode proofreading request: %w", err)
+	}
+	form := url.Values{"language": {language}, "data": {string(data)}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build proofreading request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	reqctx.Propagate(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call LanguageTool: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("LanguageTool rejected request with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		Language struct {
+			Code             string `json:"code"`
+			DetectedLanguage struct {
+				Code string `json:"code"`
+			} `json:"detectedLanguage"`
+		} `json:"language"`
+		Matches []struct {
+			Message      string `json:"message"`
+			ShortMessage string `json:"shortMessage"`
+			Offset       int    `json:"offset"`
+			Length       int    `json:"length"`
+			Replacements []struct {
+				Value string `json:"value"`
+			} `json:"replacements"`
+			Rule struct {
+				ID        string `json:"id"`
+				IssueType string `json:"issueType"`
+				Category  struct {
+					ID string `json:"id"`
+				} `json:"category"`
+			} `json:"rule"`
+		} `json:"matches"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode LanguageTool response: %w", err)
+	}
+
+	result := &models.ProofreadResult{Language: out.Language.Code, Issues: make([]models.ProofreadIssue, 0, len(out.Matches))}
+	if language == "auto" && out.Language.DetectedLanguage.Code != "" {
+		result.Language = out.Language.DetectedLanguage.Code
+	}
+	for _, m := range out.Matches {
+		issue := models.ProofreadIssue{
+			Offset:       m.Offset,
+			Length:       m.Length,
+			Message:      m.Message,
+			ShortMessage: m.ShortMessage,
+			Type:         m.Rule.IssueType,
+			Category:     m.Rule.Category.ID,
+			RuleID:       m.Rule.ID,
+		}
+		for i, r := range m.Replacements {
+			if i == maxReplacements {
+				break
+			}
+			issue.Replacements = append(issue.Replacements, r.Value)
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+	return result, nil
+}
+
+// annotate splits text into plain text and markup for LanguageTool.
+// Entities are read as the character they stand for and block-level tags as
+// paragraph breaks; other tags are ignored.
+func annotate(text string) []annotation {
+	var parts []annotation
+	last := 0
+	for _, loc := range markup.FindAllStringIndex(text, -1) {
+		if loc[0] > last {
+			parts = append(parts, annotation{Text: text[last:loc[0]]})
+		}
+		m := text[loc[0]:loc[1]]
+		part := annotation{Markup: m}
+		switch {
+		case m[0] == '&':
+			part.InterpretAs = html.UnescapeString(m)
+		case blockTag.MatchString(m):
+			part.InterpretAs = "\n\n"
+		}
+		parts = append(parts, part)
+		last = loc[1]
+	}
+	if last < len(text) {
+		parts = append(parts, annotation{Text: text[last:]})
+	}
+	return parts
+}
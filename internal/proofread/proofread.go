@@ -0,0 +1,43 @@
+// Package proofread checks spelling, grammar and style with a pluggable
+// checker, returning issues positioned in the checked text.
+package proofread
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// Supported checker drivers
+const (
+	DriverLanguageTool = "languagetool"
+)
+
+// Checker finds problems in a text. Markup (HTML tags and entities) is
+// skipped but counts towards issue offsets, so they point into the text as
+// stored.
+type Checker interface {
+	Check(ctx context.Context, text, language string) (*models.ProofreadResult, error)
+}
+
+// maxReplacements caps the replacements suggested per issue
+const maxReplacements = 5
+
+// New returns the checker selected by cfg.Driver
+func New(cfg config.ProofreadConfig) (Checker, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch cfg.Driver {
+	case DriverLanguageTool:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("languagetool: PROOFREAD_URL is required")
+		}
+		return &languageTool{url: strings.TrimRight(cfg.URL, "/") + "/v2/check", client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown driver %q", cfg.Driver)
+	}
+}
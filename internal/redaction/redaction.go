@@ -0,0 +1,100 @@
+// Package redaction hides response fields from callers whose role is too
+// low to see them. Rules are configured per entity and applied by the
+// response encoder, so every endpoint returning an entity, detail or list,
+// leaves out the same fields.
+package redaction
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/response"
+)
+
+// Rule hides a field, by its JSON name, of an entity's model from callers
+// below MinRole. A rule with a condition only hides the field on values
+// the condition holds for.
+type Rule struct {
+	Entity  string
+	Field   string
+	MinRole models.Role
+
+	model reflect.Type
+	when  func(v reflect.Value) bool
+}
+
+// For returns a rule hiding field of model T from callers below minRole,
+// on every value or, when when is set, on the values it returns true for
+func For[T any](entity, field string, minRole models.Role, when func(*T) bool) Rule {
+	rule := Rule{Entity: entity, Field: field, MinRole: minRole, model: reflect.TypeOf((*T)(nil)).Elem()}
+	if when != nil {
+		rule.when = func(v reflect.Value) bool {
+			if v.CanAddr() {
+				return when(v.Addr().Interface().(*T))
+			}
+			value := v.Interface().(T)
+			return when(&value)
+		}
+	}
+	return rule
+}
+
+// Rules lists the redacted fields of each entity
+var Rules = []Rule{
+	// Submitters' network details are for admins only
+	For[models.ContactSubmission]("contacts", "ip_address", models.RoleAdmin, nil),
+	For[models.ContactSubmission]("contacts", "user_agent", models.RoleAdmin, nil),
+
+	// Metadata of posts that aren't published yet is for editors
+	For("posts", "metadata", models.RoleEditor, func(p *models.ContentPost) bool {
+		return p.Status != models.PostStatusPublished
+	}),
+}
+
+// Middleware redacts the responses of each request for the role of its
+// user; anonymous requests get anonymousRole. It must run after the
+// authentication middleware.
+func Middleware(rules []Rule, anonymousRole models.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := anonymousRole
+			if user := auth.UserFrom(r.Context()); user != nil {
+				role = user.Role
+			}
+			if redact := Redactor(rules, role); redact != nil {
+				w = response.WithRedactor(w, redact)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Redactor returns the redactor hiding what rules hide from role, or nil
+// when role may see everything
+func Redactor(rules []Rule, role models.Role) response.Redactor {
+	byModel := make(map[reflect.Type][]Rule)
+	for _, rule := range rules {
+		if role < rule.MinRole {
+			byModel[rule.model] = append(byModel[rule.model], rule)
+		}
+	}
+	if len(byModel) == 0 {
+		return nil
+	}
+
+	return func(v reflect.Value) []string {
+		applicable := byModel[v.Type()]
+		if len(applicable) == 0 {
+			return nil
+		}
+		hidden := make([]string, 0, len(applicable))
+		for _, rule := range applicable {
+			if rule.when == nil || rule.when(v) {
+				hidden = append(hidden, rule.Field)
+			}
+		}
+		return hidden
+	}
+}
@@ -0,0 +1,112 @@
+package rendition
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Inline Markdown patterns, applied to already-escaped text
+var (
+	mdImage   = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdItalic  = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdList    = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+`)
+	mdOrdered = regexp.MustCompile(`^\s*\d+\.\s+`)
+)
+
+// markdownToHTML converts the common subset of Markdown used in post bodies:
+// headings, paragraphs, lists, block quotes, fenced code, emphasis, links and
+// images. The result is passed through the sanitizer like any other HTML.
+func markdownToHTML(md string) string {
+	var out strings.Builder
+	var paragraph []string
+	list := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + inline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+
+		case strings.HasPrefix(trimmed, "#"):
+			flushParagraph()
+			closeList()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 {
+				level = 6
+			}
+			tag := "h" + string(rune('0'+level))
+			out.WriteString("<" + tag + ">" + inline(strings.TrimSpace(trimmed[level:])) + "</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			closeList()
+			out.WriteString("<blockquote><p>" + inline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</p></blockquote>\n")
+
+		case trimmed == "---" || trimmed == "***":
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>\n")
+
+		case mdList.MatchString(line):
+			flushParagraph()
+			kind := "ul"
+			if mdOrdered.MatchString(line) {
+				kind = "ol"
+			}
+			if list != kind {
+				closeList()
+				out.WriteString("<" + kind + ">\n")
+				list = kind
+			}
+			out.WriteString("<li>" + inline(mdList.ReplaceAllString(line, "")) + "</li>\n")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return out.String()
+}
+
+func inline(s string) string {
+	s = html.EscapeString(s)
+	s = mdCode.ReplaceAllString(s, "<code>$1</code>")
+	s = mdImage.ReplaceAllString(s, `<img src="$2" alt="$1">`)
+	s = mdLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = mdBold.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdItalic.ReplaceAllString(s, "<em>$1</em>")
+	return s
+}
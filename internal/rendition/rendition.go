@@ -0,0 +1,151 @@
+// Package rendition produces a simplified, sanitized HTML version of post
+// content for places that cannot run the site's own markup, such as AMP
+// pages and email bodies.
+package rendition
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Output formats
+const (
+	FormatHTML = "html"
+	FormatAMP  = "amp"
+)
+
+// Image describes the URL (and, if known, the size) an image is rendered with
+type Image struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+// Options control a rendition
+type Options struct {
+	Format string
+
+	// RewriteImage maps an image src found in the content to the URL it
+	// should be served from, e.g. a CDN variant. It may be nil.
+	RewriteImage func(src string) Image
+}
+
+// Render converts content (HTML, or Markdown when it does not start with a
+// tag) into the constrained markup described by allowedTags
+func Render(content string, opts Options) string {
+	if !strings.HasPrefix(strings.TrimSpace(content), "<") {
+		content = markdownToHTML(content)
+	}
+
+	var out strings.Builder
+	var open []string
+	skipDepth := 0
+	skipTag := ""
+
+	for _, tok := range tokenize(content) {
+		if skipDepth > 0 {
+			if tok.tag == skipTag {
+				if tok.closing {
+					skipDepth--
+				} else {
+					skipDepth++
+				}
+			}
+			continue
+		}
+
+		if tok.tag == "" {
+			out.WriteString(html.EscapeString(tok.text))
+			continue
+		}
+
+		if droppedContent[tok.tag] && !tok.closing {
+			skipTag, skipDepth = tok.tag, 1
+			continue
+		}
+
+		allowed, ok := allowedTags[tok.tag]
+		if !ok {
+			continue
+		}
+
+		if tok.closing {
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.tag {
+					for j := len(open) - 1; j >= i; j-- {
+						out.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		if tok.tag == "img" {
+			writeImage(&out, tok.attrs, opts)
+			continue
+		}
+
+		out.WriteString("<" + tok.tag)
+		for _, name := range allowed {
+			value, ok := tok.attrs[name]
+			if !ok || (name == "href" && !safeURL(value)) {
+				continue
+			}
+			out.WriteString(" " + name + `="` + html.EscapeString(value) + `"`)
+		}
+		out.WriteString(">")
+
+		if !voidTags[tok.tag] {
+			open = append(open, tok.tag)
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+
+	return strings.TrimSpace(out.String())
+}
+
+func writeImage(out *strings.Builder, attrs map[string]string, opts Options) {
+	src := strings.TrimSpace(attrs["src"])
+	if src == "" || !safeURL(src) {
+		return
+	}
+
+	img := Image{URL: src}
+	if opts.RewriteImage != nil {
+		if rewritten := opts.RewriteImage(src); rewritten.URL != "" {
+			img = rewritten
+		}
+	}
+	if w, err := strconv.Atoi(attrs["width"]); err == nil && w > 0 {
+		img.Width = w
+	}
+	if h, err := strconv.Atoi(attrs["height"]); err == nil && h > 0 {
+		img.Height = h
+	}
+
+	tag := "img"
+	if opts.Format == FormatAMP {
+		// amp-img needs explicit dimensions to reserve layout space
+		if img.Width == 0 || img.Height == 0 {
+			return
+		}
+		tag = "amp-img"
+	}
+
+	out.WriteString("<" + tag + ` src="` + html.EscapeString(img.URL) + `"`)
+	out.WriteString(` alt="` + html.EscapeString(attrs["alt"]) + `"`)
+	if img.Width > 0 && img.Height > 0 {
+		out.WriteString(` width="` + strconv.Itoa(img.Width) + `" height="` + strconv.Itoa(img.Height) + `"`)
+	}
+	if tag == "amp-img" {
+		out.WriteString(` layout="responsive"></amp-img>`)
+		return
+	}
+	out.WriteString(">")
+}
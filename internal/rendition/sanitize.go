@@ -0,0 +1,173 @@
+package rendition
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// allowedTags lists the elements kept in a rendition and the attributes
+// kept on each. Everything else is dropped; text is always re-escaped, so the
+// output only ever contains markup produced here.
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil,
+	"blockquote": nil, "code": nil, "pre": nil,
+	"ul": nil, "ol": nil, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": nil, "td": nil,
+	"figure": nil, "figcaption": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "width", "height"},
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// droppedContent lists elements whose content is removed along with them
+var droppedContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "math": true, "form": true,
+	"head": true, "title": true,
+}
+
+type token struct {
+	text    string // text content (unescaped)
+	tag     string // lower-case tag name for tags
+	closing bool
+	attrs   map[string]string
+}
+
+// tokenize splits markup into text and tag tokens. It is deliberately
+// forgiving: anything it cannot parse as a tag is treated as text.
+func tokenize(s string) []token {
+	var tokens []token
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			tokens = append(tokens, token{text: html.UnescapeString(s)})
+			break
+		}
+		if i > 0 {
+			tokens = append(tokens, token{text: html.UnescapeString(s[:i])})
+			s = s[i:]
+		}
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+
+		tok, rest, ok := parseTag(s)
+		if !ok {
+			tokens = append(tokens, token{text: "<"})
+			s = s[1:]
+			continue
+		}
+		tokens = append(tokens, tok)
+		s = rest
+	}
+	return tokens
+}
+
+// parseTag parses a tag at the start of s ("<...>")
+func parseTag(s string) (token, string, bool) {
+	i := 1
+	tok := token{}
+	if i < len(s) && s[i] == '/' {
+		tok.closing = true
+		i++
+	}
+
+	start := i
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	if i == start {
+		// "<!doctype", "<?xml" and similar: skip to the closing bracket
+		if i < len(s) && (s[i] == '!' || s[i] == '?') {
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return tok, "", false
+			}
+			return token{}, s[end+1:], true
+		}
+		return tok, "", false
+	}
+	tok.tag = strings.ToLower(s[start:i])
+	tok.attrs = make(map[string]string)
+
+	for i < len(s) {
+		for i < len(s) && (isSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			return tok, "", false
+		}
+		if s[i] == '>' {
+			return tok, s[i+1:], true
+		}
+
+		nameStart := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					return tok, "", false
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valStart := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[valStart:i]
+			}
+		}
+		if name != "" {
+			tok.attrs[name] = html.UnescapeString(value)
+		}
+	}
+
+	return tok, "", false
+}
+
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// safeURL reports whether u may be used in href/src: absolute http(s),
+// mailto, or a relative reference
+func safeURL(u string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// failuresToFailover and successesToRecover keep one slow probe from
+// flipping every media URL back and forth
+const (
+	failuresToFailover = 2
+	successesToRecover = 2
+)
+
+// Failover probes the primary CDN and, while it is down, rewrites media URLs
+// under it to the replica CDN. A nil Failover rewrites nothing. Each replica
+// probes on its own.
+type Failover struct {
+	primary  string
+	replica  string
+	probeURL string
+	interval time.Duration
+	client   *http.Client
+
+	mu          sync.RWMutex
+	failedOver  bool
+	since       *time.Time
+	lastCheckAt *time.Time
+	lastError   *string
+	failures    int
+	successes   int
+}
+
+// NewFailover returns nil unless both CDN URLs are configured
+func NewFailover(cfg config.ReplicationConfig) *Failover {
+	if cfg.PrimaryCDNURL == "" || cfg.ReplicaCDNURL == "" {
+		return nil
+	}
+	probeURL := cfg.CDNHealthURL
+	if probeURL == "" {
+		probeURL = cfg.PrimaryCDNURL
+	}
+	return &Failover{
+		primary:  strings.TrimRight(cfg.PrimaryCDNURL, "/"),
+		replica:  strings.TrimRight(cfg.ReplicaCDNURL, "/"),
+		probeURL: probeURL,
+		interval: cfg.CDNCheckInterval,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Run probes once immediately and then on every check interval until ctx is cancelled
+func (f *Failover) Run(ctx context.Context) {
+	if f == nil {
+		return
+	}
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Failover) check(ctx context.Context) {
+	err := f.probe(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastCheckAt = &now
+	if err != nil {
+		msg := err.Error()
+		f.lastError = &msg
+		f.failures++
+		f.successes = 0
+		if !f.failedOver && f.failures >= failuresToFailover {
+			f.failedOver = true
+			f.since = &now
+			log.Printf("[WARN] Primary CDN is down, serving media from %s: %v", f.replica, err)
+		}
+		return
+	}
+
+	f.lastError = nil
+	f.successes++
+	f.failures = 0
+	if f.failedOver && f.successes >= successesToRecover {
+		f.failedOver = false
+		f.since = nil
+		log.Printf("Primary CDN recovered, serving media from %s", f.primary)
+	}
+}
+
+// probe treats any response below 500 as healthy: a missing health object
+// still shows the CDN answering
+func (f *Failover) probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, f.probeURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build CDN probe: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("CDN probe failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("CDN probe returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Status reports the failover state; nil when failover is not configured
+func (f *Failover) Status() *models.CDNFailoverState {
+	if f == nil {
+		return nil
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return &models.CDNFailoverState{
+		PrimaryURL:  f.primary,
+		ReplicaURL:  f.replica,
+		FailedOver:  f.failedOver,
+		Since:       f.since,
+		LastCheckAt: f.lastCheckAt,
+		LastError:   f.lastError,
+	}
+}
+
+func (f *Failover) active() bool {
+	if f == nil {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.failedOver
+}
+
+// Rewrite moves a URL under the primary CDN to the replica CDN while failed over
+func (f *Failover) Rewrite(url string) string {
+	if !f.active() {
+		return url
+	}
+	return f.rewrite(url)
+}
+
+func (f *Failover) rewrite(url string) string {
+	if rest, ok := strings.CutPrefix(url, f.primary); ok && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+		return f.replica + rest
+	}
+	return url
+}
+
+// Apply rewrites the cdn_url and variant URLs of media while failed over
+func (f *Failover) Apply(media ...*models.Media) {
+	if !f.active() {
+		return
+	}
+	for _, m := range media {
+		if m.CDNUrl != nil {
+			url := f.rewrite(*m.CDNUrl)
+			m.CDNUrl = &url
+		}
+		m.Variants = f.rewriteVariants(m.Variants)
+	}
+}
+
+// rewriteVariants rewrites variants given as a URL or as an object with a
+// "url"; anything it cannot parse is left as is
+func (f *Failover) rewriteVariants(raw json.RawMessage) json.RawMessage {
+	var variants map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &variants) != nil {
+		return raw
+	}
+	for name, entry := range variants {
+		var url string
+		if json.Unmarshal(entry, &url) == nil {
+			variants[name], _ = json.Marshal(f.rewrite(url))
+			continue
+		}
+		var detailed map[string]json.RawMessage
+		if json.Unmarshal(entry, &detailed) != nil || json.Unmarshal(detailed["url"], &url) != nil {
+			continue
+		}
+		detailed["url"], _ = json.Marshal(f.rewrite(url))
+		variants[name], _ = json.Marshal(detailed)
+	}
+	out, err := json.Marshal(variants)
+	if err != nil {
+		return raw
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+// Package replication decides which stored objects of a media record are
+// copied to the replica storage, and fails media URLs over to the replica
+// CDN while the primary one is down.
+package replication
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ObjectKeys returns the object keys of a media file and its variants.
+// Variants map names to a URL or to {"url", "width", "height"}; only the
+// detailed form can carry an "object_key", so URL-only variants are not
+// copied.
+func ObjectKeys(m *models.Media) []string {
+	keys := []string{m.ObjectKey}
+
+	var variants map[string]json.RawMessage
+	if len(m.Variants) == 0 || json.Unmarshal(m.Variants, &variants) != nil {
+		return keys
+	}
+	seen := map[string]bool{m.ObjectKey: true}
+	var extra []string
+	for _, entry := range variants {
+		var v struct {
+			ObjectKey string `json:"object_key"`
+		}
+		if json.Unmarshal(entry, &v) == nil && v.ObjectKey != "" && !seen[v.ObjectKey] {
+			seen[v.ObjectKey] = true
+			extra = append(extra, v.ObjectKey)
+		}
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type AccessLogRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAccessLogRepository(db *pgxpool.Pool) *AccessLogRepository {
+	return &AccessLogRepository{db: db}
+}
+
+// Record stores entries in one round trip
+func (r *AccessLogRepository) Record(ctx context.Context, entries []models.AccessLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		batch.Queue(`
+			INSERT INTO access_log (id, resource, resource_id, action, client, ip_address, request_id, query)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			uuid.New(), e.Resource, e.ResourceID, e.Action, e.Client, e.IPAddress, e.RequestID, e.Query,
+		)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to record access: %w", err)
+	}
+	return nil
+}
+
+func (r *AccessLogRepository) List(ctx context.Context, filter models.AccessLogFilter) ([]models.AccessLogEntry, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Resource != "" {
+		cb.addf("resource = %s", filter.Resource)
+	}
+	if filter.ResourceID != nil {
+		cb.addf("resource_id = %s", *filter.ResourceID)
+	}
+	if filter.Client != "" {
+		cb.addf("client = %s", filter.Client)
+	}
+	if filter.From != nil {
+		cb.addf("created_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		cb.addf("created_at < %s", *filter.To)
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM access_log %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count access log: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, resource, resource_id, action, client, ip_address, request_id, query, created_at
+		FROM access_log
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`,
+		whereClause, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list access log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AccessLogEntry
+	for rows.Next() {
+		var e models.AccessLogEntry
+		if err := rows.Scan(
+			&e.ID, &e.Resource, &e.ResourceID, &e.Action, &e.Client,
+			&e.IPAddress, &e.RequestID, &e.Query, &e.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan access log entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, total, nil
+}
+
+// Prune deletes entries recorded before the cutoff
+func (r *AccessLogRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, "DELETE FROM access_log WHERE created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune access log: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
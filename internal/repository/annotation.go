@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type AnnotationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnnotationRepository(db *pgxpool.Pool) *AnnotationRepository {
+	return &AnnotationRepository{db: db}
+}
+
+const annotationSelect = `
+	SELECT a.id, a.post_id, a.parent_id, a.author_id, a.body, a.block_id, a.range_start, a.range_end,
+	       a.quote, a.mention_ids, a.resolved_at, a.resolved_by, a.created_at, a.updated_at,
+	       u.id, u.email, u.full_name, u.role, u.is_active, u.created_at, u.updated_at
+	FROM post_annotations a
+	JOIN users u ON u.id = a.author_id`
+
+func scanAnnotation(row pgx.Row) (*models.PostAnnotation, error) {
+	a := &models.PostAnnotation{Author: &models.UserResponse{}}
+	err := row.Scan(&a.ID, &a.PostID, &a.ParentID, &a.AuthorID, &a.Body, &a.BlockID, &a.RangeStart, &a.RangeEnd,
+		&a.Quote, &a.MentionIDs, &a.ResolvedAt, &a.ResolvedBy, &a.CreatedAt, &a.UpdatedAt,
+		&a.Author.ID, &a.Author.Email, &a.Author.FullName, &a.Author.Role, &a.Author.IsActive,
+		&a.Author.CreatedAt, &a.Author.UpdatedAt)
+	return a, err
+}
+
+// Create adds an annotation mentioning mentionIDs
+func (r *AnnotationRepository) Create(ctx context.Context, postID uuid.UUID, req *models.CreateAnnotationRequest, mentionIDs []uuid.UUID) (*models.PostAnnotation, error) {
+	id := uuid.New()
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO post_annotations (id, post_id, parent_id, author_id, body, block_id, range_start, range_end, quote, mention_ids)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, NULLIF($9, ''), $10)`,
+		id, postID, req.ParentID, req.AuthorID, req.Body, req.BlockID, req.RangeStart, req.RangeEnd, req.Quote, nonNilUUIDs(mentionIDs),
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to create annotation: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *AnnotationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PostAnnotation, error) {
+	a, err := scanAnnotation(r.db.QueryRow(ctx, annotationSelect+` WHERE a.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get annotation: %w", err)
+	}
+	return a, nil
+}
+
+// ListThreads returns a post's threads, oldest first, each with its replies
+func (r *AnnotationRepository) ListThreads(ctx context.Context, filter models.AnnotationFilter) ([]models.PostAnnotation, error) {
+	rows, err := r.db.Query(ctx, annotationSelect+`
+		WHERE a.post_id = $1
+		ORDER BY a.created_at, a.id`, filter.PostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var roots []*models.PostAnnotation
+	replies := make(map[uuid.UUID][]models.PostAnnotation)
+	for rows.Next() {
+		a, err := scanAnnotation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		if a.ParentID != nil {
+			replies[*a.ParentID] = append(replies[*a.ParentID], *a)
+			continue
+		}
+		roots = append(roots, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+
+	threads := []models.PostAnnotation{}
+	for _, root := range roots {
+		if filter.Resolved != nil && (root.ResolvedAt != nil) != *filter.Resolved {
+			continue
+		}
+		root.Replies = replies[root.ID]
+		threads = append(threads, *root)
+	}
+	return threads, nil
+}
+
+// UpdateBody replaces an annotation's text and mentions
+func (r *AnnotationRepository) UpdateBody(ctx context.Context, id uuid.UUID, body string, mentionIDs []uuid.UUID) (*models.PostAnnotation, error) {
+	result, err := r.db.Exec(ctx, `UPDATE post_annotations SET body = $1, mention_ids = $2 WHERE id = $3`,
+		body, nonNilUUIDs(mentionIDs), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update annotation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// SetResolved resolves a thread as userID, or reopens it when userID is nil
+func (r *AnnotationRepository) SetResolved(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*models.PostAnnotation, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE post_annotations
+		SET resolved_at = CASE WHEN $1::uuid IS NULL THEN NULL ELSE CURRENT_TIMESTAMP END,
+		    resolved_by = $1
+		WHERE id = $2 AND parent_id IS NULL`, userID, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to resolve annotation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes an annotation and, for a top-level one, its replies
+func (r *AnnotationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM post_annotations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
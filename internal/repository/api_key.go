@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognize
+const APIKeyPrefix = "cak_"
+
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// apiKeySortColumns are the columns list results can be sorted by
+var apiKeySortColumns = []string{"name", "created_at", "last_used_at", "expires_at"}
+
+const apiKeyColumns = `id, user_id, name, key_prefix, scope, expires_at, last_used_at, created_at`
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	k := &models.APIKey{}
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.KeyPrefix, &k.Scope, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+	return k, err
+}
+
+// HashAPIKey returns the stored form of a key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create generates a key for a user and stores its hash. The key itself is
+// only returned here.
+func (r *APIKeyRepository) Create(ctx context.Context, userID uuid.UUID, req *models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := APIKeyPrefix + hex.EncodeToString(secret)
+
+	query := fmt.Sprintf(`
+		INSERT INTO api_keys (id, user_id, name, key_hash, key_prefix, scope, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING %s`, apiKeyColumns)
+
+	k, err := scanAPIKey(r.db.QueryRow(ctx, query,
+		uuid.New(), userID, req.Name, HashAPIKey(key), key[:len(APIKeyPrefix)+4], req.Scope, req.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return &models.CreatedAPIKey{APIKey: *k, Key: key}, nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return k, nil
+}
+
+// GetByKey looks a key up by its secret, whether expired or not
+func (r *APIKeyRepository) GetByKey(ctx context.Context, key string) (*models.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRow(ctx,
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", HashAPIKey(key)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return k, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context, filter models.APIKeyFilter) ([]models.APIKey, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.UserID != nil {
+		cb.addf("user_id = %s", *filter.UserID)
+	}
+	if filter.Search != "" {
+		cb.addf("(name ILIKE %[1]s OR key_prefix ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM api_keys %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count API keys: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", apiKeySortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM api_keys
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		apiKeyColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+
+	return keys, total, nil
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM api_keys WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Touch records that a key was used, at most once a minute per key
+func (r *APIKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type APIUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIUsageRepository(db *pgxpool.Pool) *APIUsageRepository {
+	return &APIUsageRepository{db: db}
+}
+
+// APIUsageDelta is a batch of calls to add to the daily rollup
+type APIUsageDelta struct {
+	Day        time.Time
+	Client     string
+	Method     string
+	Route      string
+	Count      int64
+	LastCallAt time.Time
+}
+
+// AddDeltas adds buffered call counts to the daily rollups in one round trip
+func (r *APIUsageRepository) AddDeltas(ctx context.Context, deltas []APIUsageDelta) error {
+	batch := &pgx.Batch{}
+	for _, d := range deltas {
+		batch.Queue(`
+			INSERT INTO api_usage_daily (day, client, method, route, count, last_call_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (day, client, method, route) DO UPDATE
+			SET count = api_usage_daily.count + EXCLUDED.count,
+			    last_call_at = GREATEST(api_usage_daily.last_call_at, EXCLUDED.last_call_at)`,
+			d.Day, d.Client, d.Method, d.Route, d.Count, d.LastCallAt,
+		)
+	}
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to record api usage: %w", err)
+	}
+	return nil
+}
+
+// List aggregates usage per client and route over the filtered days,
+// busiest first
+func (r *APIUsageRepository) List(ctx context.Context, filter models.APIUsageFilter) ([]models.APIUsage, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.From != nil {
+		cb.addf("day >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		cb.addf("day <= %s", *filter.To)
+	}
+	if filter.Client != "" {
+		cb.addf("client = %s", filter.Client)
+	}
+	if filter.Route != "" {
+		cb.addf("route ILIKE %s", "%"+filter.Route+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*) FROM (
+			SELECT 1 FROM api_usage_daily %s GROUP BY client, method, route
+		) groups`, whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count api usage: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT client, method, route, SUM(count), MAX(last_call_at)
+		FROM api_usage_daily
+		%s
+		GROUP BY client, method, route
+		ORDER BY SUM(count) DESC, client, route
+		LIMIT $%d OFFSET $%d`,
+		whereClause, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list api usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []models.APIUsage
+	for rows.Next() {
+		var u models.APIUsage
+		if err := rows.Scan(&u.Client, &u.Method, &u.Route, &u.Count, &u.LastCallAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan api usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, total, nil
+}
+
+// Prune deletes daily rollups older than before
+func (r *APIUsageRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, "DELETE FROM api_usage_daily WHERE day < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune api usage: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ApprovalRepository stores approval chains, the requests going through
+// them and the approval roles users hold
+type ApprovalRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewApprovalRepository(db *pgxpool.Pool) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+// GetChain returns the approval chain of a content type
+func (r *ApprovalRepository) GetChain(ctx context.Context, contentTypeID uuid.UUID) (*models.ApprovalChain, error) {
+	chain := &models.ApprovalChain{}
+	err := r.db.QueryRow(ctx, `
+		SELECT content_type_id, steps, created_at, updated_at
+		FROM approval_chains
+		WHERE content_type_id = $1`, contentTypeID,
+	).Scan(&chain.ContentTypeID, &chain.Steps, &chain.CreatedAt, &chain.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval chain: %w", err)
+	}
+	return chain, nil
+}
+
+// SetChain creates or replaces the approval chain of a content type.
+// Requests already submitted keep the steps they were submitted with.
+func (r *ApprovalRepository) SetChain(ctx context.Context, contentTypeID uuid.UUID, steps []models.ApprovalStep) (*models.ApprovalChain, error) {
+	chain := &models.ApprovalChain{}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO approval_chains (content_type_id, steps)
+		VALUES ($1, $2)
+		ON CONFLICT (content_type_id) DO UPDATE SET steps = EXCLUDED.steps
+		RETURNING content_type_id, steps, created_at, updated_at`,
+		contentTypeID, steps,
+	).Scan(&chain.ContentTypeID, &chain.Steps, &chain.CreatedAt, &chain.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to set approval chain: %w", err)
+	}
+	return chain, nil
+}
+
+// DeleteChain removes the approval chain of a content type
+func (r *ApprovalRepository) DeleteChain(ctx context.Context, contentTypeID uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM approval_chains WHERE content_type_id = $1`, contentTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete approval chain: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// publishApproved is the condition a content_posts row must meet to be
+// published: its content type has no approval chain, or the post's latest
+// approval request was approved
+var publishApproved = fmt.Sprintf(`(
+	NOT EXISTS (SELECT 1 FROM approval_chains ac WHERE ac.content_type_id = content_posts.content_type_id)
+	OR COALESCE((SELECT ar.status FROM approval_requests ar WHERE ar.post_id = content_posts.id
+	             ORDER BY ar.created_at DESC LIMIT 1) = %d, false))`, models.ApprovalStatusApproved)
+
+// HasChain reports whether a content type has an approval chain
+func (r *ApprovalRepository) HasChain(ctx context.Context, contentTypeID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM approval_chains WHERE content_type_id = $1)`, contentTypeID).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check approval chain: %w", err)
+	}
+	return ok, nil
+}
+
+// CanPublish reports whether a post may be published: its content type has
+// no approval chain, or its latest approval request was approved
+func (r *ApprovalRepository) CanPublish(ctx context.Context, postID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `SELECT `+publishApproved+` FROM content_posts WHERE id = $1`, postID).Scan(&ok)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, fmt.Errorf("failed to check approval: %w", err)
+	}
+	return ok, nil
+}
+
+const approvalRequestSelect = `
+	SELECT a.id, a.post_id, p.title, a.steps, a.step, a.status, a.requested_by,
+	       a.step_started_at, a.escalated_at, a.completed_at, a.created_at, a.updated_at
+	FROM approval_requests a
+	JOIN content_posts p ON p.id = a.post_id`
+
+const approvalRequestReturning = `
+	RETURNING id, post_id, (SELECT title FROM content_posts WHERE id = a.post_id), steps, step, status, requested_by,
+	          step_started_at, escalated_at, completed_at, created_at, updated_at`
+
+func scanApprovalRequest(row pgx.Row) (*models.ApprovalRequest, error) {
+	a := &models.ApprovalRequest{}
+	var step int16
+	err := row.Scan(&a.ID, &a.PostID, &a.PostTitle, &a.Steps, &step, &a.Status, &a.RequestedBy,
+		&a.StepStartedAt, &a.EscalatedAt, &a.CompletedAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.Step = int(step)
+	a.Fill()
+	return a, nil
+}
+
+// Submit starts an approval request for a post at the first of steps. A
+// post has at most one pending request; submitting another returns
+// ErrDuplicate.
+func (r *ApprovalRepository) Submit(ctx context.Context, postID uuid.UUID, steps []models.ApprovalStep, requestedBy *uuid.UUID) (*models.ApprovalRequest, error) {
+	id := uuid.New()
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO approval_requests (id, post_id, steps, requested_by)
+		VALUES ($1, $2, $3, $4)`,
+		id, postID, steps, requestedBy,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, ErrDuplicate
+			case "23503":
+				return nil, ErrForeignKey
+			}
+		}
+		return nil, fmt.Errorf("failed to submit approval request: %w", err)
+	}
+	return r.GetRequest(ctx, id)
+}
+
+// GetRequest returns an approval request with its decisions
+func (r *ApprovalRepository) GetRequest(ctx context.Context, id uuid.UUID) (*models.ApprovalRequest, error) {
+	a, err := scanApprovalRequest(r.db.QueryRow(ctx, approvalRequestSelect+` WHERE a.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval request: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, request_id, step, step_name, verdict, user_id, comment, created_at
+		FROM approval_decisions
+		WHERE request_id = $1
+		ORDER BY created_at, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval decisions: %w", err)
+	}
+	defer rows.Close()
+
+	a.Decisions = []models.ApprovalDecision{}
+	for rows.Next() {
+		var d models.ApprovalDecision
+		var step int16
+		if err := rows.Scan(&d.ID, &d.RequestID, &step, &d.StepName, &d.Verdict, &d.UserID, &d.Comment, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan approval decision: %w", err)
+		}
+		d.Step = int(step)
+		a.Decisions = append(a.Decisions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list approval decisions: %w", err)
+	}
+	return a, nil
+}
+
+// ListForPost returns the approval requests of a post, newest first
+func (r *ApprovalRepository) ListForPost(ctx context.Context, postID uuid.UUID) ([]models.ApprovalRequest, error) {
+	rows, err := r.db.Query(ctx, approvalRequestSelect+`
+		WHERE a.post_id = $1
+		ORDER BY a.created_at DESC`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.ApprovalRequest{}
+	for rows.Next() {
+		a, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval request: %w", err)
+		}
+		requests = append(requests, *a)
+	}
+	return requests, rows.Err()
+}
+
+// Queue returns the pending requests awaiting a decision from any of
+// filter.Roles, longest waiting first. A step waits on its role and, once
+// escalated, on its escalation role too.
+func (r *ApprovalRepository) Queue(ctx context.Context, filter models.ApprovalQueueFilter) ([]models.ApprovalRequest, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	where := `WHERE a.status = 1`
+	args := []interface{}{}
+	if filter.Roles != nil {
+		where += ` AND (a.steps->a.step->>'role' = ANY($1)
+			OR (a.escalated_at IS NOT NULL AND a.steps->a.step->>'escalate_to' = ANY($1)))`
+		args = append(args, filter.Roles)
+	}
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM approval_requests a `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count approval requests: %w", err)
+	}
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, fmt.Sprintf(approvalRequestSelect+`
+		%s
+		ORDER BY a.step_started_at, a.id
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list approval queue: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.ApprovalRequest{}
+	for rows.Next() {
+		a, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan approval request: %w", err)
+		}
+		requests = append(requests, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list approval queue: %w", err)
+	}
+	return requests, total, nil
+}
+
+// Decide records a verdict on step of a pending request. Approving the last
+// step approves the request, approving an earlier one moves it to the next
+// step and rejecting any step rejects it. ErrInvalidInput is returned when
+// the request is no longer pending at step, e.g. because someone else
+// decided it first.
+func (r *ApprovalRepository) Decide(ctx context.Context, id uuid.UUID, step int, verdict models.ApprovalVerdict, userID *uuid.UUID, comment *string) (*models.ApprovalRequest, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := scanApprovalRequest(tx.QueryRow(ctx, approvalRequestSelect+` WHERE a.id = $1 FOR UPDATE OF a`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get approval request: %w", err)
+	}
+	if current.Status != models.ApprovalStatusPending || current.Step != step || step >= len(current.Steps) {
+		return nil, ErrInvalidInput
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO approval_decisions (id, request_id, step, step_name, verdict, user_id, comment)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))`,
+		uuid.New(), id, step, current.Steps[step].Name, verdict, userID, comment,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to record approval decision: %w", err)
+	}
+
+	switch {
+	case verdict == models.ApprovalVerdictRejected:
+		_, err = tx.Exec(ctx, `UPDATE approval_requests SET status = 3, completed_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	case step+1 < len(current.Steps):
+		_, err = tx.Exec(ctx, `
+			UPDATE approval_requests
+			SET step = step + 1, step_started_at = CURRENT_TIMESTAMP, escalated_at = NULL
+			WHERE id = $1`, id)
+	default:
+		_, err = tx.Exec(ctx, `UPDATE approval_requests SET status = 2, completed_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update approval request: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetRequest(ctx, id)
+}
+
+// Cancel withdraws a pending request; ErrInvalidInput is returned when it
+// is no longer pending
+func (r *ApprovalRepository) Cancel(ctx context.Context, id uuid.UUID) (*models.ApprovalRequest, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE approval_requests SET status = 4, completed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel approval request: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		if _, err := r.GetRequest(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidInput
+	}
+	return r.GetRequest(ctx, id)
+}
+
+// EscalateDue marks the pending requests whose current step has been
+// waiting longer than its SLA as escalated and returns them
+func (r *ApprovalRepository) EscalateDue(ctx context.Context) ([]models.ApprovalRequest, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE approval_requests a
+		SET escalated_at = CURRENT_TIMESTAMP
+		WHERE a.status = 1 AND a.escalated_at IS NULL
+		  AND COALESCE((a.steps->a.step->>'sla_hours')::INT, 0) > 0
+		  AND a.step_started_at + make_interval(hours => (a.steps->a.step->>'sla_hours')::INT) <= CURRENT_TIMESTAMP
+		`+approvalRequestReturning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to escalate approval requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.ApprovalRequest{}
+	for rows.Next() {
+		a, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval request: %w", err)
+		}
+		requests = append(requests, *a)
+	}
+	return requests, rows.Err()
+}
+
+// GetUserRoles returns the approval roles a user holds, sorted
+func (r *ApprovalRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT role FROM user_approval_roles WHERE user_id = $1 ORDER BY role`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan approval role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// SetUserRoles replaces the approval roles a user holds
+func (r *ApprovalRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roles []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_approval_roles WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to clear approval roles: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_approval_roles (user_id, role)
+		SELECT $1, UNNEST($2::TEXT[])
+		ON CONFLICT DO NOTHING`, userID, roles)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to set approval roles: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
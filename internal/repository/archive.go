@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ArchiveTables are the tables old rows are archived from, with the
+// condition rows must also meet. New contact submissions stay until
+// someone has read them.
+var ArchiveTables = map[string]string{
+	"contact_submissions": fmt.Sprintf("status <> %d", models.ContactStatusNew),
+	"access_log":          "TRUE",
+}
+
+// ArchiveRepository reads old rows as JSON and deletes them once archived
+type ArchiveRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewArchiveRepository(db *pgxpool.Pool) *ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// ListOlder returns up to limit rows of table created before cutoff, oldest
+// first, each as a JSON object of its columns
+func (r *ArchiveRepository) ListOlder(ctx context.Context, table string, before time.Time, limit int) ([]models.ArchivedRow, error) {
+	cond, ok := ArchiveTables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not archived", table)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, row_to_json(t)
+		FROM %s t
+		WHERE t.created_at < $1 AND %s
+		ORDER BY t.created_at, t.id
+		LIMIT $2`, table, cond)
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s rows to archive: %w", table, err)
+	}
+	defer rows.Close()
+
+	var out []models.ArchivedRow
+	for rows.Next() {
+		var row models.ArchivedRow
+		var data []byte
+		if err := rows.Scan(&row.ID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		row.Data = json.RawMessage(data)
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// Delete removes archived rows from table
+func (r *ArchiveRepository) Delete(ctx context.Context, table string, ids []uuid.UUID) (int64, error) {
+	if _, ok := ArchiveTables[table]; !ok {
+		return 0, fmt.Errorf("table %s is not archived", table)
+	}
+	result, err := r.db.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", table), ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived %s rows: %w", table, err)
+	}
+	return result.RowsAffected(), nil
+}
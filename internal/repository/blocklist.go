@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type BlocklistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBlocklistRepository(db *pgxpool.Pool) *BlocklistRepository {
+	return &BlocklistRepository{db: db}
+}
+
+// blocklistSortColumns are the columns list results can be sorted by
+var blocklistSortColumns = []string{"created_at", "rule_type", "value", "action", "hit_count", "last_hit_at"}
+
+func (r *BlocklistRepository) Create(ctx context.Context, req *models.CreateBlocklistRuleRequest) (*models.BlocklistRule, error) {
+	rule := &models.BlocklistRule{
+		ID:       uuid.New(),
+		RuleType: req.RuleType,
+		Value:    req.Value,
+		Action:   models.BlocklistActionReject,
+		Reason:   req.Reason,
+	}
+
+	if req.Action != nil {
+		rule.Action = *req.Action
+	}
+
+	query := `
+		INSERT INTO blocklist_rules (id, rule_type, value, action, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING hit_count, created_at`
+
+	err := r.db.QueryRow(ctx, query, rule.ID, rule.RuleType, rule.Value, rule.Action, rule.Reason).
+		Scan(&rule.HitCount, &rule.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create blocklist rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *BlocklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BlocklistRule, error) {
+	query := `
+		SELECT id, rule_type, value, action, reason, hit_count, last_hit_at, created_at
+		FROM blocklist_rules
+		WHERE id = $1`
+
+	rule := &models.BlocklistRule{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&rule.ID, &rule.RuleType, &rule.Value, &rule.Action, &rule.Reason,
+		&rule.HitCount, &rule.LastHitAt, &rule.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get blocklist rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *BlocklistRepository) List(ctx context.Context, filter models.BlocklistFilter) ([]models.BlocklistRule, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.RuleType != nil {
+		cb.addf("rule_type = %s", *filter.RuleType)
+	}
+	if filter.Search != "" {
+		cb.addf("(value ILIKE %[1]s OR reason ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM blocklist_rules %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count blocklist rules: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", blocklistSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, rule_type, value, action, reason, hit_count, last_hit_at, created_at
+		FROM blocklist_rules
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list blocklist rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.BlocklistRule
+	for rows.Next() {
+		var rule models.BlocklistRule
+		if err := rows.Scan(
+			&rule.ID, &rule.RuleType, &rule.Value, &rule.Action, &rule.Reason,
+			&rule.HitCount, &rule.LastHitAt, &rule.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan blocklist rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, total, nil
+}
+
+func (r *BlocklistRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateBlocklistRuleRequest) (*models.BlocklistRule, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if req.Value != nil {
+		setClauses = append(setClauses, fmt.Sprintf("value = $%d", argNum))
+		args = append(args, *req.Value)
+		argNum++
+	}
+	if req.Action != nil {
+		setClauses = append(setClauses, fmt.Sprintf("action = $%d", argNum))
+		args = append(args, *req.Action)
+		argNum++
+	}
+	if req.Reason != nil {
+		setClauses = append(setClauses, fmt.Sprintf("reason = $%d", argNum))
+		args = append(args, *req.Reason)
+		argNum++
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`
+		UPDATE blocklist_rules
+		SET %s
+		WHERE id = $%d
+		RETURNING id, rule_type, value, action, reason, hit_count, last_hit_at, created_at`,
+		strings.Join(setClauses, ", "), argNum)
+
+	rule := &models.BlocklistRule{}
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&rule.ID, &rule.RuleType, &rule.Value, &rule.Action, &rule.Reason,
+		&rule.HitCount, &rule.LastHitAt, &rule.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to update blocklist rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *BlocklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM blocklist_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blocklist rule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Match finds the first rule blocking the given IP or email and records the hit.
+// Reject rules take precedence over discard rules. Returns ErrNotFound when
+// nothing matches.
+func (r *BlocklistRepository) Match(ctx context.Context, ip, email string) (*models.BlocklistRule, error) {
+	var ipArg interface{}
+	if ip != "" {
+		ipArg = ip
+	}
+
+	query := `
+		UPDATE blocklist_rules
+		SET hit_count = hit_count + 1, last_hit_at = NOW()
+		WHERE id = (
+			SELECT id FROM blocklist_rules
+			WHERE (rule_type = 1 AND $1::inet IS NOT NULL AND $1::inet <<= value::inet)
+			   OR (rule_type = 2 AND lower(split_part($2, '@', 2)) = lower(value))
+			   OR (rule_type = 3 AND $2 ILIKE replace(replace(value, '%', '\%'), '*', '%'))
+			ORDER BY action, created_at
+			LIMIT 1
+		)
+		RETURNING id, rule_type, value, action, reason, hit_count, last_hit_at, created_at`
+
+	rule := &models.BlocklistRule{}
+	err := r.db.QueryRow(ctx, query, ipArg, email).Scan(
+		&rule.ID, &rule.RuleType, &rule.Value, &rule.Action, &rule.Reason,
+		&rule.HitCount, &rule.LastHitAt, &rule.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to match blocklist rules: %w", err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// CacheHintRepository keeps the caching hints of content types and posts
+type CacheHintRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCacheHintRepository(db *pgxpool.Pool) *CacheHintRepository {
+	return &CacheHintRepository{db: db}
+}
+
+// cacheHintOwner is the table of the cache hints of one kind of owner, its
+// key column and how the owner's ID is set on the hints
+type cacheHintOwner struct {
+	table  string
+	column string
+	own    func(h *models.CacheHints, id uuid.UUID)
+}
+
+var (
+	contentTypeCacheHints = cacheHintOwner{
+		table:  "content_type_cache_hints",
+		column: "content_type_id",
+		own:    func(h *models.CacheHints, id uuid.UUID) { h.ContentTypeID = &id },
+	}
+	postCacheHints = cacheHintOwner{
+		table:  "post_cache_hints",
+		column: "post_id",
+		own:    func(h *models.CacheHints, id uuid.UUID) { h.PostID = &id },
+	}
+)
+
+// GetForContentType returns the cache hints of a content type
+func (r *CacheHintRepository) GetForContentType(ctx context.Context, contentTypeID uuid.UUID) (*models.CacheHints, error) {
+	return r.get(ctx, contentTypeCacheHints, contentTypeID)
+}
+
+// SetForContentType creates or replaces the cache hints of a content type
+func (r *CacheHintRepository) SetForContentType(ctx context.Context, contentTypeID uuid.UUID, req *models.SetCacheHintsRequest) (*models.CacheHints, error) {
+	return r.set(ctx, contentTypeCacheHints, contentTypeID, req)
+}
+
+// DeleteForContentType removes the cache hints of a content type
+func (r *CacheHintRepository) DeleteForContentType(ctx context.Context, contentTypeID uuid.UUID) error {
+	return r.delete(ctx, contentTypeCacheHints, contentTypeID)
+}
+
+// GetForPost returns the cache hints set on a post itself
+func (r *CacheHintRepository) GetForPost(ctx context.Context, postID uuid.UUID) (*models.CacheHints, error) {
+	return r.get(ctx, postCacheHints, postID)
+}
+
+// SetForPost creates or replaces the cache hints of a post
+func (r *CacheHintRepository) SetForPost(ctx context.Context, postID uuid.UUID, req *models.SetCacheHintsRequest) (*models.CacheHints, error) {
+	return r.set(ctx, postCacheHints, postID, req)
+}
+
+// DeleteForPost removes the cache hints of a post
+func (r *CacheHintRepository) DeleteForPost(ctx context.Context, postID uuid.UUID) error {
+	return r.delete(ctx, postCacheHints, postID)
+}
+
+// Resolve returns the cache hints that apply to a post: its own ages, else
+// its content type's, and the surrogate keys of both. Hints are empty when
+// neither has any.
+func (r *CacheHintRepository) Resolve(ctx context.Context, postID, contentTypeID uuid.UUID) (*models.CacheHints, error) {
+	h := &models.CacheHints{}
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(p.max_age, t.max_age), COALESCE(p.s_maxage, t.s_maxage),
+		       COALESCE(t.surrogate_keys, '{}') || COALESCE(p.surrogate_keys, '{}')
+		FROM (SELECT 1) one
+		LEFT JOIN post_cache_hints p ON p.post_id = $1
+		LEFT JOIN content_type_cache_hints t ON t.content_type_id = $2`,
+		postID, contentTypeID,
+	).Scan(&h.MaxAge, &h.SharedMaxAge, &h.SurrogateKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cache hints: %w", err)
+	}
+	return h, nil
+}
+
+func (r *CacheHintRepository) get(ctx context.Context, owner cacheHintOwner, id uuid.UUID) (*models.CacheHints, error) {
+	h := &models.CacheHints{}
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT max_age, s_maxage, surrogate_keys, created_at, updated_at
+		FROM %s
+		WHERE %s = $1`, owner.table, owner.column), id,
+	).Scan(&h.MaxAge, &h.SharedMaxAge, &h.SurrogateKeys, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get cache hints: %w", err)
+	}
+	owner.own(h, id)
+	return h, nil
+}
+
+func (r *CacheHintRepository) set(ctx context.Context, owner cacheHintOwner, id uuid.UUID, req *models.SetCacheHintsRequest) (*models.CacheHints, error) {
+	keys := req.SurrogateKeys
+	if keys == nil {
+		keys = []string{}
+	}
+
+	h := &models.CacheHints{}
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, max_age, s_maxage, surrogate_keys)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (%[2]s) DO UPDATE
+		SET max_age = EXCLUDED.max_age, s_maxage = EXCLUDED.s_maxage, surrogate_keys = EXCLUDED.surrogate_keys
+		RETURNING max_age, s_maxage, surrogate_keys, created_at, updated_at`, owner.table, owner.column),
+		id, req.MaxAge, req.SharedMaxAge, keys,
+	).Scan(&h.MaxAge, &h.SharedMaxAge, &h.SurrogateKeys, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to set cache hints: %w", err)
+	}
+	owner.own(h, id)
+	return h, nil
+}
+
+func (r *CacheHintRepository) delete(ctx context.Context, owner cacheHintOwner, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, owner.table, owner.column), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete cache hints: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type CampaignRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCampaignRepository(db *pgxpool.Pool) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// campaignSortColumns are the columns list results can be sorted by
+var campaignSortColumns = []string{"created_at", "name", "status", "next_batch_at", "completed_at"}
+
+const campaignColumns = `id, name, subject, intro, template_name, post_ids, status, queued_count,
+		       last_subscriber_id, next_batch_at, completed_at, created_at`
+
+func scanCampaign(row pgx.Row) (*models.Campaign, error) {
+	c := &models.Campaign{}
+	err := row.Scan(
+		&c.ID, &c.Name, &c.Subject, &c.Intro, &c.TemplateName, &c.PostIDs, &c.Status, &c.QueuedCount,
+		&c.LastSubscriber, &c.NextBatchAt, &c.CompletedAt, &c.CreatedAt,
+	)
+	return c, err
+}
+
+func (r *CampaignRepository) Create(ctx context.Context, req *models.CreateCampaignRequest) (*models.Campaign, error) {
+	sendAt := time.Now()
+	if req.SendAt != nil {
+		sendAt = *req.SendAt
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO campaigns (id, name, subject, intro, template_name, post_ids, status, next_batch_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING %s`, campaignColumns)
+
+	campaign, err := scanCampaign(r.db.QueryRow(ctx, query,
+		uuid.New(), req.Name, req.Subject, req.Intro, req.Template, req.PostIDs,
+		models.CampaignStatusSending, sendAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+func (r *CampaignRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	query := fmt.Sprintf(`SELECT %s FROM campaigns WHERE id = $1`, campaignColumns)
+
+	campaign, err := scanCampaign(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+func (r *CampaignRepository) List(ctx context.Context, filter models.CampaignFilter) ([]models.Campaign, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Status != nil {
+		cb.addf("status = %s", *filter.Status)
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM campaigns %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", campaignSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM campaigns
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		campaignColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []models.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan campaign: %w", err)
+		}
+		campaigns = append(campaigns, *campaign)
+	}
+
+	return campaigns, total, nil
+}
+
+// ClaimDue picks one sending campaign whose next batch is due and pushes its
+// next batch time back by interval, so each batch is handled by one worker
+// and batches are spaced out
+func (r *CampaignRepository) ClaimDue(ctx context.Context, interval time.Duration) (*models.Campaign, error) {
+	query := fmt.Sprintf(`
+		UPDATE campaigns
+		SET next_batch_at = $1
+		WHERE id = (
+			SELECT id FROM campaigns
+			WHERE status = $2 AND next_batch_at <= NOW()
+			ORDER BY next_batch_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, campaignColumns)
+
+	campaign, err := scanCampaign(r.db.QueryRow(ctx, query, time.Now().Add(interval), models.CampaignStatusSending))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to claim campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// AdvanceCursor records that recipients up to lastSubscriber were queued
+func (r *CampaignRepository) AdvanceCursor(ctx context.Context, id, lastSubscriber uuid.UUID, queued int) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE campaigns SET last_subscriber_id = $1, queued_count = queued_count + $2 WHERE id = $3`,
+		lastSubscriber, queued, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to advance campaign: %w", err)
+	}
+	return nil
+}
+
+// Complete marks a campaign whose recipients have all been queued as sent
+func (r *CampaignRepository) Complete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE campaigns SET status = $1, completed_at = NOW() WHERE id = $2 AND status = $3`,
+		models.CampaignStatusSent, id, models.CampaignStatusSending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete campaign: %w", err)
+	}
+	return nil
+}
+
+// Cancel stops a campaign that is still sending and drops its emails that
+// have not been delivered yet
+func (r *CampaignRepository) Cancel(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		UPDATE campaigns SET status = $1, completed_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING %s`, campaignColumns)
+
+	campaign, err := scanCampaign(tx.QueryRow(ctx, query, models.CampaignStatusCancelled, id, models.CampaignStatusSending))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to cancel campaign: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM email_queue WHERE campaign_id = $1 AND status = $2`,
+		id, models.EmailStatusPending,
+	); err != nil {
+		return nil, fmt.Errorf("failed to drop queued campaign emails: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// GetStats aggregates the delivery state of a campaign's emails
+func (r *CampaignRepository) GetStats(ctx context.Context, id uuid.UUID) (*models.CampaignStats, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE status IN ($2, $3)),
+		       COUNT(*) FILTER (WHERE status = $4),
+		       COUNT(*) FILTER (WHERE status = $5),
+		       COUNT(*) FILTER (WHERE opened_at IS NOT NULL)
+		FROM email_queue
+		WHERE campaign_id = $1`
+
+	stats := &models.CampaignStats{}
+	err := r.db.QueryRow(ctx, query, id,
+		models.EmailStatusPending, models.EmailStatusSending, models.EmailStatusSent, models.EmailStatusFailed,
+	).Scan(&stats.Pending, &stats.Sent, &stats.Failed, &stats.Opened)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	if stats.Sent > 0 {
+		stats.OpenRate = float64(stats.Opened) / float64(stats.Sent)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type CategoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// categorySortColumns are the columns list results can be sorted by
+var categorySortColumns = []string{"created_at", "name", "slug", "display_order"}
+
+const categoryColumns = `id, parent_id, name, slug, description, display_order, created_at, updated_at`
+
+// categorySubtree returns a query selecting the IDs of the category whose ID
+// is bound to placeholder and of all its descendants
+func categorySubtree(placeholder string) string {
+	return fmt.Sprintf(`WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = %s
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`, placeholder)
+}
+
+func scanCategory(row pgx.Row) (*models.Category, error) {
+	c := &models.Category{}
+	err := row.Scan(&c.ID, &c.ParentID, &c.Name, &c.Slug, &c.Description, &c.DisplayOrder, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// categoryWriteError maps constraint violations of inserts and updates
+func categoryWriteError(err error, action string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrDuplicate
+		case "23503":
+			return ErrForeignKey
+		}
+	}
+	return fmt.Errorf("failed to %s category: %w", action, err)
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, req *models.CreateCategoryRequest) (*models.Category, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO categories (id, parent_id, name, slug, description, display_order)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING %s`, categoryColumns)
+
+	category, err := scanCategory(r.db.QueryRow(ctx, query,
+		uuid.New(), req.ParentID, req.Name, req.Slug, req.Description, req.DisplayOrder,
+	))
+	if err != nil {
+		return nil, categoryWriteError(err, "create")
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	query := fmt.Sprintf(`SELECT %s FROM categories WHERE id = $1`, categoryColumns)
+	category, err := scanCategory(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	query := fmt.Sprintf(`SELECT %s FROM categories WHERE slug = $1`, categoryColumns)
+	category, err := scanCategory(r.db.QueryRow(ctx, query, slug))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get category by slug: %w", err)
+	}
+	return category, nil
+}
+
+func (r *CategoryRepository) List(ctx context.Context, filter models.CategoryFilter) ([]models.Category, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.ParentID != nil {
+		cb.addf("parent_id = %s", *filter.ParentID)
+	}
+	if filter.Search != "" {
+		cb.addf("(name ILIKE %[1]s OR slug ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+	whereClause, args, argNum := cb.build()
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM categories %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count categories: %w", err)
+	}
+
+	orderBy := sortOrder(filter.PaginationParams, "display_order ASC, name ASC", "", categorySortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM categories
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		categoryColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	return categories, total, rows.Err()
+}
+
+// Tree returns the top-level categories with their subcategories nested
+// under Children, or only the subtree of rootID when it is set. Siblings
+// are ordered by display order, then name.
+func (r *CategoryRepository) Tree(ctx context.Context, rootID *uuid.UUID) ([]models.Category, error) {
+	// Without a root every category is part of the tree
+	where := ""
+	var args []interface{}
+	if rootID != nil {
+		where = fmt.Sprintf("WHERE id IN (%s)", categorySubtree("$1"))
+		args = append(args, *rootID)
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM categories
+		%s
+		ORDER BY display_order, name`,
+		categoryColumns, where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category tree: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		roots    []models.Category
+		children = make(map[uuid.UUID][]models.Category)
+	)
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		isRoot := category.ParentID == nil
+		if rootID != nil {
+			isRoot = category.ID == *rootID
+		}
+		if isRoot {
+			roots = append(roots, *category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], *category)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get category tree: %w", err)
+	}
+	if rootID != nil && len(roots) == 0 {
+		return nil, ErrNotFound
+	}
+
+	var nest func(c *models.Category)
+	nest = func(c *models.Category) {
+		c.Children = children[c.ID]
+		for i := range c.Children {
+			nest(&c.Children[i])
+		}
+	}
+	for i := range roots {
+		nest(&roots[i])
+	}
+	return roots, nil
+}
+
+// Update changes a category. Moving a category under itself or one of its
+// descendants fails with ErrInvalidInput.
+func (r *CategoryRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateCategoryRequest) (*models.Category, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if req.ParentID != nil {
+		var parentID *uuid.UUID
+		if *req.ParentID != uuid.Nil {
+			parentID = req.ParentID
+		}
+		setClauses = append(setClauses, fmt.Sprintf("parent_id = $%d", argNum))
+		args = append(args, parentID)
+		argNum++
+	}
+
+	if req.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argNum))
+		args = append(args, *req.Name)
+		argNum++
+	}
+
+	if req.Slug != nil {
+		setClauses = append(setClauses, fmt.Sprintf("slug = $%d", argNum))
+		args = append(args, *req.Slug)
+		argNum++
+	}
+
+	if req.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = NULLIF($%d, '')", argNum))
+		args = append(args, *req.Description)
+		argNum++
+	}
+
+	if req.DisplayOrder != nil {
+		setClauses = append(setClauses, fmt.Sprintf("display_order = $%d", argNum))
+		args = append(args, *req.DisplayOrder)
+		argNum++
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if req.ParentID != nil && *req.ParentID != uuid.Nil {
+		// Serialize moves so that two concurrent moves can't form a cycle
+		if _, err := tx.Exec(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return nil, fmt.Errorf("failed to lock categories: %w", err)
+		}
+		var cycle bool
+		err := tx.QueryRow(ctx,
+			fmt.Sprintf(`SELECT $2 IN (%s)`, categorySubtree("$1")),
+			id, *req.ParentID,
+		).Scan(&cycle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check category parent: %w", err)
+		}
+		if cycle {
+			return nil, ErrInvalidInput
+		}
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`
+		UPDATE categories
+		SET %s
+		WHERE id = $%d
+		RETURNING %s`,
+		strings.Join(setClauses, ", "), argNum, categoryColumns)
+
+	category, err := scanCategory(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, categoryWriteError(err, "update")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return category, nil
+}
+
+// Delete removes a category and its post assignments. Categories with
+// subcategories fail with ErrForeignKey.
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrForeignKey
+		}
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ChangeFeedRepository reads row changes from a wal2json logical decoding
+// slot and broadcasts them with NOTIFY. The database needs wal_level =
+// logical, the wal2json plugin and a role allowed to use replication slots.
+type ChangeFeedRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChangeFeedRepository(db *pgxpool.Pool) *ChangeFeedRepository {
+	return &ChangeFeedRepository{db: db}
+}
+
+// EnsureSlot creates the logical decoding slot unless it exists
+func (r *ChangeFeedRepository) EnsureSlot(ctx context.Context, slot string) error {
+	_, err := r.db.Exec(ctx, `
+		SELECT pg_create_logical_replication_slot($1, 'wal2json')
+		WHERE NOT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)`,
+		slot)
+	if err != nil {
+		return fmt.Errorf("failed to create replication slot %s: %w", slot, err)
+	}
+	return nil
+}
+
+// PeekChanges returns about limit changes to tables (wal2json add-tables
+// format) without consuming them. Transaction boundaries are included, so
+// the last change's LSN also covers transactions that touched no watched
+// table.
+func (r *ChangeFeedRepository) PeekChanges(ctx context.Context, slot, tables string, limit int) ([]models.WALChange, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT lsn::text, data
+		FROM pg_logical_slot_peek_changes($1, NULL, $2,
+			'format-version', '2', 'include-transaction', 'true', 'add-tables', $3)`,
+		slot, limit, tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changes from %s: %w", slot, err)
+	}
+	defer rows.Close()
+
+	var changes []models.WALChange
+	for rows.Next() {
+		var c models.WALChange
+		if err := rows.Scan(&c.LSN, &c.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Notify sends payloads on channel in one transaction, so listeners get
+// all of them or none
+func (r *ChangeFeedRepository) Notify(ctx context.Context, channel string, payloads []string) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, payload := range payloads {
+		batch.Queue(`SELECT pg_notify($1, $2)`, channel, payload)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit notifications: %w", err)
+	}
+	return nil
+}
+
+// AdvanceSlot consumes the slot's changes up to lsn
+func (r *ChangeFeedRepository) AdvanceSlot(ctx context.Context, slot, lsn string) error {
+	if _, err := r.db.Exec(ctx, `SELECT pg_replication_slot_advance($1, $2::pg_lsn)`, slot, lsn); err != nil {
+		return fmt.Errorf("failed to advance replication slot %s: %w", slot, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type ConsentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewConsentRepository(db *pgxpool.Pool) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+func (r *ConsentRepository) Create(ctx context.Context, req *models.CreateConsentVersionRequest) (*models.ConsentVersion, error) {
+	cv := &models.ConsentVersion{
+		ID:          uuid.New(),
+		Version:     req.Version,
+		Title:       req.Title,
+		Content:     req.Content,
+		DocumentURL: req.DocumentURL,
+		PublishedAt: time.Now(),
+	}
+
+	if req.PublishedAt != nil {
+		cv.PublishedAt = *req.PublishedAt
+	}
+
+	query := `
+		INSERT INTO consent_versions (id, version, title, content, document_url, published_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`
+
+	err := r.db.QueryRow(ctx, query,
+		cv.ID, cv.Version, cv.Title, cv.Content, cv.DocumentURL, cv.PublishedAt,
+	).Scan(&cv.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create consent version: %w", err)
+	}
+
+	return cv, nil
+}
+
+func (r *ConsentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ConsentVersion, error) {
+	query := `
+		SELECT id, version, title, content, document_url, published_at, created_at
+		FROM consent_versions
+		WHERE id = $1`
+
+	cv := &models.ConsentVersion{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&cv.ID, &cv.Version, &cv.Title, &cv.Content, &cv.DocumentURL, &cv.PublishedAt, &cv.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get consent version: %w", err)
+	}
+
+	return cv, nil
+}
+
+// GetByVersion returns a consent version that has already been published
+func (r *ConsentRepository) GetByVersion(ctx context.Context, version string) (*models.ConsentVersion, error) {
+	query := `
+		SELECT id, version, title, content, document_url, published_at, created_at
+		FROM consent_versions
+		WHERE version = $1 AND published_at <= NOW()`
+
+	cv := &models.ConsentVersion{}
+	err := r.db.QueryRow(ctx, query, version).Scan(
+		&cv.ID, &cv.Version, &cv.Title, &cv.Content, &cv.DocumentURL, &cv.PublishedAt, &cv.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get consent version by version: %w", err)
+	}
+
+	return cv, nil
+}
+
+// GetCurrent returns the most recently published consent version
+func (r *ConsentRepository) GetCurrent(ctx context.Context) (*models.ConsentVersion, error) {
+	query := `
+		SELECT id, version, title, content, document_url, published_at, created_at
+		FROM consent_versions
+		WHERE published_at <= NOW()
+		ORDER BY published_at DESC
+		LIMIT 1`
+
+	cv := &models.ConsentVersion{}
+	err := r.db.QueryRow(ctx, query).Scan(
+		&cv.ID, &cv.Version, &cv.Title, &cv.Content, &cv.DocumentURL, &cv.PublishedAt, &cv.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get current consent version: %w", err)
+	}
+
+	return cv, nil
+}
+
+func (r *ConsentRepository) List(ctx context.Context, filter models.ConsentVersionFilter) ([]models.ConsentVersion, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM consent_versions").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count consent versions: %w", err)
+	}
+
+	query := `
+		SELECT id, version, title, content, document_url, published_at, created_at
+		FROM consent_versions
+		ORDER BY published_at DESC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(ctx, query, filter.Limit(), filter.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list consent versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []models.ConsentVersion
+	for rows.Next() {
+		var cv models.ConsentVersion
+		if err := rows.Scan(
+			&cv.ID, &cv.Version, &cv.Title, &cv.Content, &cv.DocumentURL, &cv.PublishedAt, &cv.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan consent version: %w", err)
+		}
+		versions = append(versions, cv)
+	}
+
+	return versions, total, nil
+}
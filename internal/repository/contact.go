@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keeps-dev/go-cms-template/internal/models"
 )
@@ -22,6 +23,9 @@ func NewContactRepository(db *pgxpool.Pool) *ContactRepository {
 	return &ContactRepository{db: db}
 }
 
+// contactSortColumns are the columns list results can be sorted by
+var contactSortColumns = []string{"created_at", "name", "email", "status", "read_at"}
+
 func (r *ContactRepository) Create(ctx context.Context, req *models.CreateContactRequest) (*models.ContactSubmission, error) {
 	var ipAddr *net.IP
 	if req.IPAddress != nil {
@@ -42,18 +46,31 @@ func (r *ContactRepository) Create(ctx context.Context, req *models.CreateContac
 		IPAddress: ipAddr,
 		UserAgent: req.UserAgent,
 		Metadata:  req.Metadata,
+
+		ConsentVersionID: req.ConsentVersionID,
+	}
+
+	if contact.ConsentVersionID != nil {
+		now := time.Now()
+		contact.ConsentAcceptedAt = &now
 	}
 
 	query := `
-		INSERT INTO contact_submissions (id, name, email, phone, subject, message, status, ip_address, user_agent, metadata)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO contact_submissions (id, name, email, phone, subject, message, status, ip_address, user_agent, metadata,
+		                                 consent_version_id, consent_accepted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING created_at`
 
 	err := r.db.QueryRow(ctx, query,
 		contact.ID, contact.Name, contact.Email, contact.Phone, contact.Subject,
 		contact.Message, contact.Status, contact.IPAddress, contact.UserAgent, contact.Metadata,
+		contact.ConsentVersionID, contact.ConsentAcceptedAt,
 	).Scan(&contact.CreatedAt)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
 		return nil, fmt.Errorf("failed to create contact submission: %w", err)
 	}
 
@@ -62,7 +79,8 @@ func (r *ContactRepository) Create(ctx context.Context, req *models.CreateContac
 
 func (r *ContactRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContactSubmission, error) {
 	query := `
-		SELECT id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at
+		SELECT id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at,
+		       consent_version_id, consent_accepted_at
 		FROM contact_submissions
 		WHERE id = $1`
 
@@ -71,6 +89,7 @@ func (r *ContactRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 		&contact.ID, &contact.Name, &contact.Email, &contact.Phone, &contact.Subject,
 		&contact.Message, &contact.Status, &contact.IPAddress, &contact.UserAgent,
 		&contact.Metadata, &contact.ReadAt, &contact.CreatedAt,
+		&contact.ConsentVersionID, &contact.ConsentAcceptedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -85,26 +104,7 @@ func (r *ContactRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 func (r *ContactRepository) List(ctx context.Context, filter models.ContactFilter) ([]models.ContactSubmission, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
-
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argNum))
-		args = append(args, *filter.Status)
-		argNum++
-	}
-
-	if filter.Email != "" {
-		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", argNum))
-		args = append(args, "%"+filter.Email+"%")
-		argNum++
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args, argNum := contactFilterConditions(filter)
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM contact_submissions %s", whereClause)
@@ -114,13 +114,11 @@ func (r *ContactRepository) List(ctx context.Context, filter models.ContactFilte
 	}
 
 	// Get data
-	orderBy := "created_at DESC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("%s %s", filter.SortBy, filter.SortDir)
-	}
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", contactSortColumns...)
 
 	query := fmt.Sprintf(`
-		SELECT id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at
+		SELECT id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at,
+		       consent_version_id, consent_accepted_at
 		FROM contact_submissions
 		%s
 		ORDER BY %s
@@ -141,6 +139,7 @@ func (r *ContactRepository) List(ctx context.Context, filter models.ContactFilte
 			&contact.ID, &contact.Name, &contact.Email, &contact.Phone, &contact.Subject,
 			&contact.Message, &contact.Status, &contact.IPAddress, &contact.UserAgent,
 			&contact.Metadata, &contact.ReadAt, &contact.CreatedAt,
+			&contact.ConsentVersionID, &contact.ConsentAcceptedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan contact submission: %w", err)
 		}
@@ -150,6 +149,46 @@ func (r *ContactRepository) List(ctx context.Context, filter models.ContactFilte
 	return contacts, total, nil
 }
 
+// ExportEach streams every contact submission matching the filter to fn, ignoring pagination
+func (r *ContactRepository) ExportEach(ctx context.Context, filter models.ContactFilter, fn func(*models.ContactSubmission) error) error {
+	filter.PaginationParams.Normalize()
+
+	whereClause, args, _ := contactFilterConditions(filter)
+
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", contactSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at,
+		       consent_version_id, consent_accepted_at
+		FROM contact_submissions
+		%s
+		ORDER BY %s`,
+		whereClause, orderBy)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export contact submissions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var contact models.ContactSubmission
+		if err := rows.Scan(
+			&contact.ID, &contact.Name, &contact.Email, &contact.Phone, &contact.Subject,
+			&contact.Message, &contact.Status, &contact.IPAddress, &contact.UserAgent,
+			&contact.Metadata, &contact.ReadAt, &contact.CreatedAt,
+			&contact.ConsentVersionID, &contact.ConsentAcceptedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan contact submission: %w", err)
+		}
+		if err := fn(&contact); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *ContactRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateContactRequest) (*models.ContactSubmission, error) {
 	var setClauses []string
 	var args []interface{}
@@ -176,7 +215,8 @@ func (r *ContactRepository) Update(ctx context.Context, id uuid.UUID, req *model
 		UPDATE contact_submissions
 		SET %s
 		WHERE id = $%d
-		RETURNING id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at`,
+		RETURNING id, name, email, phone, subject, message, status, ip_address, user_agent, metadata, read_at, created_at,
+		          consent_version_id, consent_accepted_at`,
 		strings.Join(setClauses, ", "), argNum)
 
 	contact := &models.ContactSubmission{}
@@ -184,6 +224,7 @@ func (r *ContactRepository) Update(ctx context.Context, id uuid.UUID, req *model
 		&contact.ID, &contact.Name, &contact.Email, &contact.Phone, &contact.Subject,
 		&contact.Message, &contact.Status, &contact.IPAddress, &contact.UserAgent,
 		&contact.Metadata, &contact.ReadAt, &contact.CreatedAt,
+		&contact.ConsentVersionID, &contact.ConsentAcceptedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -214,3 +255,48 @@ func (r *ContactRepository) GetUnreadCount(ctx context.Context) (int64, error) {
 	}
 	return count, nil
 }
+
+// CountByCountry returns the number of submissions per GeoIP country code,
+// with unresolved submissions grouped under an empty code
+func (r *ContactRepository) CountByCountry(ctx context.Context) ([]models.ContactCountryCount, error) {
+	query := `
+		SELECT COALESCE(metadata->'geo'->>'country_code', ''), COALESCE(MAX(metadata->'geo'->>'country'), ''), COUNT(*)
+		FROM contact_submissions
+		GROUP BY 1
+		ORDER BY 3 DESC`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count contact submissions by country: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.ContactCountryCount
+	for rows.Next() {
+		var c models.ContactCountryCount
+		if err := rows.Scan(&c.CountryCode, &c.Country, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan country count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, nil
+}
+
+// contactFilterConditions builds the WHERE clause shared by List and ExportEach
+func contactFilterConditions(filter models.ContactFilter) (string, []interface{}, int) {
+	var cb conditionBuilder
+	if filter.Status != nil {
+		cb.addf("status = %s", *filter.Status)
+	}
+
+	if filter.Email != "" {
+		cb.addf("email ILIKE %s", "%"+filter.Email+"%")
+	}
+
+	for _, m := range filter.Meta {
+		cb.addf(metaFilterCondition("metadata", m))
+	}
+
+	return cb.build()
+}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -39,21 +43,34 @@ func (r *ContentPostRepository) Create(ctx context.Context, req *models.CreatePo
 		Metadata:      req.Metadata,
 		Status:        models.PostStatusDraft,
 		PublishedAt:   req.PublishedAt,
+		Environment:   models.EnvironmentLive,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		Recurrence:    req.Recurrence,
+		ExpiresAt:     req.ExpiresAt,
+		TeamID:        req.TeamID,
 	}
 
 	if req.Status != nil {
 		post.Status = *req.Status
 	}
+	if req.Environment != "" {
+		post.Environment = req.Environment
+	}
 
 	query := `
-		INSERT INTO content_posts (id, content_type_id, author_id, title, slug, excerpt, content, metadata, status, published_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO content_posts (id, content_type_id, author_id, title, slug, excerpt, content, metadata, status, published_at, environment, latitude, longitude,
+		                           starts_at, ends_at, recurrence, expires_at, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''), $17, $18)
 		RETURNING view_count, created_at, updated_at
 	`
 
 	err = tx.QueryRow(ctx, query,
 		post.ID, post.ContentTypeID, post.AuthorID, post.Title, post.Slug,
-		post.Excerpt, post.Content, post.Metadata, post.Status, post.PublishedAt,
+		post.Excerpt, post.Content, post.Metadata, post.Status, post.PublishedAt, post.Environment,
+		post.Latitude, post.Longitude, post.StartsAt, post.EndsAt, post.Recurrence, post.ExpiresAt, post.TeamID,
 	).Scan(&post.ViewCount, &post.CreatedAt, &post.UpdatedAt)
 
 	if err != nil {
@@ -75,6 +92,11 @@ func (r *ContentPostRepository) Create(ctx context.Context, req *models.CreatePo
 			return nil, err
 		}
 	}
+	if len(req.CategoryIDs) > 0 {
+		if err := r.attachCategoriesTx(ctx, tx, post.ID, req.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -96,12 +118,30 @@ func (r *ContentPostRepository) attachTagsTx(ctx context.Context, tx pgx.Tx, pos
 	return nil
 }
 
+func (r *ContentPostRepository) attachCategoriesTx(ctx context.Context, tx pgx.Tx, postID uuid.UUID, categoryIDs []uuid.UUID) error {
+	for _, categoryID := range categoryIDs {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO post_categories (post_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			postID, categoryID,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrForeignKey
+			}
+			return fmt.Errorf("failed to attach category: %w", err)
+		}
+	}
+	return nil
+}
+
 func (r *ContentPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
 	query := `
 		SELECT cp.id, cp.content_type_id, cp.author_id, cp.title, cp.slug, cp.excerpt, 
 		       cp.content, cp.metadata, cp.status, cp.published_at, cp.view_count, 
-		       cp.created_at, cp.updated_at,
-		       ct.id, ct.name, ct.slug, ct.schema_fields, ct.is_active, ct.display_order, ct.created_at, ct.updated_at,
+		       cp.environment, cp.live_post_id, cp.latitude, cp.longitude,
+		       cp.starts_at, cp.ends_at, cp.recurrence, cp.expires_at, cp.team_id, cp.created_at, cp.updated_at,
+		       ct.id, ct.name, ct.slug, ct.schema_fields, ct.traits, ct.slug_pattern, ct.is_active, ct.display_order, ct.created_at, ct.updated_at,
 		       u.id, u.email, u.full_name, u.role, u.is_active, u.last_login, u.created_at, u.updated_at
 		FROM content_posts cp
 		JOIN content_types ct ON cp.content_type_id = ct.id
@@ -117,9 +157,10 @@ func (r *ContentPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&post.ID, &post.ContentTypeID, &post.AuthorID, &post.Title, &post.Slug,
 		&post.Excerpt, &post.Content, &post.Metadata, &post.Status, &post.PublishedAt,
-		&post.ViewCount, &post.CreatedAt, &post.UpdatedAt,
+		&post.ViewCount, &post.Environment, &post.LivePostID, &post.Latitude, &post.Longitude,
+		&post.StartsAt, &post.EndsAt, &post.Recurrence, &post.ExpiresAt, &post.TeamID, &post.CreatedAt, &post.UpdatedAt,
 		&post.ContentType.ID, &post.ContentType.Name, &post.ContentType.Slug,
-		&post.ContentType.SchemaFields, &post.ContentType.IsActive, &post.ContentType.DisplayOrder,
+		&post.ContentType.SchemaFields, &post.ContentType.Traits, &post.ContentType.SlugPattern, &post.ContentType.IsActive, &post.ContentType.DisplayOrder,
 		&post.ContentType.CreatedAt, &post.ContentType.UpdatedAt,
 		&post.Author.ID, &post.Author.Email, &post.Author.FullName, &post.Author.Role,
 		&post.Author.IsActive, &post.Author.LastLogin, &post.Author.CreatedAt, &post.Author.UpdatedAt,
@@ -139,6 +180,13 @@ func (r *ContentPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	}
 	post.Tags = tags
 
+	// Load categories
+	categories, err := r.getPostCategories(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	post.Categories = categories
+
 	// Load media
 	media, err := r.getPostMedia(ctx, post.ID)
 	if err != nil {
@@ -150,9 +198,26 @@ func (r *ContentPostRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 }
 
 func (r *ContentPostRepository) GetBySlug(ctx context.Context, slug string) (*models.ContentPost, error) {
+	return r.GetBySlugInEnvironment(ctx, slug, models.EnvironmentLive)
+}
+
+// GetBySlugInEnvironment looks a post up by slug as seen from env: in the
+// draft environment a draft post wins over the live post with the same slug
+func (r *ContentPostRepository) GetBySlugInEnvironment(ctx context.Context, slug, env string) (*models.ContentPost, error) {
+	envs := []string{models.EnvironmentLive}
+	if env == models.EnvironmentDraft {
+		envs = append(envs, models.EnvironmentDraft)
+	}
+
 	// First get the post ID
 	var postID uuid.UUID
-	err := r.db.QueryRow(ctx, `SELECT id FROM content_posts WHERE slug = $1`, slug).Scan(&postID)
+	err := r.db.QueryRow(ctx, `
+		SELECT id FROM content_posts
+		WHERE slug = $1 AND environment = ANY($2)
+		ORDER BY environment = $3 DESC
+		LIMIT 1`,
+		slug, envs, env,
+	).Scan(&postID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -190,6 +255,33 @@ func (r *ContentPostRepository) getPostTags(ctx context.Context, postID uuid.UUI
 	return tags, nil
 }
 
+func (r *ContentPostRepository) getPostCategories(ctx context.Context, postID uuid.UUID) ([]models.Category, error) {
+	query := `
+		SELECT c.id, c.parent_id, c.name, c.slug, c.description, c.display_order, c.created_at, c.updated_at
+		FROM categories c
+		JOIN post_categories pc ON c.id = pc.category_id
+		WHERE pc.post_id = $1
+		ORDER BY c.name
+	`
+
+	rows, err := r.db.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	return categories, nil
+}
+
 func (r *ContentPostRepository) getPostMedia(ctx context.Context, postID uuid.UUID) ([]models.PostMedia, error) {
 	query := `
 		SELECT pm.id, pm.post_id, pm.media_id, pm.media_role, pm.display_order, pm.created_at,
@@ -228,93 +320,701 @@ func (r *ContentPostRepository) getPostMedia(ctx context.Context, postID uuid.UU
 func (r *ContentPostRepository) List(ctx context.Context, filter models.PostFilter) ([]models.ContentPost, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
+	whereClause, args, argNum := postFilterConditions(filter)
 
-	if filter.ContentTypeID != nil {
-		conditions = append(conditions, fmt.Sprintf("cp.content_type_id = $%d", argNum))
-		args = append(args, *filter.ContentTypeID)
-		argNum++
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM content_posts cp %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count posts: %w", err)
 	}
-	if filter.AuthorID != nil {
-		conditions = append(conditions, fmt.Sprintf("cp.author_id = $%d", argNum))
-		args = append(args, *filter.AuthorID)
-		argNum++
+
+	// Get data
+	orderBy := postOrderBy(filter.PaginationParams)
+	if filter.SortBy == "distance" && filter.Near != nil {
+		orderBy = fmt.Sprintf("%s %s", distanceSQL(fmt.Sprintf("$%d", argNum), fmt.Sprintf("$%d", argNum+1)), filter.SortDir)
+		args = append(args, filter.Near.Latitude, filter.Near.Longitude)
+		argNum += 2
 	}
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("cp.status = $%d", argNum))
-		args = append(args, *filter.Status)
-		argNum++
+
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, postListSelect, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list posts: %w", err)
 	}
-	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(cp.title ILIKE $%d OR cp.excerpt ILIKE $%d)", argNum, argNum))
-		args = append(args, "%"+filter.Search+"%")
-		argNum++
+	defer rows.Close()
+
+	var posts []models.ContentPost
+	for rows.Next() {
+		post, err := scanPostListRow(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		attachDistance(post, filter.Near)
+		posts = append(posts, *post)
 	}
+	rows.Close()
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filter.IncludeViewStats && len(posts) > 0 {
+		if err := r.attachViewStats(ctx, posts); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM content_posts cp %s", whereClause)
-	var total int64
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to count posts: %w", err)
+	return posts, total, nil
+}
+
+func (r *ContentPostRepository) attachViewStats(ctx context.Context, posts []models.ContentPost) error {
+	ids := make([]uuid.UUID, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
 	}
 
-	// Get data
-	orderBy := "cp.created_at DESC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("cp.%s %s", filter.SortBy, filter.SortDir)
+	stats, err := r.GetViewStats(ctx, ids)
+	if err != nil {
+		return err
 	}
 
-	query := fmt.Sprintf(`
+	for i := range posts {
+		s := stats[posts[i].ID]
+		posts[i].Views7d = &s.Views7d
+		posts[i].Views30d = &s.Views30d
+	}
+
+	return nil
+}
+
+// ExportEach streams every post matching the filter to fn, ignoring pagination
+func (r *ContentPostRepository) ExportEach(ctx context.Context, filter models.PostFilter, fn func(*models.ContentPost) error) error {
+	filter.PaginationParams.Normalize()
+
+	whereClause, args, _ := postFilterConditions(filter)
+
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY %s
+	`, postListSelect, whereClause, postOrderBy(filter.PaginationParams))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post, err := scanPostListRow(rows)
+		if err != nil {
+			return err
+		}
+		attachDistance(post, filter.Near)
+		if err := fn(post); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ExpireListings archives published posts of content types with the
+// expiring trait whose expires_at has passed, returning how many were archived
+func (r *ContentPostRepository) ExpireListings(ctx context.Context) (int64, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE content_posts cp
+		SET status = $1
+		FROM content_types ct
+		WHERE ct.id = cp.content_type_id
+		  AND $2 = ANY(ct.traits)
+		  AND cp.status = $3
+		  AND cp.expires_at <= NOW()`,
+		models.PostStatusArchived, models.TraitExpiring, models.PostStatusPublished,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire listings: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListScheduled returns up to limit live drafts whose published_at has
+// passed, oldest first. Only drafts whose published_at was still ahead when
+// they were last saved count as scheduled, so posts moved back to draft
+// after going live stay drafts. Posts still waiting for approval are left
+// out until their approval request is approved.
+func (r *ContentPostRepository) ListScheduled(ctx context.Context, limit int) ([]models.ScheduledPost, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, title, published_at
+		FROM content_posts
+		WHERE environment = 'live'
+		  AND status = $1
+		  AND published_at <= NOW()
+		  AND published_at > updated_at
+		  AND `+publishApproved+`
+		ORDER BY published_at
+		LIMIT $2`,
+		models.PostStatusDraft, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []models.ScheduledPost
+	for rows.Next() {
+		var p models.ScheduledPost
+		if err := rows.Scan(&p.ID, &p.Title, &p.PublishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// PublishScheduled publishes a scheduled draft, reporting false when it was
+// edited, published or deleted since it was listed
+func (r *ContentPostRepository) PublishScheduled(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE content_posts
+		SET status = $1
+		WHERE id = $2
+		  AND environment = 'live'
+		  AND status = $3
+		  AND published_at <= NOW()
+		  AND published_at > updated_at`,
+		models.PostStatusPublished, id, models.PostStatusDraft,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to publish post: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ListEvents returns the published live posts that have a start time and
+// match filter, ordered by first start
+func (r *ContentPostRepository) ListEvents(ctx context.Context, filter models.EventFilter) ([]models.ContentPost, error) {
+	var cb conditionBuilder
+	cb.add("cp.environment = 'live'")
+	cb.addf("cp.status = %s", models.PostStatusPublished)
+	cb.add("(cp.published_at IS NULL OR cp.published_at <= NOW())")
+	cb.add("cp.starts_at IS NOT NULL")
+
+	if filter.ContentTypeSlug != "" {
+		cb.addf("ct.slug = %s", filter.ContentTypeSlug)
+	}
+	if filter.ContentTypeSlugs != nil {
+		cb.addf("ct.slug = ANY(%s)", filter.ContentTypeSlugs)
+	}
+	if filter.TagSlug != "" {
+		cb.addf("EXISTS (SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = cp.id AND t.slug = %s)", filter.TagSlug)
+	}
+	if filter.From != nil {
+		cb.addf("(cp.recurrence IS NOT NULL OR COALESCE(cp.ends_at, cp.starts_at) >= %s)", *filter.From)
+	}
+
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY cp.starts_at`, postListSelect, cb.where())
+
+	rows, err := r.db.Query(ctx, query, cb.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []models.ContentPost
+	for rows.Next() {
+		post, err := scanPostListRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, *post)
+	}
+
+	return posts, rows.Err()
+}
+
+// attachDistance sets the post's distance from near, when both are known
+func attachDistance(post *models.ContentPost, near *models.GeoPoint) {
+	if near == nil || post.Latitude == nil || post.Longitude == nil {
+		return
+	}
+	d := haversineKm(near.Latitude, near.Longitude, *post.Latitude, *post.Longitude)
+	post.DistanceKm = &d
+}
+
+// postListSelect selects posts with the minimal relations used by list views
+const postListSelect = `
 		SELECT cp.id, cp.content_type_id, cp.author_id, cp.title, cp.slug, cp.excerpt,
 		       cp.content, cp.metadata, cp.status, cp.published_at, cp.view_count,
-		       cp.created_at, cp.updated_at,
-		       ct.name as content_type_name, ct.slug as content_type_slug,
+		       cp.environment, cp.live_post_id, cp.latitude, cp.longitude,
+		       cp.starts_at, cp.ends_at, cp.recurrence, cp.expires_at, cp.team_id, cp.created_at, cp.updated_at,
+		       ct.name as content_type_name, ct.slug as content_type_slug, ct.slug_pattern,
 		       u.full_name as author_name
 		FROM content_posts cp
 		JOIN content_types ct ON cp.content_type_id = ct.id
-		JOIN users u ON cp.author_id = u.id
+		JOIN users u ON cp.author_id = u.id`
+
+func scanPostListRow(rows pgx.Rows) (*models.ContentPost, error) {
+	var post models.ContentPost
+	var ctName, ctSlug, ctSlugPattern, authorName string
+
+	if err := rows.Scan(
+		&post.ID, &post.ContentTypeID, &post.AuthorID, &post.Title, &post.Slug,
+		&post.Excerpt, &post.Content, &post.Metadata, &post.Status, &post.PublishedAt,
+		&post.ViewCount, &post.Environment, &post.LivePostID, &post.Latitude, &post.Longitude,
+		&post.StartsAt, &post.EndsAt, &post.Recurrence, &post.ExpiresAt, &post.TeamID, &post.CreatedAt, &post.UpdatedAt,
+		&ctName, &ctSlug, &ctSlugPattern, &authorName,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan post: %w", err)
+	}
+
+	// Minimal relations for list view
+	post.ContentType = &models.ContentType{ID: post.ContentTypeID, Name: ctName, Slug: ctSlug, SlugPattern: ctSlugPattern}
+	post.Author = &models.UserResponse{ID: post.AuthorID, FullName: authorName}
+
+	return &post, nil
+}
+
+// postFilterConditions builds the WHERE clause shared by List and ExportEach
+func postFilterConditions(filter models.PostFilter) (string, []interface{}, int) {
+	var cb conditionBuilder
+	if filter.Environment == models.EnvironmentDraft {
+		// Draft posts plus the live posts that have not been forked
+		cb.add(`(cp.environment = 'draft' OR (cp.environment = 'live'
+			AND NOT EXISTS (SELECT 1 FROM content_posts d WHERE d.live_post_id = cp.id)))`)
+	} else {
+		cb.add("cp.environment = 'live'")
+	}
+
+	if filter.ContentTypeID != nil {
+		cb.addf("cp.content_type_id = %s", *filter.ContentTypeID)
+	}
+	if filter.ContentTypeSlug != "" {
+		cb.addf("cp.content_type_id = (SELECT id FROM content_types WHERE slug = %s)", filter.ContentTypeSlug)
+	}
+	if filter.AuthorID != nil {
+		cb.addf("cp.author_id = %s", *filter.AuthorID)
+	}
+	if filter.Status != nil {
+		cb.addf("cp.status = %s", *filter.Status)
+	}
+	if filter.TeamID != nil {
+		cb.addf("cp.team_id = %s", *filter.TeamID)
+	}
+	if filter.CategoryID != nil {
+		cb.add(fmt.Sprintf("EXISTS (SELECT 1 FROM post_categories pc WHERE pc.post_id = cp.id AND pc.category_id IN (%s))",
+			categorySubtree(cb.arg(*filter.CategoryID))))
+	}
+	if filter.Search != "" {
+		cb.addf("(cp.title ILIKE %[1]s OR cp.excerpt ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+	if filter.Expired != nil {
+		if *filter.Expired {
+			cb.add("cp.expires_at <= NOW()")
+		} else {
+			cb.add("(cp.expires_at IS NULL OR cp.expires_at > NOW())")
+		}
+	}
+	if filter.Near != nil {
+		// The bounding box lets the location index discard far-away posts
+		// before the exact great-circle distance is checked. Near the poles
+		// or across the antimeridian only the latitude band is used.
+		latDelta := filter.RadiusKm / kmPerDegree
+		lngDelta := 180.0
+		if cos := math.Cos(filter.Near.Latitude * math.Pi / 180); cos > 0.01 {
+			lngDelta = latDelta / cos
+		}
+		if filter.Near.Longitude-lngDelta < -180 || filter.Near.Longitude+lngDelta > 180 {
+			lngDelta = 180
+		}
+		cb.add(fmt.Sprintf(
+			"cp.latitude BETWEEN %s AND %s AND (cp.longitude BETWEEN %s AND %s OR %s::float8 >= 180) AND %s <= %s",
+			cb.arg(filter.Near.Latitude-latDelta), cb.arg(filter.Near.Latitude+latDelta),
+			cb.arg(filter.Near.Longitude-lngDelta), cb.arg(filter.Near.Longitude+lngDelta), cb.arg(lngDelta),
+			distanceSQL(cb.arg(filter.Near.Latitude), cb.arg(filter.Near.Longitude)), cb.arg(filter.RadiusKm)))
+	}
+	for _, m := range filter.Meta {
+		cb.addf(metaFilterCondition("cp.metadata", m))
+	}
+
+	return cb.build()
+}
+
+// postSortColumns are the columns posts can be sorted by besides distance
+var postSortColumns = []string{
+	"created_at", "updated_at", "published_at", "title", "slug", "status",
+	"view_count", "starts_at", "expires_at",
+}
+
+func postOrderBy(p models.PaginationParams) string {
+	return sortOrder(p, "cp.created_at DESC", "cp.", postSortColumns...)
+}
+
+// kmPerDegree is the length of one degree of latitude (and of longitude at
+// the equator) on the mean Earth sphere
+const kmPerDegree = 111.195
+
+// distanceSQL returns the haversine distance in kilometres between a post and
+// the point bound to the placeholders lat and lng
+func distanceSQL(lat, lng string) string {
+	return fmt.Sprintf(`(12742 * asin(least(1, sqrt(
+		power(sin(radians(cp.latitude - %[1]s) / 2), 2) +
+		cos(radians(%[1]s)) * cos(radians(cp.latitude)) * power(sin(radians(cp.longitude - %[2]s) / 2), 2)))))`,
+		lat, lng)
+}
+
+// haversineKm returns the great-circle distance between two points in
+// kilometres, matching distanceSQL
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLng/2), 2)
+	return 12742 * math.Asin(math.Sqrt(a))
+}
+
+// maxAggregateBuckets caps the number of groups returned by Aggregate
+const maxAggregateBuckets = 500
+
+// Aggregate groups the posts matching agg.Filter by a metadata field and
+// computes a count or a numeric metric per group, largest groups first.
+// Field names are bound as parameters and metrics come from a fixed set, so
+// nothing from the request is interpolated into the SQL.
+func (r *ContentPostRepository) Aggregate(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, error) {
+	whereClause, args, argNum := postFilterConditions(agg.Filter)
+
+	key := "NULL::text"
+	if agg.GroupBy != "" {
+		key = fmt.Sprintf("cp.metadata->>$%d", argNum)
+		args = append(args, agg.GroupBy)
+		argNum++
+	}
+
+	value := "NULL::float8"
+	if fn, ok := aggregateFuncs[agg.Metric]; ok {
+		// Non-numeric values are skipped rather than failing the cast
+		value = fmt.Sprintf(`%s(CASE WHEN jsonb_typeof(cp.metadata->$%d) = 'number'
+			THEN (cp.metadata->>$%d)::float8 END)`, fn, argNum, argNum)
+		args = append(args, agg.MetricField)
+		argNum++
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, COUNT(*), %s
+		FROM content_posts cp
 		%s
-		ORDER BY %s
-		LIMIT $%d OFFSET $%d
-	`, whereClause, orderBy, argNum, argNum+1)
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+		LIMIT $%d`,
+		key, value, whereClause, argNum)
+	args = append(args, maxAggregateBuckets)
 
-	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate posts: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []models.PostAggregateBucket{}
+	for rows.Next() {
+		var b models.PostAggregateBucket
+		if err := rows.Scan(&b.Key, &b.Count, &b.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
+
+// aggregateBatchSize is how many posts AggregatePartial scans per query
+const aggregateBatchSize = 5000
+
+// aggregateGroup accumulates one group across AggregatePartial batches;
+// values counts the numeric metric values seen
+type aggregateGroup struct {
+	key                     *string
+	count, values           int64
+	sum, minValue, maxValue float64
+}
+
+// AggregatePartial computes the same groups as Aggregate by scanning the
+// matching posts in batches in ID order. When ctx's deadline passes it
+// stops and returns the groups of the posts scanned so far with truncated
+// set, so a slow report degrades into a partial one instead of failing.
+func (r *ContentPostRepository) AggregatePartial(ctx context.Context, agg models.PostAggregate) ([]models.PostAggregateBucket, bool, error) {
+	whereClause, args, argNum := postFilterConditions(agg.Filter)
+
+	key := "NULL::text"
+	if agg.GroupBy != "" {
+		key = fmt.Sprintf("cp.metadata->>$%d", argNum)
+		args = append(args, agg.GroupBy)
+		argNum++
+	}
+
+	value := "NULL::float8"
+	if _, ok := aggregateFuncs[agg.Metric]; ok {
+		value = fmt.Sprintf(`CASE WHEN jsonb_typeof(cp.metadata->$%d) = 'number'
+			THEN (cp.metadata->>$%d)::float8 END`, argNum, argNum)
+		args = append(args, agg.MetricField)
+		argNum++
+	}
+
+	after := fmt.Sprintf("cp.id > $%d", argNum)
+	if whereClause == "" {
+		whereClause = "WHERE " + after
+	} else {
+		whereClause += " AND " + after
+	}
+
+	query := fmt.Sprintf(`
+		WITH batch AS (
+			SELECT cp.id, %s AS key, %s AS value
+			FROM content_posts cp
+			%s
+			ORDER BY cp.id
+			LIMIT $%d
+		)
+		SELECT key, COUNT(*), COUNT(value), COALESCE(SUM(value), 0), COALESCE(MIN(value), 0), COALESCE(MAX(value), 0),
+		       (SELECT id FROM batch ORDER BY id DESC LIMIT 1)
+		FROM batch
+		GROUP BY key`,
+		key, value, whereClause, argNum+1)
+
+	groups := make(map[string]*aggregateGroup)
+	var nullGroup *aggregateGroup
+	lastID := uuid.Nil
+
+	for {
+		batch, batchLast, scanned, err := r.aggregateBatch(ctx, query, append(args, lastID, aggregateBatchSize))
+		if err != nil {
+			if ctx.Err() != nil {
+				return aggregateBuckets(agg.Metric, groups, nullGroup), true, nil
+			}
+			return nil, false, err
+		}
+
+		// Merge only complete batches so partial results stay consistent
+		for _, g := range batch {
+			target := nullGroup
+			if g.key != nil {
+				target = groups[*g.key]
+			}
+			switch {
+			case target == nil && g.key == nil:
+				nullGroup = g
+			case target == nil:
+				groups[*g.key] = g
+			default:
+				if g.values > 0 {
+					if target.values == 0 || g.minValue < target.minValue {
+						target.minValue = g.minValue
+					}
+					if target.values == 0 || g.maxValue > target.maxValue {
+						target.maxValue = g.maxValue
+					}
+				}
+				target.count += g.count
+				target.values += g.values
+				target.sum += g.sum
+			}
+		}
+
+		if scanned < aggregateBatchSize {
+			return aggregateBuckets(agg.Metric, groups, nullGroup), false, nil
+		}
+		lastID = batchLast
+		if ctx.Err() != nil {
+			return aggregateBuckets(agg.Metric, groups, nullGroup), true, nil
+		}
+	}
+}
 
+// aggregateBatch runs one AggregatePartial batch, returning its groups, the
+// last post ID it covered and how many posts it scanned
+func (r *ContentPostRepository) aggregateBatch(ctx context.Context, query string, args []interface{}) ([]*aggregateGroup, uuid.UUID, int64, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list posts: %w", err)
+		return nil, uuid.Nil, 0, fmt.Errorf("failed to aggregate posts: %w", err)
 	}
 	defer rows.Close()
 
-	var posts []models.ContentPost
+	var groups []*aggregateGroup
+	var lastID uuid.UUID
+	var scanned int64
 	for rows.Next() {
-		var post models.ContentPost
-		var ctName, ctSlug, authorName string
+		g := &aggregateGroup{}
+		if err := rows.Scan(&g.key, &g.count, &g.values, &g.sum, &g.minValue, &g.maxValue, &lastID); err != nil {
+			return nil, uuid.Nil, 0, fmt.Errorf("failed to scan aggregate batch: %w", err)
+		}
+		groups = append(groups, g)
+		scanned += g.count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, uuid.Nil, 0, fmt.Errorf("failed to aggregate posts: %w", err)
+	}
 
-		if err := rows.Scan(
-			&post.ID, &post.ContentTypeID, &post.AuthorID, &post.Title, &post.Slug,
-			&post.Excerpt, &post.Content, &post.Metadata, &post.Status, &post.PublishedAt,
-			&post.ViewCount, &post.CreatedAt, &post.UpdatedAt,
-			&ctName, &ctSlug, &authorName,
-		); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan post: %w", err)
+	return groups, lastID, scanned, nil
+}
+
+// aggregateBuckets turns accumulated groups into buckets ordered like
+// Aggregate's: largest first, then by key with posts lacking it last
+func aggregateBuckets(metric string, groups map[string]*aggregateGroup, nullGroup *aggregateGroup) []models.PostAggregateBucket {
+	all := make([]*aggregateGroup, 0, len(groups)+1)
+	for _, g := range groups {
+		all = append(all, g)
+	}
+	if nullGroup != nil {
+		all = append(all, nullGroup)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].count != all[j].count {
+			return all[i].count > all[j].count
+		}
+		if all[i].key == nil || all[j].key == nil {
+			return all[j].key == nil && all[i].key != nil
 		}
+		return *all[i].key < *all[j].key
+	})
+	if len(all) > maxAggregateBuckets {
+		all = all[:maxAggregateBuckets]
+	}
 
-		// Minimal relations for list view
-		post.ContentType = &models.ContentType{ID: post.ContentTypeID, Name: ctName, Slug: ctSlug}
-		post.Author = &models.UserResponse{ID: post.AuthorID, FullName: authorName}
+	buckets := make([]models.PostAggregateBucket, 0, len(all))
+	for _, g := range all {
+		b := models.PostAggregateBucket{Key: g.key, Count: g.count}
+		if g.values > 0 {
+			var v float64
+			switch metric {
+			case models.AggregateAvg:
+				v = g.sum / float64(g.values)
+			case models.AggregateSum:
+				v = g.sum
+			case models.AggregateMin:
+				v = g.minValue
+			case models.AggregateMax:
+				v = g.maxValue
+			}
+			if metric != models.AggregateCount {
+				b.Value = &v
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
 
-		posts = append(posts, post)
+// Facet counts the values of field ("tags" or meta.<field>) among posts matching filter, returning at most limit values, most common
+// first
+func (r *ContentPostRepository) Facet(ctx context.Context, filter models.PostFilter, field string, limit int) (*models.Facet, error) {
+	whereClause, args, argNum := postFilterConditions(filter)
+
+	var query string
+	if field == "tags" {
+		query = fmt.Sprintf(`
+			SELECT t.slug, COUNT(*)
+			FROM content_posts cp
+			JOIN post_tags pt ON pt.post_id = cp.id
+			JOIN tags t ON t.id = pt.tag_id
+			%s
+			GROUP BY t.slug
+			ORDER BY 2 DESC, 1
+			LIMIT $%d`,
+			whereClause, argNum)
+	} else {
+		query = fmt.Sprintf(`
+			SELECT cp.metadata->>$%d, COUNT(*)
+			FROM content_posts cp
+			%s AND cp.metadata->>$%d IS NOT NULL
+			GROUP BY 1
+			ORDER BY 2 DESC, 1
+			LIMIT $%d`,
+			argNum, whereClause, argNum, argNum+1)
+		args = append(args, strings.TrimPrefix(field, "meta."))
 	}
+	// One extra row tells whether the facet was truncated
+	args = append(args, limit+1)
 
-	return posts, total, nil
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute %s facet: %w", field, err)
+	}
+	defer rows.Close()
+
+	facet := &models.Facet{Field: field, Values: []models.FacetValue{}}
+	for rows.Next() {
+		var v models.FacetValue
+		if err := rows.Scan(&v.Value, &v.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan facet value: %w", err)
+		}
+		if len(facet.Values) == limit {
+			facet.Truncated = true
+			break
+		}
+		facet.Values = append(facet.Values, v)
+	}
+
+	return facet, nil
+}
+
+// aggregateFuncs maps numeric metrics to SQL aggregate functions
+var aggregateFuncs = map[string]string{
+	models.AggregateAvg: "AVG",
+	models.AggregateSum: "SUM",
+	models.AggregateMin: "MIN",
+	models.AggregateMax: "MAX",
+}
+
+// metaComparisons maps range operators to jsonpath comparisons
+var metaComparisons = map[string]string{
+	models.MetaOpLt:  "<",
+	models.MetaOpLte: "<=",
+	models.MetaOpGt:  ">",
+	models.MetaOpGte: ">=",
+}
+
+// metaFilterCondition turns a filter on the metadata column into a condition
+// format for conditionBuilder.addf and its argument. Equality uses JSONB
+// containment, which a GIN index on metadata serves. Number and string ranges
+// compare metadata_number or metadata_string, the expressions migrations
+// index for fields content types declare in indexed_fields; the field name
+// is then part of the SQL, so the index matches, and only names that pass
+// models.ValidMetaField get there. Other ranges use a jsonpath predicate.
+// Either way rows whose field holds a value of another type simply do not
+// match instead of failing a cast.
+func metaFilterCondition(column string, m models.MetaFilter) (string, interface{}) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(m.Value), &value); err != nil {
+		value = m.Value
+	}
+	switch value.(type) {
+	case string, float64, bool:
+	default:
+		// Objects, arrays and null are matched as plain strings
+		value = m.Value
+	}
+
+	if m.Op == models.MetaOpEq {
+		doc, _ := json.Marshal(map[string]interface{}{m.Field: value})
+		return column + " @> %s::jsonb", string(doc)
+	}
+
+	literal, _ := json.Marshal(value)
+	if models.ValidMetaField(m.Field) {
+		switch value := value.(type) {
+		case float64:
+			return fmt.Sprintf("metadata_number(%s, '%s') %s %%s::numeric", column, m.Field, metaComparisons[m.Op]), string(literal)
+		case string:
+			return fmt.Sprintf(`metadata_string(%s, '%s') COLLATE "C" %s %%s`, column, m.Field, metaComparisons[m.Op]), value
+		}
+	}
+	path := fmt.Sprintf("$.%q %s %s", m.Field, metaComparisons[m.Op], literal)
+	return "COALESCE(" + column + " @@ %s::jsonpath, false)", path
 }
 
 func (r *ContentPostRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdatePostRequest) (*models.ContentPost, error) {
@@ -368,6 +1068,42 @@ func (r *ContentPostRepository) Update(ctx context.Context, id uuid.UUID, req *m
 		args = append(args, *req.PublishedAt)
 		argNum++
 	}
+	if req.Latitude != nil && req.Longitude != nil {
+		setClauses = append(setClauses, fmt.Sprintf("latitude = $%d, longitude = $%d", argNum, argNum+1))
+		args = append(args, *req.Latitude, *req.Longitude)
+		argNum += 2
+	}
+	if req.StartsAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("starts_at = $%d", argNum))
+		args = append(args, *req.StartsAt)
+		argNum++
+	}
+	if req.EndsAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("ends_at = $%d", argNum))
+		args = append(args, *req.EndsAt)
+		argNum++
+	}
+	if req.ExpiresAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("expires_at = $%d", argNum))
+		args = append(args, *req.ExpiresAt)
+		argNum++
+	}
+	if req.Recurrence != nil {
+		// An empty rule turns a recurring event back into a one-off
+		setClauses = append(setClauses, fmt.Sprintf("recurrence = NULLIF($%d, '')", argNum))
+		args = append(args, *req.Recurrence)
+		argNum++
+	}
+	if req.TeamID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("team_id = NULLIF($%d, $%d::uuid)", argNum, argNum+1))
+		args = append(args, *req.TeamID, uuid.Nil)
+		argNum += 2
+	}
+	if req.AuthorID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("author_id = $%d", argNum))
+		args = append(args, *req.AuthorID)
+		argNum++
+	}
 
 	if len(setClauses) > 0 {
 		args = append(args, id)
@@ -408,6 +1144,16 @@ func (r *ContentPostRepository) Update(ctx context.Context, id uuid.UUID, req *m
 		}
 	}
 
+	// Update categories if provided
+	if req.CategoryIDs != nil {
+		if _, err := tx.Exec(ctx, `DELETE FROM post_categories WHERE post_id = $1`, id); err != nil {
+			return nil, fmt.Errorf("failed to remove existing categories: %w", err)
+		}
+		if err := r.attachCategoriesTx(ctx, tx, id, *req.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -415,6 +1161,190 @@ func (r *ContentPostRepository) Update(ctx context.Context, id uuid.UUID, req *m
 	return r.GetByID(ctx, id)
 }
 
+// ForkToDraft returns the draft copy of a live post, creating it with the
+// post's tags and media on first use (copy-on-write). Draft posts are
+// returned unchanged.
+func (r *ContentPostRepository) ForkToDraft(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var env string
+	var draftID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT cp.environment, (SELECT d.id FROM content_posts d WHERE d.live_post_id = cp.id)
+		FROM content_posts cp
+		WHERE cp.id = $1
+		FOR UPDATE`, id,
+	).Scan(&env, &draftID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFound
+		}
+		return uuid.Nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if env == models.EnvironmentDraft {
+		return id, nil
+	}
+	if draftID != nil {
+		return *draftID, nil
+	}
+
+	forkID := uuid.New()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO content_posts (id, content_type_id, author_id, title, slug, excerpt, content, metadata,
+		                           status, published_at, latitude, longitude,
+		                           starts_at, ends_at, recurrence, expires_at, team_id, environment, live_post_id)
+		SELECT $2, content_type_id, author_id, title, slug, excerpt, content, metadata,
+		       status, published_at, latitude, longitude, starts_at, ends_at, recurrence, expires_at, team_id, $3, id
+		FROM content_posts
+		WHERE id = $1`,
+		id, forkID, models.EnvironmentDraft,
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to fork post: %w", err)
+	}
+
+	if err := copyPostRelationsTx(ctx, tx, id, forkID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return forkID, nil
+}
+
+// PromoteDraft publishes a draft post to the live environment. A draft copy
+// overwrites the live post it was forked from (keeping the live ID, views and
+// URLs) and is then removed; a draft-only post simply moves to live.
+func (r *ContentPostRepository) PromoteDraft(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var livePostID *uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT live_post_id FROM content_posts WHERE id = $1 AND environment = $2 FOR UPDATE`,
+		id, models.EnvironmentDraft,
+	).Scan(&livePostID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get draft post: %w", err)
+	}
+
+	promotedID := id
+	if livePostID == nil {
+		_, err = tx.Exec(ctx, `UPDATE content_posts SET environment = $1 WHERE id = $2`, models.EnvironmentLive, id)
+	} else {
+		promotedID = *livePostID
+
+		// Replace the live post's tags, categories and media with the
+		// draft's before the draft (and its relations) is deleted
+		if _, err := tx.Exec(ctx, `DELETE FROM post_tags WHERE post_id = $1`, promotedID); err != nil {
+			return nil, fmt.Errorf("failed to remove existing tags: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM post_categories WHERE post_id = $1`, promotedID); err != nil {
+			return nil, fmt.Errorf("failed to remove existing categories: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM post_media WHERE post_id = $1`, promotedID); err != nil {
+			return nil, fmt.Errorf("failed to remove existing media: %w", err)
+		}
+		if err := copyPostRelationsTx(ctx, tx, id, promotedID); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, `
+			WITH draft AS (
+				DELETE FROM content_posts WHERE id = $1
+				RETURNING content_type_id, title, slug, excerpt, content, metadata, status, published_at,
+				          latitude, longitude, starts_at, ends_at, recurrence, expires_at, team_id
+			)
+			UPDATE content_posts l
+			SET content_type_id = d.content_type_id, title = d.title, slug = d.slug, excerpt = d.excerpt,
+			    content = d.content, metadata = d.metadata, status = d.status, published_at = d.published_at,
+			    latitude = d.latitude, longitude = d.longitude,
+			    starts_at = d.starts_at, ends_at = d.ends_at, recurrence = d.recurrence,
+			    expires_at = d.expires_at, team_id = d.team_id
+			FROM draft d
+			WHERE l.id = $2`,
+			id, promotedID,
+		)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to promote post: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, promotedID)
+}
+
+// copyPostRelationsTx copies tags, categories and media attachments from one
+// post to another
+func copyPostRelationsTx(ctx context.Context, tx pgx.Tx, fromID, toID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO post_tags (post_id, tag_id)
+		SELECT $2, tag_id FROM post_tags WHERE post_id = $1`,
+		fromID, toID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy tags: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO post_categories (post_id, category_id)
+		SELECT $2, category_id FROM post_categories WHERE post_id = $1`,
+		fromID, toID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy categories: %w", err)
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT media_id, media_role, display_order FROM post_media WHERE post_id = $1`, fromID)
+	if err != nil {
+		return fmt.Errorf("failed to get post media: %w", err)
+	}
+	var attachments []models.PostMedia
+	for rows.Next() {
+		var pm models.PostMedia
+		if err := rows.Scan(&pm.MediaID, &pm.MediaRole, &pm.DisplayOrder); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan post media: %w", err)
+		}
+		attachments = append(attachments, pm)
+	}
+	rows.Close()
+
+	for _, pm := range attachments {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO post_media (id, post_id, media_id, media_role, display_order)
+			VALUES ($1, $2, $3, $4, $5)`,
+			uuid.New(), toID, pm.MediaID, pm.MediaRole, pm.DisplayOrder,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy media: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (r *ContentPostRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result, err := r.db.Exec(ctx, `DELETE FROM content_posts WHERE id = $1`, id)
 	if err != nil {
@@ -477,7 +1407,60 @@ func (r *ContentPostRepository) DetachMedia(ctx context.Context, postID, mediaID
 	return nil
 }
 
+// IncrementViewCount bumps the global view counter and today's rollup in one statement
 func (r *ContentPostRepository) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `UPDATE content_posts SET view_count = view_count + 1 WHERE id = $1`, id)
+	query := `
+		WITH post AS (
+			UPDATE content_posts SET view_count = view_count + 1 WHERE id = $1 RETURNING id
+		)
+		INSERT INTO post_view_daily (post_id, day, views)
+		SELECT id, CURRENT_DATE, 1 FROM post
+		ON CONFLICT (post_id, day) DO UPDATE SET views = post_view_daily.views + 1
+	`
+	_, err := r.db.Exec(ctx, query, id)
 	return err
 }
+
+// GetViewStats returns rolling 7 and 30 day view totals for the given posts
+func (r *ContentPostRepository) GetViewStats(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.PostViewStats, error) {
+	query := `
+		SELECT post_id,
+		       COALESCE(SUM(views) FILTER (WHERE day > CURRENT_DATE - 7), 0),
+		       COALESCE(SUM(views), 0)
+		FROM post_view_daily
+		WHERE post_id = ANY($1) AND day > CURRENT_DATE - 30
+		GROUP BY post_id
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[uuid.UUID]models.PostViewStats)
+	for rows.Next() {
+		var postID uuid.UUID
+		var s models.PostViewStats
+		if err := rows.Scan(&postID, &s.Views7d, &s.Views30d); err != nil {
+			return nil, fmt.Errorf("failed to scan view stats: %w", err)
+		}
+		stats[postID] = s
+	}
+
+	return stats, nil
+}
+
+// PruneViewRollups deletes daily view and traffic source rollups older
+// than the given day
+func (r *ContentPostRepository) PruneViewRollups(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM post_view_daily WHERE day < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune view rollups: %w", err)
+	}
+	traffic, err := r.db.Exec(ctx, `DELETE FROM post_traffic_daily WHERE day < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune traffic source rollups: %w", err)
+	}
+	return result.RowsAffected() + traffic.RowsAffected(), nil
+}
@@ -21,16 +21,31 @@ func NewContentTypeRepository(db *pgxpool.Pool) *ContentTypeRepository {
 	return &ContentTypeRepository{db: db}
 }
 
+// contentTypeSortColumns are the columns list results can be sorted by
+var contentTypeSortColumns = []string{"created_at", "updated_at", "name", "slug", "display_order"}
+
 func (r *ContentTypeRepository) Create(ctx context.Context, req *models.CreateContentTypeRequest) (*models.ContentType, error) {
 	ct := &models.ContentType{
-		ID:           uuid.New(),
-		Name:         req.Name,
-		Slug:         req.Slug,
-		SchemaFields: req.SchemaFields,
-		IsActive:     true,
-		DisplayOrder: 0,
+		ID:            uuid.New(),
+		Name:          req.Name,
+		Slug:          req.Slug,
+		SchemaFields:  req.SchemaFields,
+		Traits:        req.Traits,
+		IndexedFields: req.IndexedFields,
+		SlugPattern:   req.SlugPattern,
+		IsActive:      true,
+		DisplayOrder:  0,
 	}
 
+	if ct.Traits == nil {
+		ct.Traits = []string{}
+	}
+	if ct.IndexedFields == nil {
+		ct.IndexedFields = []models.IndexedField{}
+	}
+	if ct.SlugPattern == "" {
+		ct.SlugPattern = models.DefaultSlugPattern
+	}
 	if req.IsActive != nil {
 		ct.IsActive = *req.IsActive
 	}
@@ -39,12 +54,12 @@ func (r *ContentTypeRepository) Create(ctx context.Context, req *models.CreateCo
 	}
 
 	query := `
-		INSERT INTO content_types (id, name, slug, schema_fields, is_active, display_order)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO content_types (id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING created_at, updated_at`
 
 	err := r.db.QueryRow(ctx, query,
-		ct.ID, ct.Name, ct.Slug, ct.SchemaFields, ct.IsActive, ct.DisplayOrder,
+		ct.ID, ct.Name, ct.Slug, ct.SchemaFields, ct.Traits, ct.IndexedFields, ct.SlugPattern, ct.IsActive, ct.DisplayOrder,
 	).Scan(&ct.CreatedAt, &ct.UpdatedAt)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -59,13 +74,13 @@ func (r *ContentTypeRepository) Create(ctx context.Context, req *models.CreateCo
 
 func (r *ContentTypeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContentType, error) {
 	query := `
-		SELECT id, name, slug, schema_fields, is_active, display_order, created_at, updated_at
+		SELECT id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order, created_at, updated_at
 		FROM content_types
 		WHERE id = $1`
 
 	ct := &models.ContentType{}
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields,
+		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields, &ct.Traits, &ct.IndexedFields, &ct.SlugPattern,
 		&ct.IsActive, &ct.DisplayOrder, &ct.CreatedAt, &ct.UpdatedAt,
 	)
 	if err != nil {
@@ -80,13 +95,13 @@ func (r *ContentTypeRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 
 func (r *ContentTypeRepository) GetBySlug(ctx context.Context, slug string) (*models.ContentType, error) {
 	query := `
-		SELECT id, name, slug, schema_fields, is_active, display_order, created_at, updated_at
+		SELECT id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order, created_at, updated_at
 		FROM content_types
 		WHERE slug = $1`
 
 	ct := &models.ContentType{}
 	err := r.db.QueryRow(ctx, query, slug).Scan(
-		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields,
+		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields, &ct.Traits, &ct.IndexedFields, &ct.SlugPattern,
 		&ct.IsActive, &ct.DisplayOrder, &ct.CreatedAt, &ct.UpdatedAt,
 	)
 	if err != nil {
@@ -102,20 +117,12 @@ func (r *ContentTypeRepository) GetBySlug(ctx context.Context, slug string) (*mo
 func (r *ContentTypeRepository) List(ctx context.Context, filter models.ContentTypeFilter) ([]models.ContentType, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
-
+	var cb conditionBuilder
 	if filter.IsActive != nil {
-		conditions = append(conditions, fmt.Sprintf("is_active = $%d", argNum))
-		args = append(args, *filter.IsActive)
-		argNum++
+		cb.addf("is_active = %s", *filter.IsActive)
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args, argNum := cb.build()
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM content_types %s", whereClause)
@@ -125,13 +132,10 @@ func (r *ContentTypeRepository) List(ctx context.Context, filter models.ContentT
 	}
 
 	// Get data
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("%s %s", filter.SortBy, filter.SortDir)
-	}
+	orderBy := sortOrder(filter.PaginationParams, "display_order ASC, created_at DESC", "", contentTypeSortColumns...)
 
 	query := fmt.Sprintf(`
-		SELECT id, name, slug, schema_fields, is_active, display_order, created_at, updated_at
+		SELECT id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order, created_at, updated_at
 		FROM content_types
 		%s
 		ORDER BY %s
@@ -149,7 +153,7 @@ func (r *ContentTypeRepository) List(ctx context.Context, filter models.ContentT
 	for rows.Next() {
 		var ct models.ContentType
 		if err := rows.Scan(
-			&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields,
+			&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields, &ct.Traits, &ct.IndexedFields, &ct.SlugPattern,
 			&ct.IsActive, &ct.DisplayOrder, &ct.CreatedAt, &ct.UpdatedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan content type: %w", err)
@@ -160,6 +164,32 @@ func (r *ContentTypeRepository) List(ctx context.Context, filter models.ContentT
 	return contentTypes, total, nil
 }
 
+// ListAll returns every content type, ordered by slug
+func (r *ContentTypeRepository) ListAll(ctx context.Context) ([]models.ContentType, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order, created_at, updated_at
+		FROM content_types
+		ORDER BY slug`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content types: %w", err)
+	}
+	defer rows.Close()
+
+	var contentTypes []models.ContentType
+	for rows.Next() {
+		var ct models.ContentType
+		if err := rows.Scan(
+			&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields, &ct.Traits, &ct.IndexedFields, &ct.SlugPattern,
+			&ct.IsActive, &ct.DisplayOrder, &ct.CreatedAt, &ct.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan content type: %w", err)
+		}
+		contentTypes = append(contentTypes, ct)
+	}
+
+	return contentTypes, rows.Err()
+}
+
 func (r *ContentTypeRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateContentTypeRequest) (*models.ContentType, error) {
 	var setClauses []string
 	var args []interface{}
@@ -183,6 +213,24 @@ func (r *ContentTypeRepository) Update(ctx context.Context, id uuid.UUID, req *m
 		argNum++
 	}
 
+	if req.Traits != nil {
+		setClauses = append(setClauses, fmt.Sprintf("traits = $%d", argNum))
+		args = append(args, *req.Traits)
+		argNum++
+	}
+
+	if req.IndexedFields != nil {
+		setClauses = append(setClauses, fmt.Sprintf("indexed_fields = $%d", argNum))
+		args = append(args, *req.IndexedFields)
+		argNum++
+	}
+
+	if req.SlugPattern != nil {
+		setClauses = append(setClauses, fmt.Sprintf("slug_pattern = $%d", argNum))
+		args = append(args, *req.SlugPattern)
+		argNum++
+	}
+
 	if req.IsActive != nil {
 		setClauses = append(setClauses, fmt.Sprintf("is_active = $%d", argNum))
 		args = append(args, *req.IsActive)
@@ -204,12 +252,12 @@ func (r *ContentTypeRepository) Update(ctx context.Context, id uuid.UUID, req *m
 		UPDATE content_types
 		SET %s
 		WHERE id = $%d
-		RETURNING id, name, slug, schema_fields, is_active, display_order, created_at, updated_at`,
+		RETURNING id, name, slug, schema_fields, traits, indexed_fields, slug_pattern, is_active, display_order, created_at, updated_at`,
 		strings.Join(setClauses, ", "), argNum)
 
 	ct := &models.ContentType{}
 	err := r.db.QueryRow(ctx, query, args...).Scan(
-		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields,
+		&ct.ID, &ct.Name, &ct.Slug, &ct.SchemaFields, &ct.Traits, &ct.IndexedFields, &ct.SlugPattern,
 		&ct.IsActive, &ct.DisplayOrder, &ct.CreatedAt, &ct.UpdatedAt,
 	)
 	if err != nil {
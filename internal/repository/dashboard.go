@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// DashboardViews are the materialized views behind the dashboard
+var DashboardViews = []string{"dashboard_post_counts", "dashboard_daily_views", "dashboard_tag_counts"}
+
+// DashboardRepository reads the dashboard aggregates and refreshes them
+type DashboardRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDashboardRepository(db *pgxpool.Pool) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// Refresh recomputes a dashboard view without blocking its readers and
+// records when it was refreshed
+func (r *DashboardRepository) Refresh(ctx context.Context, view string) (*models.AggregateFreshness, error) {
+	if !slices.Contains(DashboardViews, view) {
+		return nil, ErrNotFound
+	}
+
+	start := time.Now()
+	if _, err := r.db.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+		return nil, fmt.Errorf("failed to refresh %s: %w", view, err)
+	}
+
+	freshness := &models.AggregateFreshness{View: view, DurationMs: int(time.Since(start).Milliseconds())}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO dashboard_refreshes (view_name, refreshed_at, duration_ms)
+		VALUES ($1, NOW(), $2)
+		ON CONFLICT (view_name) DO UPDATE SET refreshed_at = EXCLUDED.refreshed_at, duration_ms = EXCLUDED.duration_ms
+		RETURNING refreshed_at`,
+		view, freshness.DurationMs,
+	).Scan(&freshness.RefreshedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record refresh of %s: %w", view, err)
+	}
+	return freshness, nil
+}
+
+// Freshness returns when each dashboard view was last refreshed, in
+// DashboardViews order; views never refreshed have no RefreshedAt
+func (r *DashboardRepository) Freshness(ctx context.Context) ([]models.AggregateFreshness, error) {
+	rows, err := r.db.Query(ctx, `SELECT view_name, refreshed_at, duration_ms FROM dashboard_refreshes`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard refreshes: %w", err)
+	}
+	defer rows.Close()
+
+	refreshed := make(map[string]models.AggregateFreshness)
+	for rows.Next() {
+		var f models.AggregateFreshness
+		if err := rows.Scan(&f.View, &f.RefreshedAt, &f.DurationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan dashboard refresh: %w", err)
+		}
+		refreshed[f.View] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get dashboard refreshes: %w", err)
+	}
+
+	freshness := make([]models.AggregateFreshness, len(DashboardViews))
+	for i, view := range DashboardViews {
+		freshness[i] = refreshed[view]
+		freshness[i].View = view
+	}
+	return freshness, nil
+}
+
+// PostCounts returns the live post counts by content type and status
+func (r *DashboardRepository) PostCounts(ctx context.Context) ([]models.DashboardPostCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT content_type_id, content_type, status, posts
+		FROM dashboard_post_counts
+		ORDER BY content_type, status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.DashboardPostCount{}
+	for rows.Next() {
+		var c models.DashboardPostCount
+		if err := rows.Scan(&c.ContentTypeID, &c.ContentType, &c.Status, &c.Posts); err != nil {
+			return nil, fmt.Errorf("failed to scan post count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// DailyViews returns the views per day from from (a UTC day) on, oldest
+// first
+func (r *DashboardRepository) DailyViews(ctx context.Context, from time.Time) ([]models.DashboardDailyViews, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(day, 'YYYY-MM-DD'), views, posts
+		FROM dashboard_daily_views
+		WHERE day >= $1
+		ORDER BY day`,
+		from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily views: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.DashboardDailyViews{}
+	for rows.Next() {
+		var d models.DashboardDailyViews
+		if err := rows.Scan(&d.Day, &d.Views, &d.Posts); err != nil {
+			return nil, fmt.Errorf("failed to scan daily views: %w", err)
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// TopTags returns the limit tags with the most published live posts
+func (r *DashboardRepository) TopTags(ctx context.Context, limit int) ([]models.DashboardTagCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT tag_id, name, slug, posts
+		FROM dashboard_tag_counts
+		ORDER BY posts DESC, name
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.DashboardTagCount{}
+	for rows.Next() {
+		var t models.DashboardTagCount
+		if err := rows.Scan(&t.TagID, &t.Name, &t.Slug, &t.Posts); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// DeliveryTokenPrefix starts every delivery token, so leaked tokens are
+// easy to recognize
+const DeliveryTokenPrefix = "cdt_"
+
+type DeliveryTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDeliveryTokenRepository(db *pgxpool.Pool) *DeliveryTokenRepository {
+	return &DeliveryTokenRepository{db: db}
+}
+
+// deliveryTokenSortColumns are the columns list results can be sorted by
+var deliveryTokenSortColumns = []string{"name", "created_at", "last_used_at", "expires_at"}
+
+const deliveryTokenColumns = `id, name, token_prefix, content_types, locales, environments,
+	is_active, expires_at, last_used_at, created_at, updated_at`
+
+func scanDeliveryToken(row pgx.Row) (*models.DeliveryToken, error) {
+	t := &models.DeliveryToken{}
+	err := row.Scan(&t.ID, &t.Name, &t.TokenPrefix, &t.ContentTypes, &t.Locales, &t.Environments,
+		&t.IsActive, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// HashDeliveryToken returns the stored form of a token
+func HashDeliveryToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create generates a token and stores its hash. The token itself is only
+// returned here.
+func (r *DeliveryTokenRepository) Create(ctx context.Context, req *models.CreateDeliveryTokenRequest) (*models.CreatedDeliveryToken, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate delivery token: %w", err)
+	}
+	token := DeliveryTokenPrefix + hex.EncodeToString(secret)
+
+	query := fmt.Sprintf(`
+		INSERT INTO delivery_tokens (id, name, token_hash, token_prefix, content_types, locales, environments, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING %s`, deliveryTokenColumns)
+
+	t, err := scanDeliveryToken(r.db.QueryRow(ctx, query,
+		uuid.New(), req.Name, HashDeliveryToken(token), token[:len(DeliveryTokenPrefix)+4],
+		nonNil(req.ContentTypes), nonNil(req.Locales), nonNil(req.Environments), req.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create delivery token: %w", err)
+	}
+
+	return &models.CreatedDeliveryToken{DeliveryToken: *t, Token: token}, nil
+}
+
+func (r *DeliveryTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.DeliveryToken, error) {
+	t, err := scanDeliveryToken(r.db.QueryRow(ctx, "SELECT "+deliveryTokenColumns+" FROM delivery_tokens WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get delivery token: %w", err)
+	}
+	return t, nil
+}
+
+// GetByToken looks a token up by its secret, whether usable or not
+func (r *DeliveryTokenRepository) GetByToken(ctx context.Context, token string) (*models.DeliveryToken, error) {
+	t, err := scanDeliveryToken(r.db.QueryRow(ctx,
+		"SELECT "+deliveryTokenColumns+" FROM delivery_tokens WHERE token_hash = $1", HashDeliveryToken(token)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get delivery token: %w", err)
+	}
+	return t, nil
+}
+
+func (r *DeliveryTokenRepository) List(ctx context.Context, filter models.DeliveryTokenFilter) ([]models.DeliveryToken, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.IsActive != nil {
+		cb.addf("is_active = %s", *filter.IsActive)
+	}
+	if filter.Search != "" {
+		cb.addf("(name ILIKE %[1]s OR token_prefix ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM delivery_tokens %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count delivery tokens: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", deliveryTokenSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM delivery_tokens
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		deliveryTokenColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list delivery tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []models.DeliveryToken
+	for rows.Next() {
+		t, err := scanDeliveryToken(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan delivery token: %w", err)
+		}
+		tokens = append(tokens, *t)
+	}
+
+	return tokens, total, nil
+}
+
+func (r *DeliveryTokenRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateDeliveryTokenRequest) (*models.DeliveryToken, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	set := func(column string, value interface{}) {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, argNum))
+		args = append(args, value)
+		argNum++
+	}
+	if req.Name != nil {
+		set("name", *req.Name)
+	}
+	if req.ContentTypes != nil {
+		set("content_types", nonNil(*req.ContentTypes))
+	}
+	if req.Locales != nil {
+		set("locales", nonNil(*req.Locales))
+	}
+	if req.Environments != nil {
+		set("environments", nonNil(*req.Environments))
+	}
+	if req.IsActive != nil {
+		set("is_active", *req.IsActive)
+	}
+	if req.ExpiresAt != nil {
+		set("expires_at", *req.ExpiresAt)
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`
+		UPDATE delivery_tokens
+		SET %s
+		WHERE id = $%d
+		RETURNING %s`, strings.Join(setClauses, ", "), argNum, deliveryTokenColumns)
+
+	t, err := scanDeliveryToken(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update delivery token: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *DeliveryTokenRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM delivery_tokens WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete delivery token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Touch records that a token was used. Writes are limited to one a minute
+// per token, so busy clients don't turn every read into a write.
+func (r *DeliveryTokenRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE delivery_tokens SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery token use: %w", err)
+	}
+	return nil
+}
+
+// nonNil turns a nil slice into an empty one for NOT NULL array columns
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type DigestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDigestRepository(db *pgxpool.Pool) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// GetPreference returns a user's digest subscription
+func (r *DigestRepository) GetPreference(ctx context.Context, userID uuid.UUID) (*models.DigestPreference, error) {
+	p := &models.DigestPreference{}
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, frequency, sections, locale, last_sent_at, updated_at
+		FROM digest_preferences
+		WHERE user_id = $1`, userID,
+	).Scan(&p.UserID, &p.Frequency, &p.Sections, &p.Locale, &p.LastSentAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get digest preference: %w", err)
+	}
+	return p, nil
+}
+
+// SavePreference creates or replaces a user's digest subscription. The time
+// of the last digest is kept, so changing preferences doesn't resend one.
+func (r *DigestRepository) SavePreference(ctx context.Context, userID uuid.UUID, req *models.UpdateDigestPreferenceRequest) (*models.DigestPreference, error) {
+	sections := req.Sections
+	if sections == nil {
+		sections = []string{}
+	}
+
+	p := &models.DigestPreference{}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO digest_preferences (user_id, frequency, sections, locale)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET frequency = EXCLUDED.frequency, sections = EXCLUDED.sections,
+		    locale = EXCLUDED.locale, updated_at = CURRENT_TIMESTAMP
+		RETURNING user_id, frequency, sections, locale, last_sent_at, updated_at`,
+		userID, req.Frequency, sections, req.Locale,
+	).Scan(&p.UserID, &p.Frequency, &p.Sections, &p.Locale, &p.LastSentAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save digest preference: %w", err)
+	}
+	return p, nil
+}
+
+// ListDue returns the active editors and admins whose daily or weekly
+// digest is due at now
+func (r *DigestRepository) ListDue(ctx context.Context, now time.Time) ([]models.DigestRecipient, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT dp.user_id, dp.frequency, dp.sections, dp.locale, dp.last_sent_at, dp.updated_at, u.email, u.full_name
+		FROM digest_preferences dp
+		JOIN users u ON u.id = dp.user_id
+		WHERE u.is_active AND u.role >= $1
+		  AND (
+		    (dp.frequency = $2 AND (dp.last_sent_at IS NULL OR dp.last_sent_at <= $4::timestamptz - INTERVAL '1 day')) OR
+		    (dp.frequency = $3 AND (dp.last_sent_at IS NULL OR dp.last_sent_at <= $4::timestamptz - INTERVAL '7 days'))
+		  )
+		ORDER BY dp.user_id`,
+		models.RoleEditor, models.DigestFrequencyDaily, models.DigestFrequencyWeekly, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due digests: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []models.DigestRecipient
+	for rows.Next() {
+		var d models.DigestRecipient
+		if err := rows.Scan(&d.UserID, &d.Frequency, &d.Sections, &d.Locale, &d.LastSentAt, &d.UpdatedAt, &d.Email, &d.FullName); err != nil {
+			return nil, fmt.Errorf("failed to scan digest recipient: %w", err)
+		}
+		recipients = append(recipients, d)
+	}
+	return recipients, rows.Err()
+}
+
+// MarkSent records when a user's digest was queued
+func (r *DigestRepository) MarkSent(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE digest_preferences SET last_sent_at = $2 WHERE user_id = $1`, userID, at)
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
+
+// Summary collects the editorial activity between from and to, listing up
+// to limit posts per section. Views are counted from the daily rollups of
+// the days the period touches.
+func (r *DigestRepository) Summary(ctx context.Context, from, to time.Time, limit int) (*models.DigestSummary, error) {
+	s := &models.DigestSummary{
+		From:      from,
+		To:        to,
+		Published: []models.DigestPost{},
+		Drafts:    []models.DigestPost{},
+		TopViewed: []models.DigestPost{},
+	}
+
+	err := r.db.QueryRow(ctx, `
+		SELECT
+		  (SELECT COUNT(*) FROM content_posts
+		   WHERE environment = $1 AND status = $2 AND published_at >= $4 AND published_at < $5),
+		  (SELECT COUNT(*) FROM content_posts WHERE status = $3 OR environment = $6),
+		  (SELECT COUNT(*) FROM contact_submissions WHERE created_at >= $4 AND created_at < $5)`,
+		models.EnvironmentLive, models.PostStatusPublished, models.PostStatusDraft, from, to, models.EnvironmentDraft,
+	).Scan(&s.PublishedCount, &s.DraftCount, &s.NewContacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count digest activity: %w", err)
+	}
+
+	if s.Published, err = r.posts(ctx, `
+		SELECT id, title, slug, published_at, updated_at, 0
+		FROM content_posts
+		WHERE environment = $1 AND status = $2 AND published_at >= $3 AND published_at < $4
+		ORDER BY published_at DESC
+		LIMIT $5`,
+		models.EnvironmentLive, models.PostStatusPublished, from, to, limit,
+	); err != nil {
+		return nil, err
+	}
+
+	if s.Drafts, err = r.posts(ctx, `
+		SELECT id, title, slug, published_at, updated_at, 0
+		FROM content_posts
+		WHERE status = $1 OR environment = $2
+		ORDER BY updated_at DESC
+		LIMIT $3`,
+		models.PostStatusDraft, models.EnvironmentDraft, limit,
+	); err != nil {
+		return nil, err
+	}
+
+	if s.TopViewed, err = r.posts(ctx, `
+		SELECT cp.id, cp.title, cp.slug, cp.published_at, cp.updated_at, v.views
+		FROM (
+		  SELECT post_id, SUM(views) AS views
+		  FROM post_view_daily
+		  WHERE day >= $1::timestamptz::date AND day <= $2::timestamptz::date
+		  GROUP BY post_id
+		) v
+		JOIN content_posts cp ON cp.id = v.post_id
+		WHERE cp.environment = $3
+		ORDER BY v.views DESC, cp.id
+		LIMIT $4`,
+		from, to, models.EnvironmentLive, limit,
+	); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (r *DigestRepository) posts(ctx context.Context, query string, args ...interface{}) ([]models.DigestPost, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.DigestPost{}
+	for rows.Next() {
+		var p models.DigestPost
+		if err := rows.Scan(&p.ID, &p.Title, &p.Slug, &p.PublishedAt, &p.UpdatedAt, &p.Views); err != nil {
+			return nil, fmt.Errorf("failed to scan digest post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// DuplicateRepository stores the near-duplicate post pairs found by the
+// duplicate detector
+type DuplicateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDuplicateRepository(db *pgxpool.Pool) *DuplicateRepository {
+	return &DuplicateRepository{db: db}
+}
+
+// EachPostText streams the title and body of every live post that isn't
+// archived, calling fn for each
+func (r *DuplicateRepository) EachPostText(ctx context.Context, fn func(*models.PostText) error) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, title, COALESCE(content, '')
+		FROM content_posts
+		WHERE environment = 'live' AND status <> $1`,
+		models.PostStatusArchived)
+	if err != nil {
+		return fmt.Errorf("failed to query post texts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.PostText
+		if err := rows.Scan(&p.ID, &p.Title, &p.Content); err != nil {
+			return fmt.Errorf("failed to scan post text: %w", err)
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Replace swaps the stored pairs for pairs in one transaction, so the report
+// never shows a half-finished scan
+func (r *DuplicateRepository) Replace(ctx context.Context, pairs []models.DuplicatePair) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM post_duplicates`); err != nil {
+		return fmt.Errorf("failed to clear duplicates: %w", err)
+	}
+
+	rows := make([][]interface{}, len(pairs))
+	for i, p := range pairs {
+		rows[i] = []interface{}{p.Post.ID, p.Duplicate.ID, p.Similarity, p.DetectedAt}
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"post_duplicates"},
+		[]string{"post_id", "duplicate_id", "similarity", "detected_at"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("failed to store duplicates: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// List returns stored pairs matching filter, most similar first. Pairs
+// match a content type when either post has it.
+func (r *DuplicateRepository) List(ctx context.Context, filter models.DuplicateFilter) ([]models.DuplicatePair, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.MinSimilarity != nil {
+		cb.addf("d.similarity >= %s", *filter.MinSimilarity)
+	}
+	if filter.ContentTypeID != nil {
+		cb.addf("(a.content_type_id = %[1]s OR b.content_type_id = %[1]s)", *filter.ContentTypeID)
+	}
+	whereClause, args, argNum := cb.build()
+
+	const from = `
+		FROM post_duplicates d
+		JOIN content_posts a ON a.id = d.post_id
+		JOIN content_posts b ON b.id = d.duplicate_id`
+
+	var total int64
+	if err := r.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) %s %s", from, whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count duplicates: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.content_type_id, a.title, a.slug, a.status,
+		       b.id, b.content_type_id, b.title, b.slug, b.status,
+		       d.similarity, d.detected_at
+		%s
+		%s
+		ORDER BY d.similarity DESC, d.post_id, d.duplicate_id
+		LIMIT $%d OFFSET $%d`,
+		from, whereClause, argNum, argNum+1)
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := []models.DuplicatePair{}
+	for rows.Next() {
+		var p models.DuplicatePair
+		if err := rows.Scan(
+			&p.Post.ID, &p.Post.ContentTypeID, &p.Post.Title, &p.Post.Slug, &p.Post.Status,
+			&p.Duplicate.ID, &p.Duplicate.ContentTypeID, &p.Duplicate.Title, &p.Duplicate.Slug, &p.Duplicate.Status,
+			&p.Similarity, &p.DetectedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan duplicate pair: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+
+	return pairs, total, nil
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type EmailRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailRepository(db *pgxpool.Pool) *EmailRepository {
+	return &EmailRepository{db: db}
+}
+
+// emailSortColumns are the columns list results can be sorted by
+var emailSortColumns = []string{"created_at", "sent_at", "status", "next_attempt_at", "to_address"}
+
+const emailColumns = `id, to_address, template_name, locale, subject, body_html, body_text, status,
+		       attempts, last_error, next_attempt_at, sent_at, opened_at, campaign_id, request_id, created_at`
+
+func scanEmail(row pgx.Row) (*models.Email, error) {
+	e := &models.Email{}
+	err := row.Scan(
+		&e.ID, &e.ToAddress, &e.TemplateName, &e.Locale, &e.Subject, &e.BodyHTML, &e.BodyText, &e.Status,
+		&e.Attempts, &e.LastError, &e.NextAttemptAt, &e.SentAt, &e.OpenedAt, &e.CampaignID, &e.RequestID, &e.CreatedAt,
+	)
+	return e, err
+}
+
+// Enqueue stores an already rendered email for delivery
+func (r *EmailRepository) Enqueue(ctx context.Context, email *models.Email) (*models.Email, error) {
+	email.ID = uuid.New()
+	email.Status = models.EmailStatusPending
+
+	query := `
+		INSERT INTO email_queue (id, to_address, template_name, locale, subject, body_html, body_text, status, campaign_id, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING next_attempt_at, created_at`
+
+	err := r.db.QueryRow(ctx, query,
+		email.ID, email.ToAddress, email.TemplateName, email.Locale, email.Subject,
+		email.BodyHTML, email.BodyText, email.Status, email.CampaignID, email.RequestID,
+	).Scan(&email.NextAttemptAt, &email.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return email, nil
+}
+
+// QueuedSince reports whether an email rendered from one of templates was
+// queued for an address since the given time
+func (r *EmailRepository) QueuedSince(ctx context.Context, to string, templates []string, since time.Time) (bool, error) {
+	var queued bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM email_queue
+			WHERE LOWER(to_address) = LOWER($1) AND template_name = ANY($2) AND created_at >= $3
+		)`, to, templates, since,
+	).Scan(&queued)
+	if err != nil {
+		return false, fmt.Errorf("failed to check queued emails: %w", err)
+	}
+	return queued, nil
+}
+
+func (r *EmailRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Email, error) {
+	query := fmt.Sprintf(`SELECT %s FROM email_queue WHERE id = $1`, emailColumns)
+
+	email, err := scanEmail(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get email: %w", err)
+	}
+
+	return email, nil
+}
+
+func (r *EmailRepository) List(ctx context.Context, filter models.EmailFilter) ([]models.Email, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Status != nil {
+		cb.addf("status = %s", *filter.Status)
+	}
+	if filter.To != "" {
+		cb.addf("to_address ILIKE %s", "%"+filter.To+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM email_queue %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count emails: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", emailSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM email_queue
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		emailColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []models.Email
+	for rows.Next() {
+		email, err := scanEmail(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, *email)
+	}
+
+	return emails, total, nil
+}
+
+// ClaimDue marks up to limit pending emails whose next attempt is due as
+// sending and returns them. SKIP LOCKED lets several workers share the queue.
+func (r *EmailRepository) ClaimDue(ctx context.Context, limit int) ([]models.Email, error) {
+	query := fmt.Sprintf(`
+		UPDATE email_queue
+		SET status = $1, attempts = attempts + 1, next_attempt_at = NOW()
+		WHERE id IN (
+			SELECT id FROM email_queue
+			WHERE status = $2 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, emailColumns)
+
+	rows, err := r.db.Query(ctx, query, models.EmailStatusSending, models.EmailStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []models.Email
+	for rows.Next() {
+		email, err := scanEmail(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		emails = append(emails, *email)
+	}
+
+	return emails, nil
+}
+
+func (r *EmailRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE email_queue SET status = $1, sent_at = NOW(), last_error = NULL WHERE id = $2`,
+		models.EmailStatusSent, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark email sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a delivery error. The email is retried at retryAt unless
+// it has used up its attempts, in which case it is marked failed for good.
+func (r *EmailRepository) MarkFailed(ctx context.Context, id uuid.UUID, sendErr error, retryAt *time.Time) error {
+	status := models.EmailStatusFailed
+	nextAttempt := time.Now()
+	if retryAt != nil {
+		status = models.EmailStatusPending
+		nextAttempt = *retryAt
+	}
+
+	_, err := r.db.Exec(ctx,
+		`UPDATE email_queue SET status = $1, last_error = $2, next_attempt_at = $3 WHERE id = $4`,
+		status, sendErr.Error(), nextAttempt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark email failed: %w", err)
+	}
+	return nil
+}
+
+// Retry puts a failed email back in the queue
+func (r *EmailRepository) Retry(ctx context.Context, id uuid.UUID) (*models.Email, error) {
+	query := fmt.Sprintf(`
+		UPDATE email_queue
+		SET status = $1, attempts = 0, next_attempt_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING %s`, emailColumns)
+
+	email, err := scanEmail(r.db.QueryRow(ctx, query, models.EmailStatusPending, id, models.EmailStatusFailed))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to retry email: %w", err)
+	}
+
+	return email, nil
+}
+
+// ReleaseStale returns emails stuck in sending (e.g. after a crash) to the queue
+func (r *EmailRepository) ReleaseStale(ctx context.Context, olderThan time.Duration) (int64, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE email_queue SET status = $1 WHERE status = $2 AND next_attempt_at < $3`,
+		models.EmailStatusPending, models.EmailStatusSending, time.Now().Add(-olderThan),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale emails: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// MarkOpened records the first time the tracking pixel of an email was loaded
+func (r *EmailRepository) MarkOpened(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE email_queue SET opened_at = NOW() WHERE id = $1 AND opened_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark email opened: %w", err)
+	}
+	return nil
+}
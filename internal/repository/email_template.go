@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type EmailTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailTemplateRepository(db *pgxpool.Pool) *EmailTemplateRepository {
+	return &EmailTemplateRepository{db: db}
+}
+
+// emailTemplateSortColumns are the columns list results can be sorted by
+var emailTemplateSortColumns = []string{"name", "locale", "created_at", "updated_at"}
+
+func (r *EmailTemplateRepository) Create(ctx context.Context, req *models.CreateEmailTemplateRequest) (*models.EmailTemplate, error) {
+	t := &models.EmailTemplate{
+		ID:       uuid.New(),
+		Name:     req.Name,
+		Locale:   req.Locale,
+		Subject:  req.Subject,
+		BodyHTML: req.BodyHTML,
+		BodyText: req.BodyText,
+	}
+
+	query := `
+		INSERT INTO email_templates (id, name, locale, subject, body_html, body_text)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRow(ctx, query,
+		t.ID, t.Name, t.Locale, t.Subject, t.BodyHTML, t.BodyText,
+	).Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create email template: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *EmailTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.EmailTemplate, error) {
+	query := `
+		SELECT id, name, locale, subject, body_html, body_text, created_at, updated_at
+		FROM email_templates
+		WHERE id = $1`
+
+	t := &models.EmailTemplate{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&t.ID, &t.Name, &t.Locale, &t.Subject, &t.BodyHTML, &t.BodyText, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	return t, nil
+}
+
+// GetByNameAndLocale returns the template variant stored for an exact locale
+func (r *EmailTemplateRepository) GetByNameAndLocale(ctx context.Context, name, locale string) (*models.EmailTemplate, error) {
+	query := `
+		SELECT id, name, locale, subject, body_html, body_text, created_at, updated_at
+		FROM email_templates
+		WHERE name = $1 AND locale = $2`
+
+	t := &models.EmailTemplate{}
+	err := r.db.QueryRow(ctx, query, name, locale).Scan(
+		&t.ID, &t.Name, &t.Locale, &t.Subject, &t.BodyHTML, &t.BodyText, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *EmailTemplateRepository) List(ctx context.Context, filter models.EmailTemplateFilter) ([]models.EmailTemplate, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Name != "" {
+		cb.addf("name = %s", filter.Name)
+	}
+	if filter.Locale != "" {
+		cb.addf("locale = %s", filter.Locale)
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM email_templates %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count email templates: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "name ASC, locale ASC", "", emailTemplateSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, name, locale, subject, body_html, body_text, created_at, updated_at
+		FROM email_templates
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list email templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []models.EmailTemplate
+	for rows.Next() {
+		var t models.EmailTemplate
+		if err := rows.Scan(
+			&t.ID, &t.Name, &t.Locale, &t.Subject, &t.BodyHTML, &t.BodyText, &t.CreatedAt, &t.UpdatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan email template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, total, nil
+}
+
+func (r *EmailTemplateRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateEmailTemplateRequest) (*models.EmailTemplate, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if req.Subject != nil {
+		setClauses = append(setClauses, fmt.Sprintf("subject = $%d", argNum))
+		args = append(args, *req.Subject)
+		argNum++
+	}
+	if req.BodyHTML != nil {
+		setClauses = append(setClauses, fmt.Sprintf("body_html = $%d", argNum))
+		args = append(args, *req.BodyHTML)
+		argNum++
+	}
+	if req.BodyText != nil {
+		setClauses = append(setClauses, fmt.Sprintf("body_text = $%d", argNum))
+		args = append(args, *req.BodyText)
+		argNum++
+	}
+
+	if len(setClauses) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	setClauses = append(setClauses, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE email_templates
+		SET %s
+		WHERE id = $%d
+		RETURNING id, name, locale, subject, body_html, body_text, created_at, updated_at`,
+		strings.Join(setClauses, ", "), argNum)
+
+	t := &models.EmailTemplate{}
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&t.ID, &t.Name, &t.Locale, &t.Subject, &t.BodyHTML, &t.BodyText, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update email template: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r *EmailTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM email_templates WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete email template: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
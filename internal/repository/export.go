@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ExportRepository reads interaction and content data in bulk for external
+// data pipelines
+type ExportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExportRepository(db *pgxpool.Pool) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// EachInteraction streams daily post views and poll votes matching filter,
+// oldest first, calling fn for each. Poll vote users are the stored voter
+// hashes; callers must anonymize them before handing them out.
+func (r *ExportRepository) EachInteraction(ctx context.Context, filter models.InteractionFilter, fn func(*models.Interaction) error) error {
+	include := func(event string) bool {
+		if len(filter.Events) == 0 {
+			return true
+		}
+		for _, e := range filter.Events {
+			if e == event {
+				return true
+			}
+		}
+		return false
+	}
+
+	var args []interface{}
+	rangeConditions := func(column string) string {
+		var conditions []string
+		if filter.From != nil {
+			args = append(args, *filter.From)
+			conditions = append(conditions, fmt.Sprintf("%s >= $%d", column, len(args)))
+		}
+		if filter.To != nil {
+			args = append(args, *filter.To)
+			conditions = append(conditions, fmt.Sprintf("%s < $%d", column, len(args)))
+		}
+		if len(conditions) == 0 {
+			return ""
+		}
+		return "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var selects []string
+	if include(models.InteractionView) {
+		selects = append(selects, fmt.Sprintf(`
+			SELECT 'view'::text, post_id, NULL::uuid, NULL::uuid, NULL::text, views, (day::timestamp AT TIME ZONE 'UTC') AS at
+			FROM post_view_daily
+			%s`, rangeConditions("(day::timestamp AT TIME ZONE 'UTC')")))
+	}
+	if include(models.InteractionPollVote) {
+		selects = append(selects, fmt.Sprintf(`
+			SELECT 'poll_vote', NULL::uuid, poll_id, option_id, voter_hash::text, 1, created_at AS at
+			FROM poll_votes
+			%s`, rangeConditions("created_at")))
+	}
+	if len(selects) == 0 {
+		return nil
+	}
+
+	query := strings.Join(selects, " UNION ALL ") + " ORDER BY at"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export interactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var interaction models.Interaction
+		var user *string
+		if err := rows.Scan(&interaction.Event, &interaction.PostID, &interaction.PollID, &interaction.OptionID,
+			&user, &interaction.Count, &interaction.At); err != nil {
+			return fmt.Errorf("failed to scan interaction: %w", err)
+		}
+		if user != nil {
+			interaction.User = *user
+		}
+		if err := fn(&interaction); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachContentFeatures streams feature rows for the published live posts,
+// newest first. Word and character counts are taken from the content with
+// HTML tags removed.
+func (r *ExportRepository) EachContentFeatures(ctx context.Context, fn func(*models.ContentFeatures) error) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT cp.id, ct.slug,
+			COALESCE((SELECT array_agg(t.slug ORDER BY t.slug)
+				FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = cp.id), '{}'),
+			char_length(cp.title),
+			COALESCE(array_length(regexp_split_to_array(NULLIF(btrim(txt.plain), ''), '\s+'), 1), 0),
+			char_length(btrim(txt.plain)),
+			(SELECT COUNT(*) FROM post_media pm WHERE pm.post_id = cp.id),
+			cp.view_count, cp.published_at, cp.updated_at
+		FROM content_posts cp
+		JOIN content_types ct ON ct.id = cp.content_type_id
+		CROSS JOIN LATERAL (
+			SELECT regexp_replace(COALESCE(cp.content, ''), '<[^>]*>', ' ', 'g') AS plain
+		) txt
+		WHERE cp.environment = 'live'
+		  AND cp.status = $1
+		  AND (cp.published_at IS NULL OR cp.published_at <= NOW())
+		ORDER BY cp.published_at DESC NULLS LAST, cp.id`,
+		models.PostStatusPublished,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to export content features: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f models.ContentFeatures
+		if err := rows.Scan(&f.PostID, &f.ContentType, &f.Tags, &f.TitleLength, &f.WordCount,
+			&f.CharCount, &f.MediaCount, &f.ViewCount, &f.PublishedAt, &f.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to scan content features: %w", err)
+		}
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachOutlineEntry streams every content type with its live posts (of any
+// status) in a single query, calling fn for each row. groupBy, when set, is
+// the metadata field posts are grouped by within their type.
+func (r *ExportRepository) EachOutlineEntry(ctx context.Context, groupBy string, fn func(*models.OutlineEntry) error) error {
+	var args []interface{}
+	group := "NULL::text"
+	if groupBy != "" {
+		args = append(args, groupBy)
+		group = "cp.metadata->>$1"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT ct.id, ct.name, ct.slug, %[1]s, cp.title, cp.slug, cp.status
+		FROM content_types ct
+		LEFT JOIN content_posts cp ON cp.content_type_id = ct.id AND cp.environment = 'live'
+		ORDER BY ct.display_order, ct.name, ct.id, %[1]s NULLS FIRST, cp.title, cp.id`, group)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query content outline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.OutlineEntry
+		if err := rows.Scan(&e.ContentTypeID, &e.ContentTypeName, &e.ContentTypeSlug, &e.Group,
+			&e.PostTitle, &e.PostSlug, &e.PostStatus); err != nil {
+			return fmt.Errorf("failed to scan outline entry: %w", err)
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
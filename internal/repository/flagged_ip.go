@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type FlaggedIPRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewFlaggedIPRepository(db *pgxpool.Pool) *FlaggedIPRepository {
+	return &FlaggedIPRepository{db: db}
+}
+
+// flaggedIPSortColumns are the columns list results can be sorted by
+var flaggedIPSortColumns = []string{"ip", "status", "flag_count", "expires_at", "created_at", "updated_at"}
+
+const flaggedIPColumns = "ip, status, manual, reason, flag_count, expires_at, created_at, updated_at"
+
+func scanFlaggedIP(row pgx.Row, f *models.FlaggedIP) error {
+	return row.Scan(&f.IP, &f.Status, &f.Manual, &f.Reason, &f.FlagCount, &f.ExpiresAt, &f.CreatedAt, &f.UpdatedAt)
+}
+
+// Get returns the entry for ip, expired or not
+func (r *FlaggedIPRepository) Get(ctx context.Context, ip string) (*models.FlaggedIP, error) {
+	f := &models.FlaggedIP{}
+	err := scanFlaggedIP(r.db.QueryRow(ctx, "SELECT "+flaggedIPColumns+" FROM flagged_ips WHERE ip = $1", ip), f)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get flagged IP: %w", err)
+	}
+	return f, nil
+}
+
+func (r *FlaggedIPRepository) List(ctx context.Context, filter models.FlaggedIPFilter) ([]models.FlaggedIP, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Status != nil {
+		cb.addf("status = %s", *filter.Status)
+	}
+	if filter.Manual != nil {
+		cb.addf("manual = %s", *filter.Manual)
+	}
+	if filter.Active != nil {
+		if *filter.Active {
+			cb.add("(expires_at IS NULL OR expires_at > NOW())")
+		} else {
+			cb.add("expires_at <= NOW()")
+		}
+	}
+	if filter.Search != "" {
+		cb.addf("(ip ILIKE %[1]s OR reason ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM flagged_ips %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count flagged IPs: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "updated_at DESC", "", flaggedIPSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM flagged_ips
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		flaggedIPColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list flagged IPs: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []models.FlaggedIP
+	for rows.Next() {
+		var f models.FlaggedIP
+		if err := scanFlaggedIP(rows, &f); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan flagged IP: %w", err)
+		}
+		flags = append(flags, f)
+	}
+
+	return flags, total, nil
+}
+
+// Flag records an escalation by the anomaly detector. Manual entries are
+// left alone, in which case ErrNotFound is returned.
+func (r *FlaggedIPRepository) Flag(ctx context.Context, ip string, status models.IPFlagStatus, reason string, expiresAt time.Time) (*models.FlaggedIP, error) {
+	query := `
+		INSERT INTO flagged_ips (ip, status, reason, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ip) DO UPDATE
+		SET status = EXCLUDED.status,
+		    reason = EXCLUDED.reason,
+		    expires_at = EXCLUDED.expires_at,
+		    flag_count = flagged_ips.flag_count + 1
+		WHERE NOT flagged_ips.manual
+		RETURNING ` + flaggedIPColumns
+
+	f := &models.FlaggedIP{}
+	if err := scanFlaggedIP(r.db.QueryRow(ctx, query, ip, status, reason, expiresAt), f); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to flag IP: %w", err)
+	}
+	return f, nil
+}
+
+// Set stores a manual override for ip, replacing any detector entry
+func (r *FlaggedIPRepository) Set(ctx context.Context, ip string, req *models.SetFlaggedIPRequest) (*models.FlaggedIP, error) {
+	query := `
+		INSERT INTO flagged_ips (ip, status, manual, reason, flag_count, expires_at)
+		VALUES ($1, $2, true, $3, 0, $4)
+		ON CONFLICT (ip) DO UPDATE
+		SET status = EXCLUDED.status,
+		    manual = true,
+		    reason = EXCLUDED.reason,
+		    expires_at = EXCLUDED.expires_at
+		RETURNING ` + flaggedIPColumns
+
+	f := &models.FlaggedIP{}
+	if err := scanFlaggedIP(r.db.QueryRow(ctx, query, ip, req.Status, req.Reason, req.ExpiresAt), f); err != nil {
+		return nil, fmt.Errorf("failed to set flagged IP: %w", err)
+	}
+	return f, nil
+}
+
+// Delete clears the entry for ip, manual or not
+func (r *FlaggedIPRepository) Delete(ctx context.Context, ip string) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM flagged_ips WHERE ip = $1", ip)
+	if err != nil {
+		return fmt.Errorf("failed to delete flagged IP: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
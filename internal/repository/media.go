@@ -21,6 +21,9 @@ func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
 	return &MediaRepository{db: db}
 }
 
+// mediaSortColumns are the columns list results can be sorted by
+var mediaSortColumns = []string{"created_at", "file_name", "file_type", "file_size"}
+
 func (r *MediaRepository) Create(ctx context.Context, req *models.CreateMediaRequest) (*models.Media, error) {
 	media := &models.Media{
 		ID:         uuid.New(),
@@ -35,25 +38,31 @@ func (r *MediaRepository) Create(ctx context.Context, req *models.CreateMediaReq
 		Variants:   req.Variants,
 		AltText:    req.AltText,
 		Checksum:   req.Checksum,
+		TeamID:     req.TeamID,
 	}
 
 	query := `
 		INSERT INTO media (id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		                   mime_type, file_size, dimensions, variants, alt_text, checksum)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		                   mime_type, file_size, dimensions, variants, alt_text, checksum, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING created_at
 	`
 
 	err := r.db.QueryRow(ctx, query,
 		media.ID, media.FileName, media.ObjectKey, media.BucketName, media.CDNUrl,
 		media.FileType, media.MimeType, media.FileSize, media.Dimensions, media.Variants,
-		media.AltText, media.Checksum,
+		media.AltText, media.Checksum, media.TeamID,
 	).Scan(&media.CreatedAt)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, ErrDuplicate
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, ErrDuplicate
+			case "23503":
+				return nil, ErrForeignKey
+			}
 		}
 		return nil, fmt.Errorf("failed to create media: %w", err)
 	}
@@ -64,7 +73,7 @@ func (r *MediaRepository) Create(ctx context.Context, req *models.CreateMediaReq
 func (r *MediaRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Media, error) {
 	query := `
 		SELECT id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		       mime_type, file_size, dimensions, variants, alt_text, checksum, created_at
+		       mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
 		FROM media
 		WHERE id = $1
 	`
@@ -73,7 +82,7 @@ func (r *MediaRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Me
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
 		&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
-		&media.AltText, &media.Checksum, &media.CreatedAt,
+		&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
 	)
 
 	if err != nil {
@@ -89,7 +98,7 @@ func (r *MediaRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Me
 func (r *MediaRepository) GetByObjectKey(ctx context.Context, objectKey string) (*models.Media, error) {
 	query := `
 		SELECT id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		       mime_type, file_size, dimensions, variants, alt_text, checksum, created_at
+		       mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
 		FROM media
 		WHERE object_key = $1
 	`
@@ -98,7 +107,7 @@ func (r *MediaRepository) GetByObjectKey(ctx context.Context, objectKey string)
 	err := r.db.QueryRow(ctx, query, objectKey).Scan(
 		&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
 		&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
-		&media.AltText, &media.Checksum, &media.CreatedAt,
+		&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
 	)
 
 	if err != nil {
@@ -114,25 +123,7 @@ func (r *MediaRepository) GetByObjectKey(ctx context.Context, objectKey string)
 func (r *MediaRepository) List(ctx context.Context, filter models.MediaFilter) ([]models.Media, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
-
-	if filter.FileType != nil {
-		conditions = append(conditions, fmt.Sprintf("file_type = $%d", argNum))
-		args = append(args, *filter.FileType)
-		argNum++
-	}
-	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(file_name ILIKE $%d OR alt_text ILIKE $%d)", argNum, argNum))
-		args = append(args, "%"+filter.Search+"%")
-		argNum++
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args, argNum := mediaFilterConditions(filter)
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM media %s", whereClause)
@@ -142,14 +133,11 @@ func (r *MediaRepository) List(ctx context.Context, filter models.MediaFilter) (
 	}
 
 	// Get data
-	orderBy := "created_at DESC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("%s %s", filter.SortBy, filter.SortDir)
-	}
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", mediaSortColumns...)
 
 	query := fmt.Sprintf(`
 		SELECT id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		       mime_type, file_size, dimensions, variants, alt_text, checksum, created_at
+		       mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
 		FROM media
 		%s
 		ORDER BY %s
@@ -170,7 +158,7 @@ func (r *MediaRepository) List(ctx context.Context, filter models.MediaFilter) (
 		if err := rows.Scan(
 			&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
 			&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
-			&media.AltText, &media.Checksum, &media.CreatedAt,
+			&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan media: %w", err)
 		}
@@ -180,6 +168,48 @@ func (r *MediaRepository) List(ctx context.Context, filter models.MediaFilter) (
 	return mediaList, total, nil
 }
 
+// ExportEach streams every media record matching the filter to fn along with
+// the number of posts it is attached to, ignoring pagination
+func (r *MediaRepository) ExportEach(ctx context.Context, filter models.MediaFilter, fn func(*models.Media, int64) error) error {
+	filter.PaginationParams.Normalize()
+
+	whereClause, args, _ := mediaFilterConditions(filter)
+
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", mediaSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, file_name, object_key, bucket_name, cdn_url, file_type,
+		       mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at,
+		       (SELECT COUNT(*) FROM post_media pm WHERE pm.media_id = media.id) AS usage_count
+		FROM media
+		%s
+		ORDER BY %s
+	`, whereClause, orderBy)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export media: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var media models.Media
+		var usageCount int64
+		if err := rows.Scan(
+			&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
+			&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
+			&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt, &usageCount,
+		); err != nil {
+			return fmt.Errorf("failed to scan media: %w", err)
+		}
+		if err := fn(&media, usageCount); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *MediaRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateMediaRequest) (*models.Media, error) {
 	var setClauses []string
 	var args []interface{}
@@ -210,6 +240,11 @@ func (r *MediaRepository) Update(ctx context.Context, id uuid.UUID, req *models.
 		args = append(args, *req.AltText)
 		argNum++
 	}
+	if req.TeamID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("team_id = NULLIF($%d, $%d::uuid)", argNum, argNum+1))
+		args = append(args, *req.TeamID, uuid.Nil)
+		argNum += 2
+	}
 
 	if len(setClauses) == 0 {
 		return r.GetByID(ctx, id)
@@ -221,47 +256,64 @@ func (r *MediaRepository) Update(ctx context.Context, id uuid.UUID, req *models.
 		SET %s
 		WHERE id = $%d
 		RETURNING id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		          mime_type, file_size, dimensions, variants, alt_text, checksum, created_at
+		          mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
 	`, strings.Join(setClauses, ", "), argNum)
 
 	media := &models.Media{}
 	err := r.db.QueryRow(ctx, query, args...).Scan(
 		&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
 		&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
-		&media.AltText, &media.Checksum, &media.CreatedAt,
+		&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
 		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
 		return nil, fmt.Errorf("failed to update media: %w", err)
 	}
 
 	return media, nil
 }
 
-func (r *MediaRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result, err := r.db.Exec(ctx, `DELETE FROM media WHERE id = $1`, id)
+// Delete removes a media record and returns it, so its files can be
+// removed from storage
+func (r *MediaRepository) Delete(ctx context.Context, id uuid.UUID) (*models.Media, error) {
+	query := `
+		DELETE FROM media
+		WHERE id = $1
+		RETURNING id, file_name, object_key, bucket_name, cdn_url, file_type,
+		          mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
+	`
+
+	media := &models.Media{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
+		&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
+		&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
+	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return ErrForeignKey
+			return nil, ErrForeignKey
 		}
-		return fmt.Errorf("failed to delete media: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrNotFound
+		return nil, fmt.Errorf("failed to delete media: %w", err)
 	}
 
-	return nil
+	return media, nil
 }
 
 func (r *MediaRepository) GetByChecksum(ctx context.Context, checksum string) (*models.Media, error) {
 	query := `
 		SELECT id, file_name, object_key, bucket_name, cdn_url, file_type, 
-		       mime_type, file_size, dimensions, variants, alt_text, checksum, created_at
+		       mime_type, file_size, dimensions, variants, alt_text, checksum, team_id, created_at
 		FROM media
 		WHERE checksum = $1
 	`
@@ -270,7 +322,7 @@ func (r *MediaRepository) GetByChecksum(ctx context.Context, checksum string) (*
 	err := r.db.QueryRow(ctx, query, checksum).Scan(
 		&media.ID, &media.FileName, &media.ObjectKey, &media.BucketName, &media.CDNUrl,
 		&media.FileType, &media.MimeType, &media.FileSize, &media.Dimensions, &media.Variants,
-		&media.AltText, &media.Checksum, &media.CreatedAt,
+		&media.AltText, &media.Checksum, &media.TeamID, &media.CreatedAt,
 	)
 
 	if err != nil {
@@ -282,3 +334,19 @@ func (r *MediaRepository) GetByChecksum(ctx context.Context, checksum string) (*
 
 	return media, nil
 }
+
+// mediaFilterConditions builds the WHERE clause shared by List and ExportEach
+func mediaFilterConditions(filter models.MediaFilter) (string, []interface{}, int) {
+	var cb conditionBuilder
+	if filter.FileType != nil {
+		cb.addf("file_type = %s", *filter.FileType)
+	}
+	if filter.Search != "" {
+		cb.addf("(file_name ILIKE %[1]s OR alt_text ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+	if filter.TeamID != nil {
+		cb.addf("team_id = %s", *filter.TeamID)
+	}
+
+	return cb.build()
+}
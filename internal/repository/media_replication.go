@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type MediaReplicationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaReplicationRepository(db *pgxpool.Pool) *MediaReplicationRepository {
+	return &MediaReplicationRepository{db: db}
+}
+
+// Enqueue queues object keys of a media record for copying. Keys already
+// queued or copied are left alone.
+func (r *MediaReplicationRepository) Enqueue(ctx context.Context, mediaID uuid.UUID, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO media_replications (media_id, object_key)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (media_id, object_key) DO NOTHING`,
+		mediaID, keys,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue media replication: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue marks up to limit pending copies whose next attempt is due as
+// copying and returns them. SKIP LOCKED lets several workers share the queue.
+func (r *MediaReplicationRepository) ClaimDue(ctx context.Context, limit int) ([]models.MediaReplication, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE media_replications
+			SET status = $1, attempts = attempts + 1, next_attempt_at = NOW()
+			WHERE (media_id, object_key) IN (
+				SELECT media_id, object_key FROM media_replications
+				WHERE status = $2 AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT c.media_id, c.object_key, m.bucket_name, m.mime_type, c.status, c.attempts,
+		       c.last_error, c.next_attempt_at, c.replicated_at, c.created_at
+		FROM claimed c
+		JOIN media m ON m.id = c.media_id`
+
+	rows, err := r.db.Query(ctx, query, models.ReplicationCopying, models.ReplicationPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim media replications: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.MediaReplication
+	for rows.Next() {
+		var item models.MediaReplication
+		if err := rows.Scan(
+			&item.MediaID, &item.ObjectKey, &item.BucketName, &item.MimeType, &item.Status, &item.Attempts,
+			&item.LastError, &item.NextAttemptAt, &item.ReplicatedAt, &item.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan media replication: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *MediaReplicationRepository) MarkReplicated(ctx context.Context, mediaID uuid.UUID, objectKey string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE media_replications SET status = $1, replicated_at = NOW(), last_error = NULL
+		 WHERE media_id = $2 AND object_key = $3`,
+		models.ReplicationReplicated, mediaID, objectKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark media replicated: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a copy error. The copy is retried at retryAt unless it
+// has used up its attempts, in which case it is marked failed for good.
+func (r *MediaReplicationRepository) MarkFailed(ctx context.Context, mediaID uuid.UUID, objectKey string, copyErr error, retryAt *time.Time) error {
+	status := models.ReplicationFailed
+	nextAttempt := time.Now()
+	if retryAt != nil {
+		status = models.ReplicationPending
+		nextAttempt = *retryAt
+	}
+
+	_, err := r.db.Exec(ctx,
+		`UPDATE media_replications SET status = $1, last_error = $2, next_attempt_at = $3
+		 WHERE media_id = $4 AND object_key = $5`,
+		status, copyErr.Error(), nextAttempt, mediaID, objectKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark media replication failed: %w", err)
+	}
+	return nil
+}
+
+// ReleaseStale returns copies stuck in copying (e.g. after a crash) to the queue
+func (r *MediaReplicationRepository) ReleaseStale(ctx context.Context, olderThan time.Duration) (int64, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE media_replications SET status = $1 WHERE status = $2 AND next_attempt_at < $3`,
+		models.ReplicationPending, models.ReplicationCopying, time.Now().Add(-olderThan),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale media replications: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// RetryFailed puts every failed copy back in the queue
+func (r *MediaReplicationRepository) RetryFailed(ctx context.Context) (int64, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE media_replications SET status = $1, attempts = 0, next_attempt_at = NOW() WHERE status = $2`,
+		models.ReplicationPending, models.ReplicationFailed,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retry media replications: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListMedia returns a page of media records oldest first, starting after the
+// given record, for queueing files uploaded before replication was enabled
+func (r *MediaReplicationRepository) ListMedia(ctx context.Context, afterCreatedAt time.Time, afterID uuid.UUID, limit int) ([]models.Media, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, object_key, variants, created_at
+		FROM media
+		WHERE (created_at, id) > ($1, $2)
+		ORDER BY created_at, id
+		LIMIT $3`,
+		afterCreatedAt, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	defer rows.Close()
+
+	var media []models.Media
+	for rows.Next() {
+		var m models.Media
+		if err := rows.Scan(&m.ID, &m.ObjectKey, &m.Variants, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		media = append(media, m)
+	}
+
+	return media, nil
+}
+
+// Stats counts copies by status and finds the oldest one still waiting.
+// Lag and health are left to the caller, which knows the threshold.
+func (r *MediaReplicationRepository) Stats(ctx context.Context) (*models.ReplicationStats, error) {
+	stats := &models.ReplicationStats{Enabled: true}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status IN ($1, $2)),
+			COUNT(*) FILTER (WHERE status = $3),
+			COUNT(*) FILTER (WHERE status = $4),
+			MIN(created_at) FILTER (WHERE status IN ($1, $2)),
+			MAX(replicated_at)
+		FROM media_replications`,
+		models.ReplicationPending, models.ReplicationCopying, models.ReplicationFailed, models.ReplicationReplicated,
+	).Scan(&stats.Pending, &stats.Failed, &stats.Replicated, &stats.OldestPendingAt, &stats.LastReplicatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replication stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// PartitionTables are the tables migration 4 partitions by month,
+// mapped to whether their partition key is a date rather than a timestamp
+var PartitionTables = map[string]bool{
+	"access_log":          false,
+	"contact_submissions": false,
+	"post_view_daily":     true,
+	"post_traffic_daily":  true,
+}
+
+// partitionMonthFormat is the suffix of partition names after "_p"
+const partitionMonthFormat = "200601"
+
+// PartitionRepository manages the monthly partitions of partitioned tables
+type PartitionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPartitionRepository(db *pgxpool.Pool) *PartitionRepository {
+	return &PartitionRepository{db: db}
+}
+
+// IsPartitioned reports whether table has been partitioned by migration 4
+func (r *PartitionRepository) IsPartitioned(ctx context.Context, table string) (bool, error) {
+	var partitioned bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass($1))`,
+		table,
+	).Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check partitioning of %s: %w", table, err)
+	}
+	return partitioned, nil
+}
+
+// ListPartitions returns the monthly partitions of table, oldest first.
+// Partitions not named <table>_pYYYYMM are left out.
+func (r *PartitionRepository) ListPartitions(ctx context.Context, table string) ([]models.TablePartition, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass($1)
+		ORDER BY c.relname`,
+		table,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var partitions []models.TablePartition
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+		suffix, ok := strings.CutPrefix(name, table+"_p")
+		if !ok {
+			continue
+		}
+		month, err := time.Parse(partitionMonthFormat, suffix)
+		if err != nil {
+			continue
+		}
+		partitions = append(partitions, models.TablePartition{Name: name, Month: month})
+	}
+	return partitions, rows.Err()
+}
+
+// CreatePartition adds the partition of table holding month, which must be
+// the first day of a month in UTC
+func (r *PartitionRepository) CreatePartition(ctx context.Context, table string, month time.Time) (*models.TablePartition, error) {
+	dateKey, ok := PartitionTables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not partitioned", table)
+	}
+
+	partition := &models.TablePartition{Name: table + "_p" + month.Format(partitionMonthFormat), Month: month}
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+		pgx.Identifier{partition.Name}.Sanitize(), pgx.Identifier{table}.Sanitize(),
+		partitionBound(month, dateKey), partitionBound(month.AddDate(0, 1, 0), dateKey))
+	if _, err := r.db.Exec(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to create partition %s: %w", partition.Name, err)
+	}
+	return partition, nil
+}
+
+// DropPartition drops a partition of table along with its rows
+func (r *PartitionRepository) DropPartition(ctx context.Context, table string, partition models.TablePartition) error {
+	if !strings.HasPrefix(partition.Name, table+"_p") {
+		return fmt.Errorf("%s is not a partition of %s", partition.Name, table)
+	}
+	if _, err := r.db.Exec(ctx, "DROP TABLE "+pgx.Identifier{partition.Name}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to drop partition %s: %w", partition.Name, err)
+	}
+	return nil
+}
+
+// partitionBound formats the start of month as a bound literal for a date
+// or timestamp partition key
+func partitionBound(month time.Time, dateKey bool) string {
+	if dateKey {
+		return month.Format("2006-01-02")
+	}
+	return month.Format("2006-01-02 15:04:05-07")
+}
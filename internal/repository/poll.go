@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type PollRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPollRepository(db *pgxpool.Pool) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+func (r *PollRepository) Create(ctx context.Context, req *models.CreatePollRequest) (*models.Poll, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	poll := &models.Poll{
+		ID:       uuid.New(),
+		Question: req.Question,
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+		DedupBy:  models.PollDedupIP,
+	}
+	if req.DedupBy != "" {
+		poll.DedupBy = req.DedupBy
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO polls (id, question, opens_at, closes_at, dedup_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at`,
+		poll.ID, poll.Question, poll.OpensAt, poll.ClosesAt, poll.DedupBy,
+	).Scan(&poll.CreatedAt, &poll.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	for i, label := range req.Options {
+		option := models.PollOption{ID: uuid.New(), Label: label, Position: i}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO poll_options (id, poll_id, label, position) VALUES ($1, $2, $3, $4)`,
+			option.ID, poll.ID, option.Label, option.Position,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create poll option: %w", err)
+		}
+		poll.Options = append(poll.Options, option)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return poll, nil
+}
+
+// GetByID returns a poll with its options and current vote counts
+func (r *PollRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Poll, error) {
+	poll := &models.Poll{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, question, opens_at, closes_at, dedup_by, created_at, updated_at
+		FROM polls
+		WHERE id = $1`, id,
+	).Scan(&poll.ID, &poll.Question, &poll.OpensAt, &poll.ClosesAt, &poll.DedupBy, &poll.CreatedAt, &poll.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if err := r.attachResults(ctx, []*models.Poll{poll}); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// GetByIDs returns the polls with the given IDs (with results), skipping
+// unknown IDs and keeping the order of ids
+func (r *PollRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Poll, error) {
+	if len(ids) == 0 {
+		return []models.Poll{}, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, question, opens_at, closes_at, dedup_by, created_at, updated_at
+		FROM polls
+		WHERE id = ANY($1)`, ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[uuid.UUID]*models.Poll)
+	for rows.Next() {
+		poll := &models.Poll{}
+		if err := rows.Scan(&poll.ID, &poll.Question, &poll.OpensAt, &poll.ClosesAt, &poll.DedupBy, &poll.CreatedAt, &poll.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan poll: %w", err)
+		}
+		byID[poll.ID] = poll
+	}
+	rows.Close()
+
+	var found []*models.Poll
+	for _, id := range ids {
+		if poll, ok := byID[id]; ok {
+			found = append(found, poll)
+		}
+	}
+	if err := r.attachResults(ctx, found); err != nil {
+		return nil, err
+	}
+
+	polls := make([]models.Poll, 0, len(found))
+	for _, poll := range found {
+		polls = append(polls, *poll)
+	}
+	return polls, nil
+}
+
+func (r *PollRepository) List(ctx context.Context, filter models.PollFilter) ([]models.Poll, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var total int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM polls`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count polls: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, question, opens_at, closes_at, dedup_by, created_at, updated_at
+		FROM polls
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2`,
+		filter.Limit(), filter.Offset(),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list polls: %w", err)
+	}
+	defer rows.Close()
+
+	var polls []*models.Poll
+	for rows.Next() {
+		poll := &models.Poll{}
+		if err := rows.Scan(&poll.ID, &poll.Question, &poll.OpensAt, &poll.ClosesAt, &poll.DedupBy, &poll.CreatedAt, &poll.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan poll: %w", err)
+		}
+		polls = append(polls, poll)
+	}
+	rows.Close()
+
+	if err := r.attachResults(ctx, polls); err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]models.Poll, 0, len(polls))
+	for _, poll := range polls {
+		result = append(result, *poll)
+	}
+	return result, total, nil
+}
+
+// attachResults loads the options of polls with their vote counts and shares
+func (r *PollRepository) attachResults(ctx context.Context, polls []*models.Poll) error {
+	if len(polls) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Poll, len(polls))
+	ids := make([]uuid.UUID, 0, len(polls))
+	for _, poll := range polls {
+		poll.Options = []models.PollOption{}
+		poll.TotalVotes = 0
+		byID[poll.ID] = poll
+		ids = append(ids, poll.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT o.poll_id, o.id, o.label, o.position, COUNT(v.voter_hash)
+		FROM poll_options o
+		LEFT JOIN poll_votes v ON v.option_id = o.id
+		WHERE o.poll_id = ANY($1)
+		GROUP BY o.id
+		ORDER BY o.poll_id, o.position`, ids,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get poll results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pollID uuid.UUID
+		var option models.PollOption
+		if err := rows.Scan(&pollID, &option.ID, &option.Label, &option.Position, &option.Votes); err != nil {
+			return fmt.Errorf("failed to scan poll option: %w", err)
+		}
+		poll := byID[pollID]
+		poll.Options = append(poll.Options, option)
+		poll.TotalVotes += option.Votes
+	}
+
+	for _, poll := range polls {
+		if poll.TotalVotes == 0 {
+			continue
+		}
+		for i := range poll.Options {
+			poll.Options[i].Share = float64(poll.Options[i].Votes) / float64(poll.TotalVotes)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *PollRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdatePollRequest) (*models.Poll, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if req.Question != nil {
+		setClauses = append(setClauses, fmt.Sprintf("question = $%d", argNum))
+		args = append(args, *req.Question)
+		argNum++
+	}
+	if req.OpensAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("opens_at = $%d", argNum))
+		args = append(args, *req.OpensAt)
+		argNum++
+	}
+	if req.ClosesAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("closes_at = $%d", argNum))
+		args = append(args, *req.ClosesAt)
+		argNum++
+	}
+	if req.DedupBy != nil {
+		setClauses = append(setClauses, fmt.Sprintf("dedup_by = $%d", argNum))
+		args = append(args, *req.DedupBy)
+		argNum++
+	}
+
+	if len(setClauses) > 0 {
+		args = append(args, id)
+		query := fmt.Sprintf(`UPDATE polls SET %s WHERE id = $%d`, strings.Join(setClauses, ", "), argNum)
+
+		result, err := r.db.Exec(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update poll: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, ErrNotFound
+		}
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *PollRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM polls WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete poll: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Vote records a vote for an option of a poll. It returns ErrNotFound when
+// the option does not belong to the poll and ErrDuplicate when the voter has
+// already voted.
+func (r *PollRepository) Vote(ctx context.Context, pollID, optionID uuid.UUID, voterHash string) error {
+	result, err := r.db.Exec(ctx, `
+		INSERT INTO poll_votes (poll_id, option_id, voter_hash)
+		SELECT poll_id, id, $3 FROM poll_options WHERE id = $2 AND poll_id = $1`,
+		pollID, optionID, voterHash,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("failed to record vote: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type PostTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostTemplateRepository(db *pgxpool.Pool) *PostTemplateRepository {
+	return &PostTemplateRepository{db: db}
+}
+
+// postTemplateSortColumns are the columns list results can be sorted by
+var postTemplateSortColumns = []string{"created_at", "updated_at", "name"}
+
+// postTemplateSelect leaves out tags deleted since the template was saved
+const postTemplateSelect = `
+	SELECT t.id, t.name, t.description, t.content_type_id, t.team_id, t.author_id,
+	       t.title_pattern, t.excerpt, t.content, t.metadata,
+	       ARRAY(SELECT tg.id FROM tags tg WHERE tg.id = ANY(t.tag_ids) ORDER BY tg.name),
+	       t.is_active, t.created_at, t.updated_at
+	FROM post_templates t`
+
+func scanPostTemplate(row pgx.Row) (*models.PostTemplate, error) {
+	t := &models.PostTemplate{}
+	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.ContentTypeID, &t.TeamID, &t.AuthorID,
+		&t.TitlePattern, &t.Excerpt, &t.Content, &t.Metadata,
+		&t.TagIDs, &t.IsActive, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// postTemplateWriteError maps constraint violations to repository errors
+func postTemplateWriteError(action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrDuplicate
+		case "23503":
+			return ErrForeignKey
+		}
+	}
+	return fmt.Errorf("failed to %s post template: %w", action, err)
+}
+
+func (r *PostTemplateRepository) Create(ctx context.Context, req *models.CreatePostTemplateRequest) (*models.PostTemplate, error) {
+	id := uuid.New()
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO post_templates (id, name, description, content_type_id, team_id, author_id,
+		                            title_pattern, excerpt, content, metadata, tag_ids, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		id, req.Name, req.Description, req.ContentTypeID, req.TeamID, req.AuthorID,
+		req.TitlePattern, req.Excerpt, req.Content, req.Metadata, nonNilUUIDs(req.TagIDs), isActive,
+	)
+	if err != nil {
+		return nil, postTemplateWriteError("create", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *PostTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PostTemplate, error) {
+	t, err := scanPostTemplate(r.db.QueryRow(ctx, postTemplateSelect+` WHERE t.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get post template: %w", err)
+	}
+	return t, nil
+}
+
+func (r *PostTemplateRepository) List(ctx context.Context, filter models.PostTemplateFilter) ([]models.PostTemplate, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.ContentTypeID != nil {
+		cb.addf("t.content_type_id = %s", *filter.ContentTypeID)
+	}
+	if filter.TeamID != nil {
+		cb.addf("t.team_id = %s", *filter.TeamID)
+	}
+	if filter.IsActive != nil {
+		cb.addf("t.is_active = %s", *filter.IsActive)
+	}
+	if filter.Search != "" {
+		cb.addf("(t.name ILIKE %[1]s OR t.description ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM post_templates t %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count post templates: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "t.name ASC", "t.", postTemplateSortColumns...)
+
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		postTemplateSelect, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list post templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []models.PostTemplate
+	for rows.Next() {
+		t, err := scanPostTemplate(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan post template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+
+	return templates, total, nil
+}
+
+func (r *PostTemplateRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdatePostTemplateRequest) (*models.PostTemplate, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	set := func(clause string, value interface{}) {
+		setClauses = append(setClauses, fmt.Sprintf(clause, argNum))
+		args = append(args, value)
+		argNum++
+	}
+	if req.Name != nil {
+		set("name = $%d", *req.Name)
+	}
+	if req.Description != nil {
+		set("description = NULLIF($%d, '')", *req.Description)
+	}
+	if req.TitlePattern != nil {
+		set("title_pattern = NULLIF($%d, '')", *req.TitlePattern)
+	}
+	if req.Excerpt != nil {
+		set("excerpt = NULLIF($%d, '')", *req.Excerpt)
+	}
+	if req.Content != nil {
+		set("content = NULLIF($%d, '')", *req.Content)
+	}
+	if req.Metadata != nil {
+		set("metadata = $%d", *req.Metadata)
+	}
+	if req.TagIDs != nil {
+		set("tag_ids = $%d", nonNilUUIDs(*req.TagIDs))
+	}
+	if req.IsActive != nil {
+		set("is_active = $%d", *req.IsActive)
+	}
+	if req.TeamID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("team_id = NULLIF($%d, $%d::uuid)", argNum, argNum+1))
+		args = append(args, *req.TeamID, uuid.Nil)
+		argNum += 2
+	}
+
+	if len(setClauses) > 0 {
+		args = append(args, id)
+		query := fmt.Sprintf(`UPDATE post_templates SET %s WHERE id = $%d`, strings.Join(setClauses, ", "), argNum)
+
+		result, err := r.db.Exec(ctx, query, args...)
+		if err != nil {
+			return nil, postTemplateWriteError("update", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, ErrNotFound
+		}
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *PostTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM post_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete post template: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// nonNilUUIDs stores an empty array rather than NULL for missing tags
+func nonNilUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
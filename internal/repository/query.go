@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// conditionBuilder collects AND-ed WHERE conditions and their arguments,
+// numbering placeholders in the order arguments are added
+type conditionBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg adds value as an argument and returns its placeholder ($1, $2, ...)
+func (b *conditionBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// add appends a condition. Its placeholders must come from arg.
+func (b *conditionBuilder) add(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// addf adds value as an argument and appends fmt.Sprintf(format, placeholder)
+// as a condition; use %[1]s to refer to the placeholder more than once
+func (b *conditionBuilder) addf(format string, value interface{}) {
+	b.add(fmt.Sprintf(format, b.arg(value)))
+}
+
+// where returns the WHERE clause, or "" without conditions
+func (b *conditionBuilder) where() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// nextArg is the number the next placeholder will get
+func (b *conditionBuilder) nextArg() int {
+	return len(b.args) + 1
+}
+
+// build returns the WHERE clause, its arguments and the next placeholder
+// number, the triple the *FilterConditions helpers return
+func (b *conditionBuilder) build() (string, []interface{}, int) {
+	return b.where(), b.args, b.nextArg()
+}
+
+// sortOrder returns the ORDER BY expression for p when p.SortBy is one of
+// columns, and fallback otherwise. sort_by comes straight from the query
+// string, so only whitelisted column names ever reach the SQL; prefix
+// qualifies them with a table alias (e.g. "cp.").
+func sortOrder(p models.PaginationParams, fallback, prefix string, columns ...string) string {
+	if p.SortBy == "" {
+		return fallback
+	}
+	for _, column := range columns {
+		if p.SortBy == column {
+			dir := "DESC"
+			if p.SortDir == "asc" {
+				dir = "ASC"
+			}
+			return prefix + column + " " + dir
+		}
+	}
+	return fallback
+}
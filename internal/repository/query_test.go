@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+func TestConditionBuilderNumbersPlaceholdersInOrder(t *testing.T) {
+	property := func(values []int64) bool {
+		var b conditionBuilder
+		for _, v := range values {
+			b.addf("x = %s", v)
+		}
+
+		where, args, next := b.build()
+		if next != len(values)+1 || len(args) != len(values) {
+			return false
+		}
+		if len(values) == 0 {
+			return where == ""
+		}
+
+		conditions := strings.Split(strings.TrimPrefix(where, "WHERE "), " AND ")
+		if len(conditions) != len(values) {
+			return false
+		}
+		for i, condition := range conditions {
+			if condition != fmt.Sprintf("x = $%d", i+1) || args[i] != values[i] {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConditionBuilderArgAndAddShareNumbering(t *testing.T) {
+	var b conditionBuilder
+	b.add("a = " + b.arg("x"))
+	b.addf("b = %s", "y")
+	b.add("c BETWEEN " + b.arg(1) + " AND " + b.arg(2))
+
+	where, args, next := b.build()
+	if want := "WHERE a = $1 AND b = $2 AND c BETWEEN $3 AND $4"; where != want {
+		t.Errorf("where = %q, want %q", where, want)
+	}
+	if len(args) != 4 || next != 5 {
+		t.Errorf("got %d args and next %d, want 4 and 5", len(args), next)
+	}
+}
+
+func TestConditionBuilderAddfReusesPlaceholder(t *testing.T) {
+	property := func(before uint8, value string) bool {
+		var b conditionBuilder
+		for i := 0; i < int(before%10); i++ {
+			b.addf("x = %s", i)
+		}
+		b.addf("(title ILIKE %[1]s OR content ILIKE %[1]s)", value)
+
+		n := int(before%10) + 1
+		want := fmt.Sprintf("(title ILIKE $%[1]d OR content ILIKE $%[1]d)", n)
+		return b.conditions[len(b.conditions)-1] == want &&
+			len(b.args) == n && b.args[n-1] == value && b.nextArg() == n+1
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSortOrderWhitelist(t *testing.T) {
+	columns := []string{"created_at", "title", "view_count"}
+	tests := []struct {
+		sortBy, sortDir string
+		want            string
+	}{
+		{"", "asc", "fallback"},
+		{"title", "asc", "cp.title ASC"},
+		{"title", "desc", "cp.title DESC"},
+		{"title", "", "cp.title DESC"},
+		{"title", "ASC; DROP TABLE users", "cp.title DESC"},
+		{"view_count", "asc", "cp.view_count ASC"},
+		{"Title", "asc", "fallback"},
+		{"title; DROP TABLE users", "asc", "fallback"},
+		{"title DESC, (SELECT 1)", "asc", "fallback"},
+		{"password_hash", "asc", "fallback"},
+	}
+	for _, tt := range tests {
+		p := models.PaginationParams{SortBy: tt.sortBy, SortDir: tt.sortDir}
+		if got := sortOrder(p, "fallback", "cp.", columns...); got != tt.want {
+			t.Errorf("sortOrder(%q, %q) = %q, want %q", tt.sortBy, tt.sortDir, got, tt.want)
+		}
+	}
+}
+
+func TestSortOrderNeverEmitsUnlistedInput(t *testing.T) {
+	columns := []string{"created_at", "title"}
+	property := func(sortBy, sortDir string) bool {
+		got := sortOrder(models.PaginationParams{SortBy: sortBy, SortDir: sortDir}, "created_at DESC", "", columns...)
+		switch got {
+		case "created_at DESC":
+			return true
+		case "created_at ASC", "title ASC", "title DESC":
+			return sortBy == "created_at" || sortBy == "title"
+		default:
+			return false
+		}
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzMetaFilterCondition(f *testing.F) {
+	f.Add("color", uint8(0), "red")
+	f.Add("price", uint8(4), "100")
+	f.Add("featured", uint8(0), "true")
+	f.Add("rating", uint8(1), "4.5")
+	f.Add("tags", uint8(2), `["a","b"]`)
+	f.Add("name", uint8(3), `x' OR '1'='1`)
+	f.Add(`a"b`, uint8(4), `") || true || ("`)
+	f.Add("nested", uint8(0), "null")
+
+	ops := []string{models.MetaOpEq, models.MetaOpLt, models.MetaOpLte, models.MetaOpGt, models.MetaOpGte}
+	f.Fuzz(func(t *testing.T, field string, opIndex uint8, value string) {
+		op := ops[int(opIndex)%len(ops)]
+		format, arg := metaFilterCondition("cp.metadata", models.MetaFilter{Field: field, Op: op, Value: value})
+
+		// Values only ever travel as the argument; fields reach the SQL only
+		// in the indexable range conditions, and only when valid
+		comparison := metaComparisons[op]
+		numberFormat := fmt.Sprintf("metadata_number(cp.metadata, '%s') %s %%s::numeric", field, comparison)
+		stringFormat := fmt.Sprintf(`metadata_string(cp.metadata, '%s') COLLATE "C" %s %%s`, field, comparison)
+		want := "COALESCE(cp.metadata @@ %s::jsonpath, false)"
+		switch {
+		case op == models.MetaOpEq:
+			want = "cp.metadata @> %s::jsonb"
+		case format == numberFormat || format == stringFormat:
+			if !models.ValidMetaField(field) {
+				t.Fatalf("invalid field %q reached the SQL: %q", field, format)
+			}
+			want = format
+		}
+		if format != want {
+			t.Fatalf("format = %q, want %q", format, want)
+		}
+
+		var b conditionBuilder
+		b.addf(format, arg)
+		if where := b.where(); strings.Contains(where, "%!") || strings.Count(where, "$1") != 1 {
+			t.Fatalf("malformed condition %q", where)
+		}
+
+		s, ok := arg.(string)
+		if !ok {
+			t.Fatalf("argument is %T, want string", arg)
+		}
+		if op == models.MetaOpEq {
+			var doc map[string]interface{}
+			if err := json.Unmarshal([]byte(s), &doc); err != nil {
+				t.Fatalf("containment document %q is not JSON: %v", s, err)
+			}
+			if len(doc) != 1 {
+				t.Fatalf("containment document %q has %d keys, want 1", s, len(doc))
+			}
+			if _, ok := doc[field]; !ok && utf8.ValidString(field) {
+				t.Fatalf("containment document %q lacks field %q", s, field)
+			}
+			return
+		}
+
+		switch format {
+		case numberFormat:
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				t.Fatalf("number argument %q doesn't parse: %v", s, err)
+			}
+			return
+		case stringFormat:
+			return
+		}
+
+		prefix := fmt.Sprintf("$.%q %s ", field, metaComparisons[op])
+		literal, found := strings.CutPrefix(s, prefix)
+		if !found {
+			t.Fatalf("jsonpath %q doesn't start with %q", s, prefix)
+		}
+		if !json.Valid([]byte(literal)) {
+			t.Fatalf("jsonpath literal %q is not JSON", literal)
+		}
+	})
+}
+
+func BenchmarkPostFilterConditionsSearch(b *testing.B) {
+	status := models.PostStatusPublished
+	filter := models.PostFilter{
+		Search: "golang tips",
+		Status: &status,
+		Meta: []models.MetaFilter{
+			{Field: "color", Op: models.MetaOpEq, Value: "red"},
+			{Field: "price", Op: models.MetaOpLte, Value: "100"},
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		postFilterConditions(filter)
+	}
+}
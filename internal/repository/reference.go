@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// referenceTables maps reference kinds to the tables holding the records
+var referenceTables = map[string]string{
+	"media": "media",
+	"post":  "content_posts",
+	"tag":   "tags",
+}
+
+// ReferenceRepository reads post references and stores the dangling ones
+// found by the reference checker
+type ReferenceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReferenceRepository(db *pgxpool.Pool) *ReferenceRepository {
+	return &ReferenceRepository{db: db}
+}
+
+// EachPostReferences streams the metadata and body of every post, in any
+// environment or status, that has metadata or references in its content
+func (r *ReferenceRepository) EachPostReferences(ctx context.Context, fn func(*models.PostReferences) error) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, metadata, COALESCE(content, '')
+		FROM content_posts
+		WHERE (metadata IS NOT NULL AND metadata <> '{}'::jsonb) OR content ILIKE '%data-%-id%'`)
+	if err != nil {
+		return fmt.Errorf("failed to query post references: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.PostReferences
+		if err := rows.Scan(&p.ID, &p.Metadata, &p.Content); err != nil {
+			return fmt.Errorf("failed to scan post references: %w", err)
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Missing returns the ids of the given kind that match no record
+func (r *ReferenceRepository) Missing(ctx context.Context, kind string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	table, ok := referenceTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown reference kind %q", kind)
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT u.id FROM unnest($1::uuid[]) AS u(id)
+		WHERE NOT EXISTS (SELECT 1 FROM %s t WHERE t.id = u.id)`, table), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s references: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var missing []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan missing reference: %w", err)
+		}
+		missing = append(missing, id)
+	}
+	return missing, rows.Err()
+}
+
+// ReplaceMetadata sets a post's metadata to cleaned, unless the post was
+// edited since old was read. It reports whether the post was updated.
+func (r *ReferenceRepository) ReplaceMetadata(ctx context.Context, postID uuid.UUID, old, cleaned json.RawMessage) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE content_posts SET metadata = $3, updated_at = NOW()
+		WHERE id = $1 AND metadata = $2::jsonb`,
+		postID, old, cleaned)
+	if err != nil {
+		return false, fmt.Errorf("failed to clean post metadata: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Replace swaps the stored references for refs in one transaction, so the
+// report never shows a half-finished scan
+func (r *ReferenceRepository) Replace(ctx context.Context, refs []models.DanglingReference) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM post_dangling_references`); err != nil {
+		return fmt.Errorf("failed to clear dangling references: %w", err)
+	}
+
+	rows := make([][]interface{}, len(refs))
+	for i, ref := range refs {
+		rows[i] = []interface{}{ref.Post.ID, ref.Source, ref.Path, ref.Kind, ref.ReferencedID, ref.Cleaned, ref.DetectedAt}
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"post_dangling_references"},
+		[]string{"post_id", "source", "path", "kind", "referenced_id", "cleaned", "detected_at"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("failed to store dangling references: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// List returns stored dangling references matching filter, grouped by post
+func (r *ReferenceRepository) List(ctx context.Context, filter models.DanglingReferenceFilter) ([]models.DanglingReference, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Kind != "" {
+		cb.addf("d.kind = %s", filter.Kind)
+	}
+	if filter.Source != "" {
+		cb.addf("d.source = %s", filter.Source)
+	}
+	if filter.Cleaned != nil {
+		cb.addf("d.cleaned = %s", *filter.Cleaned)
+	}
+	if filter.ContentTypeID != nil {
+		cb.addf("p.content_type_id = %s", *filter.ContentTypeID)
+	}
+	whereClause, args, argNum := cb.build()
+
+	const from = `
+		FROM post_dangling_references d
+		JOIN content_posts p ON p.id = d.post_id`
+
+	var total int64
+	if err := r.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) %s %s", from, whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count dangling references: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT p.id, p.content_type_id, p.title, p.slug, p.status,
+		       d.source, d.path, d.kind, d.referenced_id, d.cleaned, d.detected_at
+		%s
+		%s
+		ORDER BY p.title, p.id, d.source, d.path
+		LIMIT $%d OFFSET $%d`,
+		from, whereClause, argNum, argNum+1)
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list dangling references: %w", err)
+	}
+	defer rows.Close()
+
+	refs := []models.DanglingReference{}
+	for rows.Next() {
+		var ref models.DanglingReference
+		if err := rows.Scan(
+			&ref.Post.ID, &ref.Post.ContentTypeID, &ref.Post.Title, &ref.Post.Slug, &ref.Post.Status,
+			&ref.Source, &ref.Path, &ref.Kind, &ref.ReferencedID, &ref.Cleaned, &ref.DetectedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan dangling reference: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, total, nil
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// ReviewRepository keeps the content review periods of content types and
+// finds the published posts due for review
+type ReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// GetPolicy returns the review policy of a content type
+func (r *ReviewRepository) GetPolicy(ctx context.Context, contentTypeID uuid.UUID) (*models.ContentReviewPolicy, error) {
+	p := &models.ContentReviewPolicy{}
+	err := r.db.QueryRow(ctx, `
+		SELECT content_type_id, review_after_days, created_at, updated_at
+		FROM content_review_policies
+		WHERE content_type_id = $1`, contentTypeID,
+	).Scan(&p.ContentTypeID, &p.ReviewAfterDays, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get review policy: %w", err)
+	}
+	return p, nil
+}
+
+// SetPolicy creates or replaces the review policy of a content type
+func (r *ReviewRepository) SetPolicy(ctx context.Context, contentTypeID uuid.UUID, days int) (*models.ContentReviewPolicy, error) {
+	p := &models.ContentReviewPolicy{}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO content_review_policies (content_type_id, review_after_days)
+		VALUES ($1, $2)
+		ON CONFLICT (content_type_id) DO UPDATE SET review_after_days = EXCLUDED.review_after_days
+		RETURNING content_type_id, review_after_days, created_at, updated_at`,
+		contentTypeID, days,
+	).Scan(&p.ContentTypeID, &p.ReviewAfterDays, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to set review policy: %w", err)
+	}
+	return p, nil
+}
+
+// DeletePolicy removes the review policy of a content type
+func (r *ReviewRepository) DeletePolicy(ctx context.Context, contentTypeID uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM content_review_policies WHERE content_type_id = $1`, contentTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete review policy: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// staleFrom joins published live posts with their review period and review
+const staleFrom = `
+	FROM content_posts p
+	LEFT JOIN content_review_policies rp ON rp.content_type_id = p.content_type_id
+	LEFT JOIN post_reviews r ON r.post_id = p.id`
+
+// staleConditions selects the published live posts whose review period,
+// days unless overridden, has passed since they were last edited or
+// acknowledged. reviewAfter is the SQL expression of each post's period.
+func staleConditions(cb *conditionBuilder, days *int, defaultDays int) (reviewAfter string) {
+	if days != nil {
+		reviewAfter = cb.arg(*days) + "::INT"
+	} else {
+		reviewAfter = "COALESCE(rp.review_after_days, " + cb.arg(defaultDays) + "::INT)"
+	}
+	cb.add("p.status = 2 AND p.environment = 'live'")
+	cb.add(fmt.Sprintf("GREATEST(p.updated_at, r.acknowledged_at) < CURRENT_TIMESTAMP - make_interval(days => %s)", reviewAfter))
+	return reviewAfter
+}
+
+// ListStale returns the posts due for review, longest unreviewed first
+func (r *ReviewRepository) ListStale(ctx context.Context, filter models.StaleContentFilter) ([]models.StalePost, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	reviewAfter := staleConditions(&cb, filter.Days, filter.DefaultDays)
+	if filter.ContentTypeID != nil {
+		cb.addf("p.content_type_id = %s", *filter.ContentTypeID)
+	}
+	if filter.TeamID != nil {
+		cb.addf("p.team_id = %s", *filter.TeamID)
+	}
+	if filter.Reminded != nil {
+		reminded := "r.reminded_at >= GREATEST(p.updated_at, r.acknowledged_at)"
+		if *filter.Reminded {
+			cb.add(reminded)
+		} else {
+			cb.add("(r.reminded_at IS NULL OR NOT " + reminded + ")")
+		}
+	}
+	whereClause, args, argNum := cb.build()
+
+	var total int64
+	if err := r.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) %s %s", staleFrom, whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count stale posts: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT p.id, p.content_type_id, p.title, p.slug, p.status, p.team_id,
+		       p.updated_at, r.acknowledged_at, GREATEST(p.updated_at, r.acknowledged_at), %s,
+		       EXTRACT(DAY FROM CURRENT_TIMESTAMP - GREATEST(p.updated_at, r.acknowledged_at))::INT,
+		       r.reminded_at
+		%s
+		%s
+		ORDER BY GREATEST(p.updated_at, r.acknowledged_at), p.id
+		LIMIT $%d OFFSET $%d`,
+		reviewAfter, staleFrom, whereClause, argNum, argNum+1)
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list stale posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.StalePost{}
+	for rows.Next() {
+		var s models.StalePost
+		if err := rows.Scan(
+			&s.Post.ID, &s.Post.ContentTypeID, &s.Post.Title, &s.Post.Slug, &s.Post.Status, &s.TeamID,
+			&s.UpdatedAt, &s.AcknowledgedAt, &s.ReviewedAt, &s.ReviewAfterDays, &s.StaleDays, &s.RemindedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan stale post: %w", err)
+		}
+		posts = append(posts, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list stale posts: %w", err)
+	}
+	return posts, total, nil
+}
+
+// MarkReminders records a review reminder for up to limit posts due for
+// review that weren't reminded of in their current review period, longest
+// unreviewed first, and returns them
+func (r *ReviewRepository) MarkReminders(ctx context.Context, defaultDays, limit int) ([]models.ReportPost, error) {
+	var cb conditionBuilder
+	staleConditions(&cb, nil, defaultDays)
+	cb.add("(r.reminded_at IS NULL OR r.reminded_at < GREATEST(p.updated_at, r.acknowledged_at))")
+	whereClause, args, argNum := cb.build()
+
+	query := fmt.Sprintf(`
+		WITH due AS (
+			SELECT p.id
+			%s
+			%s
+			ORDER BY GREATEST(p.updated_at, r.acknowledged_at), p.id
+			LIMIT $%d
+		), marked AS (
+			INSERT INTO post_reviews (post_id, reminded_at)
+			SELECT id, CURRENT_TIMESTAMP FROM due
+			ON CONFLICT (post_id) DO UPDATE SET reminded_at = EXCLUDED.reminded_at
+			RETURNING post_id
+		)
+		SELECT p.id, p.content_type_id, p.title, p.slug, p.status
+		FROM marked
+		JOIN content_posts p ON p.id = marked.post_id
+		ORDER BY p.title`,
+		staleFrom, whereClause, argNum)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record review reminders: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.ReportPost{}
+	for rows.Next() {
+		var p models.ReportPost
+		if err := rows.Scan(&p.ID, &p.ContentTypeID, &p.Title, &p.Slug, &p.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan reminded post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// Acknowledge records that userID confirmed a post is still fresh, which
+// restarts its review period
+func (r *ReviewRepository) Acknowledge(ctx context.Context, postID uuid.UUID, userID *uuid.UUID, note *string) (*models.PostReview, error) {
+	review := &models.PostReview{}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO post_reviews (post_id, acknowledged_at, acknowledged_by, note)
+		VALUES ($1, CURRENT_TIMESTAMP, $2, NULLIF($3, ''))
+		ON CONFLICT (post_id) DO UPDATE
+		SET acknowledged_at = EXCLUDED.acknowledged_at, acknowledged_by = EXCLUDED.acknowledged_by, note = EXCLUDED.note
+		RETURNING post_id, acknowledged_at, acknowledged_by, note, reminded_at`,
+		postID, userID, note,
+	).Scan(&review.PostID, &review.AcknowledgedAt, &review.AcknowledgedBy, &review.Note, &review.RemindedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to acknowledge review: %w", err)
+	}
+	return review, nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type SessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// ErrTokenReused is returned when a refresh token that was already
+// exchanged is presented again; its session has been ended
+var ErrTokenReused = errors.New("refresh token reused")
+
+const sessionColumns = `id, user_id, token, user_agent, ip_address, expires_at, refreshed_at, created_at`
+
+func scanSession(row pgx.Row) (*models.Session, error) {
+	s := &models.Session{}
+	err := row.Scan(&s.ID, &s.UserID, &s.Token, &s.UserAgent, &s.IPAddress, &s.ExpiresAt, &s.RefreshedAt, &s.CreatedAt)
+	return s, err
+}
+
+// HashSessionToken returns the stored form of a refresh token
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create stores a session. Token must already be hashed with
+// HashSessionToken; the token itself is never stored.
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO sessions (id, user_id, token, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING refreshed_at, created_at`,
+		session.ID, session.UserID, session.Token, session.UserAgent, session.IPAddress, session.ExpiresAt,
+	).Scan(&session.RefreshedAt, &session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetUser returns the user of an unexpired session
+func (r *SessionRepository) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user := &models.User{}
+	err := r.db.QueryRow(ctx, `
+		SELECT u.id, u.email, u.password_hash, u.full_name, u.role, u.is_active, u.last_login, u.created_at, u.updated_at
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.expires_at > CURRENT_TIMESTAMP`,
+		id,
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.IsActive,
+		&user.LastLogin, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return user, nil
+}
+
+// Rotate replaces the refresh token hashing to tokenHash with newHash,
+// extending its unexpired session to expiresAt and recording the client.
+// It returns ErrNotFound for unknown or expired tokens, and ends the
+// session and returns ErrTokenReused for a token already rotated out.
+func (r *SessionRepository) Rotate(ctx context.Context, tokenHash, newHash string, expiresAt time.Time, userAgent, ipAddress *string) (*models.Session, error) {
+	session, err := scanSession(r.db.QueryRow(ctx, `
+		UPDATE sessions
+		SET previous_token = token, token = $2, expires_at = $3,
+		    user_agent = COALESCE($4, user_agent), ip_address = COALESCE($5, ip_address),
+		    refreshed_at = CURRENT_TIMESTAMP
+		WHERE token = $1 AND expires_at > CURRENT_TIMESTAMP
+		RETURNING `+sessionColumns,
+		tokenHash, newHash, expiresAt, userAgent, ipAddress))
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE previous_token = $1`, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end session: %w", err)
+	}
+	if result.RowsAffected() > 0 {
+		return nil, ErrTokenReused
+	}
+	return nil, ErrNotFound
+}
+
+// ListForUser returns a user's unexpired sessions, most recently refreshed
+// first
+func (r *SessionRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY refreshed_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, rows.Err()
+}
+
+// Delete ends a session
+func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteUserSession ends one of a user's sessions. Sessions of other users
+// are reported as not found.
+func (r *SessionRepository) DeleteUserSession(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteOthers ends all of a user's sessions except keep, returning how
+// many were ended
+func (r *SessionRepository) DeleteOthers(ctx context.Context, userID, keep uuid.UUID) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE user_id = $1 AND id <> $2`, userID, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// DeleteForUser ends all of a user's sessions
+func (r *SessionRepository) DeleteForUser(ctx context.Context, userID uuid.UUID) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes a user's expired sessions
+func (r *SessionRepository) DeleteExpired(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM sessions WHERE user_id = $1 AND expires_at <= CURRENT_TIMESTAMP`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
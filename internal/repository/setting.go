@@ -21,6 +21,12 @@ func NewSettingRepository(db *pgxpool.Pool) *SettingRepository {
 	return &SettingRepository{db: db}
 }
 
+// settingSortColumns are the columns list results can be sorted by
+var settingSortColumns = []string{"key", "updated_at"}
+
+// likeEscaper escapes LIKE wildcards so a prefix matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *SettingRepository) Create(ctx context.Context, req *models.CreateSettingRequest) (*models.Setting, error) {
 	setting := &models.Setting{
 		ID:          uuid.New(),
@@ -80,20 +86,12 @@ func (r *SettingRepository) GetByKey(ctx context.Context, key string) (*models.S
 func (r *SettingRepository) List(ctx context.Context, filter models.SettingFilter) ([]models.Setting, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
-
+	var cb conditionBuilder
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(key ILIKE $%d OR description ILIKE $%d)", argNum, argNum))
-		args = append(args, "%"+filter.Search+"%")
-		argNum++
+		cb.addf("(key ILIKE %[1]s OR description ILIKE %[1]s)", "%"+filter.Search+"%")
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args, argNum := cb.build()
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM settings %s", whereClause)
@@ -103,10 +101,7 @@ func (r *SettingRepository) List(ctx context.Context, filter models.SettingFilte
 	}
 
 	// Get data
-	orderBy := "key ASC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("%s %s", filter.SortBy, filter.SortDir)
-	}
+	orderBy := sortOrder(filter.PaginationParams, "key ASC", "", settingSortColumns...)
 
 	query := fmt.Sprintf(`
 		SELECT id, key, value, description, updated_at
@@ -212,6 +207,67 @@ func (r *SettingRepository) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// ListByPrefix returns the settings whose key starts with any of prefixes,
+// ordered by key
+func (r *SettingRepository) ListByPrefix(ctx context.Context, prefixes []string) ([]models.Setting, error) {
+	patterns := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		patterns[i] = likeEscaper.Replace(p) + "%"
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, key, value, description, updated_at
+		FROM settings
+		WHERE key LIKE ANY($1)
+		ORDER BY key`, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.Setting
+	for rows.Next() {
+		var s models.Setting
+		if err := rows.Scan(&s.ID, &s.Key, &s.Value, &s.Description, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings = append(settings, s)
+	}
+
+	return settings, rows.Err()
+}
+
+// Import writes upserts and removes the deletes keys in one transaction, so
+// a failed import leaves the settings as they were
+func (r *SettingRepository) Import(ctx context.Context, upserts []models.SettingEntry, deletes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, s := range upserts {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO settings (id, key, value, description)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, description = EXCLUDED.description`,
+			uuid.New(), s.Key, s.Value, s.Description)
+		if err != nil {
+			return fmt.Errorf("failed to import setting %s: %w", s.Key, err)
+		}
+	}
+	if len(deletes) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM settings WHERE key = ANY($1)`, deletes); err != nil {
+			return fmt.Errorf("failed to delete settings: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // GetMultiple returns multiple settings by keys
 func (r *SettingRepository) GetMultiple(ctx context.Context, keys []string) (map[string]string, error) {
 	query := `SELECT key, value FROM settings WHERE key = ANY($1)`
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type SocialShareRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSocialShareRepository(db *pgxpool.Pool) *SocialShareRepository {
+	return &SocialShareRepository{db: db}
+}
+
+const socialShareColumns = `id, post_id, post_title, account, driver, status, message, link, image_url,
+	external_id, external_url, error, created_at, shared_at`
+
+func scanSocialShare(row pgx.Row) (*models.SocialShare, error) {
+	s := &models.SocialShare{}
+	err := row.Scan(&s.ID, &s.PostID, &s.PostTitle, &s.Account, &s.Driver, &s.Status, &s.Message, &s.Link, &s.ImageURL,
+		&s.ExternalID, &s.ExternalURL, &s.Error, &s.CreatedAt, &s.SharedAt)
+	return s, err
+}
+
+// ListDue returns up to limit live posts published between since and
+// until that haven't opted out and haven't been shared to every one of
+// accounts, oldest publication first
+func (r *SocialShareRepository) ListDue(ctx context.Context, accounts []string, since, until time.Time, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT cp.id
+		FROM content_posts cp
+		WHERE cp.environment = $1 AND cp.status = $2
+		  AND cp.published_at > $3 AND cp.published_at <= $4
+		  AND NOT EXISTS (SELECT 1 FROM social_share_optouts o WHERE o.post_id = cp.id)
+		  AND EXISTS (
+		      SELECT 1 FROM unnest($5::text[]) AS a(name)
+		      WHERE NOT EXISTS (SELECT 1 FROM social_shares s WHERE s.post_id = cp.id AND s.account = a.name)
+		  )
+		ORDER BY cp.published_at
+		LIMIT $6`,
+		models.EnvironmentLive, models.PostStatusPublished, since, until, accounts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts due for sharing: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan post ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Claim records a pending share. It returns ErrDuplicate when the post was
+// already shared, or is being shared, to the account.
+func (r *SocialShareRepository) Claim(ctx context.Context, share *models.SocialShare) error {
+	share.ID = uuid.New()
+	share.Status = models.SocialSharePending
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO social_shares (id, post_id, post_title, account, driver, status, message, link, image_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at`,
+		share.ID, share.PostID, share.PostTitle, share.Account, share.Driver, share.Status,
+		share.Message, share.Link, share.ImageURL,
+	).Scan(&share.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("failed to record social share: %w", err)
+	}
+	return nil
+}
+
+// Finish records the outcome of a claimed share: sent with the network's
+// post ID and URL, or failed with shareErr
+func (r *SocialShareRepository) Finish(ctx context.Context, id uuid.UUID, externalID, externalURL string, shareErr error) error {
+	var err error
+	if shareErr != nil {
+		_, err = r.db.Exec(ctx, `UPDATE social_shares SET status = $2, error = $3 WHERE id = $1`,
+			id, models.SocialShareFailed, shareErr.Error())
+	} else {
+		_, err = r.db.Exec(ctx, `
+			UPDATE social_shares
+			SET status = $2, external_id = NULLIF($3, ''), external_url = NULLIF($4, ''), shared_at = CURRENT_TIMESTAMP
+			WHERE id = $1`,
+			id, models.SocialShareSent, externalID, externalURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update social share: %w", err)
+	}
+	return nil
+}
+
+// List returns the sharing history, newest first
+func (r *SocialShareRepository) List(ctx context.Context, filter models.SocialShareFilter) ([]models.SocialShare, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.PostID != nil {
+		cb.addf("post_id = %s", *filter.PostID)
+	}
+	if filter.Account != "" {
+		cb.addf("account = %s", filter.Account)
+	}
+	if filter.Status != "" {
+		cb.addf("status = %s", filter.Status)
+	}
+	whereClause, args, argNum := cb.build()
+
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM social_shares "+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count social shares: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM social_shares %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		socialShareColumns, whereClause, argNum, argNum+1)
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list social shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []models.SocialShare{}
+	for rows.Next() {
+		s, err := scanSocialShare(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan social share: %w", err)
+		}
+		shares = append(shares, *s)
+	}
+
+	return shares, total, nil
+}
+
+// IsOptedOut reports whether a post is excluded from sharing
+func (r *SocialShareRepository) IsOptedOut(ctx context.Context, postID uuid.UUID) (bool, error) {
+	var optedOut bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM social_share_optouts WHERE post_id = $1)`, postID).Scan(&optedOut)
+	if err != nil {
+		return false, fmt.Errorf("failed to check social sharing opt-out: %w", err)
+	}
+	return optedOut, nil
+}
+
+// SetOptOut excludes a post from sharing, or includes it again
+func (r *SocialShareRepository) SetOptOut(ctx context.Context, postID uuid.UUID, optOut bool) error {
+	var err error
+	if optOut {
+		_, err = r.db.Exec(ctx, `INSERT INTO social_share_optouts (post_id) VALUES ($1) ON CONFLICT DO NOTHING`, postID)
+	} else {
+		_, err = r.db.Exec(ctx, `DELETE FROM social_share_optouts WHERE post_id = $1`, postID)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to update social sharing opt-out: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type SubscriberRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSubscriberRepository(db *pgxpool.Pool) *SubscriberRepository {
+	return &SubscriberRepository{db: db}
+}
+
+// subscriberSortColumns are the columns list results can be sorted by
+var subscriberSortColumns = []string{"created_at", "email", "name", "status", "unsubscribed_at"}
+
+const subscriberColumns = `id, email, name, locale, status, unsubscribe_token, created_at, unsubscribed_at`
+
+func scanSubscriber(row pgx.Row) (*models.Subscriber, error) {
+	s := &models.Subscriber{}
+	err := row.Scan(&s.ID, &s.Email, &s.Name, &s.Locale, &s.Status, &s.UnsubscribeToken, &s.CreatedAt, &s.UnsubscribedAt)
+	return s, err
+}
+
+// Create adds a subscriber. Adding an address that unsubscribed earlier
+// subscribes it again.
+func (r *SubscriberRepository) Create(ctx context.Context, req *models.CreateSubscriberRequest) (*models.Subscriber, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate unsubscribe token: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO subscribers (id, email, name, locale, status, unsubscribe_token)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (email) DO UPDATE
+		SET status = EXCLUDED.status, unsubscribed_at = NULL,
+		    name = COALESCE(EXCLUDED.name, subscribers.name), locale = EXCLUDED.locale
+		WHERE subscribers.status = $7
+		RETURNING %s`, subscriberColumns)
+
+	sub, err := scanSubscriber(r.db.QueryRow(ctx, query,
+		uuid.New(), req.Email, req.Name, req.Locale, models.SubscriberStatusSubscribed,
+		hex.EncodeToString(token), models.SubscriberStatusUnsubscribed,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The address is already subscribed
+			return nil, ErrDuplicate
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create subscriber: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *SubscriberRepository) List(ctx context.Context, filter models.SubscriberFilter) ([]models.Subscriber, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Status != nil {
+		cb.addf("status = %s", *filter.Status)
+	}
+	if filter.Search != "" {
+		cb.addf("(email ILIKE %[1]s OR name ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM subscribers %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscribers: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "created_at DESC", "", subscriberSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM subscribers
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		subscriberColumns, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []models.Subscriber
+	for rows.Next() {
+		sub, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		subscribers = append(subscribers, *sub)
+	}
+
+	return subscribers, total, nil
+}
+
+// ListSubscribedAfter returns up to limit subscribed recipients ordered by
+// ID, starting after the given ID (nil starts from the beginning). Campaigns
+// page through the list with it.
+func (r *SubscriberRepository) ListSubscribedAfter(ctx context.Context, after *uuid.UUID, limit int) ([]models.Subscriber, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM subscribers
+		WHERE status = $1 AND ($2::uuid IS NULL OR id > $2)
+		ORDER BY id
+		LIMIT $3`, subscriberColumns)
+
+	rows, err := r.db.Query(ctx, query, models.SubscriberStatusSubscribed, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []models.Subscriber
+	for rows.Next() {
+		sub, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		subscribers = append(subscribers, *sub)
+	}
+
+	return subscribers, nil
+}
+
+// Unsubscribe marks the subscriber owning token as unsubscribed
+func (r *SubscriberRepository) Unsubscribe(ctx context.Context, token string) (*models.Subscriber, error) {
+	query := fmt.Sprintf(`
+		UPDATE subscribers
+		SET status = $1, unsubscribed_at = COALESCE(unsubscribed_at, NOW())
+		WHERE unsubscribe_token = $2
+		RETURNING %s`, subscriberColumns)
+
+	sub, err := scanSubscriber(r.db.QueryRow(ctx, query, models.SubscriberStatusUnsubscribed, token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *SubscriberRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM subscribers WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscriber: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// SuggestionRepository finds link and mention candidates for the editor
+type SuggestionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSuggestionRepository(db *pgxpool.Pool) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// matchScore rates how well column matches the query in $1: 1 for an exact
+// match, 0.75 for a prefix, 0.5 for a word prefix and 0.25 for anything else
+func matchScore(column string) string {
+	return fmt.Sprintf(`CASE
+		WHEN lower(%[1]s) = lower($1::text) THEN 1.0
+		WHEN %[1]s ILIKE $1 || '%%' THEN 0.75
+		WHEN %[1]s ILIKE '%% ' || $1 || '%%' THEN 0.5
+		ELSE 0.25 END`, column)
+}
+
+// suggestionScore weighs the match against recency, which halves after 30
+// days of inactivity
+func suggestionScore(match, activeAt string) string {
+	return fmt.Sprintf(`(0.7 * %s + 0.3 / (1 + EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - %s)) / 2592000.0))::float8`, match, activeAt)
+}
+
+// Suggest returns up to filter.Limit candidates of filter.Types ranked by
+// score. Posts come from the live environment and exclude archived ones;
+// authors are active users.
+func (r *SuggestionRepository) Suggest(ctx context.Context, filter models.SuggestionFilter) ([]models.Suggestion, error) {
+	queries := map[string]string{
+		models.SuggestionPost: fmt.Sprintf(`
+			SELECT cp.id, cp.title, ct.name, '/posts/' || cp.slug, cp.status, cp.updated_at, %s AS score
+			FROM content_posts cp
+			JOIN content_types ct ON ct.id = cp.content_type_id
+			WHERE cp.environment = 'live' AND cp.status <> %d AND (cp.title ILIKE $2 OR cp.slug ILIKE $2)
+			ORDER BY score DESC, cp.updated_at DESC
+			LIMIT $3`,
+			suggestionScore("GREATEST("+matchScore("cp.title")+", "+matchScore("cp.slug")+")", "cp.updated_at"),
+			models.PostStatusArchived),
+		models.SuggestionTag: fmt.Sprintf(`
+			SELECT t.id, t.name, t.slug, t.slug, NULL::smallint, t.active_at, %s AS score
+			FROM (
+				SELECT tg.*, COALESCE((SELECT MAX(pt.created_at) FROM post_tags pt WHERE pt.tag_id = tg.id), tg.created_at) AS active_at
+				FROM tags tg
+				WHERE tg.name ILIKE $2 OR tg.slug ILIKE $2
+			) t
+			ORDER BY score DESC, t.active_at DESC
+			LIMIT $3`,
+			suggestionScore("GREATEST("+matchScore("t.name")+", "+matchScore("t.slug")+")", "t.active_at")),
+		models.SuggestionAuthor: fmt.Sprintf(`
+			SELECT u.id, u.full_name, u.email, '@' || u.email, NULL::smallint, u.active_at, %s AS score
+			FROM (
+				SELECT us.*, COALESCE(GREATEST(us.last_login, (SELECT MAX(cp.updated_at) FROM content_posts cp WHERE cp.author_id = us.id)), us.created_at) AS active_at
+				FROM users us
+				WHERE us.is_active AND (us.full_name ILIKE $2 OR us.email ILIKE $2)
+			) u
+			ORDER BY score DESC, u.active_at DESC
+			LIMIT $3`,
+			suggestionScore("GREATEST("+matchScore("u.full_name")+", "+matchScore("u.email")+")", "u.active_at")),
+	}
+
+	suggestions := []models.Suggestion{}
+	for _, kind := range models.SuggestionTypes {
+		if !slices.Contains(filter.Types, kind) {
+			continue
+		}
+		rows, err := r.db.Query(ctx, queries[kind], filter.Query, "%"+filter.Query+"%", filter.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest %ss: %w", kind, err)
+		}
+		for rows.Next() {
+			s := models.Suggestion{Type: kind}
+			if err := rows.Scan(&s.ID, &s.Label, &s.Detail, &s.Insert, &s.Status, &s.ActiveAt, &s.Score); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s suggestion: %w", kind, err)
+			}
+			suggestions = append(suggestions, s)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to suggest %ss: %w", kind, err)
+		}
+	}
+
+	// Stable, so ties keep the order of SuggestionTypes
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > filter.Limit {
+		suggestions = suggestions[:filter.Limit]
+	}
+	return suggestions, nil
+}
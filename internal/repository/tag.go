@@ -21,6 +21,9 @@ func NewTagRepository(db *pgxpool.Pool) *TagRepository {
 	return &TagRepository{db: db}
 }
 
+// tagSortColumns are the columns list results can be sorted by
+var tagSortColumns = []string{"created_at", "name", "slug"}
+
 func (r *TagRepository) Create(ctx context.Context, req *models.CreateTagRequest) (*models.Tag, error) {
 	tag := &models.Tag{
 		ID:   uuid.New(),
@@ -74,20 +77,7 @@ func (r *TagRepository) GetBySlug(ctx context.Context, slug string) (*models.Tag
 func (r *TagRepository) List(ctx context.Context, filter models.TagFilter) ([]models.Tag, int64, error) {
 	filter.PaginationParams.Normalize()
 
-	var conditions []string
-	var args []interface{}
-	argNum := 1
-
-	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR slug ILIKE $%d)", argNum, argNum))
-		args = append(args, "%"+filter.Search+"%")
-		argNum++
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args, argNum := tagFilterConditions(filter)
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tags %s", whereClause)
@@ -97,10 +87,7 @@ func (r *TagRepository) List(ctx context.Context, filter models.TagFilter) ([]mo
 	}
 
 	// Get data
-	orderBy := "name ASC"
-	if filter.SortBy != "" {
-		orderBy = fmt.Sprintf("%s %s", filter.SortBy, filter.SortDir)
-	}
+	orderBy := sortOrder(filter.PaginationParams, "name ASC", "", tagSortColumns...)
 
 	query := fmt.Sprintf(`
 		SELECT id, name, slug, created_at
@@ -129,6 +116,43 @@ func (r *TagRepository) List(ctx context.Context, filter models.TagFilter) ([]mo
 	return tags, total, nil
 }
 
+// ExportEach streams every tag matching the filter to fn along with the number
+// of posts using it, ignoring pagination
+func (r *TagRepository) ExportEach(ctx context.Context, filter models.TagFilter, fn func(*models.Tag, int64) error) error {
+	filter.PaginationParams.Normalize()
+
+	whereClause, args, _ := tagFilterConditions(filter)
+
+	orderBy := sortOrder(filter.PaginationParams, "name ASC", "", tagSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, name, slug, created_at,
+		       (SELECT COUNT(*) FROM post_tags pt WHERE pt.tag_id = tags.id) AS post_count
+		FROM tags
+		%s
+		ORDER BY %s`,
+		whereClause, orderBy)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag models.Tag
+		var postCount int64
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt, &postCount); err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
+		}
+		if err := fn(&tag, postCount); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *TagRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateTagRequest) (*models.Tag, error) {
 	var setClauses []string
 	var args []interface{}
@@ -193,3 +217,13 @@ func (r *TagRepository) GetPostCountByTag(ctx context.Context, tagID uuid.UUID)
 	}
 	return count, nil
 }
+
+// tagFilterConditions builds the WHERE clause shared by List and ExportEach
+func tagFilterConditions(filter models.TagFilter) (string, []interface{}, int) {
+	var cb conditionBuilder
+	if filter.Search != "" {
+		cb.addf("(name ILIKE %[1]s OR slug ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+
+	return cb.build()
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type TeamRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTeamRepository(db *pgxpool.Pool) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// teamSortColumns are the columns list results can be sorted by
+var teamSortColumns = []string{"created_at", "name", "slug"}
+
+const teamSelect = `
+	SELECT t.id, t.name, t.slug, t.description,
+	       (SELECT COUNT(*) FROM team_members m WHERE m.team_id = t.id),
+	       t.created_at, t.updated_at
+	FROM teams t`
+
+func scanTeam(row pgx.Row) (*models.Team, error) {
+	team := &models.Team{}
+	err := row.Scan(&team.ID, &team.Name, &team.Slug, &team.Description,
+		&team.MemberCount, &team.CreatedAt, &team.UpdatedAt)
+	return team, err
+}
+
+func (r *TeamRepository) Create(ctx context.Context, req *models.CreateTeamRequest) (*models.Team, error) {
+	team := &models.Team{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+	}
+
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO teams (id, name, slug, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at`,
+		team.ID, team.Name, team.Slug, team.Description,
+	).Scan(&team.CreatedAt, &team.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	return team, nil
+}
+
+func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	team, err := scanTeam(r.db.QueryRow(ctx, teamSelect+` WHERE t.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	return team, nil
+}
+
+func (r *TeamRepository) List(ctx context.Context, filter models.TeamFilter) ([]models.Team, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Search != "" {
+		cb.addf("(t.name ILIKE %[1]s OR t.slug ILIKE %[1]s)", "%"+filter.Search+"%")
+	}
+	if filter.MemberID != nil {
+		cb.addf("EXISTS (SELECT 1 FROM team_members m WHERE m.team_id = t.id AND m.user_id = %s)", *filter.MemberID)
+	}
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM teams t %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count teams: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "t.name ASC", "t.", teamSortColumns...)
+
+	query := fmt.Sprintf(`%s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		teamSelect, whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []models.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, *team)
+	}
+
+	return teams, total, nil
+}
+
+func (r *TeamRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdateTeamRequest) (*models.Team, error) {
+	var setClauses []string
+	var args []interface{}
+	argNum := 1
+
+	if req.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argNum))
+		args = append(args, *req.Name)
+		argNum++
+	}
+	if req.Slug != nil {
+		setClauses = append(setClauses, fmt.Sprintf("slug = $%d", argNum))
+		args = append(args, *req.Slug)
+		argNum++
+	}
+	if req.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = NULLIF($%d, '')", argNum))
+		args = append(args, *req.Description)
+		argNum++
+	}
+
+	if len(setClauses) > 0 {
+		args = append(args, id)
+		query := fmt.Sprintf(`UPDATE teams SET %s WHERE id = $%d`, strings.Join(setClauses, ", "), argNum)
+
+		result, err := r.db.Exec(ctx, query, args...)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return nil, ErrDuplicate
+			}
+			return nil, fmt.Errorf("failed to update team: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, ErrNotFound
+		}
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a team and its memberships; its posts and media become shared
+func (r *TeamRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM teams WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListMembers returns a team's members with their users, managers first
+func (r *TeamRepository) ListMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.team_id, m.user_id, m.role, m.created_at,
+		       u.id, u.email, u.full_name, u.role, u.is_active, u.last_login, u.created_at, u.updated_at
+		FROM team_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1
+		ORDER BY m.role DESC, u.full_name`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.TeamMember{}
+	for rows.Next() {
+		m := models.TeamMember{User: &models.UserResponse{}}
+		if err := rows.Scan(&m.TeamID, &m.UserID, &m.Role, &m.CreatedAt,
+			&m.User.ID, &m.User.Email, &m.User.FullName, &m.User.Role, &m.User.IsActive,
+			&m.User.LastLogin, &m.User.CreatedAt, &m.User.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// SetMember adds a user to a team with role, or changes the role of an
+// existing member
+func (r *TeamRepository) SetMember(ctx context.Context, teamID, userID uuid.UUID, role models.TeamRole) (*models.TeamMember, error) {
+	m := &models.TeamMember{TeamID: teamID, UserID: userID, Role: role}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO team_members (team_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at`,
+		teamID, userID, role,
+	).Scan(&m.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to set team member: %w", err)
+	}
+	return m, nil
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove team member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CanEdit reports whether a user may own content in a team's space: active
+// admins always may, other users need the team's editor or manager role
+func (r *TeamRepository) CanEdit(ctx context.Context, teamID, userID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM users u
+			WHERE u.id = $2 AND u.is_active
+			  AND (u.role = $3 OR EXISTS (
+			      SELECT 1 FROM team_members m
+			      WHERE m.team_id = $1 AND m.user_id = u.id AND m.role >= $4))
+		)`,
+		teamID, userID, models.RoleAdmin, models.TeamRoleEditor,
+	).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check team membership: %w", err)
+	}
+	return ok, nil
+}
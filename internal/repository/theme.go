@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type ThemeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewThemeRepository(db *pgxpool.Pool) *ThemeRepository {
+	return &ThemeRepository{db: db}
+}
+
+// themeSortColumns are the columns list results can be sorted by
+var themeSortColumns = []string{"created_at", "updated_at", "name", "version", "activated_at"}
+
+// Create adds the next version of the named theme. When BaseID is set the
+// new version starts with a copy of that version's files.
+func (r *ThemeRepository) Create(ctx context.Context, req *models.CreateThemeRequest) (*models.Theme, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	theme := &models.Theme{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	query := `
+		INSERT INTO themes (id, name, version, description)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3
+		FROM themes WHERE name = $2
+		RETURNING version, is_active, created_at, updated_at`
+
+	err = tx.QueryRow(ctx, query, theme.ID, theme.Name, theme.Description).
+		Scan(&theme.Version, &theme.IsActive, &theme.CreatedAt, &theme.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicate
+		}
+		return nil, fmt.Errorf("failed to create theme: %w", err)
+	}
+
+	if req.BaseID != nil {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO theme_files (theme_id, path, content_type, size, checksum, content)
+			SELECT $1, path, content_type, size, checksum, content
+			FROM theme_files WHERE theme_id = $2`,
+			theme.ID, *req.BaseID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy theme files: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			if err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM themes WHERE id = $1)", *req.BaseID).Scan(&exists); err != nil {
+				return nil, fmt.Errorf("failed to check base theme: %w", err)
+			}
+			if !exists {
+				return nil, ErrForeignKey
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, theme.ID)
+}
+
+// GetByID returns a theme version with its file listing (without contents)
+func (r *ThemeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Theme, error) {
+	query := `
+		SELECT id, name, version, description, is_active, activated_at, created_at, updated_at
+		FROM themes
+		WHERE id = $1`
+
+	theme, err := scanTheme(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := r.listFiles(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	theme.Files = files
+
+	return theme, nil
+}
+
+// GetActive returns the active theme version with its file listing, or
+// ErrNotFound when no stored theme is active. Contents are only included
+// for files still kept in the database.
+func (r *ThemeRepository) GetActive(ctx context.Context) (*models.Theme, error) {
+	query := `
+		SELECT id, name, version, description, is_active, activated_at, created_at, updated_at
+		FROM themes
+		WHERE is_active`
+
+	theme, err := scanTheme(r.db.QueryRow(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := r.listFiles(ctx, theme.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	theme.Files = files
+
+	return theme, nil
+}
+
+// GetWithContent returns a theme version with its file listing, including
+// the contents of files still kept in the database
+func (r *ThemeRepository) GetWithContent(ctx context.Context, id uuid.UUID) (*models.Theme, error) {
+	theme, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := r.listFiles(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	theme.Files = files
+
+	return theme, nil
+}
+
+func (r *ThemeRepository) List(ctx context.Context, filter models.ThemeFilter) ([]models.Theme, int64, error) {
+	filter.PaginationParams.Normalize()
+
+	var cb conditionBuilder
+	if filter.Name != "" {
+		cb.addf("name = %s", filter.Name)
+	}
+
+	whereClause, args, argNum := cb.build()
+
+	// Count total
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM themes %s", whereClause)
+	var total int64
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count themes: %w", err)
+	}
+
+	// Get data
+	orderBy := sortOrder(filter.PaginationParams, "name ASC, version DESC", "", themeSortColumns...)
+
+	query := fmt.Sprintf(`
+		SELECT id, name, version, description, is_active, activated_at, created_at, updated_at
+		FROM themes
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		whereClause, orderBy, argNum, argNum+1)
+
+	args = append(args, filter.Limit(), filter.Offset())
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list themes: %w", err)
+	}
+	defer rows.Close()
+
+	var themes []models.Theme
+	for rows.Next() {
+		theme, err := scanTheme(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		themes = append(themes, *theme)
+	}
+
+	return themes, total, nil
+}
+
+// Activate makes id the active theme, deactivating the previous one
+func (r *ThemeRepository) Activate(ctx context.Context, id uuid.UUID) (*models.Theme, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE themes SET is_active = false, activated_at = NULL WHERE is_active AND id <> $1", id); err != nil {
+		return nil, fmt.Errorf("failed to deactivate theme: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, "UPDATE themes SET is_active = true, activated_at = COALESCE(activated_at, NOW()) WHERE id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate theme: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Deactivate turns off the active stored theme so the site falls back to
+// its configured filesystem theme
+func (r *ThemeRepository) Deactivate(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, "UPDATE themes SET is_active = false, activated_at = NULL WHERE is_active"); err != nil {
+		return fmt.Errorf("failed to deactivate theme: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a theme version and its files, returning the checksums
+// of the removed files
+func (r *ThemeRepository) Delete(ctx context.Context, id uuid.UUID) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "DELETE FROM theme_files WHERE theme_id = $1 RETURNING checksum", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete theme files: %w", err)
+	}
+	var checksums []string
+	for rows.Next() {
+		var checksum string
+		if err := rows.Scan(&checksum); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan theme file checksum: %w", err)
+		}
+		checksums = append(checksums, checksum)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to delete theme files: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, "DELETE FROM themes WHERE id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete theme: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return checksums, nil
+}
+
+// PutFile creates or replaces a file in a theme version, whose content is
+// kept in storage under file.Checksum. It returns the checksum of the file
+// it replaced, if any.
+func (r *ThemeRepository) PutFile(ctx context.Context, themeID uuid.UUID, file *models.ThemeFile) (string, error) {
+	query := `
+		WITH previous AS (
+		    SELECT checksum FROM theme_files WHERE theme_id = $1 AND path = $2
+		)
+		INSERT INTO theme_files (theme_id, path, content_type, size, checksum)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (theme_id, path) DO UPDATE
+		SET content_type = EXCLUDED.content_type, size = EXCLUDED.size,
+		    checksum = EXCLUDED.checksum, content = NULL, updated_at = NOW()
+		RETURNING updated_at, COALESCE((SELECT checksum FROM previous), '')`
+
+	file.ThemeID = themeID
+	var previous string
+	err := r.db.QueryRow(ctx, query, themeID, file.Path, file.ContentType, file.Size, file.Checksum).Scan(&file.UpdatedAt, &previous)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to save theme file: %w", err)
+	}
+
+	return previous, nil
+}
+
+// GetFile returns a single file, with its content if it is still kept in
+// the database
+func (r *ThemeRepository) GetFile(ctx context.Context, themeID uuid.UUID, path string) (*models.ThemeFile, error) {
+	query := `
+		SELECT theme_id, path, content_type, size, checksum, content, updated_at
+		FROM theme_files
+		WHERE theme_id = $1 AND path = $2`
+
+	file, err := scanThemeFile(r.db.QueryRow(ctx, query, themeID, path))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get theme file: %w", err)
+	}
+
+	return file, nil
+}
+
+// DeleteFile removes a file from a theme version and returns its checksum
+func (r *ThemeRepository) DeleteFile(ctx context.Context, themeID uuid.UUID, path string) (string, error) {
+	var checksum string
+	err := r.db.QueryRow(ctx, "DELETE FROM theme_files WHERE theme_id = $1 AND path = $2 RETURNING checksum", themeID, path).Scan(&checksum)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to delete theme file: %w", err)
+	}
+
+	return checksum, nil
+}
+
+// ChecksumInUse reports whether any theme file has the given checksum
+func (r *ThemeRepository) ChecksumInUse(ctx context.Context, checksum string) (bool, error) {
+	var inUse bool
+	if err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM theme_files WHERE checksum = $1)", checksum).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("failed to check theme file checksum: %w", err)
+	}
+	return inUse, nil
+}
+
+// FilesWithContent returns up to limit files whose content is still kept
+// in the database, with their content
+func (r *ThemeRepository) FilesWithContent(ctx context.Context, limit int) ([]models.ThemeFile, error) {
+	query := `
+		SELECT theme_id, path, content_type, size, checksum, content, updated_at
+		FROM theme_files
+		WHERE content IS NOT NULL
+		LIMIT $1`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list theme files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []models.ThemeFile
+	for rows.Next() {
+		file, err := scanThemeFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan theme file: %w", err)
+		}
+		files = append(files, *file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list theme files: %w", err)
+	}
+
+	return files, nil
+}
+
+// ClearContent drops the database copy of a file's content once it is in
+// storage. A file replaced in the meantime is left alone.
+func (r *ThemeRepository) ClearContent(ctx context.Context, themeID uuid.UUID, path, checksum string) error {
+	_, err := r.db.Exec(ctx, "UPDATE theme_files SET content = NULL WHERE theme_id = $1 AND path = $2 AND checksum = $3", themeID, path, checksum)
+	if err != nil {
+		return fmt.Errorf("failed to clear theme file content: %w", err)
+	}
+	return nil
+}
+
+func (r *ThemeRepository) listFiles(ctx context.Context, themeID uuid.UUID, withContent bool) ([]models.ThemeFile, error) {
+	content := "NULL::bytea"
+	if withContent {
+		content = "content"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT theme_id, path, content_type, size, checksum, %s, updated_at
+		FROM theme_files
+		WHERE theme_id = $1
+		ORDER BY path`, content)
+
+	rows, err := r.db.Query(ctx, query, themeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list theme files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []models.ThemeFile
+	for rows.Next() {
+		file, err := scanThemeFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan theme file: %w", err)
+		}
+		files = append(files, *file)
+	}
+
+	return files, nil
+}
+
+func scanThemeFile(row pgx.Row) (*models.ThemeFile, error) {
+	file := &models.ThemeFile{}
+	err := row.Scan(&file.ThemeID, &file.Path, &file.ContentType, &file.Size, &file.Checksum, &file.Content, &file.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func scanTheme(row pgx.Row) (*models.Theme, error) {
+	theme := &models.Theme{}
+	err := row.Scan(
+		&theme.ID, &theme.Name, &theme.Version, &theme.Description, &theme.IsActive,
+		&theme.ActivatedAt, &theme.CreatedAt, &theme.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan theme: %w", err)
+	}
+	return theme, nil
+}
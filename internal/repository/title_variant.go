@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type TitleVariantRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTitleVariantRepository(db *pgxpool.Pool) *TitleVariantRepository {
+	return &TitleVariantRepository{db: db}
+}
+
+func (r *TitleVariantRepository) Create(ctx context.Context, postID uuid.UUID, req *models.CreateTitleVariantRequest) (*models.TitleVariant, error) {
+	v := &models.TitleVariant{
+		ID:     uuid.New(),
+		PostID: postID,
+		Title:  req.Title,
+	}
+
+	query := `
+		INSERT INTO post_title_variants (id, post_id, title)
+		VALUES ($1, $2, $3)
+		RETURNING impressions, clicks, created_at`
+
+	err := r.db.QueryRow(ctx, query, v.ID, v.PostID, v.Title).Scan(&v.Impressions, &v.Clicks, &v.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, ErrDuplicate
+			case "23503":
+				return nil, ErrForeignKey
+			}
+		}
+		return nil, fmt.Errorf("failed to create title variant: %w", err)
+	}
+
+	return v, nil
+}
+
+// ListByPost returns a post's variants in creation order; the first one is the control
+func (r *TitleVariantRepository) ListByPost(ctx context.Context, postID uuid.UUID) ([]models.TitleVariant, error) {
+	query := `
+		SELECT id, post_id, title, impressions, clicks, created_at
+		FROM post_title_variants
+		WHERE post_id = $1
+		ORDER BY created_at, id`
+
+	rows, err := r.db.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list title variants: %w", err)
+	}
+	defer rows.Close()
+
+	var variants []models.TitleVariant
+	for rows.Next() {
+		var v models.TitleVariant
+		if err := rows.Scan(&v.ID, &v.PostID, &v.Title, &v.Impressions, &v.Clicks, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan title variant: %w", err)
+		}
+		variants = append(variants, v)
+	}
+
+	return variants, nil
+}
+
+func (r *TitleVariantRepository) Delete(ctx context.Context, postID, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM post_title_variants WHERE id = $1 AND post_id = $2`, id, postID)
+	if err != nil {
+		return fmt.Errorf("failed to delete title variant: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordImpression increments the impression counter of a variant
+func (r *TitleVariantRepository) RecordImpression(ctx context.Context, postID, id uuid.UUID) error {
+	return r.increment(ctx, "impressions", postID, id)
+}
+
+// RecordClick increments the click counter of a variant
+func (r *TitleVariantRepository) RecordClick(ctx context.Context, postID, id uuid.UUID) error {
+	return r.increment(ctx, "clicks", postID, id)
+}
+
+func (r *TitleVariantRepository) increment(ctx context.Context, column string, postID, id uuid.UUID) error {
+	query := fmt.Sprintf(`UPDATE post_title_variants SET %s = %s + 1 WHERE id = $1 AND post_id = $2`, column, column)
+	result, err := r.db.Exec(ctx, query, id, postID)
+	if err != nil {
+		return fmt.Errorf("failed to record title variant %s: %w", column, err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+type TrafficRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTrafficRepository(db *pgxpool.Pool) *TrafficRepository {
+	return &TrafficRepository{db: db}
+}
+
+// RecordView adds a view of a post from source to today's rollup
+func (r *TrafficRepository) RecordView(ctx context.Context, postID uuid.UUID, source models.TrafficSource) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO post_traffic_daily (post_id, day, referrer, utm_source, utm_medium, utm_campaign, views)
+		VALUES ($1, CURRENT_DATE, $2, $3, $4, $5, 1)
+		ON CONFLICT (post_id, day, referrer, utm_source, utm_medium, utm_campaign)
+		DO UPDATE SET views = post_traffic_daily.views + 1`,
+		postID, source.Referrer, source.UTMSource, source.UTMMedium, source.UTMCampaign)
+	if err != nil {
+		return fmt.Errorf("failed to record traffic source: %w", err)
+	}
+	return nil
+}
+
+// Sources summarizes the views of the filter's period by referrer and UTM
+// parameter, keeping the top filter.Limit values of each
+func (r *TrafficRepository) Sources(ctx context.Context, filter models.TrafficSourceFilter) (*models.TrafficSourceReport, error) {
+	var cb conditionBuilder
+	cb.addf("day >= %s", filter.From)
+	cb.addf("day <= %s", filter.To)
+	if filter.PostID != nil {
+		cb.addf("post_id = %s", *filter.PostID)
+	}
+
+	// One pass over the rollups: a grouping set per dimension plus the
+	// grand total, which also counts direct views
+	rows, err := r.db.Query(ctx, `
+		SELECT CASE
+		           WHEN GROUPING(referrer) = 0 THEN 'referrer'
+		           WHEN GROUPING(utm_source) = 0 THEN 'utm_source'
+		           WHEN GROUPING(utm_medium) = 0 THEN 'utm_medium'
+		           WHEN GROUPING(utm_campaign) = 0 THEN 'utm_campaign'
+		           ELSE ''
+		       END,
+		       COALESCE(referrer, utm_source, utm_medium, utm_campaign, ''),
+		       COALESCE(SUM(views), 0),
+		       COALESCE(SUM(views) FILTER (WHERE referrer = '' AND utm_source = '' AND utm_medium = '' AND utm_campaign = ''), 0)
+		FROM post_traffic_daily
+		`+cb.where()+`
+		GROUP BY GROUPING SETS ((referrer), (utm_source), (utm_medium), (utm_campaign), ())
+		ORDER BY 3 DESC, 2`, cb.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize traffic sources: %w", err)
+	}
+	defer rows.Close()
+
+	report := &models.TrafficSourceReport{
+		From:         filter.From,
+		To:           filter.To,
+		PostID:       filter.PostID,
+		Referrers:    []models.TrafficSourceCount{},
+		UTMSources:   []models.TrafficSourceCount{},
+		UTMMediums:   []models.TrafficSourceCount{},
+		UTMCampaigns: []models.TrafficSourceCount{},
+	}
+	lists := map[string]*[]models.TrafficSourceCount{
+		"referrer":     &report.Referrers,
+		"utm_source":   &report.UTMSources,
+		"utm_medium":   &report.UTMMediums,
+		"utm_campaign": &report.UTMCampaigns,
+	}
+	for rows.Next() {
+		var dimension, name string
+		var views, direct int64
+		if err := rows.Scan(&dimension, &name, &views, &direct); err != nil {
+			return nil, fmt.Errorf("failed to scan traffic source: %w", err)
+		}
+		if dimension == "" {
+			report.Views, report.Direct = views, direct
+			continue
+		}
+		list := lists[dimension]
+		if name == "" || len(*list) >= filter.Limit {
+			continue
+		}
+		*list = append(*list, models.TrafficSourceCount{Name: name, Views: views})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to summarize traffic sources: %w", err)
+	}
+
+	// The total row sorts among the others, so shares are set afterwards
+	if report.Views > 0 {
+		for _, list := range lists {
+			for i := range *list {
+				(*list)[i].Share = float64((*list)[i].Views) / float64(report.Views)
+			}
+		}
+	}
+	return report, nil
+}
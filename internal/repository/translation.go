@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// TranslationRepository keeps the per-locale translations of posts
+type TranslationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTranslationRepository(db *pgxpool.Pool) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+const translationColumns = `post_id, locale, title, excerpt, content, status, source_hash, machine_translated, created_at, updated_at`
+
+func scanTranslation(row pgx.Row) (*models.PostTranslation, error) {
+	t := &models.PostTranslation{}
+	err := row.Scan(&t.PostID, &t.Locale, &t.Title, &t.Excerpt, &t.Content, &t.Status,
+		&t.SourceHash, &t.MachineTranslated, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// ListByPost returns the translations of a post by locale
+func (r *TranslationRepository) ListByPost(ctx context.Context, postID uuid.UUID) ([]models.PostTranslation, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+translationColumns+`
+		FROM post_translations
+		WHERE post_id = $1
+		ORDER BY locale`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	defer rows.Close()
+
+	var translations []models.PostTranslation
+	for rows.Next() {
+		t, err := scanTranslation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %w", err)
+		}
+		translations = append(translations, *t)
+	}
+	return translations, rows.Err()
+}
+
+// Get returns the translation of a post into locale
+func (r *TranslationRepository) Get(ctx context.Context, postID uuid.UUID, locale string) (*models.PostTranslation, error) {
+	t, err := scanTranslation(r.db.QueryRow(ctx, `
+		SELECT `+translationColumns+`
+		FROM post_translations
+		WHERE post_id = $1 AND locale = $2`, postID, locale))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+	return t, nil
+}
+
+// Save creates or replaces the translation of a post into t.Locale
+func (r *TranslationRepository) Save(ctx context.Context, t *models.PostTranslation) (*models.PostTranslation, error) {
+	saved, err := scanTranslation(r.db.QueryRow(ctx, `
+		INSERT INTO post_translations (post_id, locale, title, excerpt, content, status, source_hash, machine_translated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (post_id, locale) DO UPDATE
+		SET title = EXCLUDED.title, excerpt = EXCLUDED.excerpt, content = EXCLUDED.content, status = EXCLUDED.status,
+		    source_hash = EXCLUDED.source_hash, machine_translated = EXCLUDED.machine_translated
+		RETURNING `+translationColumns,
+		t.PostID, t.Locale, t.Title, t.Excerpt, t.Content, t.Status, t.SourceHash, t.MachineTranslated))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrForeignKey
+		}
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return saved, nil
+}
+
+// Delete removes the translation of a post into locale
+func (r *TranslationRepository) Delete(ctx context.Context, postID uuid.UUID, locale string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM post_translations WHERE post_id = $1 AND locale = $2`, postID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
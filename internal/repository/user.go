@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keeps-dev/go-cms-template/internal/models"
 )
@@ -90,7 +91,7 @@ func (r *UserRepository) List(ctx context.Context, filter models.PaginationParam
 	}
 	defer rows.Close()
 
-	var users []models.User
+	users := []models.User{}
 	for rows.Next() {
 		var user models.User
 		if err := rows.Scan(
@@ -105,6 +106,148 @@ func (r *UserRepository) List(ctx context.Context, filter models.PaginationParam
 	return users, total, nil
 }
 
+// ListRange returns users in creation order starting at offset, for clients
+// such as SCIM that page by index rather than by page number
+func (r *UserRepository) ListRange(ctx context.Context, offset, limit int) ([]models.User, int64, error) {
+	var total int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, email, password_hash, full_name, role, is_active, last_login, created_at, updated_at
+		FROM users
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID, &user.Email, &user.PasswordHash, &user.FullName,
+			&user.Role, &user.IsActive, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// Create adds a user whose password has already been hashed
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	user.ID = uuid.New()
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO users (id, email, password_hash, full_name, role, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`,
+		user.ID, user.Email, user.PasswordHash, user.FullName, user.Role, user.IsActive,
+	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// SetRole changes a user's role and active flag
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, role models.Role, active bool) error {
+	result, err := r.db.Exec(ctx, `UPDATE users SET role = $2, is_active = $3 WHERE id = $1`, id, role, active)
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Update saves a user's email, name, role and active flag
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE users SET email = $2, full_name = $3, role = $4, is_active = $5
+		WHERE id = $1
+		RETURNING updated_at`,
+		user.ID, user.Email, user.FullName, user.Role, user.IsActive,
+	).Scan(&user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicate
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// SetPassword replaces a user's password hash
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	result, err := r.db.Exec(ctx, `UPDATE users SET password_hash = $2 WHERE id = $1`, id, passwordHash)
+	if err != nil {
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Deactivate marks a user inactive, keeping their content and history
+func (r *UserRepository) Deactivate(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user := &models.User{}
+	err := r.db.QueryRow(ctx, `
+		UPDATE users SET is_active = false
+		WHERE id = $1
+		RETURNING id, email, password_hash, full_name, role, is_active, last_login, created_at, updated_at`, id,
+	).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.FullName,
+		&user.Role, &user.IsActive, &user.LastLogin, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	return user, nil
+}
+
+// Delete removes a user. It returns ErrForeignKey while the user is still
+// the author of posts, post templates or annotations.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrForeignKey
+		}
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TouchLastLogin records that a user has just logged in
+func (r *UserRepository) TouchLastLogin(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.Exec(ctx, `UPDATE users SET last_login = CURRENT_TIMESTAMP WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
 // Exists checks if a user exists by ID
 func (r *UserRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 	var exists bool
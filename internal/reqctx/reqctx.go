@@ -0,0 +1,155 @@
+// Package reqctx carries typed per-request information through
+// context.Context, so handlers, mailers and repositories can read who is
+// calling without threading it through every signature.
+package reqctx
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// HeaderRequestID carries the request ID between services
+const HeaderRequestID = "X-Request-ID"
+
+// Info describes the request being served. It is set once by middleware
+// and must not be modified afterwards.
+type Info struct {
+	// RequestID is the X-Request-ID echoed to the client
+	RequestID string
+	// IP is the client address, taken from forwarding headers only when
+	// the request came through a trusted proxy
+	IP string
+	// Locale is the locale negotiated from Accept-Language in lowercase
+	// (e.g. "pt-br", see locale.Negotiator), or "" when neither the server
+	// nor the client names one
+	Locale string
+
+	// caller is shared by every copy of the info made while serving the
+	// request, so middleware running before authentication still sees the
+	// caller once the handler returns
+	caller *atomic.Pointer[Caller]
+}
+
+// Caller is the authenticated caller of a request. The zero value is an
+// anonymous caller.
+type Caller struct {
+	// UserID and Role are those of the user the request acts as, also for
+	// requests made with one of their API keys
+	UserID uuid.UUID
+	Role   models.Role
+	// APIKeyID is set for requests authenticated with an API key
+	APIKeyID uuid.UUID
+	// DeliveryTokenID is set for delivery API requests made with a token
+	DeliveryTokenID uuid.UUID
+}
+
+// Caller returns the authenticated caller, the zero Caller for anonymous
+// requests and outside a request
+func (i *Info) Caller() Caller {
+	if i.caller != nil {
+		if c := i.caller.Load(); c != nil {
+			return *c
+		}
+	}
+	return Caller{}
+}
+
+// Client names the caller for usage tracking, rate limits and the access
+// log: key:<API key ID>, user:<user ID>, delivery:<token ID> or anonymous.
+// Only verified credentials name a caller.
+func (i *Info) Client() string {
+	c := i.Caller()
+	switch {
+	case c.APIKeyID != uuid.Nil:
+		return "key:" + c.APIKeyID.String()
+	case c.UserID != uuid.Nil:
+		return "user:" + c.UserID.String()
+	case c.DeliveryTokenID != uuid.Nil:
+		return "delivery:" + c.DeliveryTokenID.String()
+	}
+	return "anonymous"
+}
+
+// SetCaller records the authenticated caller of the request ctx belongs
+// to. The auth middleware calls it once the credentials are verified;
+// outside a request it does nothing.
+func SetCaller(ctx context.Context, caller Caller) {
+	if info := From(ctx); info.caller != nil {
+		info.caller.Store(&caller)
+	}
+}
+
+type infoKey struct{}
+
+// With returns a copy of ctx carrying info. Info made from scratch gets the
+// caller of the request ctx belongs to, or a new anonymous one.
+func With(ctx context.Context, info *Info) context.Context {
+	if info.caller == nil {
+		info.caller = From(ctx).caller
+		if info.caller == nil {
+			info.caller = new(atomic.Pointer[Caller])
+		}
+	}
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// From returns the request info stored in ctx. It never returns nil:
+// outside a request (background jobs, tests) every field is empty.
+func From(ctx context.Context) *Info {
+	if info, ok := ctx.Value(infoKey{}).(*Info); ok {
+		return info
+	}
+	return &Info{}
+}
+
+// Detach returns a background context carrying ctx's request info, for
+// work that outlives the request but should still be correlated with it
+func Detach(ctx context.Context) context.Context {
+	return With(context.Background(), From(ctx))
+}
+
+// WithRequestID returns a copy of ctx whose request info has requestID, for
+// resuming correlation from a stored ID (e.g. a queued email)
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	info := *From(ctx)
+	info.RequestID = requestID
+	return With(ctx, &info)
+}
+
+// Logf logs like log.Printf, appending request_id=... when ctx belongs to
+// a request so log lines can be correlated across services
+func Logf(ctx context.Context, format string, v ...interface{}) {
+	if id := From(ctx).RequestID; id != "" {
+		format += " request_id=%s"
+		v = append(v, id)
+	}
+	log.Printf(format, v...)
+}
+
+// Propagate sets the X-Request-ID header of an outbound request from its
+// context, so downstream services can log the same ID
+func Propagate(req *http.Request) {
+	if id := From(req.Context()).RequestID; id != "" {
+		req.Header.Set(HeaderRequestID, id)
+	}
+}
+
+// ValidRequestID reports whether a client-supplied request ID is safe to
+// reuse: short and printable ASCII without spaces, since it ends up in log
+// lines and outbound headers
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
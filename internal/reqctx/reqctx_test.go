@@ -0,0 +1,51 @@
+package reqctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+func TestSetCallerReachesEveryCopy(t *testing.T) {
+	outer := With(context.Background(), &Info{RequestID: "req-1"})
+
+	// Middleware between the request ID and authentication copies the info
+	info := *From(outer)
+	info.IP = "192.0.2.1"
+	inner := With(outer, &info)
+
+	caller := Caller{UserID: uuid.New(), Role: models.RoleEditor}
+	SetCaller(inner, caller)
+
+	for name, ctx := range map[string]context.Context{"outer": outer, "inner": inner, "detached": Detach(inner)} {
+		if got := From(ctx).Caller(); got != caller {
+			t.Errorf("%s caller = %+v, want %+v", name, got, caller)
+		}
+	}
+	if got := From(context.Background()).Caller(); got != (Caller{}) {
+		t.Errorf("caller outside a request = %+v, want anonymous", got)
+	}
+	SetCaller(context.Background(), caller) // no request: does nothing
+}
+
+func TestInfoClient(t *testing.T) {
+	userID, keyID, tokenID := uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		caller Caller
+		want   string
+	}{
+		{Caller{}, "anonymous"},
+		{Caller{UserID: userID, Role: models.RoleUser}, "user:" + userID.String()},
+		{Caller{UserID: userID, APIKeyID: keyID}, "key:" + keyID.String()},
+		{Caller{DeliveryTokenID: tokenID}, "delivery:" + tokenID.String()},
+	}
+	for _, tt := range tests {
+		ctx := With(context.Background(), &Info{})
+		SetCaller(ctx, tt.caller)
+		if got := From(ctx).Client(); got != tt.want {
+			t.Errorf("Client() for %+v = %q, want %q", tt.caller, got, tt.want)
+		}
+	}
+}
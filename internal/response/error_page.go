@@ -0,0 +1,81 @@
+package response
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ErrorPage holds the branded content used for public error responses.
+// Reference, when set, is an ID the user can quote to support.
+type ErrorPage struct {
+	Message    string
+	SupportURL string
+	Reference  string
+	HTML       bool
+}
+
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.StatusText}}</title>
+<style>body{font-family:system-ui,sans-serif;max-width:40rem;margin:4rem auto;padding:0 1rem;color:#222}h1{font-size:1.5rem}</style>
+</head>
+<body>
+<h1>{{.Status}} {{.StatusText}}</h1>
+<p>{{.Message}}</p>
+{{if .Reference}}<p>Reference: <code>{{.Reference}}</code></p>{{end}}
+{{if .SupportURL}}<p><a href="{{.SupportURL}}">Contact support</a></p>{{end}}
+</body>
+</html>
+`))
+
+// WriteErrorPage sends an error using the branded page, rendering HTML when the
+// page allows it and the client prefers text/html
+func WriteErrorPage(w http.ResponseWriter, r *http.Request, status int, code, defaultMessage string, page ErrorPage) {
+	message := defaultMessage
+	if page.Message != "" {
+		message = page.Message
+	}
+
+	if page.HTML && acceptsHTML(r) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		err := errorPageTemplate.Execute(w, map[string]interface{}{
+			"Status":     status,
+			"StatusText": http.StatusText(status),
+			"Message":    message,
+			"SupportURL": page.SupportURL,
+			"Reference":  page.Reference,
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to render error page: %v", err)
+		}
+		return
+	}
+
+	details := map[string]string{}
+	if page.SupportURL != "" {
+		details["support_url"] = page.SupportURL
+	}
+	if page.Reference != "" {
+		details["reference"] = page.Reference
+	}
+	if len(details) > 0 {
+		ErrorWithDetails(w, status, code, message, details)
+		return
+	}
+	Error(w, status, code, message)
+}
+
+func acceptsHTML(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if mediaType == "text/html" {
+			return true
+		}
+	}
+	return false
+}
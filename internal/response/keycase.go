@@ -0,0 +1,350 @@
+package response
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KeyCase is the casing of JSON object keys in API responses
+type KeyCase string
+
+const (
+	SnakeCase KeyCase = "snake"
+	CamelCase KeyCase = "camel"
+)
+
+// ParseKeyCase validates a key case name
+func ParseKeyCase(s string) (KeyCase, bool) {
+	switch c := KeyCase(strings.ToLower(s)); c {
+	case SnakeCase, CamelCase:
+		return c, true
+	}
+	return "", false
+}
+
+// WithKeyCase returns a writer whose API responses use the given key case.
+// Models keep their snake_case json tags; the keys are converted while
+// encoding, so there is one set of models for both cases.
+func WithKeyCase(w http.ResponseWriter, c KeyCase) http.ResponseWriter {
+	return &keyCaseWriter{ResponseWriter: w, keyCase: c}
+}
+
+type keyCaseWriter struct {
+	http.ResponseWriter
+	keyCase KeyCase
+}
+
+// Flush lets streaming handlers flush through the writer
+func (kw *keyCaseWriter) Flush() {
+	if f, ok := kw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (kw *keyCaseWriter) Unwrap() http.ResponseWriter {
+	return kw.ResponseWriter
+}
+
+// keyCaseOf finds the key case set on w or a writer it wraps
+func keyCaseOf(w http.ResponseWriter) KeyCase {
+	for w != nil {
+		if kw, ok := w.(*keyCaseWriter); ok {
+			return kw.keyCase
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+	return SnakeCase
+}
+
+// encode writes v as JSON in the key case of w, leaving out the fields its
+// redactor hides
+func encode(w http.ResponseWriter, v interface{}) {
+	enc := encoder{camel: keyCaseOf(w) == CamelCase, redact: redactorOf(w)}
+	if !enc.camel && enc.redact == nil {
+		json.NewEncoder(w).Encode(v)
+		return
+	}
+	var buf bytes.Buffer
+	if err := enc.encode(&buf, reflect.ValueOf(v)); err != nil {
+		if enc.redact != nil {
+			// Encoding without the redactor would reveal hidden fields
+			w.Write([]byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Failed to encode response"}}` + "\n"))
+			return
+		}
+		json.NewEncoder(w).Encode(v)
+		return
+	}
+	buf.WriteByte('\n')
+	w.Write(buf.Bytes())
+}
+
+// CamelKey converts a snake_case key to camelCase ("page_size" to "pageSize")
+func CamelKey(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// camelKeys converts the keys of error details, which name request fields
+func camelKeys(details map[string]string) map[string]string {
+	out := make(map[string]string, len(details))
+	for k, v := range details {
+		out[CamelKey(k)] = v
+	}
+	return out
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encoder encodes like encoding/json, except that struct field names are
+// converted to camelCase when camel is set, and fields hidden by redact are
+// left out. Values with their own JSON encoding (times, IDs, stored raw
+// JSON) and the keys of maps, which usually hold data such as custom field
+// names, are left as they are.
+type encoder struct {
+	camel  bool
+	redact Redactor
+}
+
+func (e encoder) encode(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		(v.CanAddr() && (reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType))) {
+		if v.CanAddr() && t.Kind() != reflect.Pointer {
+			v = v.Addr()
+		}
+		return writeJSON(buf, v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return e.encode(buf, v.Elem())
+	case reflect.Struct:
+		var hidden []string
+		if e.redact != nil {
+			hidden = e.redact(v)
+		}
+		buf.WriteByte('{')
+		first := true
+		for _, f := range structFields(t) {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || (f.omitEmpty && isEmptyValue(fv)) || slices.Contains(hidden, f.tag) {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			if e.camel {
+				writeJSON(buf, f.name)
+			} else {
+				writeJSON(buf, f.tag)
+			}
+			buf.WriteByte(':')
+			if f.quoted {
+				b, err := json.Marshal(fv.Interface())
+				if err != nil {
+					return err
+				}
+				writeJSON(buf, string(b))
+				continue
+			}
+			if err := e.encode(buf, fv); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return writeJSON(buf, v.Interface())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, k.String())
+			buf.WriteByte(':')
+			if err := e.encode(buf, v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return writeJSON(buf, v.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := e.encode(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	return writeJSON(buf, v.Interface())
+}
+
+// writeJSON appends v as encoding/json (and json.Encoder, with HTML escaping)
+// would write it
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// encodedField is a struct field as encoded: name is its JSON name in
+// camelCase and tag the name as tagged
+type encodedField struct {
+	name      string
+	tag       string
+	index     []int
+	omitEmpty bool
+	quoted    bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []encodedField
+
+// structFields lists the encoded fields of a struct type, flattening
+// embedded structs. Unlike encoding/json, a field whose camelCase name
+// clashes with an earlier one is dropped rather than resolved by depth.
+func structFields(t reflect.Type) []encodedField {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]encodedField)
+	}
+
+	var fields []encodedField
+	seen := make(map[string]bool)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(append([]int{}, index...), i)
+
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				walk(ft, idx)
+				continue
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			camel := CamelKey(name)
+			if seen[camel] {
+				continue
+			}
+			seen[camel] = true
+			fields = append(fields, encodedField{
+				name:      camel,
+				tag:       name,
+				index:     idx,
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+				quoted:    strings.Contains(","+opts+",", ",string,"),
+			})
+		}
+	}
+	walk(t, nil)
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports a nil embedded
+// pointer instead of panicking
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue matches encoding/json's omitempty rule
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
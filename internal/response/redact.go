@@ -0,0 +1,60 @@
+package response
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+)
+
+// Redactor returns the JSON names (as tagged) of the fields of struct value
+// v to leave out of a response, or nil to encode them all
+type Redactor func(v reflect.Value) []string
+
+// WithRedactor returns a writer whose API responses leave out the fields
+// redact hides, in every struct they contain, so detail and list responses
+// are redacted alike
+func WithRedactor(w http.ResponseWriter, redact Redactor) http.ResponseWriter {
+	return &redactWriter{ResponseWriter: w, redact: redact}
+}
+
+type redactWriter struct {
+	http.ResponseWriter
+	redact Redactor
+}
+
+// Flush lets streaming handlers flush through the writer
+func (rw *redactWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *redactWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hides reports whether the redactor of w hides field (its JSON name) of
+// struct v, for responses built by hand rather than encoded from models
+func Hides(w http.ResponseWriter, v interface{}, field string) bool {
+	redact := redactorOf(w)
+	if redact == nil {
+		return false
+	}
+	return slices.Contains(redact(reflect.Indirect(reflect.ValueOf(v))), field)
+}
+
+// redactorOf finds the redactor set on w or a writer it wraps
+func redactorOf(w http.ResponseWriter) Redactor {
+	for w != nil {
+		if rw, ok := w.(*redactWriter); ok {
+			return rw.redact
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
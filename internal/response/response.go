@@ -1,9 +1,10 @@
 package response
 
 import (
-	"encoding/json"
 	"log"
 	"net/http"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
 )
 
 // APIResponse represents a standardized API response
@@ -27,6 +28,17 @@ type Meta struct {
 	PageSize   int   `json:"page_size,omitempty"`
 	Total      int64 `json:"total,omitempty"`
 	TotalPages int   `json:"total_pages,omitempty"`
+
+	// Facets holds value counts requested alongside list results
+	Facets []models.Facet `json:"facets,omitempty"`
+
+	// Freshness tells when precomputed aggregates in the data were last
+	// refreshed
+	Freshness []models.AggregateFreshness `json:"freshness,omitempty"`
+
+	// Truncated is set on partial results: true when the request ran out
+	// of time and the data covers only part of what was asked for
+	Truncated *bool `json:"truncated,omitempty"`
 }
 
 // JSON sends a JSON response with the given status code
@@ -37,7 +49,7 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 		Success: status >= 200 && status < 300,
 		Data:    data,
 	}
-	json.NewEncoder(w).Encode(response)
+	encode(w, response)
 }
 
 // JSONWithMeta sends a JSON response with metadata
@@ -49,7 +61,7 @@ func JSONWithMeta(w http.ResponseWriter, status int, data interface{}, meta *Met
 		Data:    data,
 		Meta:    meta,
 	}
-	json.NewEncoder(w).Encode(response)
+	encode(w, response)
 }
 
 // Error sends an error response
@@ -63,11 +75,14 @@ func Error(w http.ResponseWriter, status int, code, message string) {
 			Message: message,
 		},
 	}
-	json.NewEncoder(w).Encode(response)
+	encode(w, response)
 }
 
 // ErrorWithDetails sends an error response with details
 func ErrorWithDetails(w http.ResponseWriter, status int, code, message string, details map[string]string) {
+	if keyCaseOf(w) == CamelCase {
+		details = camelKeys(details)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	response := APIResponse{
@@ -78,7 +93,7 @@ func ErrorWithDetails(w http.ResponseWriter, status int, code, message string, d
 			Details: details,
 		},
 	}
-	json.NewEncoder(w).Encode(response)
+	encode(w, response)
 }
 
 // BadRequest sends a 400 Bad Request error
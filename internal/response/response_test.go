@@ -0,0 +1,148 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// discardWriter is a ResponseWriter that drops the body, so benchmarks
+// measure encoding rather than buffering
+type discardWriter struct {
+	header http.Header
+}
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardWriter) WriteHeader(int)             {}
+
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{header: make(http.Header)}
+}
+
+// recorder is a discardWriter that keeps the body
+type recorder struct {
+	discardWriter
+	body strings.Builder
+}
+
+func newRecorder() *recorder {
+	return &recorder{discardWriter: discardWriter{header: make(http.Header)}}
+}
+
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+// benchPosts returns a page of n posts shaped like list results
+func benchPosts(n int) []models.ContentPost {
+	now := time.Now()
+	content := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 40)
+	posts := make([]models.ContentPost, n)
+	for i := range posts {
+		excerpt := fmt.Sprintf("Excerpt of post %d", i)
+		views := int64(i * 10)
+		posts[i] = models.ContentPost{
+			ID:            uuid.New(),
+			ContentTypeID: uuid.New(),
+			AuthorID:      uuid.New(),
+			Title:         fmt.Sprintf("Post number %d", i),
+			Slug:          fmt.Sprintf("post-number-%d", i),
+			Excerpt:       &excerpt,
+			Content:       &content,
+			Metadata:      json.RawMessage(`{"color":"red","price":100,"tags":["a","b"]}`),
+			Status:        models.PostStatusPublished,
+			PublishedAt:   &now,
+			ViewCount:     i * 100,
+			Environment:   models.EnvironmentLive,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Views7d:       &views,
+		}
+	}
+	return posts
+}
+
+func benchMeta() *Meta {
+	return &Meta{Page: 1, PageSize: 20, Total: 95, TotalPages: 5}
+}
+
+func TestJSONWithMetaEnvelope(t *testing.T) {
+	rec := newRecorder()
+	JSONWithMeta(rec, http.StatusOK, []string{"a"}, benchMeta())
+
+	want := `{"success":true,"data":["a"],"meta":{"page":1,"page_size":20,"total":95,"total_pages":5}}` + "\n"
+	if got := rec.body.String(); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+	if ct := rec.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestJSONCamelCase(t *testing.T) {
+	rec := newRecorder()
+	JSONWithMeta(WithKeyCase(rec, CamelCase), http.StatusOK, nil, benchMeta())
+
+	want := `{"success":true,"meta":{"page":1,"pageSize":20,"total":95,"totalPages":5}}` + "\n"
+	if got := rec.body.String(); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
+
+func BenchmarkJSONDetail(b *testing.B) {
+	post := &benchPosts(1)[0]
+	w := newDiscardWriter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		JSON(w, http.StatusOK, post)
+	}
+}
+
+func BenchmarkJSONWithMetaList(b *testing.B) {
+	posts := benchPosts(20)
+	meta := benchMeta()
+	w := newDiscardWriter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		JSONWithMeta(w, http.StatusOK, posts, meta)
+	}
+}
+
+func BenchmarkJSONWithMetaListCamelCase(b *testing.B) {
+	posts := benchPosts(20)
+	meta := benchMeta()
+	w := WithKeyCase(newDiscardWriter(), CamelCase)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		JSONWithMeta(w, http.StatusOK, posts, meta)
+	}
+}
+
+func BenchmarkJSONWithMetaListRedacted(b *testing.B) {
+	posts := benchPosts(20)
+	meta := benchMeta()
+	w := WithRedactor(newDiscardWriter(), func(v reflect.Value) []string {
+		if v.Type() == reflect.TypeOf(models.ContentPost{}) {
+			return []string{"view_count", "views_7d"}
+		}
+		return nil
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		JSONWithMeta(w, http.StatusOK, posts, meta)
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	w := newDiscardWriter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NotFound(w, "Post not found")
+	}
+}
@@ -1,123 +1,774 @@
 package router
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keeps-dev/go-cms-template/internal/admin"
+	"github.com/keeps-dev/go-cms-template/internal/ai"
+	"github.com/keeps-dev/go-cms-template/internal/anomaly"
+	"github.com/keeps-dev/go-cms-template/internal/auth"
+	"github.com/keeps-dev/go-cms-template/internal/captcha"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/database"
+	"github.com/keeps-dev/go-cms-template/internal/database/migrate"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/geoip"
 	"github.com/keeps-dev/go-cms-template/internal/handlers"
+	"github.com/keeps-dev/go-cms-template/internal/leader"
+	"github.com/keeps-dev/go-cms-template/internal/locale"
+	"github.com/keeps-dev/go-cms-template/internal/mailer"
 	"github.com/keeps-dev/go-cms-template/internal/middleware"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/notify"
+	"github.com/keeps-dev/go-cms-template/internal/permalink"
+	"github.com/keeps-dev/go-cms-template/internal/proofread"
+	"github.com/keeps-dev/go-cms-template/internal/redaction"
+	"github.com/keeps-dev/go-cms-template/internal/replication"
 	"github.com/keeps-dev/go-cms-template/internal/repository"
 	"github.com/keeps-dev/go-cms-template/internal/response"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+	"github.com/keeps-dev/go-cms-template/internal/social"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
+	"github.com/keeps-dev/go-cms-template/internal/web"
 )
 
-func New(db *pgxpool.Pool) *chi.Mux {
+func New(cfg *config.Config, db *pgxpool.Pool, dbMonitor *database.HostMonitor, geo *geoip.Resolver, usage middleware.UsageRecorder, locker *leader.Locker, failover *replication.Failover, bus *events.Bus) *chi.Mux {
 	r := chi.NewRouter()
 
+	settingRepo := repository.NewSettingRepository(db)
+	errorPageHandler := handlers.NewErrorPageHandler(settingRepo)
+	notifier := notify.New(settingRepo)
+	recovery := middleware.NewRecovery(errorPageHandler.InternalError, panicAlert(notifier))
+
 	// Middleware
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	keyCase, ok := response.ParseKeyCase(cfg.Server.JSONKeyCase)
+	if !ok {
+		log.Fatalf("Invalid JSON_KEY_CASE %q (must be snake or camel)", cfg.Server.JSONKeyCase)
+	}
+
+	// Requests are served in the locale negotiated from Accept-Language
+	localeFallbacks := locale.NewFallbacks(cfg.Locale.Fallbacks)
+	negotiator := locale.NewNegotiator(cfg.Locale.Supported, localeFallbacks)
+
 	r.Use(middleware.RequestID)
+	r.Use(negotiator.Middleware)
+	r.Use(middleware.RealIP(trustedProxies))
 	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	if cfg.Database.QueryStats {
+		r.Use(middleware.QueryBudget(cfg.Database.QueryBudget, cfg.Database.QueryTimeBudget))
+	}
+	r.Use(recovery.Middleware)
+	if cfg.LoadShed.Enabled {
+		shedder := middleware.NewLoadShedder(cfg.LoadShed.MaxInFlight, cfg.LoadShed.LatencyTarget, cfg.LoadShed.RetryAfter, requestPriority)
+		r.Use(shedder.Middleware)
+	}
+	if usage != nil {
+		r.Use(middleware.APIUsage(usage))
+	}
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", auth.APIKeyHeader, anomaly.TokenHeader},
+		ExposedHeaders:   []string{"X-Request-ID", "Retry-After", middleware.HeaderDBQueries, middleware.HeaderDBTime},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	r.Use(middleware.Timeout(requestTimeout(cfg.Timeout)))
+	// Inside the timeout so handlers see the key case through its writer
+	r.Use(middleware.KeyCase(keyCase))
 
 	// Initialize repositories
 	contentTypeRepo := repository.NewContentTypeRepository(db)
 	contentPostRepo := repository.NewContentPostRepository(db)
 	mediaRepo := repository.NewMediaRepository(db)
 	tagRepo := repository.NewTagRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
 	contactRepo := repository.NewContactRepository(db)
-	settingRepo := repository.NewSettingRepository(db)
+	consentRepo := repository.NewConsentRepository(db)
+	titleVariantRepo := repository.NewTitleVariantRepository(db)
+	blocklistRepo := repository.NewBlocklistRepository(db)
+	emailRepo := repository.NewEmailRepository(db)
+	emailTemplateRepo := repository.NewEmailTemplateRepository(db)
+	themeRepo := repository.NewThemeRepository(db)
+	cacheHintRepo := repository.NewCacheHintRepository(db)
+	subscriberRepo := repository.NewSubscriberRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	apiUsageRepo := repository.NewAPIUsageRepository(db)
+	accessLogRepo := repository.NewAccessLogRepository(db)
+	pollRepo := repository.NewPollRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	duplicateRepo := repository.NewDuplicateRepository(db)
+	referenceRepo := repository.NewReferenceRepository(db)
+	digestRepo := repository.NewDigestRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
+	postTemplateRepo := repository.NewPostTemplateRepository(db)
+	annotationRepo := repository.NewAnnotationRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	suggestionRepo := repository.NewSuggestionRepository(db)
+	socialShareRepo := repository.NewSocialShareRepository(db)
+	trafficRepo := repository.NewTrafficRepository(db)
+	flaggedIPRepo := repository.NewFlaggedIPRepository(db)
+
+	// Handlers only record reads of personal data when access logging is on
+	var accessLogWriter *repository.AccessLogRepository
+	if cfg.AccessLog.Enabled {
+		accessLogWriter = accessLogRepo
+	}
+
+	mail := mailer.New(mailer.NewRenderer(emailTemplateRepo, localeFallbacks, cfg.Mail.DefaultLocale), emailRepo)
+
+	var store storage.Storage
+	if cfg.Storage.Driver != "" {
+		store, err = storage.New(cfg.Storage)
+		if err != nil {
+			log.Fatalf("Invalid storage configuration: %v", err)
+		}
+	}
+
+	// Theme files written before they were kept in storage move there once
+	themeService := service.NewThemeService(themeRepo, store, cfg.Web.ThemeBucket)
+	if !cfg.IsPublicOnly() {
+		if moved, err := themeService.MoveToStorage(context.Background()); err != nil {
+			log.Printf("[ERROR] Failed to move theme files to storage: %v", err)
+		} else if moved > 0 {
+			log.Printf("Moved %d theme files to storage", moved)
+		}
+	}
+
+	var site *web.Site
+	if cfg.Web.Enabled {
+		site, err = web.New(cfg.Web, cfg.IsDevelopment(), contentPostRepo, contentTypeRepo, settingRepo, themeService, cacheHintRepo)
+		if err != nil {
+			log.Fatalf("Failed to load web theme: %v", err)
+		}
+		if err := site.LoadActiveTheme(context.Background()); err != nil {
+			log.Printf("[ERROR] Failed to load active stored theme, using %s: %v", cfg.Web.Theme, err)
+		}
+	}
+
+	var assistant *ai.Assistant
+	if cfg.AI.Provider != "" {
+		assistant, err = ai.New(cfg.AI)
+		if err != nil {
+			log.Fatalf("Invalid AI configuration: %v", err)
+		}
+	}
+
+	var checker proofread.Checker
+	if cfg.Proofread.Driver != "" {
+		checker, err = proofread.New(cfg.Proofread)
+		if err != nil {
+			log.Fatalf("Invalid proofreading configuration: %v", err)
+		}
+	}
+
+	var verifier captcha.Verifier
+	if cfg.Captcha.Provider != "" {
+		verifier, err = captcha.New(cfg.Captcha)
+		if err != nil {
+			log.Fatalf("Invalid CAPTCHA configuration: %v", err)
+		}
+	}
+	detector := anomaly.New(flaggedIPRepo, verifier, cfg.Anomaly)
+	deliveryTokenRepo := repository.NewDeliveryTokenRepository(db)
+	deliveryAuth := delivery.New(deliveryTokenRepo, cfg.Delivery, negotiator)
+	var authenticator *auth.Authenticator
+	if cfg.Auth.JWTSecret != "" {
+		authenticator, err = auth.New(sessionRepo, userRepo, apiKeyRepo, cfg.Auth)
+		if err != nil {
+			log.Fatalf("Invalid auth configuration: %v", err)
+		}
+	} else if !cfg.IsPublicOnly() {
+		log.Println("[WARN] AUTH_JWT_SECRET is not set; only the public routes of /api/v1 are available")
+	}
+
+	anonymousRole, ok := models.ParseEnum[models.Role](cfg.Auth.AnonymousRole)
+	if !ok || anonymousRole.String() == "unknown" {
+		log.Fatalf("Invalid AUTH_ANONYMOUS_ROLE %q", cfg.Auth.AnonymousRole)
+	}
+
+	var replicationRepo *repository.MediaReplicationRepository
+	if store != nil && cfg.Replication.Replica.Driver != "" {
+		replicationRepo = repository.NewMediaReplicationRepository(db)
+	}
+
+	// Writes with side effects go through services, which publish domain
+	// events to bus
+	approvalRepo := repository.NewApprovalRepository(db)
+	postService := service.NewPostService(contentPostRepo, approvalRepo, bus)
+	mediaService := service.NewMediaService(mediaRepo, bus)
+	contactService := service.NewContactService(contactRepo, bus)
 
 	// Initialize handlers
 	contentTypeHandler := handlers.NewContentTypeHandler(contentTypeRepo)
-	contentPostHandler := handlers.NewContentPostHandler(contentPostRepo)
-	mediaHandler := handlers.NewMediaHandler(mediaRepo)
+	contentPostHandler := handlers.NewContentPostHandler(contentPostRepo, postService, contentTypeRepo, teamRepo, postTemplateRepo, annotationRepo, trafficRepo)
+	postTemplateHandler := handlers.NewPostTemplateHandler(postTemplateRepo, contentTypeRepo, teamRepo)
+	mediaHandler := handlers.NewMediaHandler(mediaRepo, mediaService, store, replicationRepo, failover, cfg.MediaURL, cfg.MediaUpload, cfg.Mail.PublicURL)
 	tagHandler := handlers.NewTagHandler(tagRepo)
-	contactHandler := handlers.NewContactHandler(contactRepo)
+	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
+	teamHandler := handlers.NewTeamHandler(teamRepo)
+	userHandler := handlers.NewUserHandler(userRepo, sessionRepo)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	contactHandler := handlers.NewContactHandler(contactRepo, consentRepo, blocklistRepo, settingRepo, geo, mail, cfg.Mail.NotifyTo, contactService, accessLogWriter)
 	settingHandler := handlers.NewSettingHandler(settingRepo)
+	consentHandler := handlers.NewConsentHandler(consentRepo)
+	titleVariantHandler := handlers.NewTitleVariantHandler(titleVariantRepo, contentPostRepo)
+	editorHandler := handlers.NewEditorHandler(suggestionRepo)
+	cacheHintHandler := handlers.NewCacheHintHandler(cacheHintRepo)
+	reviewHandler := handlers.NewReviewHandler(repository.NewReviewRepository(db), contentPostRepo, teamRepo, cfg.Jobs.StaleContentDays)
+	approvalHandler := handlers.NewApprovalHandler(approvalRepo, contentPostRepo, userRepo, notifier)
+	annotationHandler := handlers.NewAnnotationHandler(annotationRepo, contentPostRepo, teamRepo, userRepo, mail, cfg.Mail.PublicURL)
+	blocklistHandler := handlers.NewBlocklistHandler(blocklistRepo)
+	emailHandler := handlers.NewEmailHandler(emailRepo, mail)
+	emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateRepo)
+	notificationHandler := handlers.NewNotificationHandler(notifier)
+	socialHandler := handlers.NewSocialHandler(socialShareRepo, contentPostRepo, social.New(settingRepo))
+	promotionHandler := handlers.NewPromotionHandler(cfg.Promote)
+	adminHandler := handlers.NewAdminHandler(apiUsageRepo, locker, recovery)
+	accessLogHandler := handlers.NewAccessLogHandler(accessLogRepo)
+	flaggedIPHandler := handlers.NewFlaggedIPHandler(flaggedIPRepo, detector)
+	deliveryTokenHandler := handlers.NewDeliveryTokenHandler(deliveryTokenRepo, contentTypeRepo)
+	replicationHandler := handlers.NewReplicationHandler(replicationRepo, failover, cfg.Replication.MaxLag)
+	themeHandler := handlers.NewThemeHandler(themeRepo, themeService, site)
+	siteFilesHandler := handlers.NewSiteFilesHandler(settingRepo, cfg.IsProduction())
+	siteIconHandler := handlers.NewSiteIconHandler(settingRepo, mediaRepo)
+	urls := permalink.New(settingRepo, cfg.Mail.PublicURL, cfg.Web.PageType)
+	postURLHandler := handlers.NewPostURLHandler(contentPostRepo, urls)
+	structuredDataHandler := handlers.NewStructuredDataHandler(contentPostRepo, settingRepo, cacheHintRepo, urls, cfg.Mail.PublicURL)
+	renditionHandler := handlers.NewRenditionHandler(contentPostRepo, cacheHintRepo)
+	deliverySerializer := delivery.NewSerializer(cfg.Replication.PrimaryCDNURL, failover)
+	deliveryHandler := handlers.NewDeliveryHandler(contentPostRepo, cacheHintRepo, deliverySerializer)
+	eventHandler := handlers.NewEventHandler(contentPostRepo, deliverySerializer, urls)
+	pollHandler := handlers.NewPollHandler(pollRepo, contentPostRepo)
+	subscriberHandler := handlers.NewSubscriberHandler(subscriberRepo, cfg.Mail.DefaultLocale, accessLogWriter)
+	campaignHandler := handlers.NewCampaignHandler(campaignRepo, contentPostRepo)
+	exportHandler := handlers.NewExportHandler(exportRepo, cfg.Export.AnonymizationKey)
+	analyticsHandler := handlers.NewAnalyticsHandler(trafficRepo)
+	dashboardHandler := handlers.NewDashboardHandler(repository.NewDashboardRepository(db), cfg.Jobs.DashboardRefreshInterval)
+	reportHandler := handlers.NewReportHandler(duplicateRepo, referenceRepo)
+	digestHandler := handlers.NewDigestHandler(digestRepo, userRepo)
+	configSyncHandler := handlers.NewConfigSyncHandler(contentTypeRepo, settingRepo)
+	aiHandler := handlers.NewAIHandler(assistant, contentPostRepo, tagRepo)
+	translationHandler := handlers.NewTranslationHandler(repository.NewTranslationRepository(db), contentPostRepo, assistant, cfg.Locale.Supported)
+	// AI suggestions and translation prefills share one rate limit
+	aiLimiter := middleware.NewRateLimiter(cfg.AI.RateLimit, time.Minute)
+	proofreadHandler := handlers.NewProofreadHandler(checker, contentPostRepo, cfg.Proofread.Language)
+	healthHandler := handlers.NewHealthHandler(dbMonitor, migrate.New(db))
+
+	events.Subscribe(bus, contentPostHandler.InvalidateFacets)
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		response.OK(w, map[string]string{"status": "healthy"})
 	})
+	r.Get("/health/ready", healthHandler.Ready)
+	r.Get("/health/migrations", healthHandler.Migrations)
+
+	// Well-known text files
+	r.Get("/robots.txt", siteFilesHandler.RobotsTxt)
+	r.Get("/.well-known/security.txt", siteFilesHandler.SecurityTxt)
+	r.Get("/site.webmanifest", siteIconHandler.Manifest)
+	r.Get("/favicon.ico", siteIconHandler.Favicon)
+	r.Get("/apple-touch-icon.png", siteIconHandler.AppleTouchIcon)
+	r.Get("/icons/icon-{size}.png", siteIconHandler.Icon)
+
+	// Public delivery API. Public-only instances read from a replica and
+	// don't take poll votes.
+	publicAPI := func(r chi.Router) {
+		// Signed media URLs carry their own credentials
+		r.Get("/media/{id}", mediaHandler.SignedDownload)
+
+		// Delivery API, scoped by delivery tokens
+		r.Group(func(r chi.Router) {
+			r.Use(deliveryAuth.Middleware)
+			r.Get("/posts/{slug}", deliveryHandler.Post)
+			r.Get("/posts/{slug}/jsonld", structuredDataHandler.PostJSONLD)
+			r.Get("/posts/{slug}/rendition", renditionHandler.PostRendition)
+			r.Get("/events/upcoming", eventHandler.Upcoming)
+			r.Get("/events.ics", eventHandler.Feed)
+			r.Get("/polls/{id}", pollHandler.Get)
+			if !cfg.IsPublicOnly() {
+				r.With(detector.Middleware).Post("/polls/{id}/vote", pollHandler.Vote)
+			}
+			r.Get("/posts/{slug}/polls", pollHandler.PostPolls)
+		})
+	}
+
+	// Embedded admin UI
+	if !cfg.IsPublicOnly() {
+		r.Handle("/admin", http.RedirectHandler("/admin/", http.StatusMovedPermanently))
+		r.Mount("/admin/", http.StripPrefix("/admin", admin.Handler()))
+	}
+
+	// SCIM provisioning for identity providers
+	if cfg.SCIM.Token != "" && !cfg.IsPublicOnly() {
+		scimHandler, err := handlers.NewSCIMHandler(userRepo, accessLogRepo, cfg.SCIM.Token, cfg.SCIM.GroupRoles, cfg.Mail.PublicURL)
+		if err != nil {
+			log.Fatalf("Invalid SCIM_GROUP_ROLES: %v", err)
+		}
+		r.Route("/scim/v2", func(r chi.Router) {
+			r.Use(scimHandler.Authenticate)
+			r.Get("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+			r.Get("/Users", scimHandler.ListUsers)
+			r.Post("/Users", scimHandler.CreateUser)
+			r.Get("/Users/{id}", scimHandler.GetUser)
+			r.Put("/Users/{id}", scimHandler.ReplaceUser)
+			r.Patch("/Users/{id}", scimHandler.PatchUser)
+			r.Delete("/Users/{id}", scimHandler.DeleteUser)
+		})
+	}
 
 	// API v1 routes
-	r.Route("/api/v1", func(r chi.Router) {
+	fullAPI := func(r chi.Router) {
+		// Password login, and the user of requests bearing its JWTs
+		if authenticator != nil {
+			r.Use(authenticator.Middleware)
+		}
+		// Hide fields the caller's role may not see, in every response
+		r.Use(redaction.Middleware(redaction.Rules, anonymousRole))
+
+		// Every route needs a user or an API key, except login, the public
+		// forms and beacons, and the delivery API under /public, which
+		// checks delivery tokens. Editorial and admin areas also need a
+		// role. Without AUTH_JWT_SECRET nobody can authenticate, so only the
+		// public routes are available.
+		editor := auth.RequireRole(models.RoleEditor)
+		admin := auth.RequireRole(models.RoleAdmin)
+		if authenticator != nil {
+			r.Route("/auth", func(r chi.Router) {
+				limiter := middleware.NewIPRateLimiter(cfg.Auth.LoginRateLimit, time.Minute)
+				r.With(detector.Middleware, limiter.Middleware).Post("/login", authenticator.Login)
+				r.With(limiter.Middleware).Post("/refresh", authenticator.Refresh)
+				r.Group(func(r chi.Router) {
+					r.Use(auth.RequireSession)
+					r.Post("/logout", authenticator.Logout)
+					r.Get("/sessions", authenticator.ListSessions)
+					r.Delete("/sessions", authenticator.RevokeOtherSessions)
+					r.Delete("/sessions/{id}", authenticator.RevokeSession)
+				})
+			})
+		}
+
+		// API keys for machine clients, managed by logged-in users
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(auth.RequireSession)
+			r.Get("/", apiKeyHandler.List)
+			r.Post("/", apiKeyHandler.Create)
+			r.Delete("/{id}", apiKeyHandler.Delete)
+		})
+
 		// Content Types
 		r.Route("/content-types", func(r chi.Router) {
+			r.Use(auth.RequireUser)
 			r.Get("/", contentTypeHandler.List)
-			r.Post("/", contentTypeHandler.Create)
+			r.With(admin).Post("/", contentTypeHandler.Create)
 			r.Get("/slug/{slug}", contentTypeHandler.GetBySlug)
 			r.Get("/{id}", contentTypeHandler.Get)
-			r.Put("/{id}", contentTypeHandler.Update)
-			r.Delete("/{id}", contentTypeHandler.Delete)
+			r.With(admin).Put("/{id}", contentTypeHandler.Update)
+			r.With(admin).Delete("/{id}", contentTypeHandler.Delete)
+			r.Get("/{id}/approval-chain", approvalHandler.GetChain)
+			r.With(admin).Put("/{id}/approval-chain", approvalHandler.SetChain)
+			r.With(admin).Delete("/{id}/approval-chain", approvalHandler.DeleteChain)
+			r.Get("/{id}/review-policy", reviewHandler.GetPolicy)
+			r.With(admin).Put("/{id}/review-policy", reviewHandler.SetPolicy)
+			r.With(admin).Delete("/{id}/review-policy", reviewHandler.DeletePolicy)
+			r.Get("/{id}/cache-hints", cacheHintHandler.GetContentType)
+			r.With(editor).Put("/{id}/cache-hints", cacheHintHandler.SetContentType)
+			r.With(editor).Delete("/{id}/cache-hints", cacheHintHandler.DeleteContentType)
 		})
 
 		// Posts
 		r.Route("/posts", func(r chi.Router) {
-			r.Get("/", contentPostHandler.List)
-			r.Post("/", contentPostHandler.Create)
-			r.Get("/slug/{slug}", contentPostHandler.GetBySlug)
-			r.Get("/{id}", contentPostHandler.Get)
-			r.Put("/{id}", contentPostHandler.Update)
-			r.Delete("/{id}", contentPostHandler.Delete)
-			// Post media management
-			r.Post("/{id}/media", contentPostHandler.AttachMedia)
-			r.Delete("/{id}/media/{mediaId}", contentPostHandler.DetachMedia)
+			// Title A/B test assignment and beacons, used by anonymous readers
+			r.Get("/{id}/title-variants/assign", titleVariantHandler.Assign)
+			r.Post("/{id}/title-variants/{variantId}/impression", titleVariantHandler.RecordImpression)
+			r.Post("/{id}/title-variants/{variantId}/click", titleVariantHandler.RecordClick)
+			r.Group(func(r chi.Router) {
+				r.Use(auth.RequireUser)
+				r.Get("/", contentPostHandler.List)
+				r.Post("/", contentPostHandler.Create)
+				r.Get("/export", contentPostHandler.Export)
+				r.Get("/aggregate", contentPostHandler.Aggregate)
+				r.Get("/slug/{slug}", contentPostHandler.GetBySlug)
+				r.Get("/{id}", contentPostHandler.Get)
+				r.Put("/{id}", contentPostHandler.Update)
+				r.Delete("/{id}", contentPostHandler.Delete)
+				r.Get("/{id}/urls", postURLHandler.Get)
+				r.Post("/{id}/promote", contentPostHandler.Promote)
+				r.Post("/{id}/proofread", proofreadHandler.Proofread)
+				// Post media management
+				r.Post("/{id}/media", contentPostHandler.AttachMedia)
+				r.Delete("/{id}/media/{mediaId}", contentPostHandler.DetachMedia)
+				// Title A/B testing
+				r.Get("/{id}/title-variants", titleVariantHandler.List)
+				r.Post("/{id}/title-variants", titleVariantHandler.Create)
+				r.Get("/{id}/title-variants/results", titleVariantHandler.Results)
+				r.Delete("/{id}/title-variants/{variantId}", titleVariantHandler.Delete)
+				// Editorial annotations
+				r.Get("/{id}/annotations", annotationHandler.List)
+				r.Post("/{id}/annotations", annotationHandler.Create)
+				r.Put("/{id}/annotations/{annotationId}", annotationHandler.Update)
+				r.Delete("/{id}/annotations/{annotationId}", annotationHandler.Delete)
+				r.Post("/{id}/annotations/{annotationId}/resolve", annotationHandler.Resolve)
+				r.Post("/{id}/annotations/{annotationId}/reopen", annotationHandler.Reopen)
+				// Approval requests
+				r.Get("/{id}/approvals", approvalHandler.ListForPost)
+				r.Post("/{id}/approvals", approvalHandler.Submit)
+
+				// Social sharing
+				r.Get("/{id}/social-sharing", socialHandler.GetPostSharing)
+				r.Put("/{id}/social-sharing", socialHandler.UpdatePostSharing)
+
+				// Caching hints for the public endpoints
+				r.Get("/{id}/cache-hints", cacheHintHandler.GetPost)
+				r.With(editor).Put("/{id}/cache-hints", cacheHintHandler.SetPost)
+				r.With(editor).Delete("/{id}/cache-hints", cacheHintHandler.DeletePost)
+
+				// Translations into the supported locales
+				r.Get("/{id}/translations", translationHandler.List)
+				r.Get("/{id}/translations/{locale}", translationHandler.Get)
+				r.Put("/{id}/translations/{locale}", translationHandler.Save)
+				r.Delete("/{id}/translations/{locale}", translationHandler.Delete)
+				r.With(aiLimiter.Middleware).Post("/{id}/translations/{locale}/prefill", translationHandler.Prefill)
+			})
 		})
 
+		// Editor autocompletion
+		r.With(auth.RequireUser).Get("/editor/suggest", editorHandler.Suggest)
+
 		// Media
 		r.Route("/media", func(r chi.Router) {
+			r.Use(auth.RequireUser)
 			r.Get("/", mediaHandler.List)
 			r.Post("/", mediaHandler.Create)
+			r.Post("/upload", mediaHandler.Upload)
+			r.Get("/export", mediaHandler.Export)
 			r.Get("/{id}", mediaHandler.Get)
 			r.Put("/{id}", mediaHandler.Update)
 			r.Delete("/{id}", mediaHandler.Delete)
+			r.Get("/{id}/download", mediaHandler.Download)
+			r.Post("/{id}/signed-url", mediaHandler.SignURL)
 		})
 
 		// Tags
 		r.Route("/tags", func(r chi.Router) {
+			r.Use(auth.RequireUser)
 			r.Get("/", tagHandler.List)
 			r.Post("/", tagHandler.Create)
+			r.Get("/export", tagHandler.Export)
 			r.Get("/slug/{slug}", tagHandler.GetBySlug)
 			r.Get("/{id}", tagHandler.Get)
 			r.Put("/{id}", tagHandler.Update)
 			r.Delete("/{id}", tagHandler.Delete)
 		})
 
+		// Categories
+		r.Route("/categories", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Get("/", categoryHandler.List)
+			r.Post("/", categoryHandler.Create)
+			r.Get("/tree", categoryHandler.Tree)
+			r.Get("/slug/{slug}", categoryHandler.GetBySlug)
+			r.Get("/{id}", categoryHandler.Get)
+			r.Get("/{id}/tree", categoryHandler.Subtree)
+			r.Put("/{id}", categoryHandler.Update)
+			r.Delete("/{id}", categoryHandler.Delete)
+		})
+
+		// Post templates
+		r.Route("/post-templates", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Get("/", postTemplateHandler.List)
+			r.Post("/", postTemplateHandler.Create)
+			r.Get("/{id}", postTemplateHandler.Get)
+			r.Put("/{id}", postTemplateHandler.Update)
+			r.Delete("/{id}", postTemplateHandler.Delete)
+		})
+
+		// Teams and their members
+		r.Route("/teams", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Get("/", teamHandler.List)
+			r.With(admin).Post("/", teamHandler.Create)
+			r.Get("/{id}", teamHandler.Get)
+			r.With(admin).Put("/{id}", teamHandler.Update)
+			r.With(admin).Delete("/{id}", teamHandler.Delete)
+			r.Get("/{id}/members", teamHandler.ListMembers)
+			r.With(admin).Put("/{id}/members/{userId}", teamHandler.SetMember)
+			r.With(admin).Delete("/{id}/members/{userId}", teamHandler.RemoveMember)
+		})
+
 		// Contact Submissions
 		r.Route("/contacts", func(r chi.Router) {
-			r.Get("/", contactHandler.List)
-			r.Post("/", contactHandler.Create)
-			r.Get("/unread-count", contactHandler.GetUnreadCount)
-			r.Get("/{id}", contactHandler.Get)
-			r.Put("/{id}", contactHandler.Update)
-			r.Delete("/{id}", contactHandler.Delete)
+			// Public contact form
+			r.With(detector.Middleware).Post("/", contactHandler.Create)
+			r.Get("/fields", contactHandler.Fields)
+			r.Group(func(r chi.Router) {
+				r.Use(editor)
+				r.Get("/", contactHandler.List)
+				r.Get("/export", contactHandler.Export)
+				r.Get("/unread-count", contactHandler.GetUnreadCount)
+				r.Get("/by-country", contactHandler.CountByCountry)
+				r.Get("/{id}", contactHandler.Get)
+				r.Put("/{id}", contactHandler.Update)
+				r.Delete("/{id}", contactHandler.Delete)
+			})
 		})
 
 		// Settings
 		r.Route("/settings", func(r chi.Router) {
+			r.Use(admin)
 			r.Get("/", settingHandler.List)
 			r.Post("/", settingHandler.Create)
 			r.Post("/upsert", settingHandler.Upsert)
 			r.Post("/bulk", settingHandler.GetMultiple)
+			r.Get("/export", settingHandler.Export)
+			r.Post("/import", settingHandler.Import)
 			r.Get("/{key}", settingHandler.Get)
 			r.Put("/{key}", settingHandler.Update)
 			r.Delete("/{key}", settingHandler.Delete)
 		})
-	})
+
+		// Polls
+		r.Route("/polls", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Get("/", pollHandler.List)
+			r.Post("/", pollHandler.Create)
+			r.Get("/{id}", pollHandler.Get)
+			r.Put("/{id}", pollHandler.Update)
+			r.Delete("/{id}", pollHandler.Delete)
+		})
+
+		// Consent Versions
+		r.Route("/consent-versions", func(r chi.Router) {
+			// Shown with the public contact form
+			r.Get("/current", consentHandler.GetCurrent)
+			r.Group(func(r chi.Router) {
+				r.Use(auth.RequireUser)
+				r.Get("/", consentHandler.List)
+				r.With(admin).Post("/", consentHandler.Create)
+				r.Get("/{id}", consentHandler.Get)
+			})
+		})
+
+		// Blocklist
+		r.Route("/blocklist", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/", blocklistHandler.List)
+			r.Post("/", blocklistHandler.Create)
+			r.Get("/{id}", blocklistHandler.Get)
+			r.Put("/{id}", blocklistHandler.Update)
+			r.Delete("/{id}", blocklistHandler.Delete)
+		})
+
+		// Email Queue
+		r.Route("/emails", func(r chi.Router) {
+			// Loaded by the recipient's mail client
+			r.Get("/{id}/open.gif", emailHandler.TrackOpen)
+			r.Group(func(r chi.Router) {
+				r.Use(editor)
+				r.Get("/", emailHandler.List)
+				r.Post("/", emailHandler.Send)
+				r.Get("/{id}", emailHandler.Get)
+				r.Post("/{id}/retry", emailHandler.Retry)
+			})
+		})
+
+		// Email Templates
+		r.Route("/email-templates", func(r chi.Router) {
+			r.Use(editor)
+			r.Get("/", emailTemplateHandler.List)
+			r.Post("/", emailTemplateHandler.Create)
+			r.Get("/{id}", emailTemplateHandler.Get)
+			r.Put("/{id}", emailTemplateHandler.Update)
+			r.Delete("/{id}", emailTemplateHandler.Delete)
+		})
+
+		// Subscribers
+		r.Route("/subscribers", func(r chi.Router) {
+			// Public signup form and the unsubscribe link in campaign emails
+			r.With(detector.Middleware).Post("/", subscriberHandler.Create)
+			r.Get("/unsubscribe", subscriberHandler.Unsubscribe)
+			r.Group(func(r chi.Router) {
+				r.Use(editor)
+				r.Get("/", subscriberHandler.List)
+				r.Delete("/{id}", subscriberHandler.Delete)
+			})
+		})
+
+		// Email Campaigns
+		r.Route("/campaigns", func(r chi.Router) {
+			r.Use(editor)
+			r.Get("/", campaignHandler.List)
+			r.Post("/", campaignHandler.Create)
+			r.Get("/{id}", campaignHandler.Get)
+			r.Post("/{id}/cancel", campaignHandler.Cancel)
+		})
+
+		// Users
+		r.Route("/users", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Group(func(r chi.Router) {
+				r.Use(admin)
+				r.Get("/", userHandler.List)
+				r.Post("/", userHandler.Create)
+				r.Get("/{id}", userHandler.Get)
+				r.Put("/{id}", userHandler.Update)
+				r.Delete("/{id}", userHandler.Delete)
+				r.Post("/{id}/deactivate", userHandler.Deactivate)
+				r.Get("/{id}/approval-roles", approvalHandler.GetUserRoles)
+				r.Put("/{id}/approval-roles", approvalHandler.SetUserRoles)
+			})
+
+			// Editor digest subscriptions
+			r.Get("/{id}/digest", digestHandler.GetPreference)
+			r.Put("/{id}/digest", digestHandler.UpdatePreference)
+		})
+
+		// Approval queue
+		r.Route("/approvals", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Get("/", approvalHandler.Queue)
+			r.Get("/{id}", approvalHandler.Get)
+			r.Post("/{id}/approve", approvalHandler.Approve)
+			r.Post("/{id}/reject", approvalHandler.Reject)
+			r.Post("/{id}/cancel", approvalHandler.Cancel)
+		})
+
+		// Chat Notifications
+		r.Route("/notifications", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/config", notificationHandler.GetConfig)
+			r.Post("/test", notificationHandler.Test)
+		})
+
+		// Social Sharing
+		r.Route("/social", func(r chi.Router) {
+			r.Use(editor)
+			r.Get("/config", socialHandler.GetConfig)
+			r.Get("/shares", socialHandler.ListShares)
+		})
+
+		// Admin
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/ui-schema", adminHandler.UISchema)
+			r.Get("/api-usage", adminHandler.APIUsage)
+			r.Get("/access-log", accessLogHandler.List)
+			r.Get("/flagged-ips", flaggedIPHandler.List)
+			r.Put("/flagged-ips/{ip}", flaggedIPHandler.Set)
+			r.Delete("/flagged-ips/{ip}", flaggedIPHandler.Delete)
+			r.Get("/delivery-tokens", deliveryTokenHandler.List)
+			r.Post("/delivery-tokens", deliveryTokenHandler.Create)
+			r.Get("/delivery-tokens/{id}", deliveryTokenHandler.Get)
+			r.Put("/delivery-tokens/{id}", deliveryTokenHandler.Update)
+			r.Delete("/delivery-tokens/{id}", deliveryTokenHandler.Delete)
+			r.Get("/replication", replicationHandler.Stats)
+			r.Post("/replication/backfill", replicationHandler.Backfill)
+			r.Post("/replication/retry", replicationHandler.Retry)
+			r.Get("/leadership", adminHandler.Leadership)
+			r.Get("/panics", adminHandler.Panics)
+		})
+
+		// Reader analytics
+		r.With(editor).Get("/analytics/traffic-sources", analyticsHandler.TrafficSources)
+
+		// Dashboard aggregates
+		r.With(editor).Get("/dashboard", dashboardHandler.Get)
+		r.With(editor).Post("/dashboard/refresh", dashboardHandler.Refresh)
+
+		// Editorial reports
+		r.Route("/reports", func(r chi.Router) {
+			r.Use(editor)
+			r.Get("/duplicates", reportHandler.Duplicates)
+			r.Get("/dangling-references", reportHandler.DanglingReferences)
+			r.Get("/stale-content", reviewHandler.StaleContent)
+			r.Post("/stale-content/{id}/acknowledge", reviewHandler.Acknowledge)
+			r.Get("/digest", digestHandler.Preview)
+		})
+
+		// AI assistance
+		r.Route("/ai", func(r chi.Router) {
+			r.Use(auth.RequireUser)
+			r.Use(aiLimiter.Middleware)
+			r.Post("/summarize", aiHandler.Summarize)
+			r.Post("/suggest-tags", aiHandler.SuggestTags)
+			r.Post("/translate", aiHandler.Translate)
+		})
+
+		// Configuration as code
+		r.Route("/config", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/", configSyncHandler.Export)
+			r.Post("/diff", configSyncHandler.Diff)
+			r.Post("/apply", configSyncHandler.Apply)
+		})
+
+		// Data exports for analytics pipelines
+		r.Route("/export", func(r chi.Router) {
+			r.Use(editor)
+			r.Get("/interactions", exportHandler.Interactions)
+			r.Get("/content-features", exportHandler.ContentFeatures)
+			r.Get("/outline", exportHandler.Outline)
+		})
+
+		// Themes
+		r.Route("/themes", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/", themeHandler.List)
+			r.Post("/", themeHandler.Create)
+			r.Post("/deactivate", themeHandler.Deactivate)
+			r.Get("/{id}", themeHandler.Get)
+			r.Delete("/{id}", themeHandler.Delete)
+			r.Post("/{id}/activate", themeHandler.Activate)
+			r.Get("/{id}/files/*", themeHandler.GetFile)
+			r.Put("/{id}/files/*", themeHandler.PutFile)
+			r.Delete("/{id}/files/*", themeHandler.DeleteFile)
+			r.Get("/{id}/preview/*", themeHandler.Preview)
+		})
+
+		// Public
+		r.Route("/public", publicAPI)
+
+		// Content Promotion
+		r.Route("/promotion", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/diff", promotionHandler.Diff)
+			r.Post("/push", promotionHandler.Push)
+		})
+	}
+	if cfg.IsPublicOnly() {
+		r.Route("/api/v1/public", publicAPI)
+	} else {
+		r.Route("/api/v1", fullAPI)
+	}
+
+	// Server-rendered public site
+	if site != nil {
+		site.Routes(r)
+	}
 
 	// 404 handler
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		response.NotFound(w, "Endpoint not found")
+		if site != nil && !strings.HasPrefix(r.URL.Path, "/api/") {
+			site.NotFound(w, r)
+			return
+		}
+		errorPageHandler.NotFound(w, r)
 	})
 
 	// 405 handler
@@ -127,3 +778,74 @@ func New(db *pgxpool.Pool) *chi.Mux {
 
 	return r
 }
+
+// requestPriority assigns load shedding priorities per route group. Public
+// reads (site pages, feeds, /api/v1/public) are shed first and other API
+// reads next; writes, the admin UI and health checks are never shed.
+func requestPriority(r *http.Request) middleware.Priority {
+	path := r.URL.Path
+	switch {
+	case path == "/health" || strings.HasPrefix(path, "/health/") || path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		return middleware.PriorityHigh
+	case r.Method != http.MethodGet && r.Method != http.MethodHead:
+		return middleware.PriorityHigh
+	case strings.HasPrefix(path, "/api/v1/public/"):
+		return middleware.PriorityLow
+	case strings.HasPrefix(path, "/api/"):
+		return middleware.PriorityNormal
+	default:
+		return middleware.PriorityLow
+	}
+}
+
+// requestTimeout assigns handler deadlines per route group. Analytics reports
+// and AI suggestions get the longer report timeout; streaming exports and
+// media uploads and downloads have no deadline.
+func requestTimeout(cfg config.TimeoutConfig) func(*http.Request) time.Duration {
+	return func(r *http.Request) time.Duration {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/export") || strings.HasPrefix(path, "/api/v1/export/"):
+			return 0
+		case strings.HasSuffix(path, "/download") || strings.HasPrefix(path, "/api/v1/public/media/"),
+			path == "/api/v1/media/upload":
+			return 0
+		case path == "/api/v1/posts/aggregate",
+			path == "/api/v1/contacts/by-country",
+			path == "/api/v1/admin/api-usage",
+			path == "/api/v1/admin/access-log",
+			path == "/api/v1/dashboard/refresh",
+			strings.HasPrefix(path, "/api/v1/reports/"),
+			strings.HasPrefix(path, "/api/v1/ai/"),
+			strings.HasSuffix(path, "/prefill"),
+			strings.HasSuffix(path, "/title-variants/results"):
+			return cfg.Report
+		default:
+			return cfg.Request
+		}
+	}
+}
+
+// panicStackLines is how much of a panic's stack trace goes into alerts; the
+// full trace is in the log
+const panicStackLines = 20
+
+// panicAlert sends recovered panics to the server.panic notification route
+func panicAlert(notifier *notify.Notifier) func(*http.Request, *middleware.PanicReport) {
+	return func(r *http.Request, report *middleware.PanicReport) {
+		stack := strings.Split(report.Stack, "\n")
+		if len(stack) > panicStackLines {
+			stack = append(stack[:panicStackLines], "...")
+		}
+		notifier.Notify(r.Context(), notify.Notification{
+			Event: notify.EventServerPanic,
+			Title: "Server panic",
+			Text:  report.Value,
+			Fields: []notify.Field{
+				{Label: "Request", Value: report.Method + " " + report.Path},
+				{Label: "Request ID", Value: report.RequestID},
+				{Label: "Stack", Value: strings.Join(stack, "\n")},
+			},
+		})
+	}
+}
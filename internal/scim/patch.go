@@ -0,0 +1,152 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Apply carries out PATCH operations on u. It reports whether roles or
+// groups were touched, which is when the user's CMS role is re-resolved.
+// Attributes the CMS does not store, such as emails or title, are ignored.
+func (u *User) Apply(ops []PatchOperation) (bool, error) {
+	touched := make(map[string]bool)
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path == "" {
+				// Without a path the value holds the attributes to set
+				var attrs map[string]json.RawMessage
+				if err := json.Unmarshal(op.Value, &attrs); err != nil {
+					return false, invalidValue("value must be an object when path is omitted")
+				}
+				for name, value := range attrs {
+					if err := u.set(name, value); err != nil {
+						return false, err
+					}
+					touched[strings.ToLower(name)] = true
+				}
+				continue
+			}
+			if err := u.set(op.Path, op.Value); err != nil {
+				return false, err
+			}
+		case "remove":
+			if op.Path == "" {
+				return false, NewError(http.StatusBadRequest, "noTarget", "remove needs a path")
+			}
+			u.remove(op.Path)
+		default:
+			return false, invalidValue("unsupported operation " + op.Op)
+		}
+		touched[strings.ToLower(op.Path)] = true
+	}
+
+	// The stored full name fills both displayName and name.formatted, so
+	// patched name parts must win over the copies derived from it
+	nameTouched := touched["name"] || touched["name.formatted"] || touched["name.givenname"] || touched["name.familyname"]
+	if nameTouched && !touched["displayname"] {
+		u.DisplayName = ""
+	}
+	if (touched["name.givenname"] || touched["name.familyname"]) && !touched["name"] && !touched["name.formatted"] {
+		u.name().Formatted = ""
+	}
+
+	return touched["roles"] || touched["groups"], nil
+}
+
+// set sets one attribute
+func (u *User) set(path string, value json.RawMessage) error {
+	switch strings.ToLower(path) {
+	case "username":
+		return decodeString(value, &u.UserName, path)
+	case "displayname":
+		return decodeString(value, &u.DisplayName, path)
+	case "name":
+		u.Name = &Name{}
+		if err := json.Unmarshal(value, u.Name); err != nil {
+			return invalidValue("name must be an object")
+		}
+	case "name.formatted":
+		return decodeString(value, &u.name().Formatted, path)
+	case "name.givenname":
+		return decodeString(value, &u.name().GivenName, path)
+	case "name.familyname":
+		return decodeString(value, &u.name().FamilyName, path)
+	case "active":
+		active, err := decodeBool(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+	case "roles":
+		return decodeMultiValue(value, &u.Roles, path)
+	case "groups":
+		return decodeMultiValue(value, &u.Groups, path)
+	}
+	return nil
+}
+
+// remove clears one attribute
+func (u *User) remove(path string) {
+	switch strings.ToLower(path) {
+	case "displayname":
+		u.DisplayName = ""
+	case "name":
+		u.Name = nil
+	case "roles":
+		u.Roles = []MultiValue{}
+	case "groups":
+		u.Groups = []MultiValue{}
+	}
+}
+
+func (u *User) name() *Name {
+	if u.Name == nil {
+		u.Name = &Name{}
+	}
+	return u.Name
+}
+
+func decodeString(value json.RawMessage, dst *string, path string) error {
+	if err := json.Unmarshal(value, dst); err != nil {
+		return invalidValue(path + " must be a string")
+	}
+	return nil
+}
+
+// decodeBool accepts JSON booleans and, as some identity providers send
+// them, the strings "true" and "false" in any case
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, invalidValue("active must be a boolean")
+}
+
+// decodeMultiValue accepts a list of values or a single value
+func decodeMultiValue(value json.RawMessage, dst *[]MultiValue, path string) error {
+	var list []MultiValue
+	if err := json.Unmarshal(value, &list); err == nil {
+		*dst = list
+		return nil
+	}
+	var single MultiValue
+	if err := json.Unmarshal(value, &single); err != nil {
+		return invalidValue(path + " must be a list of values")
+	}
+	*dst = []MultiValue{single}
+	return nil
+}
+
+func invalidValue(detail string) *Error {
+	return NewError(http.StatusBadRequest, "invalidValue", detail)
+}
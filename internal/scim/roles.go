@@ -0,0 +1,46 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// RoleMap maps identity provider role and group names (lowercased) to CMS
+// roles
+type RoleMap map[string]models.Role
+
+// ParseRoleMap reads group=role entries, where role is user, editor or admin
+func ParseRoleMap(entries []string) (RoleMap, error) {
+	m := make(RoleMap, len(entries))
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		group, name, ok := strings.Cut(entry, "=")
+		group = strings.ToLower(strings.TrimSpace(group))
+		role, known := models.ParseEnum[models.Role](name)
+		if !ok || group == "" || !known || role.String() == "unknown" {
+			return nil, fmt.Errorf("invalid group mapping %q, expected group=user|editor|admin", entry)
+		}
+		m[group] = role
+	}
+	return m, nil
+}
+
+// Resolve returns the highest role mapped from u's roles and groups, matched
+// by value or display name; users matching none get the user role
+func (m RoleMap) Resolve(u *User) models.Role {
+	role := models.RoleUser
+	for _, values := range [][]MultiValue{u.Roles, u.Groups} {
+		for _, v := range values {
+			for _, key := range []string{v.Value, v.Display} {
+				if mapped, ok := m[strings.ToLower(key)]; ok && mapped > role {
+					role = mapped
+				}
+			}
+		}
+	}
+	return role
+}
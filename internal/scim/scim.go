@@ -0,0 +1,204 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7643, RFC 7644) that
+// identity providers use to provision CMS users: the core User resource,
+// PATCH operations, the userName eq filter and the protocol's error format.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// SCIM schema URNs
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// MaxResults caps the page size of list responses
+const MaxResults = 100
+
+// User is the SCIM core User resource. userName is the user's email
+// address, which is also their CMS login.
+type User struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *Name        `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []MultiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Roles       []MultiValue `json:"roles,omitempty"`
+	Groups      []MultiValue `json:"groups,omitempty"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// Name is a user's name components
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValue is an entry of a multi-valued attribute such as emails or roles
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta is a resource's metadata
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+// ListResponse is a page of query results; StartIndex is 1-based
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// PatchRequest is a PATCH request body
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is one add, replace or remove operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is a SCIM error response. It is also returned as a Go error by this
+// package, so handlers can write it unchanged.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// NewError returns an error with the given HTTP status, SCIM error type
+// (may be empty) and detail
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   fmt.Sprint(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// WriteJSON writes v as a SCIM response
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes err as a SCIM error response; errors other than *Error
+// become a 500 without details
+func WriteError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = NewError(http.StatusInternalServerError, "", "Internal server error")
+	}
+	var status int
+	fmt.Sscan(e.Status, &status)
+	WriteJSON(w, status, e)
+}
+
+// FromModel returns the SCIM representation of a CMS user. location is the
+// URL of the Users endpoint.
+func FromModel(user *models.User, location string) *User {
+	active := user.IsActive
+	return &User{
+		Schemas:     []string{SchemaUser},
+		ID:          user.ID.String(),
+		UserName:    user.Email,
+		Name:        &Name{Formatted: user.FullName},
+		DisplayName: user.FullName,
+		Emails:      []MultiValue{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     location + "/" + user.ID.String(),
+		},
+	}
+}
+
+// Validate checks the attributes the CMS stores and normalizes userName
+func (u *User) Validate() error {
+	u.UserName = strings.ToLower(strings.TrimSpace(u.UserName))
+	if u.UserName == "" {
+		return NewError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	if _, err := mail.ParseAddress(u.UserName); err != nil {
+		return NewError(http.StatusBadRequest, "invalidValue", "userName must be an email address")
+	}
+	return nil
+}
+
+// FullName picks the name to store: displayName, then the formatted name,
+// then given and family names, then userName
+func (u *User) FullName() string {
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+	if u.Name != nil {
+		if name := strings.TrimSpace(u.Name.Formatted); name != "" {
+			return name
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.UserName
+}
+
+// IsActive reports the active attribute, which defaults to true
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// ParseFilter reads a list filter. Only userName eq "value", the filter
+// identity providers use to look users up, is supported; it returns the
+// lowercased value.
+func ParseFilter(filter string) (string, error) {
+	fields := strings.SplitN(strings.TrimSpace(filter), " ", 3)
+	if len(fields) != 3 || !strings.EqualFold(fields[0], "userName") || !strings.EqualFold(fields[1], "eq") {
+		return "", NewError(http.StatusBadRequest, "invalidFilter", `Only userName eq "value" filters are supported`)
+	}
+	var value string
+	if err := json.Unmarshal([]byte(fields[2]), &value); err != nil {
+		return "", NewError(http.StatusBadRequest, "invalidFilter", "Filter value must be a quoted string")
+	}
+	return strings.ToLower(value), nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ContactService stores contact submissions and publishes ContactReceived
+type ContactService struct {
+	repo *repository.ContactRepository
+	bus  *events.Bus
+}
+
+func NewContactService(repo *repository.ContactRepository, bus *events.Bus) *ContactService {
+	return &ContactService{repo: repo, bus: bus}
+}
+
+// Create stores a contact submission
+func (s *ContactService) Create(ctx context.Context, req *models.CreateContactRequest) (*models.ContactSubmission, error) {
+	contact, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, events.ContactReceived{Contact: contact})
+	return contact, nil
+}
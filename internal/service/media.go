@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// MediaService creates media records and publishes MediaCreated
+type MediaService struct {
+	repo *repository.MediaRepository
+	bus  *events.Bus
+}
+
+func NewMediaService(repo *repository.MediaRepository, bus *events.Bus) *MediaService {
+	return &MediaService{repo: repo, bus: bus}
+}
+
+// Create creates a media record
+func (s *MediaService) Create(ctx context.Context, req *models.CreateMediaRequest) (*models.Media, error) {
+	media, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, events.MediaCreated{Media: media})
+	return media, nil
+}
@@ -0,0 +1,165 @@
+// Package service wraps repository writes that have side effects beyond
+// the database: each publishes the domain events features subscribe to, so
+// handlers and jobs don't need to know about them.
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// ErrApprovalRequired is returned when a post would be published while its
+// content type has an approval chain and its latest approval request isn't
+// approved
+var ErrApprovalRequired = errors.New("post has not been approved for publishing")
+
+// postRepository is the part of repository.ContentPostRepository that
+// PostService writes through
+type postRepository interface {
+	Create(ctx context.Context, req *models.CreatePostRequest) (*models.ContentPost, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ContentPost, error)
+	Update(ctx context.Context, id uuid.UUID, req *models.UpdatePostRequest) (*models.ContentPost, error)
+	PromoteDraft(ctx context.Context, id uuid.UUID) (*models.ContentPost, error)
+	PublishScheduled(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// approvalChecker is the part of repository.ApprovalRepository that tells
+// whether posts may be published
+type approvalChecker interface {
+	HasChain(ctx context.Context, contentTypeID uuid.UUID) (bool, error)
+	CanPublish(ctx context.Context, postID uuid.UUID) (bool, error)
+}
+
+// PostService writes posts and publishes PostPublished when a live post
+// becomes published. Posts of content types with an approval chain are only
+// published once their latest approval request was approved.
+type PostService struct {
+	repo      postRepository
+	approvals approvalChecker
+	bus       *events.Bus
+}
+
+func NewPostService(repo *repository.ContentPostRepository, approvals *repository.ApprovalRepository, bus *events.Bus) *PostService {
+	return &PostService{repo: repo, approvals: approvals, bus: bus}
+}
+
+// Create creates a post. Posts of content types with an approval chain
+// can't be created published, as they have no approval yet.
+func (s *PostService) Create(ctx context.Context, req *models.CreatePostRequest) (*models.ContentPost, error) {
+	if req.Status != nil && *req.Status == models.PostStatusPublished {
+		gated, err := s.approvals.HasChain(ctx, req.ContentTypeID)
+		if err != nil {
+			return nil, err
+		}
+		if gated {
+			return nil, ErrApprovalRequired
+		}
+	}
+
+	post, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.published(ctx, post)
+	return post, nil
+}
+
+// Update updates a post. The post is read first to tell whether the update
+// publishes it, which needs an approval when its content type has a chain.
+func (s *PostService) Update(ctx context.Context, id uuid.UUID, req *models.UpdatePostRequest) (*models.ContentPost, error) {
+	publishes := false
+	if req.Status != nil && *req.Status == models.PostStatusPublished {
+		current, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		publishes = current.Status != models.PostStatusPublished
+	}
+	if publishes {
+		if err := s.checkApproved(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	post, err := s.repo.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if publishes {
+		s.published(ctx, post)
+	}
+	return post, nil
+}
+
+// PromoteDraft replaces a live post with its draft copy, or makes a
+// draft-only post live. A published draft needs an approval of its own when
+// its content type has a chain.
+func (s *PostService) PromoteDraft(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	draft, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status == models.PostStatusPublished {
+		if err := s.checkApproved(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	post, err := s.repo.PromoteDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.published(ctx, post)
+	return post, nil
+}
+
+// PublishScheduled publishes a scheduled draft, reporting false when it was
+// edited, published or deleted since it was listed, or still awaits approval
+func (s *PostService) PublishScheduled(ctx context.Context, id uuid.UUID) (bool, error) {
+	approved, err := s.approvals.CanPublish(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil || !approved {
+		return false, err
+	}
+
+	ok, err := s.repo.PublishScheduled(ctx, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	post, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	s.published(ctx, post)
+	return true, nil
+}
+
+// checkApproved returns ErrApprovalRequired unless the post may be published
+func (s *PostService) checkApproved(ctx context.Context, id uuid.UUID) error {
+	ok, err := s.approvals.CanPublish(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrApprovalRequired
+	}
+	return nil
+}
+
+// published publishes PostPublished when post is a published live post
+func (s *PostService) published(ctx context.Context, post *models.ContentPost) {
+	if post.Status == models.PostStatusPublished && post.Environment == models.EnvironmentLive {
+		s.bus.Publish(ctx, events.PostPublished{Post: post})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/events"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// fakePostRepository keeps posts in memory. Scheduled posts are the drafts
+// in it; PublishScheduled publishes them.
+type fakePostRepository struct {
+	posts map[uuid.UUID]models.ContentPost
+}
+
+func (f *fakePostRepository) Create(ctx context.Context, req *models.CreatePostRequest) (*models.ContentPost, error) {
+	post := models.ContentPost{ID: uuid.New(), ContentTypeID: req.ContentTypeID, Status: models.PostStatusDraft, Environment: models.EnvironmentLive}
+	if req.Status != nil {
+		post.Status = *req.Status
+	}
+	f.posts[post.ID] = post
+	return &post, nil
+}
+
+func (f *fakePostRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &post, nil
+}
+
+func (f *fakePostRepository) Update(ctx context.Context, id uuid.UUID, req *models.UpdatePostRequest) (*models.ContentPost, error) {
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if req.Status != nil {
+		post.Status = *req.Status
+	}
+	f.posts[id] = post
+	return &post, nil
+}
+
+func (f *fakePostRepository) PromoteDraft(ctx context.Context, id uuid.UUID) (*models.ContentPost, error) {
+	post, ok := f.posts[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	post.Environment = models.EnvironmentLive
+	f.posts[id] = post
+	return &post, nil
+}
+
+func (f *fakePostRepository) PublishScheduled(ctx context.Context, id uuid.UUID) (bool, error) {
+	post, ok := f.posts[id]
+	if !ok || post.Status != models.PostStatusDraft {
+		return false, nil
+	}
+	post.Status = models.PostStatusPublished
+	f.posts[id] = post
+	return true, nil
+}
+
+// fakeApprovals gates the content types in chains; approved lists the
+// posts whose latest request was approved
+type fakeApprovals struct {
+	posts    *fakePostRepository
+	chains   map[uuid.UUID]bool
+	approved map[uuid.UUID]bool
+}
+
+func (f *fakeApprovals) HasChain(ctx context.Context, contentTypeID uuid.UUID) (bool, error) {
+	return f.chains[contentTypeID], nil
+}
+
+func (f *fakeApprovals) CanPublish(ctx context.Context, postID uuid.UUID) (bool, error) {
+	post, ok := f.posts.posts[postID]
+	if !ok {
+		return false, repository.ErrNotFound
+	}
+	return !f.chains[post.ContentTypeID] || f.approved[postID], nil
+}
+
+func TestPostServiceRequiresApprovalToPublish(t *testing.T) {
+	gated, open := uuid.New(), uuid.New()
+	posts := &fakePostRepository{posts: make(map[uuid.UUID]models.ContentPost)}
+	approvals := &fakeApprovals{posts: posts, chains: map[uuid.UUID]bool{gated: true}, approved: make(map[uuid.UUID]bool)}
+
+	publishedEvents := 0
+	bus := events.NewBus()
+	events.Subscribe(bus, func(context.Context, events.PostPublished) { publishedEvents++ })
+	s := &PostService{repo: posts, approvals: approvals, bus: bus}
+
+	ctx := context.Background()
+	draft := func(contentTypeID uuid.UUID) uuid.UUID {
+		post, err := s.Create(ctx, &models.CreatePostRequest{ContentTypeID: contentTypeID})
+		if err != nil {
+			t.Fatalf("creating a draft: %v", err)
+		}
+		return post.ID
+	}
+	published := models.PostStatusPublished
+	publish := &models.UpdatePostRequest{Status: &published}
+
+	if _, err := s.Create(ctx, &models.CreatePostRequest{ContentTypeID: gated, Status: &published}); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("creating a published post with a chain: err = %v, want ErrApprovalRequired", err)
+	}
+	if _, err := s.Create(ctx, &models.CreatePostRequest{ContentTypeID: open, Status: &published}); err != nil {
+		t.Errorf("creating a published post without a chain: %v", err)
+	}
+
+	id := draft(gated)
+	if _, err := s.Update(ctx, id, publish); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("publishing an unapproved post: err = %v, want ErrApprovalRequired", err)
+	}
+	if ok, err := s.PublishScheduled(ctx, id); ok || err != nil {
+		t.Errorf("scheduled publishing of an unapproved post = %v, %v; want false, nil", ok, err)
+	}
+	if _, err := s.PromoteDraft(ctx, id); err != nil {
+		t.Errorf("promoting an unpublished draft: %v", err)
+	}
+	if status := posts.posts[id].Status; status != models.PostStatusDraft {
+		t.Errorf("unapproved post has status %v, want draft", status)
+	}
+
+	approvals.approved[id] = true
+	if post, err := s.Update(ctx, id, publish); err != nil || post.Status != models.PostStatusPublished {
+		t.Errorf("publishing an approved post = %+v, %v", post, err)
+	}
+
+	scheduled := draft(gated)
+	approvals.approved[scheduled] = true
+	if ok, err := s.PublishScheduled(ctx, scheduled); !ok || err != nil {
+		t.Errorf("scheduled publishing of an approved post = %v, %v; want true, nil", ok, err)
+	}
+
+	if publishedEvents != 3 {
+		t.Errorf("%d PostPublished events, want 3", publishedEvents)
+	}
+}
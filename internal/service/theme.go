@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/reqctx"
+	"github.com/keeps-dev/go-cms-template/internal/storage"
+)
+
+// ErrThemeStorageDisabled is returned when theme files are written or read
+// without a storage driver configured
+var ErrThemeStorageDisabled = errors.New("no theme storage is configured")
+
+// themeMoveBatch is how many database-held files MoveToStorage reads at once
+const themeMoveBatch = 50
+
+// ThemeService keeps the contents of theme files in a storage bucket under
+// their checksum, so versions sharing a file share its object, and their
+// listing in the theme repository. Files written before contents moved to
+// storage are read from the database until MoveToStorage moves them.
+type ThemeService struct {
+	repo   *repository.ThemeRepository
+	store  storage.Storage
+	bucket string
+}
+
+// NewThemeService returns a theme service storing files in bucket. store
+// may be nil when no storage is configured; themes can then be listed and
+// activated but no files written.
+func NewThemeService(repo *repository.ThemeRepository, store storage.Storage, bucket string) *ThemeService {
+	return &ThemeService{repo: repo, store: store, bucket: bucket}
+}
+
+// PutFile stores content and records it as path in a theme version
+func (s *ThemeService) PutFile(ctx context.Context, themeID uuid.UUID, path, contentType string, content []byte) (*models.ThemeFile, error) {
+	if s.store == nil {
+		return nil, ErrThemeStorageDisabled
+	}
+
+	sum := sha256.Sum256(content)
+	file := &models.ThemeFile{
+		Path:        path,
+		ContentType: contentType,
+		Size:        len(content),
+		Checksum:    hex.EncodeToString(sum[:]),
+	}
+	if err := s.store.Put(ctx, s.bucket, file.Checksum, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store theme file: %w", err)
+	}
+
+	previous, err := s.repo.PutFile(ctx, themeID, file)
+	if err != nil {
+		s.release(ctx, file.Checksum)
+		return nil, err
+	}
+	if previous != "" && previous != file.Checksum {
+		s.release(ctx, previous)
+	}
+
+	return file, nil
+}
+
+// GetFile returns a file of a theme version with its content
+func (s *ThemeService) GetFile(ctx context.Context, themeID uuid.UUID, path string) (*models.ThemeFile, error) {
+	file, err := s.repo.GetFile(ctx, themeID, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.load(ctx, file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// GetWithContent returns a theme version with the contents of its files
+func (s *ThemeService) GetWithContent(ctx context.Context, id uuid.UUID) (*models.Theme, error) {
+	theme, err := s.repo.GetWithContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadAll(ctx, theme); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// GetActive returns the active theme version with the contents of its
+// files, or repository.ErrNotFound when no stored theme is active
+func (s *ThemeService) GetActive(ctx context.Context) (*models.Theme, error) {
+	theme, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadAll(ctx, theme); err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// DeleteFile removes a file from a theme version
+func (s *ThemeService) DeleteFile(ctx context.Context, themeID uuid.UUID, path string) error {
+	checksum, err := s.repo.DeleteFile(ctx, themeID, path)
+	if err != nil {
+		return err
+	}
+	s.release(ctx, checksum)
+	return nil
+}
+
+// Delete removes a theme version and the stored files no other version uses
+func (s *ThemeService) Delete(ctx context.Context, id uuid.UUID) error {
+	checksums, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, checksum := range checksums {
+		s.release(ctx, checksum)
+	}
+	return nil
+}
+
+// MoveToStorage copies the contents still kept in the database to storage
+// and clears them there, returning how many files it moved. Instances
+// starting together may move the same files; the copies are identical.
+func (s *ThemeService) MoveToStorage(ctx context.Context) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+
+	moved := 0
+	for {
+		files, err := s.repo.FilesWithContent(ctx, themeMoveBatch)
+		if err != nil {
+			return moved, err
+		}
+		if len(files) == 0 {
+			return moved, nil
+		}
+		for _, f := range files {
+			if err := s.store.Put(ctx, s.bucket, f.Checksum, bytes.NewReader(f.Content), int64(len(f.Content)), f.ContentType); err != nil {
+				return moved, fmt.Errorf("failed to store theme file %s: %w", f.Path, err)
+			}
+			if err := s.repo.ClearContent(ctx, f.ThemeID, f.Path, f.Checksum); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
+
+func (s *ThemeService) loadAll(ctx context.Context, theme *models.Theme) error {
+	for i := range theme.Files {
+		if err := s.load(ctx, &theme.Files[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load reads a file's content from storage unless the database still
+// holds it
+func (s *ThemeService) load(ctx context.Context, file *models.ThemeFile) error {
+	if file.Content != nil {
+		return nil
+	}
+	if s.store == nil {
+		return ErrThemeStorageDisabled
+	}
+
+	obj, _, err := s.store.Get(ctx, s.bucket, file.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to open theme file %s: %w", file.Path, err)
+	}
+	defer obj.Close()
+
+	content, err := io.ReadAll(obj)
+	if err != nil {
+		return fmt.Errorf("failed to read theme file %s: %w", file.Path, err)
+	}
+	file.Content = content
+	return nil
+}
+
+// release deletes the object stored under checksum once no theme file
+// references it. The records are already changed, so failures are only
+// logged and leave an unused object behind.
+func (s *ThemeService) release(ctx context.Context, checksum string) {
+	if s.store == nil {
+		return
+	}
+	inUse, err := s.repo.ChecksumInUse(ctx, checksum)
+	if err != nil {
+		reqctx.Logf(ctx, "[ERROR] %v", err)
+		return
+	}
+	if inUse {
+		return
+	}
+	if err := s.store.Delete(ctx, s.bucket, checksum); err != nil {
+		reqctx.Logf(ctx, "[ERROR] Failed to delete theme file %s/%s: %v", s.bucket, checksum, err)
+	}
+}
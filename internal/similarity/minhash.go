@@ -0,0 +1,140 @@
+// Package similarity finds near-duplicate texts with MinHash signatures of
+// word shingles, using locality-sensitive hashing to avoid comparing every
+// pair of documents.
+package similarity
+
+import (
+	"hash/fnv"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// shingleSize is the number of consecutive words per shingle
+	shingleSize = 5
+
+	// SignatureSize is the number of MinHash values per signature. The
+	// estimate of the Jaccard similarity has a standard error of about
+	// 1/sqrt(SignatureSize).
+	SignatureSize = 128
+
+	// Signatures are split into lshBands bands of lshRows values; documents
+	// sharing any band become candidates. With 32 bands of 4 rows, pairs at
+	// 0.8 similarity are found with >99.9% probability while pairs below
+	// 0.3 rarely are.
+	lshBands = 32
+	lshRows  = SignatureSize / lshBands
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// seeds derive the SignatureSize hash functions from one shingle hash
+var seeds = func() [SignatureSize]uint64 {
+	var s [SignatureSize]uint64
+	x := uint64(0x5851f42d4c957f2d)
+	for i := range s {
+		x = mix(x)
+		s[i] = x
+	}
+	return s
+}()
+
+// Signature is the MinHash signature of a text
+type Signature [SignatureSize]uint32
+
+// Sign computes the signature of text, ignoring HTML tags, case and
+// punctuation. It reports false when text is too short to have a shingle.
+func Sign(text string) (Signature, bool) {
+	var sig Signature
+	words := words(text)
+	if len(words) < shingleSize {
+		return sig, false
+	}
+
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+		shingle := h.Sum64()
+		for j, seed := range seeds {
+			if v := uint32(mix(shingle ^ seed)); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+	return sig, true
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind
+// two signatures, from 0 (nothing shared) to 1 (identical)
+func Similarity(a, b *Signature) float64 {
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / SignatureSize
+}
+
+// Pair is a pair of near-duplicate documents, identified by their index in
+// the slice given to Duplicates, with A < B
+type Pair struct {
+	A, B       int
+	Similarity float64
+}
+
+// Duplicates returns the pairs of signatures whose estimated similarity is
+// at least threshold. Only pairs sharing an LSH band are compared.
+func Duplicates(sigs []Signature, threshold float64) []Pair {
+	seen := make(map[[2]int]bool)
+	var pairs []Pair
+
+	for band := 0; band < lshBands; band++ {
+		buckets := make(map[uint64][]int)
+		for i := range sigs {
+			h := fnv.New64a()
+			for _, v := range sigs[i][band*lshRows : (band+1)*lshRows] {
+				h.Write([]byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)})
+			}
+			key := h.Sum64()
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, members := range buckets {
+			for x := 0; x < len(members); x++ {
+				for y := x + 1; y < len(members); y++ {
+					key := [2]int{members[x], members[y]}
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					if s := Similarity(&sigs[key[0]], &sigs[key[1]]); s >= threshold {
+						pairs = append(pairs, Pair{A: key[0], B: key[1], Similarity: s})
+					}
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// words returns the lowercase words of text with HTML tags and entities removed
+func words(text string) []string {
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, " "))
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// mix is the splitmix64 finalizer, spreading the bits of x
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
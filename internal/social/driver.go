@@ -0,0 +1,362 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Driver shares a post to a single social account
+type Driver interface {
+	Share(ctx context.Context, share *Share) (*Result, error)
+}
+
+// Supported account drivers
+const (
+	DriverX        = "x"
+	DriverLinkedIn = "linkedin"
+	DriverFacebook = "facebook"
+)
+
+// Default API versions, overridable per account as they are retired
+const (
+	defaultLinkedInVersion = "202509"
+	defaultGraphVersion    = "v21.0"
+)
+
+// maxImageBytes caps images downloaded for upload to a network
+const maxImageBytes = 5 << 20
+
+func newDriver(a Account, client *http.Client) (Driver, error) {
+	if a.AccessToken == "" {
+		return nil, fmt.Errorf("account %q: access_token is required", a.Name)
+	}
+	switch a.Driver {
+	case DriverX:
+		return &xDriver{token: a.AccessToken, client: client}, nil
+	case DriverLinkedIn:
+		if a.AuthorURN == "" {
+			return nil, fmt.Errorf("account %q: author_urn is required", a.Name)
+		}
+		version := a.APIVersion
+		if version == "" {
+			version = defaultLinkedInVersion
+		}
+		return &linkedInDriver{token: a.AccessToken, author: a.AuthorURN, version: version, client: client}, nil
+	case DriverFacebook:
+		if a.PageID == "" {
+			return nil, fmt.Errorf("account %q: page_id is required", a.Name)
+		}
+		version := a.APIVersion
+		if version == "" {
+			version = defaultGraphVersion
+		}
+		return &facebookDriver{token: a.AccessToken, pageID: a.PageID, version: version, client: client}, nil
+	default:
+		return nil, fmt.Errorf("account %q: unknown driver %q", a.Name, a.Driver)
+	}
+}
+
+// xDriver posts with the X API v2 using an OAuth 2.0 user access token
+// with the tweet.write and media.write scopes
+type xDriver struct {
+	token  string
+	client *http.Client
+}
+
+// xMaxLength is the post length limit; X counts every link as xLinkLength
+const (
+	xMaxLength  = 280
+	xLinkLength = 23
+)
+
+func (d *xDriver) Share(ctx context.Context, share *Share) (*Result, error) {
+	payload := map[string]interface{}{"text": xText(share.Message, share.Link)}
+	if share.ImageURL != "" {
+		mediaID, err := d.uploadImage(ctx, share.ImageURL)
+		if err != nil {
+			// The link preview still shows the page's own image
+			log.Printf("[ERROR] X image upload failed, posting without it: %v", err)
+		} else {
+			payload["media"] = map[string][]string{"media_ids": {mediaID}}
+		}
+	}
+
+	var resp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if _, err := doJSON(ctx, d.client, http.MethodPost, "https://api.x.com/2/tweets", d.headers(), payload, &resp); err != nil {
+		return nil, err
+	}
+	return &Result{ID: resp.Data.ID, URL: "https://x.com/i/web/status/" + resp.Data.ID}, nil
+}
+
+func (d *xDriver) uploadImage(ctx context.Context, imageURL string) (string, error) {
+	data, contentType, err := fetchImage(ctx, d.client, imageURL)
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("media_category", "tweet_image")
+	form.WriteField("media_type", contentType)
+	part, err := form.CreateFormFile("media", "image")
+	if err != nil {
+		return "", err
+	}
+	part.Write(data)
+	form.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.x.com/2/media/upload", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var resp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if _, err := do(d.client, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.ID, nil
+}
+
+func (d *xDriver) headers() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + d.token}
+}
+
+// xText moves the link to the end of the message, adding it if the
+// template left it out, and shortens the rest to fit the length limit
+func xText(message, link string) string {
+	text := strings.Join(strings.Fields(strings.Replace(message, link, "", 1)), " ")
+	if utf8.RuneCountInString(text)+1+xLinkLength > xMaxLength {
+		runes := []rune(text)
+		text = strings.TrimSpace(string(runes[:xMaxLength-xLinkLength-2])) + "…"
+	}
+	if text == "" {
+		return link
+	}
+	return text + " " + link
+}
+
+// linkedInDriver posts an article share with the LinkedIn Posts API
+type linkedInDriver struct {
+	token   string
+	author  string
+	version string
+	client  *http.Client
+}
+
+func (d *linkedInDriver) Share(ctx context.Context, share *Share) (*Result, error) {
+	article := map[string]string{"source": share.Link, "title": share.Title}
+	if share.Excerpt != "" {
+		article["description"] = share.Excerpt
+	}
+	if share.ImageURL != "" {
+		image, err := d.uploadImage(ctx, share.ImageURL)
+		if err != nil {
+			log.Printf("[ERROR] LinkedIn image upload failed, posting without it: %v", err)
+		} else {
+			article["thumbnail"] = image
+		}
+	}
+
+	header, err := doJSON(ctx, d.client, http.MethodPost, "https://api.linkedin.com/rest/posts", d.headers(), map[string]interface{}{
+		"author":     d.author,
+		"commentary": linkedInEscape(share.Message),
+		"visibility": "PUBLIC",
+		"distribution": map[string]interface{}{
+			"feedDistribution":               "MAIN_FEED",
+			"targetEntities":                 []string{},
+			"thirdPartyDistributionChannels": []string{},
+		},
+		"content":                   map[string]interface{}{"article": article},
+		"lifecycleState":            "PUBLISHED",
+		"isReshareDisabledByAuthor": false,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	id := header.Get("X-Restli-Id")
+	return &Result{ID: id, URL: "https://www.linkedin.com/feed/update/" + id}, nil
+}
+
+func (d *linkedInDriver) uploadImage(ctx context.Context, imageURL string) (string, error) {
+	data, contentType, err := fetchImage(ctx, d.client, imageURL)
+	if err != nil {
+		return "", err
+	}
+
+	var upload struct {
+		Value struct {
+			UploadURL string `json:"uploadUrl"`
+			Image     string `json:"image"`
+		} `json:"value"`
+	}
+	_, err = doJSON(ctx, d.client, http.MethodPost, "https://api.linkedin.com/rest/images?action=initializeUpload", d.headers(),
+		map[string]interface{}{"initializeUploadRequest": map[string]string{"owner": d.author}}, &upload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, upload.Value.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	req.Header.Set("Content-Type", contentType)
+	if _, err := do(d.client, req, nil); err != nil {
+		return "", err
+	}
+	return upload.Value.Image, nil
+}
+
+func (d *linkedInDriver) headers() map[string]string {
+	return map[string]string{
+		"Authorization":             "Bearer " + d.token,
+		"LinkedIn-Version":          d.version,
+		"X-Restli-Protocol-Version": "2.0.0",
+	}
+}
+
+// linkedInEscape escapes the characters LinkedIn's commentary format
+// reserves for mention and hashtag markup
+func linkedInEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(`\|{}@[]()<>#*_~`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// facebookDriver posts to a Facebook page with a page access token: a
+// photo with the message as caption when there is an image, a link post
+// otherwise
+type facebookDriver struct {
+	token   string
+	pageID  string
+	version string
+	client  *http.Client
+}
+
+func (d *facebookDriver) Share(ctx context.Context, share *Share) (*Result, error) {
+	endpoint := fmt.Sprintf("https://graph.facebook.com/%s/%s/", d.version, url.PathEscape(d.pageID))
+	form := url.Values{"access_token": {d.token}}
+	if share.ImageURL != "" {
+		endpoint += "photos"
+		caption := share.Message
+		if !strings.Contains(caption, share.Link) {
+			caption += "\n" + share.Link
+		}
+		form.Set("url", share.ImageURL)
+		form.Set("caption", caption)
+	} else {
+		endpoint += "feed"
+		form.Set("message", share.Message)
+		form.Set("link", share.Link)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		ID     string `json:"id"`
+		PostID string `json:"post_id"`
+	}
+	if _, err := do(d.client, req, &resp); err != nil {
+		return nil, err
+	}
+	id := resp.PostID
+	if id == "" {
+		id = resp.ID
+	}
+	return &Result{ID: id, URL: "https://www.facebook.com/" + id}, nil
+}
+
+// fetchImage downloads an image to upload it to a network
+func fetchImage(ctx context.Context, client *http.Client, imageURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download image: %w", err)
+	}
+	if len(data) > maxImageBytes {
+		return nil, "", fmt.Errorf("image is larger than %d bytes", maxImageBytes)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
+
+// doJSON sends payload as JSON and decodes the JSON response into out
+// (when not nil), returning the response headers
+func doJSON(ctx context.Context, client *http.Client, method, endpoint string, headers map[string]string, payload, out interface{}) (http.Header, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return do(client, req, out)
+}
+
+func do(client *http.Client, req *http.Request, out interface{}) (http.Header, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s rejected the request with status %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("invalid response from %s: %w", req.URL.Host, err)
+		}
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,232 @@
+// Package social shares published posts to social network accounts (X,
+// LinkedIn and Facebook pages) configured in settings.
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+)
+
+// Setting keys holding the accounts and the message template
+const (
+	SettingAccounts = "social.accounts"
+	SettingMessage  = "social.message"
+)
+
+// DefaultMessage is used when the social.message setting is empty
+const DefaultMessage = "{title} {url}"
+
+// Account is a configured social network account. Access tokens are
+// write-only and are omitted when the configuration is listed.
+type Account struct {
+	Name        string `json:"name"`
+	Driver      string `json:"driver"`
+	AccessToken string `json:"access_token,omitempty"`
+	// AuthorURN is the LinkedIn member or organization posting, e.g.
+	// urn:li:organization:123
+	AuthorURN string `json:"author_urn,omitempty"`
+	// PageID is the Facebook page posted to
+	PageID string `json:"page_id,omitempty"`
+	// APIVersion overrides the LinkedIn (YYYYMM) or Graph API (vNN.N)
+	// version
+	APIVersion string `json:"api_version,omitempty"`
+}
+
+// Config is the sharing configuration loaded from settings
+type Config struct {
+	Accounts []Account `json:"accounts"`
+	Message  string    `json:"message"`
+}
+
+// Names returns the names of the configured accounts
+func (c *Config) Names() []string {
+	names := make([]string, len(c.Accounts))
+	for i, a := range c.Accounts {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// masked replaces access tokens in what the API returns
+const masked = "********"
+
+// Redacted returns the configuration with access tokens masked
+func (c *Config) Redacted() *Config {
+	out := &Config{Message: c.Message, Accounts: make([]Account, len(c.Accounts))}
+	for i, a := range c.Accounts {
+		if a.AccessToken != "" {
+			a.AccessToken = masked
+		}
+		out.Accounts[i] = a
+	}
+	return out
+}
+
+// MaskAccounts masks the access tokens in a social.accounts setting value.
+// A value that isn't a list of accounts is masked whole.
+func MaskAccounts(value string) string {
+	var accounts []Account
+	if err := json.Unmarshal([]byte(value), &accounts); err != nil {
+		return masked
+	}
+	b, _ := json.Marshal((&Config{Accounts: accounts}).Redacted().Accounts)
+	return string(b)
+}
+
+// UnmaskAccounts puts back the access tokens that MaskAccounts masked in a
+// social.accounts value being written, taking them from the stored accounts
+// of the same name, so a value read from the API can be saved unchanged
+func UnmaskAccounts(value, stored string) string {
+	var accounts, current []Account
+	if json.Unmarshal([]byte(value), &accounts) != nil || json.Unmarshal([]byte(stored), &current) != nil {
+		return value
+	}
+	tokens := make(map[string]string, len(current))
+	for _, a := range current {
+		tokens[a.Name] = a.AccessToken
+	}
+	unmasked := false
+	for i, a := range accounts {
+		if a.AccessToken == masked {
+			accounts[i].AccessToken = tokens[a.Name]
+			unmasked = true
+		}
+	}
+	if !unmasked {
+		return value
+	}
+	b, _ := json.Marshal(accounts)
+	return string(b)
+}
+
+// Share is what is posted about a post: the rendered message, the link to
+// the post and the image shown with it
+type Share struct {
+	Title    string
+	Excerpt  string
+	Message  string
+	Link     string
+	ImageURL string
+}
+
+// Result identifies the post created on the social network
+type Result struct {
+	ID  string
+	URL string
+}
+
+// Publisher posts shares to the accounts configured in settings
+type Publisher struct {
+	settings *repository.SettingRepository
+	client   *http.Client
+}
+
+func New(settings *repository.SettingRepository) *Publisher {
+	return &Publisher{
+		settings: settings,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LoadConfig reads accounts and the message template from settings.
+// Missing settings yield no accounts and the default message.
+func (p *Publisher) LoadConfig(ctx context.Context) (*Config, error) {
+	values, err := p.settings.GetMultiple(ctx, []string{SettingAccounts, SettingMessage})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Message: values[SettingMessage]}
+	if cfg.Message == "" {
+		cfg.Message = DefaultMessage
+	}
+	if raw := values[SettingAccounts]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg.Accounts); err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", SettingAccounts, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Compose builds the share of a post at link. Templates may use {title},
+// {excerpt}, {url}, {content_type} and {tags} (the post's tags as
+// hashtags). The image is the post's featured image, or else its first
+// image.
+func Compose(template string, post *models.ContentPost, link string) *Share {
+	share := &Share{Title: post.Title, Link: link}
+	if post.Excerpt != nil {
+		share.Excerpt = *post.Excerpt
+	}
+
+	var contentType string
+	if post.ContentType != nil {
+		contentType = post.ContentType.Name
+	}
+	hashtags := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		if h := hashtag(tag.Name); h != "" {
+			hashtags = append(hashtags, h)
+		}
+	}
+
+	share.Message = strings.TrimSpace(strings.NewReplacer(
+		"{title}", post.Title,
+		"{excerpt}", share.Excerpt,
+		"{url}", link,
+		"{content_type}", contentType,
+		"{tags}", strings.Join(hashtags, " "),
+	).Replace(template))
+
+	media := append([]models.PostMedia(nil), post.Media...)
+	sort.SliceStable(media, func(i, j int) bool {
+		return media[i].MediaRole == models.MediaRoleFeatured && media[j].MediaRole != models.MediaRoleFeatured
+	})
+	for _, pm := range media {
+		if pm.Media != nil && pm.Media.FileType == models.FileTypeImage && pm.Media.CDNUrl != nil {
+			share.ImageURL = *pm.Media.CDNUrl
+			break
+		}
+	}
+
+	return share
+}
+
+// Send posts share to account
+func (p *Publisher) Send(ctx context.Context, account Account, share *Share) (*Result, error) {
+	driver, err := newDriver(account, p.client)
+	if err != nil {
+		return nil, err
+	}
+	return driver.Share(ctx, share)
+}
+
+// hashtag turns a tag name into a hashtag, dropping everything but letters
+// and digits ("Open Source" becomes #OpenSource)
+func hashtag(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(name) {
+		for i, r := range word {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				continue
+			}
+			if i == 0 {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "#" + b.String()
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// local keeps files in a directory holding one subdirectory per bucket
+type local struct {
+	dir string
+}
+
+func (l *local) Get(ctx context.Context, bucket, key string) (Object, time.Time, error) {
+	name, ok := objectPath(bucket, key)
+	if !ok {
+		return nil, time.Time{}, ErrNotFound
+	}
+
+	f, err := os.Open(filepath.Join(l.dir, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		return nil, time.Time{}, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, time.Time{}, fmt.Errorf("failed to stat %s: %w", name, err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, time.Time{}, ErrNotFound
+	}
+	return f, info.ModTime(), nil
+}
+
+// Put writes to a temporary file first, so readers never see a partial file
+func (l *local) Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	name, ok := objectPath(bucket, key)
+	if !ok {
+		return fmt.Errorf("invalid object path %s/%s", bucket, key)
+	}
+	path := filepath.Join(l.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if written != size {
+		return fmt.Errorf("failed to write %s: got %d of %d bytes", name, written, size)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (l *local) Delete(ctx context.Context, bucket, key string) error {
+	name, ok := objectPath(bucket, key)
+	if !ok {
+		return fmt.Errorf("invalid object path %s/%s", bucket, key)
+	}
+	if err := os.Remove(filepath.Join(l.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", name, err)
+	}
+	return nil
+}
+
+// SignedURL is unsupported: local files are only reachable through the API
+func (l *local) SignedURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	return "", ErrSigningUnsupported
+}
+
+// objectPath maps an object to its path under the storage directory.
+// Buckets are single directories and keys must not climb out of them.
+func objectPath(bucket, key string) (string, bool) {
+	if strings.ContainsAny(bucket, `/\`) || !filepath.IsLocal(bucket) || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", false
+	}
+	return filepath.Join(bucket, filepath.FromSlash(key)), true
+}
@@ -0,0 +1,304 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payload hashes: the SHA-256 of an empty request body, and the marker for
+// bodies that are streamed without hashing them first
+const (
+	emptyPayloadHash    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	unsignedPayloadHash = "UNSIGNED-PAYLOAD"
+)
+
+// s3Store reads and writes objects in S3 or a compatible store (MinIO, R2,
+// ...), signing requests with AWS Signature Version 4
+type s3Store struct {
+	endpoint  *url.URL
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+}
+
+func (s *s3Store) Get(ctx context.Context, bucket, key string) (Object, time.Time, error) {
+	objectURL := s.objectURL(bucket, key)
+	resp, err := s.do(ctx, http.MethodHead, objectURL, "")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp.Body.Close()
+
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &s3Object{store: s, ctx: ctx, url: objectURL, size: resp.ContentLength}, modTime, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(bucket, key), body)
+	if err != nil {
+		return fmt.Errorf("failed to build storage request: %w", err)
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, time.Now(), unsignedPayloadHash)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call storage: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("storage rejected PUT with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Delete succeeds for missing objects too, as S3 answers 204 either way
+func (s *s3Store) Delete(ctx context.Context, bucket, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(bucket, key), "")
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL presigns a GET of the object, with the signature in the query
+// string instead of the Authorization header
+func (s *s3Store) SignedURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	if ttl <= 0 || ttl > MaxSignedURLTTL {
+		return "", fmt.Errorf("signed URL lifetime must be between 1s and %s", MaxSignedURLTTL)
+	}
+	return s.presign(s.objectURL(bucket, key), time.Now(), ttl)
+}
+
+// presign signs a GET of objectURL valid for ttl from now
+func (s *s3Store) presign(objectURL string, now time.Time, ttl time.Duration) (string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to build signed URL: %w", err)
+	}
+
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + s.region + "/s3/aws4_request"
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(ttl / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = uriEncode(name, true) + "=" + uriEncode(query[name], true)
+	}
+	canonicalQuery := strings.Join(params, "&")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(amzDate[:8]), stringToSign))
+
+	return u.Scheme + "://" + u.Host + u.EscapedPath() + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// objectURL addresses an object in the bucket's virtual host or, with path
+// style, under the endpoint's path
+func (s *s3Store) objectURL(bucket, key string) string {
+	u := *s.endpoint
+	path := "/" + uriEncode(key, false)
+	if s.pathStyle {
+		path = "/" + uriEncode(bucket, true) + path
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	return u.Scheme + "://" + u.Host + u.Path + path
+}
+
+// do sends a signed request; byteRange is an optional Range header value
+func (s *s3Store) do(ctx context.Context, method, objectURL, byteRange string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build storage request: %w", err)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	s.sign(req, time.Now(), emptyPayloadHash)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call storage: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage rejected %s with status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 Authorization header to a request whose
+// body hashes to payloadHash
+func (s *s3Store) sign(req *http.Request, now time.Time, payloadHash string) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + s.region + "/s3/aws4_request"
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(amzDate[:8]), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// signingKey derives the Signature Version 4 key for a date (YYYYMMDD)
+func (s *s3Store) signingKey(date string) []byte {
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return key
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes unless encodeSlash is set, as Signature Version 4 requires
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Object reads an object lazily: the first read after a seek fetches
+// everything from the new offset with a ranged GET
+type s3Object struct {
+	store  *s3Store
+	ctx    context.Context
+	url    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		resp, err := o.store.do(o.ctx, http.MethodGet, o.url, "bytes="+strconv.FormatInt(o.offset, 10)+"-")
+		if err != nil {
+			return 0, err
+		}
+		o.body = resp.Body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if offset != o.offset {
+		o.Close()
+		o.offset = offset
+	}
+	return offset, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
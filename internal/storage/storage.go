@@ -0,0 +1,93 @@
+// Package storage reads, writes and deletes media files in the backend their
+// bucket and object key point into: a local directory or an S3-compatible
+// object store (AWS, MinIO, R2, ...).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/config"
+)
+
+// Supported storage drivers
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNotFound is returned when a bucket has no object under a key
+var ErrNotFound = errors.New("object not found")
+
+// ErrSigningUnsupported is returned by backends that can't hand out URLs
+// reading an object without credentials
+var ErrSigningUnsupported = errors.New("signed URLs are not supported by this storage")
+
+// MaxSignedURLTTL is the longest lifetime of a signed URL, as S3 allows
+const MaxSignedURLTTL = 7 * 24 * time.Hour
+
+// Object is an open stored file. Seeking is cheap; reads fetch from the
+// current offset, so serving a byte range only transfers that range.
+type Object interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// Storage reads, writes and deletes stored files
+type Storage interface {
+	// Get returns the object under key in bucket and when it was last
+	// modified
+	Get(ctx context.Context, bucket, key string) (Object, time.Time, error)
+
+	// Put stores size bytes read from body under key in bucket, replacing
+	// any existing object
+	Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error
+
+	// Delete removes the object under key in bucket; deleting a missing
+	// object is not an error
+	Delete(ctx context.Context, bucket, key string) error
+
+	// SignedURL returns a URL that reads the object under key in bucket
+	// without credentials for ttl (up to MaxSignedURLTTL), or
+	// ErrSigningUnsupported
+	SignedURL(ctx context.Context, bucket, key string, ttl time.Duration) (string, error)
+}
+
+// New returns the storage selected by cfg.Driver
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("local: a directory is required")
+		}
+		return &local{dir: cfg.Dir}, nil
+	case DriverS3:
+		if cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, fmt.Errorf("s3: an access key and secret key are required")
+		}
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+		}
+		u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("s3: invalid endpoint %q", endpoint)
+		}
+		return &s3Store{
+			endpoint:  u,
+			region:    cfg.Region,
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			pathStyle: cfg.PathStyle,
+			client:    &http.Client{},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,157 @@
+package uischema
+
+import "github.com/keeps-dev/go-cms-template/internal/models"
+
+// Definitions lists the entities exposed to admin frontends, in menu order
+var Definitions = []Definition{
+	{
+		Name: "posts", Label: "Posts", Path: "/api/v1/posts", IDField: "id",
+		Model: models.ContentPost{}, Create: models.CreatePostRequest{}, Update: models.UpdatePostRequest{},
+		Filter:      models.PostFilter{},
+		ListColumns: []string{"title", "slug", "status", "published_at", "view_count", "updated_at"},
+		Sortable:    []string{"created_at", "updated_at", "published_at", "title", "view_count"},
+		Deletable:   true,
+	},
+	{
+		Name: "content_types", Label: "Content Types", Path: "/api/v1/content-types", IDField: "id",
+		Model: models.ContentType{}, Create: models.CreateContentTypeRequest{}, Update: models.UpdateContentTypeRequest{},
+		Filter:      models.ContentTypeFilter{},
+		ListColumns: []string{"name", "slug", "is_active", "display_order"},
+		Sortable:    []string{"created_at", "name", "display_order"},
+		Deletable:   true,
+	},
+	{
+		Name: "media", Label: "Media", Path: "/api/v1/media", IDField: "id",
+		Model: models.Media{}, Create: models.CreateMediaRequest{}, Update: models.UpdateMediaRequest{},
+		Filter:      models.MediaFilter{},
+		ListColumns: []string{"file_name", "file_type", "mime_type", "file_size", "created_at"},
+		Sortable:    []string{"created_at", "file_name", "file_size"},
+		Deletable:   true,
+	},
+	{
+		Name: "tags", Label: "Tags", Path: "/api/v1/tags", IDField: "id",
+		Model: models.Tag{}, Create: models.CreateTagRequest{}, Update: models.UpdateTagRequest{},
+		Filter:      models.TagFilter{},
+		ListColumns: []string{"name", "slug", "created_at"},
+		Sortable:    []string{"created_at", "name"},
+		Deletable:   true,
+	},
+	{
+		Name: "categories", Label: "Categories", Path: "/api/v1/categories", IDField: "id",
+		Model: models.Category{}, Create: models.CreateCategoryRequest{}, Update: models.UpdateCategoryRequest{},
+		Filter:      models.CategoryFilter{},
+		ListColumns: []string{"name", "slug", "parent_id", "display_order"},
+		Sortable:    []string{"created_at", "name", "display_order"},
+		Deletable:   true,
+	},
+	{
+		Name: "post_templates", Label: "Post Templates", Path: "/api/v1/post-templates", IDField: "id",
+		Model: models.PostTemplate{}, Create: models.CreatePostTemplateRequest{}, Update: models.UpdatePostTemplateRequest{},
+		Filter:      models.PostTemplateFilter{},
+		ListColumns: []string{"name", "content_type_id", "is_active", "updated_at"},
+		Sortable:    []string{"name", "created_at", "updated_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "teams", Label: "Teams", Path: "/api/v1/teams", IDField: "id",
+		Model: models.Team{}, Create: models.CreateTeamRequest{}, Update: models.UpdateTeamRequest{},
+		Filter:      models.TeamFilter{},
+		ListColumns: []string{"name", "slug", "member_count", "created_at"},
+		Sortable:    []string{"created_at", "name"},
+		Deletable:   true,
+	},
+	{
+		Name: "contacts", Label: "Contact Submissions", Path: "/api/v1/contacts", IDField: "id",
+		Model: models.ContactSubmission{}, Update: models.UpdateContactRequest{},
+		Filter:      models.ContactFilter{},
+		ListColumns: []string{"name", "email", "subject", "status", "created_at"},
+		Sortable:    []string{"created_at", "status"},
+		Deletable:   true,
+	},
+	{
+		Name: "settings", Label: "Settings", Path: "/api/v1/settings", IDField: "key",
+		Model: models.Setting{}, Create: models.CreateSettingRequest{}, Update: models.UpdateSettingRequest{},
+		Filter:      models.SettingFilter{},
+		ListColumns: []string{"key", "value", "description", "updated_at"},
+		Sortable:    []string{"key", "updated_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "consent_versions", Label: "Consent Versions", Path: "/api/v1/consent-versions", IDField: "id",
+		Model: models.ConsentVersion{}, Create: models.CreateConsentVersionRequest{},
+		Filter:      models.ConsentVersionFilter{},
+		ListColumns: []string{"version", "title", "published_at"},
+		Sortable:    []string{"published_at", "created_at"},
+	},
+	{
+		Name: "blocklist", Label: "Blocklist", Path: "/api/v1/blocklist", IDField: "id",
+		Model: models.BlocklistRule{}, Create: models.CreateBlocklistRuleRequest{}, Update: models.UpdateBlocklistRuleRequest{},
+		Filter:      models.BlocklistFilter{},
+		ListColumns: []string{"rule_type", "value", "action", "hit_count", "last_hit_at"},
+		Sortable:    []string{"created_at", "hit_count", "last_hit_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "flagged_ips", Label: "Flagged IPs", Path: "/api/v1/admin/flagged-ips", IDField: "ip",
+		Model: models.FlaggedIP{}, Update: models.SetFlaggedIPRequest{},
+		Filter:      models.FlaggedIPFilter{},
+		ListColumns: []string{"ip", "status", "manual", "reason", "flag_count", "expires_at"},
+		Sortable:    []string{"updated_at", "flag_count", "expires_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "delivery_tokens", Label: "Delivery Tokens", Path: "/api/v1/admin/delivery-tokens", IDField: "id",
+		Model: models.DeliveryToken{}, Create: models.CreateDeliveryTokenRequest{}, Update: models.UpdateDeliveryTokenRequest{},
+		Filter:      models.DeliveryTokenFilter{},
+		ListColumns: []string{"name", "token_prefix", "content_types", "environments", "is_active", "last_used_at"},
+		Sortable:    []string{"name", "created_at", "last_used_at", "expires_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "email_templates", Label: "Email Templates", Path: "/api/v1/email-templates", IDField: "id",
+		Model: models.EmailTemplate{}, Create: models.CreateEmailTemplateRequest{}, Update: models.UpdateEmailTemplateRequest{},
+		Filter:      models.EmailTemplateFilter{},
+		ListColumns: []string{"name", "locale", "subject", "updated_at"},
+		Sortable:    []string{"name", "locale", "updated_at"},
+		Deletable:   true,
+	},
+	{
+		Name: "emails", Label: "Email Queue", Path: "/api/v1/emails", IDField: "id",
+		Model:       models.Email{},
+		Filter:      models.EmailFilter{},
+		ListColumns: []string{"to_address", "subject", "status", "attempts", "sent_at", "created_at"},
+		Sortable:    []string{"created_at", "sent_at", "status"},
+	},
+	{
+		Name: "polls", Label: "Polls", Path: "/api/v1/polls", IDField: "id",
+		Model: models.Poll{}, Create: models.CreatePollRequest{}, Update: models.UpdatePollRequest{},
+		Filter:      models.PollFilter{},
+		ListColumns: []string{"question", "opens_at", "closes_at", "total_votes", "created_at"},
+		Sortable:    []string{"created_at"},
+		Deletable:   true,
+	},
+}
+
+// Schema is the full UI schema document
+type Schema struct {
+	Version    int      `json:"version"`
+	Pagination []Filter `json:"pagination"`
+	Entities   []Entity `json:"entities"`
+}
+
+// Generate builds the UI schema for all registered entities
+func Generate() *Schema {
+	s := &Schema{
+		Version: 1,
+		Pagination: []Filter{
+			{Name: "page", Type: "integer"},
+			{Name: "page_size", Type: "integer"},
+			{Name: "sort_by", Type: "string"},
+			{Name: "sort_dir", Type: "string"},
+		},
+	}
+	for _, d := range Definitions {
+		s.Entities = append(s.Entities, Build(d))
+	}
+	return s
+}
@@ -0,0 +1,302 @@
+// Package uischema describes the API's entities in a machine-readable form so
+// generic admin frontends can build list, filter and edit screens without
+// hand-written configuration. Descriptions are derived from the Go models by
+// reflection.
+package uischema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Field describes a single property of an entity
+type Field struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Items    string      `json:"items,omitempty"`
+	Enum     []EnumValue `json:"enum,omitempty"`
+	Nullable bool        `json:"nullable,omitempty"`
+	ReadOnly bool        `json:"read_only,omitempty"`
+
+	// Create and update rules derived from the request models
+	Creatable bool `json:"creatable"`
+	Updatable bool `json:"updatable"`
+	Required  bool `json:"required"`
+}
+
+// EnumValue is one allowed value of an integer enum
+type EnumValue struct {
+	Value int64  `json:"value"`
+	Label string `json:"label"`
+}
+
+// Filter describes a query parameter accepted by an entity's list endpoint
+type Filter struct {
+	Name string      `json:"name"`
+	Type string      `json:"type"`
+	Enum []EnumValue `json:"enum,omitempty"`
+}
+
+// Permissions lists what the current user may do with an entity
+type Permissions struct {
+	Read   bool `json:"read"`
+	Create bool `json:"create"`
+	Update bool `json:"update"`
+	Delete bool `json:"delete"`
+}
+
+// Entity describes one resource of the API
+type Entity struct {
+	Name        string      `json:"name"`
+	Label       string      `json:"label"`
+	Path        string      `json:"path"`
+	IDField     string      `json:"id_field"`
+	Fields      []Field     `json:"fields"`
+	ListColumns []string    `json:"list_columns"`
+	Filters     []Filter    `json:"filters"`
+	Sortable    []string    `json:"sortable"`
+	Permissions Permissions `json:"permissions"`
+}
+
+// Definition ties an entity to the models it is generated from. Create and
+// Update may be nil for entities that cannot be written through the API.
+type Definition struct {
+	Name        string
+	Label       string
+	Path        string
+	IDField     string
+	Model       interface{}
+	Create      interface{}
+	Update      interface{}
+	Filter      interface{}
+	ListColumns []string
+	Sortable    []string
+	Deletable   bool
+}
+
+var (
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	timeType     = reflect.TypeOf(time.Time{})
+	rawJSONType  = reflect.TypeOf(json.RawMessage{})
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// Build generates the entity description for d
+func Build(d Definition) Entity {
+	e := Entity{
+		Name:        d.Name,
+		Label:       d.Label,
+		Path:        d.Path,
+		IDField:     d.IDField,
+		ListColumns: d.ListColumns,
+		Sortable:    d.Sortable,
+		Permissions: Permissions{
+			Read:   true,
+			Create: d.Create != nil,
+			Update: d.Update != nil,
+			Delete: d.Deletable,
+		},
+	}
+
+	creatable := requestFields(d.Create)
+	updatable := requestFields(d.Update)
+
+	for _, sf := range jsonFields(reflect.TypeOf(d.Model)) {
+		name := jsonName(sf)
+		f := describe(sf.Type)
+		f.Name = name
+
+		if c, ok := creatable[name]; ok {
+			f.Creatable = true
+			f.Required = c
+		}
+		_, f.Updatable = updatable[name]
+		f.ReadOnly = !f.Creatable && !f.Updatable
+		e.Fields = append(e.Fields, f)
+	}
+
+	// Write-only request fields, such as tag_ids on posts
+	for _, sf := range jsonFields(typeOf(d.Create)) {
+		name := jsonName(sf)
+		if hasField(e.Fields, name) {
+			continue
+		}
+		f := describe(sf.Type)
+		f.Name = name
+		f.Creatable = true
+		f.Required = creatable[name]
+		_, f.Updatable = updatable[name]
+		e.Fields = append(e.Fields, f)
+	}
+
+	if d.Filter != nil {
+		for _, sf := range jsonFields(reflect.TypeOf(d.Filter)) {
+			f := describe(sf.Type)
+			e.Filters = append(e.Filters, Filter{Name: snakeCase(sf.Name), Type: f.Type, Enum: f.Enum})
+		}
+	}
+
+	return e
+}
+
+// requestFields maps the JSON names of a request model to whether the field
+// is required. Value fields without omitempty are required; pointers,
+// slices, raw JSON and omitempty fields are optional.
+func requestFields(model interface{}) map[string]bool {
+	fields := make(map[string]bool)
+	for _, sf := range jsonFields(typeOf(model)) {
+		tag := sf.Tag.Get("json")
+		optional := strings.Contains(tag, ",omitempty")
+		switch sf.Type.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			optional = true
+		}
+		fields[jsonName(sf)] = !optional
+	}
+	return fields
+}
+
+// describe maps a Go type to a schema type
+func describe(t reflect.Type) Field {
+	var f Field
+	if t.Kind() == reflect.Ptr {
+		f.Nullable = true
+		t = t.Elem()
+	}
+
+	switch {
+	case t == uuidType:
+		f.Type = "uuid"
+	case t == timeType:
+		f.Type = "datetime"
+	case t == rawJSONType:
+		f.Type = "json"
+	case isEnum(t):
+		f.Type = "enum"
+		f.Enum = enumValues(t)
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			f.Type = "string"
+		case reflect.Bool:
+			f.Type = "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f.Type = "integer"
+		case reflect.Float32, reflect.Float64:
+			f.Type = "number"
+		case reflect.Slice, reflect.Array:
+			f.Type = "array"
+			f.Items = describe(t.Elem()).Type
+		case reflect.Struct, reflect.Map:
+			f.Type = "object"
+		default:
+			f.Type = "string"
+		}
+	}
+
+	return f
+}
+
+// isEnum reports whether t is one of the models' integer enums, which all
+// implement fmt.Stringer
+func isEnum(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return t.Implements(stringerType)
+	}
+	return false
+}
+
+// enumValues lists the labelled values of an enum. Enums start at 1 and
+// report "unknown" for values past the last one.
+func enumValues(t reflect.Type) []EnumValue {
+	var values []EnumValue
+	for i := int64(1); i < 64; i++ {
+		v := reflect.New(t).Elem()
+		v.SetInt(i)
+		label := v.Interface().(fmt.Stringer).String()
+		if label == "unknown" {
+			break
+		}
+		values = append(values, EnumValue{Value: i, Label: label})
+	}
+	return values
+}
+
+// jsonFields returns the exported, JSON-visible fields of a struct type,
+// skipping embedded structs (pagination) and relation fields
+func jsonFields(t reflect.Type) []reflect.StructField {
+	if t == nil {
+		return nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Anonymous || sf.Tag.Get("json") == "-" {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != uuidType && ft != timeType {
+			continue
+		}
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct && ft.Elem() != uuidType {
+			continue
+		}
+		fields = append(fields, sf)
+	}
+	return fields
+}
+
+func jsonName(sf reflect.StructField) string {
+	if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return snakeCase(sf.Name)
+}
+
+func typeOf(v interface{}) reflect.Type {
+	if v == nil {
+		return nil
+	}
+	return reflect.TypeOf(v)
+}
+
+func hasField(fields []Field, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// snakeCase converts a Go field name to the snake_case query parameter the
+// handlers read, keeping acronyms together (ContentTypeID -> content_type_id)
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package web
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/keeps-dev/go-cms-template/internal/models"
+)
+
+// The default theme ships with the binary. A theme directory on disk may
+// override any of its files; files missing from the directory fall back to
+// the embedded copy, so a theme can be as small as a single template.
+//
+//go:embed themes/default
+var embeddedThemes embed.FS
+
+// Page templates, each rendered inside layout.html
+const (
+	templateIndex = "index.html"
+	templatePost  = "post.html"
+	templatePage  = "page.html"
+	templateError = "error.html"
+	templateBase  = "layout.html"
+)
+
+var pageTemplates = []string{templateIndex, templatePost, templatePage, templateError}
+
+// Theme is a parsed set of page templates plus the static assets that go
+// with them
+type Theme struct {
+	files     fs.FS
+	templates map[string]*template.Template
+}
+
+// LoadTheme parses the named theme from dir. An empty dir or a missing
+// default theme directory uses the embedded default theme unchanged.
+func LoadTheme(dir, name string) (*Theme, error) {
+	base, err := defaultThemeFS()
+	if err != nil {
+		return nil, err
+	}
+
+	files := base
+	if dir != "" && name != "" {
+		themeDir := path.Join(dir, name)
+		if info, err := os.Stat(themeDir); err == nil && info.IsDir() {
+			files = overlayFS{top: os.DirFS(themeDir), bottom: base}
+		} else if name != "default" {
+			return nil, fmt.Errorf("theme %q not found in %s", name, dir)
+		}
+	}
+
+	return newTheme(files)
+}
+
+// StoredTheme parses a stored theme version from its files, contents
+// included. Like themes on disk, files it does not contain fall back to
+// the default theme.
+func StoredTheme(files []models.ThemeFile) (*Theme, error) {
+	base, err := defaultThemeFS()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(mapFS, len(files))
+	for _, f := range files {
+		stored[f.Path] = &mapFile{data: f.Content, modTime: f.UpdatedAt}
+	}
+
+	return newTheme(overlayFS{top: stored, bottom: base})
+}
+
+// IsThemePath reports whether p may be stored in a theme: a top-level
+// template or a file under static/
+func IsThemePath(p string) bool {
+	if !fs.ValidPath(p) || p == "." {
+		return false
+	}
+	if strings.HasPrefix(p, "static/") {
+		return true
+	}
+	return !strings.Contains(p, "/") && path.Ext(p) == ".html"
+}
+
+func defaultThemeFS() (fs.FS, error) {
+	return fs.Sub(embeddedThemes, "themes/default")
+}
+
+func newTheme(files fs.FS) (*Theme, error) {
+	t := &Theme{files: files, templates: make(map[string]*template.Template)}
+	for _, page := range pageTemplates {
+		tmpl, err := template.New(templateBase).Funcs(templateFuncs).ParseFS(files, templateBase, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse theme template %s: %w", page, err)
+		}
+		t.templates[page] = tmpl
+	}
+
+	return t, nil
+}
+
+// Static serves the theme's static/ directory. It must be mounted with its
+// prefix stripped.
+func (t *Theme) Static() http.Handler {
+	static, err := fs.Sub(t.files, "static")
+	if err != nil {
+		return http.NotFoundHandler()
+	}
+	return http.FileServer(http.FS(static))
+}
+
+func (t *Theme) execute(w http.ResponseWriter, status int, page string, data interface{}) error {
+	tmpl, ok := t.templates[page]
+	if !ok {
+		return fmt.Errorf("unknown template %s", page)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.ExecuteTemplate(&buf, templateBase, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", page, err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(buf.String()))
+	return err
+}
+
+var templateFuncs = template.FuncMap{
+	"formatDate": func(t *time.Time, layout string) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(layout)
+	},
+	"year": func() int {
+		return time.Now().Year()
+	},
+}
+
+// overlayFS reads from top first and falls back to bottom when a file does
+// not exist there
+type overlayFS struct {
+	top    fs.FS
+	bottom fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return o.bottom.Open(name)
+}
+
+// mapFS is an in-memory file system for themes loaded from the database.
+// It holds regular files only; directories are resolved by the overlay.
+type mapFS map[string]*mapFile
+
+type mapFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+func (m mapFS) Open(name string) (fs.File, error) {
+	f, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &openMapFile{name: name, file: f, Reader: bytes.NewReader(f.data)}, nil
+}
+
+type openMapFile struct {
+	name string
+	file *mapFile
+	*bytes.Reader
+}
+
+func (f *openMapFile) Stat() (fs.FileInfo, error) { return mapFileInfo{f}, nil }
+func (f *openMapFile) Close() error               { return nil }
+
+type mapFileInfo struct{ f *openMapFile }
+
+func (i mapFileInfo) Name() string       { return path.Base(i.f.name) }
+func (i mapFileInfo) Size() int64        { return int64(len(i.f.file.data)) }
+func (i mapFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i mapFileInfo) ModTime() time.Time { return i.f.file.modTime }
+func (i mapFileInfo) IsDir() bool        { return false }
+func (i mapFileInfo) Sys() interface{}   { return nil }
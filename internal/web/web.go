@@ -0,0 +1,399 @@
+// Package web renders a simple public website straight from the content
+// repositories using Go templates, for sites that do not need a separate
+// JavaScript frontend.
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/keeps-dev/go-cms-template/internal/config"
+	"github.com/keeps-dev/go-cms-template/internal/delivery"
+	"github.com/keeps-dev/go-cms-template/internal/models"
+	"github.com/keeps-dev/go-cms-template/internal/repository"
+	"github.com/keeps-dev/go-cms-template/internal/service"
+)
+
+// Setting keys used to brand the rendered site
+const (
+	SettingSiteName        = "site.name"
+	SettingSiteDescription = "site.description"
+)
+
+// Site serves the public HTML pages
+type Site struct {
+	cfg             config.WebConfig
+	postRepo        *repository.ContentPostRepository
+	contentTypeRepo *repository.ContentTypeRepository
+	settingRepo     *repository.SettingRepository
+	themes          *service.ThemeService
+	cacheHintRepo   *repository.CacheHintRepository
+
+	// reload re-parses a filesystem theme on every request so template
+	// edits show up without a restart
+	reload bool
+
+	// basePath prefixes every generated link; it is set for previews
+	basePath string
+
+	theme  atomic.Pointer[Theme]
+	stored atomic.Bool
+}
+
+func New(cfg config.WebConfig, reload bool, postRepo *repository.ContentPostRepository, contentTypeRepo *repository.ContentTypeRepository, settingRepo *repository.SettingRepository, themes *service.ThemeService, cacheHintRepo *repository.CacheHintRepository) (*Site, error) {
+	s := &Site{
+		cfg:             cfg,
+		postRepo:        postRepo,
+		contentTypeRepo: contentTypeRepo,
+		settingRepo:     settingRepo,
+		themes:          themes,
+		cacheHintRepo:   cacheHintRepo,
+		reload:          reload,
+	}
+
+	if err := s.SetTheme(nil); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// LoadActiveTheme switches to the active stored theme, if there is one
+func (s *Site) LoadActiveTheme(ctx context.Context) error {
+	active, err := s.themes.GetActive(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	theme, err := StoredTheme(active.Files)
+	if err != nil {
+		return fmt.Errorf("theme %s v%d: %w", active.Name, active.Version, err)
+	}
+
+	return s.SetTheme(theme)
+}
+
+// SetTheme hot-swaps the theme used for new requests. A nil theme switches
+// back to the configured filesystem theme.
+func (s *Site) SetTheme(theme *Theme) error {
+	if theme == nil {
+		fsTheme, err := LoadTheme(s.cfg.ThemesDir, s.cfg.Theme)
+		if err != nil {
+			return err
+		}
+		s.theme.Store(fsTheme)
+		s.stored.Store(false)
+		return nil
+	}
+
+	s.theme.Store(theme)
+	s.stored.Store(true)
+	return nil
+}
+
+// Preview serves the site rendered with theme, as if mounted at basePath,
+// without affecting the active theme
+func (s *Site) Preview(theme *Theme, basePath string) http.Handler {
+	preview := &Site{
+		cfg:             s.cfg,
+		postRepo:        s.postRepo,
+		contentTypeRepo: s.contentTypeRepo,
+		settingRepo:     s.settingRepo,
+		themes:          s.themes,
+		basePath:        basePath,
+	}
+	preview.theme.Store(theme)
+	preview.stored.Store(true)
+
+	r := chi.NewRouter()
+	preview.Routes(r)
+	r.NotFound(preview.NotFound)
+	return r
+}
+
+// Routes registers the site's pages on r
+func (s *Site) Routes(r chi.Router) {
+	r.Get("/", s.Index)
+	r.Get("/posts/{slug}", s.Post)
+	r.Get("/{slug}", s.Page)
+	r.Handle("/theme/*", http.StripPrefix("/theme", http.HandlerFunc(s.serveStatic)))
+}
+
+// SiteInfo describes the site as a whole
+type SiteInfo struct {
+	Name        string
+	Description string
+}
+
+// Post is the template view of a content post
+type Post struct {
+	Title       string
+	Slug        string
+	URL         string
+	Excerpt     string
+	Content     template.HTML
+	Author      string
+	Tags        []models.Tag
+	Media       []models.PostMedia
+	PublishedAt *time.Time
+}
+
+// Pagination links for list pages
+type Pagination struct {
+	Page       int
+	TotalPages int
+	PrevURL    string
+	NextURL    string
+}
+
+// PageData is passed to every template
+type PageData struct {
+	Site       SiteInfo
+	BasePath   string
+	Title      string
+	Posts      []Post
+	Post       *Post
+	Pagination *Pagination
+	Status     int
+	Message    string
+}
+
+// Index lists published posts, newest first
+func (s *Site) Index(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	filter := models.PostFilter{
+		Status:           statusPtr(models.PostStatusPublished),
+		Environment:      models.EnvironmentLive,
+		PaginationParams: models.PaginationParams{Page: page, PageSize: s.cfg.PostsPerPage, SortBy: "published_at", SortDir: "desc"},
+	}
+
+	if s.cfg.PostType != "" {
+		ct, err := s.contentTypeRepo.GetBySlug(r.Context(), s.cfg.PostType)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			s.serverError(w, r, err)
+			return
+		}
+		if ct != nil {
+			filter.ContentTypeID = &ct.ID
+		}
+	}
+
+	posts, total, err := s.postRepo.List(r.Context(), filter)
+	if err != nil {
+		s.serverError(w, r, err)
+		return
+	}
+
+	filter.PaginationParams.Normalize()
+	totalPages := int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	pagination := &Pagination{Page: filter.Page, TotalPages: totalPages}
+	if filter.Page > 1 {
+		pagination.PrevURL = s.basePath + "/?page=" + strconv.Itoa(filter.Page-1)
+	}
+	if filter.Page < totalPages {
+		pagination.NextURL = s.basePath + "/?page=" + strconv.Itoa(filter.Page+1)
+	}
+
+	data := s.pageData(r)
+	for i := range posts {
+		data.Posts = append(data.Posts, s.postView(&posts[i]))
+	}
+	data.Pagination = pagination
+
+	s.render(w, r, http.StatusOK, templateIndex, data)
+}
+
+// Post serves a published post at /posts/{slug}, its path under the default
+// slug pattern
+func (s *Site) Post(w http.ResponseWriter, r *http.Request) {
+	s.servePost(w, r, "posts/"+chi.URLParam(r, "slug"))
+}
+
+// Page serves a published post of the page content type at the site root,
+// or a post whose slug pattern is a bare {slug}
+func (s *Site) Page(w http.ResponseWriter, r *http.Request) {
+	s.servePost(w, r, chi.URLParam(r, "slug"))
+}
+
+// NotFound serves the post at the request path when there is one, since
+// slug patterns put posts at any depth, and otherwise renders the theme's
+// error page with a 404 status
+func (s *Site) NotFound(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, s.basePath), "/")
+	if path != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		s.servePost(w, r, path)
+		return
+	}
+	s.notFound(w, r)
+}
+
+func (s *Site) notFound(w http.ResponseWriter, r *http.Request) {
+	data := s.pageData(r)
+	data.Title = "Page not found"
+	data.Status = http.StatusNotFound
+	data.Message = "The page you are looking for does not exist."
+	s.render(w, r, http.StatusNotFound, templateError, data)
+}
+
+// servePost renders the live, published post whose slug is the last
+// segment of path. A post whose path is different, such as after its
+// content type's slug pattern changed, is permanently redirected there.
+func (s *Site) servePost(w http.ResponseWriter, r *http.Request, path string) {
+	slug := path[strings.LastIndex(path, "/")+1:]
+	post, err := s.postRepo.GetBySlug(r.Context(), slug)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.serverError(w, r, err)
+		return
+	}
+	if post == nil || !s.visible(post) {
+		s.notFound(w, r)
+		return
+	}
+
+	if canonical := s.postPath(post); canonical != path {
+		target := s.basePath + "/" + canonical
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	data := s.pageData(r)
+	view := s.postView(post)
+	data.Post = &view
+	data.Title = post.Title
+	// Theme previews leave cacheHintRepo unset, since they aren't for CDNs
+	if s.cacheHintRepo != nil {
+		delivery.SetCacheHeaders(w, r, s.cacheHintRepo, post, "")
+	}
+
+	if s.isPage(post) {
+		s.render(w, r, http.StatusOK, templatePage, data)
+		return
+	}
+	s.render(w, r, http.StatusOK, templatePost, data)
+}
+
+// visible reports whether a post is published and of a content type the
+// site shows
+func (s *Site) visible(post *models.ContentPost) bool {
+	if post.Status != models.PostStatusPublished || (post.PublishedAt != nil && post.PublishedAt.After(time.Now())) {
+		return false
+	}
+	return s.isPage(post) || s.cfg.PostType == "" || (post.ContentType != nil && post.ContentType.Slug == s.cfg.PostType)
+}
+
+func (s *Site) isPage(post *models.ContentPost) bool {
+	return s.cfg.PageType != "" && post.ContentType != nil && post.ContentType.Slug == s.cfg.PageType
+}
+
+// postPath returns the site path of a post: pages live at the root, other
+// posts where their content type's slug pattern puts them
+func (s *Site) postPath(post *models.ContentPost) string {
+	if s.isPage(post) {
+		return post.Slug
+	}
+	return post.Path()
+}
+
+func (s *Site) pageData(r *http.Request) *PageData {
+	data := &PageData{Site: SiteInfo{Name: "CMS"}, BasePath: s.basePath, Status: http.StatusOK}
+
+	settings, err := s.settingRepo.GetMultiple(r.Context(), []string{SettingSiteName, SettingSiteDescription})
+	if err != nil {
+		return data
+	}
+	if name := settings[SettingSiteName]; name != "" {
+		data.Site.Name = name
+	}
+	data.Site.Description = settings[SettingSiteDescription]
+
+	return data
+}
+
+func (s *Site) serverError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("[ERROR] Failed to render %s: %v", r.URL.Path, err)
+
+	data := s.pageData(r)
+	data.Title = "Something went wrong"
+	data.Status = http.StatusInternalServerError
+	data.Message = "An unexpected error occurred. Please try again later."
+	s.render(w, r, http.StatusInternalServerError, templateError, data)
+}
+
+func (s *Site) render(w http.ResponseWriter, r *http.Request, status int, page string, data *PageData) {
+	theme, err := s.currentTheme()
+	if err != nil {
+		log.Printf("[ERROR] Failed to load theme: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := theme.execute(w, status, page, data); err != nil {
+		log.Printf("[ERROR] %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (s *Site) serveStatic(w http.ResponseWriter, r *http.Request) {
+	theme, err := s.currentTheme()
+	if err != nil {
+		log.Printf("[ERROR] Failed to load theme: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	theme.Static().ServeHTTP(w, r)
+}
+
+func (s *Site) currentTheme() (*Theme, error) {
+	if s.reload && !s.stored.Load() {
+		return LoadTheme(s.cfg.ThemesDir, s.cfg.Theme)
+	}
+	return s.theme.Load(), nil
+}
+
+func (s *Site) postView(p *models.ContentPost) Post {
+	view := Post{
+		Title:       p.Title,
+		Slug:        p.Slug,
+		URL:         s.basePath + "/" + s.postPath(p),
+		Tags:        p.Tags,
+		Media:       p.Media,
+		PublishedAt: p.PublishedAt,
+	}
+	if p.Excerpt != nil {
+		view.Excerpt = *p.Excerpt
+	}
+	// Post content is authored by trusted editors and stored as HTML
+	if p.Content != nil {
+		view.Content = template.HTML(*p.Content)
+	}
+	if p.Author != nil {
+		view.Author = p.Author.FullName
+	}
+	return view
+}
+
+func statusPtr(s models.PostStatus) *models.PostStatus {
+	return &s
+}
@@ -1,151 +0,0 @@
--- Core user management
-CREATE TABLE users (
-    id UUID PRIMARY KEY,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    password_hash VARCHAR(255) NOT NULL,
-    full_name VARCHAR(255) NOT NULL,
-    role SMALLINT NOT NULL DEFAULT 1 CHECK (role BETWEEN 1 AND 3),
-    is_active BOOLEAN NOT NULL DEFAULT true,
-    last_login TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE sessions (
-    id UUID PRIMARY KEY,
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    token VARCHAR(500) NOT NULL UNIQUE,
-    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Content management
-CREATE TABLE content_types (
-    id UUID PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE,
-    slug VARCHAR(100) NOT NULL UNIQUE,
-    schema_fields JSONB,
-    is_active BOOLEAN NOT NULL DEFAULT true,
-    display_order INTEGER NOT NULL DEFAULT 0,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE content_posts (
-    id UUID PRIMARY KEY,
-    content_type_id UUID NOT NULL REFERENCES content_types(id) ON DELETE RESTRICT,
-    author_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
-    title VARCHAR(500) NOT NULL,
-    slug VARCHAR(500) NOT NULL UNIQUE,
-    excerpt TEXT,
-    content TEXT,
-    metadata JSONB,
-    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 3),
-    published_at TIMESTAMP WITH TIME ZONE,
-    view_count INTEGER NOT NULL DEFAULT 0,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Media management
-CREATE TABLE media (
-    id UUID PRIMARY KEY,
-    file_name VARCHAR(255) NOT NULL,
-    object_key VARCHAR(1000) NOT NULL UNIQUE,
-    bucket_name VARCHAR(255) NOT NULL,
-    cdn_url VARCHAR(1000),
-    file_type SMALLINT NOT NULL CHECK (file_type BETWEEN 1 AND 3),
-    mime_type VARCHAR(100) NOT NULL,
-    file_size INTEGER NOT NULL,
-    dimensions JSONB,
-    variants JSONB,
-    alt_text VARCHAR(500),
-    checksum VARCHAR(64),
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE post_media (
-    id UUID PRIMARY KEY,
-    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
-    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
-    media_role SMALLINT NOT NULL CHECK (media_role BETWEEN 1 AND 3),
-    display_order INTEGER NOT NULL DEFAULT 0,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(post_id, media_id)
-);
-
--- Tagging system
-CREATE TABLE tags (
-    id UUID PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE,
-    slug VARCHAR(100) NOT NULL UNIQUE,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE post_tags (
-    post_id UUID NOT NULL REFERENCES content_posts(id) ON DELETE CASCADE,
-    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    PRIMARY KEY (post_id, tag_id)
-);
-
--- Contact and settings
-CREATE TABLE contact_submissions (
-    id UUID PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    email VARCHAR(255) NOT NULL,
-    phone VARCHAR(50),
-    subject VARCHAR(500),
-    message TEXT NOT NULL,
-    status SMALLINT NOT NULL DEFAULT 1 CHECK (status BETWEEN 1 AND 4),
-    ip_address INET,
-    user_agent TEXT,
-    metadata JSONB,
-    read_at TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE settings (
-    id UUID PRIMARY KEY,
-    key VARCHAR(100) NOT NULL UNIQUE,
-    value TEXT,
-    description TEXT,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Indexes for performance
-CREATE INDEX idx_sessions_user_id ON sessions(user_id);
-CREATE INDEX idx_sessions_token ON sessions(token, expires_at);
-CREATE INDEX idx_sessions_expires ON sessions(expires_at);
-CREATE INDEX idx_content_posts_type_status ON content_posts(content_type_id, status);
-CREATE INDEX idx_content_posts_author ON content_posts(author_id);
-CREATE INDEX idx_content_posts_slug ON content_posts(slug);
-CREATE INDEX idx_content_posts_published ON content_posts(published_at DESC) WHERE status = 2;
-CREATE INDEX idx_content_posts_status ON content_posts(status);
-CREATE INDEX idx_post_media_post_id ON post_media(post_id);
-CREATE INDEX idx_post_media_media_id ON post_media(media_id);
-CREATE INDEX idx_post_tags_post_id ON post_tags(post_id);
-CREATE INDEX idx_post_tags_tag_id ON post_tags(tag_id);
-CREATE INDEX idx_media_object_key ON media(object_key);
-CREATE INDEX idx_media_checksum ON media(checksum);
-CREATE INDEX idx_contact_status_created ON contact_submissions(status, created_at DESC);
-CREATE INDEX idx_contact_email ON contact_submissions(email);
-CREATE INDEX idx_content_types_slug ON content_types(slug);
-CREATE INDEX idx_tags_slug ON tags(slug);
-CREATE INDEX idx_users_role ON users(role);
-CREATE INDEX idx_media_file_type ON media(file_type);
-
--- Trigger function for automatic timestamp updates
-CREATE OR REPLACE FUNCTION update_updated_at_column() 
-RETURNS TRIGGER AS $$ 
-BEGIN 
-    NEW.updated_at = CURRENT_TIMESTAMP; 
-    RETURN NEW; 
-END; 
-$$ LANGUAGE plpgsql;
-
--- Apply triggers
-CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
-CREATE TRIGGER update_content_types_updated_at BEFORE UPDATE ON content_types FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
-CREATE TRIGGER update_content_posts_updated_at BEFORE UPDATE ON content_posts FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
-CREATE TRIGGER update_settings_updated_at BEFORE UPDATE ON settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
\ No newline at end of file